@@ -26,10 +26,117 @@ const (
 	StateNotReady State = "NotReady"
 )
 
+// ConsumerIsolation defines which event consumers on the managed runtime cluster share an application.
+type ConsumerIsolation string
+
+const (
+	// ConsumerIsolationCluster shares the application of the managed runtime cluster between all event consumers.
+	ConsumerIsolationCluster ConsumerIsolation = "Cluster"
+	// ConsumerIsolationNamespace provisions a dedicated application for every namespace with Subscriptions.
+	ConsumerIsolationNamespace ConsumerIsolation = "Namespace"
+	// ConsumerIsolationSubscription provisions a dedicated application for every Subscription.
+	ConsumerIsolationSubscription ConsumerIsolation = "Subscription"
+)
+
+// CredentialPropagation defines where the credentials of the application of the managed runtime cluster are stored.
+type CredentialPropagation string
+
+const (
+	// CredentialPropagationSKR stores the credentials in the application secret on the managed runtime cluster.
+	CredentialPropagationSKR CredentialPropagation = "SKR"
+	// CredentialPropagationKCP stores the credentials only in a secret on KCP, e.g. for central components like a managed event publisher
+	// proxy.
+	CredentialPropagationKCP CredentialPropagation = "KCP"
+	// CredentialPropagationBoth stores the credentials in the application secret on the managed runtime cluster and in a secret on KCP.
+	CredentialPropagationBoth CredentialPropagation = "Both"
+)
+
 // EventingAuthSpec defines the desired state of EventingAuth.
 type EventingAuthSpec struct {
-	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
-	// Important: Run "make" to regenerate code after modifying this file
+	// ImmutableSecret defines if the secret on the managed runtime cluster is immutable.
+	// An immutable secret is deleted and created again if its credentials change.
+	ImmutableSecret bool `json:"immutableSecret,omitempty"`
+	// ConsumerIsolation defines if the event consumers get dedicated applications in addition to the application of the managed runtime
+	// cluster, either per namespace with Subscriptions or per Subscription. Defaults to 'Cluster', which provisions no dedicated applications.
+	// +kubebuilder:validation:Enum=Cluster;Namespace;Subscription
+	ConsumerIsolation ConsumerIsolation `json:"consumerIsolation,omitempty"`
+	// SeparatePublisherCredentials defines if the event publisher gets a separate application, whose credentials are delivered in the
+	// 'eventing-publisher-auth' secret, while the 'eventing-webhook-auth' secret is only used for the webhook and validation traffic.
+	SeparatePublisherCredentials bool `json:"separatePublisherCredentials,omitempty"`
+	// SharedApplication is the name of the group of managed runtime clusters that share a single application, e.g. to stay under the
+	// application quota of the IAS tenant. The application is only deleted once the last EventingAuth CR of the group is deleted.
+	// It must not be changed after the application is created.
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	SharedApplication string `json:"sharedApplication,omitempty"`
+	// CredentialsSecretRef references a secret on KCP with the keys 'client_id', 'client_secret', 'token_url' and 'certs_url' of an OAuth
+	// client that is managed outside of the manager. If it is set, no application is provisioned in the identity provider, and the
+	// credentials of the secret are only delivered to the managed runtime cluster. It can't be combined with spec.sharedApplication,
+	// spec.separatePublisherCredentials, or dedicated applications of the event consumers.
+	CredentialsSecretRef *SecretReference `json:"credentialsSecretRef,omitempty"`
+	// KubeconfigSecretRef references a secret on KCP with the kubeconfig of the target cluster that the credentials are delivered to. If
+	// it isn't set, the target cluster is the managed runtime cluster with the name of the EventingAuth CR as runtime ID.
+	KubeconfigSecretRef *KubeconfigSecretReference `json:"kubeconfigSecretRef,omitempty"`
+	// CredentialPropagation defines if the credentials are stored in the application secret on the managed runtime cluster, only in a
+	// secret on KCP, or in both. If empty, the default propagation of the manager is used.
+	// +kubebuilder:validation:Enum=SKR;KCP;Both
+	CredentialPropagation CredentialPropagation `json:"credentialPropagation,omitempty"`
+	// Provider is the name of the identity provider that the application is managed in, e.g. 'ias'.
+	// If empty, the default provider of the manager is used. It must not be changed after the application is created.
+	Provider string `json:"provider,omitempty"`
+	// IAS overrides the configuration of the 'ias' provider of the manager for this application.
+	IAS *IASProviderSpec `json:"ias,omitempty"`
+	// Keycloak overrides the configuration of the 'keycloak' provider of the manager for this application.
+	Keycloak *KeycloakProviderSpec `json:"keycloak,omitempty"`
+	// Auth0 overrides the configuration of the 'auth0' provider of the manager for this application.
+	Auth0 *Auth0ProviderSpec `json:"auth0,omitempty"`
+	// XSUAA overrides the configuration of the 'xsuaa' provider of the manager for this application.
+	XSUAA *XSUAAProviderSpec `json:"xsuaa,omitempty"`
+	// Okta overrides the configuration of the 'okta' provider of the manager for this application.
+	Okta *OktaProviderSpec `json:"okta,omitempty"`
+}
+
+// KubeconfigSecretReference references a secret in the KCP cluster with the kubeconfig of a target cluster.
+type KubeconfigSecretReference struct {
+	// Namespace of the secret.
+	Namespace string `json:"namespace"`
+	// Name of the secret.
+	Name string `json:"name"`
+	// Key of the kubeconfig in the data of the secret. Defaults to 'config'.
+	Key string `json:"key,omitempty"`
+}
+
+// IASProviderSpec defines the configuration of the 'ias' provider.
+type IASProviderSpec struct {
+	// Tenant is the name of the tenant that the application is managed in, which is defined by the IASTenant CR with this name or by
+	// the IAS credentials secret with this tenant. If empty, the tenant of the credentials secret of the manager is used.
+	Tenant string `json:"tenant,omitempty"`
+}
+
+// KeycloakProviderSpec defines the configuration of the 'keycloak' provider.
+type KeycloakProviderSpec struct {
+	// Realm is the realm that the client is created in. It is created if it doesn't exist.
+	Realm string `json:"realm,omitempty"`
+}
+
+// Auth0ProviderSpec defines the configuration of the 'auth0' provider.
+type Auth0ProviderSpec struct {
+	// Audience is the identifier of the API that the application is granted access to.
+	Audience string `json:"audience,omitempty"`
+	// Scopes are the scopes of the API that are granted to the application.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// XSUAAProviderSpec defines the configuration of the 'xsuaa' provider.
+type XSUAAProviderSpec struct {
+	// Plan is the service plan of the XSUAA service instance.
+	Plan string `json:"plan,omitempty"`
+}
+
+// OktaProviderSpec defines the configuration of the 'okta' provider.
+type OktaProviderSpec struct {
+	// AuthorizationServer is the ID of the authorization server that issues the tokens of the application.
+	AuthorizationServer string `json:"authorizationServer,omitempty"`
 }
 
 // EventingAuthStatus defines the observed state of EventingAuth.
@@ -41,19 +148,69 @@ type EventingAuthStatus struct {
 	State State `json:"state,omitempty"`
 
 	// Application contains information about a created IAS application
-	Application *IASApplication `json:"iasApplication,omitempty"`
+	Application *ClusterApplication `json:"iasApplication,omitempty"`
 	// AuthSecret contains information about created K8s secret
 	AuthSecret *AuthSecret `json:"secret,omitempty"`
+	// KCPSecret is the namespaced name of the secret on KCP with the credentials, if they are propagated to KCP.
+	KCPSecret string `json:"kcpSecret,omitempty"`
+	// ProvisionedTime is the time when the EventingAuth CR was ready for the first time.
+	ProvisionedTime *kmetav1.Time `json:"provisionedTime,omitempty"`
+	// Consumers contains the dedicated applications of the event consumers with spec.consumerIsolation.
+	Consumers []ConsumerApplication `json:"consumers,omitempty"`
+	// Publisher contains information about the separate application of the event publisher with spec.separatePublisherCredentials.
+	Publisher *PublisherApplication `json:"publisher,omitempty"`
+	// ProvidedCredentials contains information about the credentials of spec.credentialsSecretRef that are delivered.
+	ProvidedCredentials *ProvidedCredentials `json:"providedCredentials,omitempty"`
 
 	//  Conditions associated with EventingAuthStatus.
 	Conditions []kmetav1.Condition `json:"conditions,omitempty"`
 }
 
-type IASApplication struct {
+// ClusterApplication contains information about the application of the managed runtime cluster.
+type ClusterApplication struct {
+	// Name of the application in IAS
+	Name string `json:"name"`
+	// Application ID in IAS
+	UUID string `json:"uuid"`
+	// Tenant is the name of the IAS tenant that owns the application. It differs from spec.ias.tenant if the application was
+	// provisioned in the fallback tenant of spec.ias.tenant.
+	Tenant string `json:"tenant,omitempty"`
+	// CredentialsIssuedTime is the time when the credentials of the application were issued, i.e. when the application was created or
+	// its credentials were replaced. It is unset for applications that were created before the time was recorded.
+	CredentialsIssuedTime *kmetav1.Time `json:"credentialsIssuedTime,omitempty"`
+}
+
+// ConsumerApplication contains information about the dedicated application of an event consumer.
+type ConsumerApplication struct {
+	// Consumer is the namespace of the event consumer, or its namespace and the name of its Subscription separated by a slash
+	Consumer string `json:"consumer"`
 	// Name of the application in IAS
 	Name string `json:"name"`
 	// Application ID in IAS
 	UUID string `json:"uuid"`
+	// NamespacedName of the secret of the event consumer on the managed runtime cluster
+	SecretNamespacedName string `json:"secretNamespacedName"`
+}
+
+// PublisherApplication contains information about the separate application of the event publisher.
+type PublisherApplication struct {
+	// Name of the application in IAS
+	Name string `json:"name"`
+	// Application ID in IAS
+	UUID string `json:"uuid"`
+	// NamespacedName of the publisher secret on the managed runtime cluster
+	SecretNamespacedName string `json:"secretNamespacedName"`
+}
+
+// ProvidedCredentials contains information about the delivered credentials of an OAuth client that is managed outside of the manager.
+type ProvidedCredentials struct {
+	// ClientID is the client ID of the credentials
+	ClientID string `json:"clientId"`
+	// Fingerprint is the SHA-256 digest of the credentials, which identifies a change of the credentials without storing them
+	Fingerprint string `json:"fingerprint"`
+	// ObservedTime is the time when the credentials were first observed in the referenced secret. The rotation reminders are counted
+	// from this time.
+	ObservedTime *kmetav1.Time `json:"observedTime,omitempty"`
 }
 
 type AuthSecret struct {
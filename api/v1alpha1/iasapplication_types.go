@@ -0,0 +1,94 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// IASApplicationSpec defines the desired state of IASApplication.
+type IASApplicationSpec struct {
+	// ApplicationName is the name of the application in the identity provider. Defaults to '<namespace>-<name>-<uid>' with the first 8
+	// characters of the UID of the IASApplication CR, so that it doesn't collide with the applications of other CRs. It must not be changed
+	// after the application is created.
+	// +kubebuilder:validation:MaxLength=253
+	ApplicationName string `json:"applicationName,omitempty"`
+	// Provider is the name of the identity provider that the application is managed in, e.g. 'ias'.
+	// If empty, the default provider of the manager is used. It must not be changed after the application is created.
+	Provider string `json:"provider,omitempty"`
+	// IAS overrides the configuration of the 'ias' provider of the manager for this application.
+	IAS *IASProviderSpec `json:"ias,omitempty"`
+	// Auth configures the authentication of the application. It is only supported by the 'ias' provider and must not be changed after the
+	// application is created.
+	Auth *IASApplicationAuth `json:"auth,omitempty"`
+	// SecretName is the name of the secret in the namespace of the IASApplication CR that the credentials of the application are written to
+	// with the keys 'client_id', 'client_secret', 'token_url', and 'certs_url'. The secret is owned by the IASApplication CR.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	SecretName string `json:"secretName"`
+}
+
+// IASApplicationAuth defines the authentication of an application.
+type IASApplicationAuth struct {
+	// GrantTypes restricts the OAuth grant types of the application. All grant types are allowed if it is empty.
+	// +kubebuilder:validation:items:Enum=clientCredentials;jwtBearer;tokenExchange;refreshToken;password;authorizationCode;authorizationCodePkceS256;implicit
+	GrantTypes []string `json:"grantTypes,omitempty"`
+	// TokenValiditySeconds is the validity of the access tokens of the application. The default of the identity provider is used if it is
+	// zero.
+	// +kubebuilder:validation:Minimum=60
+	// +kubebuilder:validation:Maximum=43200
+	TokenValiditySeconds int32 `json:"tokenValiditySeconds,omitempty"`
+}
+
+// IASApplicationStatus defines the observed state of IASApplication.
+type IASApplicationStatus struct {
+	// State signifies current state of the IASApplication CR. Value
+	// can be one of ("Ready", "NotReady").
+	// +kubebuilder:validation:Enum=Ready;NotReady
+	State State `json:"state,omitempty"`
+	// Application contains information about the created application.
+	Application *ClusterApplication `json:"application,omitempty"`
+	// Conditions associated with IASApplicationStatus.
+	Conditions []kmetav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Secret",type="string",JSONPath=".spec.secretName"
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+
+// IASApplication is the Schema for the iasapplications API. It requests an application with OIDC client credentials in an identity
+// provider, independently of Kyma Eventing, e.g. for other Kyma components.
+type IASApplication struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IASApplicationSpec   `json:"spec,omitempty"`
+	Status IASApplicationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IASApplicationList contains a list of IASApplication.
+type IASApplicationList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []IASApplication `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Used on the package level.
+	schemeBuilder.Register(&IASApplication{}, &IASApplicationList{})
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// IASTenantSpec defines the desired state of IASTenant.
+type IASTenantSpec struct {
+	// URL of the IAS tenant, e.g. 'https://tenant.accounts.ondemand.com'. Either the URL or the subaccount must be set.
+	// +kubebuilder:validation:Pattern=`^https://`
+	URL string `json:"url,omitempty"`
+	// Subaccount is the BTP subaccount whose subscription of SAP Cloud Identity Services defines the URL of the tenant. The URL is discovered
+	// periodically, so that it doesn't need to be hardcoded. It isn't used if the URL is set.
+	Subaccount *BTPSubaccount `json:"subaccount,omitempty"`
+	// CredentialsSecretRef references the secret with the keys 'username' and 'password' of the technical user of the IAS tenant.
+	// +kubebuilder:validation:Required
+	CredentialsSecretRef SecretReference `json:"credentialsSecretRef"`
+	// RateLimit limits the rate of the requests to the IAS tenant. The requests aren't limited if it isn't set.
+	RateLimit *RateLimit `json:"rateLimit,omitempty"`
+	// TokenURL overrides the token URL of the OIDC configuration of the tenant, e.g. for a tenant behind a custom domain or an API gateway
+	// whose OIDC configuration points at internal hosts. The token URL isn't discovered if it is set.
+	// +kubebuilder:validation:Pattern=`^https://`
+	TokenURL string `json:"tokenURL,omitempty"`
+	// JWKSURI overrides the JWKS URI of the OIDC configuration of the tenant. The JWKS URI isn't discovered if it is set.
+	// +kubebuilder:validation:Pattern=`^https://`
+	JWKSURI string `json:"jwksURI,omitempty"`
+	// KymaSelector selects the Kyma CRs by their labels, e.g. by 'kyma-project.io/region', whose EventingAuth CRs are created with this
+	// tenant. A Kyma CR must not be selected by multiple tenants. The tenant of existing EventingAuth CRs isn't changed.
+	KymaSelector *kmetav1.LabelSelector `json:"kymaSelector,omitempty"`
+	// FallbackTenant is the name of the tenant that the applications of this tenant are provisioned in if their provisioning in this
+	// tenant fails persistently. Applications that already exist in this tenant aren't moved.
+	FallbackTenant string `json:"fallbackTenant,omitempty"`
+	// ApplicationQuota is the quota of the applications in the tenant. The number of applications isn't limited if it isn't set.
+	ApplicationQuota *ApplicationQuota `json:"applicationQuota,omitempty"`
+}
+
+// ApplicationQuota defines the quota of the applications in an IAS tenant.
+type ApplicationQuota struct {
+	// Limit is the maximum number of applications of the EventingAuth CRs in the IAS tenant.
+	// +kubebuilder:validation:Minimum=1
+	Limit int32 `json:"limit"`
+	// WarningPercentage is the percentage of the limit from which on warning events are emitted for the IASTenant CR. Defaults to 90.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	WarningPercentage int32 `json:"warningPercentage,omitempty"`
+	// StopProvisioning defines if the creation of applications in the tenant fails once the limit is reached.
+	StopProvisioning bool `json:"stopProvisioning,omitempty"`
+}
+
+// BTPSubaccount references a BTP subaccount and the credentials of the Cloud Management Service that the subaccount is read with.
+type BTPSubaccount struct {
+	// ID is the ID of the subaccount.
+	ID string `json:"id"`
+	// CredentialsSecretRef references the secret with the service binding of the Cloud Management Service with the 'central' plan.
+	CredentialsSecretRef SecretReference `json:"credentialsSecretRef"`
+}
+
+// SecretReference references a secret in the KCP cluster.
+type SecretReference struct {
+	// Namespace of the secret.
+	Namespace string `json:"namespace"`
+	// Name of the secret.
+	Name string `json:"name"`
+}
+
+// RateLimit defines the rate limit of the requests to an IAS tenant.
+type RateLimit struct {
+	// RequestsPerSecond is the number of requests per second that are sent to the IAS tenant.
+	// +kubebuilder:validation:Minimum=1
+	RequestsPerSecond int32 `json:"requestsPerSecond"`
+	// Burst is the number of requests that can be sent at once before they are limited. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	Burst int32 `json:"burst,omitempty"`
+}
+
+// IASTenantStatus defines the observed state of IASTenant.
+type IASTenantStatus struct {
+	// State signifies the health of the IAS tenant. Value
+	// can be one of ("Ready", "NotReady").
+	// +kubebuilder:validation:Enum=Ready;NotReady
+	State State `json:"state,omitempty"`
+	// URL of the IAS tenant that was discovered from the subaccount.
+	URL string `json:"url,omitempty"`
+	// Applications is the number of applications of the EventingAuth CRs that are owned by the tenant.
+	Applications int32 `json:"applications,omitempty"`
+	// Conditions associated with IASTenantStatus.
+	Conditions []kmetav1.Condition `json:"conditions,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="URL",type="string",JSONPath=".spec.url"
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state"
+
+// IASTenant is the Schema for the iastenants API. It defines an IAS tenant that the applications of the EventingAuth CRs can be
+// managed in.
+type IASTenant struct {
+	kmetav1.TypeMeta   `json:",inline"`
+	kmetav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IASTenantSpec   `json:"spec,omitempty"`
+	Status IASTenantStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// IASTenantList contains a list of IASTenant.
+type IASTenantList struct {
+	kmetav1.TypeMeta `json:",inline"`
+	kmetav1.ListMeta `json:"metadata,omitempty"`
+	Items            []IASTenant `json:"items"`
+}
+
+func init() { //nolint:gochecknoinits // Used on the package level.
+	schemeBuilder.Register(&IASTenant{}, &IASTenantList{})
+}
@@ -1,9 +1,13 @@
 package v1alpha1
 
 import (
+	"fmt"
 	"reflect"
+	"regexp"
 
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
 	"github.com/pkg/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -12,6 +16,10 @@ type ConditionType string
 const (
 	ConditionApplicationReady ConditionType = "IASApplicationReady"
 	ConditionSecretReady      ConditionType = "SecretReady"
+	ConditionTenantReady      ConditionType = "IASTenantReady"
+	// ConditionOrphanedInIAS is set on an EventingAuth CR that is being deleted, if its application couldn't be deleted from IAS and is
+	// left behind.
+	ConditionOrphanedInIAS ConditionType = "OrphanedInIAS"
 )
 
 type ConditionReason string
@@ -21,13 +29,86 @@ const (
 	ConditionReasonSecretCreated             string = "SecretCreated"
 	ConditionReasonApplicationCreationFailed string = "IASApplicationCreationFailed"
 	ConditionReasonSecretCreationFailed      string = "SecretCreationFailed"
+	ConditionReasonSecretNotManaged          string = "SecretNotManaged"
+	ConditionReasonSecretVerificationFailed  string = "SecretVerificationFailed"
+	ConditionReasonSinkWriteFailed           string = "SinkWriteFailed"
+	ConditionReasonTenantReachable           string = "IASTenantReachable"
+	ConditionReasonTenantUnreachable         string = "IASTenantUnreachable"
+	ConditionReasonTenantDiscoveryFailed     string = "IASTenantDiscoveryFailed"
+	ConditionReasonTenantAPIUnavailable      string = "IASTenantApplicationsAPIUnavailable"
+	ConditionReasonTenantMaintenance         string = "IASTenantMaintenance"
+	ConditionReasonApplicationDeletionFailed string = "IASApplicationDeletionFailed"
+	ConditionReasonCredentialsProvided       string = "CredentialsProvided"
 )
 
 const (
 	ConditionMessageApplicationCreated string = "IAS application is successfully created."
 	ConditionMessageSecretCreated      string = "Eventing webhook authentication secret is successfully created."
+	ConditionMessageTenantReachable    string = "IAS tenant is reachable with its credentials."
+	// ConditionMessageCredentialsProvided is the message of the ApplicationReady condition of an EventingAuth CR with spec.credentialsSecretRef.
+	ConditionMessageCredentialsProvided string = "Credentials are provided by the referenced secret."
+	// ConditionMessageCredentialsSecretCreated is the message of the SecretReady condition of an IASApplication CR.
+	ConditionMessageCredentialsSecretCreated string = "Credentials secret is successfully created."
 )
 
+// ConditionReasonError is implemented by errors that define the reason of the condition that is set because of the error.
+type ConditionReasonError interface {
+	error
+	ConditionReason() string
+}
+
+// correlationIDPattern matches the correlation ID that is appended to a message by AppendCorrelationID.
+var correlationIDPattern = regexp.MustCompile(` \(correlation ID: [^()]*\)$`) //nolint:gochecknoglobals // Compiled once.
+
+// correlatedError is the error of a reconciliation with the correlation ID of the reconciliation.
+type correlatedError struct {
+	err           error
+	correlationID string
+}
+
+func (e *correlatedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *correlatedError) Unwrap() error {
+	return e.err
+}
+
+// WithCorrelationID returns the error with the correlation ID of the reconciliation that failed with it, which is appended to the message
+// of the condition that is set because of the error. It returns the error unchanged if it is nil or the correlation ID is empty.
+func WithCorrelationID(err error, correlationID string) error {
+	if err == nil || correlationID == "" {
+		return err
+	}
+	return &correlatedError{err: err, correlationID: correlationID}
+}
+
+// AppendCorrelationID appends the correlation ID to the message of a condition or an event, so that the message can be correlated with
+// the logs and the traces of the reconciliation.
+func AppendCorrelationID(message, correlationID string) string {
+	if correlationID == "" {
+		return message
+	}
+	return fmt.Sprintf("%s (correlation ID: %s)", message, correlationID)
+}
+
+// conditionMessage returns the redacted message of the error with its correlation ID.
+func conditionMessage(err error) string {
+	message := redact.String(err.Error())
+	var correlated *correlatedError
+	if errors.As(err, &correlated) {
+		message = AppendCorrelationID(message, correlated.correlationID)
+	}
+	return message
+}
+
+// messagesEqual returns true if the messages are equal apart from their correlation IDs. A condition of a repeated failure keeps the
+// correlation ID of the reconciliation that observed the failure first, since an update of the condition would trigger the next
+// reconciliation right away.
+func messagesEqual(a, b string) bool {
+	return correlationIDPattern.ReplaceAllString(a, "") == correlationIDPattern.ReplaceAllString(b, "")
+}
+
 func UpdateConditionAndState(eventingAuth *EventingAuth, conditionType ConditionType, err error) (EventingAuthStatus, error) {
 	switch conditionType {
 	case ConditionApplicationReady:
@@ -60,16 +141,24 @@ func MakeApplicationReadyCondition(eventingAuth *EventingAuth, err error) []kmet
 		applicationReadyCondition.Status = kmetav1.ConditionTrue
 		applicationReadyCondition.Reason = ConditionReasonApplicationCreated
 		applicationReadyCondition.Message = ConditionMessageApplicationCreated
+		if eventingAuth.Spec.CredentialsSecretRef != nil {
+			applicationReadyCondition.Reason = ConditionReasonCredentialsProvided
+			applicationReadyCondition.Message = ConditionMessageCredentialsProvided
+		}
 	} else {
-		applicationReadyCondition.Message = err.Error()
+		applicationReadyCondition.Message = conditionMessage(err)
 		applicationReadyCondition.Reason = ConditionReasonApplicationCreationFailed
+		var reasonErr ConditionReasonError
+		if errors.As(err, &reasonErr) {
+			applicationReadyCondition.Reason = reasonErr.ConditionReason()
+		}
 		applicationReadyCondition.Status = kmetav1.ConditionFalse
 	}
 	for ix, activeCond := range eventingAuth.Status.Conditions {
 		if activeCond.Type == string(ConditionApplicationReady) {
 			if applicationReadyCondition.Status == activeCond.Status &&
 				applicationReadyCondition.Reason == activeCond.Reason &&
-				applicationReadyCondition.Message == activeCond.Message {
+				messagesEqual(applicationReadyCondition.Message, activeCond.Message) {
 				return eventingAuth.Status.Conditions
 			} else {
 				eventingAuth.Status.Conditions[ix] = applicationReadyCondition
@@ -80,6 +169,19 @@ func MakeApplicationReadyCondition(eventingAuth *EventingAuth, err error) []kmet
 	return append(eventingAuth.Status.Conditions, applicationReadyCondition)
 }
 
+// SetOrphanedInIASCondition sets the OrphanedInIAS condition, because the application with the given ID couldn't be deleted from IAS after
+// the given number of attempts, and marks the EventingAuth CR as not ready.
+func SetOrphanedInIASCondition(eventingAuth *EventingAuth, applicationID string, attempts int, err error) {
+	kmeta.SetStatusCondition(&eventingAuth.Status.Conditions, kmetav1.Condition{
+		Type:   string(ConditionOrphanedInIAS),
+		Status: kmetav1.ConditionTrue,
+		Reason: ConditionReasonApplicationDeletionFailed,
+		Message: fmt.Sprintf("IAS application %s is orphaned, because its deletion failed %d times: %s", applicationID, attempts,
+			conditionMessage(err)),
+	})
+	eventingAuth.Status.State = StateNotReady
+}
+
 // MakeSecretReadyCondition updates the ConditionSecretReady condition based on the given error value.
 func MakeSecretReadyCondition(eventingAuth *EventingAuth, err error) []kmetav1.Condition {
 	secretReadyCondition := kmetav1.Condition{
@@ -91,15 +193,19 @@ func MakeSecretReadyCondition(eventingAuth *EventingAuth, err error) []kmetav1.C
 		secretReadyCondition.Reason = ConditionReasonSecretCreated
 		secretReadyCondition.Message = ConditionMessageSecretCreated
 	} else {
-		secretReadyCondition.Message = err.Error()
+		secretReadyCondition.Message = conditionMessage(err)
 		secretReadyCondition.Reason = ConditionReasonSecretCreationFailed
+		var reasonErr ConditionReasonError
+		if errors.As(err, &reasonErr) {
+			secretReadyCondition.Reason = reasonErr.ConditionReason()
+		}
 		secretReadyCondition.Status = kmetav1.ConditionFalse
 	}
 	for ix, activeCond := range eventingAuth.Status.Conditions {
 		if activeCond.Type == string(ConditionSecretReady) {
 			if secretReadyCondition.Status == activeCond.Status &&
 				secretReadyCondition.Reason == activeCond.Reason &&
-				secretReadyCondition.Message == activeCond.Message {
+				messagesEqual(secretReadyCondition.Message, activeCond.Message) {
 				return eventingAuth.Status.Conditions
 			} else {
 				eventingAuth.Status.Conditions[ix] = secretReadyCondition
@@ -154,6 +260,77 @@ func IsEventingAuthStatusEqual(oldStatus, newStatus EventingAuthStatus) bool {
 		ConditionsEqual(oldStatus.Conditions, newStatus.Conditions)
 }
 
+// UpdateIASTenantConditionAndState updates the ConditionTenantReady condition and the state of the IASTenant CR based on the given error of
+// its health check.
+func UpdateIASTenantConditionAndState(tenant *IASTenant, err error) IASTenantStatus {
+	condition := kmetav1.Condition{
+		Type:               string(ConditionTenantReady),
+		Status:             kmetav1.ConditionTrue,
+		Reason:             ConditionReasonTenantReachable,
+		Message:            ConditionMessageTenantReachable,
+		ObservedGeneration: tenant.Generation,
+	}
+	tenant.Status.State = StateReady
+	if err != nil {
+		condition.Status = kmetav1.ConditionFalse
+		condition.Reason = ConditionReasonTenantUnreachable
+		var reasonErr ConditionReasonError
+		if errors.As(err, &reasonErr) {
+			condition.Reason = reasonErr.ConditionReason()
+		}
+		condition.Message = conditionMessage(err)
+		tenant.Status.State = StateNotReady
+	}
+	if existing := kmeta.FindStatusCondition(tenant.Status.Conditions, condition.Type); existing != nil &&
+		existing.Status == condition.Status && existing.Reason == condition.Reason && messagesEqual(existing.Message, condition.Message) {
+		condition.Message = existing.Message
+	}
+	kmeta.SetStatusCondition(&tenant.Status.Conditions, condition)
+	return tenant.Status
+}
+
+// UpdateIASApplicationConditionAndState updates the ConditionApplicationReady or the ConditionSecretReady condition of the IASApplication CR
+// based on the given error, and its state, which is ready once both the application and the credentials secret are created.
+func UpdateIASApplicationConditionAndState(app *IASApplication, conditionType ConditionType, err error) (IASApplicationStatus, error) {
+	condition := kmetav1.Condition{
+		Type:               string(conditionType),
+		Status:             kmetav1.ConditionTrue,
+		ObservedGeneration: app.Generation,
+	}
+	var failedReason string
+	switch conditionType {
+	case ConditionApplicationReady:
+		condition.Reason, condition.Message, failedReason = ConditionReasonApplicationCreated, ConditionMessageApplicationCreated,
+			ConditionReasonApplicationCreationFailed
+	case ConditionSecretReady:
+		condition.Reason, condition.Message, failedReason = ConditionReasonSecretCreated, ConditionMessageCredentialsSecretCreated,
+			ConditionReasonSecretCreationFailed
+	default:
+		return app.Status, errors.Errorf("unsupported condition type: %s", conditionType)
+	}
+	if err != nil {
+		condition.Status = kmetav1.ConditionFalse
+		condition.Reason = failedReason
+		var reasonErr ConditionReasonError
+		if errors.As(err, &reasonErr) {
+			condition.Reason = reasonErr.ConditionReason()
+		}
+		condition.Message = conditionMessage(err)
+	}
+	if existing := kmeta.FindStatusCondition(app.Status.Conditions, condition.Type); existing != nil &&
+		existing.Status == condition.Status && existing.Reason == condition.Reason && messagesEqual(existing.Message, condition.Message) {
+		condition.Message = existing.Message
+	}
+	kmeta.SetStatusCondition(&app.Status.Conditions, condition)
+
+	app.Status.State = StateNotReady
+	if kmeta.IsStatusConditionTrue(app.Status.Conditions, string(ConditionApplicationReady)) &&
+		kmeta.IsStatusConditionTrue(app.Status.Conditions, string(ConditionSecretReady)) {
+		app.Status.State = StateReady
+	}
+	return app.Status, nil
+}
+
 // determineEventingAuthState returns 'Ready' if both IAS app and secret are created, otherwise 'NoReady'.
 func determineEventingAuthState(status EventingAuthStatus) State {
 	var applicationReady, secretReady bool
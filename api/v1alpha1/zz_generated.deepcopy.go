@@ -26,6 +26,41 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationQuota) DeepCopyInto(out *ApplicationQuota) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationQuota.
+func (in *ApplicationQuota) DeepCopy() *ApplicationQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Auth0ProviderSpec) DeepCopyInto(out *Auth0ProviderSpec) {
+	*out = *in
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Auth0ProviderSpec.
+func (in *Auth0ProviderSpec) DeepCopy() *Auth0ProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(Auth0ProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AuthSecret) DeepCopyInto(out *AuthSecret) {
 	*out = *in
@@ -41,12 +76,62 @@ func (in *AuthSecret) DeepCopy() *AuthSecret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BTPSubaccount) DeepCopyInto(out *BTPSubaccount) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BTPSubaccount.
+func (in *BTPSubaccount) DeepCopy() *BTPSubaccount {
+	if in == nil {
+		return nil
+	}
+	out := new(BTPSubaccount)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterApplication) DeepCopyInto(out *ClusterApplication) {
+	*out = *in
+	if in.CredentialsIssuedTime != nil {
+		in, out := &in.CredentialsIssuedTime, &out.CredentialsIssuedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterApplication.
+func (in *ClusterApplication) DeepCopy() *ClusterApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsumerApplication) DeepCopyInto(out *ConsumerApplication) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsumerApplication.
+func (in *ConsumerApplication) DeepCopy() *ConsumerApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsumerApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EventingAuth) DeepCopyInto(out *EventingAuth) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -103,6 +188,41 @@ func (in *EventingAuthList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EventingAuthSpec) DeepCopyInto(out *EventingAuthSpec) {
 	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(SecretReference)
+		**out = **in
+	}
+	if in.KubeconfigSecretRef != nil {
+		in, out := &in.KubeconfigSecretRef, &out.KubeconfigSecretRef
+		*out = new(KubeconfigSecretReference)
+		**out = **in
+	}
+	if in.IAS != nil {
+		in, out := &in.IAS, &out.IAS
+		*out = new(IASProviderSpec)
+		**out = **in
+	}
+	if in.Keycloak != nil {
+		in, out := &in.Keycloak, &out.Keycloak
+		*out = new(KeycloakProviderSpec)
+		**out = **in
+	}
+	if in.Auth0 != nil {
+		in, out := &in.Auth0, &out.Auth0
+		*out = new(Auth0ProviderSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.XSUAA != nil {
+		in, out := &in.XSUAA, &out.XSUAA
+		*out = new(XSUAAProviderSpec)
+		**out = **in
+	}
+	if in.Okta != nil {
+		in, out := &in.Okta, &out.Okta
+		*out = new(OktaProviderSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventingAuthSpec.
@@ -120,14 +240,33 @@ func (in *EventingAuthStatus) DeepCopyInto(out *EventingAuthStatus) {
 	*out = *in
 	if in.Application != nil {
 		in, out := &in.Application, &out.Application
-		*out = new(IASApplication)
-		**out = **in
+		*out = new(ClusterApplication)
+		(*in).DeepCopyInto(*out)
 	}
 	if in.AuthSecret != nil {
 		in, out := &in.AuthSecret, &out.AuthSecret
 		*out = new(AuthSecret)
 		**out = **in
 	}
+	if in.ProvisionedTime != nil {
+		in, out := &in.ProvisionedTime, &out.ProvisionedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]ConsumerApplication, len(*in))
+		copy(*out, *in)
+	}
+	if in.Publisher != nil {
+		in, out := &in.Publisher, &out.Publisher
+		*out = new(PublisherApplication)
+		**out = **in
+	}
+	if in.ProvidedCredentials != nil {
+		in, out := &in.ProvidedCredentials, &out.ProvidedCredentials
+		*out = new(ProvidedCredentials)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -150,6 +289,10 @@ func (in *EventingAuthStatus) DeepCopy() *EventingAuthStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IASApplication) DeepCopyInto(out *IASApplication) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IASApplication.
@@ -161,3 +304,371 @@ func (in *IASApplication) DeepCopy() *IASApplication {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IASApplication) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IASApplicationAuth) DeepCopyInto(out *IASApplicationAuth) {
+	*out = *in
+	if in.GrantTypes != nil {
+		in, out := &in.GrantTypes, &out.GrantTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IASApplicationAuth.
+func (in *IASApplicationAuth) DeepCopy() *IASApplicationAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(IASApplicationAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IASApplicationList) DeepCopyInto(out *IASApplicationList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IASApplication, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IASApplicationList.
+func (in *IASApplicationList) DeepCopy() *IASApplicationList {
+	if in == nil {
+		return nil
+	}
+	out := new(IASApplicationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IASApplicationList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IASApplicationSpec) DeepCopyInto(out *IASApplicationSpec) {
+	*out = *in
+	if in.IAS != nil {
+		in, out := &in.IAS, &out.IAS
+		*out = new(IASProviderSpec)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(IASApplicationAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IASApplicationSpec.
+func (in *IASApplicationSpec) DeepCopy() *IASApplicationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IASApplicationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IASApplicationStatus) DeepCopyInto(out *IASApplicationStatus) {
+	*out = *in
+	if in.Application != nil {
+		in, out := &in.Application, &out.Application
+		*out = new(ClusterApplication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IASApplicationStatus.
+func (in *IASApplicationStatus) DeepCopy() *IASApplicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IASApplicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IASProviderSpec) DeepCopyInto(out *IASProviderSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IASProviderSpec.
+func (in *IASProviderSpec) DeepCopy() *IASProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IASProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IASTenant) DeepCopyInto(out *IASTenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IASTenant.
+func (in *IASTenant) DeepCopy() *IASTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(IASTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IASTenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IASTenantList) DeepCopyInto(out *IASTenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]IASTenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IASTenantList.
+func (in *IASTenantList) DeepCopy() *IASTenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(IASTenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *IASTenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IASTenantSpec) DeepCopyInto(out *IASTenantSpec) {
+	*out = *in
+	if in.Subaccount != nil {
+		in, out := &in.Subaccount, &out.Subaccount
+		*out = new(BTPSubaccount)
+		**out = **in
+	}
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimit)
+		**out = **in
+	}
+	if in.KymaSelector != nil {
+		in, out := &in.KymaSelector, &out.KymaSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApplicationQuota != nil {
+		in, out := &in.ApplicationQuota, &out.ApplicationQuota
+		*out = new(ApplicationQuota)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IASTenantSpec.
+func (in *IASTenantSpec) DeepCopy() *IASTenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(IASTenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IASTenantStatus) DeepCopyInto(out *IASTenantStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IASTenantStatus.
+func (in *IASTenantStatus) DeepCopy() *IASTenantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IASTenantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KeycloakProviderSpec) DeepCopyInto(out *KeycloakProviderSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeycloakProviderSpec.
+func (in *KeycloakProviderSpec) DeepCopy() *KeycloakProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KeycloakProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeconfigSecretReference) DeepCopyInto(out *KubeconfigSecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeconfigSecretReference.
+func (in *KubeconfigSecretReference) DeepCopy() *KubeconfigSecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeconfigSecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OktaProviderSpec) DeepCopyInto(out *OktaProviderSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OktaProviderSpec.
+func (in *OktaProviderSpec) DeepCopy() *OktaProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OktaProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvidedCredentials) DeepCopyInto(out *ProvidedCredentials) {
+	*out = *in
+	if in.ObservedTime != nil {
+		in, out := &in.ObservedTime, &out.ObservedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvidedCredentials.
+func (in *ProvidedCredentials) DeepCopy() *ProvidedCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvidedCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublisherApplication) DeepCopyInto(out *PublisherApplication) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublisherApplication.
+func (in *PublisherApplication) DeepCopy() *PublisherApplication {
+	if in == nil {
+		return nil
+	}
+	out := new(PublisherApplication)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimit) DeepCopyInto(out *RateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimit.
+func (in *RateLimit) DeepCopy() *RateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *XSUAAProviderSpec) DeepCopyInto(out *XSUAAProviderSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new XSUAAProviderSpec.
+func (in *XSUAAProviderSpec) DeepCopy() *XSUAAProviderSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(XSUAAProviderSpec)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// disabledBindAddress disables the metrics and health probe servers.
+const disabledBindAddress = "0"
+
+// bindAddressFlags are the flags of the addresses that the servers of the manager bind to.
+type bindAddressFlags struct {
+	metrics     string
+	healthProbe string
+	pprof       string
+	webhook     string
+	admin       string
+}
+
+// validate checks that the addresses are 'host:port' addresses that can be bound. An address without host, e.g. ':8080', binds to all
+// addresses, which is a dual-stack socket on hosts with IPv6 and an IPv4 socket otherwise. IPv6 addresses must be enclosed in brackets,
+// e.g. '[::]:8080' for all IPv6 addresses or '[::1]:8080' for the loopback address of IPv6-only hosts.
+func (f bindAddressFlags) validate() error {
+	for _, address := range []struct {
+		flag, value string
+		optional    bool
+	}{
+		{flag: "metrics-bind-address", value: f.metrics, optional: true},
+		{flag: "health-probe-bind-address", value: f.healthProbe, optional: true},
+		{flag: "pprof-bind-address", value: f.pprof, optional: true},
+		{flag: "webhook-bind-address", value: f.webhook},
+		{flag: "admin-bind-address", value: f.admin, optional: true},
+	} {
+		if address.optional && (address.value == "" || address.value == disabledBindAddress) {
+			continue
+		}
+		if _, _, err := splitBindAddress(address.value); err != nil {
+			return errors.Wrapf(err, "invalid --%s", address.flag)
+		}
+	}
+	return nil
+}
+
+// webhookHostAndPort returns the host and port of the address of the webhook server, whose options don't take an address.
+func (f bindAddressFlags) webhookHostAndPort() (string, int, error) {
+	return splitBindAddress(f.webhook)
+}
+
+// splitBindAddress splits a 'host:port' address into its host, without the brackets of IPv6 addresses, and its port.
+func splitBindAddress(address string) (string, int, error) {
+	host, portValue, err := net.SplitHostPort(address)
+	if err != nil {
+		if strings.Count(address, ":") > 1 && !strings.HasPrefix(address, "[") {
+			return "", 0, errors.Errorf("address %s must enclose the IPv6 address in brackets, e.g. '[::1]:8080'", address)
+		}
+		return "", 0, errors.Wrapf(err, "address %s must have the format 'host:port'", address)
+	}
+	port, err := strconv.ParseUint(portValue, 10, 16)
+	if err != nil {
+		return "", 0, errors.Errorf("address %s must have a port between 0 and 65535", address)
+	}
+	return host, int(port), nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_bindAddressFlags_validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenFlags bindAddressFlags
+		wantErr    string
+	}{
+		{
+			name:       "should accept the default addresses",
+			givenFlags: bindAddressFlags{metrics: ":8080", healthProbe: ":8081", webhook: ":9443"},
+		},
+		{
+			name:       "should accept IPv6 addresses",
+			givenFlags: bindAddressFlags{metrics: "[::1]:8080", healthProbe: "[::]:8081", pprof: "[fe80::1%eth0]:6060", webhook: "[::]:9443"},
+		},
+		{
+			name:       "should accept IPv4 addresses and host names",
+			givenFlags: bindAddressFlags{metrics: "127.0.0.1:8080", healthProbe: "localhost:8081", webhook: "0.0.0.0:9443"},
+		},
+		{
+			name:       "should accept disabled servers",
+			givenFlags: bindAddressFlags{metrics: "0", healthProbe: "0", webhook: ":9443"},
+		},
+		{
+			name:       "should fail for IPv6 addresses without brackets",
+			givenFlags: bindAddressFlags{metrics: "::1:8080", webhook: ":9443"},
+			wantErr:    "invalid --metrics-bind-address: address ::1:8080 must enclose the IPv6 address in brackets, e.g. '[::1]:8080'",
+		},
+		{
+			name:       "should fail for addresses without port",
+			givenFlags: bindAddressFlags{healthProbe: "8081", webhook: ":9443"},
+			wantErr:    "invalid --health-probe-bind-address: address 8081 must have the format 'host:port': address 8081: missing port in address",
+		},
+		{
+			name:       "should fail for invalid ports",
+			givenFlags: bindAddressFlags{webhook: "[::]:94430"},
+			wantErr:    "invalid --webhook-bind-address: address [::]:94430 must have a port between 0 and 65535",
+		},
+		{
+			name:       "should fail for an empty webhook address",
+			givenFlags: bindAddressFlags{},
+			wantErr:    "invalid --webhook-bind-address: address  must have the format 'host:port': missing port in address",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			err := tt.givenFlags.validate()
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_bindAddressFlags_webhookHostAndPort(t *testing.T) {
+	// given
+	flags := bindAddressFlags{webhook: "[fd00::1]:9443"}
+
+	// when
+	host, port, err := flags.webhookHostAndPort()
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "fd00::1", host)
+	require.Equal(t, 9443, port)
+}
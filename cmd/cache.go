@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cacheAllNamespaces caches the secrets of all namespaces.
+const cacheAllNamespaces = "*"
+
+// secretCacheFlags contains the flags of the cache of the secrets that need to be parsed.
+type secretCacheFlags struct {
+	namespaces    string
+	labelSelector string
+}
+
+// secretCache defines which secrets are cached by the manager. Secrets that aren't cached are read from the API server.
+type secretCache struct {
+	// namespaces are the namespaces of the cached secrets, or empty if the secrets of all namespaces are cached.
+	namespaces []string
+	// selector selects the cached secrets in the namespaces, or is nil if all secrets of the namespaces are cached.
+	selector labels.Selector
+}
+
+// newSecretCache returns the secrets that are cached according to the flags. The namespaces default to the given namespaces of the
+// kubeconfig and credentials secrets that the manager is configured with.
+func newSecretCache(flags secretCacheFlags, defaultNamespaces []string) (secretCache, error) {
+	var c secretCache
+	namespaces := defaultNamespaces
+	if strings.TrimSpace(flags.namespaces) != "" {
+		namespaces = strings.Split(flags.namespaces, ",")
+	}
+	unique := map[string]bool{}
+	for _, namespace := range namespaces {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == cacheAllNamespaces {
+			unique = map[string]bool{}
+			break
+		}
+		if namespace != "" {
+			unique[namespace] = true
+		}
+	}
+	for namespace := range unique {
+		c.namespaces = append(c.namespaces, namespace)
+	}
+	sort.Strings(c.namespaces)
+
+	if flags.labelSelector != "" {
+		selector, err := labels.Parse(flags.labelSelector)
+		if err != nil {
+			return secretCache{}, errors.Wrap(err, "failed to parse --cache-secret-label-selector")
+		}
+		c.selector = selector
+	}
+	return c, nil
+}
+
+// byObject returns the options of the cache of the manager for the secrets.
+func (c secretCache) byObject() map[kpkgclient.Object]cache.ByObject {
+	if len(c.namespaces) == 0 && c.selector == nil {
+		return nil
+	}
+	byObject := cache.ByObject{Label: c.selector}
+	if len(c.namespaces) > 0 {
+		byObject.Namespaces = map[string]cache.Config{}
+		for _, namespace := range c.namespaces {
+			byObject.Namespaces[namespace] = cache.Config{}
+		}
+	}
+	return map[kpkgclient.Object]cache.ByObject{&kcorev1.Secret{}: byObject}
+}
+
+// newClient returns the function that creates the client of the manager, which reads the secrets that aren't cached from the API server.
+func (c secretCache) newClient() func(config *rest.Config, options kpkgclient.Options) (kpkgclient.Client, error) {
+	return func(config *rest.Config, options kpkgclient.Options) (kpkgclient.Client, error) {
+		cached, err := kpkgclient.New(config, options)
+		if err != nil || (len(c.namespaces) == 0 && c.selector == nil) {
+			return cached, err
+		}
+		options.Cache = nil
+		uncached, err := kpkgclient.New(config, options)
+		if err != nil {
+			return nil, err
+		}
+		return newSecretCacheClient(cached, uncached, c), nil
+	}
+}
+
+// secretCacheClient reads the secrets that aren't in the namespaces of the cache with the uncached reader. If the cache only contains
+// the secrets with the labels of a selector, secrets that aren't found in the cache are read with the uncached reader as well.
+type secretCacheClient struct {
+	kpkgclient.Client
+	uncached   kpkgclient.Reader
+	namespaces map[string]bool
+	selector   labels.Selector
+}
+
+func newSecretCacheClient(cached kpkgclient.Client, uncached kpkgclient.Reader, c secretCache) kpkgclient.Client {
+	namespaces := map[string]bool{}
+	for _, namespace := range c.namespaces {
+		namespaces[namespace] = true
+	}
+	return &secretCacheClient{Client: cached, uncached: uncached, namespaces: namespaces, selector: c.selector}
+}
+
+func (c *secretCacheClient) Get(ctx context.Context, key kpkgclient.ObjectKey, obj kpkgclient.Object, opts ...kpkgclient.GetOption) error {
+	if _, ok := obj.(*kcorev1.Secret); !ok {
+		return c.Client.Get(ctx, key, obj, opts...)
+	}
+	if !c.cached(key.Namespace) {
+		return c.uncached.Get(ctx, key, obj, opts...)
+	}
+	err := c.Client.Get(ctx, key, obj, opts...)
+	if c.selector != nil && kapierrors.IsNotFound(err) {
+		return c.uncached.Get(ctx, key, obj, opts...)
+	}
+	return err
+}
+
+func (c *secretCacheClient) List(ctx context.Context, list kpkgclient.ObjectList, opts ...kpkgclient.ListOption) error {
+	if _, ok := list.(*kcorev1.SecretList); ok && !c.cached((&kpkgclient.ListOptions{}).ApplyOptions(opts).Namespace) {
+		return c.uncached.List(ctx, list, opts...)
+	}
+	return c.Client.List(ctx, list, opts...)
+}
+
+// cached returns whether the secrets of the namespace are cached. The secrets of all namespaces are only cached if the cache isn't
+// restricted to namespaces.
+func (c *secretCacheClient) cached(namespace string) bool {
+	return len(c.namespaces) == 0 || c.namespaces[namespace]
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_newSecretCache(t *testing.T) {
+	tests := []struct {
+		name                string
+		givenFlags          secretCacheFlags
+		wantNamespaces      []string
+		wantSelector        string
+		wantErr             bool
+		wantCacheAllSecrets bool
+	}{
+		{
+			name:           "should default to the namespaces of the kubeconfig and credentials secrets",
+			wantNamespaces: []string{"kcp-system", "keycloak"},
+		},
+		{
+			name:           "should use the namespaces of the flag",
+			givenFlags:     secretCacheFlags{namespaces: "kcp-system, eventing-auth,"},
+			wantNamespaces: []string{"eventing-auth", "kcp-system"},
+		},
+		{
+			name:                "should cache the secrets of all namespaces",
+			givenFlags:          secretCacheFlags{namespaces: "*"},
+			wantCacheAllSecrets: true,
+		},
+		{
+			name:           "should use the label selector of the flag",
+			givenFlags:     secretCacheFlags{labelSelector: "operator.kyma-project.io/managed-by=kcp"},
+			wantNamespaces: []string{"kcp-system", "keycloak"},
+			wantSelector:   "operator.kyma-project.io/managed-by=kcp",
+		},
+		{
+			name:       "should fail for an invalid label selector",
+			givenFlags: secretCacheFlags{labelSelector: "invalid selector!"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			c, err := newSecretCache(tt.givenFlags, []string{"kcp-system", "keycloak", "kcp-system"})
+
+			// then
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantNamespaces, c.namespaces)
+			if tt.wantSelector != "" {
+				require.Equal(t, tt.wantSelector, c.selector.String())
+			}
+			if tt.wantCacheAllSecrets {
+				require.Nil(t, c.byObject())
+			} else {
+				require.Len(t, c.byObject(), 1)
+			}
+		})
+	}
+}
+
+func Test_secretCacheClient(t *testing.T) {
+	// given
+	cachedSecret := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "kubeconfig-runtime"}}
+	uncachedSecret := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: "tenant-namespace", Name: "ias-creds"}}
+	unlabeledSecret := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "unlabeled"}}
+	cached := fake.NewClientBuilder().WithObjects(cachedSecret).Build()
+	uncached := fake.NewClientBuilder().WithObjects(cachedSecret, uncachedSecret, unlabeledSecret).Build()
+	c, err := newSecretCache(secretCacheFlags{namespaces: "kcp-system", labelSelector: "managed"}, nil)
+	require.NoError(t, err)
+	client := newSecretCacheClient(cached, uncached, c)
+	ctx := context.Background()
+
+	// when
+	getCachedErr := client.Get(ctx, kpkgclient.ObjectKeyFromObject(cachedSecret), &kcorev1.Secret{})
+	getUncachedErr := client.Get(ctx, kpkgclient.ObjectKeyFromObject(uncachedSecret), &kcorev1.Secret{})
+	getUnlabeledErr := client.Get(ctx, kpkgclient.ObjectKeyFromObject(unlabeledSecret), &kcorev1.Secret{})
+	getMissingErr := client.Get(ctx, kpkgclient.ObjectKey{Namespace: "kcp-system", Name: "missing"}, &kcorev1.Secret{})
+	var cachedList, allList kcorev1.SecretList
+	listCachedErr := client.List(ctx, &cachedList, kpkgclient.InNamespace("kcp-system"))
+	listAllErr := client.List(ctx, &allList)
+
+	// then
+	require.NoError(t, getCachedErr)
+	require.NoError(t, getUncachedErr)
+	require.NoError(t, getUnlabeledErr)
+	require.True(t, kapierrors.IsNotFound(getMissingErr))
+	require.NoError(t, listCachedErr)
+	require.Len(t, cachedList.Items, 1)
+	require.NoError(t, listAllErr)
+	require.Len(t, allList.Items, 3)
+}
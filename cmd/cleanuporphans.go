@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamcontrollers "github.com/kyma-project/eventing-auth-manager/controllers"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// cleanupOrphansCommand is the subcommand that deletes the IAS applications of the manager whose EventingAuth CR doesn't exist anymore.
+const cleanupOrphansCommand = "cleanup-orphans"
+
+// defaultCleanupOrphansTimeout is the default timeout of the cleanup of the orphaned applications.
+const defaultCleanupOrphansTimeout = 5 * time.Minute
+
+// cleanupOrphansFlags are the flags of the cleanup-orphans subcommand.
+type cleanupOrphansFlags struct {
+	tenant     string
+	dryRun     bool
+	confirm    bool
+	kubeconfig string
+	timeout    time.Duration
+}
+
+// runCleanupOrphans runs the cleanup-orphans subcommand with the arguments after the name of the subcommand. It prints the orphaned
+// applications and only deletes them with '--confirm'. It returns the exit code of the command, which is 0 unless a step failed.
+func runCleanupOrphans(args []string, stdout, stderr io.Writer) int {
+	var flags cleanupOrphansFlags
+	fs := flag.NewFlagSet(cleanupOrphansCommand, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&flags.tenant, "tenant", "",
+		"The name of the IAS tenant of an IASTenant CR or of a labeled IAS credentials secret. "+
+			"Defaults to the tenant of the credentials secret of the manager.")
+	fs.BoolVar(&flags.dryRun, "dry-run", false, "Print the orphaned applications without deleting them, which is the default without '--confirm'.")
+	fs.BoolVar(&flags.confirm, "confirm", false, "Delete the orphaned applications.")
+	fs.StringVar(&flags.kubeconfig, "kubeconfig", "",
+		"The path to the kubeconfig of the cluster of the EventingAuth CRs. Defaults to the kubeconfig of the environment or the in-cluster config.")
+	fs.DurationVar(&flags.timeout, "timeout", defaultCleanupOrphansTimeout, "The timeout of the cleanup.")
+	if err := fs.Parse(args); err != nil {
+		return exitCodeUsage
+	}
+	if flags.dryRun && flags.confirm {
+		fmt.Fprintln(stderr, "--dry-run and --confirm are mutually exclusive")
+		return exitCodeUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flags.timeout)
+	defer cancel()
+	c, err := newCommandClient(flags.kubeconfig)
+	if err != nil {
+		fmt.Fprintf(stdout, "FAIL: %s\n", redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	iasClient, err := provider.NewIASClientPool(c).Get(ctx, flags.tenant)
+	if err != nil {
+		fmt.Fprintf(stdout, "FAIL: failed to read the credentials of the IAS tenant: %s\n", redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	if err := cleanupOrphans(ctx, c, iasClient, flags.confirm, stdout); err != nil {
+		fmt.Fprintf(stdout, "FAIL: %s\n", redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	return 0
+}
+
+// cleanupOrphans prints the applications of the IAS tenant that are marked as managed by the manager but have no EventingAuth or
+// IASApplication CR with their name, and deletes them if confirmed. The applications are listed before the EventingAuth CRs, so that the application of an
+// EventingAuth CR that is created meanwhile isn't considered orphaned.
+func cleanupOrphans(ctx context.Context, c kpkgclient.Reader, iasClient eamias.Client, confirm bool, out io.Writer) error {
+	applications, err := iasClient.ListManagedApplications(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the applications of the IAS tenant")
+	}
+	var eventingAuths eamapiv1alpha1.EventingAuthList
+	if err := c.List(ctx, &eventingAuths); err != nil {
+		return errors.Wrap(err, "failed to list the EventingAuth CRs")
+	}
+	// The applications are named after their EventingAuth CRs. The namespace is ignored, so that an application is never deleted while
+	// an EventingAuth CR of its name exists. The shared applications, and the applications of the event publishers and consumers, are
+	// named as recorded in the status of their EventingAuth CRs.
+	owned := make(map[string]bool, len(eventingAuths.Items))
+	for _, eventingAuth := range eventingAuths.Items {
+		owned[eventingAuth.Name] = true
+		if eventingAuth.Status.Application != nil {
+			owned[eventingAuth.Status.Application.Name] = true
+		}
+		if eventingAuth.Status.Publisher != nil {
+			owned[eventingAuth.Status.Publisher.Name] = true
+		}
+		for _, consumer := range eventingAuth.Status.Consumers {
+			owned[consumer.Name] = true
+		}
+	}
+	// The applications of the IASApplication CRs are owned as well. The CRD of the IASApplication CRs is only installed if their controller
+	// is enabled.
+	var iasApplications eamapiv1alpha1.IASApplicationList
+	if err := c.List(ctx, &iasApplications); err != nil && !kmeta.IsNoMatchError(err) {
+		return errors.Wrap(err, "failed to list the IASApplication CRs")
+	}
+	for _, iasApplication := range iasApplications.Items {
+		owned[eamcontrollers.IASApplicationName(iasApplication)] = true
+	}
+
+	var orphans []eamias.ManagedApplication
+	for _, application := range applications {
+		if !owned[application.Name] {
+			orphans = append(orphans, application)
+		}
+	}
+	fmt.Fprintf(out, "IAS tenant %s: %d managed applications, %d orphaned\n", iasClient.GetCredentials().URL, len(applications), len(orphans))
+
+	var failed int
+	for _, orphan := range orphans {
+		created := "unknown"
+		if orphan.Created != nil {
+			created = orphan.Created.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(out, "  %s (ID %s, created %s): ", orphan.Name, orphan.ID, created)
+		if !confirm {
+			fmt.Fprintln(out, "would be deleted")
+			continue
+		}
+		err := iasClient.DeleteApplicationByID(ctx, orphan.ID, orphan.Name)
+		if eamias.IsApplicationChangedError(err) {
+			// The application was renamed or unmarked since it was listed, so it isn't an orphan of the manager anymore.
+			fmt.Fprintf(out, "skipped, %s\n", err.Error())
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(out, "FAIL: %s\n", redact.String(err.Error()))
+			failed++
+			continue
+		}
+		fmt.Fprintln(out, "deleted")
+	}
+	if !confirm && len(orphans) > 0 {
+		fmt.Fprintln(out, "Dry run: run with --confirm to delete the orphaned applications.")
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to delete %d of %d orphaned applications", failed, len(orphans))
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type orphansIASClientStub struct {
+	eamias.Client
+	applications []eamias.ManagedApplication
+	failDelete   string
+	changed      string
+	deleted      []string
+}
+
+func (c *orphansIASClientStub) ListManagedApplications(_ context.Context) ([]eamias.ManagedApplication, error) {
+	return c.applications, nil
+}
+
+func (c *orphansIASClientStub) DeleteApplicationByID(_ context.Context, id, name string) error {
+	if id == c.failDelete {
+		return errors.New("unexpected status code 500")
+	}
+	if id == c.changed {
+		return &eamias.ApplicationChangedError{ID: id, Name: name}
+	}
+	c.deleted = append(c.deleted, id)
+	return nil
+}
+
+func (c *orphansIASClientStub) GetCredentials() *eamias.Credentials {
+	return &eamias.Credentials{URL: "https://tenant.accounts.ondemand.com"}
+}
+
+func Test_cleanupOrphans(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	applications := []eamias.ManagedApplication{
+		{ID: "id-a", Name: "runtime-a", Created: &created},
+		{ID: "id-b", Name: "runtime-b"},
+		{ID: "id-c", Name: "runtime-c"},
+	}
+	tests := []struct {
+		name         string
+		givenFail    string
+		givenChanged string
+		givenConfirm bool
+		wantDeleted  []string
+		wantErr      string
+		wantReport   string
+	}{
+		{
+			name: "should only print the orphaned applications without confirmation",
+			wantReport: "IAS tenant https://tenant.accounts.ondemand.com: 3 managed applications, 2 orphaned\n" +
+				"  runtime-a (ID id-a, created 2024-03-01T12:00:00Z): would be deleted\n" +
+				"  runtime-c (ID id-c, created unknown): would be deleted\n" +
+				"Dry run: run with --confirm to delete the orphaned applications.\n",
+		},
+		{
+			name:         "should delete the orphaned applications with confirmation",
+			givenConfirm: true,
+			wantDeleted:  []string{"id-a", "id-c"},
+			wantReport: "IAS tenant https://tenant.accounts.ondemand.com: 3 managed applications, 2 orphaned\n" +
+				"  runtime-a (ID id-a, created 2024-03-01T12:00:00Z): deleted\n" +
+				"  runtime-c (ID id-c, created unknown): deleted\n",
+		},
+		{
+			name:         "should continue with the next application if a deletion fails",
+			givenFail:    "id-a",
+			givenConfirm: true,
+			wantDeleted:  []string{"id-c"},
+			wantErr:      "failed to delete 1 of 2 orphaned applications",
+			wantReport: "IAS tenant https://tenant.accounts.ondemand.com: 3 managed applications, 2 orphaned\n" +
+				"  runtime-a (ID id-a, created 2024-03-01T12:00:00Z): FAIL: unexpected status code 500\n" +
+				"  runtime-c (ID id-c, created unknown): deleted\n",
+		},
+		{
+			name:         "should skip an application that changed since it was listed",
+			givenChanged: "id-a",
+			givenConfirm: true,
+			wantDeleted:  []string{"id-c"},
+			wantReport: "IAS tenant https://tenant.accounts.ondemand.com: 3 managed applications, 2 orphaned\n" +
+				"  runtime-a (ID id-a, created 2024-03-01T12:00:00Z): skipped, application id-a is not the managed application runtime-a anymore\n" +
+				"  runtime-c (ID id-c, created unknown): deleted\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			eventingAuth := &eamapiv1alpha1.EventingAuth{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-b"}}
+			c := fake.NewClientBuilder().WithScheme(initScheme()).WithObjects(eventingAuth).Build()
+			iasClient := &orphansIASClientStub{applications: applications, failDelete: tt.givenFail, changed: tt.givenChanged}
+			var out bytes.Buffer
+
+			// when
+			err := cleanupOrphans(context.Background(), c, iasClient, tt.givenConfirm, &out)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantDeleted, iasClient.deleted)
+			require.Equal(t, tt.wantReport, out.String())
+		})
+	}
+}
+
+func Test_cleanupOrphans_ApplicationsOfStatus(t *testing.T) {
+	// given
+	eventingAuth := &eamapiv1alpha1.EventingAuth{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-b"},
+		Spec:       eamapiv1alpha1.EventingAuthSpec{SharedApplication: "trial"},
+		Status: eamapiv1alpha1.EventingAuthStatus{
+			Application: &eamapiv1alpha1.ClusterApplication{Name: "shared-trial", UUID: "id-shared"},
+			Publisher:   &eamapiv1alpha1.PublisherApplication{Name: "runtime-b-publisher", UUID: "id-publisher"},
+			Consumers:   []eamapiv1alpha1.ConsumerApplication{{Consumer: "orders", Name: "runtime-b.orders", UUID: "id-orders"}},
+		},
+	}
+	iasApplication := &eamapiv1alpha1.IASApplication{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "telemetry"},
+		Spec:       eamapiv1alpha1.IASApplicationSpec{ApplicationName: "telemetry-otlp", SecretName: "telemetry-otlp-oidc"},
+	}
+	defaultNamedIASApplication := &eamapiv1alpha1.IASApplication{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "kyma-system", Name: "connectivity", UID: "5f0e1a2b-3c4d-4e5f-8a9b-0c1d2e3f4a5b"},
+		Spec:       eamapiv1alpha1.IASApplicationSpec{SecretName: "connectivity-oidc"},
+	}
+	c := fake.NewClientBuilder().WithScheme(initScheme()).WithObjects(eventingAuth, iasApplication, defaultNamedIASApplication).Build()
+	iasClient := &orphansIASClientStub{applications: []eamias.ManagedApplication{
+		{ID: "id-shared", Name: "shared-trial"},
+		{ID: "id-telemetry", Name: "telemetry-otlp"},
+		{ID: "id-connectivity", Name: "kyma-system-connectivity-5f0e1a2b"},
+		{ID: "id-publisher", Name: "runtime-b-publisher"},
+		{ID: "id-orders", Name: "runtime-b.orders"},
+		{ID: "id-d", Name: "runtime-d"},
+	}}
+	var out bytes.Buffer
+
+	// when
+	err := cleanupOrphans(context.Background(), c, iasClient, true, &out)
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, []string{"id-d"}, iasClient.deleted)
+}
+
+func Test_runCleanupOrphans_InvalidUsage(t *testing.T) {
+	// given
+	var stdout, stderr bytes.Buffer
+
+	// when
+	exitCode := runCleanupOrphans([]string{"--dry-run", "--confirm"}, &stdout, &stderr)
+
+	// then
+	require.Equal(t, exitCodeUsage, exitCode)
+	require.Equal(t, "--dry-run and --confirm are mutually exclusive\n", stderr.String())
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"flag"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// The apiVersion and kind of the config file of the manager.
+const (
+	configAPIVersion = "operator.kyma-project.io/v1alpha1"
+	configKind       = "EventingAuthManagerConfig"
+)
+
+// configFileFlag is the flag of the path to the config file, which can't be set in the config file itself.
+const configFileFlag = "config"
+
+// applyConfigFile sets the flags of the flag set that aren't set on the command line to the options of the config file, so that flags on
+// the command line override the config file. The options are the flags by their name, e.g. 'metrics-bind-address: ":8080"'. Nested
+// options are joined with '-' to the name of the flag, e.g. 'skr: {client-qps: 20}' sets '--skr-client-qps'. Lists are joined with ',',
+// and maps of flags that accept 'key=value' pairs are joined with ',' to pairs with URL-encoded values.
+func applyConfigFile(fs *flag.FlagSet, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to read the config file")
+	}
+	var config map[string]interface{}
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return errors.Wrap(err, "failed to decode the config file")
+	}
+	if config["apiVersion"] != configAPIVersion || config["kind"] != configKind {
+		return errors.Errorf("config file must have the apiVersion %s and the kind %s", configAPIVersion, configKind)
+	}
+	delete(config, "apiVersion")
+	delete(config, "kind")
+
+	options := map[string]string{}
+	if err := flattenOptions(fs, "", config, options); err != nil {
+		return err
+	}
+	setOnCommandLine := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Name] = true
+	})
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if setOnCommandLine[name] {
+			continue
+		}
+		if err := fs.Set(name, options[name]); err != nil {
+			return errors.Wrapf(err, "invalid option %s of the config file", name)
+		}
+	}
+	return nil
+}
+
+// flattenOptions adds the options of the config to the flag values by the names of the flags.
+func flattenOptions(fs *flag.FlagSet, prefix string, config map[string]interface{}, options map[string]string) error {
+	for key, value := range config {
+		name := key
+		if prefix != "" {
+			name = prefix + "-" + key
+		}
+		if name == configFileFlag {
+			return errors.Errorf("option %s can't be set in the config file", configFileFlag)
+		}
+		if nested, ok := value.(map[string]interface{}); ok && fs.Lookup(name) == nil {
+			if err := flattenOptions(fs, name, nested, options); err != nil {
+				return err
+			}
+			continue
+		}
+		if fs.Lookup(name) == nil {
+			return errors.Errorf("unknown option %s of the config file", name)
+		}
+		if value == nil {
+			continue
+		}
+		flagValue, err := flagValueOf(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid option %s of the config file", name)
+		}
+		options[name] = flagValue
+	}
+	return nil
+}
+
+// flagValueOf returns the value of an option in the format of the flag.
+func flagValueOf(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case []interface{}:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			s, err := flagValueOf(item)
+			if err != nil {
+				return "", err
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, ","), nil
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, 0, len(keys))
+		for _, key := range keys {
+			s, err := flagValueOf(v[key])
+			if err != nil {
+				return "", err
+			}
+			pairs = append(pairs, key+"="+url.QueryEscape(s))
+		}
+		return strings.Join(pairs, ","), nil
+	default:
+		return "", errors.Errorf("unsupported value %v", v)
+	}
+}
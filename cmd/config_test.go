@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testFlags struct {
+	metricsAddr      string
+	leaderElect      bool
+	skrClientQPS     float64
+	skrClientBurst   int
+	skrCacheTTL      time.Duration
+	skrNamespaces    string
+	otlpHeaders      string
+	configFile       string
+	notInConfigValue string
+}
+
+func newTestFlagSet(flags *testFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.StringVar(&flags.metricsAddr, "metrics-bind-address", ":8080", "")
+	fs.BoolVar(&flags.leaderElect, "leader-elect", false, "")
+	fs.Float64Var(&flags.skrClientQPS, "skr-client-qps", 5, "")
+	fs.IntVar(&flags.skrClientBurst, "skr-client-burst", 10, "")
+	fs.DurationVar(&flags.skrCacheTTL, "skr-client-cache-ttl", time.Hour, "")
+	fs.StringVar(&flags.skrNamespaces, "skr-secret-namespaces", "kyma-system", "")
+	fs.StringVar(&flags.otlpHeaders, "otlp-metrics-headers", "", "")
+	fs.StringVar(&flags.configFile, configFileFlag, "", "")
+	fs.StringVar(&flags.notInConfigValue, "landscape", "dev", "")
+	return fs
+}
+
+func Test_applyConfigFile(t *testing.T) {
+	// given
+	config := `apiVersion: operator.kyma-project.io/v1alpha1
+kind: EventingAuthManagerConfig
+metrics-bind-address: ":9090"
+leader-elect: true
+skr:
+  client-qps: 20.5
+  client-burst: 40
+  client-cache-ttl: 30m
+  secret-namespaces:
+  - kyma-system
+  - default
+otlp-metrics-headers:
+  Authorization: Bearer token
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+	var flags testFlags
+	fs := newTestFlagSet(&flags)
+	require.NoError(t, fs.Parse([]string{"--config", path, "--skr-client-burst", "50"}))
+
+	// when
+	err := applyConfigFile(fs, path)
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, ":9090", flags.metricsAddr)
+	require.True(t, flags.leaderElect)
+	require.InDelta(t, 20.5, flags.skrClientQPS, 0)
+	require.Equal(t, 50, flags.skrClientBurst, "the flag on the command line overrides the config file")
+	require.Equal(t, 30*time.Minute, flags.skrCacheTTL)
+	require.Equal(t, "kyma-system,default", flags.skrNamespaces)
+	require.Equal(t, "Authorization=Bearer+token", flags.otlpHeaders)
+	require.Equal(t, "dev", flags.notInConfigValue)
+}
+
+func Test_applyConfigFile_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenConfig string
+		wantErr     string
+	}{
+		{
+			name:        "should fail without apiVersion and kind",
+			givenConfig: `metrics-bind-address: ":9090"`,
+			wantErr:     "config file must have the apiVersion operator.kyma-project.io/v1alpha1 and the kind EventingAuthManagerConfig",
+		},
+		{
+			name: "should fail for unknown options",
+			givenConfig: `apiVersion: operator.kyma-project.io/v1alpha1
+kind: EventingAuthManagerConfig
+skr:
+  client-qbs: 20`,
+			wantErr: "unknown option skr-client-qbs of the config file",
+		},
+		{
+			name: "should fail for invalid values",
+			givenConfig: `apiVersion: operator.kyma-project.io/v1alpha1
+kind: EventingAuthManagerConfig
+skr-client-cache-ttl: 1 hour`,
+			wantErr: "invalid option skr-client-cache-ttl of the config file",
+		},
+		{
+			name: "should fail if the config file is set in the config file",
+			givenConfig: `apiVersion: operator.kyma-project.io/v1alpha1
+kind: EventingAuthManagerConfig
+config: other.yaml`,
+			wantErr: "option config can't be set in the config file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.givenConfig), 0o600))
+			var flags testFlags
+			fs := newTestFlagSet(&flags)
+
+			// when
+			err := applyConfigFile(fs, path)
+
+			// then
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/kyma-project/eventing-auth-manager/internal/admin"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// exportCommand is the subcommand that exports the inventory of the managed applications for compliance reporting and capacity planning.
+const exportCommand = "export"
+
+// The formats of the inventory.
+const (
+	exportFormatJSON = "json"
+	exportFormatCSV  = "csv"
+)
+
+// defaultExportTimeout is the default timeout of the export of the inventory.
+const defaultExportTimeout = 30 * time.Second
+
+// exportFlags are the flags of the export subcommand.
+type exportFlags struct {
+	format     string
+	output     string
+	kubeconfig string
+	timeout    time.Duration
+}
+
+// inventoryCSVHeader is the header of the CSV format of the inventory, whose columns are the fields of the JSON format of the items.
+var inventoryCSVHeader = []string{
+	"namespace", "name", "provider", "tenant", "applicationName", "applicationID", "secretTarget", "secretClusterID", "state", "lastError",
+	"provisionedTime", "credentialsIssuedTime", "credentialsAgeSeconds",
+}
+
+// runExport runs the export subcommand with the arguments after the name of the subcommand. It writes the inventory of the EventingAuth
+// CRs of the cluster and returns the exit code of the command, which is 0 if the inventory was written.
+func runExport(args []string, stdout, stderr io.Writer) int {
+	var flags exportFlags
+	fs := flag.NewFlagSet(exportCommand, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&flags.format, "format", exportFormatJSON, "The format of the inventory, either 'json' or 'csv'.")
+	fs.StringVar(&flags.output, "output", "", "The path of the file that the inventory is written to. Defaults to stdout.")
+	fs.StringVar(&flags.kubeconfig, "kubeconfig", "",
+		"The path to the kubeconfig of the cluster of the EventingAuth CRs. Defaults to the kubeconfig of the environment or the in-cluster config.")
+	fs.DurationVar(&flags.timeout, "timeout", defaultExportTimeout, "The timeout of the export.")
+	if err := fs.Parse(args); err != nil {
+		return exitCodeUsage
+	}
+	if flags.format != exportFormatJSON && flags.format != exportFormatCSV {
+		fmt.Fprintf(stderr, "--format must be '%s' or '%s'\n", exportFormatJSON, exportFormatCSV)
+		return exitCodeUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flags.timeout)
+	defer cancel()
+	c, err := newCommandClient(flags.kubeconfig)
+	if err != nil {
+		fmt.Fprintf(stderr, "FAIL: %s\n", redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	out := stdout
+	if flags.output != "" {
+		file, err := os.Create(flags.output)
+		if err != nil {
+			fmt.Fprintf(stderr, "FAIL: %s\n", err)
+			return exitCodeFailed
+		}
+		defer file.Close()
+		out = file
+	}
+	if err := exportInventory(ctx, c, flags.format, time.Now(), out); err != nil {
+		fmt.Fprintf(stderr, "FAIL: %s\n", redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	return 0
+}
+
+// exportInventory writes the inventory of the EventingAuth CRs of the reader in the format, with the ages of the credentials at the time now.
+func exportInventory(ctx context.Context, reader kpkgclient.Reader, format string, now time.Time, out io.Writer) error {
+	applications, err := admin.ListApplications(ctx, reader)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the EventingAuth CRs")
+	}
+	inventory := admin.NewInventory(applications, now)
+
+	if format == exportFormatCSV {
+		return writeInventoryCSV(inventory.Items, out)
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(inventory)
+}
+
+// writeInventoryCSV writes the items of the inventory as CSV with a header row. Unknown times and ages are empty.
+func writeInventoryCSV(items []admin.InventoryItem, out io.Writer) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(inventoryCSVHeader); err != nil {
+		return err
+	}
+	for _, item := range items {
+		age := ""
+		if item.CredentialsAgeSeconds != nil {
+			age = strconv.FormatInt(*item.CredentialsAgeSeconds, 10)
+		}
+		if err := w.Write([]string{
+			item.Namespace, item.Name, item.Provider, item.Tenant, item.ApplicationName, item.ApplicationID, item.SecretTarget,
+			item.SecretClusterID, string(item.State), item.LastError, formatInventoryTime(item.ProvisionedTime),
+			formatInventoryTime(item.CredentialsIssuedTime), age,
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// formatInventoryTime returns the time in RFC 3339 format, or an empty string if it is unset.
+func formatInventoryTime(t *kmetav1.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_exportInventory(t *testing.T) {
+	now := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	provisioned := kmetav1.NewTime(time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC))
+	issued := kmetav1.NewTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	eventingAuths := []*eamapiv1alpha1.EventingAuth{
+		{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-b"},
+			Spec:       eamapiv1alpha1.EventingAuthSpec{IAS: &eamapiv1alpha1.IASProviderSpec{Tenant: "tenant-a"}},
+			Status: eamapiv1alpha1.EventingAuthStatus{
+				State: eamapiv1alpha1.StateReady,
+				Application: &eamapiv1alpha1.ClusterApplication{Name: "runtime-b", UUID: "id-b", Tenant: "tenant-a",
+					CredentialsIssuedTime: &issued},
+				AuthSecret:      &eamapiv1alpha1.AuthSecret{NamespacedName: "kyma-system/eventing-webhook-auth", ClusterID: "runtime-b"},
+				ProvisionedTime: &provisioned,
+			},
+		},
+		{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-a"},
+			Status: eamapiv1alpha1.EventingAuthStatus{
+				State:       eamapiv1alpha1.StateReady,
+				Application: &eamapiv1alpha1.ClusterApplication{Name: "runtime-a", UUID: "id-a"},
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		givenFormat string
+		wantOutput  string
+	}{
+		{
+			name:        "should export the inventory as CSV",
+			givenFormat: exportFormatCSV,
+			wantOutput: "namespace,name,provider,tenant,applicationName,applicationID,secretTarget,secretClusterID,state,lastError," +
+				"provisionedTime,credentialsIssuedTime,credentialsAgeSeconds\n" +
+				"kcp-system,runtime-a,,,runtime-a,id-a,,,Ready,,,,\n" +
+				"kcp-system,runtime-b,,tenant-a,runtime-b,id-b,kyma-system/eventing-webhook-auth,runtime-b,Ready,," +
+				"2024-03-01T11:00:00Z,2024-03-01T12:00:00Z,86400\n",
+		},
+		{
+			name:        "should export the inventory as JSON",
+			givenFormat: exportFormatJSON,
+			wantOutput: `{
+  "generatedTime": "2024-03-02T12:00:00Z",
+  "items": [
+    {
+      "namespace": "kcp-system",
+      "name": "runtime-a",
+      "applicationName": "runtime-a",
+      "applicationID": "id-a",
+      "state": "Ready"
+    },
+    {
+      "namespace": "kcp-system",
+      "name": "runtime-b",
+      "tenant": "tenant-a",
+      "applicationName": "runtime-b",
+      "applicationID": "id-b",
+      "secretTarget": "kyma-system/eventing-webhook-auth",
+      "secretClusterID": "runtime-b",
+      "state": "Ready",
+      "provisionedTime": "2024-03-01T11:00:00Z",
+      "credentialsIssuedTime": "2024-03-01T12:00:00Z",
+      "credentialsAgeSeconds": 86400
+    }
+  ]
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			builder := fake.NewClientBuilder().WithScheme(initScheme())
+			for _, eventingAuth := range eventingAuths {
+				builder = builder.WithObjects(eventingAuth.DeepCopy())
+			}
+			c := builder.Build()
+			var out bytes.Buffer
+
+			// when
+			err := exportInventory(context.Background(), c, tt.givenFormat, now, &out)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tt.wantOutput, out.String())
+			if tt.givenFormat == exportFormatJSON {
+				require.True(t, json.Valid(out.Bytes()))
+			}
+		})
+	}
+}
+
+func Test_runExport_InvalidUsage(t *testing.T) {
+	// given
+	var stdout, stderr bytes.Buffer
+
+	// when
+	exitCode := runExport([]string{"--format", "yaml"}, &stdout, &stderr)
+
+	// then
+	require.Equal(t, exitCodeUsage, exitCode)
+	require.Equal(t, "--format must be 'json' or 'csv'\n", stderr.String())
+	require.Empty(t, stdout.String())
+}
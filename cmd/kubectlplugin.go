@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamcontrollers "github.com/kyma-project/eventing-auth-manager/controllers"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// kubectlPluginName is the name of the binary of the manager that runs it as kubectl plugin, e.g. 'kubectl eventingauth status <kyma>'.
+const kubectlPluginName = "kubectl-eventingauth"
+
+// The verbs of the kubectl plugin.
+const (
+	pluginVerbStatus   = "status"
+	pluginVerbRotate   = "rotate"
+	pluginVerbDiagnose = "diagnose"
+)
+
+// Defaults of the kubectl plugin.
+const (
+	defaultPluginNamespace = "kcp-system"
+	defaultPluginTimeout   = 30 * time.Second
+	defaultPluginEvents    = 10
+)
+
+// eventInvolvedObjectNameField is the field of the events that they are selected by for a Kyma CR.
+const eventInvolvedObjectNameField = "involvedObject.name"
+
+// pluginFlags are the flags of the verbs of the kubectl plugin.
+type pluginFlags struct {
+	namespace  string
+	kubeconfig string
+	timeout    time.Duration
+	all        bool
+	events     int
+	metricsURL string
+}
+
+// runKubectlPlugin runs the kubectl plugin with the arguments after the name of the plugin, which start with the verb. It returns the exit
+// code of the plugin.
+func runKubectlPlugin(args []string, stdout, stderr io.Writer) int {
+	usage := fmt.Sprintf("usage: kubectl eventingauth %s|%s|%s <kyma> [flags]", pluginVerbStatus, pluginVerbRotate, pluginVerbDiagnose)
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, usage)
+		return exitCodeUsage
+	}
+	verb := args[0]
+	if verb != pluginVerbStatus && verb != pluginVerbRotate && verb != pluginVerbDiagnose {
+		fmt.Fprintf(stderr, "unknown verb %q\n%s\n", verb, usage)
+		return exitCodeUsage
+	}
+
+	var flags pluginFlags
+	fs := newPluginFlagSet(verb, &flags, stderr)
+	kyma, err := parsePluginArgs(fs, args[1:])
+	if err != nil {
+		return exitCodeUsage
+	}
+	if verb == pluginVerbRotate && (kyma == "") == !flags.all {
+		fmt.Fprintln(stderr, "exactly one of <kyma> and --all must be set")
+		return exitCodeUsage
+	}
+	if verb != pluginVerbRotate && kyma == "" {
+		fmt.Fprintln(stderr, usage)
+		return exitCodeUsage
+	}
+	if flags.events < 0 {
+		fmt.Fprintln(stderr, "--events must not be negative")
+		return exitCodeUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flags.timeout)
+	defer cancel()
+	c, err := newCommandClient(flags.kubeconfig)
+	if err != nil {
+		fmt.Fprintf(stdout, "FAIL: %s\n", redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	switch verb {
+	case pluginVerbRotate:
+		err = requestRotation(ctx, c, kyma, time.Now(), stdout)
+	case pluginVerbStatus:
+		err = printEventingAuthStatus(ctx, c, kpkgclient.ObjectKey{Namespace: flags.namespace, Name: kyma}, stdout)
+	case pluginVerbDiagnose:
+		err = diagnose(ctx, c, kpkgclient.ObjectKey{Namespace: flags.namespace, Name: kyma}, flags, time.Now(), stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(stdout, "FAIL: %s\n", redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	return 0
+}
+
+// newPluginFlagSet returns the flag set of the verb of the kubectl plugin, which parses the flags into the given flags.
+func newPluginFlagSet(verb string, flags *pluginFlags, output io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(kubectlPluginName+" "+verb, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.StringVar(&flags.namespace, "namespace", defaultPluginNamespace, "The namespace of the Kyma and EventingAuth CRs.")
+	fs.StringVar(&flags.kubeconfig, "kubeconfig", "",
+		"The path to the kubeconfig of the cluster of the EventingAuth CRs. Defaults to the kubeconfig of the environment.")
+	fs.DurationVar(&flags.timeout, "timeout", defaultPluginTimeout, "The timeout of the requests of the plugin.")
+	if verb == pluginVerbRotate {
+		fs.BoolVar(&flags.all, "all", false, "Rotate the credentials of all managed applications.")
+	}
+	if verb == pluginVerbDiagnose {
+		fs.IntVar(&flags.events, "events", defaultPluginEvents, "The number of the most recent events that are printed.")
+		fs.StringVar(&flags.metricsURL, "metrics-url", "",
+			"The URL of the metrics endpoint of the manager, e.g. 'http://localhost:8080/metrics' after a port-forward to the manager. "+
+				"The metrics aren't printed if it is empty.")
+	}
+	return fs
+}
+
+// parsePluginArgs parses the flags of the arguments, which can be given before and after the name of the Kyma CR like with kubectl, and
+// returns the name of the Kyma CR, or an empty name if it isn't given.
+func parsePluginArgs(fs *flag.FlagSet, args []string) (string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return "", err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	switch len(positional) {
+	case 0:
+		return "", nil
+	case 1:
+		return positional[0], nil
+	default:
+		fmt.Fprintf(fs.Output(), "only one Kyma CR can be given, got %s\n", strings.Join(positional, ", "))
+		return "", errors.New("too many arguments")
+	}
+}
+
+// printEventingAuthStatus prints the status of the EventingAuth CR of the Kyma CR with the key.
+func printEventingAuthStatus(ctx context.Context, c kpkgclient.Client, key kpkgclient.ObjectKey, out io.Writer) error {
+	var eventingAuth eamapiv1alpha1.EventingAuth
+	if err := c.Get(ctx, key, &eventingAuth); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return errors.Errorf("no EventingAuth CR of Kyma CR %s found in namespace %s", key.Name, key.Namespace)
+		}
+		return errors.Wrap(err, "failed to get the EventingAuth CR")
+	}
+	fmt.Fprintf(out, "EventingAuth %s/%s\n", eventingAuth.Namespace, eventingAuth.Name)
+	state := string(eventingAuth.Status.State)
+	if !eventingAuth.DeletionTimestamp.IsZero() {
+		state += " (deleting)"
+	}
+	fmt.Fprintf(out, "  State:              %s\n", state)
+	provider := eventingAuth.Spec.Provider
+	if provider == "" {
+		provider = "<default of the manager>"
+	}
+	fmt.Fprintf(out, "  Provider:           %s\n", provider)
+	if application := eventingAuth.Status.Application; application != nil {
+		if application.Tenant != "" {
+			fmt.Fprintf(out, "  IAS tenant:         %s\n", application.Tenant)
+		}
+		fmt.Fprintf(out, "  Application:        %s (ID %s)\n", application.Name, application.UUID)
+		if application.CredentialsIssuedTime != nil {
+			fmt.Fprintf(out, "  Credentials issued: %s\n", application.CredentialsIssuedTime.UTC().Format(time.RFC3339))
+		}
+	}
+	if secret := eventingAuth.Status.AuthSecret; secret != nil {
+		fmt.Fprintf(out, "  Secret:             %s on cluster %s\n", secret.NamespacedName, secret.ClusterID)
+	}
+	if provisioned := eventingAuth.Status.ProvisionedTime; provisioned != nil {
+		fmt.Fprintf(out, "  Provisioned:        %s\n", provisioned.UTC().Format(time.RFC3339))
+	}
+	if requested, ok := eventingAuth.Annotations[eamcontrollers.RotateCredentialsAnnotation]; ok {
+		fmt.Fprintf(out, "  Rotation requested: %s\n", requested)
+	}
+	if len(eventingAuth.Status.Conditions) > 0 {
+		fmt.Fprintln(out, "  Conditions:")
+		for _, condition := range eventingAuth.Status.Conditions {
+			fmt.Fprintf(out, "    %s=%s (%s, %s): %s\n", condition.Type, condition.Status, condition.Reason,
+				condition.LastTransitionTime.UTC().Format(time.RFC3339), redact.String(condition.Message))
+		}
+	}
+	return nil
+}
+
+// diagnose prints the status of the EventingAuth CR of the Kyma CR with the key, the most recent events of the Kyma and EventingAuth CRs,
+// and the metrics of the SKR cluster of the Kyma CR if the URL of the metrics endpoint is set.
+func diagnose(ctx context.Context, c kpkgclient.Client, key kpkgclient.ObjectKey, flags pluginFlags, now time.Time, out io.Writer) error {
+	// The events and metrics are still printed if the EventingAuth CR doesn't exist, e.g. because the Kyma controller failed to create it.
+	statusErr := printEventingAuthStatus(ctx, c, key, out)
+
+	var events kcorev1.EventList
+	if err := c.List(ctx, &events, kpkgclient.InNamespace(key.Namespace), kpkgclient.MatchingFields{eventInvolvedObjectNameField: key.Name}); err != nil {
+		return errors.Wrap(err, "failed to list the events")
+	}
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return eventTime(&events.Items[i]).Before(eventTime(&events.Items[j]))
+	})
+	items := events.Items
+	if len(items) > flags.events {
+		items = items[len(items)-flags.events:]
+	}
+	fmt.Fprintf(out, "Events (%d of %d):\n", len(items), len(events.Items))
+	for i := range items {
+		event := &items[i]
+		fmt.Fprintf(out, "  %s ago  %s  %s  %s: %s\n", now.Sub(eventTime(event)).Round(time.Second), event.Type, event.Reason,
+			event.InvolvedObject.Kind, redact.String(event.Message))
+	}
+
+	if flags.metricsURL != "" {
+		lines, err := scrapeClusterMetrics(ctx, flags.metricsURL, key.Name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Metrics (%d):\n", len(lines))
+		for _, line := range lines {
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+	}
+	return statusErr
+}
+
+// eventTime returns the time when the event was last observed.
+func eventTime(event *kcorev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// scrapeClusterMetrics returns the samples of the metrics endpoint at the URL that are labeled with the SKR cluster of the Kyma CR, e.g.
+// of the syncs of the application secret.
+func scrapeClusterMetrics(ctx context.Context, url, kyma string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid URL of the metrics endpoint")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to scrape the metrics of the manager")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("failed to scrape the metrics of the manager: %s", resp.Status)
+	}
+	label := fmt.Sprintf("cluster=%q", kyma)
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "#") && strings.Contains(line, label) {
+			lines = append(lines, line)
+		}
+	}
+	return lines, errors.Wrap(scanner.Err(), "failed to read the metrics of the manager")
+}
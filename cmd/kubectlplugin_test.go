@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_runKubectlPlugin_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "should fail without verb"},
+		{name: "should fail with an unknown verb", args: []string{"describe", "runtime-a"}},
+		{name: "should fail without Kyma CR", args: []string{"status"}},
+		{name: "should fail with several Kyma CRs", args: []string{"status", "runtime-a", "runtime-b"}},
+		{name: "should fail to rotate a Kyma CR and all", args: []string{"rotate", "runtime-a", "--all"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			require.Equal(t, exitCodeUsage, runKubectlPlugin(tt.args, &stdout, &stderr))
+			require.Empty(t, stdout.String())
+		})
+	}
+}
+
+func Test_parsePluginArgs(t *testing.T) {
+	// given
+	var flags pluginFlags
+	fs := newPluginFlagSet(pluginVerbStatus, &flags, io.Discard)
+
+	// when
+	kyma, err := parsePluginArgs(fs, []string{"--namespace", "kyma-system", "runtime-a", "--timeout", "5s"})
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "runtime-a", kyma)
+	require.Equal(t, "kyma-system", flags.namespace)
+	require.Equal(t, 5*time.Second, flags.timeout)
+}
+
+func Test_diagnose(t *testing.T) {
+	// given
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	issued := kmetav1.NewTime(now.Add(-time.Hour))
+	eventingAuth := &eamapiv1alpha1.EventingAuth{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-a"},
+		Spec:       eamapiv1alpha1.EventingAuthSpec{Provider: "ias"},
+		Status: eamapiv1alpha1.EventingAuthStatus{
+			State:       eamapiv1alpha1.StateNotReady,
+			Application: &eamapiv1alpha1.ClusterApplication{Name: "runtime-a", UUID: "app-id", CredentialsIssuedTime: &issued},
+			AuthSecret:  &eamapiv1alpha1.AuthSecret{NamespacedName: "kyma-system/eventing-webhook-auth", ClusterID: "runtime-a"},
+			Conditions: []kmetav1.Condition{{Type: "SecretReady", Status: kmetav1.ConditionFalse, Reason: "SecretSyncFailed",
+				LastTransitionTime: kmetav1.NewTime(now.Add(-time.Minute)), Message: "failed to write the secret"}},
+		},
+	}
+	newEvent := func(name, involved, reason string, age time.Duration) *kcorev1.Event {
+		return &kcorev1.Event{
+			ObjectMeta:     kmetav1.ObjectMeta{Namespace: "kcp-system", Name: name},
+			InvolvedObject: kcorev1.ObjectReference{Kind: "EventingAuth", Name: involved},
+			Type:           kcorev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        reason + " of " + involved,
+			LastTimestamp:  kmetav1.NewTime(now.Add(-age)),
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(initScheme()).
+		WithObjects(eventingAuth,
+			newEvent("event-1", "runtime-a", "SecretSyncFailed", time.Minute),
+			newEvent("event-2", "runtime-a", "ApplicationCreated", time.Hour),
+			newEvent("event-3", "runtime-a", "ReconcileFailed", 2*time.Minute),
+			newEvent("event-4", "runtime-b", "SecretSyncFailed", time.Minute)).
+		WithIndex(&kcorev1.Event{}, eventInvolvedObjectNameField, func(obj kpkgclient.Object) []string {
+			return []string{obj.(*kcorev1.Event).InvolvedObject.Name}
+		}).
+		Build()
+	metricsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintln(w, "# HELP eventing_auth_manager_skr_secret_sync_failures_total Number of failed syncs.")
+		fmt.Fprintln(w, `eventing_auth_manager_skr_secret_sync_failures_total{cluster="runtime-a"} 3`)
+		fmt.Fprintln(w, `eventing_auth_manager_skr_secret_sync_failures_total{cluster="runtime-b"} 1`)
+	}))
+	defer metricsServer.Close()
+	var out bytes.Buffer
+
+	// when
+	err := diagnose(context.Background(), c, kpkgclient.ObjectKey{Namespace: "kcp-system", Name: "runtime-a"},
+		pluginFlags{events: 2, metricsURL: metricsServer.URL}, now, &out)
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "EventingAuth kcp-system/runtime-a\n"+
+		"  State:              NotReady\n"+
+		"  Provider:           ias\n"+
+		"  Application:        runtime-a (ID app-id)\n"+
+		"  Credentials issued: 2024-03-01T11:00:00Z\n"+
+		"  Secret:             kyma-system/eventing-webhook-auth on cluster runtime-a\n"+
+		"  Conditions:\n"+
+		"    SecretReady=False (SecretSyncFailed, 2024-03-01T11:59:00Z): failed to write the secret\n"+
+		"Events (2 of 3):\n"+
+		"  2m0s ago  Warning  ReconcileFailed  EventingAuth: ReconcileFailed of runtime-a\n"+
+		"  1m0s ago  Warning  SecretSyncFailed  EventingAuth: SecretSyncFailed of runtime-a\n"+
+		"Metrics (1):\n"+
+		"  eventing_auth_manager_skr_secret_sync_failures_total{cluster=\"runtime-a\"} 3\n", out.String())
+}
+
+func Test_diagnose_MissingEventingAuth(t *testing.T) {
+	// given
+	c := fake.NewClientBuilder().WithScheme(initScheme()).
+		WithIndex(&kcorev1.Event{}, eventInvolvedObjectNameField, func(obj kpkgclient.Object) []string {
+			return []string{obj.(*kcorev1.Event).InvolvedObject.Name}
+		}).
+		Build()
+	var out bytes.Buffer
+
+	// when
+	err := diagnose(context.Background(), c, kpkgclient.ObjectKey{Namespace: "kcp-system", Name: "runtime-a"}, pluginFlags{events: 10},
+		time.Now(), &out)
+
+	// then
+	require.EqualError(t, err, "no EventingAuth CR of Kyma CR runtime-a found in namespace kcp-system")
+	require.Equal(t, "Events (0 of 0):\n", out.String())
+}
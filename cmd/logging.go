@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	kzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+const (
+	logFormatJSON    = "json"
+	logFormatConsole = "console"
+)
+
+const (
+	// logSamplingInitial and logSamplingThereafter define the sampling of the log lines: of the log lines with the same level and message
+	// within a second, the first ones are logged, and afterward only every nth one.
+	logSamplingInitial    = 100
+	logSamplingThereafter = 100
+)
+
+// logFlags are the flags of the logger of the manager.
+type logFlags struct {
+	format          string
+	level           string
+	stacktraceLevel string
+	sampling        bool
+}
+
+// newLogger returns the logger of the manager that writes to the writer, and the level of the logger. The levels are the zap levels 'debug',
+// 'info', and 'error', or a verbosity, e.g. '2' for the log lines of V(2) and lower.
+func newLogger(flags logFlags, w io.Writer) (logr.Logger, zap.AtomicLevel, error) {
+	level, err := parseLogLevel(flags.level)
+	if err != nil {
+		return logr.Logger{}, zap.AtomicLevel{}, errors.Wrap(err, "invalid --log-level")
+	}
+	stacktraceLevel, err := zapcore.ParseLevel(flags.stacktraceLevel)
+	if err != nil {
+		return logr.Logger{}, zap.AtomicLevel{}, errors.Wrap(err, "invalid --log-stacktrace-level")
+	}
+
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.RFC3339TimeEncoder
+	var encoder zapcore.Encoder
+	switch flags.format {
+	case logFormatJSON:
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case logFormatConsole:
+		encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return logr.Logger{}, zap.AtomicLevel{}, errors.Errorf("invalid --log-format %q, must be %s or %s", flags.format, logFormatJSON,
+			logFormatConsole)
+	}
+
+	sink := zapcore.AddSync(w)
+	atomicLevel := zap.NewAtomicLevelAt(level)
+	// The Kubernetes objects in the key value pairs are logged by their namespace and name, like by the default logger of controller-runtime.
+	core := zapcore.NewCore(&redactingEncoder{Encoder: &kzap.KubeAwareEncoder{Encoder: encoder}}, sink, atomicLevel)
+	if flags.sampling {
+		core = newSamplingCore(core)
+	}
+	logger := zap.New(core, zap.AddStacktrace(stacktraceLevel), zap.ErrorOutput(sink))
+	return zapr.NewLogger(logger), atomicLevel, nil
+}
+
+// redactingEncoder removes the credentials from the encoded log lines, so that credentials in messages, errors, and key value pairs don't
+// leak into the logs.
+type redactingEncoder struct {
+	zapcore.Encoder
+}
+
+func (e *redactingEncoder) Clone() zapcore.Encoder {
+	return &redactingEncoder{Encoder: e.Encoder.Clone()}
+}
+
+func (e *redactingEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line, err := e.Encoder.EncodeEntry(entry, fields)
+	if err != nil {
+		return nil, err
+	}
+	redacted := redact.String(line.String())
+	line.Reset()
+	line.AppendString(redacted)
+	return line, nil
+}
+
+// parseLogLevel parses a zap level or a verbosity. The verbosity n of logr corresponds to the zap level -n.
+func parseLogLevel(level string) (zapcore.Level, error) {
+	if verbosity, err := strconv.Atoi(level); err == nil {
+		if verbosity < 0 {
+			return 0, errors.Errorf("verbosity %d must not be negative", verbosity)
+		}
+		return zapcore.Level(-verbosity), nil
+	}
+	return zapcore.ParseLevel(strings.ToLower(level))
+}
+
+// logLevelConfigMapKey is the key of the log level in the log level ConfigMap.
+const logLevelConfigMapKey = "log-level"
+
+// logLevelWatcher sets the level of the logger to the level of a ConfigMap, so that the level can be changed without restarting the
+// manager and losing its in-memory state. If the ConfigMap or its key is removed, the level of the flags is restored.
+type logLevelWatcher struct {
+	clientset    kubernetes.Interface
+	namespace    string
+	name         string
+	level        zap.AtomicLevel
+	defaultLevel zapcore.Level
+	logger       logr.Logger
+}
+
+func newLogLevelWatcher(clientset kubernetes.Interface, namespace, name string, level zap.AtomicLevel, logger logr.Logger) *logLevelWatcher {
+	return &logLevelWatcher{
+		clientset:    clientset,
+		namespace:    namespace,
+		name:         name,
+		level:        level,
+		defaultLevel: level.Level(),
+		logger:       logger.WithName("log-level"),
+	}
+}
+
+// NeedLeaderElection returns false, since the level of each replica of the manager is changed.
+func (w *logLevelWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start watches the ConfigMap until the context is done. Since only a single ConfigMap is watched, the manager cache isn't used, which
+// would cache all ConfigMaps of the cluster.
+func (w *logLevelWatcher) Start(ctx context.Context) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(w.clientset, 0, informers.WithNamespace(w.namespace),
+		informers.WithTweakListOptions(func(opts *kmetav1.ListOptions) {
+			opts.FieldSelector = fields.OneTermEqualSelector("metadata.name", w.name).String()
+		}))
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { w.update(obj) },
+		UpdateFunc: func(_, obj interface{}) { w.update(obj) },
+		DeleteFunc: func(interface{}) { w.setLevel(w.defaultLevel) },
+	}); err != nil {
+		return errors.Wrap(err, "failed to watch log level ConfigMap")
+	}
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+	return nil
+}
+
+func (w *logLevelWatcher) update(obj interface{}) {
+	configMap, ok := obj.(*kcorev1.ConfigMap)
+	if !ok {
+		return
+	}
+	value, ok := configMap.Data[logLevelConfigMapKey]
+	if !ok {
+		w.setLevel(w.defaultLevel)
+		return
+	}
+	level, err := parseLogLevel(strings.TrimSpace(value))
+	if err != nil {
+		w.logger.Error(err, "Ignoring invalid log level of ConfigMap", "namespace", w.namespace, "name", w.name, "level", value)
+		return
+	}
+	w.setLevel(level)
+}
+
+func (w *logLevelWatcher) setLevel(level zapcore.Level) {
+	if w.level.Level() == level {
+		return
+	}
+	previous := w.level.Level()
+	// The change is logged with the more verbose of the previous and the new level, so that raising the level is logged as well.
+	if level < previous {
+		w.level.SetLevel(level)
+	}
+	w.logger.Info("Changing log level", "level", level.String(), "previousLevel", previous.String())
+	w.level.SetLevel(level)
+}
+
+// samplingCore samples the log lines of the levels debug and higher. The log lines of higher verbosities aren't sampled, because the
+// sampler of zap only supports the zap levels. Since the level can be lowered at runtime, they are passed to the unsampled core instead.
+type samplingCore struct {
+	sampled   zapcore.Core
+	unsampled zapcore.Core
+}
+
+func newSamplingCore(core zapcore.Core) zapcore.Core {
+	return &samplingCore{
+		sampled:   zapcore.NewSamplerWithOptions(core, time.Second, logSamplingInitial, logSamplingThereafter),
+		unsampled: core,
+	}
+}
+
+func (c *samplingCore) Enabled(level zapcore.Level) bool {
+	return c.unsampled.Enabled(level)
+}
+
+func (c *samplingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &samplingCore{sampled: c.sampled.With(fields), unsampled: c.unsampled.With(fields)}
+}
+
+func (c *samplingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < zapcore.DebugLevel {
+		return c.unsampled.Check(entry, checked)
+	}
+	return c.sampled.Check(entry, checked)
+}
+
+func (c *samplingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.unsampled.Write(entry, fields)
+}
+
+func (c *samplingCore) Sync() error {
+	return c.unsampled.Sync()
+}
@@ -17,18 +17,50 @@ limitations under the License.
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/go-logr/logr"
 	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/config/crd"
 	eamcontrollers "github.com/kyma-project/eventing-auth-manager/controllers"
+	"github.com/kyma-project/eventing-auth-manager/internal/admin"
+	"github.com/kyma-project/eventing-auth-manager/internal/audit"
+	"github.com/kyma-project/eventing-auth-manager/internal/crds"
+	"github.com/kyma-project/eventing-auth-manager/internal/dryrun"
+	"github.com/kyma-project/eventing-auth-manager/internal/faultinjection"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/otlpmetrics"
+	"github.com/kyma-project/eventing-auth-manager/internal/profiling"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/sink"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
+	"github.com/kyma-project/eventing-auth-manager/internal/version"
 	klmapiv1beta1 "github.com/kyma-project/lifecycle-manager/api/v1beta1"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	kutilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
 	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection"
 	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
-	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
@@ -37,45 +69,637 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
+const (
+	skrKubeconfigSourceSecret   = "secret"
+	skrKubeconfigSourceGardener = "gardener"
+	skrKubeconfigSourceLocal    = "local"
+)
+
+// serviceName is the name of the manager in the exported spans and metrics.
+const serviceName = "eventing-auth-manager"
+
+// tracingExportTimeout is the timeout of the export of the spans to the OTLP receiver.
+const tracingExportTimeout = 10 * time.Second
+
+// shutdownReturnTimeout is the duration that the manager waits for the reconciliations to return after the shutdown grace period elapsed.
+const shutdownReturnTimeout = 5 * time.Second
+
+// crdSetupTimeout is the timeout of the installation and verification of the CRDs at startup.
+const crdSetupTimeout = time.Minute
+
+// defaultAuditActor is the user of the service account of the manager, which performs the identity operations in the audit records.
+const defaultAuditActor = "system:serviceaccount:kcp-system:eventing-auth-manager-controller-manager"
+
 func main() {
-	const webhookPort = 9443
+	if filepath.Base(os.Args[0]) == kubectlPluginName {
+		os.Exit(runKubectlPlugin(os.Args[1:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case validateCredentialsCommand:
+			os.Exit(runValidateCredentials(os.Args[2:], os.Stdout, os.Stderr))
+		case cleanupOrphansCommand:
+			os.Exit(runCleanupOrphans(os.Args[2:], os.Stdout, os.Stderr))
+		case exportCommand:
+			os.Exit(runExport(os.Args[2:], os.Stdout, os.Stderr))
+		case rotateCommand:
+			os.Exit(runRotate(os.Args[2:], os.Stdout, os.Stderr))
+		case simulateCommand:
+			os.Exit(runSimulate(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
 	setupLog := kcontrollerruntime.Log.WithName("setup")
-	var metricsAddr string
+	var bindAddressFlags bindAddressFlags
 	var enableLeaderElection bool
-	var probeAddr string
-	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
-	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	var leaderElectionFlags leaderElectionFlags
+	var enableKymaController bool
+	var enableIASApplicationController bool
+	var installCRDs bool
+	var runOnce bool
+	var dryRun bool
+	var runOnceScope string
+	var adminConfig admin.Config
+	var inventoryConfig admin.SnapshotConfig
+	var enableKymaFinalizer bool
+	var sharedApplicationLabel string
+	var enableKymaEvents bool
+	var skrKubeconfigSecretNamespace string
+	var secretCacheFlags secretCacheFlags
+	var skrKubeconfigSecretNameTemplate string
+	var skrClientCacheTTL time.Duration
+	var skrKubeconfigSource string
+	var kubeconfigSecretRefNamespaces string
+	var skrAllowKubeconfigExecPlugins bool
+	var skrCreateSecretNamespace bool
+	var skrSecretFlags skrSecretFlags
+	var skrSecretType string
+	var skrSecretBTPFormat bool
+	var skrClientQPS float64
+	var skrClientBurst int
+	var skrRequestTimeout time.Duration
+	var enableSkrSecretWatch bool
+	var enableSkrSubscriptionWatch bool
+	var skrSecretWatchMaxClusters int
+	var enableSkrSecret bool
+	var sinkFlags sinkFlags
+	var defaultProvider string
+	var entraConfig provider.EntraConfig
+	var keycloakConfig provider.KeycloakConfig
+	var auth0Config provider.Auth0Config
+	var auth0Scopes string
+	var xsuaaConfig provider.XSUAAConfig
+	var iasServiceManagerConfig provider.IASServiceManagerConfig
+	var oktaConfig provider.OktaConfig
+	var enableMemoryProvider bool
+	var memoryConfig provider.MemoryConfig
+	var iasFaults faultinjection.Config
+	var skrFaults faultinjection.Config
+	var gardenerKubeconfigPath string
+	var gardenerProjectNamespace string
+	var gardenerShootNameTemplate string
+	var gardenerAdminKubeconfigTTL time.Duration
+	var stuckReconcileThreshold time.Duration
+	var maxConcurrentReconciles int
+	var shutdownGracePeriod time.Duration
+	var iasReadinessInterval time.Duration
+	var iasDuplicatePolicy string
+	var iasMaintenanceThreshold time.Duration
+	var iasDiscoveryTTL time.Duration
+	var iasRequestTimeout time.Duration
+	var iasTenantFailoverDelay time.Duration
+	var applicationDeletionMaxAttempts int
+	var orphanedApplicationPolicy string
+	var credentialsRotationReminder time.Duration
+	var credentialPropagation string
+	var printVersion bool
+	var configFile string
+	var logFlags logFlags
+	var logLevelConfigMapNamespace string
+	var logLevelConfigMap string
+	var landscape string
+	var tracingConfig tracing.Config
+	var otlpMetricsConfig otlpmetrics.Config
+	var otlpMetricsHeaders string
+	var profilingConfig profiling.Config
+	var profilingHeaders string
+	var auditFlags auditFlags
+	var logReconcileDecisions bool
+	flag.StringVar(&landscape, "landscape", "",
+		"The name of the landscape of the manager, e.g. 'eu-prod', that the fleet health metrics are labeled with.")
+	flag.StringVar(&tracingConfig.Endpoint, "otlp-endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		"The base URL of the OTLP/HTTP receiver that the spans of the reconciliations and of the requests to the IAS tenants and the SKR "+
+			"clusters are exported to, e.g. 'http://otel-collector.kyma-system:4318'. Defaults to the environment variable "+
+			"OTEL_EXPORTER_OTLP_ENDPOINT. Tracing is disabled if it is empty.")
+	flag.Float64Var(&tracingConfig.SampleRatio, "tracing-sample-ratio", tracing.DefaultSampleRatio,
+		"The ratio of the traces that are sampled, between 0 and 1. Traces that are continued from a sampled parent are always sampled. "+
+			"Only used with '--otlp-endpoint'.")
+	flag.StringVar(&otlpMetricsConfig.Endpoint, "otlp-metrics-endpoint", "",
+		"The base URL of the OTLP/HTTP receiver that the metrics are pushed to in addition to the metrics endpoint, for landscapes in which "+
+			"the manager isn't scraped, e.g. 'http://otel-collector.kyma-system:4318'. The push is disabled if it is empty.")
+	flag.StringVar(&otlpMetricsHeaders, "otlp-metrics-headers", os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"),
+		"The headers that are sent with each push of the metrics as comma-separated list of key=value pairs with URL-encoded values, "+
+			"e.g. 'Authorization=Bearer%20token'. Defaults to the environment variable OTEL_EXPORTER_OTLP_HEADERS. "+
+			"Only used with '--otlp-metrics-endpoint'.")
+	flag.DurationVar(&otlpMetricsConfig.Interval, "otlp-metrics-interval", otlpmetrics.DefaultInterval,
+		"The interval in which the metrics are pushed. Only used with '--otlp-metrics-endpoint'.")
+	flag.StringVar(&bindAddressFlags.pprof, "pprof-bind-address", "",
+		"The address the pprof endpoint binds to, e.g. '127.0.0.1:6060' or '[::1]:6060'. The pprof endpoint is disabled if it is empty.")
+	flag.StringVar(&profilingConfig.Endpoint, "profiling-endpoint", "",
+		"The base URL of the Pyroscope-compatible receiver that the CPU, heap, and goroutine profiles are pushed to continuously, "+
+			"e.g. 'http://pyroscope.kyma-system:4040'. Continuous profiling is disabled if it is empty.")
+	flag.StringVar(&profilingHeaders, "profiling-headers", "",
+		"The headers that are sent with each push of the profiles as comma-separated list of key=value pairs with URL-encoded values, "+
+			"e.g. 'Authorization=Bearer%20token'. Only used with '--profiling-endpoint'.")
+	flag.DurationVar(&profilingConfig.Interval, "profiling-interval", profiling.DefaultInterval,
+		"The interval in which the profiles are pushed. Only used with '--profiling-endpoint'.")
+	flag.StringVar(&logFlags.format, "log-format", logFormatJSON, "The encoding of the log lines, either 'json' or 'console'.")
+	flag.StringVar(&logFlags.level, "log-level", "info",
+		"The minimum level of the logged lines, either 'debug', 'info', or 'error', or a verbosity like '2' to log the lines up to V(2).")
+	flag.StringVar(&logFlags.stacktraceLevel, "log-stacktrace-level", "error",
+		"The minimum level of the log lines that contain a stacktrace, either 'info', 'error', or 'panic'.")
+	flag.BoolVar(&logFlags.sampling, "log-sampling", true,
+		"Samples repetitive log lines: of the log lines with the same level and message within a second, the first 100 are logged and "+
+			"afterward every 100th.")
+	flag.BoolVar(&logReconcileDecisions, "log-reconcile-decisions", false,
+		"Logs a decision record per reconciliation of an EventingAuth CR with the observed inputs, the actions taken, and the requeue decision, "+
+			"so that it can be reconstructed later why an application was deleted or recreated.")
+	flag.StringVar(&logLevelConfigMap, "log-level-configmap", "",
+		"The name of a ConfigMap whose key 'log-level' changes the log level at runtime, with the values of '--log-level'. "+
+			"If the ConfigMap or the key is removed, the level of '--log-level' is restored. The watch is disabled if it is empty.")
+	flag.StringVar(&logLevelConfigMapNamespace, "log-level-configmap-namespace", skr.KcpNamespace,
+		"The namespace of the ConfigMap of '--log-level-configmap'.")
+	flag.StringVar(&configFile, configFileFlag, "",
+		"The path to the config file of the manager, which sets the flags that aren't set on the command line.")
+	flag.BoolVar(&printVersion, "version", false, "Print the version, the git SHA, and the build date of the manager and exit.")
+	flag.StringVar(&bindAddressFlags.metrics, "metrics-bind-address", ":8080",
+		"The address the metric endpoint binds to. IPv6 addresses are enclosed in brackets, e.g. '[::1]:8080'. "+
+			"The metric endpoint is disabled if it is '0'.")
+	flag.StringVar(&bindAddressFlags.healthProbe, "health-probe-bind-address", ":8081",
+		"The address the probe endpoint binds to. IPv6 addresses are enclosed in brackets, e.g. '[::]:8081'.")
+	flag.StringVar(&bindAddressFlags.webhook, "webhook-bind-address", ":9443",
+		"The address the webhook server binds to. IPv6 addresses are enclosed in brackets, e.g. '[::]:9443'.")
+	flag.StringVar(&bindAddressFlags.admin, "admin-bind-address", "",
+		"The address the HTTPS server of the read-only admin API binds to, e.g. ':8444'. The admin API is disabled if it is empty.")
+	flag.StringVar(&inventoryConfig.Name, "inventory-configmap", "",
+		"The name of the ConfigMap on KCP that the inventory of the managed applications is written to in the interval of "+
+			"'--inventory-interval', as a recovery aid if the statuses of the EventingAuth CRs are lost. The inventory is disabled if it is empty.")
+	flag.StringVar(&inventoryConfig.Namespace, "inventory-configmap-namespace", skr.KcpNamespace,
+		"The namespace of the ConfigMap of '--inventory-configmap'.")
+	flag.DurationVar(&inventoryConfig.Interval, "inventory-interval", admin.DefaultSnapshotInterval,
+		"The interval in which the ConfigMap of '--inventory-configmap' is refreshed.")
+	flag.StringVar(&adminConfig.CertDir, "admin-cert-dir", "",
+		"The directory of the 'tls.crt' and 'tls.key' files of the certificate of the admin API. "+
+			"A self-signed certificate is used if it is empty. Only used with '--admin-bind-address'.")
+	flag.DurationVar(&shutdownGracePeriod, "shutdown-grace-period", eamcontrollers.DefaultShutdownGracePeriod,
+		"The duration that the in-flight reconciliations have to finish when the manager stops. No new applications are created "+
+			"during the grace period, and the leader lease is released afterward.")
+	flag.DurationVar(&iasReadinessInterval, "ias-readiness-interval", eamcontrollers.DefaultIASReadinessInterval,
+		"The interval in which the IAS tenants are probed for the 'ias' check of the readiness probe. The check is disabled if it is 0.")
+	flag.StringVar(&iasDuplicatePolicy, "ias-duplicate-application-policy", string(eamias.DuplicatePolicyFail),
+		"The handling of multiple IAS applications with the same name: 'fail' fails the reconciliation until the duplicates are removed, "+
+			"'keep-newest' keeps the newest application that is marked as managed and deletes the others, and 'adopt-by-id' keeps the "+
+			"application with the ID in the status of the EventingAuth CR and deletes the others.")
+	flag.DurationVar(&iasMaintenanceThreshold, "ias-maintenance-threshold", eamcontrollers.DefaultIASMaintenanceThreshold,
+		"The duration that an IAS tenant must respond with 503 for until it is in maintenance, during which the EventingAuth CRs of the "+
+			"tenant are reconciled again after 15 minutes instead of failing. The maintenance isn't detected if it is 0.")
+	flag.DurationVar(&iasDiscoveryTTL, "ias-discovery-ttl", eamias.DefaultDiscoveryTTL,
+		"The duration after which the token URL and the JWKS URI of the OIDC configuration of an IAS tenant are discovered again. "+
+			"They are only discovered again after the tenant rejected a request with 401 or 403 if it is 0.")
+	flag.DurationVar(&iasRequestTimeout, "ias-request-timeout", eamias.DefaultRequestTimeout,
+		"The deadline of every request to an IAS tenant. The requests aren't limited if it is 0.")
+	flag.DurationVar(&iasTenantFailoverDelay, "ias-tenant-failover-delay", eamcontrollers.DefaultIASTenantFailoverDelay,
+		"The duration that the creation of an application in an IAS tenant must fail for, before the application is created in the "+
+			"spec.fallbackTenant of the IASTenant CR.")
+	flag.IntVar(&applicationDeletionMaxAttempts, "application-deletion-max-attempts", eamcontrollers.DefaultApplicationDeletionMaxAttempts,
+		"The number of failed deletions of the application of an EventingAuth CR that is being deleted, after which the application is "+
+			"orphaned and the OrphanedInIAS condition is set. The deletion is retried until it succeeds if it is 0.")
+	flag.StringVar(&orphanedApplicationPolicy, "orphaned-application-policy", string(eamcontrollers.OrphanedApplicationPolicyKeepFinalizer),
+		"The handling of an orphaned application: 'keep-finalizer' keeps the finalizer of the EventingAuth CR and retries the deletion "+
+			"every hour, and 'remove-finalizer' removes the finalizer and leaves the application behind in IAS.")
+	flag.DurationVar(&credentialsRotationReminder, "credentials-rotation-reminder", eamcontrollers.DefaultCredentialsRotationReminder,
+		"The age of the credentials that are provided by the spec.credentialsSecretRef of an EventingAuth CR, after which warning events "+
+			"remind to rotate them. No rotation is reminded if it is 0.")
+	flag.StringVar(&credentialPropagation, "credential-propagation", string(eamapiv1alpha1.CredentialPropagationSKR),
+		"The propagation of the credentials of the EventingAuth CRs without spec.credentialPropagation: 'SKR' writes them to the "+
+			"application secret on the SKR cluster, 'KCP' only to a secret next to the EventingAuth CR on KCP, and 'Both' to both secrets.")
+	flag.DurationVar(&stuckReconcileThreshold, "stuck-reconcile-threshold", eamcontrollers.DefaultStuckReconcileThreshold,
+		"The duration after which a reconciliation that didn't return fails the liveness check, so that the manager is restarted. "+
+			"The check is disabled if it is 0.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of EventingAuth CRs that are reconciled concurrently.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-	opts := zap.Options{
-		Development: true,
-	}
-	opts.BindFlags(flag.CommandLine)
+	flag.DurationVar(&leaderElectionFlags.leaseDuration, "leader-elect-lease-duration", defaultLeaseDuration,
+		"The duration that non-leader candidates wait after the last renewal of the lease before they acquire the leadership. "+
+			"Only used with '--leader-elect'.")
+	flag.DurationVar(&leaderElectionFlags.renewDeadline, "leader-elect-renew-deadline", defaultRenewDeadline,
+		"The duration that the leader retries to renew the lease before it gives up the leadership. Only used with '--leader-elect'.")
+	flag.DurationVar(&leaderElectionFlags.retryPeriod, "leader-elect-retry-period", defaultRetryPeriod,
+		"The duration that the candidates wait between tries to acquire or renew the lease. Only used with '--leader-elect'.")
+	flag.BoolVar(&leaderElectionFlags.releaseOnCancel, "leader-elect-release-on-cancel", true,
+		"Release the lease when the manager stops, so that another replica takes over the leadership right away instead of after the "+
+			"lease duration, e.g. on rolling updates. Only used with '--leader-elect'.")
+	flag.BoolVar(&enableKymaController, "enable-kyma-controller", true,
+		"Enable the controller that creates EventingAuth CRs for Kyma CRs. "+
+			"Disabling this allows to run the manager without lifecycle-manager, acting only on externally created EventingAuth CRs.")
+	flag.BoolVar(&enableIASApplicationController, "enable-ias-application-controller", false,
+		"Enable the controller that provisions applications and their credentials secrets for IASApplication CRs, independently of Kyma Eventing.")
+	flag.BoolVar(&installCRDs, "install-crds", false,
+		"Create or update the EventingAuth, IASApplication and IASTenant CRDs that are embedded in the manager at startup, instead of requiring them to be installed.")
+	flag.BoolVar(&runOnce, "run-once", false,
+		"Reconcile the CRs of '--run-once-scope' once and exit, instead of starting the controllers, e.g. in a Job during an upgrade. "+
+			"The manager exits with 1 if a reconciliation failed.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"Log every action that the controllers would take instead of performing it: no application is created, rotated or deleted in the "+
+			"identity providers, no credentials are written to the SKR clusters or the sinks, and the writes to KCP are sent as server-side dry run.")
+	flag.StringVar(&runOnceScope, "run-once-scope", runOnceScopeAll,
+		"The CRs that '--run-once' reconciles: 'all' for all IASTenant, Kyma and EventingAuth CRs, or 'migrations' for the EventingAuth CRs "+
+			"that are annotated for a migration to another IAS tenant or a rotation of their credentials.")
+	flag.BoolVar(&enableKymaFinalizer, "enable-kyma-finalizer", true,
+		"Enable the finalizer on Kyma CRs that delays the deletion of a Kyma CR until its EventingAuth CR is cleaned up.")
+	flag.StringVar(&sharedApplicationLabel, "shared-application-label", "",
+		"The label of the Kyma CRs whose value is the group of Kyma CRs that share a single application, e.g. to stay under the application "+
+			"quota of the IAS tenant. Kyma CRs without the label get their own applications. No application is shared if it is empty.")
+	flag.BoolVar(&enableKymaEvents, "enable-kyma-events", false,
+		"Emits the events of the EventingAuth CRs additionally on the Kyma CRs that own them.")
+	flag.StringVar(&secretCacheFlags.namespaces, "cache-secret-namespaces", "",
+		"Comma-separated list of the namespaces whose secrets are cached and watched, or '*' for all namespaces. Secrets of other namespaces "+
+			"are read from the API server. Defaults to the namespaces of the kubeconfig secrets and of the credentials secrets of the providers.")
+	flag.StringVar(&secretCacheFlags.labelSelector, "cache-secret-label-selector", "",
+		"The label selector of the cached and watched secrets, e.g. 'operator.kyma-project.io/managed-by'. Secrets that aren't found in the "+
+			"cache are read from the API server.")
+	flag.StringVar(&skrKubeconfigSecretNamespace, "skr-kubeconfig-secret-namespace", skr.KcpNamespace,
+		"The namespace of the secrets containing the kubeconfig of the SKR clusters.")
+	flag.StringVar(&skrKubeconfigSecretNameTemplate, "skr-kubeconfig-secret-name-template", skr.DefaultKubeconfigSecretNameTemplate,
+		"The Go template of the name of the secrets containing the kubeconfig of the SKR clusters. The runtime ID can be referenced with '{{ .RuntimeID }}'.")
+	flag.DurationVar(&skrClientCacheTTL, "skr-client-cache-ttl", skr.DefaultClientCacheTTL,
+		"The duration after which an unused client of an SKR cluster is evicted from the cache.")
+	flag.StringVar(&skrKubeconfigSource, "skr-kubeconfig-source", skrKubeconfigSourceSecret,
+		"The source of the kubeconfig of the SKR clusters. Value can be one of ('secret', 'gardener', 'local'). "+
+			"With 'gardener', short-lived admin kubeconfigs are requested for the shoots of the SKR clusters. "+
+			"With 'local', the application secret is written into the cluster the manager runs in.")
+	flag.StringVar(&kubeconfigSecretRefNamespaces, "kubeconfig-secret-ref-namespaces", "",
+		"The comma-separated namespaces that the kubeconfig secrets of spec.kubeconfigSecretRef can be referenced from in addition to the "+
+			"namespace of the EventingAuth CR.")
+	flag.BoolVar(&skrAllowKubeconfigExecPlugins, "skr-allow-kubeconfig-exec-plugins", skr.DefaultClientOptions().AllowExecPlugins,
+		"Allow kubeconfigs of SKR clusters to use exec credential plugins. An exec credential plugin runs a command in the manager container, "+
+			"so this should only be allowed if the kubeconfig secrets are trusted.")
+	flag.BoolVar(&skrCreateSecretNamespace, "skr-create-secret-namespace", false,
+		"Create the namespace of the application secret on the SKR cluster if it doesn't exist.")
+	flag.StringVar(&skrSecretFlags.namespaces, "skr-secret-namespaces", skr.ApplicationSecretNamespace,
+		"Comma-separated list of the namespaces on the SKR cluster to which the application secret is replicated. "+
+			"The first namespace is the primary namespace that is reported in the status of the EventingAuth CR.")
+	flag.StringVar(&skrSecretFlags.namespaceSelector, "skr-secret-namespace-selector", "",
+		"Label selector of additional namespaces on the SKR cluster to which the application secret is replicated.")
+	flag.StringVar(&skrSecretFlags.conflictPolicy, "skr-secret-conflict-policy", string(skr.SecretConflictPolicyAdopt),
+		"The handling of application secrets that already exist on the SKR cluster, but weren't created by the manager. Value can be one of ('adopt', 'fail').")
+	flag.StringVar(&skrSecretType, "skr-secret-type", "",
+		"The type of the application secret on the SKR cluster. Defaults to 'Opaque', or to 'servicebinding.io/<type>' with '--skr-secret-service-binding-type'.")
+	flag.StringVar(&skrSecretFlags.keyMapping, "skr-secret-key-mapping", "",
+		"Comma-separated list of mappings of the data keys of the application secret, e.g. 'client_id=clientid,client_secret=clientsecret'.")
+	flag.StringVar(&skrSecretFlags.serviceBindingType, "skr-secret-service-binding-type", "",
+		"The type of the service binding, e.g. 'oauth2'. If set, the entries 'type' and 'provider' of the Service Binding Specification are added to the application secret.")
+	flag.StringVar(&skrSecretFlags.serviceBindingProvider, "skr-secret-service-binding-provider", "sap",
+		"The provider of the service binding. The entry is omitted if empty. Only used with '--skr-secret-service-binding-type'.")
+	flag.BoolVar(&skrSecretBTPFormat, "skr-secret-btp-format", false,
+		"Write the application secret in the format of the secrets of IAS service bindings created by the SAP BTP service operator. "+
+			"Can't be combined with '--skr-secret-key-mapping' and '--skr-secret-service-binding-type'.")
+	flag.Float64Var(&skrClientQPS, "skr-client-qps", float64(skr.DefaultClientQPS),
+		"The maximum queries per second of the client of each SKR cluster.")
+	flag.IntVar(&skrClientBurst, "skr-client-burst", skr.DefaultClientBurst,
+		"The maximum burst of queries of the client of each SKR cluster.")
+	flag.DurationVar(&skrRequestTimeout, "skr-request-timeout", skr.DefaultRequestTimeout,
+		"The deadline of every request to an SKR cluster except the watches of the application secrets. The requests aren't limited if it is 0.")
+	flag.BoolVar(&enableSkrSecretWatch, "enable-skr-secret-watch", false,
+		"Enable watches of the application secrets on the SKR clusters, so that deleted or modified secrets are restored immediately.")
+	flag.IntVar(&skrSecretWatchMaxClusters, "skr-secret-watch-max-clusters", skr.DefaultSecretWatcherMaxClusters,
+		"The maximum number of SKR clusters whose application secret is watched. Only used with '--enable-skr-secret-watch'.")
+	flag.BoolVar(&enableSkrSubscriptionWatch, "enable-skr-subscription-watch", false,
+		"Enable watches of the Subscriptions on the SKR clusters of the EventingAuth CRs with 'spec.consumerIsolation', so that the "+
+			"applications of the event consumers are provisioned immediately. The number of watched SKR clusters is limited by "+
+			"'--skr-secret-watch-max-clusters'.")
+	flag.StringVar(&defaultProvider, "provider", provider.IASName,
+		"The identity provider that the applications are managed in, unless an EventingAuth CR requests another provider with 'spec.provider'. "+
+			"Value can be one of ('ias', 'ias-service-manager', 'entra', 'keycloak', 'auth0', 'xsuaa', 'okta'), or 'memory' with '--enable-memory-provider'.")
+	flag.StringVar(&entraConfig.CredentialsSecretNamespace, "entra-credentials-secret-namespace", provider.DefaultEntraCredentialsSecretNamespace,
+		"The namespace of the secret with the Microsoft Entra ID credentials of the manager. Only used by the 'entra' provider.")
+	flag.StringVar(&entraConfig.CredentialsSecretName, "entra-credentials-secret-name", provider.DefaultEntraCredentialsSecretName,
+		"The name of the secret with the Microsoft Entra ID credentials of the manager. Only used by the 'entra' provider.")
+	flag.StringVar(&entraConfig.AuthorityHost, "entra-authority-host", provider.DefaultEntraAuthorityHost,
+		"The host of the Microsoft identity platform. Only used by the 'entra' provider.")
+	flag.StringVar(&entraConfig.GraphEndpoint, "entra-graph-endpoint", provider.DefaultEntraGraphEndpoint,
+		"The endpoint of Microsoft Graph. Only used by the 'entra' provider.")
+	flag.StringVar(&keycloakConfig.CredentialsSecretNamespace, "keycloak-credentials-secret-namespace", provider.DefaultKeycloakCredentialsSecretNamespace,
+		"The namespace of the secret with the Keycloak credentials of the manager. Only used by the 'keycloak' provider.")
+	flag.StringVar(&keycloakConfig.CredentialsSecretName, "keycloak-credentials-secret-name", provider.DefaultKeycloakCredentialsSecretName,
+		"The name of the secret with the Keycloak credentials of the manager. Only used by the 'keycloak' provider.")
+	flag.StringVar(&keycloakConfig.AdminRealm, "keycloak-admin-realm", provider.DefaultKeycloakAdminRealm,
+		"The realm of the Keycloak client of the manager. Only used by the 'keycloak' provider.")
+	flag.StringVar(&keycloakConfig.Realm, "keycloak-realm", provider.DefaultKeycloakRealm,
+		"The realm that the Keycloak clients of the SKR clusters are created in. It is created if it doesn't exist. Only used by the 'keycloak' provider.")
+	flag.StringVar(&auth0Config.CredentialsSecretNamespace, "auth0-credentials-secret-namespace", provider.DefaultAuth0CredentialsSecretNamespace,
+		"The namespace of the secret with the Auth0 credentials of the manager. Only used by the 'auth0' provider.")
+	flag.StringVar(&auth0Config.CredentialsSecretName, "auth0-credentials-secret-name", provider.DefaultAuth0CredentialsSecretName,
+		"The name of the secret with the Auth0 credentials of the manager. Only used by the 'auth0' provider.")
+	flag.StringVar(&auth0Config.Audience, "auth0-audience", "",
+		"The identifier of the Auth0 API that the applications of the SKR clusters are granted access to. Required by the 'auth0' provider.")
+	flag.StringVar(&auth0Scopes, "auth0-scopes", "",
+		"Comma-separated list of the scopes of the Auth0 API that are granted to the applications. Only used by the 'auth0' provider.")
+	flag.StringVar(&xsuaaConfig.CredentialsSecretNamespace, "xsuaa-credentials-secret-namespace", provider.DefaultXSUAACredentialsSecretNamespace,
+		"The namespace of the secret with the service manager binding that the XSUAA service instances are provisioned with. Only used by the 'xsuaa' provider.")
+	flag.StringVar(&xsuaaConfig.CredentialsSecretName, "xsuaa-credentials-secret-name", provider.DefaultXSUAACredentialsSecretName,
+		"The name of the secret with the service manager binding. Only used by the 'xsuaa' provider.")
+	flag.StringVar(&xsuaaConfig.Plan, "xsuaa-plan", provider.DefaultXSUAAPlan,
+		"The service plan of the XSUAA service instances. Only used by the 'xsuaa' provider.")
+	flag.StringVar(&iasServiceManagerConfig.CredentialsSecretNamespace, "ias-service-manager-credentials-secret-namespace",
+		provider.DefaultIASServiceManagerCredentialsSecretNamespace,
+		"The namespace of the secret with the service manager binding that the IAS service instances are provisioned with. Only used by the 'ias-service-manager' provider.")
+	flag.StringVar(&iasServiceManagerConfig.CredentialsSecretName, "ias-service-manager-credentials-secret-name",
+		provider.DefaultIASServiceManagerCredentialsSecretName,
+		"The name of the secret with the service manager binding. Only used by the 'ias-service-manager' provider.")
+	flag.StringVar(&iasServiceManagerConfig.Plan, "ias-service-manager-plan", provider.DefaultIASServiceManagerPlan,
+		"The service plan of the IAS service instances. Only used by the 'ias-service-manager' provider.")
+	flag.StringVar(&oktaConfig.CredentialsSecretNamespace, "okta-credentials-secret-namespace", provider.DefaultOktaCredentialsSecretNamespace,
+		"The namespace of the secret with the Okta credentials of the manager. Only used by the 'okta' provider.")
+	flag.StringVar(&oktaConfig.CredentialsSecretName, "okta-credentials-secret-name", provider.DefaultOktaCredentialsSecretName,
+		"The name of the secret with the Okta credentials of the manager. Only used by the 'okta' provider.")
+	flag.StringVar(&oktaConfig.AuthorizationServer, "okta-authorization-server", provider.DefaultOktaAuthorizationServer,
+		"The ID of the Okta authorization server that issues the tokens of the applications. Only used by the 'okta' provider.")
+	flag.BoolVar(&enableMemoryProvider, "enable-memory-provider", false,
+		"Enable the 'memory' provider, which only stores the applications in memory and returns credentials that no identity provider accepts. "+
+			"Only for development and tests.")
+	flag.StringVar(&memoryConfig.Issuer, "memory-provider-issuer", provider.DefaultMemoryIssuer,
+		"The URL that the token URL and the JWKS URI of the 'memory' provider are derived from.")
+	flag.DurationVar(&memoryConfig.Latency, "memory-provider-latency", 0,
+		"The duration that every call of the 'memory' provider is delayed by.")
+	flag.Float64Var(&memoryConfig.ErrorRate, "memory-provider-error-rate", 0,
+		"The probability between 0 and 1 that a call of the 'memory' provider fails.")
+	flag.Float64Var(&iasFaults.ErrorRate, "fault-injection-ias-error-rate", 0,
+		"The probability between 0 and 1 that a request to IAS fails without being sent. Only for chaos tests.")
+	flag.IntVar(&iasFaults.StatusCode, "fault-injection-ias-status-code", 0,
+		"The HTTP status code of the failed requests to IAS, e.g. 503. The requests fail with a connection error if it is 0.")
+	flag.DurationVar(&iasFaults.Latency, "fault-injection-ias-latency", 0,
+		"The duration that every request to IAS is delayed by. Only for chaos tests.")
+	flag.BoolVar(&iasFaults.WritesOnly, "fault-injection-ias-writes-only", false,
+		"Inject the faults only into the requests to IAS that create, update or delete applications.")
+	flag.Float64Var(&skrFaults.ErrorRate, "fault-injection-skr-error-rate", 0,
+		"The probability between 0 and 1 that a request to an SKR cluster fails without being sent. Only for chaos tests.")
+	flag.IntVar(&skrFaults.StatusCode, "fault-injection-skr-status-code", 0,
+		"The HTTP status code of the failed requests to the SKR clusters, e.g. 503. The requests fail with a connection error if it is 0.")
+	flag.DurationVar(&skrFaults.Latency, "fault-injection-skr-latency", 0,
+		"The duration that every request to an SKR cluster is delayed by. Only for chaos tests.")
+	flag.BoolVar(&skrFaults.WritesOnly, "fault-injection-skr-writes-only", true,
+		"Inject the faults only into the requests to the SKR clusters that write objects, e.g. the application secret.")
+	flag.BoolVar(&enableSkrSecret, "enable-skr-secret", true,
+		"Enable the creation of the application secret on the SKR clusters. If disabled, the credentials are only written to the configured sinks.")
+	flag.StringVar(&sinkFlags.vault.Address, "vault-address", "",
+		"The address of Vault, e.g. 'https://vault.example.com:8200'. If set, the credentials are additionally written to Vault.")
+	flag.StringVar(&sinkFlags.vault.Namespace, "vault-namespace", "", "The Vault Enterprise namespace. Only used with '--vault-address'.")
+	flag.StringVar(&sinkFlags.vault.AuthMount, "vault-auth-mount", sink.DefaultVaultAuthMount,
+		"The mount path of the Kubernetes auth method of Vault. Only used with '--vault-address'.")
+	flag.StringVar(&sinkFlags.vault.Role, "vault-role", "", "The role of the Kubernetes auth method of Vault. Only used with '--vault-address'.")
+	flag.StringVar(&sinkFlags.vault.ServiceAccountTokenFile, "vault-service-account-token-file", sink.DefaultVaultServiceAccountToken,
+		"The path of the service account token that is used to log in to Vault. Only used with '--vault-address'.")
+	flag.StringVar(&sinkFlags.vault.KVMount, "vault-kv-mount", sink.DefaultVaultKVMount,
+		"The mount path of the KV secrets engine (version 2) of Vault. Only used with '--vault-address'.")
+	flag.StringVar(&sinkFlags.vault.PathTemplate, "vault-path-template", sink.DefaultVaultPathTemplate,
+		"The Go template of the path of the credentials in the KV secrets engine. The runtime ID can be referenced with '{{ .RuntimeID }}'. "+
+			"Only used with '--vault-address'.")
+	flag.StringVar(&sinkFlags.vaultCACertFile, "vault-ca-cert", "",
+		"The path of the CA certificate that is used to verify the server certificate of Vault. Only used with '--vault-address'.")
+	flag.StringVar(&sinkFlags.aws.Region, "aws-secrets-manager-region", "",
+		"The AWS region of AWS Secrets Manager. If set, the credentials are additionally written to AWS Secrets Manager.")
+	flag.StringVar(&sinkFlags.aws.Endpoint, "aws-secrets-manager-endpoint", "",
+		"The endpoint of AWS Secrets Manager. Defaults to the public endpoint of the region. Only used with '--aws-secrets-manager-region'.")
+	flag.StringVar(&sinkFlags.aws.NameTemplate, "aws-secrets-manager-name-template", sink.DefaultAWSSecretsManagerNameTemplate,
+		"The Go template of the name of the secret in AWS Secrets Manager. The runtime ID can be referenced with '{{ .RuntimeID }}'. "+
+			"Only used with '--aws-secrets-manager-region'.")
+	flag.StringVar(&sinkFlags.gcp.Project, "gcp-secret-manager-project", "",
+		"The ID of the GCP project of GCP Secret Manager. If set, the credentials are additionally written to GCP Secret Manager.")
+	flag.StringVar(&sinkFlags.gcp.Endpoint, "gcp-secret-manager-endpoint", "",
+		"The endpoint of GCP Secret Manager. Defaults to the global endpoint. Only used with '--gcp-secret-manager-project'.")
+	flag.StringVar(&sinkFlags.gcp.NameTemplate, "gcp-secret-manager-name-template", sink.DefaultGCPSecretManagerNameTemplate,
+		"The Go template of the ID of the secret in GCP Secret Manager. The runtime ID can be referenced with '{{ .RuntimeID }}'. "+
+			"Only used with '--gcp-secret-manager-project'.")
+	flag.StringVar(&sinkFlags.azure.VaultURL, "azure-key-vault-url", "",
+		"The URL of Azure Key Vault, e.g. 'https://example.vault.azure.net'. If set, the credentials are additionally written to Azure Key Vault.")
+	flag.StringVar(&sinkFlags.azure.ClientID, "azure-key-vault-client-id", "",
+		"The client ID of the managed identity that is used to access Azure Key Vault. Defaults to the client ID of Azure Workload Identity "+
+			"or to the system-assigned managed identity. Only used with '--azure-key-vault-url'.")
+	flag.StringVar(&sinkFlags.azure.NameTemplate, "azure-key-vault-name-template", sink.DefaultAzureKeyVaultNameTemplate,
+		"The Go template of the name of the secret in Azure Key Vault. The runtime ID can be referenced with '{{ .RuntimeID }}'. "+
+			"Only used with '--azure-key-vault-url'.")
+	flag.StringVar(&sinkFlags.externalSecrets.StoreName, "external-secrets-store", "",
+		"The name of the secret store of the External Secrets Operator that the credentials are pushed to with PushSecrets. "+
+			"If set, the credentials are additionally delivered with the External Secrets Operator.")
+	flag.StringVar(&sinkFlags.externalSecrets.StoreKind, "external-secrets-store-kind", sink.DefaultExternalSecretsStoreKind,
+		"The kind of the secret store that the credentials are pushed to. Value can be one of ('SecretStore', 'ClusterSecretStore'). "+
+			"Only used with '--external-secrets-store'.")
+	flag.StringVar(&sinkFlags.externalSecrets.Namespace, "external-secrets-namespace", skr.KcpNamespace,
+		"The namespace of the source secrets and PushSecrets. Only used with '--external-secrets-store'.")
+	flag.StringVar(&sinkFlags.externalSecrets.RemoteKeyTemplate, "external-secrets-remote-key-template", sink.DefaultExternalSecretsRemoteKeyTemplate,
+		"The Go template of the key of the credentials in the secret store. The runtime ID can be referenced with '{{ .RuntimeID }}'. "+
+			"Only used with '--external-secrets-store'.")
+	flag.DurationVar(&sinkFlags.externalSecrets.RefreshInterval, "external-secrets-refresh-interval", sink.DefaultExternalSecretsRefreshInterval,
+		"The refresh interval of the PushSecrets and ExternalSecrets. Only used with '--external-secrets-store'.")
+	flag.StringVar(&sinkFlags.externalSecrets.SKRStoreName, "external-secrets-skr-store", "",
+		"The name of the secret store on the SKR clusters. If set, an ExternalSecret that creates the application secret from the secret store "+
+			"is created on the SKR clusters instead of the application secret. Requires '--enable-skr-secret=false'.")
+	flag.StringVar(&sinkFlags.externalSecrets.SKRStoreKind, "external-secrets-skr-store-kind", sink.DefaultExternalSecretsStoreKind,
+		"The kind of the secret store on the SKR clusters. Value can be one of ('SecretStore', 'ClusterSecretStore'). "+
+			"Only used with '--external-secrets-skr-store'.")
+	flag.BoolVar(&sinkFlags.enableSealedSecrets, "enable-sealed-secrets", false,
+		"Apply a SealedSecret that is sealed for the SealedSecrets controller of the SKR clusters instead of the application secret. "+
+			"Requires '--enable-skr-secret=false'.")
+	flag.StringVar(&sinkFlags.sealedSecrets.ControllerNamespace, "sealed-secrets-controller-namespace", sink.DefaultSealedSecretsControllerNamespace,
+		"The namespace of the SealedSecrets controller on the SKR clusters. Only used with '--enable-sealed-secrets'.")
+	flag.StringVar(&sinkFlags.sealedSecrets.ControllerName, "sealed-secrets-controller-name", sink.DefaultSealedSecretsControllerName,
+		"The name of the service of the SealedSecrets controller on the SKR clusters. Only used with '--enable-sealed-secrets'.")
+	flag.StringVar(&sinkFlags.sealedSecrets.CertFile, "sealed-secrets-cert", "",
+		"The path of the certificate that the credentials are sealed with for all SKR clusters. If not set, the certificate is read from "+
+			"the SealedSecrets controller of each SKR cluster. Only used with '--enable-sealed-secrets'.")
+	flag.StringVar(&sinkFlags.credentialStore.BindingPath, "credential-store-binding", "",
+		"The directory of the mounted service binding of the SAP Credential Store. If set, the credentials are additionally written to the "+
+			"SAP Credential Store.")
+	flag.StringVar(&sinkFlags.credentialStore.Namespace, "credential-store-namespace", sink.DefaultCredentialStoreNamespace,
+		"The namespace of the SAP Credential Store that contains the credentials. Only used with '--credential-store-binding'.")
+	flag.StringVar(&sinkFlags.credentialStore.NameTemplate, "credential-store-name-template", sink.DefaultCredentialStoreNameTemplate,
+		"The Go template of the name of the credential in the SAP Credential Store. The runtime ID can be referenced with '{{ .RuntimeID }}'. "+
+			"Only used with '--credential-store-binding'.")
+	flag.BoolVar(&sinkFlags.enableDestination, "enable-destination", false,
+		"Create or update a destination with the credentials in the SAP Destination Service of the subaccounts of the SKR clusters.")
+	flag.StringVar(&sinkFlags.destination.Namespace, "destination-namespace", skr.KcpNamespace,
+		"The namespace of the Kyma CRs and the destination service bindings. Only used with '--enable-destination'.")
+	flag.StringVar(&sinkFlags.destination.SubaccountLabel, "destination-subaccount-label", sink.DefaultDestinationSubaccountLabel,
+		"The label of the Kyma CRs that contains the subaccount ID. Only used with '--enable-destination'.")
+	flag.StringVar(&sinkFlags.destination.BindingSecretPrefix, "destination-binding-secret-prefix", sink.DefaultDestinationBindingSecretPrefix,
+		"The prefix of the name of the secret of the destination service binding of a subaccount, followed by the subaccount ID. "+
+			"Only used with '--enable-destination'.")
+	flag.StringVar(&sinkFlags.destination.NameTemplate, "destination-name-template", sink.DefaultDestinationNameTemplate,
+		"The Go template of the name of the destination. The runtime ID can be referenced with '{{ .RuntimeID }}'. "+
+			"Only used with '--enable-destination'.")
+	flag.StringVar(&sinkFlags.destination.URLTemplate, "destination-url-template", "",
+		"The Go template of the URL of the destination. The runtime ID can be referenced with '{{ .RuntimeID }}'. If not set, the URL of "+
+			"the IAS tenant is used. Only used with '--enable-destination'.")
+	flag.StringVar(&auditFlags.file, "audit-log-file", "",
+		"The path of the file that the audit records of the identity operations are appended to as JSON lines. If set, the creations, "+
+			"deletions, and rotations of the applications and the writes of their credentials are recorded in the file.")
+	flag.StringVar(&auditFlags.endpoint, "audit-log-endpoint", "",
+		"The URL that each audit record of the identity operations is posted to as JSON object.")
+	flag.StringVar(&auditFlags.endpointHeaders, "audit-log-endpoint-headers", "",
+		"The headers that are sent with each audit record as comma-separated list of key=value pairs with URL-encoded values, "+
+			"e.g. 'Authorization=Bearer%20token'. Only used with '--audit-log-endpoint'.")
+	flag.StringVar(&auditFlags.sapAuditLogBinding, "sap-audit-log-binding", "",
+		"The directory of the mounted service binding of the SAP Audit Log service with the plan 'oauth2'. If set, the audit records of the "+
+			"identity operations are written to the SAP Audit Log service as configuration changes.")
+	flag.StringVar(&auditFlags.actor, "audit-actor", defaultAuditActor,
+		"The actor of the audit records, which is the identity that the manager performs the identity operations with.")
+	flag.StringVar(&gardenerKubeconfigPath, "gardener-kubeconfig", "",
+		"The path to the kubeconfig of the Gardener cluster. Only used with '--skr-kubeconfig-source=gardener'.")
+	flag.StringVar(&gardenerProjectNamespace, "gardener-project-namespace", "",
+		"The namespace of the Gardener project containing the shoots of the SKR clusters. Only used with '--skr-kubeconfig-source=gardener'.")
+	flag.StringVar(&gardenerShootNameTemplate, "gardener-shoot-name-template", skr.DefaultGardenerShootNameTemplate,
+		"The Go template of the name of the shoots of the SKR clusters. The runtime ID can be referenced with '{{ .RuntimeID }}'. "+
+			"Only used with '--skr-kubeconfig-source=gardener'.")
+	flag.DurationVar(&gardenerAdminKubeconfigTTL, "gardener-admin-kubeconfig-ttl", skr.DefaultGardenerAdminKubeconfigTTL,
+		"The validity of the requested admin kubeconfigs. The kubeconfigs are renewed before they expire. Only used with '--skr-kubeconfig-source=gardener'.")
 	flag.Parse()
+	if configFile != "" {
+		if err := applyConfigFile(flag.CommandLine, configFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if printVersion {
+		fmt.Println(version.Get()) //nolint:forbidigo // The version is printed instead of logged.
+		return
+	}
+
+	logger, logLevel, err := newLogger(logFlags, os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	kcontrollerruntime.SetLogger(logger)
+	buildInfo := version.Get()
+	setupLog.Info("starting eventing-auth-manager", "version", buildInfo.Version, "gitSHA", buildInfo.GitSHA, "buildDate", buildInfo.BuildDate,
+		"goVersion", buildInfo.GoVersion)
+
+	tracingConfig.ServiceName = serviceName
+	tracingConfig.ServiceVersion = buildInfo.Version
+	tracingConfig.ExportTimeout = tracingExportTimeout
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
+	if err := bindAddressFlags.validate(); err != nil {
+		setupLog.Error(err, "invalid configuration of the bind addresses")
+		os.Exit(1)
+	}
+	webhookHost, webhookPort, _ := bindAddressFlags.webhookHostAndPort()
 
-	kcontrollerruntime.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	if enableLeaderElection {
+		if err := leaderElectionFlags.validate(); err != nil {
+			setupLog.Error(err, "invalid configuration of the leader election")
+			os.Exit(1)
+		}
+	}
 
-	mgr, err := kcontrollerruntime.NewManager(kcontrollerruntime.GetConfigOrDie(), kcontrollerruntime.Options{
+	if err := iasFaults.Validate(); err != nil {
+		setupLog.Error(err, "invalid configuration of the fault injection into the requests to IAS")
+		os.Exit(1)
+	}
+	if err := skrFaults.Validate(); err != nil {
+		setupLog.Error(err, "invalid configuration of the fault injection into the requests to the SKR clusters")
+		os.Exit(1)
+	}
+	if err := eamias.DuplicatePolicy(iasDuplicatePolicy).Validate(); err != nil {
+		setupLog.Error(err, "invalid configuration of the IAS clients")
+		os.Exit(1)
+	}
+	if err := eamcontrollers.OrphanedApplicationPolicy(orphanedApplicationPolicy).Validate(); err != nil {
+		setupLog.Error(err, "invalid configuration of the deletion of applications")
+		os.Exit(1)
+	}
+	switch eamapiv1alpha1.CredentialPropagation(credentialPropagation) {
+	case eamapiv1alpha1.CredentialPropagationSKR, eamapiv1alpha1.CredentialPropagationKCP, eamapiv1alpha1.CredentialPropagationBoth:
+	default:
+		setupLog.Error(errors.Errorf("unsupported credential propagation: %s", credentialPropagation),
+			"invalid configuration of the credential propagation")
+		os.Exit(1)
+	}
+	if iasTenantFailoverDelay <= 0 {
+		setupLog.Error(errors.New("--ias-tenant-failover-delay must be positive"), "invalid configuration of the failover of IAS tenants")
+		os.Exit(1)
+	}
+	if maxConcurrentReconciles < 1 {
+		setupLog.Error(errors.New("--max-concurrent-reconciles must be at least 1"), "invalid configuration of the concurrency")
+		os.Exit(1)
+	}
+	if applicationDeletionMaxAttempts < 0 {
+		setupLog.Error(errors.New("--application-deletion-max-attempts must not be negative"),
+			"invalid configuration of the deletion of applications")
+		os.Exit(1)
+	}
+	if iasFaults.Enabled() || skrFaults.Enabled() {
+		setupLog.Info("Faults are injected into the requests to IAS and the SKR clusters, only for chaos tests", "ias", iasFaults, "skr", skrFaults)
+	}
+
+	eamcontrollers.SetShutdownGracePeriod(shutdownGracePeriod)
+	// The reconciliations that are cancelled at the end of the grace period get a moment to return, before the manager gives up on them.
+	managerShutdownTimeout := shutdownGracePeriod + shutdownReturnTimeout
+	iasCredentialsSecretNamespace, _ := provider.IASCredentialsSecret()
+	secretCache, err := newSecretCache(secretCacheFlags, []string{
+		skrKubeconfigSecretNamespace, iasCredentialsSecretNamespace, entraConfig.CredentialsSecretNamespace,
+		keycloakConfig.CredentialsSecretNamespace, auth0Config.CredentialsSecretNamespace, xsuaaConfig.CredentialsSecretNamespace,
+		iasServiceManagerConfig.CredentialsSecretNamespace, oktaConfig.CredentialsSecretNamespace,
+	})
+	if err != nil {
+		setupLog.Error(err, "invalid configuration of the secret cache")
+		os.Exit(1)
+	}
+	leaderElectionID := "210590f8.kyma-project.io"
+	if dryRun {
+		if installCRDs {
+			setupLog.Error(errors.New("invalid flags"), "'--install-crds' can't be combined with '--dry-run'")
+			os.Exit(1)
+		}
+		// A dry run is usually run next to the manager of the landscape, which must keep its leader lease.
+		leaderElectionID = "210590f8.dry-run.kyma-project.io"
+		logReconcileDecisions = true
+		setupLog.Info("Dry run: the actions of the controllers are only logged")
+	}
+	restConfig := kcontrollerruntime.GetConfigOrDie()
+	if err := setupCRDs(restConfig, installCRDs, enableKymaController, enableIASApplicationController); err != nil {
+		setupLog.Error(err, "unable to verify the CRDs")
+		os.Exit(1)
+	}
+	mgr, err := kcontrollerruntime.NewManager(restConfig, kcontrollerruntime.Options{
 		Scheme:                 initScheme(),
-		HealthProbeBindAddress: probeAddr,
+		HealthProbeBindAddress: bindAddressFlags.healthProbe,
 		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "210590f8.kyma-project.io",
-		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
-		// when the Manager ends. This requires the binary to immediately end when the
-		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
-		// speeds up voluntary leader transitions as the new leader don't have to wait
-		// LeaseDuration time first.
-		//
-		// In the default scaffold provided, the program ends immediately after
-		// the manager stops, so would be fine to enable this option. However,
-		// if you are doing or is intended to do any operation such as perform cleanups
-		// after the manager stops then its usage might be unsafe.
-		// LeaderElectionReleaseOnCancel: true,
+		LeaderElectionID:       leaderElectionID,
+		LeaseDuration:          &leaderElectionFlags.leaseDuration,
+		RenewDeadline:          &leaderElectionFlags.renewDeadline,
+		RetryPeriod:            &leaderElectionFlags.retryPeriod,
+		// LeaderElectionReleaseOnCancel steps down voluntarily when the manager ends. This is safe, since the binary ends right after the
+		// manager is stopped and only exports the remaining spans afterward, and speeds up the leader transitions on rolling updates,
+		// as the new leader doesn't have to wait for the lease duration first.
+		LeaderElectionReleaseOnCancel: leaderElectionFlags.releaseOnCancel,
+		GracefulShutdownTimeout:       &managerShutdownTimeout,
 		Metrics: server.Options{
-			BindAddress: metricsAddr,
+			BindAddress: bindAddressFlags.metrics,
 		},
+		PprofBindAddress: bindAddressFlags.pprof,
+		// Only the kubeconfig and credentials secrets are cached, since caching every secret of a large KCP dominates the memory usage.
+		Cache:     cache.Options{ByObject: secretCache.byObject()},
+		NewClient: newDryRunClient(secretCache.newClient(), dryRun),
 		WebhookServer: webhook.NewServer(webhook.Options{
+			Host: webhookHost,
 			Port: webhookPort,
 		},
 		),
@@ -85,35 +709,648 @@ func main() {
 		os.Exit(1)
 	}
 
-	kymaReconciler := eamcontrollers.NewKymaReconciler(mgr.GetClient(), mgr.GetScheme())
-	if err = kymaReconciler.SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "Kyma")
+	var skrKubeconfigProvider skr.KubeconfigProvider
+	switch skrKubeconfigSource {
+	case skrKubeconfigSourceSecret:
+		skrKubeconfigSecretConfig, err := skr.NewKubeconfigSecretConfig(skrKubeconfigSecretNamespace, skrKubeconfigSecretNameTemplate)
+		if err != nil {
+			setupLog.Error(err, "invalid configuration of the SKR kubeconfig secrets")
+			os.Exit(1)
+		}
+		skrKubeconfigProvider = skr.NewSecretKubeconfigProvider(mgr.GetClient(), mgr.GetAPIReader(), skrKubeconfigSecretConfig)
+	case skrKubeconfigSourceGardener:
+		skrKubeconfigProvider, err = newGardenerKubeconfigProvider(gardenerKubeconfigPath, gardenerProjectNamespace, gardenerShootNameTemplate, gardenerAdminKubeconfigTTL)
+		if err != nil {
+			setupLog.Error(err, "unable to set up Gardener access to SKR clusters")
+			os.Exit(1)
+		}
+	case skrKubeconfigSourceLocal:
+		skrKubeconfigProvider, err = skr.NewLocalKubeconfigProvider(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to set up access to the local cluster")
+			os.Exit(1)
+		}
+		setupLog.Info("Application secrets are written into the local cluster")
+	default:
+		setupLog.Error(errors.Errorf("unsupported SKR kubeconfig source: %s", skrKubeconfigSource), "invalid configuration of the SKR kubeconfig source")
 		os.Exit(1)
 	}
+	// EventingAuth CRs with spec.kubeconfigSecretRef target arbitrary clusters regardless of the SKR kubeconfig source.
+	var allowedKubeconfigSecretNamespaces []string
+	for _, namespace := range strings.Split(kubeconfigSecretRefNamespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			allowedKubeconfigSecretNamespaces = append(allowedKubeconfigSecretNamespaces, namespace)
+		}
+	}
+	skrKubeconfigProvider = skr.NewReferencedKubeconfigProvider(mgr.GetClient(), mgr.GetAPIReader(), skrKubeconfigProvider,
+		allowedKubeconfigSecretNamespaces)
+
+	// The controllers aren't started with '--run-once', their reconcilers are run by the pass instead.
+	var kymaReconciler eamcontrollers.ManagedReconciler
+	if enableKymaController {
+		kymaReconciler = eamcontrollers.NewKymaReconciler(mgr.GetClient(), mgr.GetScheme(), enableKymaFinalizer, sharedApplicationLabel)
+		if !runOnce {
+			if err = kymaReconciler.SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "Kyma")
+				os.Exit(1)
+			}
+		}
+	} else {
+		setupLog.Info("Kyma controller is disabled, only externally created EventingAuth CRs are reconciled")
+	}
 
-	eventingAuthReconciler := eamcontrollers.NewEventingAuthReconciler(mgr.GetClient(), mgr.GetScheme())
-	if err = eventingAuthReconciler.SetupWithManager(mgr); err != nil {
-		setupLog.Error(err, "unable to create controller", "controller", "EventingAuth")
+	skrClientOptions, err := newSkrClientOptions(skr.ClientOptions{
+		AllowExecPlugins: skrAllowKubeconfigExecPlugins,
+		CreateNamespace:  skrCreateSecretNamespace,
+		QPS:              float32(skrClientQPS),
+		Burst:            skrClientBurst,
+		RequestTimeout:   skrRequestTimeout,
+		SecretFormat:     skr.SecretFormat{Type: kcorev1.SecretType(skrSecretType), BTPServiceOperator: skrSecretBTPFormat},
+		DryRun:           dryRun,
+		Faults:           skrFaults,
+	}, skrSecretFlags)
+	if err != nil {
+		setupLog.Error(err, "invalid configuration of the SKR clients")
+		os.Exit(1)
+	}
+	if enableSkrSecret && sinkFlags.writesApplicationSecret() {
+		setupLog.Error(errors.New("invalid flags"), "the application secret is created by a sink, '--enable-skr-secret' must be disabled "+
+			"with '--external-secrets-skr-store' and '--enable-sealed-secrets'")
+		os.Exit(1)
+	}
+
+	// The clients of the SKR clusters are created lazily, so the cache is also used by sinks that write to the SKR clusters.
+	skrClientCache := skr.NewClientCache(skrKubeconfigProvider, skrClientOptions, skrClientCacheTTL)
+	ctx := kcontrollerruntime.SetupSignalHandler()
+	sinks, err := newSinks(ctx, mgr.GetClient(), skrClientCache, sinkFlags)
+	if err != nil {
+		setupLog.Error(err, "invalid configuration of the sinks")
+		os.Exit(1)
+	}
+	if dryRun {
+		for i := range sinks {
+			sinks[i] = sink.NewDryRunSink(sinks[i])
+		}
+	}
+
+	var reconcilerSkrClientCache *skr.ClientCache
+	var skrSecretWatcher *skr.SecretWatcher
+	var skrSubscriptionWatcher *skr.SubscriptionWatcher
+	if enableSkrSecret {
+		reconcilerSkrClientCache = skrClientCache
+		if enableSkrSecretWatch {
+			skrSecretWatcher = skr.NewSecretWatcher(skrClientCache, skrSecretWatchMaxClusters)
+		}
+		if enableSkrSubscriptionWatch {
+			skrSubscriptionWatcher = skr.NewSubscriptionWatcher(skrClientCache, skrSecretWatchMaxClusters)
+		}
+	} else {
+		if len(sinks) == 0 {
+			setupLog.Error(errors.New("no sink is configured"), "credentials must be written to the SKR clusters or to at least one sink")
+			os.Exit(1)
+		}
+		setupLog.Info("Application secrets are not created on the SKR clusters, credentials are only written to the sinks")
+	}
+	providerHTTPClient, err := sink.NewHTTPClient("")
+	if err != nil {
+		setupLog.Error(err, "failed to create HTTP client of the identity providers")
+		os.Exit(1)
+	}
+	for _, scope := range strings.Split(auth0Scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			auth0Config.Scopes = append(auth0Config.Scopes, scope)
+		}
+	}
+	// The IAS clients of the tenants are shared by the IAS provider and the health checks of the IASTenant CRs.
+	iasClientPool := provider.NewIASClientPool(mgr.GetClient())
+	iasClientPool.InjectFaults(iasFaults)
+	iasClientPool.SetDuplicatePolicy(eamias.DuplicatePolicy(iasDuplicatePolicy))
+	iasClientPool.DetectMaintenance(iasMaintenanceThreshold)
+	iasClientPool.SetDiscoveryTTL(iasDiscoveryTTL)
+	iasClientPool.SetRequestTimeout(iasRequestTimeout)
+	// The providers only read their credentials when an EventingAuth CR requests them, so all providers are available.
+	providerFactories := map[string]provider.Factory{
+		provider.IASName:               provider.NewIASFactory(iasClientPool),
+		provider.IASServiceManagerName: provider.NewIASServiceManagerFactory(providerHTTPClient, mgr.GetClient(), iasServiceManagerConfig),
+		provider.EntraName:             provider.NewEntraFactory(providerHTTPClient, mgr.GetClient(), entraConfig),
+		provider.KeycloakName:          provider.NewKeycloakFactory(providerHTTPClient, mgr.GetClient(), keycloakConfig),
+		provider.Auth0Name:             provider.NewAuth0Factory(providerHTTPClient, mgr.GetClient(), auth0Config),
+		provider.XSUAAName:             provider.NewXSUAAFactory(providerHTTPClient, mgr.GetClient(), xsuaaConfig),
+		provider.OktaName:              provider.NewOktaFactory(providerHTTPClient, mgr.GetClient(), oktaConfig),
+	}
+	if enableMemoryProvider {
+		if memoryConfig.ErrorRate < 0 || memoryConfig.ErrorRate > 1 {
+			setupLog.Error(errors.New("error rate must be between 0 and 1"), "invalid configuration of the memory provider")
+			os.Exit(1)
+		}
+		providerFactories[provider.MemoryName] = provider.NewMemoryFactory(memoryConfig)
+		setupLog.Info("The memory provider is enabled, its credentials are not accepted by any identity provider")
+	}
+	if dryRun {
+		for name, factory := range providerFactories {
+			providerFactories[name] = provider.NewDryRunFactory(factory)
+		}
+	}
+	providers, err := provider.NewRegistry(defaultProvider, providerFactories)
+	if err != nil {
+		setupLog.Error(err, "invalid configuration of the identity providers")
+		os.Exit(1)
+	}
+	auditor, err := newAuditRecorder(auditFlags, logger)
+	if err != nil {
+		setupLog.Error(err, "invalid configuration of the audit log")
 		os.Exit(1)
 	}
+	if dryRun {
+		// The identity operations of a dry run aren't performed, so they must not appear in the audit log.
+		auditor = nil
+	}
+	eventingAuthRecorder, iasTenantRecorder := mgr.GetEventRecorderFor("eventingauth-controller"), mgr.GetEventRecorderFor("iastenant-controller")
+	if dryRun {
+		eventingAuthRecorder, iasTenantRecorder = dryrun.NewEventRecorder(eventingAuthRecorder), dryrun.NewEventRecorder(iasTenantRecorder)
+	}
+	eventingAuthReconciler := eamcontrollers.NewEventingAuthReconciler(mgr.GetClient(), mgr.GetScheme(), providers,
+		eamcontrollers.EventingAuthReconcilerOptions{
+			IASClientPool:       iasClientPool,
+			SKRClientCache:      reconcilerSkrClientCache,
+			SecretWatcher:       skrSecretWatcher,
+			SubscriptionWatcher: skrSubscriptionWatcher,
+			Sinks:               sinks,
+			Landscape:           landscape,
+			Recorder:            eventingAuthRecorder,
+			KymaEvents:          enableKymaEvents,
+			Auditor:             auditor,
+			LogDecisions:        logReconcileDecisions,
+			Deletion: eamcontrollers.ApplicationDeletionOptions{
+				MaxAttempts:    applicationDeletionMaxAttempts,
+				OrphanedPolicy: eamcontrollers.OrphanedApplicationPolicy(orphanedApplicationPolicy),
+			},
+			CredentialsRotationReminder:  credentialsRotationReminder,
+			DefaultCredentialPropagation: eamapiv1alpha1.CredentialPropagation(credentialPropagation),
+			IASTenantFailoverDelay:       iasTenantFailoverDelay,
+			HTTPClient:                   providerHTTPClient,
+			MaxConcurrentReconciles:      maxConcurrentReconciles,
+		})
+	iasTenantReconciler := eamcontrollers.NewIASTenantReconciler(mgr.GetClient(), mgr.GetScheme(), iasClientPool, providerHTTPClient,
+		iasTenantRecorder)
+	var pass *runOncePass
+	if runOnce {
+		var stop context.CancelFunc
+		ctx, stop = context.WithCancel(ctx)
+		// The objects are listed from the API server, since the caches of the controllers aren't started.
+		pass, err = newRunOncePass(runOnceScope, mgr.GetAPIReader(), iasTenantReconciler, kymaReconciler, eventingAuthReconciler, stop)
+		if err != nil {
+			setupLog.Error(err, "invalid configuration of '--run-once'")
+			os.Exit(1)
+		}
+		if err := mgr.Add(pass); err != nil {
+			setupLog.Error(err, "unable to set up the reconciliation pass")
+			os.Exit(1)
+		}
+		setupLog.Info("Controllers are not started, the CRs are reconciled once", "scope", runOnceScope)
+	} else {
+		if err = eventingAuthReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "EventingAuth")
+			os.Exit(1)
+		}
+		if err = iasTenantReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "IASTenant")
+			os.Exit(1)
+		}
+		if enableIASApplicationController {
+			iasApplicationRecorder := mgr.GetEventRecorderFor("iasapplication-controller")
+			if dryRun {
+				iasApplicationRecorder = dryrun.NewEventRecorder(iasApplicationRecorder)
+			}
+			iasApplicationReconciler := eamcontrollers.NewIASApplicationReconciler(mgr.GetClient(), mgr.GetScheme(), providers,
+				iasApplicationRecorder, auditor)
+			if err = iasApplicationReconciler.SetupWithManager(mgr); err != nil {
+				setupLog.Error(err, "unable to create controller", "controller", "IASApplication")
+				os.Exit(1)
+			}
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	if stuckReconcileThreshold > 0 {
+		if err := mgr.AddHealthzCheck("reconcile", eamcontrollers.StuckReconcileCheck(stuckReconcileThreshold)); err != nil {
+			setupLog.Error(err, "unable to set up stuck reconcile check")
+			os.Exit(1)
+		}
+	}
+	if logLevelConfigMap != "" {
+		clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create client for the log level ConfigMap")
+			os.Exit(1)
+		}
+		if err := mgr.Add(newLogLevelWatcher(clientset, logLevelConfigMapNamespace, logLevelConfigMap, logLevel, logger)); err != nil {
+			setupLog.Error(err, "unable to set up the watch of the log level ConfigMap")
+			os.Exit(1)
+		}
+	}
+	if bindAddressFlags.admin != "" && bindAddressFlags.admin != disabledBindAddress {
+		adminConfig.BindAddress = bindAddressFlags.admin
+		if err := addAdminServer(mgr, adminConfig); err != nil {
+			setupLog.Error(err, "unable to set up the admin API")
+			os.Exit(1)
+		}
+	}
+	if inventoryConfig.Name != "" {
+		if err := addInventorySnapshotter(mgr, inventoryConfig, dryRun); err != nil {
+			setupLog.Error(err, "unable to set up the inventory ConfigMap")
+			os.Exit(1)
+		}
+	}
+	if otlpMetricsConfig.Endpoint != "" {
+		if err := addOTLPMetricsPusher(mgr, otlpMetricsConfig, otlpMetricsHeaders, buildInfo.Version); err != nil {
+			setupLog.Error(err, "unable to set up the push of the metrics")
+			os.Exit(1)
+		}
+	}
+	if profilingConfig.Endpoint != "" {
+		if err := addProfilingPusher(mgr, profilingConfig, profilingHeaders, buildInfo.Version, landscape); err != nil {
+			setupLog.Error(err, "unable to set up continuous profiling")
+			os.Exit(1)
+		}
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if iasReadinessInterval > 0 {
+		iasReadinessCheck := eamcontrollers.NewIASReadinessCheck(mgr.GetClient(), iasClientPool, iasReadinessInterval)
+		if err := mgr.Add(iasReadinessCheck); err != nil {
+			setupLog.Error(err, "unable to set up the probes of the IAS tenants")
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("ias", iasReadinessCheck.Check); err != nil {
+			setupLog.Error(err, "unable to set up IAS ready check")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(kcontrollerruntime.SetupSignalHandler()); err != nil {
+	err = mgr.Start(ctx)
+	// The spans of the last reconciliations are exported, before the manager exits.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingExportTimeout)
+	defer cancel()
+	if shutdownErr := shutdownTracing(shutdownCtx); shutdownErr != nil {
+		setupLog.Error(shutdownErr, "unable to export the remaining spans")
+	}
+	if err != nil {
 		setupLog.Error(err, "problem running manager")
-		os.Exit(1)
+		os.Exit(1) //nolint:gocritic // The spans are exported before.
+	}
+	if pass != nil {
+		if err := pass.Result(); err != nil {
+			setupLog.Error(err, "reconciliation pass failed")
+			os.Exit(1)
+		}
 	}
 }
 
+// newDryRunClient returns the function that creates the client of the manager with the given function. In a dry run, the writes of the client
+// are logged and sent as server-side dry run, so that they are validated without changing KCP.
+func newDryRunClient(newClient kpkgclient.NewClientFunc, dryRun bool) kpkgclient.NewClientFunc {
+	return func(config *rest.Config, options kpkgclient.Options) (kpkgclient.Client, error) {
+		c, err := newClient(config, options)
+		if err != nil || !dryRun {
+			return c, err
+		}
+		return dryrun.NewClient(c, true), nil
+	}
+}
+
+// addAdminServer adds a runnable to the manager that serves the admin API, which lists the applications of the EventingAuth CRs in the
+// cache of the manager.
+func addAdminServer(mgr kcontrollerruntime.Manager, config admin.Config) error {
+	clientset, err := kubernetes.NewForConfig(mgr.GetConfig())
+	if err != nil {
+		return errors.Wrap(err, "failed to create the client to review the requests")
+	}
+	server, err := admin.NewServer(config, mgr.GetClient(), clientset)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(server)
+}
+
+// addInventorySnapshotter adds a runnable to the manager that writes the inventory of the EventingAuth CRs in the cache of the manager to
+// the inventory ConfigMap. The ConfigMap is read and written without the cache of the manager, so that not all ConfigMaps of the cluster
+// are cached. In a dry run, the writes are sent as server-side dry run.
+func addInventorySnapshotter(mgr kcontrollerruntime.Manager, config admin.SnapshotConfig, dryRun bool) error {
+	writer, err := kpkgclient.New(mgr.GetConfig(), kpkgclient.Options{Scheme: mgr.GetScheme()})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the client of the inventory ConfigMap")
+	}
+	if dryRun {
+		writer = dryrun.NewClient(writer, true)
+	}
+	snapshotter, err := admin.NewSnapshotter(config, mgr.GetClient(), writer)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(snapshotter)
+}
+
+// addOTLPMetricsPusher adds a runnable to the manager that pushes the metrics of the metrics endpoint to the OTLP receiver.
+func addOTLPMetricsPusher(mgr kcontrollerruntime.Manager, config otlpmetrics.Config, headers, serviceVersion string) error {
+	var err error
+	config.Headers, err = otlpmetrics.ParseHeaders(headers)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse --otlp-metrics-headers")
+	}
+	config.ServiceName = serviceName
+	config.ServiceVersion = serviceVersion
+	pusher, err := otlpmetrics.NewPusher(config, metrics.Registry)
+	if err != nil {
+		return err
+	}
+	return mgr.Add(pusher)
+}
+
+// addProfilingPusher adds a runnable to the manager that pushes the profiles of the manager to the profiling receiver. The profiles are
+// tagged with the version, the landscape, and the pod of the manager.
+func addProfilingPusher(mgr kcontrollerruntime.Manager, config profiling.Config, headers, serviceVersion, landscape string) error {
+	var err error
+	config.Headers, err = otlpmetrics.ParseHeaders(headers)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse --profiling-headers")
+	}
+	config.ApplicationName = serviceName
+	config.Tags = map[string]string{"version": serviceVersion, "landscape": landscape, "instance": os.Getenv("HOSTNAME")}
+	pusher, err := profiling.NewPusher(config, &http.Client{Timeout: config.Interval})
+	if err != nil {
+		return err
+	}
+	return mgr.Add(pusher)
+}
+
+func newGardenerKubeconfigProvider(kubeconfigPath, projectNamespace, shootNameTemplate string, kubeconfigTTL time.Duration) (skr.KubeconfigProvider, error) {
+	gardenerConfig, err := skr.NewGardenerConfig(projectNamespace, shootNameTemplate, kubeconfigTTL)
+	if err != nil {
+		return nil, err
+	}
+	if kubeconfigPath == "" {
+		return nil, errors.New("path to the kubeconfig of the Gardener cluster must not be empty")
+	}
+	gardenRestConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read kubeconfig of the Gardener cluster")
+	}
+	gardenClient, err := kpkgclient.New(gardenRestConfig, kpkgclient.Options{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client of the Gardener cluster")
+	}
+	return skr.NewGardenerKubeconfigProvider(gardenClient, gardenerConfig), nil
+}
+
+// sinkFlags contains the configurations of the sinks that are set by flags. A sink is enabled if its required configuration is set.
+type sinkFlags struct {
+	vault           sink.VaultConfig
+	vaultCACertFile string
+	aws             sink.AWSSecretsManagerConfig
+	gcp             sink.GCPSecretManagerConfig
+	azure           sink.AzureKeyVaultConfig
+	externalSecrets sink.ExternalSecretsConfig
+	// enableSealedSecrets enables the SealedSecrets sink, since it has no required configuration.
+	enableSealedSecrets bool
+	sealedSecrets       sink.SealedSecretsConfig
+	credentialStore     sink.CredentialStoreConfig
+	// enableDestination enables the SAP Destination Service sink, since the destination service bindings are read per subaccount.
+	enableDestination bool
+	destination       sink.DestinationConfig
+}
+
+// writesApplicationSecret returns true if an enabled sink writes the application secret on the SKR clusters.
+func (f sinkFlags) writesApplicationSecret() bool {
+	return f.externalSecrets.SKRStoreName != "" || f.enableSealedSecrets
+}
+
+// newSinks returns the sinks that are enabled by the given flags.
+func newSinks(ctx context.Context, kcpClient kpkgclient.Client, skrClientCache *skr.ClientCache, flags sinkFlags) ([]sink.Sink, error) {
+	var sinks []sink.Sink
+	if flags.vault.Address != "" {
+		httpClient, err := sink.NewHTTPClient(flags.vaultCACertFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create HTTP client of Vault")
+		}
+		vaultSink, err := sink.NewVaultSink(httpClient, flags.vault)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, vaultSink)
+	}
+	if flags.aws.Region != "" {
+		httpClient, err := sink.NewHTTPClient("")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create HTTP client of AWS Secrets Manager")
+		}
+		awsSink, err := sink.NewAWSSecretsManagerSink(ctx, httpClient, flags.aws)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, awsSink)
+	}
+	if flags.gcp.Project != "" {
+		httpClient, err := sink.NewHTTPClient("")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create HTTP client of GCP Secret Manager")
+		}
+		gcpSink, err := sink.NewGCPSecretManagerSink(ctx, httpClient, flags.gcp)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, gcpSink)
+	}
+	if flags.azure.VaultURL != "" {
+		httpClient, err := sink.NewHTTPClient("")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create HTTP client of Azure Key Vault")
+		}
+		azureSink, err := sink.NewAzureKeyVaultSink(httpClient, flags.azure)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, azureSink)
+	}
+	if flags.externalSecrets.StoreName != "" {
+		externalSecretsSink, err := sink.NewExternalSecretsSink(kcpClient, skrClientCache, flags.externalSecrets)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, externalSecretsSink)
+	}
+	if flags.enableSealedSecrets {
+		sealedSecretsSink, err := sink.NewSealedSecretsSink(skrClientCache, flags.sealedSecrets)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sealedSecretsSink)
+	}
+	if flags.credentialStore.BindingPath != "" {
+		httpClient, err := sink.NewHTTPClient("")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create HTTP client of the SAP Credential Store")
+		}
+		credentialStoreSink, err := sink.NewCredentialStoreSink(httpClient, flags.credentialStore)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, credentialStoreSink)
+	}
+	if flags.enableDestination {
+		httpClient, err := sink.NewHTTPClient("")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create HTTP client of the SAP Destination Service")
+		}
+		destinationSink, err := sink.NewDestinationSink(httpClient, kcpClient, flags.destination)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, destinationSink)
+	}
+	return sinks, nil
+}
+
+// auditFlags contains the configurations of the audit sinks that are set by flags. An audit sink is enabled if its required configuration
+// is set.
+type auditFlags struct {
+	file               string
+	endpoint           string
+	endpointHeaders    string
+	sapAuditLogBinding string
+	actor              string
+}
+
+// newAuditRecorder returns the recorder of the identity operations with the audit sinks that are enabled by the given flags. It returns nil
+// if no audit sink is enabled.
+func newAuditRecorder(flags auditFlags, logger logr.Logger) (*audit.Recorder, error) {
+	var sinks []audit.Sink
+	if flags.file != "" {
+		fileSink, err := audit.NewFileSink(flags.file)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, fileSink)
+	}
+	if flags.endpoint != "" {
+		headers, err := otlpmetrics.ParseHeaders(flags.endpointHeaders)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse --audit-log-endpoint-headers")
+		}
+		httpClient, err := sink.NewHTTPClient("")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create HTTP client of the audit log endpoint")
+		}
+		httpSink, err := audit.NewHTTPSink(httpClient, flags.endpoint, headers)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, httpSink)
+	}
+	if flags.sapAuditLogBinding != "" {
+		httpClient, err := sink.NewHTTPClient("")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create HTTP client of the SAP Audit Log service")
+		}
+		auditLogSink, err := audit.NewAuditLogSink(httpClient, flags.sapAuditLogBinding)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, auditLogSink)
+	}
+	if len(sinks) == 0 {
+		return nil, nil //nolint:nilnil // The identity operations aren't recorded without audit sinks.
+	}
+	return audit.NewRecorder(flags.actor, sinks, logger), nil
+}
+
+// Defaults of the leader election, which are the defaults of controller-runtime.
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+)
+
+// leaderElectionFlags contains the flags of the timing of the leader election.
+type leaderElectionFlags struct {
+	leaseDuration   time.Duration
+	renewDeadline   time.Duration
+	retryPeriod     time.Duration
+	releaseOnCancel bool
+}
+
+// validate checks the constraints of the leader election of client-go, so that an invalid timing fails before the manager is started.
+func (f leaderElectionFlags) validate() error {
+	if f.retryPeriod <= 0 {
+		return errors.New("--leader-elect-retry-period must be positive")
+	}
+	if f.leaseDuration <= f.renewDeadline {
+		return errors.New("--leader-elect-lease-duration must be greater than --leader-elect-renew-deadline")
+	}
+	if float64(f.renewDeadline) <= leaderelection.JitterFactor*float64(f.retryPeriod) {
+		return errors.Errorf("--leader-elect-renew-deadline must be greater than %.1f times --leader-elect-retry-period",
+			leaderelection.JitterFactor)
+	}
+	return nil
+}
+
+// skrSecretFlags contains the flags of the application secret that need to be parsed.
+type skrSecretFlags struct {
+	namespaces        string
+	namespaceSelector string
+	conflictPolicy    string
+	keyMapping        string
+	// serviceBindingType enables the entries of the Service Binding Specification if set.
+	serviceBindingType     string
+	serviceBindingProvider string
+}
+
+// newSkrClientOptions completes the given options of the SKR clients with the options that need to be parsed.
+func newSkrClientOptions(opts skr.ClientOptions, secretFlags skrSecretFlags) (skr.ClientOptions, error) {
+	if opts.QPS <= 0 || opts.Burst <= 0 {
+		return skr.ClientOptions{}, errors.New("QPS and burst of the SKR clients must be greater than zero")
+	}
+	opts.SecretConflictPolicy = skr.SecretConflictPolicy(secretFlags.conflictPolicy)
+	if opts.SecretConflictPolicy != skr.SecretConflictPolicyAdopt && opts.SecretConflictPolicy != skr.SecretConflictPolicyFail {
+		return skr.ClientOptions{}, errors.Errorf("unsupported conflict policy of the application secret: %s", secretFlags.conflictPolicy)
+	}
+	opts.SecretNamespaces = nil
+	for _, namespace := range strings.Split(secretFlags.namespaces, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			opts.SecretNamespaces = append(opts.SecretNamespaces, namespace)
+		}
+	}
+	if len(opts.SecretNamespaces) == 0 {
+		return skr.ClientOptions{}, errors.New("at least one namespace of the application secret must be configured")
+	}
+	if secretFlags.namespaceSelector != "" {
+		selector, err := labels.Parse(secretFlags.namespaceSelector)
+		if err != nil {
+			return skr.ClientOptions{}, errors.Wrap(err, "invalid namespace selector of the application secret")
+		}
+		opts.SecretNamespaceSelector = selector
+	}
+	keyMapping, err := skr.ParseSecretKeyMapping(secretFlags.keyMapping)
+	if err != nil {
+		return skr.ClientOptions{}, errors.Wrap(err, "invalid key mapping of the application secret")
+	}
+	opts.SecretFormat.KeyMapping = keyMapping
+	if secretFlags.serviceBindingType != "" {
+		opts.SecretFormat.ServiceBinding = &skr.ServiceBinding{Type: secretFlags.serviceBindingType, Provider: secretFlags.serviceBindingProvider}
+	}
+	if err := opts.SecretFormat.Validate(); err != nil {
+		return skr.ClientOptions{}, errors.Wrap(err, "invalid format of the application secret")
+	}
+	return opts, nil
+}
+
 func initScheme() *runtime.Scheme {
 	scheme := runtime.NewScheme()
 	kutilruntime.Must(kscheme.AddToScheme(scheme))
@@ -121,3 +1358,53 @@ func initScheme() *runtime.Scheme {
 	kutilruntime.Must(eamapiv1alpha1.AddToScheme(scheme))
 	return scheme
 }
+
+// setupCRDs verifies that the API server serves the resources of the CRDs that the manager watches, so that the manager fails with a
+// clear message instead of timing out on the sync of its caches. The embedded CRDs are installed first if requested.
+func setupCRDs(restConfig *rest.Config, install, enableKymaController, enableIASApplicationController bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), crdSetupTimeout)
+	defer cancel()
+	if install {
+		c, err := kpkgclient.New(restConfig, kpkgclient.Options{})
+		if err != nil {
+			return errors.Wrap(err, "failed to create the client to install the CRDs")
+		}
+		if err := crds.Install(ctx, c, crd.Bases); err != nil {
+			return err
+		}
+	}
+
+	eamGVK, err := apiutil.GVKForObject(&eamapiv1alpha1.EventingAuth{}, initScheme())
+	if err != nil {
+		return err
+	}
+	eamHint := "install the CRDs of config/crd or start the manager with '--install-crds'"
+	requirements := []crds.Requirement{
+		{GroupVersion: eamGVK.GroupVersion(), Resource: "eventingauths", Hint: eamHint},
+		{GroupVersion: eamGVK.GroupVersion(), Resource: "iastenants", Hint: eamHint},
+	}
+	if enableIASApplicationController {
+		requirements = append(requirements, crds.Requirement{GroupVersion: eamGVK.GroupVersion(), Resource: "iasapplications", Hint: eamHint})
+	}
+	if enableKymaController {
+		requirements = append(requirements, crds.Requirement{
+			GroupVersion: klmapiv1beta1.GroupVersion, Resource: "kymas",
+			Hint: "the CRD is installed by lifecycle-manager, or start the manager with '--enable-kyma-controller=false'",
+		})
+	}
+	d, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the discovery client")
+	}
+	// Resources that were just installed can take a moment until they are served.
+	for {
+		err = crds.Verify(d, requirements...)
+		if err == nil || !install || ctx.Err() != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"testing"
+	"time"
 
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
 	"github.com/stretchr/testify/require"
 )
 
@@ -10,3 +12,98 @@ func Test_initScheme(t *testing.T) {
 	scheme := initScheme()
 	require.NotNil(t, scheme)
 }
+
+func Test_newGardenerKubeconfigProvider(t *testing.T) {
+	_, err := newGardenerKubeconfigProvider("", "garden-kyma", skr.DefaultGardenerShootNameTemplate, skr.DefaultGardenerAdminKubeconfigTTL)
+	require.EqualError(t, err, "path to the kubeconfig of the Gardener cluster must not be empty")
+
+	_, err = newGardenerKubeconfigProvider("/some/kubeconfig", "", skr.DefaultGardenerShootNameTemplate, skr.DefaultGardenerAdminKubeconfigTTL)
+	require.Error(t, err)
+}
+
+func Test_newSkrClientOptions(t *testing.T) {
+	opts, err := newSkrClientOptions(skr.DefaultClientOptions(), skrSecretFlags{
+		namespaces:        "kyma-system, default,",
+		namespaceSelector: "eventing=enabled",
+		conflictPolicy:    "fail",
+		keyMapping:        "client_id=clientid",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"kyma-system", "default"}, opts.SecretNamespaces)
+	require.Equal(t, "eventing=enabled", opts.SecretNamespaceSelector.String())
+	require.Equal(t, skr.SecretConflictPolicyFail, opts.SecretConflictPolicy)
+	require.Equal(t, map[string]string{"client_id": "clientid"}, opts.SecretFormat.KeyMapping)
+
+	_, err = newSkrClientOptions(skr.DefaultClientOptions(), skrSecretFlags{namespaces: " , ", conflictPolicy: "adopt"})
+	require.Error(t, err)
+
+	_, err = newSkrClientOptions(skr.DefaultClientOptions(), skrSecretFlags{namespaces: "kyma-system", namespaceSelector: "invalid selector!", conflictPolicy: "adopt"})
+	require.Error(t, err)
+
+	_, err = newSkrClientOptions(skr.DefaultClientOptions(), skrSecretFlags{namespaces: "kyma-system", conflictPolicy: "overwrite"})
+	require.Error(t, err)
+
+	_, err = newSkrClientOptions(skr.DefaultClientOptions(), skrSecretFlags{namespaces: "kyma-system", conflictPolicy: "adopt", keyMapping: "unknown=key"})
+	require.Error(t, err)
+
+	opts, err = newSkrClientOptions(skr.DefaultClientOptions(), skrSecretFlags{
+		namespaces:             "kyma-system",
+		conflictPolicy:         "adopt",
+		serviceBindingType:     "oauth2",
+		serviceBindingProvider: "sap",
+	})
+	require.NoError(t, err)
+	require.Equal(t, &skr.ServiceBinding{Type: "oauth2", Provider: "sap"}, opts.SecretFormat.ServiceBinding)
+
+	_, err = newSkrClientOptions(skr.DefaultClientOptions(), skrSecretFlags{
+		namespaces:         "kyma-system",
+		conflictPolicy:     "adopt",
+		keyMapping:         "client_id=type",
+		serviceBindingType: "oauth2",
+	})
+	require.Error(t, err)
+
+	_, err = newSkrClientOptions(skr.ClientOptions{QPS: 0, Burst: skr.DefaultClientBurst}, skrSecretFlags{namespaces: "kyma-system", conflictPolicy: "adopt"})
+	require.Error(t, err)
+}
+
+func Test_leaderElectionFlags_validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenFlags leaderElectionFlags
+		wantErr    bool
+	}{
+		{
+			name:       "should accept the defaults",
+			givenFlags: leaderElectionFlags{leaseDuration: defaultLeaseDuration, renewDeadline: defaultRenewDeadline, retryPeriod: defaultRetryPeriod},
+		},
+		{
+			name:       "should fail if the lease duration isn't greater than the renew deadline",
+			givenFlags: leaderElectionFlags{leaseDuration: 10 * time.Second, renewDeadline: 10 * time.Second, retryPeriod: defaultRetryPeriod},
+			wantErr:    true,
+		},
+		{
+			name:       "should fail if the renew deadline isn't greater than the jittered retry period",
+			givenFlags: leaderElectionFlags{leaseDuration: defaultLeaseDuration, renewDeadline: 2 * time.Second, retryPeriod: 2 * time.Second},
+			wantErr:    true,
+		},
+		{
+			name:       "should fail if the retry period isn't positive",
+			givenFlags: leaderElectionFlags{leaseDuration: defaultLeaseDuration, renewDeadline: defaultRenewDeadline},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			err := tt.givenFlags.validate()
+
+			// then
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamcontrollers "github.com/kyma-project/eventing-auth-manager/controllers"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rotateCommand is the subcommand that requests the rotation of the credentials of managed applications, e.g. after a credential leak.
+const rotateCommand = "rotate"
+
+// defaultRotateTimeout is the default timeout of the requests of the rotations.
+const defaultRotateTimeout = 30 * time.Second
+
+// rotateFlags are the flags of the rotate subcommand.
+type rotateFlags struct {
+	kyma       string
+	all        bool
+	kubeconfig string
+	timeout    time.Duration
+}
+
+// runRotate runs the rotate subcommand with the arguments after the name of the subcommand. It annotates the EventingAuth CRs of the
+// selected applications, whose credentials are then rotated by the manager. It returns the exit code of the command, which is 0 if all
+// EventingAuth CRs were annotated.
+func runRotate(args []string, stdout, stderr io.Writer) int {
+	var flags rotateFlags
+	fs := flag.NewFlagSet(rotateCommand, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&flags.kyma, "kyma", "", "The name of the Kyma CR, i.e. the runtime ID, whose application credentials are rotated.")
+	fs.BoolVar(&flags.all, "all", false, "Rotate the credentials of all managed applications.")
+	fs.StringVar(&flags.kubeconfig, "kubeconfig", "",
+		"The path to the kubeconfig of the cluster of the EventingAuth CRs. Defaults to the kubeconfig of the environment or the in-cluster config.")
+	fs.DurationVar(&flags.timeout, "timeout", defaultRotateTimeout, "The timeout of the requests of the rotations.")
+	if err := fs.Parse(args); err != nil {
+		return exitCodeUsage
+	}
+	if (flags.kyma == "") == !flags.all {
+		fmt.Fprintln(stderr, "exactly one of --kyma and --all must be set")
+		return exitCodeUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flags.timeout)
+	defer cancel()
+	c, err := newCommandClient(flags.kubeconfig)
+	if err != nil {
+		fmt.Fprintf(stdout, "FAIL: %s\n", redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	if err := requestRotation(ctx, c, flags.kyma, time.Now(), stdout); err != nil {
+		fmt.Fprintf(stdout, "FAIL: %s\n", redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	return 0
+}
+
+// requestRotation annotates the EventingAuth CRs with the name of the Kyma CR, or all EventingAuth CRs if the name is empty, with the time of
+// the request, and prints the result for each of them. It fails if an EventingAuth CR couldn't be annotated or no EventingAuth CR matched.
+func requestRotation(ctx context.Context, c kpkgclient.Client, kyma string, now time.Time, out io.Writer) error {
+	var eventingAuths eamapiv1alpha1.EventingAuthList
+	if err := c.List(ctx, &eventingAuths); err != nil {
+		return errors.Wrap(err, "failed to list the EventingAuth CRs")
+	}
+	requested := now.UTC().Format(time.RFC3339)
+	var matched, failed int
+	for i := range eventingAuths.Items {
+		eventingAuth := &eventingAuths.Items[i]
+		if kyma != "" && eventingAuth.Name != kyma {
+			continue
+		}
+		matched++
+		fmt.Fprintf(out, "EventingAuth %s/%s: ", eventingAuth.Namespace, eventingAuth.Name)
+		patch := kpkgclient.MergeFrom(eventingAuth.DeepCopy())
+		if eventingAuth.Annotations == nil {
+			eventingAuth.Annotations = map[string]string{}
+		}
+		eventingAuth.Annotations[eamcontrollers.RotateCredentialsAnnotation] = requested
+		if err := c.Patch(ctx, eventingAuth, patch); err != nil {
+			fmt.Fprintf(out, "FAIL: %s\n", redact.String(err.Error()))
+			failed++
+			continue
+		}
+		fmt.Fprintln(out, "rotation requested")
+	}
+	if matched == 0 {
+		if kyma != "" {
+			return errors.Errorf("no EventingAuth CR of Kyma CR %s found", kyma)
+		}
+		return errors.New("no EventingAuth CR found")
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to request the rotation of %d of %d EventingAuth CRs", failed, matched)
+	}
+	return nil
+}
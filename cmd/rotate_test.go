@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamcontrollers "github.com/kyma-project/eventing-auth-manager/controllers"
+	"github.com/stretchr/testify/require"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_requestRotation(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		givenKyma   string
+		wantErr     string
+		wantRotated []string
+		wantReport  string
+	}{
+		{
+			name:        "should annotate the EventingAuth CR of the Kyma CR",
+			givenKyma:   "runtime-b",
+			wantRotated: []string{"runtime-b"},
+			wantReport:  "EventingAuth kcp-system/runtime-b: rotation requested\n",
+		},
+		{
+			name:        "should annotate all EventingAuth CRs",
+			wantRotated: []string{"runtime-a", "runtime-b"},
+			wantReport: "EventingAuth kcp-system/runtime-a: rotation requested\n" +
+				"EventingAuth kcp-system/runtime-b: rotation requested\n",
+		},
+		{
+			name:      "should fail if no EventingAuth CR of the Kyma CR exists",
+			givenKyma: "runtime-c",
+			wantErr:   "no EventingAuth CR of Kyma CR runtime-c found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			c := fake.NewClientBuilder().WithScheme(initScheme()).WithObjects(
+				&eamapiv1alpha1.EventingAuth{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-a"}},
+				&eamapiv1alpha1.EventingAuth{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-b",
+					Annotations: map[string]string{"owner": "kcp"}}},
+			).Build()
+			var out bytes.Buffer
+
+			// when
+			err := requestRotation(context.Background(), c, tt.givenKyma, now, &out)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantReport, out.String())
+			var rotated []string
+			for _, name := range []string{"runtime-a", "runtime-b"} {
+				var eventingAuth eamapiv1alpha1.EventingAuth
+				require.NoError(t, c.Get(context.Background(), kpkgclient.ObjectKey{Namespace: "kcp-system", Name: name}, &eventingAuth))
+				if requested, ok := eventingAuth.Annotations[eamcontrollers.RotateCredentialsAnnotation]; ok {
+					require.Equal(t, "2024-03-01T12:00:00Z", requested)
+					rotated = append(rotated, name)
+				}
+				if name == "runtime-b" {
+					require.Equal(t, "kcp", eventingAuth.Annotations["owner"])
+				}
+			}
+			require.Equal(t, tt.wantRotated, rotated)
+		})
+	}
+}
+
+func Test_runRotate_InvalidUsage(t *testing.T) {
+	tests := []struct {
+		name      string
+		givenArgs []string
+	}{
+		{
+			name: "should fail without selection",
+		},
+		{
+			name:      "should fail with both selections",
+			givenArgs: []string{"--kyma", "runtime-a", "--all"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var stdout, stderr bytes.Buffer
+
+			// when
+			exitCode := runRotate(tt.givenArgs, &stdout, &stderr)
+
+			// then
+			require.Equal(t, exitCodeUsage, exitCode)
+			require.Equal(t, "exactly one of --kyma and --all must be set\n", stderr.String())
+		})
+	}
+}
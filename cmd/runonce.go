@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamcontrollers "github.com/kyma-project/eventing-auth-manager/controllers"
+	klmapiv1beta1 "github.com/kyma-project/lifecycle-manager/api/v1beta1"
+	"github.com/pkg/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// The scopes of the pass of '--run-once'.
+const (
+	// runOnceScopeAll reconciles all IASTenant, Kyma and EventingAuth CRs.
+	runOnceScopeAll = "all"
+	// runOnceScopeMigrations only reconciles the EventingAuth CRs that are annotated for a migration or a rotation of their credentials.
+	runOnceScopeMigrations = "migrations"
+)
+
+// runOnceStep reconciles the objects of a list with the reconciler of a controller.
+type runOnceStep struct {
+	controller string
+	list       kpkgclient.ObjectList
+	// selected returns whether an object of the list is reconciled. All objects are reconciled if it is nil.
+	selected   func(kpkgclient.Object) bool
+	reconciler reconcile.Reconciler
+}
+
+// runOncePass is a runnable that reconciles the objects of its steps once, in the order of the steps, instead of starting the controllers,
+// and stops the manager afterwards. The objects of a step are listed when the step starts, so that the objects created by a previous step are
+// reconciled as well.
+type runOncePass struct {
+	reader kpkgclient.Reader
+	steps  []runOnceStep
+	// stop stops the manager when the pass finished.
+	stop context.CancelFunc
+
+	mu       sync.Mutex
+	finished bool
+	failed   int
+	total    int
+}
+
+// newRunOncePass returns the pass of the scope with the reconcilers of the manager. The Kyma CRs are only reconciled if the Kyma reconciler
+// is set, i.e. the Kyma controller is enabled.
+func newRunOncePass(scope string, reader kpkgclient.Reader, iasTenants, kymas, eventingAuths eamcontrollers.ManagedReconciler,
+	stop context.CancelFunc,
+) (*runOncePass, error) {
+	pass := &runOncePass{reader: reader, stop: stop}
+	switch scope {
+	case runOnceScopeAll:
+		pass.steps = append(pass.steps, runOnceStep{
+			controller: "iastenant", list: &eamapiv1alpha1.IASTenantList{}, reconciler: iasTenants.InstrumentedReconciler(),
+		})
+		if kymas != nil {
+			// The Kyma CRs are only read as metadata, like by the Kyma controller.
+			list := &kmetav1.PartialObjectMetadataList{}
+			list.SetGroupVersionKind(klmapiv1beta1.GroupVersion.WithKind("KymaList"))
+			pass.steps = append(pass.steps, runOnceStep{controller: "kyma", list: list, reconciler: kymas.InstrumentedReconciler()})
+		}
+		pass.steps = append(pass.steps, runOnceStep{
+			controller: "eventingauth", list: &eamapiv1alpha1.EventingAuthList{}, reconciler: eventingAuths.InstrumentedReconciler(),
+		})
+	case runOnceScopeMigrations:
+		pass.steps = append(pass.steps, runOnceStep{
+			controller: "eventingauth",
+			list:       &eamapiv1alpha1.EventingAuthList{},
+			selected: func(obj kpkgclient.Object) bool {
+				_, migrate := obj.GetAnnotations()[eamcontrollers.MigrateToIASTenantAnnotation]
+				_, rotate := obj.GetAnnotations()[eamcontrollers.RotateCredentialsAnnotation]
+				return migrate || rotate
+			},
+			reconciler: eventingAuths.InstrumentedReconciler(),
+		})
+	default:
+		return nil, errors.Errorf("unsupported scope %s, must be '%s' or '%s'", scope, runOnceScopeAll, runOnceScopeMigrations)
+	}
+	return pass, nil
+}
+
+// NeedLeaderElection returns true, so that the pass doesn't reconcile the objects concurrently to the controllers of a running leader.
+func (p *runOncePass) NeedLeaderElection() bool {
+	return true
+}
+
+// Start reconciles the objects of the steps and stops the manager. A failed reconciliation doesn't stop the pass, it is counted in the result
+// instead. A reconciliation that requests a requeue isn't repeated, the next pass or the controllers reconcile the object again.
+func (p *runOncePass) Start(ctx context.Context) error {
+	defer p.stop()
+	logger := log.FromContext(ctx).WithName("run-once")
+	for _, step := range p.steps {
+		if err := p.reader.List(ctx, step.list); err != nil {
+			return errors.Wrapf(err, "failed to list the objects of the %s controller", step.controller)
+		}
+		objects, err := kmeta.ExtractList(step.list)
+		if err != nil {
+			return err
+		}
+		for _, object := range objects {
+			obj, ok := object.(kpkgclient.Object)
+			if !ok || (step.selected != nil && !step.selected(obj)) {
+				continue
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			request := reconcile.Request{NamespacedName: kpkgclient.ObjectKeyFromObject(obj)}
+			_, err := step.reconciler.Reconcile(ctx, request)
+			p.count(err)
+			if err != nil {
+				logger.Error(err, "Reconciliation failed", "controller", step.controller, "object", request.NamespacedName)
+			}
+		}
+	}
+	p.mu.Lock()
+	p.finished = true
+	p.mu.Unlock()
+	logger.Info("Finished reconciliation pass", "reconciled", p.total, "failed", p.failed)
+	return nil
+}
+
+// count counts the result of a reconciliation.
+func (p *runOncePass) count(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total++
+	if err != nil {
+		p.failed++
+	}
+}
+
+// Result returns an error if the pass didn't finish, e.g. because the manager was stopped before, or if a reconciliation failed.
+func (p *runOncePass) Result() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.finished {
+		return errors.New("reconciliation pass didn't finish")
+	}
+	if p.failed > 0 {
+		return errors.Errorf("%d of %d reconciliations failed", p.failed, p.total)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamcontrollers "github.com/kyma-project/eventing-auth-manager/controllers"
+	klmapiv1beta1 "github.com/kyma-project/lifecycle-manager/api/v1beta1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type runOnceReconcilerStub struct {
+	controller string
+	fail       string
+	reconciled *[]string
+}
+
+func (r runOnceReconcilerStub) SetupWithManager(kcontrollerruntime.Manager) error {
+	return nil
+}
+
+func (r runOnceReconcilerStub) InstrumentedReconciler() reconcile.Reconciler {
+	return r
+}
+
+func (r runOnceReconcilerStub) Reconcile(_ context.Context, req kcontrollerruntime.Request) (kcontrollerruntime.Result, error) {
+	*r.reconciled = append(*r.reconciled, r.controller+" "+req.String())
+	if req.Name == r.fail {
+		return kcontrollerruntime.Result{}, errors.New("reconciliation failed")
+	}
+	return kcontrollerruntime.Result{Requeue: true}, nil
+}
+
+func Test_runOncePass(t *testing.T) {
+	tests := []struct {
+		name            string
+		givenScope      string
+		givenKyma       bool
+		givenFail       string
+		wantReconciled  []string
+		wantResultError string
+	}{
+		{
+			name:       "should reconcile all CRs in the order of the controllers",
+			givenScope: runOnceScopeAll,
+			givenKyma:  true,
+			wantReconciled: []string{
+				"iastenant /tenant-a",
+				"kyma kcp-system/runtime-a",
+				"eventingauth kcp-system/runtime-a",
+				"eventingauth kcp-system/runtime-b",
+			},
+		},
+		{
+			name:       "should not reconcile the Kyma CRs if the Kyma controller is disabled",
+			givenScope: runOnceScopeAll,
+			wantReconciled: []string{
+				"iastenant /tenant-a",
+				"eventingauth kcp-system/runtime-a",
+				"eventingauth kcp-system/runtime-b",
+			},
+		},
+		{
+			name:           "should only reconcile the annotated EventingAuth CRs for migrations",
+			givenScope:     runOnceScopeMigrations,
+			givenKyma:      true,
+			wantReconciled: []string{"eventingauth kcp-system/runtime-b"},
+		},
+		{
+			name:       "should continue after a failed reconciliation",
+			givenScope: runOnceScopeAll,
+			givenFail:  "runtime-a",
+			wantReconciled: []string{
+				"iastenant /tenant-a",
+				"eventingauth kcp-system/runtime-a",
+				"eventingauth kcp-system/runtime-b",
+			},
+			wantResultError: "1 of 3 reconciliations failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			c := fake.NewClientBuilder().WithScheme(initScheme()).WithObjects(
+				&eamapiv1alpha1.IASTenant{ObjectMeta: kmetav1.ObjectMeta{Name: "tenant-a"}},
+				&klmapiv1beta1.Kyma{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-a"}},
+				&eamapiv1alpha1.EventingAuth{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-a"}},
+				&eamapiv1alpha1.EventingAuth{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-b",
+					Annotations: map[string]string{eamcontrollers.RotateCredentialsAnnotation: "2024-03-01T12:00:00Z"}}},
+			).Build()
+			var reconciled []string
+			var kymas eamcontrollers.ManagedReconciler
+			if tt.givenKyma {
+				kymas = runOnceReconcilerStub{controller: "kyma", reconciled: &reconciled}
+			}
+			ctx, stop := context.WithCancel(context.Background())
+			pass, err := newRunOncePass(tt.givenScope, c,
+				runOnceReconcilerStub{controller: "iastenant", reconciled: &reconciled},
+				kymas,
+				runOnceReconcilerStub{controller: "eventingauth", fail: tt.givenFail, reconciled: &reconciled},
+				stop)
+			require.NoError(t, err)
+
+			// when
+			err = pass.Start(ctx)
+
+			// then
+			require.NoError(t, err)
+			require.Error(t, ctx.Err(), "the manager must be stopped after the pass")
+			require.Equal(t, tt.wantReconciled, reconciled)
+			if tt.wantResultError != "" {
+				require.EqualError(t, pass.Result(), tt.wantResultError)
+			} else {
+				require.NoError(t, pass.Result())
+			}
+		})
+	}
+}
+
+func Test_runOncePass_Stopped(t *testing.T) {
+	// given
+	c := fake.NewClientBuilder().WithScheme(initScheme()).WithObjects(
+		&eamapiv1alpha1.EventingAuth{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-a"}},
+	).Build()
+	var reconciled []string
+	stub := runOnceReconcilerStub{reconciled: &reconciled}
+	ctx, stop := context.WithCancel(context.Background())
+	pass, err := newRunOncePass(runOnceScopeAll, c, stub, nil, stub, stop)
+	require.NoError(t, err)
+	stop()
+
+	// when
+	err = pass.Start(ctx)
+
+	// then
+	require.NoError(t, err)
+	require.Empty(t, reconciled)
+	require.EqualError(t, pass.Result(), "reconciliation pass didn't finish")
+}
+
+func Test_newRunOncePass_InvalidScope(t *testing.T) {
+	// when
+	_, err := newRunOncePass("kymas", nil, nil, nil, nil, func() {})
+
+	// then
+	require.EqualError(t, err, "unsupported scope kymas, must be 'all' or 'migrations'")
+}
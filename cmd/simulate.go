@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	klmapiv1beta1 "github.com/kyma-project/lifecycle-manager/api/v1beta1"
+	"github.com/pkg/errors"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// simulateCommand is the subcommand that creates synthetic CRs and measures how fast the manager reconciles them, e.g. to validate the
+// scalability targets before a rollout to a landscape.
+const simulateCommand = "simulate"
+
+// The resources that the simulate subcommand creates.
+const (
+	simulateResourceKyma         = "kyma"
+	simulateResourceEventingAuth = "eventingauth"
+)
+
+// simulationLabel labels the CRs that the simulate subcommand creates with the ID of the simulation.
+const simulationLabel = "eventingauth.kyma-project.io/simulation"
+
+// Defaults of the simulate subcommand.
+const (
+	defaultSimulateCount        = 10
+	defaultSimulateNamespace    = "kcp-system"
+	defaultSimulateTimeout      = 10 * time.Minute
+	defaultSimulatePollInterval = time.Second
+	simulateCleanupTimeout      = 30 * time.Second
+)
+
+// simulateFlags are the flags of the simulate subcommand.
+type simulateFlags struct {
+	count        int
+	namespace    string
+	resource     string
+	provider     string
+	kubeconfig   string
+	timeout      time.Duration
+	pollInterval time.Duration
+	keep         bool
+}
+
+// simulationResult contains the reconcile latencies of the CRs of a simulation.
+type simulationResult struct {
+	// created is the number of created CRs.
+	created int
+	// latencies are the durations from the creation of the CRs until their EventingAuth CRs were observed as ready, in ascending order.
+	latencies []time.Duration
+	// duration is the duration from the creation of the first CR until the last EventingAuth CR was observed as ready, or until the timeout.
+	duration time.Duration
+}
+
+// runSimulate runs the simulate subcommand with the arguments after the name of the subcommand. It creates synthetic Kyma or EventingAuth
+// CRs, waits until the manager reconciled their EventingAuth CRs, prints the throughput and the latencies, and deletes the CRs. It returns
+// the exit code of the command, which is 0 if all EventingAuth CRs became ready within the timeout.
+func runSimulate(args []string, stdout, stderr io.Writer) int {
+	var flags simulateFlags
+	fs := flag.NewFlagSet(simulateCommand, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.IntVar(&flags.count, "count", defaultSimulateCount, "The number of synthetic CRs that are created.")
+	fs.StringVar(&flags.namespace, "namespace", defaultSimulateNamespace, "The namespace of the synthetic CRs.")
+	fs.StringVar(&flags.resource, "resource", simulateResourceKyma,
+		"The synthetic CRs, either 'kyma' for Kyma CRs, whose EventingAuth CRs are created by the manager, or 'eventingauth' for EventingAuth CRs.")
+	fs.StringVar(&flags.provider, "provider", provider.MemoryName,
+		"The identity provider of the synthetic EventingAuth CRs. Only used with '--resource=eventingauth', the EventingAuth CRs of Kyma CRs "+
+			"use the default provider of the manager.")
+	fs.StringVar(&flags.kubeconfig, "kubeconfig", "",
+		"The path to the kubeconfig of the cluster of the manager. Defaults to the kubeconfig of the environment or the in-cluster config.")
+	fs.DurationVar(&flags.timeout, "timeout", defaultSimulateTimeout, "The duration that the EventingAuth CRs are waited for to become ready.")
+	fs.DurationVar(&flags.pollInterval, "poll-interval", defaultSimulatePollInterval,
+		"The interval of the checks of the EventingAuth CRs, which is the resolution of the measured latencies.")
+	fs.BoolVar(&flags.keep, "keep", false, "Keep the synthetic CRs after the simulation instead of deleting them.")
+	if err := fs.Parse(args); err != nil {
+		return exitCodeUsage
+	}
+	if flags.count <= 0 {
+		fmt.Fprintln(stderr, "--count must be greater than zero")
+		return exitCodeUsage
+	}
+	if flags.resource != simulateResourceKyma && flags.resource != simulateResourceEventingAuth {
+		fmt.Fprintf(stderr, "--resource must be '%s' or '%s'\n", simulateResourceKyma, simulateResourceEventingAuth)
+		return exitCodeUsage
+	}
+	if flags.pollInterval <= 0 {
+		fmt.Fprintln(stderr, "--poll-interval must be greater than zero")
+		return exitCodeUsage
+	}
+
+	c, err := newCommandClient(flags.kubeconfig)
+	if err != nil {
+		fmt.Fprintf(stdout, "FAIL: %s\n", redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	id := fmt.Sprintf("sim-%d", time.Now().Unix())
+	objects := newSimulationObjects(id, flags)
+	ctx, cancel := context.WithTimeout(context.Background(), flags.timeout)
+	defer cancel()
+	result, simErr := simulate(ctx, c, objects, flags.pollInterval)
+	printSimulationResult(stdout, id, result, len(objects))
+	if !flags.keep {
+		// The CRs are also deleted after the timeout of the simulation.
+		cleanupCtx, cancelCleanup := context.WithTimeout(context.Background(), simulateCleanupTimeout)
+		defer cancelCleanup()
+		if err := deleteSimulationObjects(cleanupCtx, c, objects); err != nil {
+			fmt.Fprintf(stdout, "FAIL: %s\n", redact.String(err.Error()))
+			return exitCodeFailed
+		}
+	}
+	if simErr != nil {
+		fmt.Fprintf(stdout, "FAIL: %s\n", redact.String(simErr.Error()))
+		return exitCodeFailed
+	}
+	return 0
+}
+
+// newSimulationObjects returns the synthetic CRs of the simulation with the given ID, which are labeled with simulationLabel.
+func newSimulationObjects(id string, flags simulateFlags) []kpkgclient.Object {
+	objects := make([]kpkgclient.Object, 0, flags.count)
+	for i := 0; i < flags.count; i++ {
+		meta := kmetav1.ObjectMeta{
+			Namespace: flags.namespace,
+			Name:      fmt.Sprintf("%s-%d", id, i),
+			Labels:    map[string]string{simulationLabel: id},
+		}
+		if flags.resource == simulateResourceKyma {
+			objects = append(objects, &klmapiv1beta1.Kyma{ObjectMeta: meta, Spec: klmapiv1beta1.KymaSpec{Channel: "regular"}})
+		} else {
+			objects = append(objects, &eamapiv1alpha1.EventingAuth{ObjectMeta: meta, Spec: eamapiv1alpha1.EventingAuthSpec{Provider: flags.provider}})
+		}
+	}
+	return objects
+}
+
+// simulate creates the CRs and waits until the EventingAuth CRs of the same names are ready, or the context is done. It returns the latencies
+// of the EventingAuth CRs that became ready, and an error if not all of them became ready.
+func simulate(ctx context.Context, c kpkgclient.Client, objects []kpkgclient.Object, pollInterval time.Duration) (simulationResult, error) {
+	var result simulationResult
+	start := time.Now()
+	createdTimes := map[string]time.Time{}
+	for _, obj := range objects {
+		if err := c.Create(ctx, obj); err != nil {
+			result.duration = time.Since(start)
+			return result, errors.Wrapf(err, "failed to create %s", obj.GetName())
+		}
+		createdTimes[obj.GetName()] = time.Now()
+		result.created++
+	}
+	namespace := objects[0].GetNamespace()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		var eventingAuths eamapiv1alpha1.EventingAuthList
+		if err := c.List(ctx, &eventingAuths, kpkgclient.InNamespace(namespace)); err != nil && ctx.Err() == nil {
+			result.duration = time.Since(start)
+			return result, errors.Wrap(err, "failed to list the EventingAuth CRs")
+		}
+		now := time.Now()
+		for i := range eventingAuths.Items {
+			eventingAuth := &eventingAuths.Items[i]
+			created, ok := createdTimes[eventingAuth.Name]
+			if !ok || eventingAuth.Status.State != eamapiv1alpha1.StateReady {
+				continue
+			}
+			result.latencies = append(result.latencies, now.Sub(created))
+			delete(createdTimes, eventingAuth.Name)
+		}
+		if len(createdTimes) == 0 {
+			result.duration = time.Since(start)
+			sort.Slice(result.latencies, func(i, j int) bool { return result.latencies[i] < result.latencies[j] })
+			return result, nil
+		}
+		select {
+		case <-ctx.Done():
+			result.duration = time.Since(start)
+			sort.Slice(result.latencies, func(i, j int) bool { return result.latencies[i] < result.latencies[j] })
+			return result, errors.Errorf("%d of %d EventingAuth CRs didn't become ready", len(createdTimes), result.created)
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteSimulationObjects deletes the CRs of the simulation. CRs that were never created or are already deleted are ignored.
+func deleteSimulationObjects(ctx context.Context, c kpkgclient.Client, objects []kpkgclient.Object) error {
+	var failed int
+	for _, obj := range objects {
+		if err := c.Delete(ctx, obj); err != nil && !kapierrors.IsNotFound(err) {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to delete %d of %d synthetic CRs", failed, len(objects))
+	}
+	return nil
+}
+
+// printSimulationResult prints the throughput and the latency percentiles of the simulation.
+func printSimulationResult(out io.Writer, id string, result simulationResult, count int) {
+	ready := len(result.latencies)
+	fmt.Fprintf(out, "Simulation %s: %d of %d CRs created, %d ready in %s\n", id, result.created, count, ready,
+		result.duration.Round(time.Millisecond))
+	if ready == 0 {
+		return
+	}
+	fmt.Fprintf(out, "Throughput: %.2f reconciliations/s\n", float64(ready)/result.duration.Seconds())
+	fmt.Fprintf(out, "Latency: p50 %s, p90 %s, p99 %s, max %s\n", percentile(result.latencies, 50), percentile(result.latencies, 90),
+		percentile(result.latencies, 99), result.latencies[ready-1].Round(time.Millisecond))
+}
+
+// percentile returns the nearest-rank percentile of the ascending durations, rounded to milliseconds.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Millisecond)
+}
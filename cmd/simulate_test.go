@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_simulate(t *testing.T) {
+	tests := []struct {
+		name      string
+		state     eamapiv1alpha1.State
+		wantErr   string
+		wantReady int
+	}{
+		{
+			name:      "should measure the latencies of the ready EventingAuth CRs",
+			state:     eamapiv1alpha1.StateReady,
+			wantReady: 3,
+		},
+		{
+			name:    "should fail if the EventingAuth CRs don't become ready",
+			state:   eamapiv1alpha1.StateNotReady,
+			wantErr: "3 of 3 EventingAuth CRs didn't become ready",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			c := fake.NewClientBuilder().WithScheme(initScheme()).Build()
+			objects := newSimulationObjects("sim-1", simulateFlags{count: 3, namespace: "kcp-system", resource: simulateResourceEventingAuth,
+				provider: "memory"})
+			// The status isn't a subresource of the fake client, so the state of the created EventingAuth CRs is kept.
+			for _, obj := range objects {
+				obj.(*eamapiv1alpha1.EventingAuth).Status.State = tt.state
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			// when
+			result, err := simulate(ctx, c, objects, 10*time.Millisecond)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, 3, result.created)
+			require.Len(t, result.latencies, tt.wantReady)
+			var eventingAuth eamapiv1alpha1.EventingAuth
+			require.NoError(t, c.Get(context.Background(), kpkgclient.ObjectKey{Namespace: "kcp-system", Name: "sim-1-0"}, &eventingAuth))
+			require.Equal(t, "sim-1", eventingAuth.Labels[simulationLabel])
+			require.Equal(t, "memory", eventingAuth.Spec.Provider)
+
+			require.NoError(t, deleteSimulationObjects(context.Background(), c, objects))
+			err = c.Get(context.Background(), kpkgclient.ObjectKey{Namespace: "kcp-system", Name: "sim-1-0"}, &eventingAuth)
+			require.True(t, kapierrors.IsNotFound(err), "the synthetic CRs must be deleted")
+		})
+	}
+}
+
+func Test_printSimulationResult(t *testing.T) {
+	// given
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	var out bytes.Buffer
+
+	// when
+	printSimulationResult(&out, "sim-1", simulationResult{created: 100, latencies: latencies, duration: 2 * time.Second}, 100)
+
+	// then
+	require.Equal(t, "Simulation sim-1: 100 of 100 CRs created, 100 ready in 2s\n"+
+		"Throughput: 50.00 reconciliations/s\n"+
+		"Latency: p50 50ms, p90 90ms, p99 99ms, max 100ms\n", out.String())
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// validateCredentialsCommand is the subcommand that validates the credentials of an IAS tenant, instead of starting the manager.
+const validateCredentialsCommand = "validate-credentials"
+
+// The exit codes of the subcommands.
+const (
+	exitCodeFailed = 1
+	exitCodeUsage  = 2
+)
+
+// defaultValidateCredentialsTimeout is the default timeout of the validation of the credentials.
+const defaultValidateCredentialsTimeout = 30 * time.Second
+
+// validateCredentialsFlags are the flags of the validate-credentials subcommand.
+type validateCredentialsFlags struct {
+	secret     string
+	secretFile string
+	kubeconfig string
+	timeout    time.Duration
+}
+
+// runValidateCredentials runs the validate-credentials subcommand with the arguments after the name of the subcommand. It prints a
+// report of the checks of the IAS tenant and returns the exit code of the command, which is 0 if all checks passed.
+func runValidateCredentials(args []string, stdout, stderr io.Writer) int {
+	namespace, name := provider.IASCredentialsSecret()
+	var flags validateCredentialsFlags
+	fs := flag.NewFlagSet(validateCredentialsCommand, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&flags.secret, "secret", namespace+"/"+name,
+		"The namespace and name of the IAS credentials secret in the cluster, e.g. 'kcp-system/eventing-auth-ias-creds'.")
+	fs.StringVar(&flags.secretFile, "secret-file", "",
+		"The path to a manifest of the IAS credentials secret, which is validated instead of the secret in the cluster, e.g. before it is rolled out.")
+	fs.StringVar(&flags.kubeconfig, "kubeconfig", "",
+		"The path to the kubeconfig of the cluster of the secret. Defaults to the kubeconfig of the environment or the in-cluster config.")
+	fs.DurationVar(&flags.timeout, "timeout", defaultValidateCredentialsTimeout, "The timeout of the validation.")
+	if err := fs.Parse(args); err != nil {
+		return exitCodeUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), flags.timeout)
+	defer cancel()
+	credentials, source, err := readValidatedCredentials(ctx, flags)
+	if err != nil {
+		fmt.Fprintf(stdout, "Secret %s: FAIL: %s\n", source, redact.String(err.Error()))
+		return exitCodeFailed
+	}
+	if !validateCredentials(ctx, credentials, source, stdout) {
+		return exitCodeFailed
+	}
+	return 0
+}
+
+// readValidatedCredentials returns the IAS credentials of the secret of the flags, and the description of the secret in the report.
+func readValidatedCredentials(ctx context.Context, flags validateCredentialsFlags) (*eamias.Credentials, string, error) {
+	if flags.secretFile != "" {
+		content, err := os.ReadFile(flags.secretFile)
+		if err != nil {
+			return nil, flags.secretFile, err
+		}
+		var secret kcorev1.Secret
+		if err := yaml.Unmarshal(content, &secret); err != nil {
+			return nil, flags.secretFile, err
+		}
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		// Manifests that are written by hand usually contain the plain values of stringData, which the API server merges into data.
+		for key, value := range secret.StringData {
+			secret.Data[key] = []byte(value)
+		}
+		credentials, err := eamias.CredentialsFromSecret(&secret)
+		return credentials, flags.secretFile, err
+	}
+
+	namespace, name, ok := strings.Cut(flags.secret, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, flags.secret, errors.New("--secret must have the format '<namespace>/<name>'")
+	}
+	c, err := newCommandClient(flags.kubeconfig)
+	if err != nil {
+		return nil, flags.secret, err
+	}
+	var secret kcorev1.Secret
+	if err := c.Get(ctx, kpkgclient.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
+		return nil, flags.secret, err
+	}
+	credentials, err := eamias.CredentialsFromSecret(&secret)
+	return credentials, flags.secret, err
+}
+
+// validateCredentials checks that the IAS tenant of the credentials is reachable with them, and prints the result of each check. It
+// returns whether all checks passed.
+func validateCredentials(ctx context.Context, credentials *eamias.Credentials, source string, out io.Writer) bool {
+	fmt.Fprintf(out, "Secret %s: PASS\n", source)
+	fmt.Fprintf(out, "IAS tenant %s, user %s\n", credentials.URL, credentials.Username)
+	iasClient, err := eamias.NewClient(credentials.URL, credentials.Username, credentials.Password, eamias.ClientOptions{})
+	if err != nil {
+		fmt.Fprintf(out, "Result: FAIL: %s\n", redact.String(err.Error()))
+		return false
+	}
+
+	err = iasClient.Probe(ctx)
+	var probeErr *eamias.ProbeError
+	if err != nil && !errors.As(err, &probeErr) {
+		fmt.Fprintf(out, "Result: FAIL: %s\n", redact.String(err.Error()))
+		return false
+	}
+	failed := false
+	for _, endpoint := range []string{eamias.ProbeEndpointOIDCDiscovery, eamias.ProbeEndpointApplicationAPI} {
+		switch {
+		case failed:
+			fmt.Fprintf(out, "  %s: SKIPPED\n", endpoint)
+		case probeErr != nil && probeErr.Endpoint == endpoint:
+			fmt.Fprintf(out, "  %s: FAIL: %s\n", endpoint, redact.String(probeErr.Err.Error()))
+			failed = true
+		default:
+			fmt.Fprintf(out, "  %s: PASS\n", endpoint)
+		}
+	}
+	if failed {
+		fmt.Fprintln(out, "Result: FAIL")
+		return false
+	}
+	fmt.Fprintln(out, "Result: PASS")
+	return true
+}
+
+// newCommandClient returns an uncached client of the cluster of the kubeconfig for the subcommands. It defaults to the kubeconfig of the
+// environment or the in-cluster config if the path is empty.
+func newCommandClient(kubeconfig string) (kpkgclient.Client, error) {
+	restConfig, err := kcontrollerruntime.GetConfig()
+	if kubeconfig != "" {
+		restConfig, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return kpkgclient.New(restConfig, kpkgclient.Options{Scheme: initScheme()})
+}
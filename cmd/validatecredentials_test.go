@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIASTenant(t *testing.T, applicationsStatus int) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"token_endpoint":"%s/oauth2/token","jwks_uri":"%s/oauth2/certs"}`, server.URL, server.URL)
+		case "/Applications/v1/":
+			if username, password, _ := r.BasicAuth(); username != "user" || password != "valid-password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.WriteHeader(applicationsStatus)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_runValidateCredentials(t *testing.T) {
+	tests := []struct {
+		name                    string
+		givenSecret             func(tenantURL string) string
+		givenApplicationsStatus int
+		wantExitCode            int
+		wantReport              func(path, tenantURL string) string
+	}{
+		{
+			name: "should pass for valid credentials",
+			givenSecret: func(tenantURL string) string {
+				return "apiVersion: v1\nkind: Secret\nstringData:\n  url: " + tenantURL + "\n  username: user\n  password: valid-password\n"
+			},
+			givenApplicationsStatus: http.StatusNotFound,
+			wantExitCode:            0,
+			wantReport: func(path, tenantURL string) string {
+				return "Secret " + path + ": PASS\n" +
+					"IAS tenant " + tenantURL + ", user user\n" +
+					"  OIDC discovery: PASS\n" +
+					"  Applications API: PASS\n" +
+					"Result: PASS\n"
+			},
+		},
+		{
+			name: "should fail for rejected credentials",
+			givenSecret: func(tenantURL string) string {
+				return "apiVersion: v1\nkind: Secret\nstringData:\n  url: " + tenantURL + "\n  username: user\n  password: invalid-password\n"
+			},
+			givenApplicationsStatus: http.StatusOK,
+			wantExitCode:            exitCodeFailed,
+			wantReport: func(path, tenantURL string) string {
+				return "Secret " + path + ": PASS\n" +
+					"IAS tenant " + tenantURL + ", user user\n" +
+					"  OIDC discovery: PASS\n" +
+					"  Applications API: FAIL: unexpected status code 401\n" +
+					"Result: FAIL\n"
+			},
+		},
+		{
+			name: "should skip the Applications API if the discovery fails",
+			givenSecret: func(tenantURL string) string {
+				return "apiVersion: v1\nkind: Secret\nstringData:\n  url: " + tenantURL + "/unknown\n  username: user\n  password: valid-password\n"
+			},
+			wantExitCode: exitCodeFailed,
+			wantReport: func(path, tenantURL string) string {
+				return "Secret " + path + ": PASS\n" +
+					"IAS tenant " + tenantURL + "/unknown, user user\n" +
+					"  OIDC discovery: FAIL: unexpected status code 404\n" +
+					"  Applications API: SKIPPED\n" +
+					"Result: FAIL\n"
+			},
+		},
+		{
+			name: "should fail for a secret without credentials",
+			givenSecret: func(string) string {
+				return "apiVersion: v1\nkind: Secret\nstringData:\n  username: user\n  password: valid-password\n"
+			},
+			wantExitCode: exitCodeFailed,
+			wantReport: func(path, _ string) string {
+				return "Secret " + path + ": FAIL: key url is not found in ias secret\n"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			tenant := newTestIASTenant(t, tt.givenApplicationsStatus)
+			path := filepath.Join(t.TempDir(), "secret.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.givenSecret(tenant.URL)), 0o600))
+			var stdout, stderr bytes.Buffer
+
+			// when
+			exitCode := runValidateCredentials([]string{"--secret-file", path}, &stdout, &stderr)
+
+			// then
+			require.Equal(t, tt.wantExitCode, exitCode)
+			require.Equal(t, tt.wantReport(path, tenant.URL), stdout.String())
+			require.Empty(t, stderr.String())
+		})
+	}
+}
+
+func Test_runValidateCredentials_InvalidUsage(t *testing.T) {
+	tests := []struct {
+		name         string
+		givenArgs    []string
+		wantExitCode int
+		wantStdout   string
+	}{
+		{
+			name:         "should fail for unknown flags",
+			givenArgs:    []string{"--unknown"},
+			wantExitCode: exitCodeUsage,
+		},
+		{
+			name:         "should fail for a secret without namespace",
+			givenArgs:    []string{"--secret", "eventing-auth-ias-creds"},
+			wantExitCode: exitCodeFailed,
+			wantStdout:   "Secret eventing-auth-ias-creds: FAIL: --secret must have the format '<namespace>/<name>'\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var stdout, stderr bytes.Buffer
+
+			// when
+			exitCode := runValidateCredentials(tt.givenArgs, &stdout, &stderr)
+
+			// then
+			require.Equal(t, tt.wantExitCode, exitCode)
+			require.Equal(t, tt.wantStdout, stdout.String())
+		})
+	}
+}
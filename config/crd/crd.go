@@ -0,0 +1,9 @@
+// Package crd embeds the CustomResourceDefinitions of the manager, so that the manager can install them at startup.
+package crd
+
+import "embed"
+
+// Bases contains the CustomResourceDefinitions of the manager that are generated into the directory bases.
+//
+//go:embed bases/*.yaml
+var Bases embed.FS
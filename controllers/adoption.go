@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/audit"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/pkg/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// AdoptApplicationAnnotation is the annotation of an EventingAuth CR that triggers the adoption of the existing application with the ID of
+// the value, e.g. an application that was created manually or by an older tool, instead of the creation of a new application.
+const AdoptApplicationAnnotation = "eventingauth.operator.kyma-project.io/adopt-application"
+
+// adoptApplication takes over the existing application with the given ID as the application of the EventingAuth CR and removes the
+// annotation. An application that was already adopted isn't adopted again, so that its credentials aren't replaced on every reconciliation.
+func (r *eventingAuthReconciler) adoptApplication(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr eamapiv1alpha1.EventingAuth, id string,
+) error {
+	if sharesApplication(cr) {
+		return errors.Errorf("shared application %s can't be replaced by adopted application %s", applicationName(cr), id)
+	}
+	if providesCredentials(cr) {
+		return errors.Errorf("provided credentials can't be replaced by adopted application %s", id)
+	}
+	if applicationID(cr) != id {
+		logger.Info("Adopting application", "id", id, "previousID", applicationID(cr))
+		if err := r.takeOverApplication(ctx, p, &cr, id); err != nil {
+			return errors.Wrapf(err, "failed to adopt application %s", id)
+		}
+	}
+
+	latest := &eamapiv1alpha1.EventingAuth{}
+	if err := r.Client.Get(ctx, kpkgclient.ObjectKeyFromObject(&cr), latest); err != nil {
+		return err
+	}
+	if latest.Annotations[AdoptApplicationAnnotation] != id {
+		decisionOf(ctx).act("kept adoption annotation, because another application was requested meanwhile")
+		return nil
+	}
+	delete(latest.Annotations, AdoptApplicationAnnotation)
+	if err := r.Update(ctx, latest); err != nil {
+		return errors.Wrap(err, "failed to finish adoption of application")
+	}
+	return nil
+}
+
+// takeOverApplication adopts the application in the provider, replaces the delivered credentials with the new credentials of the adopted
+// application, and only then deletes the previous application of the EventingAuth CR, so that the delivered credentials stay valid until
+// they are replaced. The adopted application is recorded in the status last. Since the previous credentials of the adopted application are
+// already revoked, a failed attempt is retried with new credentials.
+func (r *eventingAuthReconciler) takeOverApplication(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.EventingAuth,
+	id string,
+) error {
+	tenant := owningIASTenant(*cr)
+	previousID := applicationID(*cr)
+	app, err := provider.Adopt(ctx, p, id, cr.Name)
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionAdoptApplication, Application: cr.Name, ApplicationID: id, Tenant: tenant}, err)
+	if err != nil {
+		return err
+	}
+	decisionOf(ctx).act("adopted application %s%s", id, inIASTenant(tenant))
+	r.events.warning(ctx, cr, eventReasonApplicationSecretsRevoked, "Adopting application %s revoked its previous API secrets, clients of "+
+		"the application must use the credentials that the manager delivers", id)
+	// The cached application was replaced by the adopted application.
+	r.applications.forgetCreatedApplication(cr.Name)
+
+	if err := r.replaceCredentials(ctx, cr, app, tenant); err != nil {
+		return err
+	}
+	if previousID != "" && previousID != id {
+		if err := r.deletePreviousApplication(ctx, p, cr, previousID, tenant); err != nil {
+			return err
+		}
+	}
+	now := kmetav1.Now()
+	cr.Status.Application = &eamapiv1alpha1.ClusterApplication{
+		Name:                  cr.Name,
+		UUID:                  app.GetID(),
+		Tenant:                tenant,
+		CredentialsIssuedTime: &now,
+	}
+	if err := r.updateEventingAuthStatus(ctx, cr, eamapiv1alpha1.ConditionApplicationReady, nil); err != nil {
+		return err
+	}
+	r.events.normal(ctx, cr, eventReasonApplicationAdopted, "Adopted application %s as application %s%s", id, cr.Name, inIASTenant(tenant))
+	return nil
+}
+
+// deletePreviousApplication deletes the previous application of the EventingAuth CR by its ID, since the adopted application has the same
+// name. A previous application that was renamed or unmarked meanwhile isn't managed anymore and is kept.
+func (r *eventingAuthReconciler) deletePreviousApplication(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.EventingAuth,
+	previousID, tenant string,
+) error {
+	err := provider.DeleteReplaced(ctx, p, previousID, cr.Name)
+	if eamias.IsApplicationChangedError(err) {
+		log.FromContext(ctx).Info("Kept replaced application, because it changed", "id", previousID)
+		decisionOf(ctx).act("kept replaced application %s, because it changed", previousID)
+		return nil
+	}
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteApplication, Application: cr.Name, ApplicationID: previousID,
+		Tenant: tenant}, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete replaced application %s", previousID)
+	}
+	decisionOf(ctx).act("deleted replaced application %s", previousID)
+	return nil
+}
@@ -0,0 +1,112 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/controllers"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	kcorev1 "k8s.io/api/core/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Since all tests use the same target cluster and therefore share the same application secret, they need to be executed serially.
+var _ = Describe("EventingAuth Controller adoption tests", Serial, Ordered, func() {
+	var (
+		iasClient    *recordingIasClientStub
+		eventingAuth *eamapiv1alpha1.EventingAuth
+		crName       string
+		adoptedID    string
+	)
+
+	BeforeEach(func() {
+		iasClient = &recordingIasClientStub{}
+		stubIasAppCreation(iasClient)
+		crName = generateCrName()
+		adoptedID = uuid.New().String()
+		createKubeconfigSecret(crName)
+
+		eventingAuth = createEventingAuth(crName)
+		verifyEventingAuthStatusReady(eventingAuth)
+		verifyApplicationSecretClientID("client-id-for-" + crName)
+	})
+
+	AfterEach(func() {
+		deleteEventingAuthAndVerify(eventingAuth)
+		deleteApplicationSecretOnTargetCluster()
+		deleteKubeconfigSecret(crName)
+		revertReadCredentialsStub()
+		revertIasNewClientStub()
+	})
+
+	It("should deliver the credentials of the adopted application before deleting the previous application", func() {
+		// given
+		var deliveredBeforeDeletion atomic.Bool
+		iasClient.setOnDelete(func(string) {
+			var secret kcorev1.Secret
+			err := targetClusterK8sClient.Get(context.TODO(), appSecretObjectKey, &secret)
+			deliveredBeforeDeletion.Store(err == nil && string(secret.Data[eamias.SecretKeyClientID]) == "client-id-for-"+adoptedID)
+		})
+
+		// when
+		annotateAdoptApplication(eventingAuth, adoptedID)
+
+		// then
+		By(fmt.Sprintf("Verifying that EventingAuth %s adopted application %s", eventingAuth.Name, adoptedID))
+		Eventually(func(g Gomega) {
+			e := eamapiv1alpha1.EventingAuth{}
+			g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(eventingAuth), &e)).Should(Succeed())
+			g.Expect(e.Status.State).To(Equal(eamapiv1alpha1.StateReady))
+			g.Expect(e.Status.Application).NotTo(BeNil())
+			g.Expect(e.Status.Application.UUID).To(Equal(adoptedID))
+			g.Expect(e.Annotations).NotTo(HaveKey(controllers.AdoptApplicationAnnotation))
+		}, defaultTimeout).Should(Succeed())
+		verifyApplicationSecretClientID("client-id-for-" + adoptedID)
+		Expect(iasClient.deletedApplicationIDs()).To(ConsistOf("id-for-" + crName))
+		Expect(deliveredBeforeDeletion.Load()).To(BeTrue())
+		verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeWarning, "ApplicationSecretsRevoked")
+		verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeNormal, "ApplicationAdopted")
+	})
+
+	It("should keep the previous application if the adoption fails", func() {
+		// given
+		iasClient.setAdoptionErr(errIASApplicationAdoption)
+
+		// when
+		annotateAdoptApplication(eventingAuth, adoptedID)
+
+		// then
+		verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeWarning, "ReconcileFailed")
+		e := eamapiv1alpha1.EventingAuth{}
+		Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(eventingAuth), &e)).Should(Succeed())
+		Expect(e.Status.Application.UUID).To(Equal("id-for-" + crName))
+		Expect(e.Annotations).To(HaveKeyWithValue(controllers.AdoptApplicationAnnotation, adoptedID))
+		Expect(iasClient.deletedApplicationIDs()).To(BeEmpty())
+		verifyApplicationSecretClientID("client-id-for-" + crName)
+
+		// when the adoption succeeds on a retry
+		iasClient.setAdoptionErr(nil)
+
+		// then
+		verifyApplicationSecretClientID("client-id-for-" + adoptedID)
+		Eventually(iasClient.deletedApplicationIDs, defaultTimeout).Should(ConsistOf("id-for-" + crName))
+	})
+})
+
+func annotateAdoptApplication(cr *eamapiv1alpha1.EventingAuth, id string) {
+	By(fmt.Sprintf("Annotating EventingAuth %s to adopt application %s", cr.Name, id))
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), cr)).Should(Succeed())
+		if cr.Annotations == nil {
+			cr.Annotations = map[string]string{}
+		}
+		cr.Annotations[controllers.AdoptApplicationAnnotation] = id
+		g.Expect(k8sClient.Update(context.TODO(), cr)).Should(Succeed())
+	}, defaultTimeout).Should(Succeed())
+}
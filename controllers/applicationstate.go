@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+)
+
+// applicationState stores the state of the applications of the EventingAuth CRs that is kept across reconciliations. It is shared by the
+// workers of the controller, while the state of a single EventingAuth CR is never accessed concurrently, since a request is never reconciled
+// concurrently.
+type applicationState struct {
+	mu sync.Mutex
+	// created stores the applications that were created, but whose credentials aren't delivered yet, so that they aren't created again.
+	created map[string]createdApplication
+	// creationFailures stores the time of the first failed creation of the applications whose creation didn't succeed since.
+	creationFailures map[string]time.Time
+	// deletionFailures stores the number of failed deletions of the applications whose deletion didn't succeed since.
+	deletionFailures map[string]int
+}
+
+func newApplicationState() *applicationState {
+	return &applicationState{
+		created:          map[string]createdApplication{},
+		creationFailures: map[string]time.Time{},
+		deletionFailures: map[string]int{},
+	}
+}
+
+// createdApplication returns the application that was created for the EventingAuth CR, if its credentials aren't delivered yet.
+func (s *applicationState) createdApplication(name string) (createdApplication, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	created, ok := s.created[name]
+	return created, ok
+}
+
+// storeCreatedApplication stores the application that was created for the EventingAuth CR.
+func (s *applicationState) storeCreatedApplication(name string, created createdApplication) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.created[name] = created
+}
+
+// forgetCreatedApplication removes the application that was created for the EventingAuth CR.
+func (s *applicationState) forgetCreatedApplication(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.created, name)
+}
+
+// creationFailedSince returns the time of the first failed creation of the application of the EventingAuth CR, if its creation didn't
+// succeed since.
+func (s *applicationState) creationFailedSince(name string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	firstFailure, ok := s.creationFailures[name]
+	return firstFailure, ok
+}
+
+// failedCreation records the failed creation of the application of the EventingAuth CR. It returns the time of the first failed creation,
+// and false if the creation didn't fail before.
+func (s *applicationState) failedCreation(name string) (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	firstFailure, failedBefore := s.creationFailures[name]
+	if !failedBefore {
+		firstFailure = time.Now()
+		s.creationFailures[name] = firstFailure
+	}
+	return firstFailure, failedBefore
+}
+
+// succeededCreation resets the failed creations of the application of the EventingAuth CR.
+func (s *applicationState) succeededCreation(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.creationFailures, name)
+}
+
+// failedDeletion records the failed deletion of the application of the EventingAuth CR and returns the number of failed deletions.
+func (s *applicationState) failedDeletion(name string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deletionFailures[name]++
+	return s.deletionFailures[name]
+}
+
+// forget removes the state of the application of the deleted EventingAuth CR.
+func (s *applicationState) forget(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.created, name)
+	delete(s.creationFailures, name)
+	delete(s.deletionFailures, name)
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/audit"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/sink"
+	kcorev1 "k8s.io/api/core/v1"
+)
+
+// Targets of the audit records of the secret writes.
+const (
+	auditTargetSKR   = "skr"
+	auditTargetSinks = "sinks"
+	// auditTargetKCP is the target of the writes of the secrets on KCP, i.e. the credentials secrets of the IASApplication CRs and the KCP
+	// secrets of the EventingAuth CRs.
+	auditTargetKCP = "kcp"
+)
+
+// writeSinks writes the credentials of the application to the sinks and records the write in the audit log. The KCP secret of the
+// EventingAuth CR is written first if the credentials are propagated to KCP, so that it is kept consistent with the other copies.
+func (r *eventingAuthReconciler) writeSinks(ctx context.Context, cr *eamapiv1alpha1.EventingAuth, app eamias.Application,
+	tenant string,
+) error {
+	if r.propagatesToKCP(*cr) {
+		if err := r.writeKCPSecret(ctx, cr, app, tenant); err != nil {
+			return err
+		}
+	}
+	if len(r.sinks) == 0 {
+		return nil
+	}
+	err := sink.WriteAll(ctx, r.sinks, cr.Name, app)
+	r.auditor.Record(ctx, audit.Record{
+		Action:        audit.ActionWriteSecret,
+		Application:   cr.Name,
+		ApplicationID: app.GetID(),
+		Tenant:        tenant,
+		Target:        sinksTarget(r.sinks),
+	}, err)
+	return err
+}
+
+// deleteSinks deletes the credentials of the application from the sinks and records the deletion in the audit log.
+func (r *eventingAuthReconciler) deleteSinks(ctx context.Context, cr *eamapiv1alpha1.EventingAuth) error {
+	if len(r.sinks) == 0 {
+		return nil
+	}
+	err := sink.DeleteAll(ctx, r.sinks, cr.Name)
+	if err == nil {
+		decisionOf(ctx).act("deleted credentials from %s", sinksTarget(r.sinks))
+	}
+	r.auditor.Record(ctx, audit.Record{
+		Action:        audit.ActionDeleteSecret,
+		Application:   cr.Name,
+		ApplicationID: applicationID(*cr),
+		Tenant:        owningIASTenant(*cr),
+		Target:        sinksTarget(r.sinks),
+	}, err)
+	return err
+}
+
+// sinksTarget returns the target of the audit records of the writes to the sinks, e.g. 'sinks:vault,credential-store'.
+func sinksTarget(sinks []sink.Sink) string {
+	names := make([]string, 0, len(sinks))
+	for _, s := range sinks {
+		names = append(names, s.Name())
+	}
+	return auditTargetSinks + ":" + strings.Join(names, ",")
+}
+
+// applicationID returns the ID of the application in the status of the EventingAuth CR, or an empty ID if no application was created.
+func applicationID(cr eamapiv1alpha1.EventingAuth) string {
+	if cr.Status.Application == nil {
+		return ""
+	}
+	return cr.Status.Application.UUID
+}
+
+// auditSKRSecretWrite records the write of the application secret on the SKR cluster in the audit log.
+func (r *eventingAuthReconciler) auditSKRSecretWrite(ctx context.Context, cr *eamapiv1alpha1.EventingAuth, app eamias.Application,
+	tenant string, appSecret kcorev1.Secret, err error,
+) {
+	target := auditTargetSKR
+	if err == nil {
+		target += ":" + appSecret.Namespace + "/" + appSecret.Name
+	}
+	r.auditor.Record(ctx, audit.Record{
+		Action:        audit.ActionWriteSecret,
+		Application:   cr.Name,
+		ApplicationID: app.GetID(),
+		Tenant:        tenant,
+		Target:        target,
+	}, err)
+}
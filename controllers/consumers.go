@@ -0,0 +1,231 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/go-logr/logr"
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/audit"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// isolatesConsumers returns true if the event consumers of the EventingAuth CR get dedicated applications.
+func isolatesConsumers(cr eamapiv1alpha1.EventingAuth) bool {
+	return cr.Spec.ConsumerIsolation == eamapiv1alpha1.ConsumerIsolationNamespace ||
+		cr.Spec.ConsumerIsolation == eamapiv1alpha1.ConsumerIsolationSubscription
+}
+
+// consumerApplicationName returns the name of the dedicated application of the consumer, which is the name of the EventingAuth CR followed by
+// the namespace and the name of the Subscription of the consumer, separated by dots. Since namespaces can't contain dots, the names of
+// different consumers never collide.
+func consumerApplicationName(cr eamapiv1alpha1.EventingAuth, consumer skr.Consumer) string {
+	name := cr.Name + "." + consumer.Namespace
+	if consumer.Subscription != "" {
+		name += "." + consumer.Subscription
+	}
+	return name
+}
+
+// reconcileConsumers provisions a dedicated application and secret for every event consumer on the SKR cluster that is requested by
+// spec.consumerIsolation, and deletes the applications and secrets of the consumers whose Subscriptions were deleted. The applications are
+// created in the IAS tenant of the application of the SKR cluster. The secret of an existing consumer that is missing on the SKR cluster is
+// restored with a new application, since the credentials of the existing application can't be read again.
+func (r *eventingAuthReconciler) reconcileConsumers(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr *eamapiv1alpha1.EventingAuth,
+) (kcontrollerruntime.Result, error) {
+	if !isolatesConsumers(*cr) && len(cr.Status.Consumers) == 0 {
+		return kcontrollerruntime.Result{}, nil
+	}
+	var desired []skr.Consumer
+	if isolatesConsumers(*cr) {
+		perSubscription := cr.Spec.ConsumerIsolation == eamapiv1alpha1.ConsumerIsolationSubscription
+		err := r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+			var err error
+			desired, err = skrClient.ListConsumers(ctx, perSubscription)
+			return err
+		})
+		if err != nil {
+			return kcontrollerruntime.Result{}, errors.Wrap(err, "failed to list event consumers")
+		}
+	}
+
+	for _, existing := range slices.Clone(cr.Status.Consumers) {
+		if slices.Contains(desired, skr.ParseConsumer(existing.Consumer)) {
+			continue
+		}
+		if err := r.deleteConsumer(ctx, p, cr, existing); err != nil {
+			return kcontrollerruntime.Result{}, err
+		}
+		logger.Info("Deleted application of event consumer", "consumer", existing.Consumer)
+	}
+
+	secretOpts := skr.SecretOptions{Immutable: cr.Spec.ImmutableSecret}
+	for _, consumer := range desired {
+		i := slices.IndexFunc(cr.Status.Consumers, func(c eamapiv1alpha1.ConsumerApplication) bool {
+			return c.Consumer == consumer.String()
+		})
+		if i >= 0 {
+			var exists bool
+			err := r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+				var err error
+				exists, err = skrClient.HasConsumerSecret(ctx, consumer)
+				return err
+			})
+			if err != nil {
+				return kcontrollerruntime.Result{}, errors.Wrapf(err, "failed to check secret of event consumer %s", consumer)
+			}
+			if exists {
+				continue
+			}
+		}
+		if shuttingDown(ctx) {
+			logger.Info("Not creating application of event consumer, because the manager stops", "consumer", consumer.String())
+			decisionOf(ctx).act("deferred creation of application of event consumer %s, because the manager stops", consumer)
+			return kcontrollerruntime.Result{Requeue: true}, nil
+		}
+		if err := r.createConsumer(ctx, p, cr, consumer, secretOpts); err != nil {
+			return kcontrollerruntime.Result{}, err
+		}
+		logger.Info("Created application of event consumer", "consumer", consumer.String())
+	}
+	return kcontrollerruntime.Result{}, nil
+}
+
+// createConsumer creates the dedicated application of the consumer, replacing an existing application with the same name, and writes its
+// credentials to the secret of the consumer. The application is recorded in the status before the secret is written, so that it is deleted
+// with the EventingAuth CR even if the secret can't be written.
+func (r *eventingAuthReconciler) createConsumer(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.EventingAuth,
+	consumer skr.Consumer, secretOpts skr.SecretOptions,
+) error {
+	name := consumerApplicationName(*cr, consumer)
+	tenant := owningIASTenant(*cr)
+	// The ID of the application of the SKR cluster must not identify the application of the consumer.
+	app, err := createApplicationInIASTenant(eamias.WithApplicationID(ctx, consumerApplicationID(*cr, consumer)), r.Client, p, tenant, name)
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionCreateApplication, Application: name, ApplicationID: app.GetID(),
+		Tenant: tenant}, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create application of event consumer %s", consumer)
+	}
+	decisionOf(ctx).act("created application %s of event consumer %s", app.GetID(), consumer)
+	r.events.normal(ctx, cr, eventReasonApplicationCreated, "Created application %s of event consumer %s", name, consumer)
+
+	consumers := slices.DeleteFunc(slices.Clone(cr.Status.Consumers), func(c eamapiv1alpha1.ConsumerApplication) bool {
+		return c.Consumer == consumer.String()
+	})
+	consumers = append(consumers, eamapiv1alpha1.ConsumerApplication{
+		Consumer:             consumer.String(),
+		Name:                 name,
+		UUID:                 app.GetID(),
+		SecretNamespacedName: fmt.Sprintf("%s/%s", consumer.Namespace, consumer.SecretName()),
+	})
+	if err := r.updateConsumersStatus(ctx, cr, consumers); err != nil {
+		return err
+	}
+
+	var secret kcorev1.Secret
+	err = r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+		var err error
+		secret, err = skrClient.CreateConsumerSecret(ctx, app, consumer, secretOpts)
+		return err
+	})
+	target := auditTargetSKR
+	if err == nil {
+		target += ":" + secret.Namespace + "/" + secret.Name
+	}
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionWriteSecret, Application: name, ApplicationID: app.GetID(), Tenant: tenant,
+		Target: target}, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create secret of event consumer %s", consumer)
+	}
+	decisionOf(ctx).act("created secret %s/%s of event consumer %s on SKR cluster", secret.Namespace, secret.Name, consumer)
+	r.events.normal(ctx, cr, eventReasonSecretSynced, "Created secret %s/%s of event consumer %s on SKR cluster", secret.Namespace,
+		secret.Name, consumer)
+	return nil
+}
+
+// deleteConsumers deletes the applications and secrets of all consumers of the EventingAuth CR.
+func (r *eventingAuthReconciler) deleteConsumers(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.EventingAuth) error {
+	for _, existing := range slices.Clone(cr.Status.Consumers) {
+		if err := r.deleteConsumer(ctx, p, cr, existing); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteConsumer deletes the application and the secret of the consumer, and removes the consumer from the status.
+func (r *eventingAuthReconciler) deleteConsumer(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.EventingAuth,
+	existing eamapiv1alpha1.ConsumerApplication,
+) error {
+	tenant := owningIASTenant(*cr)
+	err := p.DeleteApplication(eamias.WithApplicationID(ctx, existing.UUID), existing.Name)
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteApplication, Application: existing.Name, ApplicationID: existing.UUID,
+		Tenant: tenant}, err)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete application of event consumer %s", existing.Consumer)
+	}
+	decisionOf(ctx).act("deleted application %s of event consumer %s", existing.UUID, existing.Consumer)
+
+	if r.skrClientCache != nil {
+		err := r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+			return skrClient.DeleteConsumerSecret(ctx, skr.ParseConsumer(existing.Consumer))
+		})
+		r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteSecret, Application: existing.Name, ApplicationID: existing.UUID,
+			Tenant: tenant, Target: auditTargetSKR}, err)
+		if ignoreMissingSkrCluster(err) != nil {
+			return errors.Wrapf(err, "failed to delete secret of event consumer %s", existing.Consumer)
+		}
+	}
+
+	consumers := slices.DeleteFunc(slices.Clone(cr.Status.Consumers), func(c eamapiv1alpha1.ConsumerApplication) bool {
+		return c.Consumer == existing.Consumer
+	})
+	return r.updateConsumersStatus(ctx, cr, consumers)
+}
+
+// consumerApplicationID returns the ID of the application of the consumer in the status of the EventingAuth CR, or an empty ID if the
+// consumer has no application.
+func consumerApplicationID(cr eamapiv1alpha1.EventingAuth, consumer skr.Consumer) string {
+	for _, c := range cr.Status.Consumers {
+		if c.Consumer == consumer.String() {
+			return c.UUID
+		}
+	}
+	return ""
+}
+
+// updateConsumersStatus writes the consumers to the status of the latest version of the EventingAuth CR.
+func (r *eventingAuthReconciler) updateConsumersStatus(ctx context.Context, cr *eamapiv1alpha1.EventingAuth,
+	consumers []eamapiv1alpha1.ConsumerApplication,
+) error {
+	return r.mutateEventingAuthStatus(ctx, cr, func(status *eamapiv1alpha1.EventingAuthStatus) {
+		status.Consumers = consumers
+	})
+}
+
+// mutateEventingAuthStatus applies the mutation to the status of the latest version of the EventingAuth CR and writes it, and applies it to
+// the given EventingAuth CR afterward. Only the mutated fields are written, so that the conditions of a stale EventingAuth CR don't
+// overwrite the conditions of the latest version.
+func (r *eventingAuthReconciler) mutateEventingAuthStatus(ctx context.Context, cr *eamapiv1alpha1.EventingAuth,
+	mutate func(status *eamapiv1alpha1.EventingAuthStatus),
+) error {
+	actualEventingAuth := &eamapiv1alpha1.EventingAuth{}
+	if err := r.Client.Get(ctx, kpkgclient.ObjectKeyFromObject(cr), actualEventingAuth); err != nil {
+		return err
+	}
+	desiredEventingAuth := actualEventingAuth.DeepCopy()
+	mutate(&desiredEventingAuth.Status)
+	if err := r.updateStatus(ctx, actualEventingAuth, desiredEventingAuth); err != nil {
+		return errors.Wrap(err, "failed to update EventingAuth status")
+	}
+	mutate(&cr.Status)
+	return nil
+}
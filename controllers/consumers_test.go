@@ -0,0 +1,153 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	onsigomegatypes "github.com/onsi/gomega/types"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+// Since all tests use the same target cluster and therefore share the same application secret, they need to be executed serially.
+var _ = Describe("EventingAuth Controller consumer isolation tests", Serial, Ordered, func() {
+	var (
+		iasClient    *recordingIasClientStub
+		eventingAuth *eamapiv1alpha1.EventingAuth
+		crName       string
+		consumer     skr.Consumer
+	)
+
+	BeforeEach(func() {
+		iasClient = &recordingIasClientStub{}
+		stubIasAppCreation(iasClient)
+		crName = generateCrName()
+		createKubeconfigSecret(crName)
+		consumer = skr.Consumer{Namespace: "consumer-" + generateCrName()}
+		By(fmt.Sprintf("Creating namespace %s of event consumer on target cluster", consumer.Namespace))
+		Expect(targetClusterK8sClient.Create(context.TODO(), &kcorev1.Namespace{
+			ObjectMeta: kmetav1.ObjectMeta{Name: consumer.Namespace},
+		})).Should(Succeed())
+
+		eventingAuth = &eamapiv1alpha1.EventingAuth{
+			ObjectMeta: kmetav1.ObjectMeta{Name: crName, Namespace: skr.KcpNamespace},
+			Spec:       eamapiv1alpha1.EventingAuthSpec{ConsumerIsolation: eamapiv1alpha1.ConsumerIsolationNamespace},
+		}
+		By("Creating EventingAuth CR with consumer isolation per namespace")
+		Expect(k8sClient.Create(context.TODO(), eventingAuth)).Should(Succeed())
+		verifyEventingAuthStatusReady(eventingAuth)
+	})
+
+	AfterEach(func() {
+		deleteEventingAuthAndVerify(eventingAuth)
+		deleteApplicationSecretOnTargetCluster()
+		deleteKubeconfigSecret(crName)
+		// The envtest cluster doesn't finalize namespaces, so the namespace of each test is unique.
+		Expect(kpkgclient.IgnoreNotFound(targetClusterK8sClient.Delete(context.TODO(), &kcorev1.Namespace{
+			ObjectMeta: kmetav1.ObjectMeta{Name: consumer.Namespace},
+		}))).Should(Succeed())
+		revertReadCredentialsStub()
+		revertIasNewClientStub()
+	})
+
+	It("should provision the application of a namespace with Subscriptions and delete it with the last Subscription", func() {
+		// given
+		name := crName + "." + consumer.Namespace
+
+		// when
+		subscription := createSubscription(consumer.Namespace)
+
+		// then
+		verifyConsumersInStatus(eventingAuth, ContainElement(MatchFields(IgnoreExtras, Fields{
+			"Consumer":             Equal(consumer.String()),
+			"Name":                 Equal(name),
+			"SecretNamespacedName": Equal(consumer.Namespace + "/" + skr.ConsumerSecretName),
+		})))
+		verifyConsumerSecretClientID(consumer, "client-id-for-"+name)
+		Expect(iasClient.createdApplications()).To(ContainElement(name))
+		verifyApplicationSecretClientID("client-id-for-" + crName)
+
+		// when
+		By(fmt.Sprintf("Deleting Subscription %s/%s on target cluster", subscription.GetNamespace(), subscription.GetName()))
+		Expect(targetClusterK8sClient.Delete(context.TODO(), subscription)).Should(Succeed())
+
+		// then
+		verifyConsumersInStatus(eventingAuth, BeEmpty())
+		verifyConsumerSecretDoesNotExist(consumer)
+		Eventually(iasClient.deletedApplications, defaultTimeout).Should(ContainElement(name))
+		Expect(iasClient.deletedApplications()).NotTo(ContainElement(crName))
+	})
+
+	It("should not record the consumer if its application can't be created", func() {
+		// given
+		iasClient.setCreationErr(errIASApplicationCreation)
+
+		// when
+		createSubscription(consumer.Namespace)
+
+		// then
+		verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeWarning, "ReconcileFailed")
+		e := eamapiv1alpha1.EventingAuth{}
+		Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(eventingAuth), &e)).Should(Succeed())
+		Expect(e.Status.Consumers).To(BeEmpty())
+		verifyConsumerSecretDoesNotExist(consumer)
+		verifyApplicationSecretClientID("client-id-for-" + crName)
+
+		// when the creation succeeds on a retry
+		iasClient.setCreationErr(nil)
+
+		// then
+		verifyConsumerSecretClientID(consumer, "client-id-for-"+crName+"."+consumer.Namespace)
+		verifyConsumersInStatus(eventingAuth, HaveLen(1))
+	})
+})
+
+// createSubscription creates a Subscription of Kyma Eventing in the namespace on the target cluster. Only its metadata is read.
+func createSubscription(namespace string) *unstructured.Unstructured {
+	subscription := &unstructured.Unstructured{}
+	subscription.SetAPIVersion(skr.SubscriptionListGVK.GroupVersion().String())
+	subscription.SetKind("Subscription")
+	subscription.SetNamespace(namespace)
+	subscription.SetName("subscription")
+	By(fmt.Sprintf("Creating Subscription in namespace %s on target cluster", namespace))
+	Expect(targetClusterK8sClient.Create(context.TODO(), subscription)).Should(Succeed())
+	return subscription
+}
+
+func verifyConsumersInStatus(cr *eamapiv1alpha1.EventingAuth, matcher onsigomegatypes.GomegaMatcher) {
+	By(fmt.Sprintf("Verifying the consumers in the status of EventingAuth %s", cr.Name))
+	Eventually(func(g Gomega) {
+		e := eamapiv1alpha1.EventingAuth{}
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), &e)).Should(Succeed())
+		g.Expect(e.Status.Consumers).To(matcher)
+	}, defaultTimeout).Should(Succeed())
+}
+
+func verifyConsumerSecretClientID(consumer skr.Consumer, clientID string) {
+	By(fmt.Sprintf("Verifying that secret of event consumer %s on target cluster has client ID %s", consumer, clientID))
+	Eventually(func(g Gomega) {
+		var secret kcorev1.Secret
+		key := kpkgclient.ObjectKey{Namespace: consumer.Namespace, Name: consumer.SecretName()}
+		g.Expect(targetClusterK8sClient.Get(context.TODO(), key, &secret)).Should(Succeed())
+		g.Expect(string(secret.Data[eamias.SecretKeyClientID])).To(Equal(clientID))
+	}, defaultTimeout).Should(Succeed())
+}
+
+func verifyConsumerSecretDoesNotExist(consumer skr.Consumer) {
+	By(fmt.Sprintf("Verifying that secret of event consumer %s does not exist on target cluster", consumer))
+	Eventually(func(g Gomega) {
+		key := kpkgclient.ObjectKey{Namespace: consumer.Namespace, Name: consumer.SecretName()}
+		err := targetClusterK8sClient.Get(context.TODO(), key, &kcorev1.Secret{})
+		g.Expect(kapierrors.IsNotFound(err)).To(BeTrue())
+	}, defaultTimeout).Should(Succeed())
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// Requeue decisions of a reconciliation in the decision log.
+const (
+	decisionDone         = "done"
+	decisionRequeue      = "requeue"
+	decisionRequeueAfter = "requeueAfter"
+	decisionRetry        = "retry"
+)
+
+// reconcileDecision is the decision record of a reconciliation of an EventingAuth CR, which contains the inputs that the reconciliation
+// observed, the actions that it took, and its requeue decision, so that it can be reconstructed later why an application was deleted or
+// recreated. A nil decision doesn't record anything.
+type reconcileDecision struct {
+	observed decisionInputs
+	actions  []string
+}
+
+// decisionInputs are the inputs that the decisions of a reconciliation are based on.
+type decisionInputs struct {
+	Generation          int64  `json:"generation"`
+	ResourceVersion     string `json:"resourceVersion"`
+	Deleting            bool   `json:"deleting"`
+	Finalizer           bool   `json:"finalizer"`
+	State               string `json:"state,omitempty"`
+	Provider            string `json:"provider,omitempty"`
+	RequestedTenant     string `json:"requestedTenant,omitempty"`
+	MigrateToTenant     string `json:"migrateToTenant,omitempty"`
+	RotateCredentials   string `json:"rotateCredentials,omitempty"`
+	ApplicationID       string `json:"applicationId,omitempty"`
+	ApplicationTenant   string `json:"applicationTenant,omitempty"`
+	AuthSecret          string `json:"authSecret,omitempty"`
+	CachedApplication   bool   `json:"cachedApplication"`
+	ApplicationSecret   *bool  `json:"applicationSecretExists,omitempty"`
+	CreationFailedSince string `json:"creationFailedSince,omitempty"`
+}
+
+type reconcileDecisionKey struct{}
+
+// newReconcileDecision returns the decision record of a reconciliation of the EventingAuth CR with the inputs of the CR and of the state
+// of the reconciler.
+func (r *eventingAuthReconciler) newReconcileDecision(cr eamapiv1alpha1.EventingAuth) *reconcileDecision {
+	inputs := decisionInputs{
+		Generation:        cr.Generation,
+		ResourceVersion:   cr.ResourceVersion,
+		Deleting:          !cr.DeletionTimestamp.IsZero(),
+		Finalizer:         controllerutil.ContainsFinalizer(&cr, eventingAuthFinalizerName),
+		State:             string(cr.Status.State),
+		Provider:          cr.Spec.Provider,
+		RequestedTenant:   requestedIASTenant(cr.Spec),
+		MigrateToTenant:   cr.Annotations[MigrateToIASTenantAnnotation],
+		RotateCredentials: cr.Annotations[RotateCredentialsAnnotation],
+		ApplicationID:     applicationID(cr),
+	}
+	if cr.Status.Application != nil {
+		inputs.ApplicationTenant = cr.Status.Application.Tenant
+	}
+	if cr.Status.AuthSecret != nil {
+		inputs.AuthSecret = cr.Status.AuthSecret.NamespacedName
+	}
+	_, inputs.CachedApplication = r.applications.createdApplication(cr.Name)
+	if firstFailure, ok := r.applications.creationFailedSince(cr.Name); ok {
+		inputs.CreationFailedSince = firstFailure.UTC().Format(time.RFC3339)
+	}
+	return &reconcileDecision{observed: inputs}
+}
+
+// withReconcileDecision returns a context with the decision record of the reconciliation.
+func withReconcileDecision(ctx context.Context, d *reconcileDecision) context.Context {
+	return context.WithValue(ctx, reconcileDecisionKey{}, d)
+}
+
+// decisionOf returns the decision record of the reconciliation of the context, or nil if decisions aren't logged.
+func decisionOf(ctx context.Context) *reconcileDecision {
+	d, _ := ctx.Value(reconcileDecisionKey{}).(*reconcileDecision)
+	return d
+}
+
+// observeApplicationSecret records whether the application secret exists on the SKR cluster.
+func (d *reconcileDecision) observeApplicationSecret(exists bool) {
+	if d == nil {
+		return
+	}
+	d.observed.ApplicationSecret = &exists
+}
+
+// act records an action that the reconciliation took.
+func (d *reconcileDecision) act(format string, args ...interface{}) {
+	if d == nil {
+		return
+	}
+	d.actions = append(d.actions, fmt.Sprintf(format, args...))
+}
+
+// log logs the decision record with the requeue decision of the result and the error of the reconciliation.
+func (d *reconcileDecision) log(logger logr.Logger, result kcontrollerruntime.Result, err error) {
+	if d == nil {
+		return
+	}
+	actions := d.actions
+	if actions == nil {
+		actions = []string{}
+	}
+	keysAndValues := []interface{}{"observed", d.observed, "actions", actions}
+	switch {
+	case err != nil:
+		keysAndValues = append(keysAndValues, "decision", decisionRetry, "error", redact.String(err.Error()))
+	case result.RequeueAfter > 0:
+		keysAndValues = append(keysAndValues, "decision", decisionRequeueAfter, "requeueAfter", result.RequeueAfter.String())
+	case result.Requeue:
+		keysAndValues = append(keysAndValues, "decision", decisionRequeue)
+	default:
+		keysAndValues = append(keysAndValues, "decision", decisionDone)
+	}
+	logger.Info("Reconcile decision", keysAndValues...)
+}
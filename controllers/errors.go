@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"errors"
+	"net"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Classes of the reconcile errors, that the reconcile error metric is labeled with.
+const (
+	errorClassIASAuth           = "iasAuth"
+	errorClassIASQuota          = "iasQuota"
+	errorClassIASThrottled      = "iasThrottled"
+	errorClassIASUnavailable    = "iasUnavailable"
+	errorClassIAS               = "ias"
+	errorClassKubeconfigMissing = "kubeconfigMissing"
+	errorClassSKRUnreachable    = "skrUnreachable"
+	errorClassSKR               = "skr"
+	errorClassValidation        = "validation"
+	errorClassOther             = "other"
+)
+
+// skrClusterError is returned if an operation on an SKR cluster fails, so that the errors of the SKR clusters can be told apart from the
+// errors of the identity providers. Its message is the message of the wrapped error.
+type skrClusterError struct {
+	err error
+	// kubeconfig is set if the client of the SKR cluster couldn't be created from its kubeconfig.
+	kubeconfig bool
+}
+
+func (e *skrClusterError) Error() string {
+	return e.err.Error()
+}
+
+func (e *skrClusterError) Unwrap() error {
+	return e.err
+}
+
+// classifyError returns the class of a reconcile error, and whether the error is retryable or terminal. A terminal error isn't resolved by
+// retrying until the credentials, the quota, or the configuration is fixed.
+func classifyError(err error) (string, bool) {
+	var quotaErr *applicationQuotaError
+	var skrErr *skrClusterError
+	switch {
+	case provider.IsValidationError(err), kapierrors.IsInvalid(err):
+		return errorClassValidation, false
+	case errors.As(err, &quotaErr):
+		return errorClassIASQuota, false
+	case eamias.IsAuthenticationError(err):
+		return errorClassIASAuth, false
+	case eamias.IsThrottledError(err):
+		return errorClassIASThrottled, true
+	case eamias.IsUnavailableError(err):
+		return errorClassIASUnavailable, true
+	case eamias.IsStatusError(err):
+		return errorClassIAS, true
+	case errors.As(err, &skrErr):
+		return classifySKRError(skrErr)
+	default:
+		return errorClassOther, true
+	}
+}
+
+// classifySKRError returns the class of an error of an SKR cluster, and whether the error is retryable.
+func classifySKRError(err *skrClusterError) (string, bool) {
+	var netErr net.Error
+	switch {
+	case err.kubeconfig && kapierrors.IsNotFound(err):
+		// The kubeconfig secret is created when the SKR cluster is provisioned.
+		return errorClassKubeconfigMissing, true
+	case skr.IsSecretNotManagedError(err):
+		return errorClassSKR, false
+	case skr.IsAuthenticationError(err), errors.As(err, &netErr):
+		return errorClassSKRUnreachable, true
+	default:
+		return errorClassSKR, true
+	}
+}
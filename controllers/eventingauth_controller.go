@@ -19,51 +19,196 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"os"
-	"reflect"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/go-logr/logr"
 	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/audit"
 	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/kyma-project/eventing-auth-manager/internal/sink"
 	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
 	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	kcontrollerruntime "sigs.k8s.io/controller-runtime"
 	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 const (
-	eventingAuthFinalizerName           = "eventingauth.operator.kyma-project.io/finalizer"
-	iasCredsSecretNamespace      string = "IAS_CREDS_SECRET_NAMESPACE"
-	iasCredsSecretName           string = "IAS_CREDS_SECRET_NAME"
-	defaultIasCredsNamespaceName string = "kcp-system"
-	DefaultIasCredsSecretName    string = "eventing-auth-ias-creds" //nolint:gosec
+	eventingAuthFinalizerName        = "eventingauth.operator.kyma-project.io/finalizer"
+	DefaultIasCredsSecretName string = provider.DefaultIASCredentialsSecretName
+	// DefaultIASTenantFailoverDelay is the default duration that the creation of an application in an IAS tenant must fail for, before the
+	// application is created in the fallback tenant of the tenant.
+	DefaultIASTenantFailoverDelay = time.Minute * 10
+	// defaultHTTPClientTimeout is the timeout of the default HTTP client of the smoke tests, so that a hanging token endpoint doesn't stall
+	// the reconciliation.
+	defaultHTTPClientTimeout = time.Second * 30
+	// MigrateToIASTenantAnnotation is the annotation of an EventingAuth CR that triggers the migration of its application to the IAS tenant
+	// with the name of the value.
+	MigrateToIASTenantAnnotation = "eventingauth.operator.kyma-project.io/migrate-to-ias-tenant"
+	// RotateCredentialsAnnotation is the annotation of an EventingAuth CR that triggers the rotation of the credentials of its application.
+	// Its value is informational, e.g. the time of the request.
+	RotateCredentialsAnnotation = "eventingauth.operator.kyma-project.io/rotate-credentials"
 )
 
+// createdApplication is an application that was created in the identity provider, but whose credentials aren't delivered yet.
+type createdApplication struct {
+	application eamias.Application
+	// tenant is the IAS tenant that the application was created in. It is empty if the application wasn't created in a tenant that is
+	// requested by the EventingAuth CR.
+	tenant string
+	// joined is true if an existing shared application was joined instead of creating an application.
+	joined bool
+}
+
 // eventingAuthReconciler reconciles a EventingAuth object.
 type eventingAuthReconciler struct {
 	kpkgclient.Client
-	Scheme    *runtime.Scheme
-	iasClient eamias.Client
-	// existingIasApplications stores existing IAS apps in memory not to recreate again if exists
-	existingIasApplications map[string]eamias.Application
+	Scheme *runtime.Scheme
+	// providers contains the identity providers that the applications are managed in.
+	providers *provider.Registry
+	// iasClientPool is the pool of the clients of the IAS tenants, whose credentials secrets are watched. It is nil if the credentials
+	// secrets aren't watched.
+	iasClientPool *provider.IASClientPool
+	// skrClientCache caches the clients of the SKR clusters to reuse them across reconciliations.
+	skrClientCache *skr.ClientCache
+	// secretWatcher watches the application secrets on the SKR clusters. It is nil if watching is disabled.
+	secretWatcher *skr.SecretWatcher
+	// subscriptionWatcher watches the Subscriptions on the SKR clusters of the EventingAuth CRs with spec.consumerIsolation. It is nil if
+	// watching is disabled.
+	subscriptionWatcher *skr.SubscriptionWatcher
+	// sinks store the credentials of the IAS applications additionally to, or instead of, the application secrets on the SKR clusters.
+	sinks []sink.Sink
+	// applications stores the created applications and the failed creations and deletions of the applications across reconciliations.
+	applications *applicationState
+	// deletionOpts configures the deletion of the applications and when they are orphaned.
+	deletionOpts ApplicationDeletionOptions
+	// sharedApplications serializes joining and leaving the shared applications of the EventingAuth CRs with spec.sharedApplication.
+	sharedApplications sync.Mutex
+	// landscape is the landscape of the manager, e.g. 'eu-prod', that the fleet health metrics are labeled with.
+	landscape string
+	// events emits the events of the milestones and failures of the reconciliations.
+	events eventingAuthEvents
+	// auditor records the identity operations in the audit log. It is nil if no audit sink is configured.
+	auditor *audit.Recorder
+	// logDecisions enables the decision log, which logs a decision record per reconciliation.
+	logDecisions bool
+	// credentialsRotationReminder is the age of the provided credentials from which on their rotation is reminded. No rotation is reminded
+	// if it is 0.
+	credentialsRotationReminder time.Duration
+	// defaultCredentialPropagation is the propagation of the credentials of the EventingAuth CRs that don't request one.
+	defaultCredentialPropagation eamapiv1alpha1.CredentialPropagation
+	// iasTenantFailoverDelay is the duration that the creation of an application in an IAS tenant must fail for, before the application is
+	// created in the fallback tenant of the tenant.
+	iasTenantFailoverDelay time.Duration
+	// httpClient requests the tokens of the smoke tests of the applications that are migrated to another IAS tenant.
+	httpClient *http.Client
+	// maxConcurrentReconciles is the number of EventingAuth CRs that are reconciled concurrently.
+	maxConcurrentReconciles int
+}
+
+// EventingAuthReconcilerOptions configures the optional collaborators and the behavior of the reconciler of EventingAuth CRs. The zero value
+// of every field disables the behavior that the field configures.
+type EventingAuthReconcilerOptions struct {
+	// IASClientPool is the pool of the clients of the IAS tenants. If it is set, the EventingAuth CRs that aren't ready are reconciled again
+	// when the credentials secret of their IAS tenant changes, so that rotated credentials are used right away.
+	IASClientPool *provider.IASClientPool
+	// SKRClientCache caches the clients of the SKR clusters. If it is nil, no application secrets are written to the SKR clusters and the
+	// credentials are only written to the sinks and the KCP secrets.
+	SKRClientCache *skr.ClientCache
+	// SecretWatcher watches the application secrets on the SKR clusters. If it is nil, their changes are only detected with the resync of
+	// the EventingAuth CRs.
+	SecretWatcher *skr.SecretWatcher
+	// SubscriptionWatcher watches the Subscriptions of the event consumers. If it is nil, they are only detected with the resync of the
+	// EventingAuth CRs.
+	SubscriptionWatcher *skr.SubscriptionWatcher
+	// Sinks store the credentials additionally to, or instead of, the application secrets on the SKR clusters.
+	Sinks []sink.Sink
+	// Landscape labels the fleet health metrics.
+	Landscape string
+	// Recorder emits the events of the EventingAuth CRs, and additionally of their Kyma CRs if KymaEvents is set.
+	Recorder   record.EventRecorder
+	KymaEvents bool
+	// Auditor records the creations, deletions, and rotations of the applications and the writes of their credentials, if it isn't nil.
+	Auditor *audit.Recorder
+	// LogDecisions logs the observed inputs, the actions, and the requeue decision of every reconciliation as a single decision record.
+	LogDecisions bool
+	// Deletion configures when the applications whose deletion failed too often are orphaned.
+	Deletion ApplicationDeletionOptions
+	// CredentialsRotationReminder is the age of provided credentials from which on their rotation is reminded with warning events. No
+	// rotation is reminded if it is 0.
+	CredentialsRotationReminder time.Duration
+	// DefaultCredentialPropagation is the propagation of the credentials of the EventingAuth CRs that don't request one. Defaults to
+	// eamapiv1alpha1.CredentialPropagationSKR.
+	DefaultCredentialPropagation eamapiv1alpha1.CredentialPropagation
+	// IASTenantFailoverDelay is the duration that the creation of an application in an IAS tenant must fail for, before the application is
+	// created in the fallback tenant of the tenant. Defaults to DefaultIASTenantFailoverDelay.
+	IASTenantFailoverDelay time.Duration
+	// HTTPClient requests the tokens of the smoke tests of the applications that are migrated to another IAS tenant. Defaults to a client
+	// with a timeout of 30 seconds.
+	HTTPClient *http.Client
+	// MaxConcurrentReconciles is the number of EventingAuth CRs that are reconciled concurrently. Defaults to 1.
+	MaxConcurrentReconciles int
 }
 
-func NewEventingAuthReconciler(c kpkgclient.Client, s *runtime.Scheme) ManagedReconciler {
+// NewEventingAuthReconciler returns the reconciler of EventingAuth CRs. The applications are managed in the provider of the registry that
+// is requested by the EventingAuth CR, or in its default provider.
+func NewEventingAuthReconciler(c kpkgclient.Client, s *runtime.Scheme, providers *provider.Registry, opts EventingAuthReconcilerOptions,
+) ManagedReconciler {
+	if opts.DefaultCredentialPropagation == "" {
+		opts.DefaultCredentialPropagation = eamapiv1alpha1.CredentialPropagationSKR
+	}
+	if opts.IASTenantFailoverDelay == 0 {
+		opts.IASTenantFailoverDelay = DefaultIASTenantFailoverDelay
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: defaultHTTPClientTimeout}
+	}
 	return &eventingAuthReconciler{
-		Client:                  c,
-		Scheme:                  s,
-		existingIasApplications: map[string]eamias.Application{},
+		Client:                       c,
+		Scheme:                       s,
+		providers:                    providers,
+		iasClientPool:                opts.IASClientPool,
+		skrClientCache:               opts.SKRClientCache,
+		secretWatcher:                opts.SecretWatcher,
+		subscriptionWatcher:          opts.SubscriptionWatcher,
+		sinks:                        opts.Sinks,
+		applications:                 newApplicationState(),
+		deletionOpts:                 opts.Deletion,
+		landscape:                    opts.Landscape,
+		events:                       eventingAuthEvents{recorder: redact.EventRecorder(opts.Recorder), kymaEvents: opts.KymaEvents},
+		auditor:                      opts.Auditor,
+		logDecisions:                 opts.LogDecisions,
+		credentialsRotationReminder:  opts.CredentialsRotationReminder,
+		defaultCredentialPropagation: opts.DefaultCredentialPropagation,
+		iasTenantFailoverDelay:       opts.IASTenantFailoverDelay,
+		httpClient:                   opts.HTTPClient,
+		maxConcurrentReconciles:      opts.MaxConcurrentReconciles,
 	}
 }
 
 // +kubebuilder:rbac:groups=operator.kyma-project.io,resources=eventingauths,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operator.kyma-project.io,resources=eventingauths/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=operator.kyma-project.io,resources=eventingauths/finalizers,verbs=update
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=watch,list
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;watch;list;create;update;delete
 func (r *eventingAuthReconciler) Reconcile(ctx context.Context, req kcontrollerruntime.Request) (kcontrollerruntime.Result, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Reconciling EventingAuth")
@@ -72,71 +217,236 @@ func (r *eventingAuthReconciler) Reconcile(ctx context.Context, req kcontrollerr
 	if err != nil {
 		return kcontrollerruntime.Result{}, kpkgclient.IgnoreNotFound(err)
 	}
+	// The target cluster of the EventingAuth CR is accessed with the kubeconfig of spec.kubeconfigSecretRef, if it is set.
+	ctx = skr.WithEventingAuth(ctx, req.NamespacedName)
+
+	var decision *reconcileDecision
+	if r.logDecisions {
+		decision = r.newReconcileDecision(cr)
+		ctx = withReconcileDecision(ctx, decision)
+	}
+	result, err := r.reconcileEventingAuth(ctx, logger, req, cr)
+	if maintenanceErr := iasMaintenanceErrorOf(r.iasClientPool, owningIASTenant(cr), err); maintenanceErr != nil {
+		// The failures during an announced maintenance of the tenant aren't retried with backoff, and aren't logged or emitted as errors.
+		logger.Info("IAS tenant is in maintenance, reconciling again later", "error", redact.String(maintenanceErr.Error()),
+			"requeueAfter", iasMaintenanceRequeueInterval.String())
+		decisionOf(ctx).act("deferred reconciliation, because the IAS tenant is in maintenance")
+		result, err = kcontrollerruntime.Result{RequeueAfter: iasMaintenanceRequeueInterval}, nil
+	}
+	if err != nil {
+		r.events.failure(ctx, &cr, err)
+	}
+	decision.log(logger, result, err)
+	return result, err
+}
 
-	// sync IAS client credentials
-	r.iasClient, err = r.getIasClient()
+// reconcileEventingAuth reconciles the fetched EventingAuth CR. The returned error is emitted as warning event of the EventingAuth CR.
+func (r *eventingAuthReconciler) reconcileEventingAuth(ctx context.Context, logger logr.Logger, req kcontrollerruntime.Request,
+	cr eamapiv1alpha1.EventingAuth,
+) (kcontrollerruntime.Result, error) {
+	// The stored ID identifies the application of the CR among multiple applications with the same name, see eamias.DuplicatePolicyAdoptByID.
+	if cr.Status.Application != nil && cr.Status.Application.UUID != "" {
+		ctx = eamias.WithApplicationID(ctx, cr.Status.Application.UUID)
+	}
+	// The provider is requested on every reconciliation, so that it is recreated if its credentials changed.
+	p, err := r.providers.Get(ctx, providerSpec(cr))
 	if err != nil {
 		return kcontrollerruntime.Result{}, err
 	}
 	// check DeletionTimestamp to determine if object is under deletion
 	if cr.ObjectMeta.DeletionTimestamp.IsZero() {
-		if err = r.addFinalizer(ctx, &cr); err != nil {
+		if err := r.addFinalizer(ctx, &cr); err != nil {
 			return kcontrollerruntime.Result{}, err
 		}
+		if r.secretWatcher != nil && !r.secretWatcher.Watch(req.NamespacedName) {
+			logger.V(1).Info("Application secret is not watched, because the maximum number of watched SKR clusters is reached")
+		}
+		if r.subscriptionWatcher != nil {
+			if !isolatesConsumers(cr) {
+				r.subscriptionWatcher.Stop(cr.Name)
+			} else if !r.subscriptionWatcher.Watch(req.NamespacedName) {
+				logger.V(1).Info("Subscriptions are not watched, because the maximum number of watched SKR clusters is reached")
+			}
+		}
+		if tenant, ok := cr.Annotations[MigrateToIASTenantAnnotation]; ok {
+			if shuttingDown(ctx) {
+				logger.Info("Not migrating application, because the manager stops")
+				decisionOf(ctx).act("deferred migration of application, because the manager stops")
+				return kcontrollerruntime.Result{Requeue: true}, nil
+			}
+			return kcontrollerruntime.Result{}, r.migrateApplication(ctx, logger, p, cr, tenant)
+		}
+		if id, ok := cr.Annotations[AdoptApplicationAnnotation]; ok {
+			if shuttingDown(ctx) {
+				logger.Info("Not adopting application, because the manager stops")
+				decisionOf(ctx).act("deferred adoption of application, because the manager stops")
+				return kcontrollerruntime.Result{Requeue: true}, nil
+			}
+			return kcontrollerruntime.Result{}, r.adoptApplication(ctx, logger, p, cr, id)
+		}
+		if requested, ok := cr.Annotations[RotateCredentialsAnnotation]; ok {
+			if shuttingDown(ctx) {
+				logger.Info("Not rotating credentials, because the manager stops")
+				decisionOf(ctx).act("deferred rotation of credentials, because the manager stops")
+				return kcontrollerruntime.Result{Requeue: true}, nil
+			}
+			return kcontrollerruntime.Result{}, r.rotateCredentials(ctx, logger, p, cr, requested)
+		}
 	} else {
 		logger.Info("Handling deletion")
-		if err = r.handleDeletion(ctx, r.iasClient, &cr); err != nil {
-			return kcontrollerruntime.Result{}, err
-		}
 		// Stop reconciliation as the item is being deleted
-		return kcontrollerruntime.Result{}, nil
+		return r.handleDeletion(ctx, p, &cr)
 	}
 
-	return r.handleApplicationSecret(ctx, logger, cr)
-}
-
-func (r *eventingAuthReconciler) handleApplicationSecret(ctx context.Context, logger logr.Logger, cr eamapiv1alpha1.EventingAuth) (kcontrollerruntime.Result, error) {
-	skrClient, err := skr.NewClient(r.Client, cr.Name)
-	if err != nil {
-		logger.Error(err, "Failed to retrieve client of target cluster")
+	if cr.Spec.KubeconfigSecretRef != nil && r.skrClientCache == nil {
+		// Without application secrets on the target clusters, the kubeconfig of the target cluster would be ignored.
+		err := &provider.ValidationError{Message: "spec.kubeconfigSecretRef can't be used with --enable-skr-secret=false"}
+		if updateErr := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionSecretReady, err); updateErr != nil {
+			return kcontrollerruntime.Result{}, updateErr
+		}
 		return kcontrollerruntime.Result{}, err
 	}
+	if providesCredentials(cr) {
+		return r.reconcileProvidedCredentials(ctx, logger, p, cr)
+	}
+	result, err := r.handleApplicationSecret(ctx, logger, p, cr)
+	if err != nil || !result.IsZero() || !r.propagatesToSKR(cr) {
+		return result, err
+	}
+	result, err = r.reconcilePublisher(ctx, logger, p, &cr)
+	if err != nil || !result.IsZero() {
+		return result, err
+	}
+	return r.reconcileConsumers(ctx, logger, p, &cr)
+}
 
-	appSecretExists, err := skrClient.HasApplicationSecret(ctx)
-	if err != nil {
-		logger.Error(err, "Failed to retrieve secret state from target cluster")
-		return kcontrollerruntime.Result{}, err
+func (r *eventingAuthReconciler) handleApplicationSecret(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr eamapiv1alpha1.EventingAuth,
+) (kcontrollerruntime.Result, error) {
+	secretOpts := skr.SecretOptions{Immutable: cr.Spec.ImmutableSecret}
+	// A missing KCP secret is written before the reconciliation is done, since it is the only copy that the credentials can be read from.
+	var kcpApplication eamias.Application
+	kcpCurrent := true
+	if r.propagatesToKCP(cr) {
+		var err error
+		if kcpApplication, kcpCurrent, err = r.readKCPSecret(ctx, cr); err != nil {
+			return kcontrollerruntime.Result{}, err
+		}
 	}
-	if appSecretExists {
-		logger.Info("Reconciliation done, Application secret already exists")
-		return kcontrollerruntime.Result{}, nil
+	if kcpCurrent {
+		if !r.propagatesToSKR(cr) {
+			// Without application secrets on the SKR clusters, the SecretReady condition is the only indicator that the credentials were
+			// written.
+			if cr.Status.Application != nil && kmeta.IsStatusConditionTrue(cr.Status.Conditions, string(eamapiv1alpha1.ConditionSecretReady)) {
+				logger.Info("Reconciliation done, credentials were already written to the sinks")
+				return kcontrollerruntime.Result{}, r.removeStaleCopies(ctx, &cr)
+			}
+		} else {
+			done, err := r.syncApplicationSecret(ctx, logger, p, &cr, secretOpts)
+			if err != nil || done {
+				if err == nil {
+					err = r.removeStaleCopies(ctx, &cr)
+				}
+				return kcontrollerruntime.Result{}, err
+			}
+		}
 	}
 
-	iasApplication, appExists := r.existingIasApplications[cr.Name]
+	created, appExists := r.applications.createdApplication(cr.Name)
+	if !appExists && kcpApplication.GetID() != "" {
+		// The credentials of the current application are restored from the KCP secret, e.g. after the application secret on the SKR
+		// cluster was deleted, instead of creating a new application.
+		logger.Info("Restoring credentials of application from KCP secret")
+		decisionOf(ctx).act("restored credentials of application %s from KCP secret", kcpApplication.GetID())
+		created, appExists = createdApplication{application: kcpApplication, tenant: owningIASTenant(cr)}, true
+	}
 	if !appExists {
+		if shuttingDown(ctx) {
+			logger.Info("Not creating application in IAS, because the manager stops")
+			decisionOf(ctx).act("deferred creation of application, because the manager stops")
+			return kcontrollerruntime.Result{Requeue: true}, nil
+		}
 		var createAppErr error
 		logger.Info("Creating application in IAS")
-		iasApplication, createAppErr = r.iasClient.CreateApplication(ctx, cr.Name)
+		created, createAppErr = r.createApplication(ctx, logger, p, cr)
+		auditRecord := audit.Record{Action: audit.ActionCreateApplication, Application: applicationName(cr),
+			ApplicationID: created.application.GetID(), Tenant: created.tenant}
+		if createAppErr != nil {
+			auditRecord.Tenant = requestedIASTenant(cr.Spec)
+		}
+		if !created.joined {
+			r.auditor.Record(ctx, auditRecord, createAppErr)
+		}
 		if createAppErr != nil {
-			logger.Error(createAppErr, "Failed to create application in IAS")
+			if maintenanceErr := iasMaintenanceErrorOf(r.iasClientPool, owningIASTenant(cr), createAppErr); maintenanceErr != nil {
+				createAppErr = maintenanceErr
+			} else {
+				logger.Error(createAppErr, "Failed to create application in IAS")
+			}
 			if err := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionApplicationReady, createAppErr); err != nil {
 				return kcontrollerruntime.Result{}, err
 			}
 			return kcontrollerruntime.Result{}, createAppErr
 		}
-		logger.Info("Successfully created application in IAS")
-		r.existingIasApplications[cr.Name] = iasApplication
+		if created.joined {
+			r.events.normal(ctx, &cr, eventReasonApplicationCreated, "Joined shared application %s%s", applicationName(cr),
+				inIASTenant(created.tenant))
+		} else {
+			logger.Info("Successfully created application in IAS")
+			decisionOf(ctx).act("created application %s%s", created.application.GetID(), inIASTenant(created.tenant))
+			r.events.normal(ctx, &cr, eventReasonApplicationCreated, "Created application %s%s", applicationName(cr),
+				inIASTenant(created.tenant))
+		}
+		r.applications.storeCreatedApplication(cr.Name, created)
 	}
-	cr.Status.Application = &eamapiv1alpha1.IASApplication{
-		Name: cr.Name,
-		UUID: iasApplication.GetID(),
+	iasApplication := created.application
+	cr.Status.Application = &eamapiv1alpha1.ClusterApplication{
+		Name:                  applicationName(cr),
+		UUID:                  iasApplication.GetID(),
+		Tenant:                created.tenant,
+		CredentialsIssuedTime: credentialsIssuedTime(cr.Status.Application, iasApplication.GetID()),
 	}
 	if err := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionApplicationReady, nil); err != nil {
 		return kcontrollerruntime.Result{}, err
 	}
 
+	// The credentials are written to the sinks before the application secret is created on the SKR, so that a failed write is retried,
+	// because the next reconciliation doesn't find an application secret.
+	if err := r.writeSinks(ctx, &cr, iasApplication, created.tenant); err != nil {
+		logger.Error(err, "Failed to write credentials to sinks")
+		if updateErr := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionSecretReady, err); updateErr != nil {
+			return kcontrollerruntime.Result{}, updateErr
+		}
+		return kcontrollerruntime.Result{}, err
+	}
+	if len(r.sinks) > 0 {
+		decisionOf(ctx).act("wrote credentials of application %s to %s", iasApplication.GetID(), sinksTarget(r.sinks))
+	}
+
+	if !r.propagatesToSKR(cr) {
+		r.applications.forgetCreatedApplication(cr.Name)
+		if err := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionSecretReady, nil); err != nil {
+			return kcontrollerruntime.Result{}, err
+		}
+		r.events.normal(ctx, &cr, eventReasonSecretSynced, "Wrote credentials of application %s to the sinks", cr.Name)
+		logger.Info("Reconciliation done, credentials were written to the sinks")
+		return kcontrollerruntime.Result{}, r.removeStaleCopies(ctx, &cr)
+	}
+
 	logger.Info("Creating application secret on SKR")
-	appSecret, createSecretErr := skrClient.CreateSecret(ctx, iasApplication)
+	var appSecret kcorev1.Secret
+	start := time.Now()
+	createSecretErr := r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+		var err error
+		appSecret, err = skrClient.CreateSecret(ctx, iasApplication, secretOpts)
+		if err != nil {
+			return err
+		}
+		// The secret is read back, so that the SecretReady condition is only set if the credentials are consumable on the SKR.
+		return skrClient.VerifySecret(ctx, appSecret)
+	})
+	observeSecretSync(cr.Name, start, true, createSecretErr)
+	r.auditSKRSecretWrite(ctx, &cr, iasApplication, created.tenant, appSecret, createSecretErr)
 	if createSecretErr != nil {
 		logger.Error(createSecretErr, "Failed to create application secret on SKR")
 		if err := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionSecretReady, createSecretErr); err != nil {
@@ -145,9 +455,12 @@ func (r *eventingAuthReconciler) handleApplicationSecret(ctx context.Context, lo
 		return kcontrollerruntime.Result{}, createSecretErr
 	}
 	logger.Info("Successfully created application secret on SKR")
+	decisionOf(ctx).act("created application secret %s/%s of application %s on SKR cluster", appSecret.Namespace, appSecret.Name,
+		iasApplication.GetID())
+	r.events.normal(ctx, &cr, eventReasonSecretSynced, "Created application secret %s/%s on SKR cluster", appSecret.Namespace, appSecret.Name)
 
 	// Because the application secret is created on the SKR, we can delete it from the cache.
-	delete(r.existingIasApplications, cr.Name)
+	r.applications.forgetCreatedApplication(cr.Name)
 
 	cr.Status.AuthSecret = &eamapiv1alpha1.AuthSecret{
 		ClusterID:      cr.Name,
@@ -158,37 +471,319 @@ func (r *eventingAuthReconciler) handleApplicationSecret(ctx context.Context, lo
 	}
 
 	logger.Info("Reconciliation done")
-	return kcontrollerruntime.Result{}, nil
+	return kcontrollerruntime.Result{}, r.removeStaleCopies(ctx, &cr)
 }
 
-func (r *eventingAuthReconciler) getIasClient() (eamias.Client, error) {
-	namespace, name := getIasSecretNamespaceAndNameConfigs()
-	newIasCredentials, err := eamias.ReadCredentials(namespace, name, r.Client)
+// createApplication creates the application in the provider. If the creation in the IAS tenant that is requested by the EventingAuth CR
+// fails for longer than the failover delay, the application is created in the fallback tenant of the tenant instead. A shared
+// application is joined instead, and never fails over, since the group must stay in one tenant.
+func (r *eventingAuthReconciler) createApplication(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr eamapiv1alpha1.EventingAuth,
+) (createdApplication, error) {
+	if sharesApplication(cr) {
+		return r.joinSharedApplication(ctx, logger, p, cr)
+	}
+	tenant := requestedIASTenant(cr.Spec)
+	app, err := createApplicationInIASTenant(ctx, r.Client, p, tenant, cr.Name)
+	if err == nil {
+		r.applications.succeededCreation(cr.Name)
+		return createdApplication{application: app, tenant: tenant}, nil
+	}
+
+	firstFailure, failedBefore := r.applications.failedCreation(cr.Name)
+	if !failedBefore {
+		return createdApplication{}, err
+	}
+	if tenant == "" || time.Since(firstFailure) < r.iasTenantFailoverDelay {
+		return createdApplication{}, err
+	}
+	fallbackTenant, fallbackErr := provider.FallbackIASTenant(ctx, r.Client, tenant)
+	if fallbackErr != nil {
+		return createdApplication{}, errors.Wrapf(err, "failed to determine fallback tenant: %s", fallbackErr)
+	}
+	if fallbackTenant == "" {
+		return createdApplication{}, err
+	}
+
+	logger.Info("Creating application in fallback IAS tenant", "tenant", tenant, "fallbackTenant", fallbackTenant, "error", err.Error())
+	decisionOf(ctx).act("failed over to fallback IAS tenant %s, because the creation in IAS tenant %s failed since %s", fallbackTenant, tenant,
+		firstFailure.UTC().Format(time.RFC3339))
+	spec := cr.Spec.DeepCopy()
+	spec.IAS.Tenant = fallbackTenant
+	fallbackProvider, err := r.providers.Get(ctx, *spec)
+	if err != nil {
+		return createdApplication{}, err
+	}
+	app, err = createApplicationInIASTenant(ctx, r.Client, fallbackProvider, fallbackTenant, cr.Name)
 	if err != nil {
-		return nil, err
+		return createdApplication{}, errors.Wrapf(err, "failed to create application in fallback IAS tenant %s", fallbackTenant)
+	}
+	r.applications.succeededCreation(cr.Name)
+	return createdApplication{application: app, tenant: fallbackTenant}, nil
+}
+
+// migrateApplication migrates the application to the given IAS tenant. The application is created in the new tenant and smoke tested, before
+// the credentials of the sinks and the application secret are replaced and the application is deleted from the old tenant. Since every step
+// is repeatable, a failed migration is retried from the start, while the consumers keep the credentials of the old tenant. Once the new
+// tenant owns the application, spec.ias.tenant is set to the new tenant and the annotation is removed.
+func (r *eventingAuthReconciler) migrateApplication(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr eamapiv1alpha1.EventingAuth, tenant string,
+) error {
+	if sharesApplication(cr) {
+		return errors.Errorf("shared application %s can't be migrated to IAS tenant %s by a single EventingAuth CR", applicationName(cr),
+			tenant)
 	}
-	// return from cache unless credentials are changed
-	if r.iasClient != nil && reflect.DeepEqual(r.iasClient.GetCredentials(), newIasCredentials) {
-		return r.iasClient, nil
+	if providesCredentials(cr) {
+		return errors.Errorf("provided credentials can't be migrated to IAS tenant %s", tenant)
 	}
-	// update IAS client if credentials are changed
-	iasClient, err := eamias.NewClient(newIasCredentials.URL, newIasCredentials.Username, newIasCredentials.Password)
+	spec := cr.Spec.DeepCopy()
+	spec.IAS = &eamapiv1alpha1.IASProviderSpec{Tenant: tenant}
+	// The provider of the new tenant is requested even if no application needs to be moved, so that an invalid migration is rejected.
+	target, err := r.providers.Get(ctx, *spec)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create a new IAS client")
+		return errors.Wrapf(err, "failed to migrate application to IAS tenant %s", tenant)
+	}
+
+	// An application that wasn't created yet is created in the new tenant after the spec was updated.
+	moved := false
+	if cr.Status.Application != nil && owningIASTenant(cr) != tenant {
+		logger.Info("Migrating application to IAS tenant", "tenant", tenant, "previousTenant", owningIASTenant(cr))
+		if err := r.moveApplication(ctx, logger, p, target, &cr, tenant); err != nil {
+			return errors.Wrapf(err, "failed to migrate application to IAS tenant %s", tenant)
+		}
+		moved = true
+	}
+
+	latest := &eamapiv1alpha1.EventingAuth{}
+	if err := r.Client.Get(ctx, kpkgclient.ObjectKeyFromObject(&cr), latest); err != nil {
+		return err
+	}
+	delete(latest.Annotations, MigrateToIASTenantAnnotation)
+	// The moved application has new credentials, which fulfills a rotation that was requested before the migration.
+	if requested, ok := cr.Annotations[RotateCredentialsAnnotation]; moved && ok && latest.Annotations[RotateCredentialsAnnotation] == requested {
+		delete(latest.Annotations, RotateCredentialsAnnotation)
+	}
+	latest.Spec.IAS = &eamapiv1alpha1.IASProviderSpec{Tenant: tenant}
+	if err := r.Update(ctx, latest); err != nil {
+		return errors.Wrap(err, "failed to finish migration")
+	}
+	logger.Info("Migrated application to IAS tenant", "tenant", tenant)
+	decisionOf(ctx).act("finished migration to IAS tenant %s", tenant)
+	return nil
+}
+
+// moveApplication creates the application in the target provider and replaces the delivered credentials with its credentials, before the
+// application is deleted from the current provider and the target tenant is recorded as owner of the application. Every step and the
+// rotation of the credentials as a whole are recorded in the audit log.
+func (r *eventingAuthReconciler) moveApplication(ctx context.Context, logger logr.Logger, current, target provider.Provider,
+	cr *eamapiv1alpha1.EventingAuth, tenant string,
+) (err error) {
+	var app eamias.Application
+	defer func() {
+		r.auditor.Record(ctx, audit.Record{Action: audit.ActionRotateCredentials, Application: cr.Name, ApplicationID: app.GetID(),
+			Tenant: tenant}, err)
+	}()
+
+	// An application that was created by a failed attempt is replaced.
+	app, err = createApplicationInIASTenant(ctx, r.Client, target, tenant, cr.Name)
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionCreateApplication, Application: cr.Name, ApplicationID: app.GetID(),
+		Tenant: tenant}, err)
+	if err != nil {
+		return err
+	}
+	if err := provider.SmokeTest(ctx, r.httpClient, app); err != nil {
+		return err
+	}
+	logger.Info("Smoke test of application in new IAS tenant passed, replacing credentials")
+
+	if err := r.replaceCredentials(ctx, cr, app, tenant); err != nil {
+		return err
+	}
+
+	// The application is deleted from the old tenant before the new tenant is recorded, so that a retry after a failed status update
+	// doesn't leave the application in the old tenant behind.
+	decisionOf(ctx).act("replaced credentials with application %s in IAS tenant %s", app.GetID(), tenant)
+	deleteErr := current.DeleteApplication(ctx, cr.Name)
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteApplication, Application: cr.Name, ApplicationID: applicationID(*cr),
+		Tenant: owningIASTenant(*cr)}, deleteErr)
+	if deleteErr != nil {
+		return errors.Wrap(deleteErr, "failed to delete application from previous IAS tenant")
+	}
+	decisionOf(ctx).act("deleted application %s from previous IAS tenant %s", applicationID(*cr), owningIASTenant(*cr))
+	r.applications.forgetCreatedApplication(cr.Name)
+	now := kmetav1.Now()
+	cr.Status.Application = &eamapiv1alpha1.ClusterApplication{
+		Name:                  cr.Name,
+		UUID:                  app.GetID(),
+		Tenant:                tenant,
+		CredentialsIssuedTime: &now,
+	}
+	if err := r.updateEventingAuthStatus(ctx, cr, eamapiv1alpha1.ConditionApplicationReady, nil); err != nil {
+		return err
+	}
+	r.events.normal(ctx, cr, eventReasonCredentialsRotated, "Replaced credentials with the credentials of application %s%s", cr.Name,
+		inIASTenant(tenant))
+	return nil
+}
+
+// credentialsIssuedTime returns the time when the credentials of the application with the ID were issued. The time of the previous status is
+// kept for the same application, which may be unset if it was created before the time was recorded, and a new application is issued now.
+func credentialsIssuedTime(previous *eamapiv1alpha1.ClusterApplication, applicationID string) *kmetav1.Time {
+	if previous != nil && previous.UUID == applicationID {
+		return previous.CredentialsIssuedTime
 	}
-	return iasClient, nil
+	now := kmetav1.Now()
+	return &now
 }
 
-func getIasSecretNamespaceAndNameConfigs() (string, string) {
-	namespace := os.Getenv(iasCredsSecretNamespace)
-	if len(namespace) == 0 {
-		namespace = defaultIasCredsNamespaceName
+// rotateCredentials rotates the credentials of the application and removes the annotation with the requested value. An application that
+// wasn't created yet only gets credentials after the annotation was removed, so that nothing needs to be rotated. If another rotation was
+// requested meanwhile, the annotation is kept for the next reconciliation.
+func (r *eventingAuthReconciler) rotateCredentials(ctx context.Context, logger logr.Logger, p provider.Provider, cr eamapiv1alpha1.EventingAuth,
+	requested string,
+) error {
+	if sharesApplication(cr) {
+		// The rotation would revoke the credentials of the other EventingAuth CRs of the group.
+		return errors.Errorf("credentials of shared application %s can't be rotated by a single EventingAuth CR", applicationName(cr))
+	}
+	if providesCredentials(cr) {
+		// The OAuth client of the provided credentials isn't managed by the manager.
+		return errors.Errorf("provided credentials can't be rotated by the manager, rotate them in secret %s/%s",
+			cr.Spec.CredentialsSecretRef.Namespace, cr.Spec.CredentialsSecretRef.Name)
+	}
+	if cr.Status.Application != nil {
+		logger.Info("Rotating credentials of application", "requested", requested)
+		if err := r.rotateApplication(ctx, logger, p, &cr); err != nil {
+			return errors.Wrap(err, "failed to rotate credentials")
+		}
 	}
-	name := os.Getenv(iasCredsSecretName)
-	if len(name) == 0 {
-		name = DefaultIasCredsSecretName
+
+	latest := &eamapiv1alpha1.EventingAuth{}
+	if err := r.Client.Get(ctx, kpkgclient.ObjectKeyFromObject(&cr), latest); err != nil {
+		return err
 	}
-	return namespace, name
+	if latest.Annotations[RotateCredentialsAnnotation] != requested {
+		decisionOf(ctx).act("kept rotation annotation, because another rotation was requested meanwhile")
+		return nil
+	}
+	delete(latest.Annotations, RotateCredentialsAnnotation)
+	if err := r.Update(ctx, latest); err != nil {
+		return errors.Wrap(err, "failed to finish rotation of credentials")
+	}
+	return nil
+}
+
+// rotateApplication creates new credentials of the application in its provider, which revokes the previous credentials, and replaces the
+// delivered credentials with them. Since the previous credentials are already revoked, a failed attempt is retried with new credentials
+// instead of keeping the consumers on the previous ones. The rotation is recorded in the audit log.
+func (r *eventingAuthReconciler) rotateApplication(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr *eamapiv1alpha1.EventingAuth,
+) (err error) {
+	var app eamias.Application
+	tenant := owningIASTenant(*cr)
+	defer func() {
+		r.auditor.Record(ctx, audit.Record{Action: audit.ActionRotateCredentials, Application: cr.Name, ApplicationID: app.GetID(),
+			Tenant: tenant}, err)
+	}()
+
+	app, err = p.Rotate(ctx, cr.Name)
+	if err != nil {
+		return err
+	}
+	// The cached application has the revoked credentials, which must not be delivered again.
+	r.applications.forgetCreatedApplication(cr.Name)
+	if err := r.replaceCredentials(ctx, cr, app, tenant); err != nil {
+		return err
+	}
+	logger.Info("Rotated credentials of application")
+	decisionOf(ctx).act("rotated credentials of application %s", app.GetID())
+
+	now := kmetav1.Now()
+	cr.Status.Application.CredentialsIssuedTime = &now
+	if err := r.updateEventingAuthStatus(ctx, cr, eamapiv1alpha1.ConditionApplicationReady, nil); err != nil {
+		return err
+	}
+	r.events.normal(ctx, cr, eventReasonCredentialsRotated, "Rotated credentials of application %s%s", cr.Name, inIASTenant(tenant))
+	return nil
+}
+
+// replaceCredentials replaces the credentials of the sinks, of the KCP secret, and of the application secret on the SKR with the credentials
+// of the application, according to the propagation of the EventingAuth CR. The application secret is verified after it was written.
+func (r *eventingAuthReconciler) replaceCredentials(ctx context.Context, cr *eamapiv1alpha1.EventingAuth, app eamias.Application,
+	tenant string,
+) error {
+	if err := r.writeSinks(ctx, cr, app, tenant); err != nil {
+		return err
+	}
+	if !r.propagatesToSKR(*cr) {
+		return nil
+	}
+	var appSecret kcorev1.Secret
+	start := time.Now()
+	err := r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+		var err error
+		appSecret, err = skrClient.CreateSecret(ctx, app, skr.SecretOptions{Immutable: cr.Spec.ImmutableSecret})
+		if err != nil {
+			return err
+		}
+		return skrClient.VerifySecret(ctx, appSecret)
+	})
+	observeSecretSync(cr.Name, start, true, err)
+	r.auditSKRSecretWrite(ctx, cr, app, tenant, appSecret, err)
+	if err != nil {
+		return errors.Wrap(err, "failed to replace application secret on SKR")
+	}
+	return nil
+}
+
+// inIASTenant returns the suffix of the event messages that names the IAS tenant of an application, if the application is owned by a tenant
+// that is requested by the EventingAuth CR.
+func inIASTenant(tenant string) string {
+	if tenant == "" {
+		return ""
+	}
+	return " in IAS tenant " + tenant
+}
+
+// owningIASTenant returns the IAS tenant that owns the existing application, which is the requested tenant unless another tenant was
+// recorded in the status.
+func owningIASTenant(cr eamapiv1alpha1.EventingAuth) string {
+	if cr.Status.Application != nil && cr.Status.Application.Tenant != "" {
+		return cr.Status.Application.Tenant
+	}
+	return requestedIASTenant(cr.Spec)
+}
+
+// createApplicationInIASTenant creates the application in the provider of the IAS tenant with the given name, unless the tenant reached its
+// application quota. The quota isn't checked if no tenant is requested.
+func createApplicationInIASTenant(ctx context.Context, c kpkgclient.Client, p provider.Provider, tenant, name string) (eamias.Application,
+	error,
+) {
+	if tenant != "" {
+		if err := checkIASApplicationQuota(ctx, c, tenant); err != nil {
+			return eamias.Application{}, err
+		}
+	}
+	return p.CreateApplication(ctx, name)
+}
+
+// providerSpec returns the spec that the provider of the application is requested with. The IAS tenant that owns an existing application
+// takes precedence over the tenant of the spec, since the application might have been created in the fallback tenant.
+func providerSpec(cr eamapiv1alpha1.EventingAuth) eamapiv1alpha1.EventingAuthSpec {
+	if cr.Status.Application == nil || cr.Status.Application.Tenant == "" {
+		return cr.Spec
+	}
+	spec := cr.Spec.DeepCopy()
+	spec.IAS = &eamapiv1alpha1.IASProviderSpec{Tenant: cr.Status.Application.Tenant}
+	return *spec
+}
+
+// requestedIASTenant returns the IAS tenant that is requested by the spec, or an empty name if no tenant is requested.
+func requestedIASTenant(spec eamapiv1alpha1.EventingAuthSpec) string {
+	if spec.IAS == nil {
+		return ""
+	}
+	return spec.IAS.Tenant
 }
 
 // Adds the finalizer if none exists.
@@ -199,59 +794,249 @@ func (r *eventingAuthReconciler) addFinalizer(ctx context.Context, cr *eamapiv1a
 		if err := r.Update(ctx, cr); err != nil {
 			return errors.Wrap(err, "failed to add finalizer")
 		}
+		decisionOf(ctx).act("added finalizer")
 	}
 	return nil
 }
 
-// Deletes the secret and IAS app. Finally, removes the finalizer.
-func (r *eventingAuthReconciler) handleDeletion(ctx context.Context, iasClient eamias.Client, cr *eamapiv1alpha1.EventingAuth) error {
+// Deletes the secret and IAS app. Finally, removes the finalizer. If the deletion of the IAS app failed too often, the app is orphaned and
+// the finalizer is kept or removed according to the policy of orphaned applications.
+func (r *eventingAuthReconciler) handleDeletion(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.EventingAuth,
+) (kcontrollerruntime.Result, error) {
 	// The object is being deleted
 	if controllerutil.ContainsFinalizer(cr, eventingAuthFinalizerName) {
+		if r.secretWatcher != nil {
+			r.secretWatcher.Stop(cr.Name)
+		}
+		if r.subscriptionWatcher != nil {
+			r.subscriptionWatcher.Stop(cr.Name)
+		}
+
+		// The applications of the event publisher and consumers are deleted first, since they are only recorded in the status of the
+		// EventingAuth CR.
+		if err := r.deletePublisher(ctx, p, cr); err != nil {
+			return kcontrollerruntime.Result{}, err
+		}
+		if err := r.deleteConsumers(ctx, p, cr); err != nil {
+			return kcontrollerruntime.Result{}, err
+		}
+
 		// delete IAS application clean-up
-		if err := iasClient.DeleteApplication(ctx, cr.Name); err != nil {
-			return errors.Wrap(err, "failed to delete IAS Application")
+		deleted, err := r.deleteApplication(ctx, p, cr)
+		if err != nil {
+			orphaned, orphanErr := r.failedApplicationDeletion(ctx, cr, err)
+			if orphanErr != nil {
+				return kcontrollerruntime.Result{}, orphanErr
+			}
+			if !orphaned {
+				return kcontrollerruntime.Result{}, errors.Wrap(err, "failed to delete IAS Application")
+			}
+			if r.orphanedPolicy() == OrphanedApplicationPolicyKeepFinalizer {
+				return kcontrollerruntime.Result{RequeueAfter: orphanedApplicationRetryInterval}, nil
+			}
+		} else if deleted {
+			decisionOf(ctx).act("deleted application %s, because the EventingAuth CR is deleted", applicationID(*cr))
+			kcontrollerruntime.Log.Info("Deleted IAS application",
+				"eventingAuth", cr.Name, "namespace", cr.Namespace)
 		}
-		kcontrollerruntime.Log.Info("Deleted IAS application",
-			"eventingAuth", cr.Name, "namespace", cr.Namespace)
 
-		if err := r.deleteK8sSecretOnSkr(ctx, cr); err != nil {
-			return err
+		if err := r.deleteSinks(ctx, cr); err != nil {
+			return kcontrollerruntime.Result{}, err
+		}
+
+		if cr.Status.KCPSecret != "" {
+			if err := r.deleteKCPSecret(ctx, cr); err != nil {
+				return kcontrollerruntime.Result{}, err
+			}
+		}
+
+		if r.skrClientCache != nil {
+			if err := r.deleteK8sSecretOnSkr(ctx, cr); err != nil {
+				return kcontrollerruntime.Result{}, err
+			}
 		}
 
 		// delete the app from the cache
-		delete(r.existingIasApplications, cr.Name)
+		r.applications.forget(cr.Name)
+		forgetSecretSync(cr.Name)
 
 		// remove our finalizer from the list and update it.
 		controllerutil.RemoveFinalizer(cr, eventingAuthFinalizerName)
 		if err := r.Update(ctx, cr); err != nil {
-			return errors.Wrap(err, "failed to remove finalizer")
+			return kcontrollerruntime.Result{}, errors.Wrap(err, "failed to remove finalizer")
 		}
+		decisionOf(ctx).act("removed finalizer")
 	}
-	return nil
+	return kcontrollerruntime.Result{}, nil
+}
+
+// deleteApplication deletes the application of the EventingAuth CR. A shared application is only deleted if no other EventingAuth CR uses
+// it, and provided credentials have no application. It returns true if the application was deleted.
+func (r *eventingAuthReconciler) deleteApplication(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.EventingAuth) (bool, error) {
+	if sharesApplication(*cr) {
+		return r.leaveSharedApplication(ctx, p, cr)
+	}
+	if providesCredentials(*cr) && cr.Status.Application == nil {
+		// The OAuth client of the provided credentials isn't deleted, since it isn't managed by the manager.
+		return false, nil
+	}
+	err := p.DeleteApplication(ctx, cr.Name)
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteApplication, Application: cr.Name, ApplicationID: applicationID(*cr),
+		Tenant: owningIASTenant(*cr)}, err)
+	return err == nil, err
 }
 
 func (r *eventingAuthReconciler) deleteK8sSecretOnSkr(ctx context.Context, eventingAuth *eamapiv1alpha1.EventingAuth) error {
-	skrClient, err := skr.NewClient(r.Client, eventingAuth.Name)
+	err := r.withSkrClient(ctx, eventingAuth.Name, func(skrClient skr.Client) error {
+		return skrClient.DeleteSecret(ctx)
+	})
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteSecret, Application: eventingAuth.Name,
+		ApplicationID: applicationID(*eventingAuth), Tenant: owningIASTenant(*eventingAuth), Target: auditTargetSKR}, err)
+	if err != nil {
+		return ignoreMissingSkrCluster(err)
+	}
+	decisionOf(ctx).act("deleted application secret on SKR cluster")
+	// The cluster is no longer managed, so the client is not needed anymore.
+	r.skrClientCache.Evict(eventingAuth.Name)
+	kcontrollerruntime.Log.Info("Deleted SKR k8s secret",
+		"eventingAuth", eventingAuth.Name, "namespace", eventingAuth.Namespace)
+	return nil
+}
+
+// ignoreMissingSkrCluster returns nil if the error is a not found error, since the absence of the SKR kubeconfig secret means that the SKR
+// cluster might have been deleted, and the secrets on it with it.
+func ignoreMissingSkrCluster(err error) error {
+	return kpkgclient.IgnoreNotFound(err)
+}
+
+// syncApplicationSecret syncs an existing application secret on the SKR cluster, including the endpoints of the provider in the secret. It
+// returns true if the application secret exists, so that no new application needs to be created in IAS.
+func (r *eventingAuthReconciler) syncApplicationSecret(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr *eamapiv1alpha1.EventingAuth, secretOpts skr.SecretOptions,
+) (bool, error) {
+	var existingAppSecret *kcorev1.Secret
+	start := time.Now()
+	err := r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+		var err error
+		// Replicas of an existing application secret are synced without creating a new application in IAS.
+		existingAppSecret, err = skrClient.SyncApplicationSecret(ctx, secretOpts)
+		return err
+	})
+	observeSecretSync(cr.Name, start, existingAppSecret != nil, err)
+	if err == nil {
+		decisionOf(ctx).observeApplicationSecret(existingAppSecret != nil)
+	}
 	if err != nil {
-		// SKR kubeconfig secret absence means it might have been deleted
-		return kpkgclient.IgnoreNotFound(err)
+		logger.Error(err, "Failed to retrieve secret state from target cluster")
+		if skr.IsSecretNotManagedError(err) {
+			if updateErr := r.updateEventingAuthStatus(ctx, cr, eamapiv1alpha1.ConditionSecretReady, err); updateErr != nil {
+				return false, updateErr
+			}
+		}
+		return false, err
+	}
+	if existingAppSecret != nil {
+		// The secret was read from the SKR by the sync, so a SecretReady condition that is missing, e.g. because the read back after the
+		// creation of the secret failed, can be set now.
+		if !kmeta.IsStatusConditionTrue(cr.Status.Conditions, string(eamapiv1alpha1.ConditionSecretReady)) {
+			cr.Status.AuthSecret = &eamapiv1alpha1.AuthSecret{
+				ClusterID:      cr.Name,
+				NamespacedName: fmt.Sprintf("%s/%s", existingAppSecret.Namespace, existingAppSecret.Name),
+			}
+			if err := r.updateEventingAuthStatus(ctx, cr, eamapiv1alpha1.ConditionSecretReady, nil); err != nil {
+				return false, err
+			}
+		}
+		r.syncDiscovery(ctx, logger, p, cr, secretOpts)
+		logger.Info("Reconciliation done, Application secret already exists")
+		return true, nil
 	}
-	err = skrClient.DeleteSecret(ctx)
+	return false, nil
+}
+
+// syncDiscovery replaces the token URL and the JWKS URI of the existing application secret on the SKR cluster with the current endpoints of
+// the provider, e.g. after the endpoints of the IAS tenant moved to new hosts, so that the consumers don't keep stale endpoints until the
+// application is recreated. A failure doesn't fail the reconciliation, because the credentials in the secret are still valid, and the
+// endpoints are synced again with the next resync.
+func (r *eventingAuthReconciler) syncDiscovery(ctx context.Context, logger logr.Logger, p provider.Provider, cr *eamapiv1alpha1.EventingAuth,
+	secretOpts skr.SecretOptions,
+) {
+	discovery, err := p.Discovery(ctx)
 	if err != nil {
+		logger.Info("Failed to discover the endpoints of the provider, keeping the endpoints of the application secret", "error",
+			redact.String(err.Error()))
+		return
+	}
+	var updated bool
+	err = r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+		var err error
+		updated, err = skrClient.SyncDiscovery(ctx, discovery.TokenURL, discovery.JWKSURI, secretOpts)
 		return err
+	})
+	if err != nil {
+		logger.Info("Failed to update the endpoints of the application secret on SKR", "error", redact.String(err.Error()))
+		return
+	}
+	if !updated {
+		return
+	}
+	logger.Info("Updated the endpoints of the application secret on SKR", "tokenURL", discovery.TokenURL, "jwksURI", discovery.JWKSURI)
+	decisionOf(ctx).act("updated token URL and JWKS URI of application secret on SKR cluster")
+	r.events.normal(ctx, cr, eventReasonSecretSynced, "Updated token URL %s and JWKS URI %s of application secret on SKR cluster",
+		discovery.TokenURL, discovery.JWKSURI)
+}
+
+// withSkrClient executes the operation with the cached client of the SKR cluster. If the SKR cluster rejects the credentials of the client,
+// e.g. because the kubeconfig was rotated, the client is recreated from the latest kubeconfig and the operation is retried once.
+// A client is evicted from the cache if the operation fails, so that it is recreated on the next reconciliation.
+func (r *eventingAuthReconciler) withSkrClient(ctx context.Context, skrClusterID string, operation func(skr.Client) error) error {
+	skrClient, err := r.skrClientCache.Get(ctx, skrClusterID)
+	if err != nil {
+		return &skrClusterError{err: err, kubeconfig: true}
+	}
+
+	err = operation(skrClient)
+	if skr.IsAuthenticationError(err) {
+		log.FromContext(ctx).Info("Target cluster rejected the credentials, recreating client from latest kubeconfig", "error", err.Error())
+		skrClient, err = r.skrClientCache.Refresh(ctx, skrClusterID)
+		if err != nil {
+			return &skrClusterError{err: err, kubeconfig: true}
+		}
+		err = operation(skrClient)
+	}
+
+	if err != nil {
+		r.skrClientCache.Evict(skrClusterID)
+		return &skrClusterError{err: err}
 	}
-	kcontrollerruntime.Log.Info("Deleted SKR k8s secret",
-		"eventingAuth", eventingAuth.Name, "namespace", eventingAuth.Namespace)
 	return nil
 }
 
 // updateEventingAuthStatus updates the subscription's status changes to k8s.
 func (r *eventingAuthReconciler) updateEventingAuthStatus(ctx context.Context, cr *eamapiv1alpha1.EventingAuth, conditionType eamapiv1alpha1.ConditionType, errToCheck error) error {
-	_, err := eamapiv1alpha1.UpdateConditionAndState(cr, conditionType, errToCheck)
+	_, err := eamapiv1alpha1.UpdateConditionAndState(cr, conditionType,
+		eamapiv1alpha1.WithCorrelationID(errToCheck, tracing.CorrelationID(ctx)))
 	if err != nil {
 		return err
 	}
+	provisioned := cr.Status.State == eamapiv1alpha1.StateReady && cr.Status.ProvisionedTime == nil
+	if provisioned {
+		now := kmetav1.Now()
+		cr.Status.ProvisionedTime = &now
+	}
+
+	if err := r.writeEventingAuthStatus(ctx, cr); err != nil {
+		return err
+	}
+	if provisioned {
+		r.observeProvisioning(ctx, cr)
+	}
+
+	return nil
+}
 
+// writeEventingAuthStatus writes the status of the EventingAuth CR to the latest version of the CR.
+func (r *eventingAuthReconciler) writeEventingAuthStatus(ctx context.Context, cr *eamapiv1alpha1.EventingAuth) error {
 	namespacedName := &types.NamespacedName{
 		Name:      cr.Name,
 		Namespace: cr.Namespace,
@@ -268,13 +1053,33 @@ func (r *eventingAuthReconciler) updateEventingAuthStatus(ctx context.Context, c
 	desiredEventingAuth.Status = cr.Status
 
 	// sync EventingAuth status with k8s
-	if err = r.updateStatus(ctx, actualEventingAuth, desiredEventingAuth); err != nil {
+	if err := r.updateStatus(ctx, actualEventingAuth, desiredEventingAuth); err != nil {
 		return errors.Wrap(err, "failed to update EventingAuth status")
 	}
-
 	return nil
 }
 
+// observeProvisioning records the time from the creation of the Kyma CR that owns the EventingAuth CR, or of the EventingAuth CR if it
+// isn't owned by a Kyma CR, until the EventingAuth CR was ready for the first time.
+func (r *eventingAuthReconciler) observeProvisioning(ctx context.Context, cr *eamapiv1alpha1.EventingAuth) {
+	source, created := provisioningSourceEventingAuth, cr.CreationTimestamp
+	for _, owner := range cr.OwnerReferences {
+		if owner.Kind != "Kyma" {
+			continue
+		}
+		kyma := &kmetav1.PartialObjectMetadata{}
+		kyma.SetGroupVersionKind(schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind))
+		if err := r.Client.Get(ctx, kpkgclient.ObjectKey{Namespace: cr.Namespace, Name: owner.Name}, kyma); err != nil {
+			log.FromContext(ctx).V(1).Info("Failed to get Kyma CR, measuring provisioning time from creation of EventingAuth CR",
+				"error", err.Error())
+			break
+		}
+		source, created = provisioningSourceKyma, kyma.CreationTimestamp
+		break
+	}
+	provisioningDuration.WithLabelValues(source).Observe(cr.Status.ProvisionedTime.Sub(created.Time).Seconds())
+}
+
 func (r *eventingAuthReconciler) updateStatus(ctx context.Context, oldEventingAuth, newEventingAuth *eamapiv1alpha1.EventingAuth) error {
 	// compare the status taking into consideration lastTransitionTime in conditions
 	if eamapiv1alpha1.IsEventingAuthStatusEqual(oldEventingAuth.Status, newEventingAuth.Status) {
@@ -294,11 +1099,67 @@ func fetchEventingAuth(ctx context.Context, c kpkgclient.Client, name types.Name
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *eventingAuthReconciler) SetupWithManager(mgr kcontrollerruntime.Manager) error {
-	return kcontrollerruntime.NewControllerManagedBy(mgr).
+	if err := metrics.Registry.Register(newEventingAuthCollector(mgr.GetClient(), r.landscape)); err != nil {
+		return errors.Wrap(err, "failed to register EventingAuth metrics")
+	}
+	b := kcontrollerruntime.NewControllerManagedBy(mgr).
 		For(&eamapiv1alpha1.EventingAuth{}).
-		Complete(r)
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.maxConcurrentReconciles})
+	if r.secretWatcher != nil {
+		if err := mgr.Add(r.secretWatcher); err != nil {
+			return errors.Wrap(err, "failed to add SKR secret watcher to manager")
+		}
+		b = b.WatchesRawSource(&source.Channel{Source: r.secretWatcher.Events()}, &handler.EnqueueRequestForObject{})
+	}
+	if r.subscriptionWatcher != nil {
+		if err := mgr.Add(r.subscriptionWatcher); err != nil {
+			return errors.Wrap(err, "failed to add SKR Subscription watcher to manager")
+		}
+		b = b.WatchesRawSource(&source.Channel{Source: r.subscriptionWatcher.Events()}, &handler.EnqueueRequestForObject{})
+	}
+	if r.iasClientPool != nil {
+		b = b.Watches(&kcorev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.eventingAuthsOfIASCredentialsSecret))
+	}
+	b = b.Watches(&kcorev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.eventingAuthsOfProvidedCredentialsSecret))
+	return b.Complete(r.InstrumentedReconciler())
+}
+
+// InstrumentedReconciler returns the reconciler as it is started by the controller.
+func (r *eventingAuthReconciler) InstrumentedReconciler() reconcile.Reconciler {
+	return instrument(eventingAuthControllerName, r)
+}
+
+// eventingAuthsOfIASCredentialsSecret returns the EventingAuth CRs that aren't ready and whose applications are owned by an IAS tenant
+// whose credentials are read from the secret. The IAS client of the tenant is recreated with the changed credentials when the EventingAuth
+// CRs are reconciled. Ready EventingAuth CRs aren't affected, since they don't use the client until they are changed.
+func (r *eventingAuthReconciler) eventingAuthsOfIASCredentialsSecret(ctx context.Context, secret kpkgclient.Object) []reconcile.Request {
+	logger := log.FromContext(ctx)
+	tenants, err := r.iasClientPool.TenantsOfSecret(ctx, secret)
+	if err != nil {
+		logger.Error(err, "Failed to determine IAS tenants of secret", "secret", kpkgclient.ObjectKeyFromObject(secret))
+		return nil
+	}
+	if len(tenants) == 0 {
+		return nil
+	}
+	var eventingAuths eamapiv1alpha1.EventingAuthList
+	if err := r.Client.List(ctx, &eventingAuths); err != nil {
+		logger.Error(err, "Failed to list EventingAuth CRs of IAS credentials secret", "secret", kpkgclient.ObjectKeyFromObject(secret))
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, cr := range eventingAuths.Items {
+		if cr.Status.State == eamapiv1alpha1.StateReady || !slices.Contains(tenants, owningIASTenant(cr)) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: kpkgclient.ObjectKeyFromObject(&cr)})
+	}
+	return requests
 }
 
 type ManagedReconciler interface {
 	SetupWithManager(mgr kcontrollerruntime.Manager) error
+	// InstrumentedReconciler returns the reconciler with the metrics, tracing and shutdown handling of the controller, so that objects can
+	// be reconciled without starting the controller, e.g. in a single pass of all objects.
+	InstrumentedReconciler() reconcile.Reconciler
 }
@@ -54,6 +54,8 @@ var _ = Describe("EventingAuth Controller happy tests", Serial, Ordered, func()
 		It("should delete secret with IAS applications credentials", func() {
 			eventingAuth = createEventingAuth(crName)
 			verifyEventingAuthStatusReady(eventingAuth)
+			verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeNormal, "ApplicationCreated")
+			verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeNormal, "SecretSynced")
 
 			// Time-based EventingAuth CR reconciliation
 			secret := verifySecretExistsOnTargetCluster()
@@ -108,6 +110,7 @@ var _ = Describe("EventingAuth Controller unhappy tests", Serial, Ordered, func(
 		stubFailedIasAppCreation()
 		eventingAuth = createEventingAuth(crName)
 		verifyEventingAuthStatusNotReadyAppCreationFailed(eventingAuth)
+		verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeWarning, "ReconcileFailed")
 	})
 
 	It("should have CR status NotReady when secret creation on target cluster fails", func() {
@@ -217,7 +220,7 @@ func verifyEventingAuthStatusNotReadyAppCreationFailed(cr *eamapiv1alpha1.Eventi
 		g.Expect(e.Status.State).To(Equal(eamapiv1alpha1.StateNotReady))
 
 		g.Expect(e.Status.Conditions).To(ContainElements(
-			conditionMatcher(
+			failureConditionMatcher(
 				string(eamapiv1alpha1.ConditionApplicationReady),
 				kmetav1.ConditionFalse,
 				eamapiv1alpha1.ConditionReasonApplicationCreationFailed,
@@ -235,7 +238,7 @@ func verifyEventingAuthStatusNotReadySecretCreationFailed(cr *eamapiv1alpha1.Eve
 		g.Expect(e.Status.State).To(Equal(eamapiv1alpha1.StateNotReady))
 
 		g.Expect(e.Status.Conditions).To(ContainElements(
-			conditionMatcher(
+			failureConditionMatcher(
 				string(eamapiv1alpha1.ConditionSecretReady),
 				kmetav1.ConditionFalse,
 				eamapiv1alpha1.ConditionReasonSecretCreationFailed,
@@ -244,6 +247,19 @@ func verifyEventingAuthStatusNotReadySecretCreationFailed(cr *eamapiv1alpha1.Eve
 	}, defaultTimeout).Should(Succeed())
 }
 
+func verifyEventingAuthEvent(cr *eamapiv1alpha1.EventingAuth, eventType, reason string) {
+	By(fmt.Sprintf("Verifying that EventingAuth %s has event %s", cr.Name, reason))
+	Eventually(func(g Gomega) {
+		var events kcorev1.EventList
+		g.Expect(k8sClient.List(context.TODO(), &events, kpkgclient.InNamespace(cr.Namespace))).Should(Succeed())
+		g.Expect(events.Items).To(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"InvolvedObject": MatchFields(IgnoreExtras, Fields{"Kind": Equal("EventingAuth"), "Name": Equal(cr.Name)}),
+			"Type":           Equal(eventType),
+			"Reason":         Equal(reason),
+		})))
+	}, defaultTimeout).Should(Succeed())
+}
+
 func conditionMatcher(t string, s kmetav1.ConditionStatus, r, m string) onsigomegatypes.GomegaMatcher {
 	return MatchFields(IgnoreExtras, Fields{
 		"Type":    Equal(t),
@@ -253,6 +269,16 @@ func conditionMatcher(t string, s kmetav1.ConditionStatus, r, m string) onsigome
 	})
 }
 
+// failureConditionMatcher matches a failed condition, whose message is followed by the correlation ID of the failed reconciliation.
+func failureConditionMatcher(t string, s kmetav1.ConditionStatus, r, m string) onsigomegatypes.GomegaMatcher {
+	return MatchFields(IgnoreExtras, Fields{
+		"Type":    Equal(t),
+		"Status":  Equal(s),
+		"Reason":  Equal(r),
+		"Message": HavePrefix(m + " (correlation ID: "),
+	})
+}
+
 func deleteEventingAuthAndVerify(e *eamapiv1alpha1.EventingAuth) {
 	By(fmt.Sprintf("Deleting EventingAuth %s", e.Name))
 	if err := k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(e), &eamapiv1alpha1.EventingAuth{}); err != nil {
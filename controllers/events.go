@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the events of the milestones of the reconciliation of an EventingAuth CR.
+const (
+	eventReasonApplicationCreated = "ApplicationCreated"
+	eventReasonSecretSynced       = "SecretSynced"
+	eventReasonCredentialsRotated = "CredentialsRotated"
+	eventReasonApplicationAdopted = "ApplicationAdopted"
+	// eventReasonApplicationSecretsRevoked is the reason of the warning events that adoptions revoked the API secrets of applications.
+	eventReasonApplicationSecretsRevoked = "ApplicationSecretsRevoked"
+	// eventReasonCredentialsRotationDue is the reason of the warning events that remind to rotate the provided credentials.
+	eventReasonCredentialsRotationDue = "CredentialsRotationDue"
+)
+
+// failureEventReasons are the reasons of the warning events of the failed reconciliations of an EventingAuth CR by the class of their error.
+var failureEventReasons = map[string]string{ //nolint:gochecknoglobals // Lookup table.
+	errorClassIASAuth:           "IASAuthenticationFailed",
+	errorClassIASQuota:          "IASQuotaExceeded",
+	errorClassIASThrottled:      "IASThrottled",
+	errorClassIASUnavailable:    "IASUnavailable",
+	errorClassIAS:               "IASRequestFailed",
+	errorClassKubeconfigMissing: "KubeconfigMissing",
+	errorClassSKRUnreachable:    "SKRUnreachable",
+	errorClassSKR:               "SKRRequestFailed",
+	errorClassValidation:        "InvalidConfiguration",
+	errorClassOther:             "ReconcileFailed",
+}
+
+// eventingAuthEvents emits the events of an EventingAuth CR, so that `kubectl describe` shows the milestones and failures of its
+// reconciliation. If enabled, the events are additionally emitted on the Kyma CR that owns the EventingAuth CR. The messages of the events
+// end with the correlation ID of the reconciliation.
+type eventingAuthEvents struct {
+	recorder   record.EventRecorder
+	kymaEvents bool
+}
+
+// normal emits an event of a milestone of the reconciliation.
+func (e eventingAuthEvents) normal(ctx context.Context, cr *eamapiv1alpha1.EventingAuth, reason, messageFmt string, args ...interface{}) {
+	e.emit(ctx, cr, kcorev1.EventTypeNormal, reason, messageFmt, args...)
+}
+
+// warning emits a warning event that requires an action of the operators, but doesn't fail the reconciliation.
+func (e eventingAuthEvents) warning(ctx context.Context, cr *eamapiv1alpha1.EventingAuth, reason, messageFmt string, args ...interface{}) {
+	e.emit(ctx, cr, kcorev1.EventTypeWarning, reason, messageFmt, args...)
+}
+
+// failure emits a warning event of a failed reconciliation, whose reason is the class of the error. Conflicts aren't emitted, since they
+// are resolved by the next reconciliation.
+func (e eventingAuthEvents) failure(ctx context.Context, cr *eamapiv1alpha1.EventingAuth, err error) {
+	if kapierrors.IsConflict(err) {
+		return
+	}
+	class, _ := classifyError(err)
+	e.emit(ctx, cr, kcorev1.EventTypeWarning, failureEventReasons[class], "Reconciliation failed: %s", err.Error())
+}
+
+func (e eventingAuthEvents) emit(ctx context.Context, cr *eamapiv1alpha1.EventingAuth, eventType, reason, messageFmt string,
+	args ...interface{},
+) {
+	if e.recorder == nil {
+		return
+	}
+	messageFmt = eamapiv1alpha1.AppendCorrelationID(messageFmt, strings.ReplaceAll(tracing.CorrelationID(ctx), "%", "%%"))
+	e.recorder.Eventf(cr, eventType, reason, messageFmt, args...)
+	if !e.kymaEvents {
+		return
+	}
+	for _, owner := range cr.OwnerReferences {
+		if owner.Kind != "Kyma" {
+			continue
+		}
+		kyma := &kmetav1.PartialObjectMetadata{ObjectMeta: kmetav1.ObjectMeta{Namespace: cr.Namespace, Name: owner.Name, UID: owner.UID}}
+		kyma.SetGroupVersionKind(schema.FromAPIVersionAndKind(owner.APIVersion, owner.Kind))
+		e.recorder.Eventf(kyma, eventType, reason, "EventingAuth %s: "+messageFmt, append([]interface{}{cr.Name}, args...)...)
+	}
+}
@@ -0,0 +1,148 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Since all tests use the same target cluster and therefore share the same application secret, they need to be executed serially.
+var _ = Describe("EventingAuth Controller IAS tenant failover tests", Serial, Ordered, func() {
+	var (
+		primaryClient  *recordingIasClientStub
+		fallbackClient *recordingIasClientStub
+		tenants        []*eamapiv1alpha1.IASTenant
+		eventingAuth   *eamapiv1alpha1.EventingAuth
+		crName         string
+	)
+
+	BeforeEach(func() {
+		crName = generateCrName()
+		createKubeconfigSecret(crName)
+		primaryName, fallbackName := generateCrName(), generateCrName()
+		primaryClient = &recordingIasClientStub{}
+		fallbackClient = &recordingIasClientStub{}
+		// The clients are stubbed before the IASTenant CRs are created, since the pool keeps the clients of unchanged credentials.
+		replaceIasNewIasClientWithTenantStubs(map[string]eamias.Client{
+			iasTenantURL(primaryName):  primaryClient,
+			iasTenantURL(fallbackName): fallbackClient,
+		})
+		tenants = []*eamapiv1alpha1.IASTenant{createIASTenant(primaryName, fallbackName), createIASTenant(fallbackName, "")}
+	})
+
+	AfterEach(func() {
+		deleteEventingAuthAndVerify(eventingAuth)
+		deleteApplicationSecretOnTargetCluster()
+		deleteKubeconfigSecret(crName)
+		for _, tenant := range tenants {
+			deleteIASTenant(tenant)
+		}
+		revertIasNewClientStub()
+	})
+
+	It("should create the application in the requested IAS tenant", func() {
+		// when
+		eventingAuth = createEventingAuthInIASTenant(crName, tenants[0].Name)
+
+		// then
+		verifyEventingAuthStatusReady(eventingAuth)
+		verifyApplicationTenant(eventingAuth, tenants[0].Name)
+		Expect(primaryClient.createdApplications()).To(ConsistOf(crName))
+		Expect(fallbackClient.createdApplications()).To(BeEmpty())
+
+		// when
+		deleteEventingAuthAndVerify(eventingAuth)
+
+		// then
+		Expect(primaryClient.deletedApplications()).To(ContainElement(crName))
+		Expect(fallbackClient.deletedApplications()).To(BeEmpty())
+	})
+
+	It("should create the application in the fallback IAS tenant once the creation failed for the failover delay", func() {
+		// given
+		primaryClient.setCreationErr(errIASApplicationCreation)
+
+		// when
+		eventingAuth = createEventingAuthInIASTenant(crName, tenants[0].Name)
+
+		// then
+		verifyEventingAuthStatusNotReadyAppCreationFailed(eventingAuth)
+		Expect(fallbackClient.createdApplications()).To(BeEmpty())
+
+		// then after the failover delay
+		verifyEventingAuthStatusReady(eventingAuth)
+		verifyApplicationTenant(eventingAuth, tenants[1].Name)
+		Expect(fallbackClient.createdApplications()).To(ConsistOf(crName))
+		verifyApplicationSecretClientID("client-id-for-" + crName)
+
+		// when
+		deleteEventingAuthAndVerify(eventingAuth)
+
+		// then the application is deleted in the tenant that owns it
+		Expect(fallbackClient.deletedApplications()).To(ContainElement(crName))
+		Expect(primaryClient.deletedApplications()).To(BeEmpty())
+	})
+})
+
+// iasTenantURL returns the URL of the IAS tenant with the given name, so that the IAS client stubs can be assigned to the tenants.
+func iasTenantURL(name string) string {
+	return fmt.Sprintf("https://%s.accounts.ondemand.com", name)
+}
+
+// createIASTenant creates the IASTenant CR with the given fallback tenant and the secret with its credentials.
+func createIASTenant(name, fallbackTenant string) *eamapiv1alpha1.IASTenant {
+	tenant := &eamapiv1alpha1.IASTenant{
+		ObjectMeta: kmetav1.ObjectMeta{Name: name},
+		Spec: eamapiv1alpha1.IASTenantSpec{
+			URL:                  iasTenantURL(name),
+			CredentialsSecretRef: eamapiv1alpha1.SecretReference{Namespace: skr.KcpNamespace, Name: "ias-tenant-" + name},
+			FallbackTenant:       fallbackTenant,
+		},
+	}
+	By(fmt.Sprintf("Creating IASTenant CR %s", name))
+	Expect(k8sClient.Create(context.TODO(), &kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: tenant.Spec.CredentialsSecretRef.Namespace, Name: tenant.Spec.CredentialsSecretRef.Name},
+		Data:       map[string][]byte{"username": []byte("user"), "password": []byte("password")},
+	})).Should(Succeed())
+	Expect(k8sClient.Create(context.TODO(), tenant)).Should(Succeed())
+	return tenant
+}
+
+func deleteIASTenant(tenant *eamapiv1alpha1.IASTenant) {
+	By(fmt.Sprintf("Deleting IASTenant CR %s", tenant.Name))
+	Expect(kpkgclient.IgnoreNotFound(k8sClient.Delete(context.TODO(), tenant))).Should(Succeed())
+	Expect(kpkgclient.IgnoreNotFound(k8sClient.Delete(context.TODO(), &kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: tenant.Spec.CredentialsSecretRef.Namespace, Name: tenant.Spec.CredentialsSecretRef.Name},
+	}))).Should(Succeed())
+}
+
+func createEventingAuthInIASTenant(name, tenant string) *eamapiv1alpha1.EventingAuth {
+	e := eamapiv1alpha1.EventingAuth{
+		ObjectMeta: kmetav1.ObjectMeta{Name: name, Namespace: skr.KcpNamespace},
+		Spec:       eamapiv1alpha1.EventingAuthSpec{IAS: &eamapiv1alpha1.IASProviderSpec{Tenant: tenant}},
+	}
+
+	By(fmt.Sprintf("Creating EventingAuth CR in IAS tenant %s", tenant))
+	Expect(k8sClient.Create(context.TODO(), &e)).Should(Succeed())
+
+	return &e
+}
+
+func verifyApplicationTenant(cr *eamapiv1alpha1.EventingAuth, tenant string) {
+	By(fmt.Sprintf("Verifying that application of EventingAuth %s is owned by IAS tenant %s", cr.Name, tenant))
+	Eventually(func(g Gomega) {
+		e := eamapiv1alpha1.EventingAuth{}
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), &e)).Should(Succeed())
+		g.Expect(e.Status.Application).NotTo(BeNil())
+		g.Expect(e.Status.Application.Tenant).To(Equal(tenant))
+	}, defaultTimeout).Should(Succeed())
+}
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultStuckReconcileThreshold is the default duration after which a reconciliation that didn't return is considered stuck.
+	DefaultStuckReconcileThreshold = 10 * time.Minute
+	// DefaultIASReadinessInterval is the default interval in which the IAS tenants are probed for the readiness check.
+	DefaultIASReadinessInterval = time.Minute
+	// iasReadinessProbeTimeout is the timeout of the probe of a single IAS tenant.
+	iasReadinessProbeTimeout = 10 * time.Second
+)
+
+// inFlightReconciles tracks the start of the reconciliations that are in progress, so that stuck reconciliations can be detected.
+var inFlightReconciles = &reconcileTracker{started: map[string]time.Time{}} //nolint:gochecknoglobals // Shared by the controllers of the manager.
+
+// reconcileTracker stores the start of the reconciliations that are in progress by the controller and the request.
+type reconcileTracker struct {
+	mu      sync.Mutex
+	started map[string]time.Time
+}
+
+// start records the start of the reconciliation of the request by the controller. The returned function records its end.
+func (t *reconcileTracker) start(controller string, req kcontrollerruntime.Request) func() {
+	// Since a request is never reconciled concurrently by the workers of a controller, the key is unique.
+	key := controller + "/" + req.String()
+	t.mu.Lock()
+	t.started[key] = time.Now()
+	t.mu.Unlock()
+	return func() {
+		t.mu.Lock()
+		delete(t.started, key)
+		t.mu.Unlock()
+	}
+}
+
+// oldest returns the reconciliation that is in progress for the longest time, and when it started.
+func (t *reconcileTracker) oldest() (string, time.Time, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var oldestKey string
+	var oldestStart time.Time
+	for key, start := range t.started {
+		if oldestKey == "" || start.Before(oldestStart) {
+			oldestKey, oldestStart = key, start
+		}
+	}
+	return oldestKey, oldestStart, oldestKey != ""
+}
+
+// StuckReconcileCheck returns a liveness check that fails if a reconciliation of any controller hasn't returned for longer than the
+// threshold, e.g. because a request to an identity provider or an SKR cluster hangs without deadline. Since a stuck worker never picks up
+// another item, the manager is restarted by the failed liveness probe instead.
+func StuckReconcileCheck(threshold time.Duration) healthz.Checker {
+	return func(_ *http.Request) error {
+		key, start, ok := inFlightReconciles.oldest()
+		if !ok {
+			return nil
+		}
+		if running := time.Since(start); running > threshold {
+			return errors.Errorf("reconciliation %s is running for %s, which is longer than %s", key, running.Round(time.Second), threshold)
+		}
+		return nil
+	}
+}
+
+// IASReadinessCheck probes the IAS tenants of the manager in an interval, which are the tenant of the credentials secret of the manager
+// and the tenants of the IASTenant CRs, and reports the results of the last probes as readiness check. A manager whose credentials are
+// invalid or whose tenants are unreachable therefore doesn't report ready, instead of failing all provisioning silently. The probes are
+// cached, so that the readiness probes of the kubelet don't send requests to the tenants. The check must be added to the manager to start
+// probing.
+type IASReadinessCheck struct {
+	client   kpkgclient.Client
+	pool     *provider.IASClientPool
+	interval time.Duration
+
+	mu     sync.Mutex
+	probed bool
+	err    error
+}
+
+// NewIASReadinessCheck returns a readiness check that probes the IAS tenants of the pool in the interval, and lists the IASTenant CRs with
+// the client.
+func NewIASReadinessCheck(c kpkgclient.Client, pool *provider.IASClientPool, interval time.Duration) *IASReadinessCheck {
+	if interval <= 0 {
+		interval = DefaultIASReadinessInterval
+	}
+	return &IASReadinessCheck{client: c, pool: pool, interval: interval}
+}
+
+// NeedLeaderElection returns false, since each replica of the manager reports its own readiness.
+func (c *IASReadinessCheck) NeedLeaderElection() bool {
+	return false
+}
+
+// Start probes the IAS tenants right away and then in the interval until the context is done.
+func (c *IASReadinessCheck) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("ias-readiness")
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		err := c.probe(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Error(err, "IAS tenants are not ready")
+		}
+		c.mu.Lock()
+		c.probed, c.err = true, err
+		c.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check returns the error of the last probes of the IAS tenants.
+func (c *IASReadinessCheck) Check(_ *http.Request) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.probed {
+		return errors.New("IAS tenants were not probed yet")
+	}
+	return c.err
+}
+
+// probe probes the IAS tenants and returns the failures of all tenants. The tenant of the credentials secret of the manager is skipped if
+// the secret doesn't exist, since the manager can be operated with IASTenant CRs only.
+func (c *IASReadinessCheck) probe(ctx context.Context) error {
+	var tenants eamapiv1alpha1.IASTenantList
+	if err := c.client.List(ctx, &tenants); err != nil {
+		return errors.Wrap(err, "failed to list IAS tenants")
+	}
+	names := []string{""}
+	for _, tenant := range tenants.Items {
+		names = append(names, tenant.Name)
+	}
+	sort.Strings(names)
+
+	var failures []string
+	for _, name := range names {
+		err := c.probeTenant(ctx, name)
+		switch {
+		case err == nil:
+		case name == "":
+			if !kapierrors.IsNotFound(errors.Cause(err)) {
+				failures = append(failures, "IAS tenant of the credentials secret: "+redact.String(err.Error()))
+			}
+		default:
+			failures = append(failures, "IAS tenant "+name+": "+redact.String(err.Error()))
+		}
+	}
+	if len(failures) > 0 {
+		return errors.New(strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+func (c *IASReadinessCheck) probeTenant(ctx context.Context, tenant string) error {
+	ctx, cancel := context.WithTimeout(ctx, iasReadinessProbeTimeout)
+	defer cancel()
+	iasClient, err := c.pool.Get(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	return iasClient.Probe(ctx)
+}
@@ -0,0 +1,288 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/audit"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	iasApplicationFinalizerName = "iasapplication.operator.kyma-project.io/finalizer"
+	// iasApplicationUIDLength is the number of characters of the UID of the IASApplication CR in the default name of its application.
+	iasApplicationUIDLength = 8
+)
+
+// iasApplicationReconciler reconciles IASApplication CRs, which request applications in the identity providers independently of Kyma
+// Eventing. The credentials of an application are written to a secret in the namespace of its IASApplication CR.
+type iasApplicationReconciler struct {
+	kpkgclient.Client
+	Scheme *runtime.Scheme
+	// providers contains the identity providers that the applications are managed in.
+	providers *provider.Registry
+	// recorder emits the events of the IASApplication CRs.
+	recorder record.EventRecorder
+	// auditor records the identity operations in the audit log. It is nil if no audit sink is configured.
+	auditor *audit.Recorder
+}
+
+// NewIASApplicationReconciler returns the reconciler of IASApplication CRs. The applications are managed in the provider of the registry
+// that is requested by the IASApplication CR, or in its default provider. The events of the IASApplication CRs are emitted with the
+// recorder, and the creations and deletions of the applications and the writes of their credentials are recorded with the auditor, if it
+// isn't nil.
+func NewIASApplicationReconciler(c kpkgclient.Client, s *runtime.Scheme, providers *provider.Registry, recorder record.EventRecorder,
+	auditor *audit.Recorder,
+) ManagedReconciler {
+	return &iasApplicationReconciler{
+		Client:    c,
+		Scheme:    s,
+		providers: providers,
+		recorder:  redact.EventRecorder(recorder),
+		auditor:   auditor,
+	}
+}
+
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=iasapplications,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=iasapplications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=iasapplications/finalizers,verbs=update
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=eventingauths,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update
+func (r *iasApplicationReconciler) Reconcile(ctx context.Context, req kcontrollerruntime.Request) (kcontrollerruntime.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Reconciling IASApplication")
+
+	var cr eamapiv1alpha1.IASApplication
+	if err := r.Client.Get(ctx, req.NamespacedName, &cr); err != nil {
+		return kcontrollerruntime.Result{}, kpkgclient.IgnoreNotFound(err)
+	}
+	if cr.Status.Application != nil && cr.Status.Application.UUID != "" {
+		ctx = eamias.WithApplicationID(ctx, cr.Status.Application.UUID)
+	}
+	p, err := r.providers.Get(ctx, iasApplicationProviderSpec(cr))
+	if err != nil {
+		return kcontrollerruntime.Result{}, err
+	}
+
+	if !cr.DeletionTimestamp.IsZero() {
+		logger.Info("Handling deletion")
+		return kcontrollerruntime.Result{}, r.handleDeletion(ctx, p, &cr)
+	}
+	if !controllerutil.ContainsFinalizer(&cr, iasApplicationFinalizerName) {
+		controllerutil.AddFinalizer(&cr, iasApplicationFinalizerName)
+		if err := r.Update(ctx, &cr); err != nil {
+			return kcontrollerruntime.Result{}, errors.Wrap(err, "failed to add finalizer")
+		}
+	}
+
+	var secret kcorev1.Secret
+	err = r.Client.Get(ctx, kpkgclient.ObjectKey{Namespace: cr.Namespace, Name: cr.Spec.SecretName}, &secret)
+	switch {
+	case err == nil && !kmetav1.IsControlledBy(&secret, &cr):
+		// A secret of another owner is never overwritten with the credentials.
+		err = errors.Errorf("secret %s/%s isn't managed by the IASApplication CR", cr.Namespace, cr.Spec.SecretName)
+		if statusErr := r.updateStatus(ctx, &cr, *cr.Status.DeepCopy(), eamapiv1alpha1.ConditionSecretReady, err); statusErr != nil {
+			return kcontrollerruntime.Result{}, statusErr
+		}
+		return kcontrollerruntime.Result{}, err
+	case err == nil && cr.Status.Application != nil:
+		// The credentials of the existing application can't be read again, so the application is only created again if its secret is missing.
+		logger.Info("Reconciliation done, credentials secret already exists")
+		return kcontrollerruntime.Result{}, nil
+	case kpkgclient.IgnoreNotFound(err) != nil:
+		return kcontrollerruntime.Result{}, errors.Wrap(err, "failed to get credentials secret")
+	}
+
+	if shuttingDown(ctx) {
+		logger.Info("Not creating application, because the manager stops")
+		return kcontrollerruntime.Result{Requeue: true}, nil
+	}
+	if err := r.validateApplication(ctx, cr); err != nil {
+		if statusErr := r.updateStatus(ctx, &cr, *cr.Status.DeepCopy(), eamapiv1alpha1.ConditionApplicationReady, err); statusErr != nil {
+			return kcontrollerruntime.Result{}, statusErr
+		}
+		return kcontrollerruntime.Result{}, err
+	}
+	if auth := cr.Spec.Auth; auth != nil {
+		ctx = eamias.WithAuthConfig(ctx, eamias.AuthConfig{GrantTypes: auth.GrantTypes, TokenValiditySeconds: int(auth.TokenValiditySeconds)})
+	}
+	return kcontrollerruntime.Result{}, r.createApplication(ctx, p, &cr)
+}
+
+// validateApplication rejects an authentication that the provider of the IASApplication CR doesn't support, and an application name that is
+// used by another IASApplication CR or EventingAuth CR, since the existing application with the name would be replaced by the creation.
+func (r *iasApplicationReconciler) validateApplication(ctx context.Context, cr eamapiv1alpha1.IASApplication) error {
+	if name := r.providers.Name(iasApplicationProviderSpec(cr)); cr.Spec.Auth != nil && name != provider.IASName {
+		return &provider.ValidationError{Message: fmt.Sprintf("spec.auth is only supported by provider %s, but the application is managed "+
+			"in provider %s", provider.IASName, name)}
+	}
+	name := IASApplicationName(cr)
+	var iasApplications eamapiv1alpha1.IASApplicationList
+	if err := r.Client.List(ctx, &iasApplications); err != nil {
+		return errors.Wrap(err, "failed to list IASApplication CRs")
+	}
+	for _, other := range iasApplications.Items {
+		if other.UID != cr.UID && IASApplicationName(other) == name {
+			return &provider.ValidationError{Message: fmt.Sprintf("application %s is owned by IASApplication CR %s/%s", name, other.Namespace,
+				other.Name)}
+		}
+	}
+	var eventingAuths eamapiv1alpha1.EventingAuthList
+	if err := r.Client.List(ctx, &eventingAuths); err != nil {
+		return errors.Wrap(err, "failed to list EventingAuth CRs")
+	}
+	for _, eventingAuth := range eventingAuths.Items {
+		if ownsApplicationName(eventingAuth, name) {
+			return &provider.ValidationError{Message: fmt.Sprintf("application %s is owned by EventingAuth CR %s/%s", name,
+				eventingAuth.Namespace, eventingAuth.Name)}
+		}
+	}
+	return nil
+}
+
+// ownsApplicationName returns true if the EventingAuth CR names one of its applications with the name, which are its application, the
+// separate application of the event publisher, and the dedicated applications of the event consumers.
+func ownsApplicationName(cr eamapiv1alpha1.EventingAuth, name string) bool {
+	return name == applicationName(cr) || name == cr.Name+publisherApplicationSuffix || strings.HasPrefix(name, cr.Name+".")
+}
+
+// createApplication creates the application of the IASApplication CR, replacing an existing application with the same name, which can only
+// be an application of the IASApplication CR itself, and writes its credentials to the secret of the IASApplication CR.
+func (r *iasApplicationReconciler) createApplication(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.IASApplication) error {
+	name := IASApplicationName(*cr)
+	tenant := requestedIASTenant(iasApplicationProviderSpec(*cr))
+	app, err := createApplicationInIASTenant(ctx, r.Client, p, tenant, name)
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionCreateApplication, Application: name, ApplicationID: app.GetID(), Tenant: tenant},
+		err)
+	if err != nil {
+		if statusErr := r.updateStatus(ctx, cr, *cr.Status.DeepCopy(), eamapiv1alpha1.ConditionApplicationReady, err); statusErr != nil {
+			return statusErr
+		}
+		return errors.Wrap(err, "failed to create application")
+	}
+	log.FromContext(ctx).Info("Created application", "application", name, "applicationID", app.GetID())
+	r.recorder.Eventf(cr, kcorev1.EventTypeNormal, eventReasonApplicationCreated, "Created application %s%s", name, inIASTenant(tenant))
+	existingStatus := *cr.Status.DeepCopy()
+	now := kmetav1.Now()
+	cr.Status.Application = &eamapiv1alpha1.ClusterApplication{Name: name, UUID: app.GetID(), Tenant: tenant, CredentialsIssuedTime: &now}
+	if err := r.updateStatus(ctx, cr, existingStatus, eamapiv1alpha1.ConditionApplicationReady, nil); err != nil {
+		return err
+	}
+
+	secret := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: cr.Namespace, Name: cr.Spec.SecretName}}
+	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Labels = map[string]string{skr.ManagedByLabelKey: skr.ManagedByLabelValue}
+		secret.Data = app.Credentials()
+		return controllerutil.SetControllerReference(cr, secret, r.Scheme)
+	})
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionWriteSecret, Application: name, ApplicationID: app.GetID(), Tenant: tenant,
+		Target: auditTargetKCP + ":" + cr.Namespace + "/" + cr.Spec.SecretName}, err)
+	if err != nil {
+		err = errors.Wrap(err, "failed to write credentials secret")
+		if statusErr := r.updateStatus(ctx, cr, *cr.Status.DeepCopy(), eamapiv1alpha1.ConditionSecretReady, err); statusErr != nil {
+			return statusErr
+		}
+		return err
+	}
+	r.recorder.Eventf(cr, kcorev1.EventTypeNormal, eventReasonSecretSynced, "Wrote credentials of application %s to secret %s", name,
+		cr.Spec.SecretName)
+	return r.updateStatus(ctx, cr, *cr.Status.DeepCopy(), eamapiv1alpha1.ConditionSecretReady, nil)
+}
+
+// handleDeletion deletes the application that the IASApplication CR created and removes the finalizer. The credentials secret is deleted by
+// the garbage collector, since it is owned by the IASApplication CR.
+func (r *iasApplicationReconciler) handleDeletion(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.IASApplication) error {
+	if !controllerutil.ContainsFinalizer(cr, iasApplicationFinalizerName) {
+		return nil
+	}
+	// An IASApplication CR without an application in its status never created one, and an application with its name belongs to another CR.
+	if cr.Status.Application != nil {
+		name := IASApplicationName(*cr)
+		err := p.DeleteApplication(ctx, name)
+		r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteApplication, Application: name,
+			ApplicationID: cr.Status.Application.UUID, Tenant: requestedIASTenant(iasApplicationProviderSpec(*cr))}, err)
+		if err != nil {
+			return errors.Wrap(err, "failed to delete application")
+		}
+	}
+	controllerutil.RemoveFinalizer(cr, iasApplicationFinalizerName)
+	if err := r.Update(ctx, cr); err != nil {
+		return errors.Wrap(err, "failed to remove finalizer")
+	}
+	return nil
+}
+
+// updateStatus updates the condition and the state of the IASApplication CR based on the given error, and writes the status if it differs
+// from the existing status.
+func (r *iasApplicationReconciler) updateStatus(ctx context.Context, cr *eamapiv1alpha1.IASApplication,
+	existingStatus eamapiv1alpha1.IASApplicationStatus, conditionType eamapiv1alpha1.ConditionType, errToCheck error,
+) error {
+	status, err := eamapiv1alpha1.UpdateIASApplicationConditionAndState(cr, conditionType,
+		eamapiv1alpha1.WithCorrelationID(errToCheck, tracing.CorrelationID(ctx)))
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(existingStatus, status) {
+		return nil
+	}
+	if err := r.Client.Status().Update(ctx, cr); err != nil {
+		if kapierrors.IsConflict(err) {
+			return errors.Wrap(err, "IASApplication CR changed, reconciling again")
+		}
+		return errors.Wrap(err, "failed to update IASApplication status")
+	}
+	return nil
+}
+
+// IASApplicationName returns the name of the application of the IASApplication CR in the identity provider. The name of a created
+// application is kept, so that the application is still found if the default name changes.
+func IASApplicationName(cr eamapiv1alpha1.IASApplication) string {
+	switch {
+	case cr.Status.Application != nil && cr.Status.Application.Name != "":
+		return cr.Status.Application.Name
+	case cr.Spec.ApplicationName != "":
+		return cr.Spec.ApplicationName
+	}
+	// The names of the CRs are only unique in their namespace, and the UID distinguishes a recreated CR from the deleted one.
+	uid := string(cr.UID)
+	if len(uid) > iasApplicationUIDLength {
+		uid = uid[:iasApplicationUIDLength]
+	}
+	return cr.Namespace + "-" + cr.Name + "-" + uid
+}
+
+// iasApplicationProviderSpec returns the spec that the provider of the application of the IASApplication CR is requested with.
+func iasApplicationProviderSpec(cr eamapiv1alpha1.IASApplication) eamapiv1alpha1.EventingAuthSpec {
+	return eamapiv1alpha1.EventingAuthSpec{Provider: cr.Spec.Provider, IAS: cr.Spec.IAS}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *iasApplicationReconciler) SetupWithManager(mgr kcontrollerruntime.Manager) error {
+	return kcontrollerruntime.NewControllerManagedBy(mgr).
+		For(&eamapiv1alpha1.IASApplication{}).
+		Owns(&kcorev1.Secret{}).
+		Complete(r.InstrumentedReconciler())
+}
+
+// InstrumentedReconciler returns the reconciler as it is started by the controller.
+func (r *iasApplicationReconciler) InstrumentedReconciler() reconcile.Reconciler {
+	return instrument(iasApplicationControllerName, r)
+}
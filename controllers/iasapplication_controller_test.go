@@ -0,0 +1,138 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IASApplication Controller", Serial, Ordered, func() {
+	var (
+		iasClient       *recordingIasClientStub
+		iasApplications []*eamapiv1alpha1.IASApplication
+	)
+
+	BeforeAll(func() {
+		iasClient = &recordingIasClientStub{}
+		stubIasAppCreation(iasClient)
+	})
+
+	AfterAll(func() {
+		revertReadCredentialsStub()
+		revertIasNewClientStub()
+	})
+
+	AfterEach(func() {
+		for _, iasApplication := range iasApplications {
+			deleteIASApplicationAndVerify(iasApplication)
+			// The envtest cluster doesn't run the garbage collector, which deletes the owned secret in a real cluster.
+			secret := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: iasApplication.Namespace, Name: iasApplication.Spec.SecretName}}
+			Expect(kpkgclient.IgnoreNotFound(k8sClient.Delete(context.TODO(), secret))).Should(Succeed())
+		}
+		iasApplications = nil
+	})
+
+	It("should create the application with a unique name and delete it with the CR", func() {
+		// given
+		iasApplication := createIASApplication("")
+		iasApplications = append(iasApplications, iasApplication)
+		name := fmt.Sprintf("%s-%s-%s", iasApplication.Namespace, iasApplication.Name, string(iasApplication.UID)[:8])
+
+		// then
+		verifyIASApplicationState(iasApplication, eamapiv1alpha1.StateReady, eamapiv1alpha1.ConditionApplicationReady,
+			eamapiv1alpha1.ConditionReasonApplicationCreated)
+		Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(iasApplication), iasApplication)).Should(Succeed())
+		Expect(iasApplication.Status.Application).NotTo(BeNil())
+		Expect(iasApplication.Status.Application.Name).To(Equal(name))
+		Expect(iasApplication.Status.Application.UUID).To(Equal("id-for-" + name))
+		Expect(iasClient.createdApplications()).To(ContainElement(name))
+
+		var secret kcorev1.Secret
+		Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Namespace: iasApplication.Namespace, Name: iasApplication.Spec.SecretName},
+			&secret)).Should(Succeed())
+		Expect(kmetav1.IsControlledBy(&secret, iasApplication)).To(BeTrue())
+		Expect(string(secret.Data[eamias.SecretKeyClientID])).To(Equal("client-id-for-" + name))
+
+		// when
+		deleteIASApplicationAndVerify(iasApplication)
+
+		// then
+		Expect(iasClient.deletedApplications()).To(ContainElement(name))
+	})
+
+	It("should refuse an application name that is owned by another IASApplication CR", func() {
+		// given
+		name := "shared-" + generateCrName()
+		owner := createIASApplication(name)
+		iasApplications = append(iasApplications, owner)
+		verifyIASApplicationState(owner, eamapiv1alpha1.StateReady, eamapiv1alpha1.ConditionApplicationReady,
+			eamapiv1alpha1.ConditionReasonApplicationCreated)
+		created := iasClient.createdApplications()
+
+		// when
+		iasApplication := createIASApplication(name)
+		iasApplications = append(iasApplications, iasApplication)
+
+		// then
+		verifyIASApplicationState(iasApplication, eamapiv1alpha1.StateNotReady, eamapiv1alpha1.ConditionApplicationReady,
+			eamapiv1alpha1.ConditionReasonApplicationCreationFailed)
+		Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(iasApplication), iasApplication)).Should(Succeed())
+		condition := kmeta.FindStatusCondition(iasApplication.Status.Conditions, string(eamapiv1alpha1.ConditionApplicationReady))
+		Expect(condition.Message).To(HavePrefix(fmt.Sprintf("application %s is owned by IASApplication CR %s/%s", name, owner.Namespace,
+			owner.Name)))
+		Expect(iasClient.createdApplications()).To(Equal(created))
+		err := k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Namespace: iasApplication.Namespace, Name: iasApplication.Spec.SecretName},
+			&kcorev1.Secret{})
+		Expect(kapierrors.IsNotFound(err)).To(BeTrue())
+
+		// when
+		deleteIASApplicationAndVerify(iasApplication)
+
+		// then the application of the owner is kept
+		Expect(iasClient.deletedApplications()).NotTo(ContainElement(name))
+	})
+})
+
+func createIASApplication(applicationName string) *eamapiv1alpha1.IASApplication {
+	iasApplication := &eamapiv1alpha1.IASApplication{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: skr.KcpNamespace, Name: generateCrName()},
+		Spec:       eamapiv1alpha1.IASApplicationSpec{ApplicationName: applicationName, SecretName: generateCrName()},
+	}
+	By(fmt.Sprintf("Creating IASApplication CR %s", iasApplication.Name))
+	Expect(k8sClient.Create(context.TODO(), iasApplication)).Should(Succeed())
+	return iasApplication
+}
+
+func verifyIASApplicationState(iasApplication *eamapiv1alpha1.IASApplication, state eamapiv1alpha1.State,
+	conditionType eamapiv1alpha1.ConditionType, reason string,
+) {
+	By(fmt.Sprintf("Verifying state %s of IASApplication CR %s", state, iasApplication.Name))
+	Eventually(func(g Gomega) {
+		a := &eamapiv1alpha1.IASApplication{}
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(iasApplication), a)).Should(Succeed())
+		g.Expect(a.Status.State).To(Equal(state))
+		condition := kmeta.FindStatusCondition(a.Status.Conditions, string(conditionType))
+		g.Expect(condition).NotTo(BeNil())
+		g.Expect(condition.Reason).To(Equal(reason))
+	}, defaultTimeout).Should(Succeed())
+}
+
+func deleteIASApplicationAndVerify(iasApplication *eamapiv1alpha1.IASApplication) {
+	By(fmt.Sprintf("Deleting IASApplication CR %s", iasApplication.Name))
+	Expect(kpkgclient.IgnoreNotFound(k8sClient.Delete(context.TODO(), iasApplication))).Should(Succeed())
+	Eventually(func(g Gomega) {
+		err := k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(iasApplication), &eamapiv1alpha1.IASApplication{})
+		g.Expect(kapierrors.IsNotFound(err)).To(BeTrue())
+	}, defaultTimeout).Should(Succeed())
+}
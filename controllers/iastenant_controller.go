@@ -0,0 +1,294 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	kcorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// defaultApplicationQuotaWarningPercentage is the percentage of the application quota of a tenant from which on warning events are emitted.
+	defaultApplicationQuotaWarningPercentage = 90
+	eventReasonApplicationQuotaAlmostReached = "ApplicationQuotaAlmostReached"
+	eventReasonApplicationQuotaReached       = "ApplicationQuotaReached"
+)
+
+// iasTenantHealthCheckInterval is the interval in which the health of an IAS tenant is checked again.
+const iasTenantHealthCheckInterval = time.Minute * 5
+
+// iasTenantProbeBackoff retries a failed probe of an IAS tenant, so that a single failed request doesn't mark the tenant as not ready.
+var iasTenantProbeBackoff = wait.Backoff{ //nolint:gochecknoglobals // Read-only backoff of the probes.
+	Steps:    3,
+	Duration: time.Second,
+	Factor:   2,
+}
+
+// iasTenantReady reports the result of the last health check of the IAS tenants of the IASTenant CRs.
+var iasTenantReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_ias_tenant_ready",
+	Help: "Whether the IAS tenant of the IASTenant CR is reachable with its credentials (1) or not (0).",
+}, []string{"tenant"})
+
+// iasTenantApplications reports the number of applications that are owned by the IAS tenants of the IASTenant CRs.
+var iasTenantApplications = prometheus.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_ias_tenant_applications",
+	Help: "Number of applications of the EventingAuth CRs that are owned by the IAS tenant of the IASTenant CR.",
+}, []string{"tenant"})
+
+// iasTenantApplicationQuota reports the application quota of the IAS tenants of the IASTenant CRs that have a quota.
+var iasTenantApplicationQuota = prometheus.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_ias_tenant_application_quota",
+	Help: "Maximum number of applications of the EventingAuth CRs in the IAS tenant of the IASTenant CR.",
+}, []string{"tenant"})
+
+// iasTenantMaintenance reports whether the IAS tenants of the IASTenant CRs are in maintenance.
+var iasTenantMaintenance = prometheus.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_ias_tenant_maintenance",
+	Help: "Whether the IAS tenant of the IASTenant CR is in maintenance (1) or not (0), because it responds with 503 for longer than the " +
+		"maintenance threshold.",
+}, []string{"tenant"})
+
+func init() { //nolint:gochecknoinits // Metrics are registered on the package level.
+	metrics.Registry.MustRegister(iasTenantReady, iasTenantApplications, iasTenantApplicationQuota, iasTenantMaintenance)
+}
+
+// iasTenantReconciler checks the health of the IAS tenants of the IASTenant CRs with the clients of the pool, that the applications are
+// managed with.
+type iasTenantReconciler struct {
+	kpkgclient.Client
+	Scheme *runtime.Scheme
+	pool   *provider.IASClientPool
+	// httpClient is the client of the requests to the Cloud Management Service, that the URLs of the tenants of subaccounts are discovered with.
+	httpClient *http.Client
+	// recorder emits the warning events of tenants that approach their application quota.
+	recorder record.EventRecorder
+}
+
+// NewIASTenantReconciler returns the reconciler of IASTenant CRs, which reports the health of the IAS tenants in the status of the CRs. The
+// URLs of the tenants of subaccounts are discovered with the HTTP client and stored in the status of the CRs. The applications of the tenants
+// are counted, and warning events are emitted with the recorder for tenants that approach their application quota.
+func NewIASTenantReconciler(c kpkgclient.Client, s *runtime.Scheme, pool *provider.IASClientPool, httpClient *http.Client,
+	recorder record.EventRecorder,
+) ManagedReconciler {
+	return &iasTenantReconciler{
+		Client:     c,
+		Scheme:     s,
+		pool:       pool,
+		httpClient: httpClient,
+		recorder:   redact.EventRecorder(recorder),
+	}
+}
+
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=iastenants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=iastenants/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+func (r *iasTenantReconciler) Reconcile(ctx context.Context, req kcontrollerruntime.Request) (kcontrollerruntime.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("Reconciling IASTenant")
+
+	var tenant eamapiv1alpha1.IASTenant
+	if err := r.Client.Get(ctx, req.NamespacedName, &tenant); err != nil {
+		if kpkgclient.IgnoreNotFound(err) == nil {
+			iasTenantReady.DeleteLabelValues(req.Name)
+			iasTenantApplications.DeleteLabelValues(req.Name)
+			iasTenantApplicationQuota.DeleteLabelValues(req.Name)
+			iasTenantMaintenance.DeleteLabelValues(req.Name)
+		}
+		return kcontrollerruntime.Result{}, kpkgclient.IgnoreNotFound(err)
+	}
+
+	var healthErr error
+	if tenant.Spec.URL == "" && tenant.Spec.Subaccount != nil {
+		tenantURL, err := provider.ResolveIASTenantURL(ctx, r.httpClient, r.Client, *tenant.Spec.Subaccount)
+		if err == nil && tenantURL != tenant.Status.URL {
+			// The client of the tenant is created with the URL of the status, so the health is checked after the status was updated.
+			logger.Info("Discovered URL of IAS tenant", "url", tenantURL)
+			tenant.Status.URL = tenantURL
+			return kcontrollerruntime.Result{Requeue: true}, r.Client.Status().Update(ctx, &tenant)
+		}
+		healthErr = errors.Wrap(err, "failed to discover the URL of the IAS tenant")
+	}
+	if healthErr == nil {
+		healthErr = r.checkHealth(ctx, tenant.Name)
+	}
+	maintenanceErr := iasMaintenanceErrorOf(r.pool, tenant.Name, healthErr)
+	switch {
+	case maintenanceErr != nil:
+		// The maintenance ends with the first health check that isn't answered with 503.
+		logger.Info("IAS tenant is in maintenance", "error", redact.String(maintenanceErr.Error()))
+		healthErr = maintenanceErr
+		iasTenantReady.WithLabelValues(tenant.Name).Set(0)
+	case healthErr != nil:
+		logger.Error(healthErr, "IAS tenant is not healthy")
+		iasTenantReady.WithLabelValues(tenant.Name).Set(0)
+	default:
+		iasTenantReady.WithLabelValues(tenant.Name).Set(1)
+	}
+	if maintenanceErr != nil {
+		iasTenantMaintenance.WithLabelValues(tenant.Name).Set(1)
+	} else {
+		iasTenantMaintenance.WithLabelValues(tenant.Name).Set(0)
+	}
+
+	applications, err := countIASApplications(ctx, r.Client, tenant.Name)
+	if err != nil {
+		return kcontrollerruntime.Result{}, err
+	}
+	r.reportApplications(ctx, &tenant, applications)
+
+	existingStatus := tenant.Status.DeepCopy()
+	tenant.Status.Applications = applications
+	status := eamapiv1alpha1.UpdateIASTenantConditionAndState(&tenant, eamapiv1alpha1.WithCorrelationID(healthErr, tracing.CorrelationID(ctx)))
+	if status.State != existingStatus.State || status.Applications != existingStatus.Applications ||
+		!eamapiv1alpha1.ConditionsEqual(existingStatus.Conditions, status.Conditions) {
+		if err := r.Client.Status().Update(ctx, &tenant); err != nil {
+			return kcontrollerruntime.Result{}, err
+		}
+	}
+	// The health is checked periodically, since the availability of the tenant doesn't trigger a reconciliation.
+	return kcontrollerruntime.Result{RequeueAfter: iasTenantHealthCheckInterval}, nil
+}
+
+// checkHealth checks that the credentials of the tenant can be read, and probes the OIDC discovery endpoint and the Applications API of the
+// tenant with the credentials. A failed probe is retried with iasTenantProbeBackoff.
+func (r *iasTenantReconciler) checkHealth(ctx context.Context, tenant string) error {
+	iasClient, err := r.pool.Get(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	return retry.OnError(iasTenantProbeBackoff, func(error) bool { return ctx.Err() == nil }, func() error {
+		return iasClient.Probe(ctx)
+	})
+}
+
+// reportApplications exports the number of applications and the application quota of the tenant as metrics, and emits a warning event if
+// the tenant approaches or reached its application quota.
+func (r *iasTenantReconciler) reportApplications(ctx context.Context, tenant *eamapiv1alpha1.IASTenant, applications int32) {
+	iasTenantApplications.WithLabelValues(tenant.Name).Set(float64(applications))
+	quota := tenant.Spec.ApplicationQuota
+	if quota == nil {
+		iasTenantApplicationQuota.DeleteLabelValues(tenant.Name)
+		return
+	}
+	iasTenantApplicationQuota.WithLabelValues(tenant.Name).Set(float64(quota.Limit))
+
+	warningPercentage := quota.WarningPercentage
+	if warningPercentage == 0 {
+		warningPercentage = defaultApplicationQuotaWarningPercentage
+	}
+	correlationID := tracing.CorrelationID(ctx)
+	switch {
+	case applications >= quota.Limit:
+		r.recorder.Event(tenant, kcorev1.EventTypeWarning, eventReasonApplicationQuotaReached, eamapiv1alpha1.AppendCorrelationID(
+			fmt.Sprintf("IAS tenant reached its quota with %d of %d applications", applications, quota.Limit), correlationID))
+	case int64(applications)*100 >= int64(quota.Limit)*int64(warningPercentage):
+		r.recorder.Event(tenant, kcorev1.EventTypeWarning, eventReasonApplicationQuotaAlmostReached, eamapiv1alpha1.AppendCorrelationID(
+			fmt.Sprintf("IAS tenant approaches its quota with %d of %d applications", applications, quota.Limit), correlationID))
+	}
+}
+
+// countIASApplications returns the number of applications of the EventingAuth CRs that are owned by the IAS tenant with the given name.
+func countIASApplications(ctx context.Context, c kpkgclient.Client, tenant string) (int32, error) {
+	var eventingAuths eamapiv1alpha1.EventingAuthList
+	if err := c.List(ctx, &eventingAuths); err != nil {
+		return 0, errors.Wrap(err, "failed to list EventingAuth CRs")
+	}
+	var applications int32
+	// A shared application is counted once for all EventingAuth CRs of its group.
+	shared := map[string]bool{}
+	for _, cr := range eventingAuths.Items {
+		if cr.Status.Application == nil || owningIASTenant(cr) != tenant {
+			continue
+		}
+		if sharesApplication(cr) {
+			if shared[cr.Spec.SharedApplication] {
+				continue
+			}
+			shared[cr.Spec.SharedApplication] = true
+		}
+		applications++
+	}
+	return applications, nil
+}
+
+// checkIASApplicationQuota returns an error if the IASTenant CR with the given name stops the provisioning once its application quota is
+// reached, and the quota is reached.
+func checkIASApplicationQuota(ctx context.Context, c kpkgclient.Client, name string) error {
+	var tenant eamapiv1alpha1.IASTenant
+	if err := c.Get(ctx, kpkgclient.ObjectKey{Name: name}, &tenant); err != nil {
+		// The tenant of a labeled secret has no quota.
+		return errors.Wrapf(kpkgclient.IgnoreNotFound(err), "failed to get IASTenant %s", name)
+	}
+	quota := tenant.Spec.ApplicationQuota
+	if quota == nil || !quota.StopProvisioning {
+		return nil
+	}
+	applications, err := countIASApplications(ctx, c, name)
+	if err != nil {
+		return err
+	}
+	if applications >= quota.Limit {
+		return &applicationQuotaError{tenant: name, limit: quota.Limit}
+	}
+	return nil
+}
+
+// applicationQuotaError is returned if an application isn't created, because its IAS tenant reached its application quota.
+type applicationQuotaError struct {
+	tenant string
+	limit  int32
+}
+
+func (e *applicationQuotaError) Error() string {
+	return fmt.Sprintf("IAS tenant %s reached its quota of %d applications", e.tenant, e.limit)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *iasTenantReconciler) SetupWithManager(mgr kcontrollerruntime.Manager) error {
+	return kcontrollerruntime.NewControllerManagedBy(mgr).
+		For(&eamapiv1alpha1.IASTenant{}).
+		Watches(&kcorev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.tenantsOfSecret)).
+		Complete(r.InstrumentedReconciler())
+}
+
+// InstrumentedReconciler returns the reconciler as it is started by the controller.
+func (r *iasTenantReconciler) InstrumentedReconciler() reconcile.Reconciler {
+	return instrument(iasTenantControllerName, r)
+}
+
+// tenantsOfSecret returns the IASTenant CRs whose credentials are read from the secret, so that the client of a tenant is recreated and
+// its health is checked right away when its credentials are rotated.
+func (r *iasTenantReconciler) tenantsOfSecret(ctx context.Context, secret kpkgclient.Object) []reconcile.Request {
+	tenants, err := r.pool.TenantsOfSecret(ctx, secret)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to determine IAS tenants of secret", "secret", kpkgclient.ObjectKeyFromObject(secret))
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, tenant := range tenants {
+		// The tenant of the credentials secret of the manager has no IASTenant CR.
+		if tenant != "" {
+			requests = append(requests, reconcile.Request{NamespacedName: kpkgclient.ObjectKey{Name: tenant}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,94 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	kcorev1 "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("IASTenant Controller", Serial, Ordered, func() {
+	var tenant *eamapiv1alpha1.IASTenant
+
+	BeforeAll(func() {
+		replaceIasNewIasClientWithStub(iasClientStub{})
+	})
+
+	AfterAll(func() {
+		revertIasNewClientStub()
+	})
+
+	BeforeEach(func() {
+		tenant = &eamapiv1alpha1.IASTenant{
+			ObjectMeta: kmetav1.ObjectMeta{Name: generateCrName()},
+			Spec: eamapiv1alpha1.IASTenantSpec{
+				URL:                  "https://tenant.accounts.ondemand.com",
+				CredentialsSecretRef: eamapiv1alpha1.SecretReference{Namespace: skr.KcpNamespace, Name: generateCrName()},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		Expect(kpkgclient.IgnoreNotFound(k8sClient.Delete(context.TODO(), tenant))).Should(Succeed())
+		secret := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{
+			Namespace: tenant.Spec.CredentialsSecretRef.Namespace,
+			Name:      tenant.Spec.CredentialsSecretRef.Name,
+		}}
+		Expect(kpkgclient.IgnoreNotFound(k8sClient.Delete(context.TODO(), secret))).Should(Succeed())
+	})
+
+	It("should be ready if the tenant is reachable with its credentials", func() {
+		Expect(k8sClient.Create(context.TODO(), &kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{
+				Namespace: tenant.Spec.CredentialsSecretRef.Namespace,
+				Name:      tenant.Spec.CredentialsSecretRef.Name,
+			},
+			Data: map[string][]byte{"username": []byte("user"), "password": []byte("password")},
+		})).Should(Succeed())
+		Expect(k8sClient.Create(context.TODO(), tenant)).Should(Succeed())
+
+		verifyIASTenantState(tenant, eamapiv1alpha1.StateReady, eamapiv1alpha1.ConditionReasonTenantReachable)
+	})
+
+	It("should not be ready if the credentials secret doesn't exist", func() {
+		Expect(k8sClient.Create(context.TODO(), tenant)).Should(Succeed())
+
+		verifyIASTenantState(tenant, eamapiv1alpha1.StateNotReady, eamapiv1alpha1.ConditionReasonTenantUnreachable)
+	})
+
+	It("should be ready right away once the credentials secret is created", func() {
+		Expect(k8sClient.Create(context.TODO(), tenant)).Should(Succeed())
+		verifyIASTenantState(tenant, eamapiv1alpha1.StateNotReady, eamapiv1alpha1.ConditionReasonTenantUnreachable)
+
+		Expect(k8sClient.Create(context.TODO(), &kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{
+				Namespace: tenant.Spec.CredentialsSecretRef.Namespace,
+				Name:      tenant.Spec.CredentialsSecretRef.Name,
+			},
+			Data: map[string][]byte{"username": []byte("user"), "password": []byte("password")},
+		})).Should(Succeed())
+
+		// The health check interval is longer than the timeout, so the tenant is only ready if the secret triggered the health check.
+		verifyIASTenantState(tenant, eamapiv1alpha1.StateReady, eamapiv1alpha1.ConditionReasonTenantReachable)
+	})
+})
+
+func verifyIASTenantState(tenant *eamapiv1alpha1.IASTenant, state eamapiv1alpha1.State, reason string) {
+	By(fmt.Sprintf("Verifying state %s of IASTenant CR %s", state, tenant.Name))
+	Eventually(func(g Gomega) {
+		t := &eamapiv1alpha1.IASTenant{}
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(tenant), t)).Should(Succeed())
+		g.Expect(t.Status.State).To(Equal(state))
+		condition := kmeta.FindStatusCondition(t.Status.Conditions, string(eamapiv1alpha1.ConditionTenantReady))
+		g.Expect(condition).NotTo(BeNil())
+		g.Expect(condition.Reason).To(Equal(reason))
+	}, defaultTimeout).Should(Succeed())
+}
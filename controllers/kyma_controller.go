@@ -5,6 +5,7 @@ import (
 	"time"
 
 	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
 	klmapiv1beta1 "github.com/kyma-project/lifecycle-manager/api/v1beta1"
 	"github.com/pkg/errors"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -12,38 +13,70 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
-// KymaReconciler reconciles a Kyma resource.
+const (
+	kymaFinalizerName = "eventingauth.operator.kyma-project.io/kyma-finalizer"
+	// kymaDeletionRequeueInterval is the interval in which the deletion of a Kyma CR is checked again while the EventingAuth CR is still being cleaned up.
+	kymaDeletionRequeueInterval = time.Second * 5
+)
+
+// kymaGVK is the GroupVersionKind of the Kyma CRs, which are only read as metadata.
+var kymaGVK = klmapiv1beta1.GroupVersion.WithKind("Kyma") //nolint:gochecknoglobals // Read-only GroupVersionKind.
+
+// KymaReconciler reconciles a Kyma resource. Only the metadata of the Kyma CRs is cached and read, since the name, the labels, and the
+// finalizers are all that is needed of them, and the full Kyma CRs of a large fleet dominate the memory of the cache.
 type KymaReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
 	time.Duration
+	// useFinalizer defines if a finalizer is set on the Kyma CR to ensure that the Kyma CR is only removed after the EventingAuth CR is cleaned up.
+	useFinalizer bool
+	// sharedApplicationLabel is the label of the Kyma CRs whose value is the group of the Kyma CRs that share an application. No application
+	// is shared if it is empty.
+	sharedApplicationLabel string
 }
 
-func NewKymaReconciler(c client.Client, s *runtime.Scheme) *KymaReconciler {
+// NewKymaReconciler returns the reconciler of Kyma CRs. The EventingAuth CRs of the Kyma CRs with the same value of the shared application
+// label share an application, unless the label is empty.
+func NewKymaReconciler(c client.Client, s *runtime.Scheme, useFinalizer bool, sharedApplicationLabel string) *KymaReconciler {
 	return &KymaReconciler{
-		Client: c,
-		Scheme: s,
+		Client:                 c,
+		Scheme:                 s,
+		useFinalizer:           useFinalizer,
+		sharedApplicationLabel: sharedApplicationLabel,
 	}
 }
 
-// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=kymas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=kymas,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=kymas/finalizers,verbs=update
 // +kubebuilder:rbac:groups=operator.kyma-project.io,resources=eventingauths,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=operator.kyma-project.io,resources=eventingauths/status,verbs=get;list
+// +kubebuilder:rbac:groups=operator.kyma-project.io,resources=iastenants,verbs=get;list;watch
 func (r *KymaReconciler) Reconcile(ctx context.Context, req kcontrollerruntime.Request) (kcontrollerruntime.Result, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Reconciling Kyma resource")
 
-	kyma := &klmapiv1beta1.Kyma{}
+	kyma := newKymaMetadata()
 	err := r.Client.Get(ctx, req.NamespacedName, kyma)
 	if err != nil {
 		return kcontrollerruntime.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if !kyma.ObjectMeta.DeletionTimestamp.IsZero() {
+		logger.Info("Handling deletion")
+		return r.handleDeletion(ctx, kyma)
+	}
+
+	if err = r.syncFinalizer(ctx, kyma); err != nil {
+		return kcontrollerruntime.Result{}, err
+	}
+
 	if err = r.createEventingAuth(ctx, kyma); err != nil {
 		return kcontrollerruntime.Result{}, err
 	}
@@ -51,7 +84,7 @@ func (r *KymaReconciler) Reconcile(ctx context.Context, req kcontrollerruntime.R
 	return kcontrollerruntime.Result{}, nil
 }
 
-func (r *KymaReconciler) createEventingAuth(ctx context.Context, kyma *klmapiv1beta1.Kyma) error {
+func (r *KymaReconciler) createEventingAuth(ctx context.Context, kyma *kmetav1.PartialObjectMetadata) error {
 	eventingAuth := &eamapiv1alpha1.EventingAuth{
 		ObjectMeta: kmetav1.ObjectMeta{
 			Namespace: kyma.Namespace,
@@ -65,6 +98,21 @@ func (r *KymaReconciler) createEventingAuth(ctx context.Context, kyma *klmapiv1b
 			if err = controllerutil.SetControllerReference(kyma, eventingAuth, r.Scheme); err != nil {
 				return err
 			}
+			// The tenant is only selected on creation, since the application can't be moved to another tenant afterwards.
+			var tenant string
+			if tenant, err = provider.SelectIASTenant(ctx, r.Client, kyma.Labels); err != nil {
+				return err
+			}
+			if tenant != "" {
+				log.FromContext(ctx).Info("Selected IAS tenant of Kyma", "tenant", tenant)
+				eventingAuth.Spec.Provider = provider.IASName
+				eventingAuth.Spec.IAS = &eamapiv1alpha1.IASProviderSpec{Tenant: tenant}
+			}
+			// Like the tenant, the group is only selected on creation, since the application of the group can't be left afterwards.
+			if group := kyma.Labels[r.sharedApplicationLabel]; r.sharedApplicationLabel != "" && group != "" {
+				log.FromContext(ctx).Info("Selected shared application of Kyma", "group", group)
+				eventingAuth.Spec.SharedApplication = group
+			}
 			err = r.Client.Create(ctx, eventingAuth)
 			if err != nil {
 				return errors.Wrap(err, "failed to create EventingAuth resource")
@@ -76,10 +124,80 @@ func (r *KymaReconciler) createEventingAuth(ctx context.Context, kyma *klmapiv1b
 	return nil
 }
 
+// syncFinalizer adds the finalizer to the Kyma CR if the finalizer is enabled, otherwise it removes a finalizer that was set before.
+func (r *KymaReconciler) syncFinalizer(ctx context.Context, kyma *kmetav1.PartialObjectMetadata) error {
+	if r.useFinalizer && !controllerutil.ContainsFinalizer(kyma, kymaFinalizerName) {
+		log.FromContext(ctx).Info("Adding finalizer")
+		patch := kymaMetadataPatch(kyma)
+		controllerutil.AddFinalizer(kyma, kymaFinalizerName)
+		if err := r.Client.Patch(ctx, kyma, patch); err != nil {
+			return errors.Wrap(err, "failed to add finalizer to Kyma resource")
+		}
+	}
+	if !r.useFinalizer && controllerutil.ContainsFinalizer(kyma, kymaFinalizerName) {
+		log.FromContext(ctx).Info("Removing finalizer since it is disabled")
+		patch := kymaMetadataPatch(kyma)
+		controllerutil.RemoveFinalizer(kyma, kymaFinalizerName)
+		if err := r.Client.Patch(ctx, kyma, patch); err != nil {
+			return errors.Wrap(err, "failed to remove finalizer from Kyma resource")
+		}
+	}
+	return nil
+}
+
+// handleDeletion deletes the EventingAuth CR of the Kyma CR and removes the finalizer once the EventingAuth CR, and therefore the IAS application, is cleaned up.
+func (r *KymaReconciler) handleDeletion(ctx context.Context, kyma *kmetav1.PartialObjectMetadata) (kcontrollerruntime.Result, error) {
+	if !controllerutil.ContainsFinalizer(kyma, kymaFinalizerName) {
+		return kcontrollerruntime.Result{}, nil
+	}
+
+	eventingAuth := &eamapiv1alpha1.EventingAuth{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: kyma.Namespace, Name: kyma.Name}, eventingAuth)
+	if err != nil && !kapierrors.IsNotFound(err) {
+		return kcontrollerruntime.Result{}, errors.Wrap(err, "failed to retrieve EventingAuth resource")
+	}
+
+	if err == nil {
+		if eventingAuth.ObjectMeta.DeletionTimestamp.IsZero() {
+			if err = r.Client.Delete(ctx, eventingAuth); client.IgnoreNotFound(err) != nil {
+				return kcontrollerruntime.Result{}, errors.Wrap(err, "failed to delete EventingAuth resource")
+			}
+		}
+		// The EventingAuth CR is not removed until its finalizer is removed, so we need to wait for the cleanup to be done.
+		log.FromContext(ctx).Info("Waiting for EventingAuth resource to be deleted")
+		return kcontrollerruntime.Result{RequeueAfter: kymaDeletionRequeueInterval}, nil
+	}
+
+	patch := kymaMetadataPatch(kyma)
+	controllerutil.RemoveFinalizer(kyma, kymaFinalizerName)
+	if err = r.Client.Patch(ctx, kyma, patch); err != nil {
+		return kcontrollerruntime.Result{}, errors.Wrap(err, "failed to remove finalizer from Kyma resource")
+	}
+	return kcontrollerruntime.Result{}, nil
+}
+
+// newKymaMetadata returns the metadata of a Kyma CR to read it into.
+func newKymaMetadata() *kmetav1.PartialObjectMetadata {
+	kyma := &kmetav1.PartialObjectMetadata{}
+	kyma.SetGroupVersionKind(kymaGVK)
+	return kyma
+}
+
+// kymaMetadataPatch returns the patch of the metadata of the Kyma CR, since the metadata can't be updated without the full CR. The patch
+// fails on a conflict, so that the finalizers of other controllers aren't overwritten.
+func kymaMetadataPatch(kyma *kmetav1.PartialObjectMetadata) client.Patch {
+	return client.MergeFromWithOptions(kyma.DeepCopy(), client.MergeFromWithOptimisticLock{})
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *KymaReconciler) SetupWithManager(mgr kcontrollerruntime.Manager) error {
 	return kcontrollerruntime.NewControllerManagedBy(mgr).
-		For(&klmapiv1beta1.Kyma{}).
+		For(&klmapiv1beta1.Kyma{}, builder.OnlyMetadata).
 		Owns(&eamapiv1alpha1.EventingAuth{}).
-		Complete(r)
+		Complete(r.InstrumentedReconciler())
+}
+
+// InstrumentedReconciler returns the reconciler as it is started by the controller.
+func (r *KymaReconciler) InstrumentedReconciler() reconcile.Reconciler {
+	return instrument(kymaControllerName, r)
 }
@@ -10,11 +10,13 @@ import (
 	"github.com/kyma-project/eventing-auth-manager/internal/skr"
 	klmapiv1beta1 "github.com/kyma-project/lifecycle-manager/api/v1beta1"
 	klmapiv1beta2 "github.com/kyma-project/lifecycle-manager/api/v1beta2"
+	dto "github.com/prometheus/client_model/go"
 	kcorev1 "k8s.io/api/core/v1"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -55,9 +57,90 @@ var _ = Describe("Kyma Controller", Serial, Ordered, func() {
 
 			deleteKymaResource(kyma)
 		})
+
+		It("should add finalizer to Kyma CR", func() {
+			kyma = createKymaResource(crName)
+
+			verifyKymaFinalizer(kyma)
+
+			deleteKymaResource(kyma)
+		})
+
+		It("should record the reconciliations in the reconcile metrics", func() {
+			kyma = createKymaResource(crName)
+
+			verifyEventingAuth(kyma.Namespace, kyma.Name)
+			verifyReconcileMetrics("kyma")
+			verifyReconcileMetrics("eventingauth")
+			verifyEventingAuthsMetric("Ready")
+
+			deleteKymaResource(kyma)
+		})
 	})
 })
 
+func verifyReconcileMetrics(controller string) {
+	By(fmt.Sprintf("Verifying reconcile metrics of controller %s", controller))
+	Eventually(func(g Gomega) {
+		families, err := metrics.Registry.Gather()
+		g.Expect(err).NotTo(HaveOccurred())
+		var reconciliations, observations uint64
+		for _, family := range families {
+			for _, m := range family.GetMetric() {
+				if !hasLabel(m.GetLabel(), "controller", controller) {
+					continue
+				}
+				switch family.GetName() {
+				case "eventing_auth_manager_reconcile_total":
+					reconciliations += uint64(m.GetCounter().GetValue())
+				case "eventing_auth_manager_reconcile_duration_seconds":
+					observations += m.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+		g.Expect(reconciliations).To(BeNumerically(">", 0))
+		g.Expect(observations).To(BeNumerically(">=", reconciliations))
+	}, defaultTimeout).Should(Succeed())
+}
+
+func verifyEventingAuthsMetric(state string) {
+	By(fmt.Sprintf("Verifying EventingAuth metric of state %s", state))
+	Eventually(func(g Gomega) {
+		families, err := metrics.Registry.Gather()
+		g.Expect(err).NotTo(HaveOccurred())
+		var eventingAuths float64
+		for _, family := range families {
+			if family.GetName() != "eventing_auth_manager_eventing_auths" {
+				continue
+			}
+			for _, m := range family.GetMetric() {
+				if hasLabel(m.GetLabel(), "state", state) {
+					eventingAuths += m.GetGauge().GetValue()
+				}
+			}
+		}
+		g.Expect(eventingAuths).To(BeNumerically(">", 0))
+	}, defaultTimeout).Should(Succeed())
+}
+
+func hasLabel(labels []*dto.LabelPair, name, value string) bool {
+	for _, l := range labels {
+		if l.GetName() == name && l.GetValue() == value {
+			return true
+		}
+	}
+	return false
+}
+
+func verifyKymaFinalizer(kyma *klmapiv1beta1.Kyma) {
+	By(fmt.Sprintf("Verifying finalizer of Kyma CR %s", kyma.Name))
+	Eventually(func(g Gomega) {
+		k := &klmapiv1beta1.Kyma{}
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(kyma), k)).Should(Succeed())
+		g.Expect(k.Finalizers).To(ContainElement("eventingauth.operator.kyma-project.io/kyma-finalizer"))
+	}, defaultTimeout).Should(Succeed())
+}
+
 func verifyEventingAuth(namespace, name string) {
 	nsName := types.NamespacedName{Namespace: namespace, Name: name}
 	By(fmt.Sprintf("Verifying Kyma CR %s", nsName.String()))
@@ -67,6 +150,7 @@ func verifyEventingAuth(namespace, name string) {
 		g.Expect(eventingAuth.Name).To(Equal(name))
 		g.Expect(eventingAuth.Namespace).To(Equal(namespace))
 		g.Expect(eventingAuth.Status.State).To(Equal(eamapiv1alpha1.StateReady))
+		g.Expect(eventingAuth.Status.ProvisionedTime).ShouldNot(BeNil())
 		g.Expect(eventingAuth.Status.Application).ShouldNot(BeNil())
 		g.Expect(eventingAuth.Status.Application.Name).To(Equal(name))
 		g.Expect(eventingAuth.Status.Application.UUID).ShouldNot(BeEmpty())
@@ -102,11 +186,12 @@ func deleteKymaResource(kyma *klmapiv1beta1.Kyma) {
 		g.Expect(kapierrors.IsNotFound(err)).To(BeTrue())
 		eventingAuth := &eamapiv1alpha1.EventingAuth{}
 		err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: skr.KcpNamespace, Name: kyma.Name}, eventingAuth)
-		if useExistingCluster {
-			g.Expect(err).Should(HaveOccurred())
+		if useExistingCluster || kapierrors.IsNotFound(err) {
+			// The EventingAuth CR is deleted before the finalizer of the Kyma CR is removed.
 			g.Expect(kapierrors.IsNotFound(err)).To(BeTrue())
 		} else {
 			// clean up EventingAuth for not real cluster
+			g.Expect(err).NotTo(HaveOccurred())
 			deleteEventingAuthAndVerify(eventingAuth)
 		}
 	}, defaultTimeout).Should(Succeed())
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+)
+
+// DefaultIASMaintenanceThreshold is the default duration that the responses of an IAS tenant must be 503 for until the tenant is in
+// maintenance.
+const DefaultIASMaintenanceThreshold = time.Minute * 10
+
+// iasMaintenanceRequeueInterval is the interval in which the EventingAuth CRs whose IAS tenant is in maintenance are reconciled again,
+// instead of retrying their failed reconciliations with backoff for the duration of the maintenance.
+const iasMaintenanceRequeueInterval = time.Minute * 15
+
+// iasMaintenanceError is returned if an operation on an IAS tenant failed while the tenant is in maintenance. Its message contains the
+// message of the wrapped error.
+type iasMaintenanceError struct {
+	tenant string
+	since  time.Time
+	err    error
+}
+
+func (e *iasMaintenanceError) Error() string {
+	tenant := "of the credentials secret"
+	if e.tenant != "" {
+		tenant = e.tenant
+	}
+	return fmt.Sprintf("IAS tenant %s is in maintenance since %s: %s", tenant, e.since.UTC().Format(time.RFC3339), e.err)
+}
+
+func (e *iasMaintenanceError) Unwrap() error {
+	return e.err
+}
+
+// ConditionReason returns the reason of the conditions that are set because of the maintenance.
+func (e *iasMaintenanceError) ConditionReason() string {
+	return eamapiv1alpha1.ConditionReasonTenantMaintenance
+}
+
+// iasMaintenanceErrorOf returns the error as iasMaintenanceError if the IAS tenant with the given name is in maintenance, or nil if the
+// tenant isn't in maintenance, there is no error, or the error is an error of an SKR cluster.
+func iasMaintenanceErrorOf(pool *provider.IASClientPool, tenant string, err error) *iasMaintenanceError {
+	var maintenanceErr *iasMaintenanceError
+	if errors.As(err, &maintenanceErr) {
+		return maintenanceErr
+	}
+	var skrErr *skrClusterError
+	if pool == nil || err == nil || errors.As(err, &skrErr) {
+		return nil
+	}
+	inMaintenance, since := pool.InMaintenance(tenant)
+	if !inMaintenance {
+		return nil
+	}
+	return &iasMaintenanceError{tenant: tenant, since: since, err: err}
+}
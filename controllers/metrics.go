@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Names of the controllers, that the reconcile metrics are labeled with.
+const (
+	kymaControllerName           = "kyma"
+	eventingAuthControllerName   = "eventingauth"
+	iasTenantControllerName      = "iastenant"
+	iasApplicationControllerName = "iasapplication"
+)
+
+// Results of a reconciliation, that the reconcile metrics are labeled with.
+const (
+	reconcileResultSuccess = "success"
+	reconcileResultError   = "error"
+	reconcileResultRequeue = "requeue"
+)
+
+// States of the EventingAuth CRs, that the EventingAuth gauge is labeled with.
+const (
+	eventingAuthStateReady    = "Ready"
+	eventingAuthStateNotReady = "NotReady"
+	eventingAuthStateDeleting = "Deleting"
+	eventingAuthStateFailed   = "Failed"
+)
+
+// eventingAuthStates are the states that the EventingAuth gauge is reported for, even if no EventingAuth CR is in the state.
+var eventingAuthStates = []string{ //nolint:gochecknoglobals // Read-only list of the states.
+	eventingAuthStateReady, eventingAuthStateNotReady, eventingAuthStateDeleting, eventingAuthStateFailed,
+}
+
+// eventingAuthCollectTimeout is the timeout of listing the EventingAuth CRs on a scrape of the metrics.
+const eventingAuthCollectTimeout = 10 * time.Second
+
+// eventingAuthsDesc describes the gauge of the number of EventingAuth CRs per state and IAS tenant.
+var eventingAuthsDesc = prometheus.NewDesc( //nolint:gochecknoglobals // Describes the metric of the collector.
+	"eventing_auth_manager_eventing_auths",
+	"Number of EventingAuth CRs by their state, which is one of Ready, NotReady, Deleting, or Failed, and by the IAS tenant that owns their "+
+		"application. The tenant is empty for the tenant of the credentials secret of the manager and for other providers.",
+	[]string{"state", "tenant"}, nil,
+)
+
+// sharedApplicationMembersDesc describes the gauge of the number of EventingAuth CRs that share an application, which is the reference count
+// of the application.
+var sharedApplicationMembersDesc = prometheus.NewDesc( //nolint:gochecknoglobals // Describes the metric of the collector.
+	"eventing_auth_manager_shared_application_members",
+	"Number of EventingAuth CRs that share the application of the group and aren't being deleted. The application is deleted with the "+
+		"last EventingAuth CR of the group.",
+	[]string{"group"}, nil,
+)
+
+// Sources of the start of the provisioning, that the provisioning duration is labeled with.
+const (
+	provisioningSourceKyma         = "kyma"
+	provisioningSourceEventingAuth = "eventingauth"
+)
+
+// provisioningDuration observes the time from the creation of the Kyma CR, or of the EventingAuth CR, until the EventingAuth CR is ready.
+var provisioningDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_provisioning_duration_seconds",
+	Help: "Time from the creation of the Kyma CR, or of the EventingAuth CR if it isn't owned by a Kyma CR, until the EventingAuth CR " +
+		"is ready for the first time.",
+	Buckets: []float64{5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+}, []string{"source"})
+
+// eventingAuthsReadyRatioDesc describes the gauge of the ratio of the ready EventingAuth CRs, that is the top-level health indicator of
+// the fleet.
+var eventingAuthsReadyRatioDesc = prometheus.NewDesc( //nolint:gochecknoglobals // Describes the metric of the collector.
+	"eventing_auth_manager_eventing_auths_ready_ratio",
+	"Ratio of the ready EventingAuth CRs to all EventingAuth CRs that aren't being deleted, by the landscape of the manager. "+
+		"It is 1 if there are no EventingAuth CRs.",
+	[]string{"landscape"}, nil,
+)
+
+// reconcileTotal counts the reconciliations of the controllers by their result.
+var reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_reconcile_total",
+	Help: "Number of reconciliations of the controller by their result, which is one of success, error, or requeue.",
+}, []string{"controller", "result"})
+
+// reconcileErrors counts the errors of the reconciliations of the controllers by their class.
+var reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_reconcile_errors_total",
+	Help: "Number of errors of the reconciliations of the controller by their class, and whether they are retryable or terminal.",
+}, []string{"controller", "class", "retryable"})
+
+// reconcileDuration observes the duration of the reconciliations of the controllers.
+var reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name:    "eventing_auth_manager_reconcile_duration_seconds",
+	Help:    "Duration of the reconciliations of the controller in seconds.",
+	Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+}, []string{"controller"})
+
+// secretLastSyncTimestamp reports the time of the last successful sync of the application secret per SKR cluster.
+var secretLastSyncTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_skr_secret_last_sync_timestamp_seconds",
+	Help: "Unix time of the last successful sync of the application secret on the SKR cluster.",
+}, []string{"cluster"})
+
+// secretSyncFailures counts the failed syncs of the application secret per SKR cluster.
+var secretSyncFailures = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_skr_secret_sync_failures_total",
+	Help: "Number of failed syncs of the application secret on the SKR cluster, e.g. because the cluster is unreachable or rejects the write.",
+}, []string{"cluster"})
+
+// secretSyncDuration observes the latency of the syncs of the application secret per SKR cluster.
+var secretSyncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name:    "eventing_auth_manager_skr_secret_sync_duration_seconds",
+	Help:    "Latency of the syncs of the application secret on the SKR cluster in seconds.",
+	Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+}, []string{"cluster"})
+
+// orphanedApplications counts the applications that were orphaned in IAS, because their deletion kept failing.
+var orphanedApplications = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_orphaned_applications_total",
+	Help: "Number of applications that were orphaned in IAS, because their deletion failed too often while their EventingAuth CR was " +
+		"deleted. The tenant is empty for the tenant of the credentials secret of the manager and for other providers.",
+}, []string{"tenant"})
+
+func init() { //nolint:gochecknoinits // Metrics are registered on the package level.
+	metrics.Registry.MustRegister(reconcileTotal, reconcileErrors, reconcileDuration, provisioningDuration, secretLastSyncTimestamp, secretSyncFailures, secretSyncDuration,
+		orphanedApplications)
+}
+
+// instrumentedReconciler records the result and the duration of the reconciliations of the wrapped reconciler in the reconcile metrics,
+// tracks the reconciliations that are in progress for the detection of stuck reconciliations, and traces them with a span that is the
+// parent of the spans of the requests to the IAS tenants and the SKR clusters, and with a correlation ID.
+type instrumentedReconciler struct {
+	controller string
+	reconcile.Reconciler
+}
+
+// instrument wraps the reconciler, so that its reconciliations are recorded in the reconcile metrics with the label of the controller.
+func instrument(controller string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &instrumentedReconciler{controller: controller, Reconciler: r}
+}
+
+func (r *instrumentedReconciler) Reconcile(ctx context.Context, req kcontrollerruntime.Request) (kcontrollerruntime.Result, error) {
+	if ctx.Err() != nil {
+		// The manager stops, the item is reconciled by the next leader.
+		return kcontrollerruntime.Result{Requeue: true}, nil
+	}
+	ctx, cancel := detachFromShutdown(ctx)
+	defer cancel()
+	done := inFlightReconciles.start(r.controller, req)
+	defer done()
+	ctx, span := tracing.Tracer().Start(ctx, "Reconcile "+r.controller, trace.WithAttributes(
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("k8s.object.name", req.Name),
+	))
+	// The correlation ID links the log lines, events, status messages, and audit records of the reconciliation to its trace.
+	ctx, correlationID := tracing.WithCorrelationID(ctx)
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues("correlationID", correlationID))
+	start := time.Now()
+	result, err := r.Reconciler.Reconcile(ctx, req)
+	tracing.End(span, err)
+	reconcileDuration.WithLabelValues(r.controller).Observe(time.Since(start).Seconds())
+	reconcileTotal.WithLabelValues(r.controller, reconcileResultOf(result, err)).Inc()
+	if err != nil {
+		class, retryable := classifyError(err)
+		reconcileErrors.WithLabelValues(r.controller, class, strconv.FormatBool(retryable)).Inc()
+	}
+	return result, err
+}
+
+// reconcileResultOf returns the result label of a reconciliation. A reconciliation that is requeued after an interval, like the periodic
+// health check of the IAS tenants, counts as requeue.
+func reconcileResultOf(result kcontrollerruntime.Result, err error) string {
+	switch {
+	case err != nil:
+		return reconcileResultError
+	case result.Requeue || result.RequeueAfter > 0:
+		return reconcileResultRequeue
+	default:
+		return reconcileResultSuccess
+	}
+}
+
+// eventingAuthCollector reports the number of EventingAuth CRs per state and IAS tenant, the ratio of the ready EventingAuth CRs, and the
+// number of EventingAuth CRs per shared application. The
+// CRs are counted on every scrape from the cache of the manager, so that CRs that are deleted don't need to be removed from the gauges.
+type eventingAuthCollector struct {
+	reader kpkgclient.Reader
+	// landscape is the landscape of the manager, that the ratio of the ready EventingAuth CRs is labeled with.
+	landscape string
+}
+
+// newEventingAuthCollector returns the collector of the EventingAuth gauges, that lists the EventingAuth CRs with the reader.
+func newEventingAuthCollector(reader kpkgclient.Reader, landscape string) prometheus.Collector {
+	return &eventingAuthCollector{reader: reader, landscape: landscape}
+}
+
+func (c *eventingAuthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- eventingAuthsDesc
+	ch <- eventingAuthsReadyRatioDesc
+	ch <- sharedApplicationMembersDesc
+}
+
+func (c *eventingAuthCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), eventingAuthCollectTimeout)
+	defer cancel()
+	var eventingAuths eamapiv1alpha1.EventingAuthList
+	if err := c.reader.List(ctx, &eventingAuths); err != nil {
+		kcontrollerruntime.Log.Error(err, "Failed to list EventingAuth CRs for metrics")
+		return
+	}
+
+	counts := map[string]map[string]int{}
+	members := map[string]int{}
+	var ready, total int
+	for _, cr := range eventingAuths.Items {
+		if sharesApplication(cr) && cr.DeletionTimestamp.IsZero() {
+			members[cr.Spec.SharedApplication]++
+		}
+		tenant := owningIASTenant(cr)
+		if counts[tenant] == nil {
+			counts[tenant] = map[string]int{}
+		}
+		state := eventingAuthStateOf(cr)
+		counts[tenant][state]++
+		if state != eventingAuthStateDeleting {
+			total++
+		}
+		if state == eventingAuthStateReady {
+			ready++
+		}
+	}
+	ratio := 1.0
+	if total > 0 {
+		ratio = float64(ready) / float64(total)
+	}
+	ch <- prometheus.MustNewConstMetric(eventingAuthsReadyRatioDesc, prometheus.GaugeValue, ratio, c.landscape)
+
+	if len(counts) == 0 {
+		counts[""] = map[string]int{}
+	}
+	for tenant, states := range counts {
+		for _, state := range eventingAuthStates {
+			ch <- prometheus.MustNewConstMetric(eventingAuthsDesc, prometheus.GaugeValue, float64(states[state]), state, tenant)
+		}
+	}
+	for group, count := range members {
+		ch <- prometheus.MustNewConstMetric(sharedApplicationMembersDesc, prometheus.GaugeValue, float64(count), group)
+	}
+}
+
+// eventingAuthStateOf returns the state label of the EventingAuth CR. A CR that isn't ready is failed if its application couldn't be
+// created, and not ready if it wasn't reconciled yet or only its secret isn't ready.
+func eventingAuthStateOf(cr eamapiv1alpha1.EventingAuth) string {
+	switch {
+	case !cr.DeletionTimestamp.IsZero():
+		return eventingAuthStateDeleting
+	case cr.Status.State == eamapiv1alpha1.StateReady:
+		return eventingAuthStateReady
+	case kmeta.IsStatusConditionFalse(cr.Status.Conditions, string(eamapiv1alpha1.ConditionApplicationReady)):
+		return eventingAuthStateFailed
+	default:
+		return eventingAuthStateNotReady
+	}
+}
+
+// observeSecretSync records a sync of the application secret on the SKR cluster that started at the given time in the secret sync metrics.
+// The time of the last successful sync is only updated if the secret was synced, and not if the sync found no secret to sync.
+func observeSecretSync(cluster string, start time.Time, synced bool, err error) {
+	secretSyncDuration.WithLabelValues(cluster).Observe(time.Since(start).Seconds())
+	switch {
+	case err != nil:
+		secretSyncFailures.WithLabelValues(cluster).Inc()
+	case synced:
+		secretLastSyncTimestamp.WithLabelValues(cluster).SetToCurrentTime()
+	}
+}
+
+// forgetSecretSync removes the secret sync metrics of the SKR cluster, whose application secret is no longer managed.
+func forgetSecretSync(cluster string) {
+	secretLastSyncTimestamp.DeleteLabelValues(cluster)
+	secretSyncFailures.DeleteLabelValues(cluster)
+	secretSyncDuration.DeleteLabelValues(cluster)
+}
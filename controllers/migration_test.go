@@ -0,0 +1,150 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/controllers"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	kcorev1 "k8s.io/api/core/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Since all tests use the same target cluster and therefore share the same application secret, they need to be executed serially.
+var _ = Describe("EventingAuth Controller IAS tenant migration tests", Serial, Ordered, func() {
+	var (
+		sourceClient *recordingIasClientStub
+		targetClient *recordingIasClientStub
+		tenants      []*eamapiv1alpha1.IASTenant
+		eventingAuth *eamapiv1alpha1.EventingAuth
+		crName       string
+		tokenServer  *httptest.Server
+		rejectTokens atomic.Bool
+	)
+
+	BeforeEach(func() {
+		// The smoke test of the migration requests a token with the credentials of the application in the new tenant.
+		rejectTokens.Store(false)
+		tokenServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if rejectTokens.Load() {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"test-token","token_type":"bearer"}`))
+		}))
+
+		crName = generateCrName()
+		createKubeconfigSecret(crName)
+		sourceName, targetName := generateCrName(), generateCrName()
+		sourceClient = &recordingIasClientStub{}
+		targetClient = &recordingIasClientStub{tokenURL: tokenServer.URL + "/token"}
+		replaceIasNewIasClientWithTenantStubs(map[string]eamias.Client{
+			iasTenantURL(sourceName): sourceClient,
+			iasTenantURL(targetName): targetClient,
+		})
+		tenants = []*eamapiv1alpha1.IASTenant{createIASTenant(sourceName, ""), createIASTenant(targetName, "")}
+
+		eventingAuth = createEventingAuthInIASTenant(crName, sourceName)
+		verifyEventingAuthStatusReady(eventingAuth)
+		verifyApplicationTenant(eventingAuth, sourceName)
+	})
+
+	AfterEach(func() {
+		deleteEventingAuthAndVerify(eventingAuth)
+		deleteApplicationSecretOnTargetCluster()
+		deleteKubeconfigSecret(crName)
+		for _, tenant := range tenants {
+			deleteIASTenant(tenant)
+		}
+		revertIasNewClientStub()
+		tokenServer.Close()
+	})
+
+	It("should deliver the credentials of the new IAS tenant before deleting the application from the previous tenant", func() {
+		// given
+		var deliveredBeforeDeletion atomic.Bool
+		sourceClient.setOnDelete(func(string) {
+			var secret kcorev1.Secret
+			err := targetClusterK8sClient.Get(context.TODO(), appSecretObjectKey, &secret)
+			deliveredBeforeDeletion.Store(err == nil && string(secret.Data[eamias.SecretKeyTokenURL]) == tokenServer.URL+"/token")
+		})
+
+		// when
+		annotateMigrateToIASTenant(eventingAuth, tenants[1].Name)
+
+		// then
+		verifyMigratedToIASTenant(eventingAuth, tenants[1].Name)
+		verifyApplicationTenant(eventingAuth, tenants[1].Name)
+		verifyApplicationSecretTokenURL(tokenServer.URL + "/token")
+		Expect(targetClient.createdApplications()).To(ConsistOf(crName))
+		Expect(sourceClient.deletedApplications()).To(ConsistOf(crName))
+		Expect(deliveredBeforeDeletion.Load()).To(BeTrue())
+		verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeNormal, "CredentialsRotated")
+	})
+
+	It("should keep the application in the previous IAS tenant as long as the smoke test of the new tenant fails", func() {
+		// given
+		rejectTokens.Store(true)
+
+		// when
+		annotateMigrateToIASTenant(eventingAuth, tenants[1].Name)
+
+		// then
+		verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeWarning, "ReconcileFailed")
+		e := eamapiv1alpha1.EventingAuth{}
+		Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(eventingAuth), &e)).Should(Succeed())
+		Expect(e.Spec.IAS.Tenant).To(Equal(tenants[0].Name))
+		Expect(e.Annotations).To(HaveKeyWithValue(controllers.MigrateToIASTenantAnnotation, tenants[1].Name))
+		Expect(e.Status.Application.Tenant).To(Equal(tenants[0].Name))
+		Expect(sourceClient.deletedApplications()).To(BeEmpty())
+		verifyApplicationSecretTokenURL("https://test-token-url.com/token")
+
+		// when the smoke test succeeds on a retry
+		rejectTokens.Store(false)
+
+		// then
+		verifyMigratedToIASTenant(eventingAuth, tenants[1].Name)
+		verifyApplicationSecretTokenURL(tokenServer.URL + "/token")
+		Expect(sourceClient.deletedApplications()).To(ConsistOf(crName))
+	})
+})
+
+func annotateMigrateToIASTenant(cr *eamapiv1alpha1.EventingAuth, tenant string) {
+	By(fmt.Sprintf("Annotating EventingAuth %s to migrate to IAS tenant %s", cr.Name, tenant))
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), cr)).Should(Succeed())
+		if cr.Annotations == nil {
+			cr.Annotations = map[string]string{}
+		}
+		cr.Annotations[controllers.MigrateToIASTenantAnnotation] = tenant
+		g.Expect(k8sClient.Update(context.TODO(), cr)).Should(Succeed())
+	}, defaultTimeout).Should(Succeed())
+}
+
+func verifyMigratedToIASTenant(cr *eamapiv1alpha1.EventingAuth, tenant string) {
+	By(fmt.Sprintf("Verifying that EventingAuth %s migrated to IAS tenant %s", cr.Name, tenant))
+	Eventually(func(g Gomega) {
+		e := eamapiv1alpha1.EventingAuth{}
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), &e)).Should(Succeed())
+		g.Expect(e.Spec.IAS).NotTo(BeNil())
+		g.Expect(e.Spec.IAS.Tenant).To(Equal(tenant))
+		g.Expect(e.Annotations).NotTo(HaveKey(controllers.MigrateToIASTenantAnnotation))
+	}, defaultTimeout).Should(Succeed())
+}
+
+func verifyApplicationSecretTokenURL(tokenURL string) {
+	By(fmt.Sprintf("Verifying that application secret on target cluster has token URL %s", tokenURL))
+	Eventually(func(g Gomega) {
+		var secret kcorev1.Secret
+		g.Expect(targetClusterK8sClient.Get(context.TODO(), appSecretObjectKey, &secret)).Should(Succeed())
+		g.Expect(string(secret.Data[eamias.SecretKeyTokenURL])).To(Equal(tokenURL))
+	}, defaultTimeout).Should(Succeed())
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// OrphanedApplicationPolicy defines how the deletion of an EventingAuth CR continues once its application is orphaned in IAS.
+type OrphanedApplicationPolicy string
+
+const (
+	// OrphanedApplicationPolicyKeepFinalizer keeps the finalizer of the EventingAuth CR and retries the deletion of the application every
+	// orphanedApplicationRetryInterval, so that the application isn't left behind, but the deletion of the CR stays blocked.
+	OrphanedApplicationPolicyKeepFinalizer OrphanedApplicationPolicy = "keep-finalizer"
+	// OrphanedApplicationPolicyRemoveFinalizer deletes the application secret and the credentials in the sinks and removes the finalizer of
+	// the EventingAuth CR, so that the deletion of its namespace or Kyma CR isn't blocked, and leaves the application behind in IAS.
+	OrphanedApplicationPolicyRemoveFinalizer OrphanedApplicationPolicy = "remove-finalizer"
+)
+
+// OrphanedApplicationPolicies are the supported policies of orphaned applications.
+var OrphanedApplicationPolicies = []OrphanedApplicationPolicy{ //nolint:gochecknoglobals // Read-only list of the policies.
+	OrphanedApplicationPolicyKeepFinalizer, OrphanedApplicationPolicyRemoveFinalizer,
+}
+
+// Validate returns an error if the policy isn't supported. The empty policy is OrphanedApplicationPolicyKeepFinalizer.
+func (p OrphanedApplicationPolicy) Validate() error {
+	if p == "" {
+		return nil
+	}
+	for _, policy := range OrphanedApplicationPolicies {
+		if p == policy {
+			return nil
+		}
+	}
+	return errors.Errorf("unsupported policy for orphaned applications: %s", p)
+}
+
+// ApplicationDeletionOptions configures the deletion of the applications of the EventingAuth CRs that are being deleted.
+type ApplicationDeletionOptions struct {
+	// MaxAttempts is the number of failed deletions of an application after which the application is orphaned. The deletion is retried
+	// until it succeeds if it is 0.
+	MaxAttempts int
+	// OrphanedPolicy defines how the deletion of the EventingAuth CR continues once its application is orphaned. Defaults to
+	// OrphanedApplicationPolicyKeepFinalizer.
+	OrphanedPolicy OrphanedApplicationPolicy
+}
+
+// DefaultApplicationDeletionMaxAttempts is the default number of failed deletions of an application after which the application is
+// orphaned. With the backoff of the controller, the deletion is retried for more than an hour.
+const DefaultApplicationDeletionMaxAttempts = 20
+
+// orphanedApplicationRetryInterval is the interval in which the deletion of an orphaned application is retried with
+// OrphanedApplicationPolicyKeepFinalizer, instead of retrying it with backoff.
+const orphanedApplicationRetryInterval = time.Hour
+
+// eventReasonApplicationOrphaned is the reason of the warning event of an application that is orphaned in IAS.
+const eventReasonApplicationOrphaned = "ApplicationOrphaned"
+
+// failedApplicationDeletion records the failed deletion of the application of the EventingAuth CR. It returns true if the application is
+// orphaned, because the deletion failed for MaxAttempts times. The failures during the maintenance of the IAS tenant aren't counted.
+func (r *eventingAuthReconciler) failedApplicationDeletion(ctx context.Context, cr *eamapiv1alpha1.EventingAuth, err error) (bool, error) {
+	if r.deletionOpts.MaxAttempts <= 0 || iasMaintenanceErrorOf(r.iasClientPool, owningIASTenant(*cr), err) != nil {
+		return false, nil
+	}
+	attempts := r.applications.failedDeletion(cr.Name)
+	if attempts < r.deletionOpts.MaxAttempts {
+		return false, nil
+	}
+
+	// The condition is only set once, so that the orphaned application is only counted and emitted once.
+	if !kmeta.IsStatusConditionTrue(cr.Status.Conditions, string(eamapiv1alpha1.ConditionOrphanedInIAS)) {
+		log.FromContext(ctx).Info("Orphaned application in IAS, because its deletion failed too often", "applicationID", applicationID(*cr),
+			"attempts", attempts, "policy", r.orphanedPolicy(), "error", redact.String(err.Error()))
+		eamapiv1alpha1.SetOrphanedInIASCondition(cr, applicationID(*cr), attempts,
+			eamapiv1alpha1.WithCorrelationID(err, tracing.CorrelationID(ctx)))
+		if err := r.writeEventingAuthStatus(ctx, cr); err != nil {
+			return false, err
+		}
+		orphanedApplications.WithLabelValues(owningIASTenant(*cr)).Inc()
+		r.events.emit(ctx, cr, kcorev1.EventTypeWarning, eventReasonApplicationOrphaned,
+			"Orphaned application %s%s, because its deletion failed %d times: %s", applicationID(*cr), inIASTenant(owningIASTenant(*cr)),
+			attempts, err.Error())
+	}
+	decisionOf(ctx).act("orphaned application %s after %d failed deletions, policy %s", applicationID(*cr), attempts, r.orphanedPolicy())
+	return true, nil
+}
+
+// orphanedPolicy returns the policy of orphaned applications.
+func (r *eventingAuthReconciler) orphanedPolicy() OrphanedApplicationPolicy {
+	if r.deletionOpts.OrphanedPolicy == "" {
+		return OrphanedApplicationPolicyKeepFinalizer
+	}
+	return r.deletionOpts.OrphanedPolicy
+}
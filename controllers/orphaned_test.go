@@ -0,0 +1,94 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	kcorev1 "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+)
+
+// Since all tests use the same target cluster and therefore share the same application secret, they need to be executed serially.
+var _ = Describe("EventingAuth Controller orphaned application tests", Serial, Ordered, func() {
+	var (
+		iasClient    *recordingIasClientStub
+		eventingAuth *eamapiv1alpha1.EventingAuth
+		crName       string
+	)
+
+	BeforeEach(func() {
+		iasClient = &recordingIasClientStub{}
+		stubIasAppCreation(iasClient)
+		crName = generateCrName()
+		createKubeconfigSecret(crName)
+		eventingAuth = createEventingAuth(crName)
+		verifyEventingAuthStatusReady(eventingAuth)
+	})
+
+	AfterEach(func() {
+		iasClient.setDeletionErr(nil)
+		deleteEventingAuthAndVerify(eventingAuth)
+		deleteApplicationSecretOnTargetCluster()
+		deleteKubeconfigSecret(crName)
+		revertReadCredentialsStub()
+		revertIasNewClientStub()
+	})
+
+	It("should delete the application without orphaning it if the deletion succeeds before the max attempts", func() {
+		// given
+		iasClient.failDeletions(applicationDeletionMaxAttempts - 1)
+
+		// when
+		deleteEventingAuthAndVerify(eventingAuth)
+
+		// then
+		Expect(iasClient.deletedApplications()).To(ContainElement(crName))
+		var events kcorev1.EventList
+		Expect(k8sClient.List(context.TODO(), &events, kpkgclient.InNamespace(eventingAuth.Namespace))).Should(Succeed())
+		Expect(events.Items).NotTo(ContainElement(MatchFields(IgnoreExtras, Fields{
+			"InvolvedObject": MatchFields(IgnoreExtras, Fields{"Kind": Equal("EventingAuth"), "Name": Equal(crName)}),
+			"Reason":         Equal("ApplicationOrphaned"),
+		})))
+	})
+
+	It("should orphan the application and keep the finalizer once the deletion failed for the max attempts", func() {
+		// given
+		iasClient.setDeletionErr(errIASApplicationDeletion)
+
+		// when
+		By(fmt.Sprintf("Deleting EventingAuth %s", crName))
+		Expect(k8sClient.Delete(context.TODO(), eventingAuth)).Should(Succeed())
+
+		// then
+		verifyOrphanedInIAS(eventingAuth)
+		verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeWarning, "ApplicationOrphaned")
+		e := eamapiv1alpha1.EventingAuth{}
+		Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(eventingAuth), &e)).Should(Succeed())
+		Expect(e.Finalizers).NotTo(BeEmpty())
+		Expect(iasClient.deletedApplications()).To(BeEmpty())
+		verifySecretExistsOnTargetCluster()
+
+		// when the deletion of the orphaned application succeeds on a retry
+		iasClient.setDeletionErr(nil)
+
+		// then
+		deleteEventingAuthAndVerify(eventingAuth)
+		Expect(iasClient.deletedApplications()).To(ContainElement(crName))
+	})
+})
+
+func verifyOrphanedInIAS(cr *eamapiv1alpha1.EventingAuth) {
+	By(fmt.Sprintf("Verifying that application of EventingAuth %s is orphaned in IAS", cr.Name))
+	Eventually(func(g Gomega) {
+		e := eamapiv1alpha1.EventingAuth{}
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), &e)).Should(Succeed())
+		g.Expect(kmeta.IsStatusConditionTrue(e.Status.Conditions, string(eamapiv1alpha1.ConditionOrphanedInIAS))).To(BeTrue())
+		g.Expect(e.Status.State).To(Equal(eamapiv1alpha1.StateNotReady))
+	}, defaultTimeout).Should(Succeed())
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/audit"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// kcpSecretPrefix is prepended to the name of the EventingAuth CR to name its KCP secret, which has the data keys of the application secret
+// on the SKR cluster.
+const kcpSecretPrefix = skr.ApplicationSecretName + "-"
+
+// credentialPropagation returns where the credentials of the EventingAuth CR are stored, which is the default propagation of the manager
+// unless the EventingAuth CR requests another one.
+func (r *eventingAuthReconciler) credentialPropagation(cr eamapiv1alpha1.EventingAuth) eamapiv1alpha1.CredentialPropagation {
+	if cr.Spec.CredentialPropagation != "" {
+		return cr.Spec.CredentialPropagation
+	}
+	return r.defaultCredentialPropagation
+}
+
+// propagatesToSKR returns true if the credentials of the EventingAuth CR are written to the application secret on the SKR cluster, which
+// requires the application secrets on the SKR clusters to be enabled.
+func (r *eventingAuthReconciler) propagatesToSKR(cr eamapiv1alpha1.EventingAuth) bool {
+	return r.skrClientCache != nil && r.credentialPropagation(cr) != eamapiv1alpha1.CredentialPropagationKCP
+}
+
+// propagatesToKCP returns true if the credentials of the EventingAuth CR are written to its KCP secret.
+func (r *eventingAuthReconciler) propagatesToKCP(cr eamapiv1alpha1.EventingAuth) bool {
+	propagation := r.credentialPropagation(cr)
+	return propagation == eamapiv1alpha1.CredentialPropagationKCP || propagation == eamapiv1alpha1.CredentialPropagationBoth
+}
+
+// kcpSecretKey returns the key of the KCP secret of the EventingAuth CR.
+func kcpSecretKey(cr eamapiv1alpha1.EventingAuth) kpkgclient.ObjectKey {
+	return kpkgclient.ObjectKey{Namespace: cr.Namespace, Name: kcpSecretPrefix + cr.Name}
+}
+
+// writeKCPSecret writes the credentials of the application to the KCP secret of the EventingAuth CR, which is owned by the CR, and records
+// the write in the audit log. The secret is annotated with the ID of the application, so that the credentials of the current application
+// can be restored from it.
+func (r *eventingAuthReconciler) writeKCPSecret(ctx context.Context, cr *eamapiv1alpha1.EventingAuth, app eamias.Application,
+	tenant string,
+) error {
+	key := kcpSecretKey(*cr)
+	secret := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		secret.Labels = map[string]string{skr.ManagedByLabelKey: skr.ManagedByLabelValue}
+		secret.Annotations = map[string]string{sharedApplicationIDAnnotation: app.GetID()}
+		secret.Data = app.Credentials()
+		return controllerutil.SetControllerReference(cr, secret, r.Scheme)
+	})
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionWriteSecret, Application: applicationName(*cr), ApplicationID: app.GetID(),
+		Tenant: tenant, Target: auditTargetKCP + ":" + key.String()}, err)
+	if err != nil {
+		return errors.Wrap(err, "failed to write KCP secret")
+	}
+	decisionOf(ctx).act("wrote credentials of application %s to KCP secret %s", app.GetID(), key)
+	if cr.Status.KCPSecret == key.String() {
+		return nil
+	}
+	return r.mutateEventingAuthStatus(ctx, cr, func(status *eamapiv1alpha1.EventingAuthStatus) {
+		status.KCPSecret = key.String()
+	})
+}
+
+// readKCPSecret returns the application of the credentials of the KCP secret of the EventingAuth CR, and false if the secret doesn't exist
+// or contains the credentials of another application than the application in the status.
+func (r *eventingAuthReconciler) readKCPSecret(ctx context.Context, cr eamapiv1alpha1.EventingAuth) (eamias.Application, bool, error) {
+	var secret kcorev1.Secret
+	if err := r.Client.Get(ctx, kcpSecretKey(cr), &secret); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return eamias.Application{}, false, nil
+		}
+		return eamias.Application{}, false, errors.Wrap(err, "failed to get KCP secret")
+	}
+	id := secret.Annotations[sharedApplicationIDAnnotation]
+	if id == "" || id != applicationID(cr) {
+		return eamias.Application{}, false, nil
+	}
+	return eamias.NewApplication(id, string(secret.Data[eamias.SecretKeyClientID]), string(secret.Data[eamias.SecretKeyClientSecret]),
+		string(secret.Data[eamias.SecretKeyTokenURL]), string(secret.Data[eamias.SecretKeyCertsURL])), true, nil
+}
+
+// deleteKCPSecret deletes the KCP secret of the EventingAuth CR, records the deletion in the audit log, and removes the secret from the
+// status.
+func (r *eventingAuthReconciler) deleteKCPSecret(ctx context.Context, cr *eamapiv1alpha1.EventingAuth) error {
+	key := kcpSecretKey(*cr)
+	secret := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+	err := kpkgclient.IgnoreNotFound(r.Client.Delete(ctx, secret))
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteSecret, Application: applicationName(*cr), ApplicationID: applicationID(*cr),
+		Tenant: owningIASTenant(*cr), Target: auditTargetKCP + ":" + key.String()}, err)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete KCP secret")
+	}
+	decisionOf(ctx).act("deleted KCP secret %s", key)
+	return r.mutateEventingAuthStatus(ctx, cr, func(status *eamapiv1alpha1.EventingAuthStatus) {
+		status.KCPSecret = ""
+	})
+}
+
+// removeStaleCopies deletes the copies of the credentials that aren't propagated anymore after the propagation of the EventingAuth CR
+// changed, once the credentials were delivered to the copies that are propagated.
+func (r *eventingAuthReconciler) removeStaleCopies(ctx context.Context, cr *eamapiv1alpha1.EventingAuth) error {
+	if !r.propagatesToKCP(*cr) && cr.Status.KCPSecret != "" {
+		if err := r.deleteKCPSecret(ctx, cr); err != nil {
+			return err
+		}
+	}
+	if r.skrClientCache != nil && !r.propagatesToSKR(*cr) && cr.Status.AuthSecret != nil {
+		if err := r.deleteK8sSecretOnSkr(ctx, cr); err != nil {
+			return err
+		}
+		return r.mutateEventingAuthStatus(ctx, cr, func(status *eamapiv1alpha1.EventingAuthStatus) {
+			status.AuthSecret = nil
+		})
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Since all tests use the same target cluster and therefore share the same application secret, they need to be executed serially.
+var _ = Describe("EventingAuth Controller credential propagation tests", Serial, Ordered, func() {
+	var (
+		eventingAuth *eamapiv1alpha1.EventingAuth
+		crName       string
+	)
+
+	BeforeAll(func() {
+		stubSuccessfulIasAppCreation()
+	})
+
+	AfterAll(func() {
+		revertReadCredentialsStub()
+		revertIasNewClientStub()
+	})
+
+	BeforeEach(func() {
+		crName = generateCrName()
+		createKubeconfigSecret(crName)
+	})
+
+	AfterEach(func() {
+		deleteEventingAuthAndVerify(eventingAuth)
+		deleteApplicationSecretOnTargetCluster()
+		deleteKubeconfigSecret(crName)
+		// The envtest cluster doesn't run the garbage collector, which deletes the owned KCP secret in a real cluster.
+		Expect(kpkgclient.IgnoreNotFound(k8sClient.Delete(context.TODO(), &kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: skr.KcpNamespace, Name: skr.ApplicationSecretName + "-" + crName},
+		}))).Should(Succeed())
+	})
+
+	It("should delete the application secret on the SKR cluster when the propagation changes from SKR to KCP", func() {
+		// given
+		eventingAuth = createEventingAuthWithPropagation(crName, eamapiv1alpha1.CredentialPropagationSKR)
+		verifyEventingAuthStatusReady(eventingAuth)
+		verifySecretExistsOnTargetCluster()
+
+		// when
+		updateCredentialPropagation(eventingAuth, eamapiv1alpha1.CredentialPropagationKCP)
+
+		// then
+		verifyKCPSecretExists(eventingAuth)
+		verifySecretDoesNotExistOnTargetCluster()
+		verifyPropagatedCopiesInStatus(eventingAuth, false, true)
+	})
+
+	It("should write the application secret on the SKR cluster when the propagation changes from KCP to Both", func() {
+		// given
+		eventingAuth = createEventingAuthWithPropagation(crName, eamapiv1alpha1.CredentialPropagationKCP)
+		verifyKCPSecretExists(eventingAuth)
+		verifySecretDoesNotExistOnTargetCluster()
+
+		// when
+		updateCredentialPropagation(eventingAuth, eamapiv1alpha1.CredentialPropagationBoth)
+
+		// then
+		secret := verifySecretExistsOnTargetCluster()
+		kcpSecret := verifyKCPSecretExists(eventingAuth)
+		Expect(secret.Data[eamias.SecretKeyClientID]).To(Equal(kcpSecret.Data[eamias.SecretKeyClientID]))
+		verifyPropagatedCopiesInStatus(eventingAuth, true, true)
+	})
+
+	It("should delete the KCP secret when the propagation changes from Both to SKR", func() {
+		// given
+		eventingAuth = createEventingAuthWithPropagation(crName, eamapiv1alpha1.CredentialPropagationBoth)
+		verifyEventingAuthStatusReady(eventingAuth)
+		verifyKCPSecretExists(eventingAuth)
+		verifySecretExistsOnTargetCluster()
+
+		// when
+		updateCredentialPropagation(eventingAuth, eamapiv1alpha1.CredentialPropagationSKR)
+
+		// then
+		verifyKCPSecretDoesNotExist(eventingAuth)
+		verifySecretExistsOnTargetCluster()
+		verifyPropagatedCopiesInStatus(eventingAuth, true, false)
+	})
+})
+
+func createEventingAuthWithPropagation(name string, propagation eamapiv1alpha1.CredentialPropagation) *eamapiv1alpha1.EventingAuth {
+	e := eamapiv1alpha1.EventingAuth{
+		ObjectMeta: kmetav1.ObjectMeta{Name: name, Namespace: skr.KcpNamespace},
+		Spec:       eamapiv1alpha1.EventingAuthSpec{CredentialPropagation: propagation},
+	}
+
+	By(fmt.Sprintf("Creating EventingAuth CR with credential propagation %s", propagation))
+	Expect(k8sClient.Create(context.TODO(), &e)).Should(Succeed())
+
+	return &e
+}
+
+func updateCredentialPropagation(cr *eamapiv1alpha1.EventingAuth, propagation eamapiv1alpha1.CredentialPropagation) {
+	By(fmt.Sprintf("Changing credential propagation of EventingAuth %s to %s", cr.Name, propagation))
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), cr)).Should(Succeed())
+		cr.Spec.CredentialPropagation = propagation
+		g.Expect(k8sClient.Update(context.TODO(), cr)).Should(Succeed())
+	}, defaultTimeout).Should(Succeed())
+}
+
+func kcpSecretObjectKey(cr *eamapiv1alpha1.EventingAuth) kpkgclient.ObjectKey {
+	return kpkgclient.ObjectKey{Namespace: cr.Namespace, Name: skr.ApplicationSecretName + "-" + cr.Name}
+}
+
+func verifyKCPSecretExists(cr *eamapiv1alpha1.EventingAuth) *kcorev1.Secret {
+	By(fmt.Sprintf("Verifying that KCP secret of EventingAuth %s exists", cr.Name))
+	s := kcorev1.Secret{}
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(context.TODO(), kcpSecretObjectKey(cr), &s)).Should(Succeed())
+		g.Expect(s.Data[eamias.SecretKeyClientID]).NotTo(BeEmpty())
+		g.Expect(s.Data[eamias.SecretKeyClientSecret]).NotTo(BeEmpty())
+	}, defaultTimeout).Should(Succeed())
+
+	return &s
+}
+
+func verifyKCPSecretDoesNotExist(cr *eamapiv1alpha1.EventingAuth) {
+	By(fmt.Sprintf("Verifying that KCP secret of EventingAuth %s does not exist", cr.Name))
+	Eventually(func(g Gomega) {
+		err := k8sClient.Get(context.TODO(), kcpSecretObjectKey(cr), &kcorev1.Secret{})
+		g.Expect(kapierrors.IsNotFound(err)).To(BeTrue())
+	}, defaultTimeout).Should(Succeed())
+}
+
+// verifyPropagatedCopiesInStatus verifies that the status of the EventingAuth CR records exactly the copies of the credentials that are
+// propagated.
+func verifyPropagatedCopiesInStatus(cr *eamapiv1alpha1.EventingAuth, skrCopy, kcpCopy bool) {
+	By(fmt.Sprintf("Verifying the propagated copies in the status of EventingAuth %s", cr.Name))
+	Eventually(func(g Gomega) {
+		e := eamapiv1alpha1.EventingAuth{}
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), &e)).Should(Succeed())
+		g.Expect(e.Status.State).To(Equal(eamapiv1alpha1.StateReady))
+		if skrCopy {
+			g.Expect(e.Status.AuthSecret).NotTo(BeNil())
+		} else {
+			g.Expect(e.Status.AuthSecret).To(BeNil())
+		}
+		if kcpCopy {
+			g.Expect(e.Status.KCPSecret).To(Equal(kcpSecretObjectKey(cr).String()))
+		} else {
+			g.Expect(e.Status.KCPSecret).To(BeEmpty())
+		}
+	}, defaultTimeout).Should(Succeed())
+}
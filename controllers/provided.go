@@ -0,0 +1,257 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultCredentialsRotationReminder is the default age of the provided credentials from which on the rotation of the credentials is
+// reminded with warning events.
+const DefaultCredentialsRotationReminder = 90 * 24 * time.Hour
+
+// providesCredentials returns true if the credentials of the EventingAuth CR are provided by spec.credentialsSecretRef instead of an
+// application that the manager provisions.
+func providesCredentials(cr eamapiv1alpha1.EventingAuth) bool {
+	return cr.Spec.CredentialsSecretRef != nil
+}
+
+// validateProvidedCredentials rejects the features that provision applications in addition to the provided credentials.
+func validateProvidedCredentials(cr eamapiv1alpha1.EventingAuth) error {
+	switch {
+	case sharesApplication(cr):
+		return &provider.ValidationError{Message: "spec.credentialsSecretRef can't be combined with spec.sharedApplication"}
+	case cr.Spec.SeparatePublisherCredentials:
+		return &provider.ValidationError{Message: "spec.credentialsSecretRef can't be combined with spec.separatePublisherCredentials"}
+	case isolatesConsumers(cr):
+		return &provider.ValidationError{Message: fmt.Sprintf("spec.credentialsSecretRef can't be combined with spec.consumerIsolation %s",
+			cr.Spec.ConsumerIsolation)}
+	}
+	return nil
+}
+
+// reconcileProvidedCredentials delivers the credentials of spec.credentialsSecretRef to the sinks and the application secret on the SKR
+// cluster, instead of provisioning an application. The application secret is written again if it drifted from the provided credentials,
+// e.g. because the credentials were rotated in the referenced secret or the application secret was changed on the SKR cluster. An
+// application that was provisioned before the credentials were provided is deleted once the provided credentials were read, validated,
+// and delivered, and is kept as long as any of these steps fails.
+func (r *eventingAuthReconciler) reconcileProvidedCredentials(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr eamapiv1alpha1.EventingAuth,
+) (kcontrollerruntime.Result, error) {
+	if err := validateProvidedCredentials(cr); err != nil {
+		if updateErr := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionApplicationReady, err); updateErr != nil {
+			return kcontrollerruntime.Result{}, updateErr
+		}
+		return kcontrollerruntime.Result{}, err
+	}
+	app, err := r.readProvidedCredentials(ctx, cr)
+	if err != nil {
+		if updateErr := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionApplicationReady, err); updateErr != nil {
+			return kcontrollerruntime.Result{}, updateErr
+		}
+		return kcontrollerruntime.Result{}, err
+	}
+	clientID := string(app.Credentials()[eamias.SecretKeyClientID])
+	fingerprint := credentialsFingerprint(app)
+	changed := cr.Status.ProvidedCredentials == nil || cr.Status.ProvidedCredentials.Fingerprint != fingerprint
+	if changed {
+		now := kmetav1.Now()
+		cr.Status.ProvidedCredentials = &eamapiv1alpha1.ProvidedCredentials{ClientID: clientID, Fingerprint: fingerprint, ObservedTime: &now}
+		logger.Info("Observed provided credentials", "clientID", clientID)
+		decisionOf(ctx).act("observed provided credentials of client %s", clientID)
+	}
+	if err := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionApplicationReady, nil); err != nil {
+		return kcontrollerruntime.Result{}, err
+	}
+
+	if changed || !kmeta.IsStatusConditionTrue(cr.Status.Conditions, string(eamapiv1alpha1.ConditionSecretReady)) ||
+		(r.propagatesToKCP(cr) && cr.Status.KCPSecret == "") {
+		if err := r.writeSinks(ctx, &cr, app, ""); err != nil {
+			logger.Error(err, "Failed to write provided credentials to sinks")
+			if updateErr := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionSecretReady, err); updateErr != nil {
+				return kcontrollerruntime.Result{}, updateErr
+			}
+			return kcontrollerruntime.Result{}, err
+		}
+		if len(r.sinks) > 0 {
+			decisionOf(ctx).act("wrote provided credentials of client %s to %s", clientID, sinksTarget(r.sinks))
+		}
+	}
+	if r.propagatesToSKR(cr) {
+		if err := r.deliverProvidedCredentials(ctx, logger, &cr, app, changed); err != nil {
+			return kcontrollerruntime.Result{}, err
+		}
+	}
+	if err := r.updateEventingAuthStatus(ctx, &cr, eamapiv1alpha1.ConditionSecretReady, nil); err != nil {
+		return kcontrollerruntime.Result{}, err
+	}
+	if err := r.deleteReplacedApplication(ctx, logger, p, &cr); err != nil {
+		return kcontrollerruntime.Result{}, err
+	}
+	if err := r.removeStaleCopies(ctx, &cr); err != nil {
+		return kcontrollerruntime.Result{}, err
+	}
+	return r.remindCredentialsRotation(ctx, &cr), nil
+}
+
+// deleteReplacedApplication deletes the application that was provisioned before the credentials were provided. It is only called once the
+// provided credentials were delivered, so that the sinks and the SKR cluster never lack valid credentials during the switch-over.
+func (r *eventingAuthReconciler) deleteReplacedApplication(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr *eamapiv1alpha1.EventingAuth,
+) error {
+	if cr.Status.Application == nil {
+		return nil
+	}
+	id := applicationID(*cr)
+	if _, err := r.deleteApplication(ctx, p, cr); err != nil {
+		return errors.Wrap(err, "failed to delete application that is replaced by the provided credentials")
+	}
+	logger.Info("Deleted application, because the credentials are provided")
+	decisionOf(ctx).act("deleted application %s, because the credentials are provided", id)
+	return r.mutateEventingAuthStatus(ctx, cr, func(status *eamapiv1alpha1.EventingAuthStatus) {
+		status.Application = nil
+	})
+}
+
+// deliverProvidedCredentials writes the provided credentials to the application secret on the SKR cluster, unless the secret already
+// contains them.
+func (r *eventingAuthReconciler) deliverProvidedCredentials(ctx context.Context, logger logr.Logger, cr *eamapiv1alpha1.EventingAuth,
+	app eamias.Application, changed bool,
+) error {
+	var delivered bool
+	err := r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+		var err error
+		delivered, err = skrClient.HasApplicationCredentials(ctx, app)
+		return err
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to check application secret")
+	}
+	if delivered {
+		return nil
+	}
+
+	var appSecret kcorev1.Secret
+	start := time.Now()
+	err = r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+		var err error
+		appSecret, err = skrClient.CreateSecret(ctx, app, skr.SecretOptions{Immutable: cr.Spec.ImmutableSecret})
+		if err != nil {
+			return err
+		}
+		return skrClient.VerifySecret(ctx, appSecret)
+	})
+	observeSecretSync(cr.Name, start, true, err)
+	r.auditSKRSecretWrite(ctx, cr, app, "", appSecret, err)
+	if err != nil {
+		logger.Error(err, "Failed to write provided credentials to application secret on SKR")
+		if updateErr := r.updateEventingAuthStatus(ctx, cr, eamapiv1alpha1.ConditionSecretReady, err); updateErr != nil {
+			return updateErr
+		}
+		return err
+	}
+	if changed {
+		logger.Info("Wrote provided credentials to application secret on SKR")
+		r.events.normal(ctx, cr, eventReasonSecretSynced, "Wrote provided credentials to application secret %s/%s on SKR cluster",
+			appSecret.Namespace, appSecret.Name)
+	} else {
+		logger.Info("Corrected drift of application secret on SKR from the provided credentials")
+		r.events.normal(ctx, cr, eventReasonSecretSynced, "Corrected drift of application secret %s/%s on SKR cluster from the provided "+
+			"credentials", appSecret.Namespace, appSecret.Name)
+	}
+	decisionOf(ctx).act("wrote provided credentials to application secret %s/%s on SKR cluster", appSecret.Namespace, appSecret.Name)
+	cr.Status.AuthSecret = &eamapiv1alpha1.AuthSecret{
+		ClusterID:      cr.Name,
+		NamespacedName: fmt.Sprintf("%s/%s", appSecret.Namespace, appSecret.Name),
+	}
+	return nil
+}
+
+// readProvidedCredentials reads the credentials of spec.credentialsSecretRef. The provided credentials have no application ID, since the
+// OAuth client isn't managed by the manager.
+func (r *eventingAuthReconciler) readProvidedCredentials(ctx context.Context, cr eamapiv1alpha1.EventingAuth) (eamias.Application, error) {
+	ref := cr.Spec.CredentialsSecretRef
+	var secret kcorev1.Secret
+	if err := r.Client.Get(ctx, kpkgclient.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return eamias.Application{}, errors.Wrapf(err, "failed to read provided credentials secret %s/%s", ref.Namespace, ref.Name)
+	}
+	for _, key := range []string{eamias.SecretKeyClientID, eamias.SecretKeyClientSecret, eamias.SecretKeyTokenURL} {
+		if len(secret.Data[key]) == 0 {
+			return eamias.Application{}, &provider.ValidationError{
+				Message: fmt.Sprintf("provided credentials secret %s/%s has no key %s", ref.Namespace, ref.Name, key),
+			}
+		}
+	}
+	return eamias.NewApplication("", string(secret.Data[eamias.SecretKeyClientID]), string(secret.Data[eamias.SecretKeyClientSecret]),
+		string(secret.Data[eamias.SecretKeyTokenURL]), string(secret.Data[eamias.SecretKeyCertsURL])), nil
+}
+
+// credentialsFingerprint returns the hex encoded SHA-256 digest of the credentials of the application.
+func credentialsFingerprint(app eamias.Application) string {
+	credentials := app.Credentials()
+	digest := sha256.New()
+	for _, key := range eamias.SecretKeys() {
+		digest.Write([]byte(key))
+		digest.Write([]byte{0})
+		digest.Write(credentials[key])
+		digest.Write([]byte{0})
+	}
+	return hex.EncodeToString(digest.Sum(nil))
+}
+
+// remindCredentialsRotation emits a warning event if the provided credentials are older than the rotation reminder, since the manager
+// can't rotate them itself. Otherwise, the EventingAuth CR is reconciled again once the reminder is due.
+func (r *eventingAuthReconciler) remindCredentialsRotation(ctx context.Context, cr *eamapiv1alpha1.EventingAuth) kcontrollerruntime.Result {
+	provided := cr.Status.ProvidedCredentials
+	if r.credentialsRotationReminder <= 0 || provided == nil || provided.ObservedTime == nil {
+		return kcontrollerruntime.Result{}
+	}
+	age := time.Since(provided.ObservedTime.Time)
+	if age < r.credentialsRotationReminder {
+		return kcontrollerruntime.Result{RequeueAfter: r.credentialsRotationReminder - age}
+	}
+	log.FromContext(ctx).Info("Provided credentials are due for rotation", "clientID", provided.ClientID, "observed",
+		provided.ObservedTime.UTC().Format(time.RFC3339))
+	decisionOf(ctx).act("reminded rotation of provided credentials of client %s", provided.ClientID)
+	r.events.warning(ctx, cr, eventReasonCredentialsRotationDue, "Provided credentials of client %s are unchanged since %s, rotate them "+
+		"in secret %s/%s", provided.ClientID, provided.ObservedTime.UTC().Format(time.RFC3339), cr.Spec.CredentialsSecretRef.Namespace,
+		cr.Spec.CredentialsSecretRef.Name)
+	return kcontrollerruntime.Result{}
+}
+
+// eventingAuthsOfProvidedCredentialsSecret returns the EventingAuth CRs whose credentials are provided by the secret, so that changed
+// credentials are delivered right away.
+func (r *eventingAuthReconciler) eventingAuthsOfProvidedCredentialsSecret(ctx context.Context, secret kpkgclient.Object,
+) []reconcile.Request {
+	var eventingAuths eamapiv1alpha1.EventingAuthList
+	if err := r.Client.List(ctx, &eventingAuths); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list EventingAuth CRs of provided credentials secret", "secret",
+			kpkgclient.ObjectKeyFromObject(secret))
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, cr := range eventingAuths.Items {
+		ref := cr.Spec.CredentialsSecretRef
+		if ref == nil || ref.Namespace != secret.GetNamespace() || ref.Name != secret.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: kpkgclient.ObjectKeyFromObject(&cr)})
+	}
+	return requests
+}
@@ -0,0 +1,197 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	kcorev1 "k8s.io/api/core/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const providedClientID = "provided-client-id"
+
+// Since all tests use the same target cluster and therefore share the same application secret, they need to be executed serially.
+var _ = Describe("EventingAuth Controller provided credentials tests", Serial, Ordered, func() {
+	var (
+		iasClient         *recordingIasClientStub
+		eventingAuth      *eamapiv1alpha1.EventingAuth
+		crName            string
+		credentialsSecret *kcorev1.Secret
+	)
+
+	BeforeEach(func() {
+		iasClient = &recordingIasClientStub{}
+		stubIasAppCreation(iasClient)
+		crName = generateCrName()
+		createKubeconfigSecret(crName)
+		credentialsSecret = &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: skr.KcpNamespace, Name: "provided-" + crName}}
+
+		eventingAuth = createEventingAuth(crName)
+		verifyEventingAuthStatusReady(eventingAuth)
+		verifyApplicationSecretClientID("client-id-for-" + crName)
+	})
+
+	AfterEach(func() {
+		deleteEventingAuthAndVerify(eventingAuth)
+		deleteApplicationSecretOnTargetCluster()
+		deleteKubeconfigSecret(crName)
+		Expect(kpkgclient.IgnoreNotFound(k8sClient.Delete(context.TODO(), credentialsSecret))).Should(Succeed())
+		revertReadCredentialsStub()
+		revertIasNewClientStub()
+	})
+
+	It("should deliver the provided credentials before deleting the replaced application", func() {
+		// given
+		var deliveredBeforeDeletion atomic.Bool
+		iasClient.setOnDelete(func(string) {
+			var secret kcorev1.Secret
+			err := targetClusterK8sClient.Get(context.TODO(), appSecretObjectKey, &secret)
+			deliveredBeforeDeletion.Store(err == nil && string(secret.Data[eamias.SecretKeyClientID]) == providedClientID)
+		})
+		createProvidedCredentialsSecret(credentialsSecret, providedCredentials("provided-client-secret"))
+
+		// when
+		referenceProvidedCredentialsSecret(eventingAuth, credentialsSecret)
+
+		// then
+		verifyProvidedCredentialsDelivered(eventingAuth)
+		Eventually(iasClient.deletedApplications, defaultTimeout).Should(ContainElement(crName))
+		Expect(deliveredBeforeDeletion.Load()).To(BeTrue())
+	})
+
+	It("should keep the application as long as the provided credentials are invalid", func() {
+		// given
+		data := providedCredentials("")
+		createProvidedCredentialsSecret(credentialsSecret, data)
+
+		// when
+		referenceProvidedCredentialsSecret(eventingAuth, credentialsSecret)
+
+		// then
+		By(fmt.Sprintf("Verifying that EventingAuth %s has status %s", eventingAuth.Name, eamapiv1alpha1.StateNotReady))
+		Eventually(func(g Gomega) {
+			e := eamapiv1alpha1.EventingAuth{}
+			g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(eventingAuth), &e)).Should(Succeed())
+			g.Expect(e.Status.State).To(Equal(eamapiv1alpha1.StateNotReady))
+			condition := kmeta.FindStatusCondition(e.Status.Conditions, string(eamapiv1alpha1.ConditionApplicationReady))
+			g.Expect(condition).NotTo(BeNil())
+			g.Expect(condition.Status).To(Equal(kmetav1.ConditionFalse))
+			g.Expect(condition.Message).To(HavePrefix(fmt.Sprintf("provided credentials secret %s/%s has no key %s",
+				credentialsSecret.Namespace, credentialsSecret.Name, eamias.SecretKeyClientSecret)))
+			g.Expect(e.Status.Application).NotTo(BeNil())
+		}, defaultTimeout).Should(Succeed())
+		Expect(iasClient.deletedApplications()).NotTo(ContainElement(crName))
+		verifyApplicationSecretClientID("client-id-for-" + crName)
+
+		// when the provided credentials are fixed
+		data[eamias.SecretKeyClientSecret] = []byte("provided-client-secret")
+		updateProvidedCredentialsSecret(credentialsSecret, data)
+
+		// then
+		verifyProvidedCredentialsDelivered(eventingAuth)
+		Eventually(iasClient.deletedApplications, defaultTimeout).Should(ContainElement(crName))
+	})
+
+	It("should correct the drift of the application secret from the provided credentials", func() {
+		// given
+		createProvidedCredentialsSecret(credentialsSecret, providedCredentials("provided-client-secret"))
+		referenceProvidedCredentialsSecret(eventingAuth, credentialsSecret)
+		verifyProvidedCredentialsDelivered(eventingAuth)
+
+		// when the application secret is changed on the target cluster
+		By("Changing the application secret on target cluster")
+		Eventually(func(g Gomega) {
+			var secret kcorev1.Secret
+			g.Expect(targetClusterK8sClient.Get(context.TODO(), appSecretObjectKey, &secret)).Should(Succeed())
+			secret.Data[eamias.SecretKeyClientID] = []byte("drifted-client-id")
+			g.Expect(targetClusterK8sClient.Update(context.TODO(), &secret)).Should(Succeed())
+		}, defaultTimeout).Should(Succeed())
+
+		// then
+		verifyApplicationSecretClientID(providedClientID)
+
+		// when the provided credentials are rotated
+		updateProvidedCredentialsSecret(credentialsSecret, providedCredentials("rotated-client-secret"))
+
+		// then
+		By("Verifying that the rotated credentials are delivered to target cluster")
+		Eventually(func(g Gomega) {
+			var secret kcorev1.Secret
+			g.Expect(targetClusterK8sClient.Get(context.TODO(), appSecretObjectKey, &secret)).Should(Succeed())
+			g.Expect(string(secret.Data[eamias.SecretKeyClientSecret])).To(Equal("rotated-client-secret"))
+		}, defaultTimeout).Should(Succeed())
+	})
+})
+
+// providedCredentials returns the data of a provided credentials secret, which lacks the client secret if it is empty.
+func providedCredentials(clientSecret string) map[string][]byte {
+	data := map[string][]byte{
+		eamias.SecretKeyClientID: []byte(providedClientID),
+		eamias.SecretKeyTokenURL: []byte("https://provided.example.com/token"),
+		eamias.SecretKeyCertsURL: []byte("https://provided.example.com/certs"),
+	}
+	if clientSecret != "" {
+		data[eamias.SecretKeyClientSecret] = []byte(clientSecret)
+	}
+	return data
+}
+
+func createProvidedCredentialsSecret(secret *kcorev1.Secret, data map[string][]byte) {
+	By("Creating secret with provided credentials")
+	secret.Data = data
+	Expect(k8sClient.Create(context.TODO(), secret)).Should(Succeed())
+}
+
+func updateProvidedCredentialsSecret(secret *kcorev1.Secret, data map[string][]byte) {
+	By("Updating secret with provided credentials")
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(secret), secret)).Should(Succeed())
+		secret.Data = data
+		g.Expect(k8sClient.Update(context.TODO(), secret)).Should(Succeed())
+	}, defaultTimeout).Should(Succeed())
+}
+
+func referenceProvidedCredentialsSecret(cr *eamapiv1alpha1.EventingAuth, secret *kcorev1.Secret) {
+	By(fmt.Sprintf("Referencing provided credentials in EventingAuth %s", cr.Name))
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), cr)).Should(Succeed())
+		cr.Spec.CredentialsSecretRef = &eamapiv1alpha1.SecretReference{Namespace: secret.Namespace, Name: secret.Name}
+		g.Expect(k8sClient.Update(context.TODO(), cr)).Should(Succeed())
+	}, defaultTimeout).Should(Succeed())
+}
+
+func verifyProvidedCredentialsDelivered(cr *eamapiv1alpha1.EventingAuth) {
+	By(fmt.Sprintf("Verifying that EventingAuth %s delivered the provided credentials", cr.Name))
+	Eventually(func(g Gomega) {
+		e := eamapiv1alpha1.EventingAuth{}
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), &e)).Should(Succeed())
+		g.Expect(e.Status.State).To(Equal(eamapiv1alpha1.StateReady))
+		g.Expect(e.Status.Application).To(BeNil())
+		g.Expect(e.Status.ProvidedCredentials).NotTo(BeNil())
+		g.Expect(e.Status.ProvidedCredentials.ClientID).To(Equal(providedClientID))
+		g.Expect(e.Status.Conditions).To(ContainElement(conditionMatcher(
+			string(eamapiv1alpha1.ConditionApplicationReady),
+			kmetav1.ConditionTrue,
+			eamapiv1alpha1.ConditionReasonCredentialsProvided,
+			eamapiv1alpha1.ConditionMessageCredentialsProvided)))
+	}, defaultTimeout).Should(Succeed())
+	verifyApplicationSecretClientID(providedClientID)
+}
+
+func verifyApplicationSecretClientID(clientID string) {
+	By(fmt.Sprintf("Verifying that application secret on target cluster has client ID %s", clientID))
+	Eventually(func(g Gomega) {
+		var secret kcorev1.Secret
+		g.Expect(targetClusterK8sClient.Get(context.TODO(), appSecretObjectKey, &secret)).Should(Succeed())
+		g.Expect(string(secret.Data[eamias.SecretKeyClientID])).To(Equal(clientID))
+	}, defaultTimeout).Should(Succeed())
+}
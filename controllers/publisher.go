@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/audit"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+)
+
+// publisherApplicationSuffix is appended to the name of the EventingAuth CR to name the separate application of the event publisher. Since
+// the names of the applications of the event consumers are separated by dots, the names never collide.
+const publisherApplicationSuffix = "-publisher"
+
+// reconcilePublisher provisions the separate application of the event publisher and the publisher secret with spec.separatePublisherCredentials,
+// or deletes them if the credentials aren't separated anymore. The application is created in the IAS tenant of the application of the SKR
+// cluster. A missing publisher secret is restored with a new application, since the credentials of the existing application can't be read
+// again.
+func (r *eventingAuthReconciler) reconcilePublisher(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr *eamapiv1alpha1.EventingAuth,
+) (kcontrollerruntime.Result, error) {
+	if !cr.Spec.SeparatePublisherCredentials {
+		if cr.Status.Publisher == nil {
+			return kcontrollerruntime.Result{}, nil
+		}
+		if err := r.deletePublisher(ctx, p, cr); err != nil {
+			return kcontrollerruntime.Result{}, err
+		}
+		logger.Info("Deleted application of event publisher, because the credentials aren't separated anymore")
+		return kcontrollerruntime.Result{}, nil
+	}
+
+	if cr.Status.Publisher != nil {
+		var exists bool
+		err := r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+			var err error
+			exists, err = skrClient.HasPublisherSecret(ctx)
+			return err
+		})
+		if err != nil {
+			return kcontrollerruntime.Result{}, errors.Wrap(err, "failed to check publisher secret")
+		}
+		if exists {
+			return kcontrollerruntime.Result{}, nil
+		}
+	}
+	if shuttingDown(ctx) {
+		logger.Info("Not creating application of event publisher, because the manager stops")
+		decisionOf(ctx).act("deferred creation of application of event publisher, because the manager stops")
+		return kcontrollerruntime.Result{Requeue: true}, nil
+	}
+	if err := r.createPublisher(ctx, p, cr); err != nil {
+		return kcontrollerruntime.Result{}, err
+	}
+	logger.Info("Created application of event publisher")
+	return kcontrollerruntime.Result{}, nil
+}
+
+// createPublisher creates the separate application of the event publisher, replacing an existing application with the same name, and writes
+// its credentials to the publisher secret. The application is recorded in the status before the secret is written, so that it is deleted
+// with the EventingAuth CR even if the secret can't be written.
+func (r *eventingAuthReconciler) createPublisher(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.EventingAuth) error {
+	name := cr.Name + publisherApplicationSuffix
+	tenant := owningIASTenant(*cr)
+	// The ID of the application of the SKR cluster must not identify the application of the publisher.
+	app, err := createApplicationInIASTenant(eamias.WithApplicationID(ctx, publisherApplicationID(*cr)), r.Client, p, tenant, name)
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionCreateApplication, Application: name, ApplicationID: app.GetID(),
+		Tenant: tenant}, err)
+	if err != nil {
+		return errors.Wrap(err, "failed to create application of event publisher")
+	}
+	decisionOf(ctx).act("created application %s of event publisher", app.GetID())
+	r.events.normal(ctx, cr, eventReasonApplicationCreated, "Created application %s of event publisher", name)
+
+	publisher := &eamapiv1alpha1.PublisherApplication{Name: name, UUID: app.GetID()}
+	if err := r.mutateEventingAuthStatus(ctx, cr, func(status *eamapiv1alpha1.EventingAuthStatus) {
+		status.Publisher = publisher.DeepCopy()
+	}); err != nil {
+		return err
+	}
+
+	var secret kcorev1.Secret
+	err = r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+		var err error
+		secret, err = skrClient.CreatePublisherSecret(ctx, app, skr.SecretOptions{Immutable: cr.Spec.ImmutableSecret})
+		return err
+	})
+	target := auditTargetSKR
+	if err == nil {
+		target += ":" + secret.Namespace + "/" + secret.Name
+	}
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionWriteSecret, Application: name, ApplicationID: app.GetID(), Tenant: tenant,
+		Target: target}, err)
+	if err != nil {
+		return errors.Wrap(err, "failed to create publisher secret")
+	}
+	decisionOf(ctx).act("created publisher secret %s/%s on SKR cluster", secret.Namespace, secret.Name)
+	r.events.normal(ctx, cr, eventReasonSecretSynced, "Created publisher secret %s/%s on SKR cluster", secret.Namespace, secret.Name)
+
+	publisher.SecretNamespacedName = fmt.Sprintf("%s/%s", secret.Namespace, secret.Name)
+	return r.mutateEventingAuthStatus(ctx, cr, func(status *eamapiv1alpha1.EventingAuthStatus) {
+		status.Publisher = publisher.DeepCopy()
+	})
+}
+
+// deletePublisher deletes the separate application of the event publisher and the publisher secret, and removes the publisher from the
+// status.
+func (r *eventingAuthReconciler) deletePublisher(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.EventingAuth) error {
+	if cr.Status.Publisher == nil {
+		return nil
+	}
+	publisher := *cr.Status.Publisher
+	tenant := owningIASTenant(*cr)
+	err := p.DeleteApplication(eamias.WithApplicationID(ctx, publisher.UUID), publisher.Name)
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteApplication, Application: publisher.Name, ApplicationID: publisher.UUID,
+		Tenant: tenant}, err)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete application of event publisher")
+	}
+	decisionOf(ctx).act("deleted application %s of event publisher", publisher.UUID)
+
+	if r.skrClientCache != nil {
+		err := r.withSkrClient(ctx, cr.Name, func(skrClient skr.Client) error {
+			return skrClient.DeletePublisherSecret(ctx)
+		})
+		r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteSecret, Application: publisher.Name, ApplicationID: publisher.UUID,
+			Tenant: tenant, Target: auditTargetSKR}, err)
+		if ignoreMissingSkrCluster(err) != nil {
+			return errors.Wrap(err, "failed to delete publisher secret")
+		}
+	}
+
+	return r.mutateEventingAuthStatus(ctx, cr, func(status *eamapiv1alpha1.EventingAuthStatus) {
+		status.Publisher = nil
+	})
+}
+
+// publisherApplicationID returns the ID of the application of the event publisher in the status of the EventingAuth CR, or an empty ID if
+// the publisher has no application.
+func publisherApplicationID(cr eamapiv1alpha1.EventingAuth) string {
+	if cr.Status.Publisher == nil {
+		return ""
+	}
+	return cr.Status.Publisher.UUID
+}
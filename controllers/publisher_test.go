@@ -0,0 +1,146 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var publisherSecretObjectKey = kpkgclient.ObjectKey{Name: skr.PublisherSecretName, Namespace: skr.ApplicationSecretNamespace}
+
+// Since all tests use the same target cluster and therefore share the same application secret, they need to be executed serially.
+var _ = Describe("EventingAuth Controller separate publisher credentials tests", Serial, Ordered, func() {
+	var (
+		iasClient    *recordingIasClientStub
+		eventingAuth *eamapiv1alpha1.EventingAuth
+		crName       string
+	)
+
+	BeforeEach(func() {
+		iasClient = &recordingIasClientStub{}
+		stubIasAppCreation(iasClient)
+		crName = generateCrName()
+		createKubeconfigSecret(crName)
+	})
+
+	AfterEach(func() {
+		deleteEventingAuthAndVerify(eventingAuth)
+		deleteApplicationSecretOnTargetCluster()
+		Expect(kpkgclient.IgnoreNotFound(targetClusterK8sClient.Delete(context.TODO(), &kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: publisherSecretObjectKey.Namespace, Name: publisherSecretObjectKey.Name},
+		}))).Should(Succeed())
+		deleteKubeconfigSecret(crName)
+		revertReadCredentialsStub()
+		revertIasNewClientStub()
+	})
+
+	It("should provision the publisher application and delete it when the credentials aren't separated anymore", func() {
+		// given
+		name := crName + "-publisher"
+
+		// when
+		eventingAuth = createEventingAuthWithSeparatePublisherCredentials(crName)
+
+		// then
+		verifyEventingAuthStatusReady(eventingAuth)
+		verifyPublisherInStatus(eventingAuth, &eamapiv1alpha1.PublisherApplication{
+			Name:                 name,
+			UUID:                 "id-for-" + name,
+			SecretNamespacedName: publisherSecretObjectKey.String(),
+		})
+		verifyPublisherSecretClientID("client-id-for-" + name)
+		verifyApplicationSecretClientID("client-id-for-" + crName)
+
+		// when
+		By(fmt.Sprintf("Disabling separate publisher credentials of EventingAuth %s", crName))
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(eventingAuth), eventingAuth)).Should(Succeed())
+			eventingAuth.Spec.SeparatePublisherCredentials = false
+			g.Expect(k8sClient.Update(context.TODO(), eventingAuth)).Should(Succeed())
+		}, defaultTimeout).Should(Succeed())
+
+		// then
+		verifyPublisherInStatus(eventingAuth, nil)
+		verifyPublisherSecretDoesNotExist()
+		Expect(iasClient.deletedApplications()).To(ContainElement(name))
+		Expect(iasClient.deletedApplications()).NotTo(ContainElement(crName))
+		verifyApplicationSecretClientID("client-id-for-" + crName)
+	})
+
+	It("should not record the publisher application if it can't be created", func() {
+		// given
+		eventingAuth = createEventingAuth(crName)
+		verifyEventingAuthStatusReady(eventingAuth)
+		iasClient.setCreationErr(errIASApplicationCreation)
+
+		// when
+		By(fmt.Sprintf("Enabling separate publisher credentials of EventingAuth %s", crName))
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(eventingAuth), eventingAuth)).Should(Succeed())
+			eventingAuth.Spec.SeparatePublisherCredentials = true
+			g.Expect(k8sClient.Update(context.TODO(), eventingAuth)).Should(Succeed())
+		}, defaultTimeout).Should(Succeed())
+
+		// then
+		verifyEventingAuthEvent(eventingAuth, kcorev1.EventTypeWarning, "ReconcileFailed")
+		e := eamapiv1alpha1.EventingAuth{}
+		Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(eventingAuth), &e)).Should(Succeed())
+		Expect(e.Status.Publisher).To(BeNil())
+		verifyPublisherSecretDoesNotExist()
+		verifyApplicationSecretClientID("client-id-for-" + crName)
+
+		// when the creation succeeds on a retry
+		iasClient.setCreationErr(nil)
+
+		// then
+		verifyPublisherSecretClientID("client-id-for-" + crName + "-publisher")
+	})
+})
+
+func createEventingAuthWithSeparatePublisherCredentials(name string) *eamapiv1alpha1.EventingAuth {
+	e := eamapiv1alpha1.EventingAuth{
+		ObjectMeta: kmetav1.ObjectMeta{Name: name, Namespace: skr.KcpNamespace},
+		Spec:       eamapiv1alpha1.EventingAuthSpec{SeparatePublisherCredentials: true},
+	}
+
+	By("Creating EventingAuth CR with separate publisher credentials")
+	Expect(k8sClient.Create(context.TODO(), &e)).Should(Succeed())
+
+	return &e
+}
+
+func verifyPublisherInStatus(cr *eamapiv1alpha1.EventingAuth, publisher *eamapiv1alpha1.PublisherApplication) {
+	By(fmt.Sprintf("Verifying the publisher in the status of EventingAuth %s", cr.Name))
+	Eventually(func(g Gomega) {
+		e := eamapiv1alpha1.EventingAuth{}
+		g.Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(cr), &e)).Should(Succeed())
+		g.Expect(e.Status.Publisher).To(Equal(publisher))
+	}, defaultTimeout).Should(Succeed())
+}
+
+func verifyPublisherSecretClientID(clientID string) {
+	By(fmt.Sprintf("Verifying that publisher secret on target cluster has client ID %s", clientID))
+	Eventually(func(g Gomega) {
+		var secret kcorev1.Secret
+		g.Expect(targetClusterK8sClient.Get(context.TODO(), publisherSecretObjectKey, &secret)).Should(Succeed())
+		g.Expect(string(secret.Data[eamias.SecretKeyClientID])).To(Equal(clientID))
+	}, defaultTimeout).Should(Succeed())
+}
+
+func verifyPublisherSecretDoesNotExist() {
+	By("Verifying that publisher secret does not exist on target cluster")
+	Eventually(func(g Gomega) {
+		err := targetClusterK8sClient.Get(context.TODO(), publisherSecretObjectKey, &kcorev1.Secret{})
+		g.Expect(kapierrors.IsNotFound(err)).To(BeTrue())
+	}, defaultTimeout).Should(Succeed())
+}
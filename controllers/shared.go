@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/audit"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// sharedApplicationPrefix is prepended to the name of the group of a shared application to name the application, so that it never
+	// collides with the applications of the EventingAuth CRs, which are named after the runtime IDs.
+	sharedApplicationPrefix = "shared-"
+	// sharedCredentialsSecretPrefix is prepended to the name of the group of a shared application to name the secret on KCP that keeps the
+	// credentials of the application for the EventingAuth CRs that join the group later.
+	sharedCredentialsSecretPrefix = "eventing-auth-shared-"
+	// sharedApplicationIDAnnotation is the annotation of the shared credentials secret with the ID of the shared application.
+	sharedApplicationIDAnnotation = "eventingauth.operator.kyma-project.io/application-id"
+	// sharedApplicationTenantAnnotation is the annotation of the shared credentials secret with the IAS tenant of the shared application.
+	sharedApplicationTenantAnnotation = "eventingauth.operator.kyma-project.io/ias-tenant"
+)
+
+// sharesApplication returns true if the EventingAuth CR shares its application with the other EventingAuth CRs of its group.
+func sharesApplication(cr eamapiv1alpha1.EventingAuth) bool {
+	return cr.Spec.SharedApplication != ""
+}
+
+// applicationName returns the name of the application of the EventingAuth CR, which is the name of the CR unless the application is shared.
+func applicationName(cr eamapiv1alpha1.EventingAuth) string {
+	if !sharesApplication(cr) {
+		return cr.Name
+	}
+	return sharedApplicationPrefix + cr.Spec.SharedApplication
+}
+
+// sharedCredentialsSecretKey returns the key of the secret on KCP that keeps the credentials of the shared application of the EventingAuth CR.
+func sharedCredentialsSecretKey(cr eamapiv1alpha1.EventingAuth) kpkgclient.ObjectKey {
+	return kpkgclient.ObjectKey{Namespace: cr.Namespace, Name: sharedCredentialsSecretPrefix + cr.Spec.SharedApplication}
+}
+
+// joinSharedApplication returns the shared application of the group of the EventingAuth CR. The application is created with the first
+// EventingAuth CR of the group, and its credentials are kept in a secret on KCP, since they can't be read from the provider again. The
+// EventingAuth CRs that join the group later get the credentials of the secret.
+func (r *eventingAuthReconciler) joinSharedApplication(ctx context.Context, logger logr.Logger, p provider.Provider,
+	cr eamapiv1alpha1.EventingAuth,
+) (createdApplication, error) {
+	// The applications of a group are joined and left one at a time, so that a leaving EventingAuth CR never deletes the application
+	// that another EventingAuth CR joins meanwhile.
+	r.sharedApplications.Lock()
+	defer r.sharedApplications.Unlock()
+
+	tenant := requestedIASTenant(cr.Spec)
+	var secret kcorev1.Secret
+	err := r.Client.Get(ctx, sharedCredentialsSecretKey(cr), &secret)
+	if err == nil {
+		if owner := secret.Annotations[sharedApplicationTenantAnnotation]; owner != tenant {
+			return createdApplication{}, errors.Errorf("shared application %s is owned by IAS tenant %q instead of %q", applicationName(cr),
+				owner, tenant)
+		}
+		app := eamias.NewApplication(secret.Annotations[sharedApplicationIDAnnotation],
+			string(secret.Data[eamias.SecretKeyClientID]), string(secret.Data[eamias.SecretKeyClientSecret]),
+			string(secret.Data[eamias.SecretKeyTokenURL]), string(secret.Data[eamias.SecretKeyCertsURL]))
+		logger.Info("Joined shared application", "application", applicationName(cr))
+		decisionOf(ctx).act("joined shared application %s", app.GetID())
+		return createdApplication{application: app, tenant: tenant, joined: true}, nil
+	}
+	if !kapierrors.IsNotFound(err) {
+		return createdApplication{}, errors.Wrap(err, "failed to get credentials of shared application")
+	}
+
+	// The application of the group isn't identified by the ID of a previous application of the EventingAuth CR.
+	app, err := createApplicationInIASTenant(eamias.WithApplicationID(ctx, ""), r.Client, p, tenant, applicationName(cr))
+	if err != nil {
+		return createdApplication{}, err
+	}
+	secret = app.ToSecret(sharedCredentialsSecretKey(cr).Name, cr.Namespace)
+	secret.Labels = map[string]string{skr.ManagedByLabelKey: skr.ManagedByLabelValue}
+	secret.Annotations = map[string]string{sharedApplicationIDAnnotation: app.GetID(), sharedApplicationTenantAnnotation: tenant}
+	// The secret isn't owned by the EventingAuth CR that created the application, since it outlives the CR if other CRs share the application.
+	if err := r.Client.Create(ctx, &secret); err != nil {
+		return createdApplication{}, errors.Wrap(err, "failed to store credentials of shared application")
+	}
+	return createdApplication{application: app, tenant: tenant}, nil
+}
+
+// leaveSharedApplication deletes the shared application of the EventingAuth CR and the secret with its credentials on KCP, unless other
+// EventingAuth CRs that aren't being deleted share the application. It returns true if the application was deleted.
+func (r *eventingAuthReconciler) leaveSharedApplication(ctx context.Context, p provider.Provider, cr *eamapiv1alpha1.EventingAuth,
+) (bool, error) {
+	r.sharedApplications.Lock()
+	defer r.sharedApplications.Unlock()
+
+	members, err := sharedApplicationMembers(ctx, r.Client, *cr)
+	if err != nil {
+		return false, err
+	}
+	if members > 0 {
+		log.FromContext(ctx).Info("Kept shared application, because other EventingAuth CRs use it", "application", applicationName(*cr),
+			"members", members)
+		decisionOf(ctx).act("kept shared application %s, because %d other EventingAuth CRs use it", applicationID(*cr), members)
+		return false, nil
+	}
+
+	err = p.DeleteApplication(ctx, applicationName(*cr))
+	r.auditor.Record(ctx, audit.Record{Action: audit.ActionDeleteApplication, Application: applicationName(*cr),
+		ApplicationID: applicationID(*cr), Tenant: owningIASTenant(*cr)}, err)
+	if err != nil {
+		return false, err
+	}
+	secret := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: cr.Namespace, Name: sharedCredentialsSecretKey(*cr).Name}}
+	if err := r.Client.Delete(ctx, secret); kpkgclient.IgnoreNotFound(err) != nil {
+		return false, errors.Wrap(err, "failed to delete credentials of shared application")
+	}
+	return true, nil
+}
+
+// sharedApplicationMembers returns the number of the other EventingAuth CRs that share the application of the EventingAuth CR and aren't
+// being deleted. The EventingAuth CRs that are being deleted aren't counted, so that the application is deleted even if the last CRs of
+// the group are deleted at once.
+func sharedApplicationMembers(ctx context.Context, c kpkgclient.Client, cr eamapiv1alpha1.EventingAuth) (int, error) {
+	var eventingAuths eamapiv1alpha1.EventingAuthList
+	if err := c.List(ctx, &eventingAuths, kpkgclient.InNamespace(cr.Namespace)); err != nil {
+		return 0, errors.Wrap(err, "failed to list EventingAuth CRs of shared application")
+	}
+	var members int
+	for _, member := range eventingAuths.Items {
+		if member.Name != cr.Name && member.Spec.SharedApplication == cr.Spec.SharedApplication && member.DeletionTimestamp.IsZero() {
+			members++
+		}
+	}
+	return members, nil
+}
@@ -0,0 +1,139 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// Since all tests use the same target cluster and therefore share the same application secret, they need to be executed serially.
+var _ = Describe("EventingAuth Controller shared application tests", Serial, Ordered, func() {
+	var (
+		iasClient     *recordingIasClientStub
+		eventingAuths []*eamapiv1alpha1.EventingAuth
+		group         string
+	)
+
+	BeforeEach(func() {
+		iasClient = &recordingIasClientStub{}
+		stubIasAppCreation(iasClient)
+		group = generateCrName()
+		eventingAuths = []*eamapiv1alpha1.EventingAuth{
+			createEventingAuthWithSharedApplication(generateCrName(), group, eamapiv1alpha1.CredentialPropagationSKR),
+			// The EventingAuth CRs share the target cluster, so the second CR only propagates the credentials to KCP.
+			createEventingAuthWithSharedApplication(generateCrName(), group, eamapiv1alpha1.CredentialPropagationKCP),
+		}
+		verifyEventingAuthStatusReady(eventingAuths[0])
+		verifyPropagatedCopiesInStatus(eventingAuths[1], false, true)
+	})
+
+	AfterEach(func() {
+		iasClient.setDeletionErr(nil)
+		for _, eventingAuth := range eventingAuths {
+			deleteEventingAuthAndVerify(eventingAuth)
+			deleteKubeconfigSecret(eventingAuth.Name)
+			// The envtest cluster doesn't run the garbage collector, which deletes the owned KCP secret in a real cluster.
+			Expect(kpkgclient.IgnoreNotFound(k8sClient.Delete(context.TODO(), &kcorev1.Secret{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: skr.KcpNamespace, Name: kcpSecretObjectKey(eventingAuth).Name},
+			}))).Should(Succeed())
+		}
+		deleteApplicationSecretOnTargetCluster()
+		Expect(kpkgclient.IgnoreNotFound(k8sClient.Delete(context.TODO(), &kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: skr.KcpNamespace, Name: sharedCredentialsSecretObjectKey(group).Name},
+		}))).Should(Succeed())
+		revertReadCredentialsStub()
+		revertIasNewClientStub()
+	})
+
+	It("should share a single application and delete it with the last EventingAuth CR of the group", func() {
+		// given
+		name := "shared-" + group
+		Expect(iasClient.createdApplications()).To(Equal([]string{name}))
+		verifySharedCredentialsSecretExists(group)
+		verifyApplicationSecretClientID("client-id-for-" + name)
+		Expect(string(verifyKCPSecretExists(eventingAuths[1]).Data[eamias.SecretKeyClientID])).To(Equal("client-id-for-" + name))
+
+		// when
+		deleteEventingAuthAndVerify(eventingAuths[0])
+
+		// then
+		Expect(iasClient.deletedApplications()).NotTo(ContainElement(name))
+		verifySharedCredentialsSecretExists(group)
+		verifyPropagatedCopiesInStatus(eventingAuths[1], false, true)
+
+		// when
+		deleteEventingAuthAndVerify(eventingAuths[1])
+
+		// then
+		Expect(iasClient.deletedApplications()).To(ContainElement(name))
+		verifySharedCredentialsSecretDoesNotExist(group)
+	})
+
+	It("should keep the credentials of the shared application as long as the last EventingAuth CR can't delete it", func() {
+		// given
+		name := "shared-" + group
+		deleteEventingAuthAndVerify(eventingAuths[0])
+		iasClient.setDeletionErr(errIASApplicationDeletion)
+
+		// when
+		By(fmt.Sprintf("Deleting EventingAuth %s", eventingAuths[1].Name))
+		Expect(k8sClient.Delete(context.TODO(), eventingAuths[1])).Should(Succeed())
+
+		// then
+		verifyEventingAuthEvent(eventingAuths[1], kcorev1.EventTypeWarning, "ReconcileFailed")
+		Expect(k8sClient.Get(context.TODO(), kpkgclient.ObjectKeyFromObject(eventingAuths[1]), &eamapiv1alpha1.EventingAuth{})).Should(Succeed())
+		verifySharedCredentialsSecretExists(group)
+		Expect(iasClient.deletedApplications()).To(BeEmpty())
+
+		// when the deletion succeeds on a retry
+		iasClient.setDeletionErr(nil)
+
+		// then
+		deleteEventingAuthAndVerify(eventingAuths[1])
+		Expect(iasClient.deletedApplications()).To(ContainElement(name))
+		verifySharedCredentialsSecretDoesNotExist(group)
+	})
+})
+
+func createEventingAuthWithSharedApplication(name, group string, propagation eamapiv1alpha1.CredentialPropagation,
+) *eamapiv1alpha1.EventingAuth {
+	createKubeconfigSecret(name)
+	e := eamapiv1alpha1.EventingAuth{
+		ObjectMeta: kmetav1.ObjectMeta{Name: name, Namespace: skr.KcpNamespace},
+		Spec:       eamapiv1alpha1.EventingAuthSpec{SharedApplication: group, CredentialPropagation: propagation},
+	}
+
+	By(fmt.Sprintf("Creating EventingAuth CR %s of shared application group %s", name, group))
+	Expect(k8sClient.Create(context.TODO(), &e)).Should(Succeed())
+
+	return &e
+}
+
+func sharedCredentialsSecretObjectKey(group string) kpkgclient.ObjectKey {
+	return kpkgclient.ObjectKey{Namespace: skr.KcpNamespace, Name: "eventing-auth-shared-" + group}
+}
+
+func verifySharedCredentialsSecretExists(group string) {
+	By(fmt.Sprintf("Verifying that credentials secret of shared application group %s exists", group))
+	Eventually(func(g Gomega) {
+		g.Expect(k8sClient.Get(context.TODO(), sharedCredentialsSecretObjectKey(group), &kcorev1.Secret{})).Should(Succeed())
+	}, defaultTimeout).Should(Succeed())
+}
+
+func verifySharedCredentialsSecretDoesNotExist(group string) {
+	By(fmt.Sprintf("Verifying that credentials secret of shared application group %s does not exist", group))
+	Eventually(func(g Gomega) {
+		err := k8sClient.Get(context.TODO(), sharedCredentialsSecretObjectKey(group), &kcorev1.Secret{})
+		g.Expect(kapierrors.IsNotFound(err)).To(BeTrue())
+	}, defaultTimeout).Should(Succeed())
+}
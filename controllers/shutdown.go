@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultShutdownGracePeriod is the default duration that the in-flight reconciliations have to finish when the manager stops.
+const DefaultShutdownGracePeriod = 30 * time.Second
+
+// shutdownGracePeriod is the grace period of the in-flight reconciliations. It is only set before the controllers are started.
+var shutdownGracePeriod = DefaultShutdownGracePeriod //nolint:gochecknoglobals // Shared by the controllers of the manager.
+
+// SetShutdownGracePeriod sets the duration that the in-flight reconciliations of the controllers have to finish when the manager stops.
+// The context of a reconciliation is only cancelled when the grace period elapsed, so that a reconciliation that already created an
+// application in an identity provider can still deliver its credentials, instead of leaving a half-created application behind.
+func SetShutdownGracePeriod(gracePeriod time.Duration) {
+	shutdownGracePeriod = gracePeriod
+}
+
+type shutdownKey struct{}
+
+// detachFromShutdown returns a context of a reconciliation that is cancelled when the grace period elapsed after the manager started
+// to stop, instead of right away. The returned function must be called when the reconciliation returns.
+func detachFromShutdown(ctx context.Context) (context.Context, context.CancelFunc) {
+	gracePeriod := shutdownGracePeriod
+	detached, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	var timer atomic.Pointer[time.Timer]
+	stop := context.AfterFunc(ctx, func() {
+		timer.Store(time.AfterFunc(gracePeriod, cancel))
+	})
+	return context.WithValue(detached, shutdownKey{}, ctx.Done()), func() {
+		stop()
+		if t := timer.Load(); t != nil {
+			t.Stop()
+		}
+		cancel()
+	}
+}
+
+// shuttingDown returns whether the manager stops during the reconciliation of the context. A reconciliation that is shutting down
+// doesn't start new operations in the identity providers, like the creation of an application, and leaves them to the next leader.
+func shuttingDown(ctx context.Context) bool {
+	done, ok := ctx.Value(shutdownKey{}).(<-chan struct{})
+	if !ok {
+		return ctx.Err() != nil
+	}
+	select {
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
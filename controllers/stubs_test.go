@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/google/uuid"
 	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
@@ -15,12 +17,14 @@ import (
 )
 
 var (
-	originalNewIasClientFunc    func(iasTenantUrl, user, password string) (eamias.Client, error)
+	originalNewIasClientFunc    func(iasTenantUrl, user, password string, opts eamias.ClientOptions) (eamias.Client, error)
 	originalReadCredentialsFunc func(namespace, name string, k8sClient client.Client) (*eamias.Credentials, error)
-	originalNewSkrClientFunc    func(k8sClient client.Client, targetClusterId string) (skr.Client, error)
+	originalNewSkrClientFunc    func(kubeconfig []byte, opts skr.ClientOptions) (skr.Client, error)
 
 	errIASApplicationCreation = errors.New("stubbed IAS application creation error")
 	errSKRSecretCreation      = errors.New("stubbed skr secret creation error")
+	errIASApplicationAdoption = errors.New("stubbed IAS application adoption error")
+	errIASApplicationDeletion = errors.New("stubbed IAS application deletion error")
 )
 
 func stubSuccessfulIasAppCreation() {
@@ -52,14 +56,48 @@ func (i iasClientStub) CreateApplication(_ context.Context, name string) (eamias
 	), nil
 }
 
+func (i iasClientStub) UpdateApplication(_ context.Context, _ string) error {
+	return nil
+}
+
+func (i iasClientStub) RotateSecret(ctx context.Context, name string) (eamias.Application, error) {
+	return i.CreateApplication(ctx, name)
+}
+
+func (i iasClientStub) AdoptApplication(ctx context.Context, _, name string) (eamias.Application, error) {
+	return i.CreateApplication(ctx, name)
+}
+
 func (i iasClientStub) DeleteApplication(_ context.Context, _ string) error {
 	return nil
 }
 
+func (i iasClientStub) ListManagedApplications(_ context.Context) ([]eamias.ManagedApplication, error) {
+	return nil, nil
+}
+
+func (i iasClientStub) DeleteApplicationByID(_ context.Context, _, _ string) error {
+	return nil
+}
+
+func (i iasClientStub) GetTokenURL(_ context.Context) (*string, error) {
+	tokenURL := "https://test-token-url.com/token"
+	return &tokenURL, nil
+}
+
+func (i iasClientStub) GetJWKSURI(_ context.Context) (*string, error) {
+	jwksURI := "https://test-token-url.com/certs"
+	return &jwksURI, nil
+}
+
 func (i iasClientStub) GetCredentials() *eamias.Credentials {
 	return &eamias.Credentials{}
 }
 
+func (i iasClientStub) Probe(_ context.Context) error {
+	return nil
+}
+
 type appCreationFailsIasClientStub struct {
 	iasClientStub
 }
@@ -68,6 +106,137 @@ func (i appCreationFailsIasClientStub) CreateApplication(_ context.Context, _ st
 	return eamias.Application{}, errIASApplicationCreation
 }
 
+// recordingIasClientStub records the names of the applications that are created and deleted, so that tests can verify which applications
+// the controllers manage.
+type recordingIasClientStub struct {
+	iasClientStub
+	mu      sync.Mutex
+	created []string
+	deleted []string
+	// deletedIDs are the IDs of the applications that are deleted by their ID.
+	deletedIDs []string
+	// onDelete is called before an application is deleted, so that tests can verify the state of the clusters at the deletion.
+	onDelete func(name string)
+	// adoptionErr fails the adoptions of applications, if it is set.
+	adoptionErr error
+	// creationErr fails the creations of applications, if it is set.
+	creationErr error
+	// deletionErr fails the deletions of applications by their name, if it is set.
+	deletionErr error
+	// deletionFailures is the number of the next deletions of applications by their name that fail with errIASApplicationDeletion.
+	deletionFailures int
+	// tokenURL is the token URL of the tenant and its created applications, if it is set.
+	tokenURL string
+}
+
+func (i *recordingIasClientStub) CreateApplication(ctx context.Context, name string) (eamias.Application, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.creationErr != nil {
+		return eamias.Application{}, i.creationErr
+	}
+	i.created = append(i.created, name)
+	if i.tokenURL != "" {
+		return eamias.NewApplication(fmt.Sprintf("id-for-%s", name), fmt.Sprintf("client-id-for-%s", name), "test-client-secret", i.tokenURL,
+			"https://test-token-url.com/certs"), nil
+	}
+	return i.iasClientStub.CreateApplication(ctx, name)
+}
+
+func (i *recordingIasClientStub) GetTokenURL(ctx context.Context) (*string, error) {
+	if i.tokenURL != "" {
+		tokenURL := i.tokenURL
+		return &tokenURL, nil
+	}
+	return i.iasClientStub.GetTokenURL(ctx)
+}
+
+func (i *recordingIasClientStub) DeleteApplication(_ context.Context, name string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.deletionErr != nil {
+		return i.deletionErr
+	}
+	if i.deletionFailures > 0 {
+		i.deletionFailures--
+		return errIASApplicationDeletion
+	}
+	if i.onDelete != nil {
+		i.onDelete(name)
+	}
+	i.deleted = append(i.deleted, name)
+	return nil
+}
+
+// AdoptApplication adopts the application with the given ID, whose client ID is derived from the ID.
+func (i *recordingIasClientStub) AdoptApplication(_ context.Context, id, _ string) (eamias.Application, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.adoptionErr != nil {
+		return eamias.Application{}, i.adoptionErr
+	}
+	return eamias.NewApplication(id, fmt.Sprintf("client-id-for-%s", id), "test-client-secret", "https://test-token-url.com/token",
+		"https://test-token-url.com/certs"), nil
+}
+
+func (i *recordingIasClientStub) DeleteApplicationByID(_ context.Context, id, name string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.onDelete != nil {
+		i.onDelete(name)
+	}
+	i.deletedIDs = append(i.deletedIDs, id)
+	return nil
+}
+
+func (i *recordingIasClientStub) deletedApplicationIDs() []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return slices.Clone(i.deletedIDs)
+}
+
+func (i *recordingIasClientStub) setAdoptionErr(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.adoptionErr = err
+}
+
+func (i *recordingIasClientStub) setCreationErr(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.creationErr = err
+}
+
+func (i *recordingIasClientStub) setDeletionErr(err error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.deletionErr = err
+}
+
+func (i *recordingIasClientStub) failDeletions(n int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.deletionFailures = n
+}
+
+func (i *recordingIasClientStub) setOnDelete(onDelete func(name string)) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.onDelete = onDelete
+}
+
+func (i *recordingIasClientStub) createdApplications() []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return slices.Clone(i.created)
+}
+
+func (i *recordingIasClientStub) deletedApplications() []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return slices.Clone(i.deleted)
+}
+
 func replaceIasReadCredentialsWithStub(credentials eamias.Credentials) {
 	eamias.ReadCredentials = func(namespace, name string, k8sClient client.Client) (*eamias.Credentials, error) {
 		return &credentials, nil
@@ -93,16 +262,26 @@ func revertSkrNewClientStub() {
 }
 
 func replaceIasNewIasClientWithStub(c eamias.Client) {
-	eamias.NewClient = func(iasTenantUrl, user, password string) (eamias.Client, error) {
+	eamias.NewClient = func(iasTenantUrl, user, password string, _ eamias.ClientOptions) (eamias.Client, error) {
 		return c, nil
 	}
 }
 
+// replaceIasNewIasClientWithTenantStubs returns the client of the tenant URL for the IAS tenants, and a successful stub for the other tenants.
+func replaceIasNewIasClientWithTenantStubs(clients map[string]eamias.Client) {
+	eamias.NewClient = func(iasTenantUrl, user, password string, _ eamias.ClientOptions) (eamias.Client, error) {
+		if c, ok := clients[iasTenantUrl]; ok {
+			return c, nil
+		}
+		return iasClientStub{}, nil
+	}
+}
+
 type skrClientStub struct {
 	skr.Client
 }
 
-func (s skrClientStub) CreateSecret(_ context.Context, app eamias.Application) (kcorev1.Secret, error) {
+func (s skrClientStub) CreateSecret(_ context.Context, app eamias.Application, _ skr.SecretOptions) (kcorev1.Secret, error) {
 	return app.ToSecret(skr.ApplicationSecretName, skr.ApplicationSecretNamespace), nil
 }
 
@@ -110,6 +289,34 @@ func (s skrClientStub) HasApplicationSecret(_ context.Context) (bool, error) {
 	return false, nil
 }
 
+func (s skrClientStub) SyncApplicationSecret(_ context.Context, _ skr.SecretOptions) (*kcorev1.Secret, error) {
+	return nil, nil
+}
+
+func (s skrClientStub) SyncDiscovery(_ context.Context, _, _ string, _ skr.SecretOptions) (bool, error) {
+	return false, nil
+}
+
+func (s skrClientStub) ApplyObject(_ context.Context, _ client.Object) error {
+	return nil
+}
+
+func (s skrClientStub) DeleteObject(_ context.Context, _ client.Object) error {
+	return nil
+}
+
+func (s skrClientStub) GetServiceProxy(_ context.Context, _, _, _, _ string) ([]byte, error) {
+	return nil, nil
+}
+
+func (s skrClientStub) VerifySecret(_ context.Context, _ kcorev1.Secret) error {
+	return nil
+}
+
+func (s skrClientStub) HasApplicationCredentials(_ context.Context, _ eamias.Application) (bool, error) {
+	return true, nil
+}
+
 func (s skrClientStub) DeleteSecret(_ context.Context) error {
 	return nil
 }
@@ -128,12 +335,14 @@ type secretCreationFailedSkrClientStub struct {
 	skrClientStub
 }
 
-func (s secretCreationFailedSkrClientStub) CreateSecret(_ context.Context, _ eamias.Application) (kcorev1.Secret, error) {
+func (s secretCreationFailedSkrClientStub) CreateSecret(_ context.Context, _ eamias.Application, _ skr.SecretOptions) (kcorev1.Secret, error) {
 	return kcorev1.Secret{}, errSKRSecretCreation
 }
 
 func replaceSkrClientWithStub(c skr.Client) {
-	skr.NewClient = func(k8sClient client.Client, targetClusterId string) (skr.Client, error) {
+	// The SKR client is cached by the controller, but a client is not reused after an operation on the SKR failed. Stubs that only
+	// fail on some operations are therefore replaced on the next reconciliation.
+	skr.NewClient = func(kubeconfig []byte, opts skr.ClientOptions) (skr.Client, error) {
 		return c, nil
 	}
 }
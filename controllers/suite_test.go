@@ -20,6 +20,7 @@ import (
 	"context"
 	//+kubebuilder:scaffold:imports
 	"log"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
@@ -27,6 +28,7 @@ import (
 
 	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
 	"github.com/kyma-project/eventing-auth-manager/controllers"
+	"github.com/kyma-project/eventing-auth-manager/internal/provider"
 	"github.com/kyma-project/eventing-auth-manager/internal/skr"
 	klmapiv1beta1 "github.com/kyma-project/lifecycle-manager/api/v1beta1"
 	kcorev1 "k8s.io/api/core/v1"
@@ -53,6 +55,11 @@ import (
 
 const (
 	defaultTimeout = time.Second * 60
+	// iasTenantFailoverDelay shortens the failover to the fallback IAS tenant, so that it happens within the timeout of the tests.
+	iasTenantFailoverDelay = time.Second * 3
+	// applicationDeletionMaxAttempts orphans the applications after a few failed deletions, so that it happens within the timeout of the
+	// tests.
+	applicationDeletionMaxAttempts = 3
 )
 
 var (
@@ -144,12 +151,33 @@ var _ = BeforeSuite(func(specCtx SpecContext) {
 	// Since we are replacing in some test scenarios the original functions we need to keep them, so we are able to reset them after the tests.
 	storeOriginalsOfStubbedFunctions()
 
-	kymaReconciler := controllers.NewKymaReconciler(mgr.GetClient(), mgr.GetScheme())
+	kymaReconciler := controllers.NewKymaReconciler(mgr.GetClient(), mgr.GetScheme(), true, "")
 	Expect(kymaReconciler.SetupWithManager(mgr)).Should(Succeed())
 
-	eventingAuthReconciler := controllers.NewEventingAuthReconciler(mgr.GetClient(), mgr.GetScheme())
+	iasClientPool := provider.NewIASClientPool(mgr.GetClient())
+	iasTenantReconciler := controllers.NewIASTenantReconciler(mgr.GetClient(), mgr.GetScheme(), iasClientPool, http.DefaultClient,
+		mgr.GetEventRecorderFor("iastenant-controller"))
+	Expect(iasTenantReconciler.SetupWithManager(mgr)).Should(Succeed())
+
+	providers, err := provider.NewRegistry(provider.IASName, map[string]provider.Factory{provider.IASName: provider.NewIASFactory(iasClientPool)})
+	Expect(err).NotTo(HaveOccurred())
+	skrClientCache := skr.NewClientCache(skr.NewSecretKubeconfigProvider(mgr.GetClient(), mgr.GetAPIReader(), skr.DefaultKubeconfigSecretConfig()),
+		skr.DefaultClientOptions(), skr.DefaultClientCacheTTL)
+	eventingAuthReconciler := controllers.NewEventingAuthReconciler(mgr.GetClient(), mgr.GetScheme(), providers,
+		controllers.EventingAuthReconcilerOptions{
+			IASClientPool:          iasClientPool,
+			SKRClientCache:         skrClientCache,
+			Recorder:               mgr.GetEventRecorderFor("eventingauth-controller"),
+			LogDecisions:           true,
+			IASTenantFailoverDelay: iasTenantFailoverDelay,
+			Deletion:               controllers.ApplicationDeletionOptions{MaxAttempts: applicationDeletionMaxAttempts},
+		})
 	Expect(eventingAuthReconciler.SetupWithManager(mgr)).Should(Succeed())
 
+	iasApplicationReconciler := controllers.NewIASApplicationReconciler(mgr.GetClient(), mgr.GetScheme(), providers,
+		mgr.GetEventRecorderFor("iasapplication-controller"), nil)
+	Expect(iasApplicationReconciler.SetupWithManager(mgr)).Should(Succeed())
+
 	go func() {
 		defer GinkgoRecover()
 		Expect(mgr.Start(ctx)).Should(Succeed())
@@ -196,7 +224,11 @@ func initTargetClusterConfig() (client.Client, error) {
 
 	if targetK8sCfgPath == "" {
 		log.Println("Starting local KubeAPI and ETCD server as target TEST_EVENTING_AUTH_TARGET_KUBECONFIG_PATH is missing")
-		targetClusterTestEnv = &envtest.Environment{}
+		// The Subscriptions of Kyma Eventing determine the event consumers on the target cluster.
+		targetClusterTestEnv = &envtest.Environment{
+			CRDDirectoryPaths:     []string{filepath.Join("testdata", "crd")},
+			ErrorIfCRDPathMissing: true,
+		}
 
 		clientConfig, err = targetClusterTestEnv.Start()
 		Expect(err).NotTo(HaveOccurred())
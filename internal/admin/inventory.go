@@ -0,0 +1,170 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// InventoryKey is the key of the inventory in the data of the inventory ConfigMap.
+	InventoryKey = "inventory.json"
+	// DefaultSnapshotInterval is the default interval in which the inventory ConfigMap is refreshed.
+	DefaultSnapshotInterval = 10 * time.Minute
+	// maxConfigMapSize is the maximum size of the data of a ConfigMap that the API server accepts.
+	maxConfigMapSize = 1 << 20
+)
+
+// Inventory is the inventory of the managed applications, which the export subcommand writes and the inventory ConfigMap contains.
+type Inventory struct {
+	// GeneratedTime is the time when the inventory was generated, which the credential ages refer to.
+	GeneratedTime kmetav1.Time    `json:"generatedTime"`
+	Items         []InventoryItem `json:"items"`
+}
+
+// InventoryItem is a managed application of the inventory.
+type InventoryItem struct {
+	Application
+	// CredentialsAgeSeconds is the age of the credentials of the application at the time of the inventory, or empty if it isn't known.
+	CredentialsAgeSeconds *int64 `json:"credentialsAgeSeconds,omitempty"`
+}
+
+// NewInventory returns the inventory of the applications with the ages of their credentials at the time now.
+func NewInventory(applications ApplicationList, now time.Time) Inventory {
+	items := make([]InventoryItem, 0, len(applications.Items))
+	for _, application := range applications.Items {
+		item := InventoryItem{Application: application}
+		if issued := application.CredentialsIssuedTime; issued != nil {
+			age := int64(now.Sub(issued.Time) / time.Second)
+			item.CredentialsAgeSeconds = &age
+		}
+		items = append(items, item)
+	}
+	return Inventory{GeneratedTime: kmetav1.NewTime(now), Items: items}
+}
+
+// SnapshotConfig defines the ConfigMap that the inventory is written to.
+type SnapshotConfig struct {
+	// Namespace and Name are the namespace and name of the inventory ConfigMap.
+	Namespace string
+	Name      string
+	// Interval is the interval in which the inventory ConfigMap is refreshed.
+	Interval time.Duration
+}
+
+// Snapshotter writes the inventory of the EventingAuth CRs to a ConfigMap on KCP in a regular interval, so that the applications, their IAS
+// tenants, and the ages of their credentials can be recovered if the statuses of the EventingAuth CRs are lost. The snapshotter must be
+// added to the manager to start writing.
+type Snapshotter struct {
+	config SnapshotConfig
+	reader kpkgclient.Reader
+	writer kpkgclient.Client
+	now    func() time.Time
+}
+
+// NewSnapshotter returns a snapshotter that lists the EventingAuth CRs of the reader, which is usually the cache of the manager, and writes
+// the inventory ConfigMap with the writer. The writer shouldn't read from the cache of the manager, which would cache all ConfigMaps of
+// the cluster.
+func NewSnapshotter(config SnapshotConfig, reader kpkgclient.Reader, writer kpkgclient.Client) (*Snapshotter, error) {
+	if config.Name == "" {
+		return nil, errors.New("name of the inventory ConfigMap must be set")
+	}
+	if config.Interval <= 0 {
+		return nil, errors.New("interval of the inventory ConfigMap must be positive")
+	}
+	return &Snapshotter{config: config, reader: reader, writer: writer, now: time.Now}, nil
+}
+
+// NeedLeaderElection returns true, so that only the leader writes the inventory ConfigMap.
+func (s *Snapshotter) NeedLeaderElection() bool {
+	return true
+}
+
+// Start writes the inventory ConfigMap right away and then in the interval of the snapshotter until the context is cancelled. A failed write
+// is logged and retried with the next interval.
+func (s *Snapshotter) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithValues("namespace", s.config.Namespace, "name", s.config.Name)
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.Snapshot(ctx); err != nil {
+			logger.Error(err, "Failed to write the inventory ConfigMap")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Snapshot writes the inventory of the EventingAuth CRs to the inventory ConfigMap, which is created if it doesn't exist. The inventory is
+// merged with the previous inventory of the ConfigMap, so that the application of an EventingAuth CR whose status lost it is kept.
+func (s *Snapshotter) Snapshot(ctx context.Context) error {
+	applications, err := ListApplications(ctx, s.reader)
+	if err != nil {
+		return errors.Wrap(err, "failed to list the EventingAuth CRs")
+	}
+	now := s.now()
+
+	configMap := &kcorev1.ConfigMap{ObjectMeta: kmetav1.ObjectMeta{Namespace: s.config.Namespace, Name: s.config.Name}}
+	_, err = controllerutil.CreateOrUpdate(ctx, s.writer, configMap, func() error {
+		var previous Inventory
+		if data, ok := configMap.Data[InventoryKey]; ok {
+			if err := json.Unmarshal([]byte(data), &previous); err != nil {
+				// The previous inventory isn't replaced by an inventory that might lack its applications.
+				return errors.Wrap(err, "failed to decode the previous inventory")
+			}
+		}
+		data, err := json.Marshal(NewInventory(mergeApplications(previous, applications), now))
+		if err != nil {
+			return errors.Wrap(err, "failed to encode the inventory")
+		}
+		if len(data) > maxConfigMapSize {
+			return errors.Errorf("inventory of %d applications exceeds the maximum size of a ConfigMap with %d bytes",
+				len(applications.Items), len(data))
+		}
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[InventoryKey] = string(data)
+		return nil
+	})
+	return errors.Wrap(err, "failed to write the inventory ConfigMap")
+}
+
+// mergeApplications returns the applications of the EventingAuth CRs, whose application is taken from the previous inventory if their
+// status doesn't contain it anymore. The applications of EventingAuth CRs that don't exist anymore are dropped, since their applications
+// were deleted with them.
+func mergeApplications(previous Inventory, applications ApplicationList) ApplicationList {
+	previousItems := make(map[string]InventoryItem, len(previous.Items))
+	for _, item := range previous.Items {
+		previousItems[item.Namespace+"/"+item.Name] = item
+	}
+	merged := ApplicationList{Items: make([]Application, 0, len(applications.Items))}
+	for _, application := range applications.Items {
+		item, ok := previousItems[application.Namespace+"/"+application.Name]
+		if ok && application.ApplicationID == "" && item.ApplicationID != "" {
+			application.Tenant = item.Tenant
+			application.ApplicationName = item.ApplicationName
+			application.ApplicationID = item.ApplicationID
+			application.CredentialsIssuedTime = item.CredentialsIssuedTime
+		}
+		if ok && application.SecretTarget == "" {
+			application.SecretTarget = item.SecretTarget
+			application.SecretClusterID = item.SecretClusterID
+		}
+		if ok && application.ProvisionedTime == nil {
+			application.ProvisionedTime = item.ProvisionedTime
+		}
+		merged.Items = append(merged.Items, application)
+	}
+	return merged
+}
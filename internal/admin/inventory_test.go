@@ -0,0 +1,167 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSnapshotter_Snapshot(t *testing.T) {
+	// given
+	now := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	issued := kmetav1.NewTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	scheme := runtime.NewScheme()
+	require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+	require.NoError(t, kcorev1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&eamapiv1alpha1.EventingAuth{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-a"},
+			Status: eamapiv1alpha1.EventingAuthStatus{
+				Application: &eamapiv1alpha1.ClusterApplication{Name: "runtime-a", UUID: "id-a", Tenant: "tenant-a",
+					CredentialsIssuedTime: &issued},
+			},
+		},
+		&kcorev1.ConfigMap{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "eventing-auth-inventory"},
+			Data:       map[string]string{"note": "kept"},
+		},
+	).Build()
+	snapshotter, err := NewSnapshotter(SnapshotConfig{Namespace: "kcp-system", Name: "eventing-auth-inventory", Interval: time.Minute},
+		c, c)
+	require.NoError(t, err)
+	snapshotter.now = func() time.Time { return now }
+
+	// when
+	err = snapshotter.Snapshot(context.TODO())
+
+	// then
+	require.NoError(t, err)
+	var configMap kcorev1.ConfigMap
+	require.NoError(t, c.Get(context.TODO(), kpkgclient.ObjectKey{Namespace: "kcp-system", Name: "eventing-auth-inventory"}, &configMap))
+	require.Equal(t, "kept", configMap.Data["note"])
+	var inventory Inventory
+	require.NoError(t, json.Unmarshal([]byte(configMap.Data[InventoryKey]), &inventory))
+	require.True(t, inventory.GeneratedTime.Equal(&kmetav1.Time{Time: now}))
+	require.Len(t, inventory.Items, 1)
+	require.Equal(t, "tenant-a", inventory.Items[0].Tenant)
+	require.Equal(t, "id-a", inventory.Items[0].ApplicationID)
+	require.Equal(t, int64(86400), *inventory.Items[0].CredentialsAgeSeconds)
+}
+
+func TestSnapshotter_Snapshot_KeepsLostApplications(t *testing.T) {
+	// given
+	now := time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)
+	issued := kmetav1.NewTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+	previous, err := json.Marshal(Inventory{
+		GeneratedTime: kmetav1.NewTime(now.Add(-time.Minute)),
+		Items: []InventoryItem{
+			{Application: Application{Namespace: "kcp-system", Name: "runtime-a", Tenant: "tenant-a", ApplicationName: "runtime-a",
+				ApplicationID: "id-a", SecretTarget: "kyma-system/eventing-webhook-auth", SecretClusterID: "runtime-a",
+				CredentialsIssuedTime: &issued}},
+			{Application: Application{Namespace: "kcp-system", Name: "runtime-deleted", ApplicationID: "id-deleted"}},
+		},
+	})
+	require.NoError(t, err)
+	scheme := runtime.NewScheme()
+	require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+	require.NoError(t, kcorev1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		// The status of the EventingAuth CR was lost, e.g. by a restore of KCP without the status subresources.
+		&eamapiv1alpha1.EventingAuth{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-a"}},
+		&kcorev1.ConfigMap{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "eventing-auth-inventory"},
+			Data:       map[string]string{InventoryKey: string(previous)},
+		},
+	).Build()
+	snapshotter, err := NewSnapshotter(SnapshotConfig{Namespace: "kcp-system", Name: "eventing-auth-inventory", Interval: time.Minute},
+		c, c)
+	require.NoError(t, err)
+	snapshotter.now = func() time.Time { return now }
+
+	// when
+	err = snapshotter.Snapshot(context.TODO())
+
+	// then the application of the EventingAuth CR is kept, while the application of the deleted EventingAuth CR is dropped
+	require.NoError(t, err)
+	var configMap kcorev1.ConfigMap
+	require.NoError(t, c.Get(context.TODO(), kpkgclient.ObjectKey{Namespace: "kcp-system", Name: "eventing-auth-inventory"}, &configMap))
+	var inventory Inventory
+	require.NoError(t, json.Unmarshal([]byte(configMap.Data[InventoryKey]), &inventory))
+	require.Len(t, inventory.Items, 1)
+	require.Equal(t, "runtime-a", inventory.Items[0].Name)
+	require.Equal(t, "tenant-a", inventory.Items[0].Tenant)
+	require.Equal(t, "id-a", inventory.Items[0].ApplicationID)
+	require.Equal(t, "kyma-system/eventing-webhook-auth", inventory.Items[0].SecretTarget)
+	require.Equal(t, int64(86400), *inventory.Items[0].CredentialsAgeSeconds)
+}
+
+func TestSnapshotter_Snapshot_KeepsUndecodableInventory(t *testing.T) {
+	// given
+	scheme := runtime.NewScheme()
+	require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+	require.NoError(t, kcorev1.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&eamapiv1alpha1.EventingAuth{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-a"}},
+		&kcorev1.ConfigMap{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "eventing-auth-inventory"},
+			Data:       map[string]string{InventoryKey: "{"},
+		},
+	).Build()
+	snapshotter, err := NewSnapshotter(SnapshotConfig{Namespace: "kcp-system", Name: "eventing-auth-inventory", Interval: time.Minute},
+		c, c)
+	require.NoError(t, err)
+
+	// when
+	err = snapshotter.Snapshot(context.TODO())
+
+	// then
+	require.ErrorContains(t, err, "failed to decode the previous inventory")
+	var configMap kcorev1.ConfigMap
+	require.NoError(t, c.Get(context.TODO(), kpkgclient.ObjectKey{Namespace: "kcp-system", Name: "eventing-auth-inventory"}, &configMap))
+	require.Equal(t, "{", configMap.Data[InventoryKey])
+}
+
+func TestNewSnapshotter(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenConfig SnapshotConfig
+		wantErr     string
+	}{
+		{
+			name:        "should return a snapshotter",
+			givenConfig: SnapshotConfig{Namespace: "kcp-system", Name: "eventing-auth-inventory", Interval: time.Minute},
+		},
+		{
+			name:        "should fail without the name of the ConfigMap",
+			givenConfig: SnapshotConfig{Namespace: "kcp-system", Interval: time.Minute},
+			wantErr:     "name of the inventory ConfigMap must be set",
+		},
+		{
+			name:        "should fail without a positive interval",
+			givenConfig: SnapshotConfig{Namespace: "kcp-system", Name: "eventing-auth-inventory"},
+			wantErr:     "interval of the inventory ConfigMap must be positive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			_, err := NewSnapshotter(tt.givenConfig, nil, nil)
+
+			// then
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
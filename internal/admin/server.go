@@ -0,0 +1,285 @@
+// Package admin serves the read-only admin API of the manager, which lists the applications that the manager manages for landscape tooling,
+// so that the tooling doesn't have to read the EventingAuth CRs itself. It also writes the inventory of the applications to a ConfigMap on
+// KCP as a recovery aid.
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/pkg/errors"
+	kauthenticationv1 "k8s.io/api/authentication/v1"
+	kauthorizationv1 "k8s.io/api/authorization/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// ApplicationsPath is the path of the endpoint that lists the managed applications.
+	ApplicationsPath = "/applications"
+	// reviewTimeout is the timeout of the TokenReview and SubjectAccessReview of a request.
+	reviewTimeout = 10 * time.Second
+	// shutdownTimeout is the duration that the server waits for the requests to finish when the manager stops.
+	shutdownTimeout = 5 * time.Second
+	// readHeaderTimeout limits the duration of reading the headers of a request, to protect the server against slow clients.
+	readHeaderTimeout = 10 * time.Second
+)
+
+// Config defines where the admin API is served.
+type Config struct {
+	// BindAddress is the address the server binds to, e.g. ':8443'.
+	BindAddress string
+	// CertDir is the directory of the 'tls.crt' and 'tls.key' files of the server certificate, which are reloaded when they change.
+	// If it is empty, the server uses a self-signed certificate.
+	CertDir string
+}
+
+// Application is a managed application as it is listed by the admin API.
+type Application struct {
+	// Namespace and Name are the namespace and name of the EventingAuth CR, whose name is the runtime ID of the managed runtime.
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Provider is the identity provider of the application, or empty for the default provider of the manager.
+	Provider string `json:"provider,omitempty"`
+	// Tenant is the IAS tenant that owns the application, or empty for the tenant of the credentials secret of the manager.
+	Tenant string `json:"tenant,omitempty"`
+	// ApplicationName and ApplicationID identify the application in the identity provider. They are empty until it is created.
+	ApplicationName string `json:"applicationName,omitempty"`
+	ApplicationID   string `json:"applicationID,omitempty"`
+	// SecretTarget is the namespaced name of the secret of the credentials on the managed runtime of SecretClusterID.
+	SecretTarget    string `json:"secretTarget,omitempty"`
+	SecretClusterID string `json:"secretClusterID,omitempty"`
+	// State is the state of the EventingAuth CR.
+	State eamapiv1alpha1.State `json:"state,omitempty"`
+	// LastError is the message of the failed condition of the EventingAuth CR that transitioned last, or empty if no condition failed.
+	LastError string `json:"lastError,omitempty"`
+	// ProvisionedTime is the time when the EventingAuth CR was ready for the first time.
+	ProvisionedTime *kmetav1.Time `json:"provisionedTime,omitempty"`
+	// CredentialsIssuedTime is the time when the credentials of the application were issued, or empty if it isn't known.
+	CredentialsIssuedTime *kmetav1.Time `json:"credentialsIssuedTime,omitempty"`
+}
+
+// ApplicationList is the response of the endpoint that lists the managed applications.
+type ApplicationList struct {
+	Items []Application `json:"items"`
+}
+
+// Server serves the admin API. The requests are authenticated with a TokenReview of their bearer token and authorized with a
+// SubjectAccessReview of the verb 'get' on the non-resource URL of their path, like the requests to the metrics endpoint behind
+// kube-rbac-proxy. The server must be added to the manager to start serving.
+type Server struct {
+	config    Config
+	reader    kpkgclient.Reader
+	clientset kubernetes.Interface
+}
+
+// NewServer returns a server that lists the EventingAuth CRs of the reader, which is usually the cache of the manager, and reviews the
+// requests with the clientset.
+func NewServer(config Config, reader kpkgclient.Reader, clientset kubernetes.Interface) (*Server, error) {
+	if config.BindAddress == "" {
+		return nil, errors.New("bind address of the admin API must be set")
+	}
+	return &Server{config: config, reader: reader, clientset: clientset}, nil
+}
+
+// NeedLeaderElection returns false, since every replica can list the applications of its cache.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// Start serves the admin API until the context is cancelled.
+func (s *Server) Start(ctx context.Context) error {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s.config.CertDir != "" {
+		watcher, err := certwatcher.New(filepath.Join(s.config.CertDir, "tls.crt"), filepath.Join(s.config.CertDir, "tls.key"))
+		if err != nil {
+			return errors.Wrap(err, "failed to load the certificate of the admin API")
+		}
+		go func() {
+			if err := watcher.Start(ctx); err != nil {
+				log.FromContext(ctx).Error(err, "Failed to watch the certificate of the admin API")
+			}
+		}()
+		tlsConfig.GetCertificate = watcher.GetCertificate
+	} else {
+		certificate, err := selfSignedCertificate()
+		if err != nil {
+			return err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	listener, err := tls.Listen("tcp", s.config.BindAddress, tlsConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to listen on the address of the admin API")
+	}
+	mux := http.NewServeMux()
+	mux.Handle(ApplicationsPath, s.Handler())
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	log.FromContext(ctx).Info("Serving the admin API", "address", s.config.BindAddress)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.Wrap(err, "failed to serve the admin API")
+	}
+	return nil
+}
+
+// Handler returns the handler of the endpoints of the admin API, which reviews each request before it is served.
+func (s *Server) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if status, err := s.review(r); err != nil {
+			if status == http.StatusInternalServerError {
+				log.FromContext(r.Context()).Error(err, "Failed to review a request to the admin API", "path", r.URL.Path)
+			}
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		applications, err := ListApplications(r.Context(), s.reader)
+		if err != nil {
+			log.FromContext(r.Context()).Error(err, "Failed to list the applications of the admin API")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(applications)
+	})
+}
+
+// review authenticates and authorizes the request. It returns the HTTP status of the response if the request is rejected.
+func (s *Server) review(r *http.Request) (int, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return http.StatusUnauthorized, errors.New("request has no bearer token")
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), reviewTimeout)
+	defer cancel()
+
+	tokenReview, err := s.clientset.AuthenticationV1().TokenReviews().Create(ctx, &kauthenticationv1.TokenReview{
+		Spec: kauthenticationv1.TokenReviewSpec{Token: token},
+	}, kmetav1.CreateOptions{})
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "failed to review the token")
+	}
+	if !tokenReview.Status.Authenticated {
+		return http.StatusUnauthorized, errors.New("token isn't authenticated")
+	}
+
+	user := tokenReview.Status.User
+	extra := make(map[string]kauthorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = kauthorizationv1.ExtraValue(value)
+	}
+	accessReview, err := s.clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, &kauthorizationv1.SubjectAccessReview{
+		Spec: kauthorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			NonResourceAttributes: &kauthorizationv1.NonResourceAttributes{
+				Path: r.URL.Path,
+				Verb: "get",
+			},
+		},
+	}, kmetav1.CreateOptions{})
+	if err != nil {
+		return http.StatusInternalServerError, errors.Wrap(err, "failed to review the access")
+	}
+	if !accessReview.Status.Allowed {
+		return http.StatusForbidden, errors.Errorf("user %s isn't allowed to get %s", user.Username, r.URL.Path)
+	}
+	return http.StatusOK, nil
+}
+
+// ListApplications returns the applications of all EventingAuth CRs of the reader, sorted by their namespace and name.
+func ListApplications(ctx context.Context, reader kpkgclient.Reader) (ApplicationList, error) {
+	var eventingAuths eamapiv1alpha1.EventingAuthList
+	if err := reader.List(ctx, &eventingAuths); err != nil {
+		return ApplicationList{}, err
+	}
+	applications := ApplicationList{Items: make([]Application, 0, len(eventingAuths.Items))}
+	for i := range eventingAuths.Items {
+		applications.Items = append(applications.Items, applicationOf(&eventingAuths.Items[i]))
+	}
+	sort.Slice(applications.Items, func(i, j int) bool {
+		a, b := applications.Items[i], applications.Items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return applications, nil
+}
+
+// applicationOf returns the application of the EventingAuth CR.
+func applicationOf(eventingAuth *eamapiv1alpha1.EventingAuth) Application {
+	application := Application{
+		Namespace:       eventingAuth.Namespace,
+		Name:            eventingAuth.Name,
+		Provider:        eventingAuth.Spec.Provider,
+		State:           eventingAuth.Status.State,
+		ProvisionedTime: eventingAuth.Status.ProvisionedTime,
+	}
+	if eventingAuth.Spec.IAS != nil {
+		application.Tenant = eventingAuth.Spec.IAS.Tenant
+	}
+	if app := eventingAuth.Status.Application; app != nil {
+		application.ApplicationName = app.Name
+		application.ApplicationID = app.UUID
+		application.CredentialsIssuedTime = app.CredentialsIssuedTime
+		if app.Tenant != "" {
+			application.Tenant = app.Tenant
+		}
+	}
+	if secret := eventingAuth.Status.AuthSecret; secret != nil {
+		application.SecretTarget = secret.NamespacedName
+		application.SecretClusterID = secret.ClusterID
+	}
+	var lastFailure *kmetav1.Condition
+	for i, condition := range eventingAuth.Status.Conditions {
+		if condition.Status == kmetav1.ConditionFalse &&
+			(lastFailure == nil || lastFailure.LastTransitionTime.Before(&condition.LastTransitionTime)) {
+			lastFailure = &eventingAuth.Status.Conditions[i]
+		}
+	}
+	if lastFailure != nil {
+		application.LastError = lastFailure.Message
+	}
+	return application
+}
+
+// selfSignedCertificate returns a self-signed certificate for the server, for clients that don't verify the certificate of the server.
+func selfSignedCertificate() (tls.Certificate, error) {
+	certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey("eventing-auth-manager-admin", nil, nil)
+	if err != nil {
+		return tls.Certificate{}, errors.Wrap(err, "failed to generate the self-signed certificate of the admin API")
+	}
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	return certificate, errors.Wrap(err, "failed to load the self-signed certificate of the admin API")
+}
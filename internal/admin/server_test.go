@@ -0,0 +1,172 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	kauthenticationv1 "k8s.io/api/authentication/v1"
+	kauthorizationv1 "k8s.io/api/authorization/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	kubetesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	validToken   = "valid-token"
+	allowedUser  = "landscape-tooling"
+	rejectedUser = "someone-else"
+)
+
+func newTestServer(t *testing.T, objects ...runtime.Object) *Server {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build()
+
+	clientset := kubefake.NewSimpleClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		review := action.(kubetesting.CreateAction).GetObject().(*kauthenticationv1.TokenReview)
+		switch review.Spec.Token {
+		case validToken:
+			review.Status = kauthenticationv1.TokenReviewStatus{Authenticated: true, User: kauthenticationv1.UserInfo{Username: allowedUser}}
+		case "other-token":
+			review.Status = kauthenticationv1.TokenReviewStatus{Authenticated: true, User: kauthenticationv1.UserInfo{Username: rejectedUser}}
+		}
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		review := action.(kubetesting.CreateAction).GetObject().(*kauthorizationv1.SubjectAccessReview)
+		review.Status.Allowed = review.Spec.User == allowedUser && review.Spec.NonResourceAttributes.Path == ApplicationsPath &&
+			review.Spec.NonResourceAttributes.Verb == "get"
+		return true, review, nil
+	})
+
+	server, err := NewServer(Config{BindAddress: ":0"}, reader, clientset)
+	require.NoError(t, err)
+	return server
+}
+
+func TestServer_Handler_Review(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenMethod   string
+		givenToken    string
+		wantStatus    int
+		wantAllowHead bool
+	}{
+		{
+			name:        "should list the applications for an authorized user",
+			givenMethod: http.MethodGet,
+			givenToken:  validToken,
+			wantStatus:  http.StatusOK,
+		},
+		{
+			name:        "should reject requests without token",
+			givenMethod: http.MethodGet,
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			name:        "should reject requests with an invalid token",
+			givenMethod: http.MethodGet,
+			givenToken:  "invalid-token",
+			wantStatus:  http.StatusUnauthorized,
+		},
+		{
+			name:        "should reject requests of users without access",
+			givenMethod: http.MethodGet,
+			givenToken:  "other-token",
+			wantStatus:  http.StatusForbidden,
+		},
+		{
+			name:          "should reject other methods than GET",
+			givenMethod:   http.MethodDelete,
+			givenToken:    validToken,
+			wantStatus:    http.StatusMethodNotAllowed,
+			wantAllowHead: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			server := newTestServer(t)
+			request := httptest.NewRequest(tt.givenMethod, ApplicationsPath, nil)
+			if tt.givenToken != "" {
+				request.Header.Set("Authorization", "Bearer "+tt.givenToken)
+			}
+			recorder := httptest.NewRecorder()
+
+			// when
+			server.Handler().ServeHTTP(recorder, request)
+
+			// then
+			require.Equal(t, tt.wantStatus, recorder.Code)
+			if tt.wantAllowHead {
+				require.Equal(t, http.MethodGet, recorder.Header().Get("Allow"))
+			}
+		})
+	}
+}
+
+func TestServer_Handler_Applications(t *testing.T) {
+	// given
+	earlier := kmetav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := kmetav1.NewTime(earlier.Add(time.Minute))
+	ready := &eamapiv1alpha1.EventingAuth{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-b"},
+		Spec:       eamapiv1alpha1.EventingAuthSpec{IAS: &eamapiv1alpha1.IASProviderSpec{Tenant: "tenant-a"}},
+		Status: eamapiv1alpha1.EventingAuthStatus{
+			State:           eamapiv1alpha1.StateReady,
+			Application:     &eamapiv1alpha1.ClusterApplication{Name: "runtime-b-app", UUID: "app-id", Tenant: "fallback-tenant"},
+			AuthSecret:      &eamapiv1alpha1.AuthSecret{NamespacedName: "kyma-system/eventing-webhook-auth", ClusterID: "runtime-b"},
+			ProvisionedTime: &earlier,
+		},
+	}
+	failed := &eamapiv1alpha1.EventingAuth{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "runtime-a"},
+		Spec:       eamapiv1alpha1.EventingAuthSpec{Provider: "keycloak"},
+		Status: eamapiv1alpha1.EventingAuthStatus{
+			State: eamapiv1alpha1.StateNotReady,
+			Conditions: []kmetav1.Condition{
+				{Type: string(eamapiv1alpha1.ConditionApplicationReady), Status: kmetav1.ConditionFalse, Message: "earlier", LastTransitionTime: earlier},
+				{Type: string(eamapiv1alpha1.ConditionSecretReady), Status: kmetav1.ConditionFalse, Message: "later", LastTransitionTime: later},
+			},
+		},
+	}
+	server := newTestServer(t, ready, failed)
+	request := httptest.NewRequest(http.MethodGet, ApplicationsPath, nil)
+	request.Header.Set("Authorization", "Bearer "+validToken)
+	recorder := httptest.NewRecorder()
+
+	// when
+	server.Handler().ServeHTTP(recorder, request)
+
+	// then
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var applications ApplicationList
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &applications))
+	require.Len(t, applications.Items, 2)
+	require.Equal(t, Application{
+		Namespace: "kcp-system",
+		Name:      "runtime-a",
+		Provider:  "keycloak",
+		State:     eamapiv1alpha1.StateNotReady,
+		LastError: "later",
+	}, applications.Items[0])
+	require.Equal(t, "runtime-b", applications.Items[1].Name)
+	require.Equal(t, "fallback-tenant", applications.Items[1].Tenant)
+	require.Equal(t, "runtime-b-app", applications.Items[1].ApplicationName)
+	require.Equal(t, "app-id", applications.Items[1].ApplicationID)
+	require.Equal(t, "kyma-system/eventing-webhook-auth", applications.Items[1].SecretTarget)
+	require.Equal(t, "runtime-b", applications.Items[1].SecretClusterID)
+	require.Equal(t, eamapiv1alpha1.StateReady, applications.Items[1].State)
+	require.Empty(t, applications.Items[1].LastError)
+	require.True(t, earlier.Equal(applications.Items[1].ProvisionedTime))
+}
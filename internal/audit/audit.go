@@ -0,0 +1,99 @@
+// Package audit records the identity operations of the manager, i.e. the creation, deletion, and rotation of the applications in the
+// identity providers and the writes of their credentials, in audit sinks for compliance audits.
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
+)
+
+// Action is an identity operation.
+type Action string
+
+const (
+	ActionCreateApplication Action = "CreateApplication"
+	ActionDeleteApplication Action = "DeleteApplication"
+	// ActionAdoptApplication takes over an existing application that wasn't created by the manager, which replaces its credentials.
+	ActionAdoptApplication Action = "AdoptApplication"
+	// ActionRotateCredentials replaces the delivered credentials with new credentials, e.g. of another application on the migration to
+	// another IAS tenant, or of the same application on a requested rotation.
+	ActionRotateCredentials Action = "RotateCredentials"
+	ActionWriteSecret       Action = "WriteSecret"
+	ActionDeleteSecret      Action = "DeleteSecret"
+)
+
+// Outcome is the outcome of an identity operation.
+type Outcome string
+
+const (
+	OutcomeSuccess Outcome = "success"
+	OutcomeFailure Outcome = "failure"
+)
+
+// Record is the audit record of an identity operation.
+type Record struct {
+	Time    time.Time `json:"time"`
+	Action  Action    `json:"action"`
+	Outcome Outcome   `json:"outcome"`
+	// Actor is the identity that performed the operation.
+	Actor string `json:"actor"`
+	// Application is the name of the application, which is the runtime ID of the SKR cluster.
+	Application string `json:"application"`
+	// ApplicationID is the ID of the application in the identity provider. It is empty if the application wasn't created.
+	ApplicationID string `json:"applicationId,omitempty"`
+	// Tenant is the IAS tenant of the application. It is empty if the application isn't owned by a requested IAS tenant.
+	Tenant string `json:"tenant,omitempty"`
+	// Target is the store of the credentials of secret writes, e.g. the application secret on the SKR cluster or a sink.
+	Target string `json:"target,omitempty"`
+	// CorrelationID identifies the reconciliation that performed the operation in the logs and traces.
+	CorrelationID string `json:"correlationId,omitempty"`
+	// Error is the redacted error of a failed operation.
+	Error string `json:"error,omitempty"`
+}
+
+// Sink stores the audit records.
+type Sink interface {
+	// Name returns the name of the sink that is used in logs.
+	Name() string
+	// Write writes the audit record.
+	Write(ctx context.Context, record Record) error
+}
+
+// Recorder records the identity operations in the audit sinks. A nil Recorder doesn't record anything.
+type Recorder struct {
+	actor  string
+	sinks  []Sink
+	logger logr.Logger
+}
+
+// NewRecorder returns a recorder that records the identity operations of the given actor in the sinks. Records that can't be written to a
+// sink are logged with the logger, since a failed audit doesn't fail the identity operation.
+func NewRecorder(actor string, sinks []Sink, logger logr.Logger) *Recorder {
+	return &Recorder{actor: actor, sinks: sinks, logger: logger.WithName("audit")}
+}
+
+// Record records the outcome of an identity operation. The record is completed with the time, the actor, the correlation ID of the context,
+// and the outcome and the redacted message of the error.
+func (r *Recorder) Record(ctx context.Context, record Record, err error) {
+	if r == nil || len(r.sinks) == 0 {
+		return
+	}
+	record.Time = time.Now().UTC()
+	record.Actor = r.actor
+	record.CorrelationID = tracing.CorrelationID(ctx)
+	record.Outcome = OutcomeSuccess
+	if err != nil {
+		record.Outcome = OutcomeFailure
+		record.Error = redact.String(err.Error())
+	}
+	for _, s := range r.sinks {
+		if writeErr := s.Write(ctx, record); writeErr != nil {
+			r.logger.Error(writeErr, "Failed to write audit record", "sink", s.Name(), "action", record.Action,
+				"application", record.Application, "correlationID", record.CorrelationID)
+		}
+	}
+}
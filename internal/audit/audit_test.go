@@ -0,0 +1,219 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type sinkStub struct {
+	records []Record
+	err     error
+}
+
+func (s *sinkStub) Name() string {
+	return "stub"
+}
+
+func (s *sinkStub) Write(_ context.Context, record Record) error {
+	s.records = append(s.records, record)
+	return s.err
+}
+
+func TestRecorder_Record(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenErr    error
+		wantOutcome Outcome
+		wantError   string
+	}{
+		{
+			name:        "should record a successful operation",
+			wantOutcome: OutcomeSuccess,
+		},
+		{
+			name:        "should record a failed operation with the redacted error",
+			givenErr:    errors.Wrap(errors.New(`{"client_secret":"s3cr3t"}`), "failed to create application"),
+			wantOutcome: OutcomeFailure,
+			wantError:   `failed to create application: {"client_secret":"[REDACTED]"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			failingSink := &sinkStub{err: errors.New("unavailable")}
+			s := &sinkStub{}
+			recorder := NewRecorder("system:serviceaccount:kcp-system:manager", []Sink{failingSink, s}, logr.Discard())
+			ctx, correlationID := tracing.WithCorrelationID(context.Background())
+
+			// when
+			recorder.Record(ctx, Record{Action: ActionCreateApplication, Application: "runtime-id", ApplicationID: "app-id", Tenant: "tenant"},
+				tt.givenErr)
+
+			// then
+			require.Len(t, failingSink.records, 1)
+			require.Len(t, s.records, 1)
+			record := s.records[0]
+			require.Equal(t, ActionCreateApplication, record.Action)
+			require.Equal(t, tt.wantOutcome, record.Outcome)
+			require.Equal(t, tt.wantError, record.Error)
+			require.Equal(t, "system:serviceaccount:kcp-system:manager", record.Actor)
+			require.Equal(t, "runtime-id", record.Application)
+			require.Equal(t, "app-id", record.ApplicationID)
+			require.Equal(t, "tenant", record.Tenant)
+			require.Equal(t, correlationID, record.CorrelationID)
+			require.NotEmpty(t, record.CorrelationID)
+			require.False(t, record.Time.IsZero())
+		})
+	}
+}
+
+func TestRecorder_Record_Nil(t *testing.T) {
+	// given
+	var recorder *Recorder
+
+	// when
+	recorder.Record(context.Background(), Record{Action: ActionDeleteApplication}, nil)
+
+	// then nothing is recorded without panicking
+}
+
+func TestFileSink_Write(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "audit.log")
+	s, err := NewFileSink(path)
+	require.NoError(t, err)
+
+	// when
+	require.NoError(t, s.Write(context.Background(), Record{Action: ActionCreateApplication, Application: "runtime-1"}))
+	require.NoError(t, s.Write(context.Background(), Record{Action: ActionWriteSecret, Application: "runtime-1", Target: "skr"}))
+
+	// then
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+	var record Record
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+	require.Equal(t, ActionWriteSecret, record.Action)
+	require.Equal(t, "skr", record.Target)
+}
+
+func TestHTTPSink_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		givenCode  int
+		wantRecord bool
+		wantErr    bool
+	}{
+		{
+			name:       "should post the record",
+			givenCode:  http.StatusCreated,
+			wantRecord: true,
+		},
+		{
+			name:      "should fail if the endpoint doesn't accept the record",
+			givenCode: http.StatusInternalServerError,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var authorization string
+			var received Record
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				authorization = req.Header.Get("Authorization")
+				_ = json.NewDecoder(req.Body).Decode(&received)
+				w.WriteHeader(tt.givenCode)
+			}))
+			defer server.Close()
+			s, err := NewHTTPSink(server.Client(), server.URL, map[string]string{"Authorization": "Bearer token"})
+			require.NoError(t, err)
+
+			// when
+			err = s.Write(context.Background(), Record{Action: ActionDeleteApplication, Application: "runtime-1"})
+
+			// then
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "Bearer token", authorization)
+			require.Equal(t, ActionDeleteApplication, received.Action)
+		})
+	}
+}
+
+func TestAuditLogSink_Write(t *testing.T) {
+	// given
+	var path, authorization string
+	var message auditLogMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/oauth/token" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"audit-token","token_type":"bearer","expires_in":3600}`))
+			return
+		}
+		path = req.URL.Path
+		authorization = req.Header.Get("Authorization")
+		_ = json.NewDecoder(req.Body).Decode(&message)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	bindingPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bindingPath, auditLogBindingURLKey), []byte(server.URL+"\n"), 0o600))
+	uaa := `{"clientid":"client-id","clientsecret":"client-secret","url":"` + server.URL + `"}`
+	require.NoError(t, os.WriteFile(filepath.Join(bindingPath, auditLogBindingUAAKey), []byte(uaa), 0o600))
+	s, err := NewAuditLogSink(server.Client(), bindingPath)
+	require.NoError(t, err)
+
+	// when
+	err = s.Write(context.Background(), Record{
+		Action:        ActionRotateCredentials,
+		Outcome:       OutcomeSuccess,
+		Actor:         "manager",
+		Application:   "runtime-1",
+		ApplicationID: "app-id",
+		Tenant:        "tenant",
+		CorrelationID: "correlation-id",
+	})
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, auditLogConfigurationChangesPath, path)
+	require.Equal(t, "Bearer audit-token", authorization)
+	require.Equal(t, "manager", message.User)
+	require.Equal(t, auditLogProviderTenant, message.Tenant)
+	require.Equal(t, map[string]string{"application": "runtime-1", "applicationId": "app-id", "tenant": "tenant"}, message.Object.ID)
+	require.Equal(t, []auditLogAttribute{
+		{Name: "action", New: string(ActionRotateCredentials)},
+		{Name: "outcome", New: string(OutcomeSuccess)},
+	}, message.Attributes)
+	require.Equal(t, map[string]string{"correlationId": "correlation-id"}, message.CustomDetails)
+}
+
+func TestNewAuditLogSink_InvalidBinding(t *testing.T) {
+	// given
+	bindingPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bindingPath, auditLogBindingURLKey), []byte("https://auditlog"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(bindingPath, auditLogBindingUAAKey), []byte(`{"clientid":"client-id"}`), 0o600))
+
+	// when
+	_, err := NewAuditLogSink(http.DefaultClient, bindingPath)
+
+	// then
+	require.Error(t, err)
+}
@@ -0,0 +1,149 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+const (
+	auditLogConfigurationChangesPath = "/audit-log/oauth2/v2/configuration-changes"
+	// auditLogProviderTenant is the placeholder of the tenant of the audit log messages that are written to the subaccount of the binding.
+	auditLogProviderTenant = "$PROVIDER"
+	auditLogObjectType     = "identity-application"
+	// Files of the service binding of the SAP Audit Log service as mounted by the SAP BTP service operator.
+	auditLogBindingURLKey = "url"
+	auditLogBindingUAAKey = "uaa"
+)
+
+type auditLogSink struct {
+	httpClient *http.Client
+	tokens     oauth2.TokenSource
+	url        string
+}
+
+// auditLogMessage is a configuration change message of the SAP Audit Log service.
+type auditLogMessage struct {
+	UUID          string              `json:"uuid"`
+	User          string              `json:"user"`
+	Time          string              `json:"time"`
+	Tenant        string              `json:"tenant"`
+	Object        auditLogObject      `json:"object"`
+	Attributes    []auditLogAttribute `json:"attributes"`
+	CustomDetails map[string]string   `json:"customDetails,omitempty"`
+}
+
+type auditLogObject struct {
+	Type string            `json:"type"`
+	ID   map[string]string `json:"id"`
+}
+
+type auditLogAttribute struct {
+	Name string `json:"name"`
+	Old  string `json:"old"`
+	New  string `json:"new"`
+}
+
+// NewAuditLogSink returns a sink that writes the audit records as configuration change messages to the SAP Audit Log service. The binding
+// path is the directory of the service binding of the SAP Audit Log service with the plan 'oauth2', which contains the files 'url' and
+// 'uaa', as mounted by the SAP BTP service operator.
+func NewAuditLogSink(httpClient *http.Client, bindingPath string) (Sink, error) {
+	if bindingPath == "" {
+		return nil, errors.New("binding path of the SAP Audit Log service must not be empty")
+	}
+	serviceURL, err := os.ReadFile(filepath.Join(bindingPath, auditLogBindingURLKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s of the SAP Audit Log binding", auditLogBindingURLKey)
+	}
+	uaaJSON, err := os.ReadFile(filepath.Join(bindingPath, auditLogBindingUAAKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s of the SAP Audit Log binding", auditLogBindingUAAKey)
+	}
+	var uaa struct {
+		ClientID     string `json:"clientid"`
+		ClientSecret string `json:"clientsecret"`
+		URL          string `json:"url"`
+	}
+	if err := json.Unmarshal(uaaJSON, &uaa); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s of the SAP Audit Log binding", auditLogBindingUAAKey)
+	}
+	if strings.TrimSpace(string(serviceURL)) == "" || uaa.ClientID == "" || uaa.ClientSecret == "" || uaa.URL == "" {
+		return nil, errors.Errorf("%s and %s of the SAP Audit Log binding must contain the service URL and the client credentials",
+			auditLogBindingURLKey, auditLogBindingUAAKey)
+	}
+	redact.Secret(uaa.ClientSecret)
+
+	oauthConfig := clientcredentials.Config{
+		ClientID:     uaa.ClientID,
+		ClientSecret: uaa.ClientSecret,
+		TokenURL:     strings.TrimSuffix(uaa.URL, "/") + "/oauth/token",
+	}
+	return &auditLogSink{
+		httpClient: httpClient,
+		// The token source caches the token until it expires.
+		tokens: oauthConfig.TokenSource(context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)),
+		url:    strings.TrimSuffix(strings.TrimSpace(string(serviceURL)), "/"),
+	}, nil
+}
+
+func (s *auditLogSink) Name() string {
+	return "sap-audit-log"
+}
+
+func (s *auditLogSink) Write(ctx context.Context, record Record) error {
+	body, err := json.Marshal(newAuditLogMessage(record))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit log message")
+	}
+	token, err := s.tokens.Token()
+	if err != nil {
+		return errors.Wrap(err, "failed to get token of the SAP Audit Log service")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url+auditLogConfigurationChangesPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	token.SetAuthHeader(req)
+	return send(s.httpClient, req)
+}
+
+// newAuditLogMessage returns the configuration change message of the audit record. The application is the changed object, and the action
+// and its outcome are the changed attributes.
+func newAuditLogMessage(record Record) auditLogMessage {
+	id := map[string]string{"application": record.Application}
+	if record.ApplicationID != "" {
+		id["applicationId"] = record.ApplicationID
+	}
+	if record.Tenant != "" {
+		id["tenant"] = record.Tenant
+	}
+	details := map[string]string{}
+	for key, value := range map[string]string{"correlationId": record.CorrelationID, "target": record.Target, "error": record.Error} {
+		if value != "" {
+			details[key] = value
+		}
+	}
+	return auditLogMessage{
+		UUID:   string(uuid.NewUUID()),
+		User:   record.Actor,
+		Time:   record.Time.UTC().Format("2006-01-02T15:04:05.000Z"),
+		Tenant: auditLogProviderTenant,
+		Object: auditLogObject{Type: auditLogObjectType, ID: id},
+		Attributes: []auditLogAttribute{
+			{Name: "action", New: string(record.Action)},
+			{Name: "outcome", New: string(record.Outcome)},
+		},
+		CustomDetails: details,
+	}
+}
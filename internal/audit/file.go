@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const auditFileMode = 0o600
+
+type fileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink returns a sink that appends the audit records as JSON lines to the file, e.g. a file that is collected by a log shipper.
+// The file is created if it doesn't exist.
+func NewFileSink(path string) (Sink, error) {
+	if path == "" {
+		return nil, errors.New("path of the audit log file must not be empty")
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, auditFileMode)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open audit log file")
+	}
+	return &fileSink{file: file}, nil
+}
+
+func (s *fileSink) Name() string {
+	return "file"
+}
+
+func (s *fileSink) Write(_ context.Context, record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit record")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The record is written with a single write, so that the lines of concurrent reconciliations aren't interleaved.
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return errors.Wrap(err, "failed to write audit record to file")
+	}
+	return nil
+}
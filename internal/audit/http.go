@@ -0,0 +1,60 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+type httpSink struct {
+	httpClient *http.Client
+	endpoint   string
+	headers    map[string]string
+}
+
+// NewHTTPSink returns a sink that posts each audit record as JSON object to the endpoint with the given headers, e.g. a header that
+// authenticates the manager.
+func NewHTTPSink(httpClient *http.Client, endpoint string, headers map[string]string) (Sink, error) {
+	if endpoint == "" {
+		return nil, errors.New("endpoint of the audit log must not be empty")
+	}
+	return &httpSink{httpClient: httpClient, endpoint: endpoint, headers: headers}, nil
+}
+
+func (s *httpSink) Name() string {
+	return "http"
+}
+
+func (s *httpSink) Write(ctx context.Context, record Record) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit record")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+	return send(s.httpClient, req)
+}
+
+// send sends the request and fails if the response doesn't have a success status.
+func send(httpClient *http.Client, req *http.Request) error {
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to send audit record")
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		b, _ := io.ReadAll(res.Body)
+		return errors.Errorf("failed to send audit record: %d %s", res.StatusCode, string(b))
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Package crds verifies at startup that the CustomResourceDefinitions of the resources of the manager are installed with the versions of
+// the manager, and optionally installs the embedded CustomResourceDefinitions of the manager.
+package crds
+
+import (
+	"context"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// fieldManager is the field manager of the installed CustomResourceDefinitions.
+const fieldManager = "eventing-auth-manager"
+
+// Requirement is a resource that must be served by the API server.
+type Requirement struct {
+	// GroupVersion is the version of the resource that the manager uses.
+	GroupVersion schema.GroupVersion
+	// Resource is the plural name of the resource, e.g. 'eventingauths'.
+	Resource string
+	// Hint tells how the CustomResourceDefinition of the resource is installed if it is missing.
+	Hint string
+}
+
+func (r Requirement) crdName() string {
+	return r.Resource + "." + r.GroupVersion.Group
+}
+
+// Verify checks that the API server serves the resources of the requirements in their versions. It returns an error that lists all
+// missing resources, instead of the cache sync errors that the manager would fail with later.
+func Verify(d discovery.DiscoveryInterface, requirements ...Requirement) error {
+	served := map[schema.GroupVersion]map[string]bool{}
+	var missing []string
+	for _, requirement := range requirements {
+		resources, ok := served[requirement.GroupVersion]
+		if !ok {
+			list, err := d.ServerResourcesForGroupVersion(requirement.GroupVersion.String())
+			if err != nil && !kapierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to discover the resources of %s", requirement.GroupVersion)
+			}
+			resources = map[string]bool{}
+			if list != nil {
+				for _, resource := range list.APIResources {
+					resources[resource.Name] = true
+				}
+			}
+			served[requirement.GroupVersion] = resources
+		}
+		if !resources[requirement.Resource] {
+			missing = append(missing, "CRD "+requirement.crdName()+" doesn't serve version "+requirement.GroupVersion.Version+", "+
+				requirement.Hint)
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New(strings.Join(missing, "; "))
+	}
+	return nil
+}
+
+// Install creates or updates the CustomResourceDefinitions of the YAML files of the file system with a server-side apply. The fields of
+// the CustomResourceDefinitions that are managed by another field manager are taken over.
+func Install(ctx context.Context, c kpkgclient.Client, manifests fs.FS) error {
+	crds, err := decode(manifests)
+	if err != nil {
+		return err
+	}
+	for _, crd := range crds {
+		if err := c.Patch(ctx, crd, kpkgclient.Apply, kpkgclient.FieldOwner(fieldManager), kpkgclient.ForceOwnership); err != nil {
+			return errors.Wrapf(err, "failed to apply CRD %s", crd.GetName())
+		}
+	}
+	return nil
+}
+
+// decode returns the CustomResourceDefinitions of the YAML files of the file system, sorted by the paths of the files.
+func decode(manifests fs.FS) ([]*unstructured.Unstructured, error) {
+	var files []string
+	err := fs.WalkDir(manifests, ".", func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() && strings.HasSuffix(path, ".yaml") {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the CRD manifests")
+	}
+	sort.Strings(files)
+
+	crds := make([]*unstructured.Unstructured, 0, len(files))
+	for _, file := range files {
+		content, err := fs.ReadFile(manifests, file)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", file)
+		}
+		crd := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(content, &crd.Object); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s", file)
+		}
+		if crd.GetKind() != "CustomResourceDefinition" {
+			return nil, errors.Errorf("%s doesn't contain a CustomResourceDefinition", file)
+		}
+		crds = append(crds, crd)
+	}
+	return crds, nil
+}
@@ -0,0 +1,89 @@
+package crds
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/kyma-project/eventing-auth-manager/config/crd"
+	"github.com/stretchr/testify/require"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	kubetesting "k8s.io/client-go/testing"
+)
+
+func TestVerify(t *testing.T) {
+	v1alpha1 := schema.GroupVersion{Group: "operator.kyma-project.io", Version: "v1alpha1"}
+	v1beta1 := schema.GroupVersion{Group: "operator.kyma-project.io", Version: "v1beta1"}
+	tests := []struct {
+		name              string
+		givenResources    []*kmetav1.APIResourceList
+		givenRequirements []Requirement
+		wantErr           string
+	}{
+		{
+			name: "should succeed if all resources are served",
+			givenResources: []*kmetav1.APIResourceList{
+				{GroupVersion: v1alpha1.String(), APIResources: []kmetav1.APIResource{{Name: "eventingauths"}, {Name: "iastenants"}}},
+				{GroupVersion: v1beta1.String(), APIResources: []kmetav1.APIResource{{Name: "kymas"}}},
+			},
+			givenRequirements: []Requirement{
+				{GroupVersion: v1alpha1, Resource: "eventingauths"},
+				{GroupVersion: v1alpha1, Resource: "iastenants"},
+				{GroupVersion: v1beta1, Resource: "kymas"},
+			},
+		},
+		{
+			name: "should list the resources that aren't served",
+			givenResources: []*kmetav1.APIResourceList{
+				{GroupVersion: v1alpha1.String(), APIResources: []kmetav1.APIResource{{Name: "eventingauths"}}},
+			},
+			givenRequirements: []Requirement{
+				{GroupVersion: v1alpha1, Resource: "eventingauths"},
+				{GroupVersion: v1alpha1, Resource: "iastenants", Hint: "install the CRDs"},
+				{GroupVersion: v1beta1, Resource: "kymas", Hint: "install lifecycle-manager"},
+			},
+			wantErr: "CRD iastenants.operator.kyma-project.io doesn't serve version v1alpha1, install the CRDs; " +
+				"CRD kymas.operator.kyma-project.io doesn't serve version v1beta1, install lifecycle-manager",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			d := &fakediscovery.FakeDiscovery{Fake: &kubetesting.Fake{Resources: tt.givenResources}}
+
+			// when
+			err := Verify(d, tt.givenRequirements...)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_decode(t *testing.T) {
+	// when
+	crds, err := decode(crd.Bases)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, crds, 3)
+	require.Equal(t, "eventingauths.operator.kyma-project.io", crds[0].GetName())
+	require.Equal(t, "iasapplications.operator.kyma-project.io", crds[1].GetName())
+	require.Equal(t, "iastenants.operator.kyma-project.io", crds[2].GetName())
+}
+
+func Test_decode_Invalid(t *testing.T) {
+	// given
+	manifests := fstest.MapFS{"bases/deployment.yaml": {Data: []byte("apiVersion: apps/v1\nkind: Deployment\n")}}
+
+	// when
+	_, err := decode(manifests)
+
+	// then
+	require.EqualError(t, err, "bases/deployment.yaml doesn't contain a CustomResourceDefinition")
+}
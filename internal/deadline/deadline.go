@@ -0,0 +1,58 @@
+// Package deadline applies a deadline to every outbound request of the manager, so that a hung connection to IAS or an unresponsive API
+// server of an SKR cluster can't block a reconciliation until the reconciliation itself is canceled.
+package deadline
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// transport derives the context of every request from the context of the caller, e.g. of the reconciliation, with the timeout of the
+// transport as deadline.
+type transport struct {
+	next    http.RoundTripper
+	timeout time.Duration
+	skip    func(*http.Request) bool
+}
+
+// NewTransport returns a transport whose requests fail once they didn't complete within the timeout, including the read of the response
+// body. The requests for which skip returns true, e.g. long-running watches, aren't limited. The given transport is returned if the timeout
+// is 0.
+func NewTransport(next http.RoundTripper, timeout time.Duration, skip func(*http.Request) bool) http.RoundTripper {
+	if timeout <= 0 {
+		return next
+	}
+	return &transport{next: next, timeout: timeout, skip: skip}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.skip != nil && t.skip(req) {
+		return t.next.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	res, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The deadline is released once the body is closed, since the body is read after RoundTrip returned.
+	res.Body = &cancelOnClose{ReadCloser: res.Body, cancel: cancel}
+	return res, nil
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// IsWatch returns true if the request is a watch of the Kubernetes API, which is kept open until the watch is stopped.
+func IsWatch(req *http.Request) bool {
+	return req.URL.Query().Get("watch") == "true"
+}
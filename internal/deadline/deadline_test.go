@@ -0,0 +1,79 @@
+package deadline
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewTransport(t *testing.T) {
+	// given
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/hung" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	defer close(release)
+	httpClient := &http.Client{Transport: NewTransport(http.DefaultTransport, 50*time.Millisecond, func(req *http.Request) bool {
+		return req.URL.Path == "/hung" && req.URL.Query().Get("skip") == "true"
+	})}
+	get := func(ctx context.Context, path string) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		res, err := httpClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		return string(body), err
+	}
+
+	// when a request completes within the timeout
+	body, err := get(context.Background(), "/")
+
+	// then its response body can be read
+	require.NoError(t, err)
+	require.Equal(t, "ok", body)
+
+	// when a request hangs
+	_, err = get(context.Background(), "/hung")
+
+	// then it fails with the deadline
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// when a skipped request hangs
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = get(ctx, "/hung?skip=true")
+
+	// then it is only canceled by the context of the caller
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond)
+}
+
+func Test_NewTransport_NoTimeout(t *testing.T) {
+	require.Equal(t, http.DefaultTransport, NewTransport(http.DefaultTransport, 0, nil))
+}
+
+func Test_IsWatch(t *testing.T) {
+	watch, err := http.NewRequest(http.MethodGet, "https://skr/api/v1/namespaces/kyma-system/secrets?watch=true", nil)
+	require.NoError(t, err)
+	list, err := http.NewRequest(http.MethodGet, "https://skr/api/v1/namespaces/kyma-system/secrets", nil)
+	require.NoError(t, err)
+
+	require.True(t, IsWatch(watch))
+	require.False(t, IsWatch(list))
+}
@@ -0,0 +1,173 @@
+// Package dryrun implements the dry run of the manager, in which the controllers compute and log every write they would perform without
+// changing the state of the clusters.
+package dryrun
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/record"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// MessagePrefix prefixes the messages of the events that are emitted in a dry run, since the actions of the events weren't performed.
+const MessagePrefix = "Dry run: "
+
+// client logs the writes of the wrapped client. The writes are either sent as server-side dry run or not sent at all.
+type client struct {
+	kpkgclient.Client
+	// serverSide defines if the writes are sent as server-side dry run, which validates them and completes the written objects like a
+	// persisted write.
+	serverSide bool
+}
+
+// watchingClient is a client whose wrapped client supports watches.
+type watchingClient struct {
+	*client
+	watcher kpkgclient.WithWatch
+}
+
+// NewClient returns a client that reads with the given client and logs its writes instead of persisting them. With serverSide, the writes
+// are sent to the API server as server-side dry run, so that they are validated. Otherwise they aren't sent at all and succeed. The returned
+// client supports watches if the given client supports them.
+func NewClient(c kpkgclient.Client, serverSide bool) kpkgclient.Client {
+	watcher, watches := c.(kpkgclient.WithWatch)
+	if serverSide {
+		c = kpkgclient.NewDryRunClient(c)
+	}
+	wrapped := &client{Client: c, serverSide: serverSide}
+	if watches {
+		return &watchingClient{client: wrapped, watcher: watcher}
+	}
+	return wrapped
+}
+
+func (c *watchingClient) Watch(ctx context.Context, list kpkgclient.ObjectList, opts ...kpkgclient.ListOption) (watch.Interface, error) {
+	return c.watcher.Watch(ctx, list, opts...)
+}
+
+func (c *client) Create(ctx context.Context, obj kpkgclient.Object, opts ...kpkgclient.CreateOption) error {
+	c.log(ctx, "create", obj, "")
+	if !c.serverSide {
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *client) Update(ctx context.Context, obj kpkgclient.Object, opts ...kpkgclient.UpdateOption) error {
+	c.log(ctx, "update", obj, "")
+	if !c.serverSide {
+		return nil
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *client) Patch(ctx context.Context, obj kpkgclient.Object, patch kpkgclient.Patch, opts ...kpkgclient.PatchOption) error {
+	c.log(ctx, "patch", obj, "")
+	if !c.serverSide {
+		return nil
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *client) Delete(ctx context.Context, obj kpkgclient.Object, opts ...kpkgclient.DeleteOption) error {
+	c.log(ctx, "delete", obj, "")
+	if !c.serverSide {
+		return nil
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *client) DeleteAllOf(ctx context.Context, obj kpkgclient.Object, opts ...kpkgclient.DeleteAllOfOption) error {
+	c.log(ctx, "delete all of", obj, "")
+	if !c.serverSide {
+		return nil
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *client) Status() kpkgclient.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+func (c *client) SubResource(subResource string) kpkgclient.SubResourceClient {
+	return &subResourceClient{SubResourceClient: c.Client.SubResource(subResource), client: c, subResource: subResource}
+}
+
+// subResourceClient logs the writes of the subresource of the wrapped client like the writes of the client.
+type subResourceClient struct {
+	kpkgclient.SubResourceClient
+	client      *client
+	subResource string
+}
+
+func (c *subResourceClient) Create(ctx context.Context, obj, subResource kpkgclient.Object, opts ...kpkgclient.SubResourceCreateOption) error {
+	c.client.log(ctx, "create", obj, c.subResource)
+	if !c.client.serverSide {
+		return nil
+	}
+	return c.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+func (c *subResourceClient) Update(ctx context.Context, obj kpkgclient.Object, opts ...kpkgclient.SubResourceUpdateOption) error {
+	c.client.log(ctx, "update", obj, c.subResource)
+	if !c.client.serverSide {
+		return nil
+	}
+	return c.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+func (c *subResourceClient) Patch(ctx context.Context, obj kpkgclient.Object, patch kpkgclient.Patch,
+	opts ...kpkgclient.SubResourcePatchOption,
+) error {
+	c.client.log(ctx, "patch", obj, c.subResource)
+	if !c.client.serverSide {
+		return nil
+	}
+	return c.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
+
+// log logs the write of the verb of the object or of its subresource.
+func (c *client) log(ctx context.Context, verb string, obj kpkgclient.Object, subResource string) {
+	kind := fmt.Sprintf("%T", obj)
+	if gvk, err := c.GroupVersionKindFor(obj); err == nil {
+		kind = gvk.Kind
+	}
+	message := "Dry run: skipped write"
+	if c.serverSide {
+		message = "Dry run: sent write as server-side dry run"
+	}
+	keysAndValues := []any{"verb", verb, "kind", kind, "object", kpkgclient.ObjectKeyFromObject(obj).String()}
+	if subResource != "" {
+		keysAndValues = append(keysAndValues, "subresource", subResource)
+	}
+	log.FromContext(ctx).Info(message, keysAndValues...)
+}
+
+// eventRecorder prefixes the messages of the events of the wrapped recorder with MessagePrefix.
+type eventRecorder struct {
+	record.EventRecorder
+}
+
+// NewEventRecorder returns a recorder that emits the events of the given recorder with messages that are prefixed with MessagePrefix, so
+// that the events of a dry run aren't mistaken for performed actions.
+func NewEventRecorder(r record.EventRecorder) record.EventRecorder {
+	return eventRecorder{EventRecorder: r}
+}
+
+func (r eventRecorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.EventRecorder.Event(object, eventtype, reason, MessagePrefix+message)
+}
+
+func (r eventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...any) {
+	r.EventRecorder.Eventf(object, eventtype, reason, MessagePrefix+messageFmt, args...)
+}
+
+func (r eventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventtype, reason, messageFmt string,
+	args ...any,
+) {
+	r.EventRecorder.AnnotatedEventf(object, annotations, eventtype, reason, MessagePrefix+messageFmt, args...)
+}
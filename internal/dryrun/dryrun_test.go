@@ -0,0 +1,72 @@
+package dryrun
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name       string
+		serverSide bool
+	}{
+		{
+			name: "should not send the writes",
+		},
+		{
+			name:       "should send the writes as server-side dry run",
+			serverSide: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			ctx := context.TODO()
+			existing := &kcorev1.Secret{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: "kyma-system", Name: "existing"},
+				Data:       map[string][]byte{"key": []byte("value")},
+			}
+			wrapped := fake.NewClientBuilder().WithObjects(existing).Build()
+			c := NewClient(wrapped, tt.serverSide)
+			created := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: "kyma-system", Name: "created"}}
+			updated := existing.DeepCopy()
+			updated.Data["key"] = []byte("changed")
+
+			// when
+			require.NoError(t, c.Create(ctx, created))
+			require.NoError(t, c.Update(ctx, updated))
+			require.NoError(t, c.Delete(ctx, existing.DeepCopy()))
+
+			// then
+			err := wrapped.Get(ctx, kpkgclient.ObjectKeyFromObject(created), &kcorev1.Secret{})
+			require.True(t, kapierrors.IsNotFound(err), "the created secret must not exist")
+			var actual kcorev1.Secret
+			require.NoError(t, c.Get(ctx, kpkgclient.ObjectKeyFromObject(existing), &actual))
+			require.Equal(t, []byte("value"), actual.Data["key"])
+			_, watches := c.(kpkgclient.WithWatch)
+			require.True(t, watches, "the client must support the watches of the wrapped client")
+		})
+	}
+}
+
+func TestNewEventRecorder(t *testing.T) {
+	// given
+	wrapped := record.NewFakeRecorder(2)
+	recorder := NewEventRecorder(wrapped)
+
+	// when
+	recorder.Event(&kcorev1.Secret{}, kcorev1.EventTypeNormal, "ApplicationCreated", "Created application runtime-id")
+	recorder.Eventf(&kcorev1.Secret{}, kcorev1.EventTypeNormal, "ApplicationDeleted", "Deleted application %s", "runtime-id")
+
+	// then
+	require.Equal(t, "Normal ApplicationCreated Dry run: Created application runtime-id", <-wrapped.Events)
+	require.Equal(t, "Normal ApplicationDeleted Dry run: Deleted application runtime-id", <-wrapped.Events)
+}
@@ -0,0 +1,127 @@
+// Package faultinjection injects faults into the requests of the manager to IAS and the SKR clusters, so that chaos tests can verify the
+// retries, backoffs and terminal states of the reconciliations against the real clients instead of mocks.
+package faultinjection
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Config defines the faults that are injected into the requests of a client.
+type Config struct {
+	// ErrorRate is the probability between 0 and 1 that a request fails without being sent. The failures are pseudo-random with a fixed
+	// seed, so that the same sequence of requests fails in the same way on every run.
+	ErrorRate float64
+	// StatusCode is the HTTP status code of the responses of the failed requests, e.g. 503. The failed requests fail with a connection
+	// error instead if it is 0.
+	StatusCode int
+	// Latency is the duration that every affected request is delayed by, whether it fails or not.
+	Latency time.Duration
+	// WritesOnly restricts the faults to the requests that change objects, i.e. with the methods POST, PUT, PATCH and DELETE.
+	WritesOnly bool
+}
+
+// Enabled returns whether any fault is injected.
+func (c Config) Enabled() bool {
+	return c.ErrorRate > 0 || c.Latency > 0
+}
+
+// Validate returns an error if the error rate isn't between 0 and 1 or the status code isn't an error status code.
+func (c Config) Validate() error {
+	if c.ErrorRate < 0 || c.ErrorRate > 1 {
+		return errors.New("error rate must be between 0 and 1")
+	}
+	if c.StatusCode != 0 && (c.StatusCode < 400 || c.StatusCode > 599) {
+		return errors.Errorf("status code %d must be between 400 and 599", c.StatusCode)
+	}
+	if c.Latency < 0 {
+		return errors.New("latency must not be negative")
+	}
+	return nil
+}
+
+// ErrInjected is the error of the requests that fail with an injected connection error.
+var ErrInjected = errors.New("injected connection error")
+
+// transport injects the faults of its config into the requests of the wrapped transport.
+type transport struct {
+	next   http.RoundTripper
+	config Config
+
+	mu     sync.Mutex
+	random *rand.Rand
+}
+
+// NewTransport returns a transport that injects the faults of the config into the requests of the given transport. It returns the given
+// transport if no fault is injected.
+func NewTransport(next http.RoundTripper, config Config) http.RoundTripper {
+	if !config.Enabled() {
+		return next
+	}
+	return &transport{
+		next:   next,
+		config: config,
+		random: rand.New(rand.NewSource(1)), //nolint:gosec // The failures are simulated and need to be reproducible.
+	}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.config.WritesOnly && !isWrite(req.Method) {
+		return t.next.RoundTrip(req)
+	}
+	if t.config.Latency > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(t.config.Latency):
+		}
+	}
+	if !t.fails() {
+		return t.next.RoundTrip(req)
+	}
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	if t.config.StatusCode == 0 {
+		return nil, ErrInjected
+	}
+	body := fmt.Sprintf("injected fault: %s", http.StatusText(t.config.StatusCode))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", t.config.StatusCode, http.StatusText(t.config.StatusCode)),
+		StatusCode:    t.config.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"text/plain"}},
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// fails returns whether the next request fails.
+func (t *transport) fails() bool {
+	if t.config.ErrorRate <= 0 {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.random.Float64() < t.config.ErrorRate
+}
+
+// isWrite returns whether the requests of the method change objects.
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,90 @@
+package faultinjection
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransport(t *testing.T) {
+	tests := []struct {
+		name               string
+		config             Config
+		method             string
+		expectedStatusCode int
+		expectedErr        error
+		expectedRequests   int
+	}{
+		{
+			name:               "should send the request without faults",
+			method:             http.MethodGet,
+			expectedStatusCode: http.StatusOK,
+			expectedRequests:   1,
+		},
+		{
+			name:               "should return the injected status code",
+			config:             Config{ErrorRate: 1, StatusCode: http.StatusServiceUnavailable},
+			method:             http.MethodPost,
+			expectedStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:        "should return the injected connection error",
+			config:      Config{ErrorRate: 1},
+			method:      http.MethodDelete,
+			expectedErr: ErrInjected,
+		},
+		{
+			name:               "should send the reads if only the writes fail",
+			config:             Config{ErrorRate: 1, StatusCode: http.StatusInternalServerError, WritesOnly: true},
+			method:             http.MethodGet,
+			expectedStatusCode: http.StatusOK,
+			expectedRequests:   1,
+		},
+		{
+			name:               "should delay the request",
+			config:             Config{Latency: 10 * time.Millisecond},
+			method:             http.MethodGet,
+			expectedStatusCode: http.StatusOK,
+			expectedRequests:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+			client := &http.Client{Transport: NewTransport(http.DefaultTransport, tt.config)}
+			req, err := http.NewRequest(tt.method, server.URL, http.NoBody)
+			require.NoError(t, err)
+
+			// when
+			start := time.Now()
+			resp, err := client.Do(req)
+
+			// then
+			require.GreaterOrEqual(t, time.Since(start), tt.config.Latency)
+			require.Equal(t, tt.expectedRequests, requests)
+			if tt.expectedErr != nil {
+				require.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	require.NoError(t, Config{ErrorRate: 0.5, StatusCode: http.StatusTooManyRequests, Latency: time.Second}.Validate())
+	require.Error(t, Config{ErrorRate: 1.5}.Validate())
+	require.Error(t, Config{ErrorRate: 1, StatusCode: http.StatusOK}.Validate())
+	require.Error(t, Config{Latency: -time.Second}.Validate())
+}
@@ -9,9 +9,14 @@ import (
 
 	"github.com/deepmap/oapi-codegen/pkg/securityprovider"
 	"github.com/google/uuid"
+	"github.com/kyma-project/eventing-auth-manager/internal/deadline"
+	"github.com/kyma-project/eventing-auth-manager/internal/faultinjection"
 	"github.com/kyma-project/eventing-auth-manager/internal/ias/internal/api"
 	"github.com/kyma-project/eventing-auth-manager/internal/ias/internal/oidc"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/retry"
 	kcontrollerruntime "sigs.k8s.io/controller-runtime"
 )
 
@@ -24,48 +29,113 @@ var (
 	errFetchTokenURL                           = errors.New("failed to fetch token url")
 	errFetchJWKSURI                            = errors.New("failed to fetch jwks uri")
 	errDeleteApplication                       = errors.New("failed to delete application")
+	errUpdateApplication                       = errors.New("failed to update application")
+	errFetchAPISecrets                         = errors.New("failed to fetch api secrets")
+	errDeleteAPISecret                         = errors.New("failed to delete api secret")
+	errClientIDNotAvailable                    = errors.New("client ID of application is not yet available")
+	errFetchApplication                        = errors.New("failed to fetch application")
+	errAdoptApplication                        = errors.New("failed to adopt application")
 )
 
+// readBackBackoff retries the read of a created application, since the Applications API is eventually consistent and might not return the
+// application or its client ID right after the application and its secret were created.
+var readBackBackoff = wait.Backoff{ //nolint:gochecknoglobals // Read-only backoff of the reads, overridden in tests.
+	Steps:    5,
+	Duration: 500 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+}
+
+// ManagedApplicationDescription is the description of the applications that are created by the manager, which marks them as managed by it.
+const ManagedApplicationDescription = "Managed by eventing-auth-manager"
+
 type Client interface {
 	CreateApplication(ctx context.Context, name string) (Application, error)
+	UpdateApplication(ctx context.Context, name string) error
+	RotateSecret(ctx context.Context, name string) (Application, error)
+	AdoptApplication(ctx context.Context, id, name string) (Application, error)
 	DeleteApplication(ctx context.Context, name string) error
+	ListManagedApplications(ctx context.Context) ([]ManagedApplication, error)
+	DeleteApplicationByID(ctx context.Context, id, name string) error
+	GetTokenURL(ctx context.Context) (*string, error)
+	GetJWKSURI(ctx context.Context) (*string, error)
 	GetCredentials() *Credentials
+	Probe(ctx context.Context) error
+}
+
+// DefaultRequestTimeout is the default deadline of every request to an IAS tenant.
+const DefaultRequestTimeout = 30 * time.Second
+
+// ClientOptions contains the options of the IAS client.
+type ClientOptions struct {
+	// RequestsPerSecond limits the rate of the requests to the IAS tenant. The requests aren't limited if it is 0.
+	RequestsPerSecond int
+	// Burst is the number of requests that can be sent at once before they are limited. Defaults to 1 if RequestsPerSecond is set.
+	Burst int
+	// TokenURL overrides the token URL of the OIDC configuration of the tenant. The token URL isn't discovered if it is set.
+	TokenURL string
+	// JWKSURI overrides the JWKS URI of the OIDC configuration of the tenant. The JWKS URI isn't discovered if it is set.
+	JWKSURI string
+	// Faults are injected into the requests to the IAS tenant, e.g. for chaos tests. The faults are injected before the rate limit, the
+	// metrics, and the tracing of the requests, so that they are limited, measured, and traced like the responses of the tenant.
+	Faults faultinjection.Config
+	// DuplicatePolicy defines how multiple applications with the same name are handled. Defaults to DuplicatePolicyFail.
+	DuplicatePolicy DuplicatePolicy
+	// Maintenance records the responses of the tenant to detect its maintenance. The maintenance isn't detected if it is nil.
+	Maintenance *Maintenance
+	// DiscoveryTTL is the duration after which the discovered endpoints of the OIDC configuration are discovered again. They are kept until
+	// the tenant rejects a request with 401 or 403 if it is 0.
+	DiscoveryTTL time.Duration
+	// RequestTimeout is the deadline of every request to the tenant, which is derived from the context of the caller. The waiting time of
+	// the rate limit doesn't count towards the deadline. The requests aren't limited if it is 0.
+	RequestTimeout time.Duration
 }
 
-var NewClient = func(iasTenantUrl, user, password string) (Client, error) { //nolint:gochecknoglobals // For mocking purposes.
+var NewClient = func(iasTenantUrl, user, password string, opts ClientOptions) (Client, error) { //nolint:gochecknoglobals // For mocking purposes.
 	basicAuthProvider, err := securityprovider.NewSecurityProviderBasicAuth(user, password)
 	if err != nil {
 		return nil, err
 	}
 
+	discovery := newDiscoveryCache(opts.DiscoveryTTL, opts.TokenURL, opts.JWKSURI)
+	// The requests of the Applications API and the OIDC requests count towards the same rate limit of the tenant.
+	transport := newRateLimitedTransport(deadline.NewTransport(newInstrumentedTransport(tracing.NewTransport(newDiscoveryTransport(
+		newMaintenanceTransport(faultinjection.NewTransport(http.DefaultTransport, opts.Faults), opts.Maintenance), discovery), spanNameOf)),
+		opts.RequestTimeout, nil), opts)
 	applicationsEndpointURL := fmt.Sprintf("%s/Applications/v1/", iasTenantUrl)
-	apiClient, err := api.NewClientWithResponses(applicationsEndpointURL, api.WithRequestEditorFn(basicAuthProvider.Intercept))
+	apiClient, err := api.NewClientWithResponses(applicationsEndpointURL, api.WithRequestEditorFn(basicAuthProvider.Intercept),
+		api.WithHTTPClient(&http.Client{Transport: transport}))
 	if err != nil {
 		return nil, err
 	}
 
 	const timeout = time.Second * 5
 	oidcHTTPClient := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: transport,
 	}
 
-	return &client{
-		api:         apiClient,
-		oidcClient:  oidc.NewOidcClient(oidcHTTPClient, iasTenantUrl),
-		credentials: &Credentials{URL: iasTenantUrl, Username: user, Password: password},
-	}, nil
+	c := &client{
+		api:             apiClient,
+		oidcClient:      oidc.NewOidcClient(oidcHTTPClient, iasTenantUrl),
+		credentials:     &Credentials{URL: iasTenantUrl, Username: user, Password: password},
+		duplicatePolicy: opts.DuplicatePolicy,
+		discovery:       discovery,
+	}
+	c.staticEndpoints = opts.TokenURL != "" && opts.JWKSURI != ""
+	return c, nil
 }
 
 type client struct {
 	api        api.ClientWithResponsesInterface
 	oidcClient oidc.Client
-	// The token URL of the IAS client. Since this URL should only change when the tenant changes and this will lead to the initialization of
-	// a new client, we can cache the URL to avoid an additional request at each application creation.
-	tokenURL *string
-	// The jwks URI of the IAS client. Since this URI should only change when the tenant changes and this will lead to the initialization of
-	// a new client, we can cache the URI to avoid an additional request at each application creation.
-	jwksURI     *string
+	// discovery caches the token URL and the JWKS URI of the tenant to avoid additional requests at each application creation.
+	discovery   *discoveryCache
 	credentials *Credentials
+	// staticEndpoints is set if both the token URL and the JWKS URI are overridden, so that the OIDC configuration isn't used at all.
+	staticEndpoints bool
+	// duplicatePolicy defines how multiple applications with the same name are handled.
+	duplicatePolicy DuplicatePolicy
 }
 
 func (c *client) GetCredentials() *Credentials {
@@ -92,7 +162,7 @@ func (c *client) CreateApplication(ctx context.Context, name string) (Applicatio
 		}
 		if res.StatusCode() != http.StatusOK {
 			kcontrollerruntime.Log.Error(err, "Failed to delete existing application", "id", *existingApp.Id, "statusCode", res.StatusCode())
-			return Application{}, errDeleteExistingApplicationBeforeCreation
+			return Application{}, newStatusError(errDeleteExistingApplicationBeforeCreation, res.StatusCode())
 		}
 	}
 
@@ -107,6 +177,11 @@ func (c *client) CreateApplication(ctx context.Context, name string) (Applicatio
 		return Application{}, err
 	}
 
+	return c.newApplication(ctx, appID, *clientSecret)
+}
+
+// newApplication returns the application with the given ID and client secret.
+func (c *client) newApplication(ctx context.Context, appID uuid.UUID, clientSecret string) (Application, error) {
 	clientID, err := c.getClientID(ctx, appID)
 	if err != nil {
 		return Application{}, err
@@ -124,43 +199,156 @@ func (c *client) CreateApplication(ctx context.Context, name string) (Applicatio
 		return Application{}, err
 	}
 
-	return NewApplication(appID.String(), *clientID, *clientSecret, *tokenURL, *jwksURI), nil
+	return NewApplication(appID.String(), *clientID, clientSecret, *tokenURL, *jwksURI), nil
 }
 
 func (c *client) GetTokenURL(ctx context.Context) (*string, error) {
-	if c.tokenURL == nil {
-		tokenEndpoint, err := c.oidcClient.GetTokenEndpoint(ctx)
-		if err != nil {
-			return nil, err
-		}
-		if tokenEndpoint == nil {
-			return nil, errFetchTokenURL
-		}
+	return c.discovery.get(ctx, &c.discovery.tokenURL, func(ctx context.Context) (*string, error) {
+		return c.oidcClient.GetTokenEndpoint(ctx)
+	}, errFetchTokenURL)
+}
+
+func (c *client) GetJWKSURI(ctx context.Context) (*string, error) {
+	return c.discovery.get(ctx, &c.discovery.jwksURI, func(ctx context.Context) (*string, error) {
+		return c.oidcClient.GetJWKSURI(ctx)
+	}, errFetchJWKSURI)
+}
+
+// UpdateApplication updates the display name of an existing application in IAS to its name.
+func (c *client) UpdateApplication(ctx context.Context, name string) error {
+	existingApp, err := c.getExistingApplicationByName(ctx, name)
+	if err != nil {
+		return err
+	}
 
-		c.tokenURL = tokenEndpoint
+	res, err := c.api.PatchApplicationWithResponse(ctx, *existingApp.Id, &api.PatchApplicationParams{}, api.PatchApplicationJSONRequestBody{
+		Operations: []api.PatchOperation{{
+			Op:    api.Replace,
+			Path:  "/branding",
+			Value: &api.PatchOperationValue{"displayName": name},
+		}},
+	})
+	if err != nil {
+		return err
 	}
+	if res.StatusCode() != http.StatusOK && res.StatusCode() != http.StatusNoContent {
+		kcontrollerruntime.Log.Error(err, "Failed to update application", "id", *existingApp.Id, "statusCode", res.StatusCode())
+		return newStatusError(errUpdateApplication, res.StatusCode())
+	}
+	return nil
+}
 
-	return c.tokenURL, nil
+// RotateSecret creates a new API secret for an existing application in IAS and deletes the previous API secrets, so that only the returned
+// credentials are valid afterwards.
+func (c *client) RotateSecret(ctx context.Context, name string) (Application, error) {
+	existingApp, err := c.getExistingApplicationByName(ctx, name)
+	if err != nil {
+		return Application{}, err
+	}
+	return c.rotateSecret(ctx, *existingApp.Id)
 }
 
-func (c *client) GetJWKSURI(ctx context.Context) (*string, error) {
-	if c.jwksURI == nil {
-		jwksURI, err := c.oidcClient.GetJWKSURI(ctx)
-		if err != nil {
-			return nil, err
-		}
-		if jwksURI == nil {
-			return nil, errFetchJWKSURI
+// rotateSecret creates a new API secret for the application with the given ID and deletes its previous API secrets.
+func (c *client) rotateSecret(ctx context.Context, appID uuid.UUID) (Application, error) {
+	secretsRes, err := c.api.GetApiSecretsWithResponse(ctx, appID)
+	if err != nil {
+		return Application{}, err
+	}
+	if secretsRes.StatusCode() != http.StatusOK {
+		kcontrollerruntime.Log.Error(err, "Failed to fetch api secrets", "id", appID, "statusCode", secretsRes.StatusCode())
+		return Application{}, newStatusError(errFetchAPISecrets, secretsRes.StatusCode())
+	}
+
+	clientSecret, err := c.createSecret(ctx, appID)
+	if err != nil {
+		return Application{}, err
+	}
+
+	// The previous secrets are only deleted after the new secret is created, so that the application always has a valid secret.
+	if secretsRes.JSON200 != nil && secretsRes.JSON200.Secrets != nil {
+		for _, secret := range *secretsRes.JSON200.Secrets {
+			if secret.Hint == nil {
+				continue
+			}
+			if err := c.deleteSecret(ctx, appID, *secret.Hint); err != nil {
+				return Application{}, err
+			}
 		}
+	}
 
-		c.jwksURI = jwksURI
+	return c.newApplication(ctx, appID, *clientSecret)
+}
+
+// AdoptApplication takes over the existing application with the given ID that wasn't created by the manager, e.g. an application that was
+// created manually or by an older tool. The application must use OpenID Connect and must not be managed under another name already. It is
+// renamed to the given name and marked with ManagedApplicationDescription, so that it is managed like the applications that the manager
+// created, and gets new credentials, which revoke its previous API secrets. Another application with the given name isn't deleted, so
+// that its credentials stay valid until the credentials of the adopted application were delivered. It must be deleted by its ID with
+// DeleteApplicationByID afterwards.
+func (c *client) AdoptApplication(ctx context.Context, id, name string) (Application, error) {
+	appID, err := uuid.Parse(id)
+	if err != nil {
+		return Application{}, errors.Wrapf(err, "invalid application ID %s", id)
+	}
+	res, err := c.api.GetApplicationWithResponse(ctx, appID, &api.GetApplicationParams{})
+	if err != nil {
+		return Application{}, err
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return Application{}, errors.Errorf("application %s does not exist", id)
 	}
+	if res.StatusCode() != http.StatusOK || res.JSON200 == nil {
+		return Application{}, newStatusError(errFetchApplication, res.StatusCode())
+	}
+	if err := verifyAdoptable(res.JSON200, id, name); err != nil {
+		return Application{}, err
+	}
+	// The value of a patch operation is an object, so the attributes are replaced at the root of the application.
+	patchRes, err := c.api.PatchApplicationWithResponse(ctx, appID, &api.PatchApplicationParams{}, api.PatchApplicationJSONRequestBody{
+		Operations: []api.PatchOperation{{
+			Op: api.Replace,
+			Value: &api.PatchOperationValue{
+				"name":        name,
+				"description": ManagedApplicationDescription,
+				"branding":    map[string]interface{}{"displayName": name},
+			},
+		}},
+	})
+	if err != nil {
+		return Application{}, err
+	}
+	if patchRes.StatusCode() != http.StatusOK && patchRes.StatusCode() != http.StatusNoContent {
+		kcontrollerruntime.Log.Error(err, "Failed to adopt application", "id", id, "statusCode", patchRes.StatusCode())
+		return Application{}, newStatusError(errAdoptApplication, patchRes.StatusCode())
+	}
+	kcontrollerruntime.Log.Info("Adopted application", "name", name, "id", id)
+
+	return c.rotateSecret(ctx, appID)
+}
 
-	return c.jwksURI, nil
+// verifyAdoptable returns an error if the application with the given ID can't be adopted under the given name.
+func verifyAdoptable(app *api.ApplicationResponse, id, name string) error {
+	if app.Description != nil && *app.Description == ManagedApplicationDescription && app.Name != nil && *app.Name != name {
+		return errors.Errorf("application %s is already managed as application %s", id, *app.Name)
+	}
+	if app.UrnSapIdentityApplicationSchemasExtensionSci10Authentication != nil &&
+		app.UrnSapIdentityApplicationSchemasExtensionSci10Authentication.SsoType != nil &&
+		*app.UrnSapIdentityApplicationSchemasExtensionSci10Authentication.SsoType != api.OpenIdConnect {
+		return errors.Errorf("application %s uses SSO type %s instead of %s", id,
+			*app.UrnSapIdentityApplicationSchemasExtensionSci10Authentication.SsoType, api.OpenIdConnect)
+	}
+	return nil
 }
 
-// DeleteApplication deletes an application in IAS. If the application does not exist, this function does nothing.
+// DeleteApplication deletes an application in IAS. If the application does not exist, this function does nothing. If the ID of the
+// application is stored in the context with WithApplicationID, the application is only deleted by its ID if it still has the name and is
+// marked as managed, and an ApplicationChangedError is returned otherwise. The application isn't looked up by its name then, so that an
+// application that was recreated externally under the same name isn't deleted.
 func (c *client) DeleteApplication(ctx context.Context, name string) error {
+	if id := applicationIDOf(ctx); id != "" {
+		return c.DeleteApplicationByID(ctx, id, name)
+	}
+
 	existingApp, err := c.getApplicationByName(ctx, name)
 	if err != nil {
 		return err
@@ -172,8 +360,87 @@ func (c *client) DeleteApplication(ctx context.Context, name string) error {
 	return c.deleteApplication(ctx, *existingApp.Id)
 }
 
+// ListManagedApplications returns the applications of the tenant that are marked with ManagedApplicationDescription. Applications that were
+// created before the marker was introduced aren't returned.
+func (c *client) ListManagedApplications(ctx context.Context) ([]ManagedApplication, error) {
+	var managed []ManagedApplication
+	params := &api.GetAllApplicationsParams{}
+	for {
+		res, err := c.api.GetAllApplicationsWithResponse(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		// This is not documented in the API, but the actual API returned 404 if no applications were found.
+		if res.StatusCode() == http.StatusNotFound {
+			return managed, nil
+		}
+		if res.StatusCode() != http.StatusOK {
+			return nil, newStatusError(errFetchExistingApplications, res.StatusCode())
+		}
+		if res.JSON200 == nil || res.JSON200.Applications == nil {
+			return managed, nil
+		}
+		for _, app := range *res.JSON200.Applications {
+			if app.Id == nil || app.Name == nil || app.Description == nil || *app.Description != ManagedApplicationDescription {
+				continue
+			}
+			application := ManagedApplication{ID: app.Id.String(), Name: *app.Name}
+			if app.Meta != nil {
+				application.Created = app.Meta.Created
+			}
+			managed = append(managed, application)
+		}
+
+		if res.JSON200.NextCursor == nil || *res.JSON200.NextCursor == "" {
+			return managed, nil
+		}
+		cursor, err := uuid.Parse(*res.JSON200.NextCursor)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse the cursor of the next page of applications")
+		}
+		if params.Cursor != nil && *params.Cursor == cursor {
+			return nil, errors.Errorf("the next page of applications has the same cursor %s", cursor)
+		}
+		params = &api.GetAllApplicationsParams{Cursor: &cursor}
+	}
+}
+
+// DeleteApplicationByID deletes the application with the given ID in IAS, if it still has the given name and is marked with
+// ManagedApplicationDescription. Otherwise, it returns an ApplicationChangedError. If the application does not exist, this function does
+// nothing.
+func (c *client) DeleteApplicationByID(ctx context.Context, id, name string) error {
+	appID, err := uuid.Parse(id)
+	if err != nil {
+		return errors.Wrapf(err, "invalid application ID %s", id)
+	}
+	return c.deleteManagedApplication(ctx, appID, name)
+}
+
+// deleteManagedApplication deletes the application with the given ID, if it still has the given name and is marked with
+// ManagedApplicationDescription, so that an application that was changed externally since its ID was read isn't deleted.
+func (c *client) deleteManagedApplication(ctx context.Context, id uuid.UUID, name string) error {
+	res, err := c.api.GetApplicationWithResponse(ctx, id, &api.GetApplicationParams{})
+	if err != nil {
+		return err
+	}
+	if res.StatusCode() == http.StatusNotFound {
+		return nil
+	}
+	if res.StatusCode() != http.StatusOK {
+		return newStatusError(errFetchApplication, res.StatusCode())
+	}
+	app := res.JSON200
+	if app == nil || app.Name == nil || *app.Name != name || app.Description == nil || *app.Description != ManagedApplicationDescription {
+		return &ApplicationChangedError{ID: id.String(), Name: name}
+	}
+	return c.deleteApplication(ctx, id)
+}
+
 func (c *client) getApplicationByName(ctx context.Context, name string) (*api.ApplicationResponse, error) {
-	appsFilter := fmt.Sprintf("name eq %s", name)
+	appsFilter, err := nameFilter(name)
+	if err != nil {
+		return nil, err
+	}
 	res, err := c.api.GetAllApplicationsWithResponse(ctx, &api.GetAllApplicationsParams{Filter: &appsFilter})
 	if err != nil {
 		return nil, err
@@ -186,7 +453,7 @@ func (c *client) getApplicationByName(ctx context.Context, name string) (*api.Ap
 
 	if res.StatusCode() != http.StatusOK {
 		kcontrollerruntime.Log.Error(err, "Failed to fetch existing applications filtered by name", "name", name, "statusCode", res.StatusCode())
-		return nil, errFetchExistingApplications
+		return nil, newStatusError(errFetchExistingApplications, res.StatusCode())
 	}
 
 	if res.JSON200.Applications != nil {
@@ -198,14 +465,26 @@ func (c *client) getApplicationByName(ctx context.Context, name string) (*api.Ap
 		case 1:
 			return &(*res.JSON200.Applications)[0], nil
 		default:
-			return nil, errors.Errorf("found multiple applications with the same name %s", name)
+			return c.resolveDuplicates(ctx, name, *res.JSON200.Applications)
 		}
 	}
 	return nil, nil //nolint:nilnil
 }
 
+// getExistingApplicationByName returns the application with the given name, and fails if the application doesn't exist.
+func (c *client) getExistingApplicationByName(ctx context.Context, name string) (*api.ApplicationResponse, error) {
+	existingApp, err := c.getApplicationByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if existingApp == nil {
+		return nil, errors.Errorf("application %s does not exist", name)
+	}
+	return existingApp, nil
+}
+
 func (c *client) createNewApplication(ctx context.Context, name string) (uuid.UUID, error) {
-	newApplication := newIasApplication(name)
+	newApplication := newIasApplication(name, authConfigOf(ctx))
 	res, err := c.api.CreateApplicationWithResponse(ctx, &api.CreateApplicationParams{}, newApplication)
 	if err != nil {
 		return uuid.UUID{}, err
@@ -213,7 +492,7 @@ func (c *client) createNewApplication(ctx context.Context, name string) (uuid.UU
 
 	if res.StatusCode() != http.StatusCreated {
 		kcontrollerruntime.Log.Error(err, "Failed to create application", "name", name, "statusCode", res.StatusCode())
-		return uuid.UUID{}, errCreateApplication
+		return uuid.UUID{}, newStatusError(errCreateApplication, res.StatusCode())
 	}
 
 	return extractApplicationID(res)
@@ -227,13 +506,43 @@ func (c *client) createSecret(ctx context.Context, appID uuid.UUID) (*string, er
 
 	if res.StatusCode() != http.StatusCreated {
 		kcontrollerruntime.Log.Error(err, "Failed to create api secret", "id", appID, "statusCode", res.StatusCode())
-		return nil, errCreateAPISecret
+		return nil, newStatusError(errCreateAPISecret, res.StatusCode())
 	}
 
 	return res.JSON201.Secret, nil
 }
 
+func (c *client) deleteSecret(ctx context.Context, appID uuid.UUID, hint string) error {
+	res, err := c.api.DeleteApiSecretWithResponse(ctx, appID, &api.DeleteApiSecretParams{Hint: hint})
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode() != http.StatusOK && res.StatusCode() != http.StatusNoContent && res.StatusCode() != http.StatusNotFound {
+		kcontrollerruntime.Log.Error(err, "Failed to delete api secret", "id", appID, "statusCode", res.StatusCode())
+		return newStatusError(errDeleteAPISecret, res.StatusCode())
+	}
+
+	return nil
+}
+
+// getClientID returns the client ID of the application. A response without the application or its client ID is retried with readBackBackoff,
+// so that a created application isn't recreated only because it isn't readable yet.
 func (c *client) getClientID(ctx context.Context, appID uuid.UUID) (*string, error) {
+	var clientID *string
+	err := retry.OnError(readBackBackoff, func(err error) bool { return ctx.Err() == nil && isStaleRead(err) }, func() error {
+		var err error
+		clientID, err = c.readClientID(ctx, appID)
+		if err != nil && isStaleRead(err) {
+			kcontrollerruntime.Log.V(1).Info("Application is not yet readable, retrying", "id", appID, "reason", err.Error())
+		}
+		return err
+	})
+	return clientID, err
+}
+
+// readClientID reads the client ID of the application once.
+func (c *client) readClientID(ctx context.Context, appID uuid.UUID) (*string, error) {
 	// The client ID is generated only after an API secret is created, so we need to retrieve the application again to get the client ID.
 	applicationResponse, err := c.api.GetApplicationWithResponse(ctx, appID, &api.GetApplicationParams{})
 	if err != nil {
@@ -242,9 +551,21 @@ func (c *client) getClientID(ctx context.Context, appID uuid.UUID) (*string, err
 
 	if applicationResponse.StatusCode() != http.StatusOK {
 		kcontrollerruntime.Log.Error(err, "Failed to retrieve client ID", "id", appID, "statusCode", applicationResponse.StatusCode())
-		return nil, errRetrieveClientID
+		return nil, newStatusError(errRetrieveClientID, applicationResponse.StatusCode())
+	}
+	app := applicationResponse.JSON200
+	if app == nil || app.UrnSapIdentityApplicationSchemasExtensionSci10Authentication == nil ||
+		app.UrnSapIdentityApplicationSchemasExtensionSci10Authentication.ClientId == nil {
+		return nil, errClientIDNotAvailable
 	}
-	return applicationResponse.JSON200.UrnSapIdentityApplicationSchemasExtensionSci10Authentication.ClientId, nil
+	return app.UrnSapIdentityApplicationSchemasExtensionSci10Authentication.ClientId, nil
+}
+
+// isStaleRead returns true if the error is caused by a read that doesn't reflect a preceding write yet, i.e. the application isn't found
+// or has no client ID.
+func isStaleRead(err error) bool {
+	var statusErr *StatusError
+	return errors.Is(err, errClientIDNotAvailable) || (errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound)
 }
 
 func (c *client) deleteApplication(ctx context.Context, id uuid.UUID) error {
@@ -260,7 +581,7 @@ func (c *client) deleteApplication(ctx context.Context, id uuid.UUID) error {
 
 	if res.StatusCode() != http.StatusOK {
 		kcontrollerruntime.Log.Error(err, "Failed to delete application", "id", id, "statusCode", res.StatusCode())
-		return errDeleteApplication
+		return newStatusError(errDeleteApplication, res.StatusCode())
 	}
 
 	return nil
@@ -279,10 +600,47 @@ func extractApplicationID(createAppResponse *api.CreateApplicationResponse) (uui
 	return parsedAppID, nil
 }
 
-func newIasApplication(name string) api.Application {
+type authConfigKey struct{}
+
+// AuthConfig configures the authentication of the applications that are created.
+type AuthConfig struct {
+	// GrantTypes restricts the OAuth grant types of the application, or allows all grant types if it is empty.
+	GrantTypes []string
+	// TokenValiditySeconds is the validity of the access tokens of the application, or zero for the default of the tenant.
+	TokenValiditySeconds int
+}
+
+// WithAuthConfig returns a context that stores the authentication of the application that is created with the context.
+func WithAuthConfig(ctx context.Context, config AuthConfig) context.Context {
+	return context.WithValue(ctx, authConfigKey{}, config)
+}
+
+// authConfigOf returns the authentication that is stored in the context, or an empty authentication.
+func authConfigOf(ctx context.Context) AuthConfig {
+	config, _ := ctx.Value(authConfigKey{}).(AuthConfig)
+	return config
+}
+
+func newIasApplication(name string, auth AuthConfig) api.Application {
 	ssoType := api.OpenIdConnect
+	description := ManagedApplicationDescription
+	var oidc *api.OIDCConfiguration
+	if len(auth.GrantTypes) > 0 || auth.TokenValiditySeconds > 0 {
+		oidc = &api.OIDCConfiguration{}
+		if len(auth.GrantTypes) > 0 {
+			grantTypes := make([]api.GrantType, 0, len(auth.GrantTypes))
+			for _, grantType := range auth.GrantTypes {
+				grantTypes = append(grantTypes, api.GrantType(grantType))
+			}
+			oidc.RestrictedGrantTypes = &grantTypes
+		}
+		if auth.TokenValiditySeconds > 0 {
+			oidc.TokenPolicy = &api.TokenPolicy{JwtValidity: &auth.TokenValiditySeconds}
+		}
+	}
 	return api.Application{
-		Name: &name,
+		Name:        &name,
+		Description: &description,
 		Branding: &api.Branding{
 			DisplayName: &name,
 		},
@@ -290,7 +648,8 @@ func newIasApplication(name string) api.Application {
 			api.SchemasEnumUrnSapIdentityApplicationSchemasExtensionSci10Authentication,
 		},
 		UrnSapIdentityApplicationSchemasExtensionSci10Authentication: &api.AuthenticationSchema{
-			SsoType: &ssoType,
+			SsoType:                    &ssoType,
+			OpenIdConnectConfiguration: oidc,
 		},
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kyma-project/eventing-auth-manager/internal/ias/internal/api"
@@ -13,17 +14,21 @@ import (
 	eamoidcmocks "github.com/kyma-project/eventing-auth-manager/internal/ias/internal/oidc/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/utils/ptr"
 )
 
 func Test_CreateApplication(t *testing.T) {
 	appID := uuid.MustParse("90764f89-f041-4ccf-8da9-7a7c2d60d7fc")
+	defaultReadBackBackoff := readBackBackoff
+	readBackBackoff = wait.Backoff{Steps: 3, Duration: time.Millisecond}
+	t.Cleanup(func() { readBackBackoff = defaultReadBackBackoff })
 	tests := []struct {
 		name               string
 		givenAPIMock       func() *mocks.ClientWithResponsesInterface
 		oidcClientMock     *eamoidcmocks.Client
-		clientTokenURLMock *string
-		clientJWKSURIMock  *string
+		clientTokenURLMock string
+		clientJWKSURIMock  string
 		assertCalls        func(*testing.T, *mocks.ClientWithResponsesInterface)
 		wantApp            Application
 		wantError          error
@@ -108,6 +113,55 @@ func Test_CreateApplication(t *testing.T) {
 				"https://test.com/certs",
 			),
 		},
+		{
+			name: "should retry reading the client ID until the created application is readable",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+
+				mockGetAllApplicationsWithResponseStatusNotFound(&clientMock)
+				mockCreateApplicationWithResponseStatusCreated(&clientMock, appID.String())
+				mockCreateAPISecretWithResponseStatusCreated(&clientMock, appID)
+				mockGetApplicationWithResponseStatusNotFound(&clientMock, 1)
+				mockGetApplicationWithResponseWithoutClientID(&clientMock, 1)
+				mockGetApplicationWithResponseStatusOK(&clientMock, appID)
+
+				return &clientMock
+			},
+			oidcClientMock: mockClient(
+				t,
+				ptr.To("https://test.com/token"),
+				ptr.To("https://test.com/certs"),
+			),
+			assertCalls: func(t *testing.T, clientMock *mocks.ClientWithResponsesInterface) {
+				clientMock.AssertNumberOfCalls(t, "GetApplicationWithResponse", 3)
+				clientMock.AssertNumberOfCalls(t, "CreateApplicationWithResponse", 1)
+			},
+			wantApp: NewApplication(
+				appID.String(),
+				"clientIdMock",
+				"clientSecretMock",
+				"https://test.com/token",
+				"https://test.com/certs",
+			),
+		},
+		{
+			name: "should return error when the created application isn't readable within the backoff",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+
+				mockGetAllApplicationsWithResponseStatusNotFound(&clientMock)
+				mockCreateApplicationWithResponseStatusCreated(&clientMock, appID.String())
+				mockCreateAPISecretWithResponseStatusCreated(&clientMock, appID)
+				mockGetApplicationWithResponseStatusNotFound(&clientMock, 3)
+
+				return &clientMock
+			},
+			assertCalls: func(t *testing.T, clientMock *mocks.ClientWithResponsesInterface) {
+				clientMock.AssertNumberOfCalls(t, "GetApplicationWithResponse", 3)
+			},
+			wantApp:   Application{},
+			wantError: errRetrieveClientID,
+		},
 		{
 			name: "should return an error when multiple applications exist for the given name",
 			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
@@ -247,8 +301,8 @@ func Test_CreateApplication(t *testing.T) {
 
 				return &clientMock
 			},
-			clientTokenURLMock: ptr.To("https://from-cache.com/token"),
-			clientJWKSURIMock:  ptr.To("https://from-cache.com/certs"),
+			clientTokenURLMock: "https://from-cache.com/token",
+			clientJWKSURIMock:  "https://from-cache.com/certs",
 			wantApp: NewApplication(
 				appID.String(),
 				"clientIdMock",
@@ -267,8 +321,7 @@ func Test_CreateApplication(t *testing.T) {
 			client := client{
 				api:        apiMock,
 				oidcClient: oidcMock,
-				tokenURL:   tt.clientTokenURLMock,
-				jwksURI:    tt.clientJWKSURIMock,
+				discovery:  newDiscoveryCache(0, tt.clientTokenURLMock, tt.clientJWKSURIMock),
 			}
 
 			// when
@@ -297,6 +350,39 @@ func Test_CreateApplication(t *testing.T) {
 	}
 }
 
+func Test_CreateApplication_AuthConfig(t *testing.T) {
+	// given
+	appID := uuid.MustParse("90764f89-f041-4ccf-8da9-7a7c2d60d7fc")
+	apiMock := &mocks.ClientWithResponsesInterface{}
+	mockGetAllApplicationsWithResponseStatusOkEmptyResponse(apiMock)
+	wantApplication := newIasApplication("Test-App-Name", AuthConfig{})
+	wantApplication.UrnSapIdentityApplicationSchemasExtensionSci10Authentication.OpenIdConnectConfiguration = &api.OIDCConfiguration{
+		RestrictedGrantTypes: &[]api.GrantType{api.CLIENTCREDENTIALS},
+		TokenPolicy:          &api.TokenPolicy{JwtValidity: ptr.To(600)},
+	}
+	apiMock.On("CreateApplicationWithResponse", mock.Anything, mock.Anything, wantApplication).
+		Return(&api.CreateApplicationResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: http.StatusCreated,
+				Header:     map[string][]string{"Location": {fmt.Sprintf("https://test.com/v1/Applications/%s", appID)}},
+			},
+		}, nil)
+	mockCreateAPISecretWithResponseStatusCreated(apiMock, appID)
+	mockGetApplicationWithResponseStatusOK(apiMock, appID)
+	client := client{
+		api:       apiMock,
+		discovery: newDiscoveryCache(0, "https://test.com/token", "https://test.com/certs"),
+	}
+	ctx := WithAuthConfig(context.TODO(), AuthConfig{GrantTypes: []string{"clientCredentials"}, TokenValiditySeconds: 600})
+
+	// when
+	_, err := client.CreateApplication(ctx, "Test-App-Name")
+
+	// then
+	require.NoError(t, err)
+	apiMock.AssertExpectations(t)
+}
+
 func Test_DeleteApplication(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -377,6 +463,449 @@ func Test_DeleteApplication(t *testing.T) {
 	}
 }
 
+func Test_ListManagedApplications(t *testing.T) {
+	managedID := uuid.MustParse("90764f89-f041-4ccf-8da9-7a7c2d60d7fc")
+	otherID := uuid.MustParse("4e2b7ea4-4b47-4a88-9b36-c5e8d2f0a1b3")
+	nextPageID := uuid.MustParse("a1b2c3d4-0000-4000-8000-000000000001")
+	cursor := uuid.MustParse("0f0e0d0c-0b0a-4900-8800-000000000002")
+	managed, other := ManagedApplicationDescription, "Created by someone else"
+	tests := []struct {
+		name         string
+		givenAPIMock func() *mocks.ClientWithResponsesInterface
+		wantApps     []ManagedApplication
+		wantError    error
+	}{
+		{
+			name: "should return the managed applications of all pages",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+				clientMock.On("GetAllApplicationsWithResponse", mock.Anything, &api.GetAllApplicationsParams{}).
+					Return(&api.GetAllApplicationsResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+						JSON200: &api.ApplicationsResponse{
+							Applications: &[]api.ApplicationResponse{
+								{Id: &managedID, Name: ptr.To("runtime-a"), Description: &managed},
+								{Id: &otherID, Name: ptr.To("other-app"), Description: &other},
+								{Id: &otherID, Name: ptr.To("unmarked-app")},
+							},
+							NextCursor: ptr.To(cursor.String()),
+						},
+					}, nil)
+				clientMock.On("GetAllApplicationsWithResponse", mock.Anything, &api.GetAllApplicationsParams{Cursor: &cursor}).
+					Return(&api.GetAllApplicationsResponse{
+						HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+						JSON200: &api.ApplicationsResponse{
+							Applications: &[]api.ApplicationResponse{
+								{Id: &nextPageID, Name: ptr.To("runtime-b"), Description: &managed},
+							},
+						},
+					}, nil)
+				return &clientMock
+			},
+			wantApps: []ManagedApplication{
+				{ID: managedID.String(), Name: "runtime-a"},
+				{ID: nextPageID.String(), Name: "runtime-b"},
+			},
+		},
+		{
+			name: "should return no applications when fetching the applications returns status 404",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+				mockGetAllApplicationsWithResponseStatusNotFound(&clientMock)
+				return &clientMock
+			},
+		},
+		{
+			name: "should return error when fetching the applications fails",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+				mockGetAllApplicationsWithResponseStatusInternalServerError(&clientMock)
+				return &clientMock
+			},
+			wantError: errFetchExistingApplications,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			apiMock := tt.givenAPIMock()
+			client := client{
+				api: apiMock,
+			}
+
+			// when
+			apps, err := client.ListManagedApplications(context.TODO())
+
+			// then
+			if tt.wantError != nil {
+				require.EqualError(t, err, tt.wantError.Error())
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantApps, apps)
+			}
+			apiMock.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_DeleteApplicationByID(t *testing.T) {
+	appID := uuid.MustParse("90764f89-f041-4ccf-8da9-7a7c2d60d7fc")
+	managed := ManagedApplicationDescription
+	tests := []struct {
+		name         string
+		givenAPIMock func() *mocks.ClientWithResponsesInterface
+		wantError    string
+	}{
+		{
+			name: "should delete the managed application with the name",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := &mocks.ClientWithResponsesInterface{}
+				mockGetApplicationWithResponseApplication(clientMock, appID, &api.ApplicationResponse{Name: ptr.To("Test-App-Name"),
+					Description: &managed})
+				mockDeleteApplicationWithResponseStatusOk(clientMock, appID)
+				return clientMock
+			},
+		},
+		{
+			name: "should do nothing if the application doesn't exist",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := &mocks.ClientWithResponsesInterface{}
+				mockGetApplicationWithResponseStatusNotFound(clientMock, 1)
+				return clientMock
+			},
+		},
+		{
+			name: "should not delete the application if it was renamed",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := &mocks.ClientWithResponsesInterface{}
+				mockGetApplicationWithResponseApplication(clientMock, appID, &api.ApplicationResponse{Name: ptr.To("Other-App-Name"),
+					Description: &managed})
+				return clientMock
+			},
+			wantError: "application 90764f89-f041-4ccf-8da9-7a7c2d60d7fc is not the managed application Test-App-Name anymore",
+		},
+		{
+			name: "should not delete the application if it isn't marked as managed",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := &mocks.ClientWithResponsesInterface{}
+				mockGetApplicationWithResponseApplication(clientMock, appID, &api.ApplicationResponse{Name: ptr.To("Test-App-Name")})
+				return clientMock
+			},
+			wantError: "application 90764f89-f041-4ccf-8da9-7a7c2d60d7fc is not the managed application Test-App-Name anymore",
+		},
+		{
+			name: "should fail if the application can't be read",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := &mocks.ClientWithResponsesInterface{}
+				mockGetApplicationWithResponseStatusInternalServerError(clientMock)
+				return clientMock
+			},
+			wantError: errFetchApplication.Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			apiMock := tt.givenAPIMock()
+			client := client{
+				api: apiMock,
+			}
+
+			// when
+			err := client.DeleteApplicationByID(context.TODO(), appID.String(), "Test-App-Name")
+
+			// then
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+			}
+			apiMock.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_DeleteApplicationByID_InvalidID(t *testing.T) {
+	// when
+	err := (&client{}).DeleteApplicationByID(context.TODO(), "invalid-id", "Test-App-Name")
+
+	// then
+	require.ErrorContains(t, err, "invalid application ID invalid-id")
+}
+
+func Test_DeleteApplication_StoredID(t *testing.T) {
+	appID := uuid.MustParse("90764f89-f041-4ccf-8da9-7a7c2d60d7fc")
+	tests := []struct {
+		name         string
+		givenAPIMock func() *mocks.ClientWithResponsesInterface
+		wantError    string
+	}{
+		{
+			name: "should delete the application by its stored ID",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := &mocks.ClientWithResponsesInterface{}
+				mockGetApplicationWithResponseApplication(clientMock, appID, &api.ApplicationResponse{Name: ptr.To("Test-App-Name"),
+					Description: ptr.To(ManagedApplicationDescription)})
+				mockDeleteApplicationWithResponseStatusOk(clientMock, appID)
+				return clientMock
+			},
+		},
+		{
+			name: "should do nothing and not look up the application by name if the application with the stored ID doesn't exist",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := &mocks.ClientWithResponsesInterface{}
+				mockGetApplicationWithResponseStatusNotFound(clientMock, 1)
+				return clientMock
+			},
+		},
+		{
+			name: "should not delete any application if the application with the stored ID isn't marked as managed",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := &mocks.ClientWithResponsesInterface{}
+				mockGetApplicationWithResponseApplication(clientMock, appID, &api.ApplicationResponse{Name: ptr.To("Test-App-Name")})
+				return clientMock
+			},
+			wantError: "application 90764f89-f041-4ccf-8da9-7a7c2d60d7fc is not the managed application Test-App-Name anymore",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			apiMock := tt.givenAPIMock()
+			client := client{
+				api: apiMock,
+			}
+
+			// when
+			err := client.DeleteApplication(WithApplicationID(context.TODO(), appID.String()), "Test-App-Name")
+
+			// then
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+				require.True(t, IsApplicationChangedError(err))
+			} else {
+				require.NoError(t, err)
+			}
+			apiMock.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_RotateSecret(t *testing.T) {
+	appID := uuid.MustParse("90764f89-f041-4ccf-8da9-7a7c2d60d7fc")
+	tests := []struct {
+		name         string
+		givenAPIMock func() *mocks.ClientWithResponsesInterface
+		wantApp      Application
+		wantError    error
+	}{
+		{
+			name: "should create a new secret and delete the previous secrets",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+
+				mockGetAllApplicationsWithResponseStatusOk(&clientMock, appID)
+				mockGetAPISecretsWithResponseStatusOk(&clientMock, appID, "old-hint")
+				mockCreateAPISecretWithResponseStatusCreated(&clientMock, appID)
+				mockDeleteAPISecretWithResponseStatusOk(&clientMock, appID, "old-hint")
+				mockGetApplicationWithResponseStatusOK(&clientMock, appID)
+
+				return &clientMock
+			},
+			wantApp: NewApplication(
+				appID.String(),
+				"clientIdMock",
+				"clientSecretMock",
+				"https://test.com/token",
+				"https://test.com/certs",
+			),
+		},
+		{
+			name: "should return an error when the application doesn't exist",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+
+				mockGetAllApplicationsWithResponseStatusOkEmptyResponse(&clientMock)
+
+				return &clientMock
+			},
+			wantError: errors.New("application Test-App-Name does not exist"), //nolint:goerr113 // used one time only in tests.
+		},
+		{
+			name: "should not delete the previous secrets when the new secret isn't created",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+
+				mockGetAllApplicationsWithResponseStatusOk(&clientMock, appID)
+				mockGetAPISecretsWithResponseStatusOk(&clientMock, appID, "old-hint")
+				mockCreateAPISecretWithResponseStatusInternalServerError(&clientMock)
+
+				return &clientMock
+			},
+			wantError: errCreateAPISecret,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			apiMock := tt.givenAPIMock()
+
+			client := client{
+				api:       apiMock,
+				discovery: newDiscoveryCache(0, "https://test.com/token", "https://test.com/certs"),
+			}
+
+			// when
+			app, err := client.RotateSecret(context.TODO(), "Test-App-Name")
+
+			// then
+			require.Equal(t, tt.wantApp, app)
+			if tt.wantError != nil {
+				require.EqualError(t, err, tt.wantError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			apiMock.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_AdoptApplication(t *testing.T) {
+	appID := uuid.MustParse("90764f89-f041-4ccf-8da9-7a7c2d60d7fc")
+	tests := []struct {
+		name         string
+		givenAPIMock func() *mocks.ClientWithResponsesInterface
+		wantApp      Application
+		wantError    error
+	}{
+		{
+			name: "should rename and mark the application as managed and create new credentials",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+
+				mockGetApplicationWithResponseStatusOK(&clientMock, appID)
+				mockPatchApplicationWithResponseStatusOk(&clientMock, appID)
+				mockGetAPISecretsWithResponseStatusOk(&clientMock, appID, "old-hint")
+				mockCreateAPISecretWithResponseStatusCreated(&clientMock, appID)
+				mockDeleteAPISecretWithResponseStatusOk(&clientMock, appID, "old-hint")
+
+				return &clientMock
+			},
+			wantApp: NewApplication(
+				appID.String(),
+				"clientIdMock",
+				"clientSecretMock",
+				"https://test.com/token",
+				"https://test.com/certs",
+			),
+		},
+		{
+			name: "should return an error when the application doesn't exist",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+
+				mockGetApplicationWithResponseStatusNotFound(&clientMock, 1)
+
+				return &clientMock
+			},
+			wantError: errors.New("application 90764f89-f041-4ccf-8da9-7a7c2d60d7fc does not exist"), //nolint:goerr113 // used one time only in tests.
+		},
+		{
+			name: "should return an error when the application is managed under another name",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+
+				mockGetApplicationWithResponseApplication(&clientMock, appID, &api.ApplicationResponse{
+					Id:          &appID,
+					Name:        ptr.To("Other-App-Name"),
+					Description: ptr.To(ManagedApplicationDescription),
+				})
+
+				return &clientMock
+			},
+			wantError: errors.New("application 90764f89-f041-4ccf-8da9-7a7c2d60d7fc is already managed as application Other-App-Name"), //nolint:goerr113,lll // used one time only in tests.
+		},
+		{
+			name: "should return an error when the application doesn't use OpenID Connect",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+
+				mockGetApplicationWithResponseApplication(&clientMock, appID, &api.ApplicationResponse{
+					Id: &appID,
+					UrnSapIdentityApplicationSchemasExtensionSci10Authentication: &api.AuthenticationSchema{
+						SsoType: ptr.To(api.Saml2),
+					},
+				})
+
+				return &clientMock
+			},
+			wantError: errors.New("application 90764f89-f041-4ccf-8da9-7a7c2d60d7fc uses SSO type saml2 instead of openIdConnect"), //nolint:goerr113,lll // used one time only in tests.
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			apiMock := tt.givenAPIMock()
+
+			client := client{
+				api:       apiMock,
+				discovery: newDiscoveryCache(0, "https://test.com/token", "https://test.com/certs"),
+			}
+
+			// when
+			app, err := client.AdoptApplication(context.TODO(), appID.String(), "Test-App-Name")
+
+			// then
+			require.Equal(t, tt.wantApp, app)
+			if tt.wantError != nil {
+				require.EqualError(t, err, tt.wantError.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			apiMock.AssertExpectations(t)
+		})
+	}
+}
+
+func mockPatchApplicationWithResponseStatusOk(clientMock *mocks.ClientWithResponsesInterface, appID uuid.UUID) {
+	clientMock.On("PatchApplicationWithResponse", mock.Anything, appID, mock.Anything, mock.Anything).
+		Return(&api.PatchApplicationResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: http.StatusOK,
+			},
+		}, nil)
+}
+
+func mockGetAPISecretsWithResponseStatusOk(clientMock *mocks.ClientWithResponsesInterface, appID uuid.UUID, hints ...string) {
+	secrets := make([]api.ApiSecretData, 0, len(hints))
+	for _, hint := range hints {
+		secrets = append(secrets, api.ApiSecretData{Hint: ptr.To(hint)})
+	}
+	clientMock.On("GetApiSecretsWithResponse", mock.Anything, appID).
+		Return(&api.GetApiSecretsResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: http.StatusOK,
+			},
+			JSON200: &api.ApiSecretsResponse{
+				Secrets: &secrets,
+			},
+		}, nil)
+}
+
+func mockDeleteAPISecretWithResponseStatusOk(clientMock *mocks.ClientWithResponsesInterface, appID uuid.UUID, hint string) {
+	clientMock.On("DeleteApiSecretWithResponse", mock.Anything, appID, &api.DeleteApiSecretParams{Hint: hint}).
+		Return(&api.DeleteApiSecretResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: http.StatusOK,
+			},
+		}, nil)
+}
+
 func mockGetAllApplicationsWithResponseStatusInternalServerError(clientMock *mocks.ClientWithResponsesInterface) {
 	clientMock.On("GetAllApplicationsWithResponse", mock.Anything, mock.Anything).
 		Return(&api.GetAllApplicationsResponse{
@@ -429,7 +958,7 @@ func mockGetAllApplicationsWithResponseStatusOk(clientMock *mocks.ClientWithResp
 }
 
 func mockCreateApplicationWithResponseStatusInternalServerError(clientMock *mocks.ClientWithResponsesInterface) {
-	clientMock.On("CreateApplicationWithResponse", mock.Anything, mock.Anything, newIasApplication("Test-App-Name")).
+	clientMock.On("CreateApplicationWithResponse", mock.Anything, mock.Anything, newIasApplication("Test-App-Name", AuthConfig{})).
 		Return(&api.CreateApplicationResponse{
 			HTTPResponse: &http.Response{
 				StatusCode: http.StatusInternalServerError,
@@ -438,7 +967,7 @@ func mockCreateApplicationWithResponseStatusInternalServerError(clientMock *mock
 }
 
 func mockCreateApplicationWithResponseStatusCreated(clientMock *mocks.ClientWithResponsesInterface, appID string) {
-	clientMock.On("CreateApplicationWithResponse", mock.Anything, mock.Anything, newIasApplication("Test-App-Name")).
+	clientMock.On("CreateApplicationWithResponse", mock.Anything, mock.Anything, newIasApplication("Test-App-Name", AuthConfig{})).
 		Return(&api.CreateApplicationResponse{
 			HTTPResponse: &http.Response{
 				StatusCode: http.StatusCreated,
@@ -485,6 +1014,25 @@ func mockGetApplicationWithResponseStatusInternalServerError(clientMock *mocks.C
 		}, nil)
 }
 
+func mockGetApplicationWithResponseStatusNotFound(clientMock *mocks.ClientWithResponsesInterface, times int) {
+	clientMock.On("GetApplicationWithResponse", mock.Anything, mock.Anything, mock.Anything).
+		Return(&api.GetApplicationResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: http.StatusNotFound,
+			},
+		}, nil).Times(times)
+}
+
+func mockGetApplicationWithResponseWithoutClientID(clientMock *mocks.ClientWithResponsesInterface, times int) {
+	clientMock.On("GetApplicationWithResponse", mock.Anything, mock.Anything, mock.Anything).
+		Return(&api.GetApplicationResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: http.StatusOK,
+			},
+			JSON200: &api.ApplicationResponse{},
+		}, nil).Times(times)
+}
+
 func mockGetApplicationWithResponseStatusOK(clientMock *mocks.ClientWithResponsesInterface, appID uuid.UUID) {
 	cID := "clientIdMock"
 	clientMock.On("GetApplicationWithResponse", mock.Anything, appID, mock.Anything).
@@ -500,6 +1048,16 @@ func mockGetApplicationWithResponseStatusOK(clientMock *mocks.ClientWithResponse
 		}, nil)
 }
 
+func mockGetApplicationWithResponseApplication(clientMock *mocks.ClientWithResponsesInterface, appID uuid.UUID, app *api.ApplicationResponse) {
+	clientMock.On("GetApplicationWithResponse", mock.Anything, appID, mock.Anything).
+		Return(&api.GetApplicationResponse{
+			HTTPResponse: &http.Response{
+				StatusCode: http.StatusOK,
+			},
+			JSON200: app,
+		}, nil)
+}
+
 func mockDeleteApplicationWithResponseStatusOk(clientMock *mocks.ClientWithResponsesInterface, appID uuid.UUID) {
 	clientMock.On("DeleteApplicationWithResponse", mock.Anything, appID).
 		Return(&api.DeleteApplicationResponse{
@@ -541,3 +1099,113 @@ func mockGetTokenEndpoint(t *testing.T, tokenURL *string) *eamoidcmocks.Client {
 	clientMock.On("GetTokenEndpoint", mock.Anything).Return(tokenURL, nil)
 	return clientMock
 }
+
+func Test_Probe(t *testing.T) {
+	tests := []struct {
+		name           string
+		givenAPIMock   func() *mocks.ClientWithResponsesInterface
+		oidcClientMock func(t *testing.T) *eamoidcmocks.Client
+		wantError      string
+		wantReason     string
+	}{
+		{
+			name: "should succeed if the OIDC configuration is fetched and the Applications API responds with status 200",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+				mockGetAllApplicationsWithResponseStatusOkEmptyResponse(&clientMock)
+				return &clientMock
+			},
+			oidcClientMock: func(t *testing.T) *eamoidcmocks.Client {
+				t.Helper()
+				return mockGetTokenEndpoint(t, ptr.To("https://test.com/token"))
+			},
+		},
+		{
+			name: "should succeed if the Applications API responds with status 404",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+				mockGetAllApplicationsWithResponseStatusNotFound(&clientMock)
+				return &clientMock
+			},
+			oidcClientMock: func(t *testing.T) *eamoidcmocks.Client {
+				t.Helper()
+				return mockGetTokenEndpoint(t, ptr.To("https://test.com/token"))
+			},
+		},
+		{
+			name: "should fail if the OIDC configuration can't be fetched",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				return &mocks.ClientWithResponsesInterface{}
+			},
+			oidcClientMock: func(t *testing.T) *eamoidcmocks.Client {
+				t.Helper()
+				return mockGetTokenEndpoint(t, nil)
+			},
+			wantError:  "OIDC discovery of the IAS tenant failed: failed to fetch token url",
+			wantReason: "IASTenantDiscoveryFailed",
+		},
+		{
+			name: "should fail if the Applications API responds with an error",
+			givenAPIMock: func() *mocks.ClientWithResponsesInterface {
+				clientMock := mocks.ClientWithResponsesInterface{}
+				mockGetAllApplicationsWithResponseStatusInternalServerError(&clientMock)
+				return &clientMock
+			},
+			oidcClientMock: func(t *testing.T) *eamoidcmocks.Client {
+				t.Helper()
+				return mockGetTokenEndpoint(t, ptr.To("https://test.com/token"))
+			},
+			wantError:  "Applications API of the IAS tenant failed: unexpected status code 500",
+			wantReason: "IASTenantApplicationsAPIUnavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			apiMock := tt.givenAPIMock()
+			client := client{
+				api:        apiMock,
+				oidcClient: tt.oidcClientMock(t),
+			}
+
+			// when
+			err := client.Probe(context.TODO())
+
+			// then
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+				var probeErr *ProbeError
+				require.ErrorAs(t, err, &probeErr)
+				require.Equal(t, tt.wantReason, probeErr.ConditionReason())
+			} else {
+				require.NoError(t, err)
+			}
+			apiMock.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_NewClient_staticEndpoints(t *testing.T) {
+	// given
+	opts := ClientOptions{TokenURL: "https://tenant.example.com/oauth2/token", JWKSURI: "https://tenant.example.com/oauth2/certs"}
+	apiMock := &mocks.ClientWithResponsesInterface{}
+	mockGetAllApplicationsWithResponseStatusOkEmptyResponse(apiMock)
+
+	// when
+	c, err := NewClient("https://tenant.accounts.ondemand.com", "user", "password", opts)
+	require.NoError(t, err)
+	// The OIDC client mock without expectations fails the test if the OIDC configuration is requested.
+	c.(*client).oidcClient = eamoidcmocks.NewClient(t)
+	c.(*client).api = apiMock
+
+	// then
+	tokenURL, err := c.GetTokenURL(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, "https://tenant.example.com/oauth2/token", *tokenURL)
+	jwksURI, err := c.GetJWKSURI(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, "https://tenant.example.com/oauth2/certs", *jwksURI)
+	require.NoError(t, c.Probe(context.TODO()))
+	apiMock.AssertExpectations(t)
+}
@@ -0,0 +1,105 @@
+package ias
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+)
+
+// DefaultDiscoveryTTL is the default duration after which the endpoints of the OIDC configuration of a tenant are discovered again.
+const DefaultDiscoveryTTL = time.Hour
+
+// discoveryCache caches the token URL and the JWKS URI of the OIDC configuration of a tenant. Since tenants occasionally move their endpoints
+// to new hosts, a discovered endpoint is discovered again once it is older than the TTL or the cache was invalidated. Overridden endpoints
+// are never discovered.
+type discoveryCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu       sync.Mutex
+	tokenURL cachedEndpoint
+	jwksURI  cachedEndpoint
+	// invalidations counts the invalidations of the cache. It is incremented without the lock, because the responses of the discovery itself
+	// can invalidate the cache while the lock is held.
+	invalidations atomic.Uint64
+}
+
+type cachedEndpoint struct {
+	url *string
+	// static is set if the endpoint is overridden.
+	static        bool
+	discoveredAt  time.Time
+	invalidations uint64
+}
+
+// newDiscoveryCache returns a cache whose discovered endpoints expire after the TTL, or are kept until the cache is invalidated if the TTL
+// is 0. The token URL and the JWKS URI are overridden if they aren't empty.
+func newDiscoveryCache(ttl time.Duration, tokenURL, jwksURI string) *discoveryCache {
+	d := &discoveryCache{ttl: ttl, now: time.Now}
+	if tokenURL != "" {
+		d.tokenURL = cachedEndpoint{url: &tokenURL, static: true}
+	}
+	if jwksURI != "" {
+		d.jwksURI = cachedEndpoint{url: &jwksURI, static: true}
+	}
+	return d
+}
+
+// invalidate marks all discovered endpoints as outdated, so that they are discovered again on their next use.
+func (d *discoveryCache) invalidate() {
+	d.invalidations.Add(1)
+}
+
+// get returns the cached endpoint, or discovers it if it isn't cached, is expired, or was invalidated. It returns errNotDiscovered if the
+// OIDC configuration doesn't contain the endpoint. Concurrent calls discover the endpoint only once.
+func (d *discoveryCache) get(ctx context.Context, endpoint *cachedEndpoint, discover func(context.Context) (*string, error),
+	errNotDiscovered error,
+) (*string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	invalidations := d.invalidations.Load()
+	if endpoint.url != nil && (endpoint.static || endpoint.invalidations == invalidations && !d.expired(endpoint)) {
+		return endpoint.url, nil
+	}
+
+	url, err := discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if url == nil {
+		return nil, errNotDiscovered
+	}
+	if endpoint.url != nil && *endpoint.url != *url {
+		kcontrollerruntime.Log.Info("Discovered a changed endpoint of the OIDC configuration of the IAS tenant", "previous", *endpoint.url,
+			"current", *url)
+	}
+	*endpoint = cachedEndpoint{url: url, discoveredAt: d.now(), invalidations: invalidations}
+	return url, nil
+}
+
+func (d *discoveryCache) expired(endpoint *cachedEndpoint) bool {
+	return d.ttl > 0 && d.now().Sub(endpoint.discoveredAt) >= d.ttl
+}
+
+// discoveryTransport invalidates the discovery cache if the tenant rejects a request with 401 Unauthorized or 403 Forbidden, since the
+// rejection can be caused by endpoints that were moved to new hosts.
+type discoveryTransport struct {
+	next      http.RoundTripper
+	discovery *discoveryCache
+}
+
+func newDiscoveryTransport(next http.RoundTripper, discovery *discoveryCache) http.RoundTripper {
+	return &discoveryTransport{next: next, discovery: discovery}
+}
+
+func (t *discoveryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err == nil && (res.StatusCode == http.StatusUnauthorized || res.StatusCode == http.StatusForbidden) {
+		t.discovery.invalidate()
+	}
+	return res, err
+}
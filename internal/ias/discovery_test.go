@@ -0,0 +1,134 @@
+package ias
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	eamoidcmocks "github.com/kyma-project/eventing-auth-manager/internal/ias/internal/oidc/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func Test_GetTokenURL_Discovery(t *testing.T) {
+	tests := []struct {
+		name         string
+		ttl          time.Duration
+		elapsed      time.Duration
+		invalidate   bool
+		wantTokenURL string
+	}{
+		{
+			name:         "should return the cached token URL before the TTL expired",
+			ttl:          time.Hour,
+			elapsed:      59 * time.Minute,
+			wantTokenURL: "https://old.example.com/token",
+		},
+		{
+			name:         "should discover the token URL again after the TTL expired",
+			ttl:          time.Hour,
+			elapsed:      time.Hour,
+			wantTokenURL: "https://new.example.com/token",
+		},
+		{
+			name:         "should keep the token URL without TTL",
+			elapsed:      24 * time.Hour,
+			wantTokenURL: "https://old.example.com/token",
+		},
+		{
+			name:         "should discover the token URL again after the cache was invalidated",
+			ttl:          time.Hour,
+			invalidate:   true,
+			wantTokenURL: "https://new.example.com/token",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+			oidcMock := eamoidcmocks.NewClient(t)
+			oidcMock.On("GetTokenEndpoint", mock.Anything).Return(ptr.To("https://old.example.com/token"), nil).Once()
+			oidcMock.On("GetTokenEndpoint", mock.Anything).Return(ptr.To("https://new.example.com/token"), nil).Maybe()
+			discovery := newDiscoveryCache(tt.ttl, "", "")
+			discovery.now = func() time.Time { return now }
+			c := client{oidcClient: oidcMock, discovery: discovery}
+			_, err := c.GetTokenURL(context.TODO())
+			require.NoError(t, err)
+
+			// when
+			now = now.Add(tt.elapsed)
+			if tt.invalidate {
+				discovery.invalidate()
+			}
+			tokenURL, err := c.GetTokenURL(context.TODO())
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tt.wantTokenURL, *tokenURL)
+		})
+	}
+}
+
+func Test_GetJWKSURI_StaticAfterInvalidation(t *testing.T) {
+	// given
+	// The OIDC client mock without expectations fails the test if the OIDC configuration is requested.
+	discovery := newDiscoveryCache(time.Hour, "", "https://static.example.com/certs")
+	discovery.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	c := client{oidcClient: eamoidcmocks.NewClient(t), discovery: discovery}
+
+	// when
+	discovery.invalidate()
+	jwksURI, err := c.GetJWKSURI(context.TODO())
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "https://static.example.com/certs", *jwksURI)
+}
+
+func Test_GetTokenURL_Concurrent(t *testing.T) {
+	// given
+	oidcMock := eamoidcmocks.NewClient(t)
+	oidcMock.On("GetTokenEndpoint", mock.Anything).Return(ptr.To("https://test.com/token"), nil).Once()
+	c := client{oidcClient: oidcMock, discovery: newDiscoveryCache(time.Hour, "", "")}
+
+	// when
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokenURL, err := c.GetTokenURL(context.TODO())
+			require.NoError(t, err)
+			require.Equal(t, "https://test.com/token", *tokenURL)
+		}()
+	}
+	wg.Wait()
+
+	// then
+	oidcMock.AssertNumberOfCalls(t, "GetTokenEndpoint", 1)
+}
+
+func Test_discoveryTransport(t *testing.T) {
+	for _, statusCode := range []int{http.StatusOK, http.StatusNotFound, http.StatusUnauthorized, http.StatusForbidden} {
+		// given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(statusCode)
+		}))
+		discovery := newDiscoveryCache(0, "", "")
+		httpClient := &http.Client{Transport: newDiscoveryTransport(http.DefaultTransport, discovery)}
+
+		// when
+		res, err := httpClient.Get(server.URL)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+		server.Close()
+
+		// then
+		wantInvalidated := statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden
+		require.Equal(t, wantInvalidated, discovery.invalidations.Load() > 0, "status code %d", statusCode)
+	}
+}
@@ -0,0 +1,123 @@
+package ias
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/kyma-project/eventing-auth-manager/internal/ias/internal/api"
+	"github.com/pkg/errors"
+	kcontrollerruntime "sigs.k8s.io/controller-runtime"
+)
+
+// DuplicatePolicy defines how the client handles multiple applications with the same name, e.g. after a creation was retried while IAS
+// still created the application of the previous try.
+type DuplicatePolicy string
+
+const (
+	// DuplicatePolicyFail fails the operations on the applications until the duplicates are removed manually.
+	DuplicatePolicyFail DuplicatePolicy = "fail"
+	// DuplicatePolicyKeepNewest keeps the most recently created application that is marked with ManagedApplicationDescription and deletes
+	// the other applications with the same name.
+	DuplicatePolicyKeepNewest DuplicatePolicy = "keep-newest"
+	// DuplicatePolicyAdoptByID keeps the application whose ID is stored in the context with WithApplicationID and deletes the other
+	// applications with the same name.
+	DuplicatePolicyAdoptByID DuplicatePolicy = "adopt-by-id"
+)
+
+// DuplicatePolicies are the supported duplicate policies.
+var DuplicatePolicies = []DuplicatePolicy{DuplicatePolicyFail, DuplicatePolicyKeepNewest, DuplicatePolicyAdoptByID} //nolint:gochecknoglobals // Read-only list of the policies.
+
+// Validate returns an error if the policy isn't supported. The empty policy is DuplicatePolicyFail.
+func (p DuplicatePolicy) Validate() error {
+	if p == "" {
+		return nil
+	}
+	for _, policy := range DuplicatePolicies {
+		if p == policy {
+			return nil
+		}
+	}
+	return errors.Errorf("unsupported policy for duplicate applications: %s", p)
+}
+
+type applicationIDKey struct{}
+
+// WithApplicationID returns a context that stores the ID of the application that was last provisioned for the EventingAuth CR, which is
+// kept by DuplicatePolicyAdoptByID.
+func WithApplicationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, applicationIDKey{}, id)
+}
+
+// applicationIDOf returns the application ID that is stored in the context, or an empty ID.
+func applicationIDOf(ctx context.Context) string {
+	id, _ := ctx.Value(applicationIDKey{}).(string)
+	return id
+}
+
+// resolveDuplicates returns the application with the given name that is kept by the duplicate policy of the client, and deletes the other
+// applications. It fails if the policy is DuplicatePolicyFail or if no application can be kept.
+func (c *client) resolveDuplicates(ctx context.Context, name string, apps []api.ApplicationResponse) (*api.ApplicationResponse, error) {
+	var kept *api.ApplicationResponse
+	switch c.duplicatePolicy {
+	case DuplicatePolicyKeepNewest:
+		kept = newestManagedApplication(apps)
+		if kept == nil {
+			return nil, errors.Errorf("found multiple applications with the same name %s, but none is marked as managed", name)
+		}
+	case DuplicatePolicyAdoptByID:
+		id := applicationIDOf(ctx)
+		if id == "" {
+			return nil, errors.Errorf("found multiple applications with the same name %s, but no application ID is stored", name)
+		}
+		for i := range apps {
+			if apps[i].Id != nil && apps[i].Id.String() == id {
+				kept = &apps[i]
+				break
+			}
+		}
+		if kept == nil {
+			return nil, errors.Errorf("found multiple applications with the same name %s, but none has the stored ID %s", name, id)
+		}
+	default:
+		return nil, errors.Errorf("found multiple applications with the same name %s", name)
+	}
+
+	for i := range apps {
+		if &apps[i] == kept || apps[i].Id == nil {
+			continue
+		}
+		kcontrollerruntime.Log.Info("Deleting duplicate application", "name", name, "id", *apps[i].Id, "keptID", *kept.Id,
+			"policy", c.duplicatePolicy)
+		if err := c.deleteApplication(ctx, *apps[i].Id); err != nil {
+			return nil, errors.Wrapf(err, "failed to delete duplicate application %s", *apps[i].Id)
+		}
+	}
+	return kept, nil
+}
+
+// newestManagedApplication returns the most recently created application that is marked with ManagedApplicationDescription, or nil if no
+// application is marked. Applications without creation time are older than all others.
+func newestManagedApplication(apps []api.ApplicationResponse) *api.ApplicationResponse {
+	var managed []*api.ApplicationResponse
+	for i := range apps {
+		if apps[i].Id != nil && apps[i].Description != nil && *apps[i].Description == ManagedApplicationDescription {
+			managed = append(managed, &apps[i])
+		}
+	}
+	if len(managed) == 0 {
+		return nil
+	}
+	sort.SliceStable(managed, func(i, j int) bool {
+		return createdTime(managed[i]).Before(createdTime(managed[j]))
+	})
+	return managed[len(managed)-1]
+}
+
+// createdTime returns the creation time of the application, or the zero time if it isn't known.
+func createdTime(app *api.ApplicationResponse) time.Time {
+	if app.Meta == nil || app.Meta.Created == nil {
+		return time.Time{}
+	}
+	return *app.Meta.Created
+}
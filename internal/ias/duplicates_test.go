@@ -0,0 +1,119 @@
+package ias
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kyma-project/eventing-auth-manager/internal/ias/internal/api"
+	"github.com/kyma-project/eventing-auth-manager/internal/ias/internal/api/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func Test_GetApplicationByName_Duplicates(t *testing.T) {
+	oldID := uuid.MustParse("90764f89-f041-4ccf-8da9-7a7c2d60d7fc")
+	newID := uuid.MustParse("41de6fec-e0fc-47d7-b35c-3b19c4927e4f")
+	unmarkedID := uuid.MustParse("4e2b7ea4-4b47-4a88-9b36-c5e8d2f0a1b3")
+	managed := ManagedApplicationDescription
+	duplicates := []api.ApplicationResponse{
+		{Id: &oldID, Description: &managed, Meta: &api.Meta{Created: ptr.To(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))}},
+		{Id: &newID, Description: &managed, Meta: &api.Meta{Created: ptr.To(time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC))}},
+		{Id: &unmarkedID},
+	}
+	tests := []struct {
+		name        string
+		policy      DuplicatePolicy
+		storedID    string
+		wantDeleted []uuid.UUID
+		wantKept    uuid.UUID
+		wantError   string
+		givenApps   []api.ApplicationResponse
+	}{
+		{
+			name:      "should fail with the fail policy",
+			policy:    DuplicatePolicyFail,
+			givenApps: duplicates,
+			wantError: "found multiple applications with the same name Test-App-Name",
+		},
+		{
+			name:        "should keep the newest managed application and delete the others",
+			policy:      DuplicatePolicyKeepNewest,
+			givenApps:   duplicates,
+			wantDeleted: []uuid.UUID{oldID, unmarkedID},
+			wantKept:    newID,
+		},
+		{
+			name:      "should fail to keep the newest application if none is managed",
+			policy:    DuplicatePolicyKeepNewest,
+			givenApps: []api.ApplicationResponse{{Id: &oldID}, {Id: &unmarkedID}},
+			wantError: "found multiple applications with the same name Test-App-Name, but none is marked as managed",
+		},
+		{
+			name:        "should adopt the application with the stored ID and delete the others",
+			policy:      DuplicatePolicyAdoptByID,
+			storedID:    oldID.String(),
+			givenApps:   duplicates,
+			wantDeleted: []uuid.UUID{newID, unmarkedID},
+			wantKept:    oldID,
+		},
+		{
+			name:      "should fail to adopt the application without stored ID",
+			policy:    DuplicatePolicyAdoptByID,
+			givenApps: duplicates,
+			wantError: "found multiple applications with the same name Test-App-Name, but no application ID is stored",
+		},
+		{
+			name:      "should fail to adopt the application if no application has the stored ID",
+			policy:    DuplicatePolicyAdoptByID,
+			storedID:  "a1b2c3d4-0000-4000-8000-000000000001",
+			givenApps: duplicates,
+			wantError: "found multiple applications with the same name Test-App-Name, but none has the stored ID " +
+				"a1b2c3d4-0000-4000-8000-000000000001",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			apiMock := &mocks.ClientWithResponsesInterface{}
+			apps := append([]api.ApplicationResponse(nil), tt.givenApps...)
+			appsFilter := "name eq Test-App-Name"
+			apiMock.On("GetAllApplicationsWithResponse", mock.Anything, &api.GetAllApplicationsParams{Filter: &appsFilter}).
+				Return(&api.GetAllApplicationsResponse{
+					HTTPResponse: &http.Response{StatusCode: http.StatusOK},
+					JSON200:      &api.ApplicationsResponse{Applications: &apps},
+				}, nil)
+			var deleted []uuid.UUID
+			apiMock.On("DeleteApplicationWithResponse", mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) { deleted = append(deleted, args.Get(1).(uuid.UUID)) }).
+				Return(&api.DeleteApplicationResponse{HTTPResponse: &http.Response{StatusCode: http.StatusOK}}, nil)
+			client := client{api: apiMock, duplicatePolicy: tt.policy}
+			ctx := context.TODO()
+			if tt.storedID != "" {
+				ctx = WithApplicationID(ctx, tt.storedID)
+			}
+
+			// when
+			kept, err := client.getApplicationByName(ctx, "Test-App-Name")
+
+			// then
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantKept, *kept.Id)
+			}
+			require.Equal(t, tt.wantDeleted, deleted)
+		})
+	}
+}
+
+func TestDuplicatePolicy_Validate(t *testing.T) {
+	for _, policy := range append(DuplicatePolicies, "") {
+		require.NoError(t, policy.Validate())
+	}
+	require.EqualError(t, DuplicatePolicy("delete-all").Validate(), "unsupported policy for duplicate applications: delete-all")
+}
@@ -0,0 +1,68 @@
+package ias
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// StatusError is returned if the Applications API of the IAS tenant responds with an unexpected status code. Its message is the message of
+// the wrapped error, so that the status code is only used to classify the error.
+type StatusError struct {
+	Err        error
+	StatusCode int
+}
+
+func newStatusError(err error, statusCode int) error {
+	return &StatusError{Err: err, StatusCode: statusCode}
+}
+
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// IsAuthenticationError returns true if the error is or wraps a StatusError because the IAS tenant rejected the credentials of the client.
+func IsAuthenticationError(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusUnauthorized || statusErr.StatusCode == http.StatusForbidden)
+}
+
+// IsThrottledError returns true if the error is or wraps a StatusError because the IAS tenant throttled the requests of the client.
+func IsThrottledError(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusTooManyRequests
+}
+
+// IsUnavailableError returns true if the error is or wraps a StatusError because the IAS tenant is temporarily unavailable, e.g. during its
+// maintenance.
+func IsUnavailableError(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusServiceUnavailable
+}
+
+// IsStatusError returns true if the error is or wraps a StatusError.
+func IsStatusError(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr)
+}
+
+// ApplicationChangedError is returned if an application isn't deleted by its ID, because it doesn't have the expected name or isn't marked
+// with ManagedApplicationDescription anymore, e.g. because it was changed externally since its ID was read.
+type ApplicationChangedError struct {
+	ID   string
+	Name string
+}
+
+func (e *ApplicationChangedError) Error() string {
+	return fmt.Sprintf("application %s is not the managed application %s anymore", e.ID, e.Name)
+}
+
+// IsApplicationChangedError returns true if the error is or wraps an ApplicationChangedError.
+func IsApplicationChangedError(err error) bool {
+	var changedErr *ApplicationChangedError
+	return errors.As(err, &changedErr)
+}
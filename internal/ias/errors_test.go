@@ -0,0 +1,72 @@
+package ias
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StatusError(t *testing.T) {
+	tests := []struct {
+		name              string
+		err               error
+		wantAuthenticated bool
+		wantThrottled     bool
+		wantUnavailable   bool
+		wantStatus        bool
+	}{
+		{
+			name:              "should be an authentication error if the credentials are rejected",
+			err:               errors.Wrap(newStatusError(errCreateApplication, http.StatusUnauthorized), "failed to create application in IAS"),
+			wantAuthenticated: true,
+			wantStatus:        true,
+		},
+		{
+			name:              "should be an authentication error if the technical user lacks permissions",
+			err:               newStatusError(errCreateApplication, http.StatusForbidden),
+			wantAuthenticated: true,
+			wantStatus:        true,
+		},
+		{
+			name:          "should be a throttled error if the requests are throttled",
+			err:           newStatusError(errFetchExistingApplications, http.StatusTooManyRequests),
+			wantThrottled: true,
+			wantStatus:    true,
+		},
+		{
+			name:            "should be an unavailable error if the tenant is unavailable",
+			err:             errors.Wrap(newStatusError(errCreateApplication, http.StatusServiceUnavailable), "failed to create application in IAS"),
+			wantUnavailable: true,
+			wantStatus:      true,
+		},
+		{
+			name:       "should only be a status error for other status codes",
+			err:        newStatusError(errCreateApplication, http.StatusInternalServerError),
+			wantStatus: true,
+		},
+		{
+			name: "should not be a status error without a status code",
+			err:  errFetchTokenURL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// then
+			require.Equal(t, tt.wantAuthenticated, IsAuthenticationError(tt.err))
+			require.Equal(t, tt.wantThrottled, IsThrottledError(tt.err))
+			require.Equal(t, tt.wantUnavailable, IsUnavailableError(tt.err))
+			require.Equal(t, tt.wantStatus, IsStatusError(tt.err))
+		})
+	}
+}
+
+func Test_StatusError_Error(t *testing.T) {
+	// when
+	err := newStatusError(errCreateApplication, http.StatusUnauthorized)
+
+	// then
+	require.EqualError(t, err, "failed to create application")
+	require.ErrorIs(t, err, errCreateApplication)
+}
@@ -0,0 +1,34 @@
+package ias
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxFilterLength is the maximum length of the filter of the Applications API.
+const maxFilterLength = 255
+
+// unquotedFilterValue matches the values that are used without quotes in a filter, like in the examples of the Applications API, e.g.
+// 'name eq SFSF'.
+var unquotedFilterValue = regexp.MustCompile(`^[A-Za-z0-9._~-]+$`)
+
+// nameFilter returns the filter of the Applications API that matches the applications with the given name. Names with other characters than
+// letters, digits, '.', '_', '~', and '-', e.g. with spaces or quotes, are quoted as OData string literal, whose single quotes are doubled.
+// The filter is URL-encoded by the client of the Applications API.
+func nameFilter(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("the name of the application must not be empty")
+	}
+	value := name
+	if !unquotedFilterValue.MatchString(name) {
+		value = "'" + strings.ReplaceAll(name, "'", "''") + "'"
+	}
+	filter := "name eq " + value
+	if len(filter) > maxFilterLength {
+		return "", errors.Errorf("the name of the application is too long for the filter of the Applications API, which is limited to %d "+
+			"characters", maxFilterLength)
+	}
+	return filter, nil
+}
@@ -0,0 +1,98 @@
+package ias
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_nameFilter(t *testing.T) {
+	tests := []struct {
+		name       string
+		appName    string
+		wantFilter string
+		wantError  string
+	}{
+		{
+			name:       "should not quote a name with letters, digits, and dashes",
+			appName:    "Test-App-Name",
+			wantFilter: "name eq Test-App-Name",
+		},
+		{
+			name:       "should quote a name with spaces",
+			appName:    "Test App",
+			wantFilter: "name eq 'Test App'",
+		},
+		{
+			name:       "should double the single quotes of the name",
+			appName:    "Test's App",
+			wantFilter: "name eq 'Test''s App'",
+		},
+		{
+			name:       "should quote a name with operators",
+			appName:    "a or name eq b",
+			wantFilter: "name eq 'a or name eq b'",
+		},
+		{
+			name:      "should fail for an empty name",
+			wantError: "the name of the application must not be empty",
+		},
+		{
+			name:    "should fail for a name that exceeds the maximum length of the filter",
+			appName: strings.Repeat("a", 250),
+			wantError: "the name of the application is too long for the filter of the Applications API, which is limited to 255 " +
+				"characters",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			filter, err := nameFilter(tt.appName)
+
+			// then
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantFilter, filter)
+			}
+		})
+	}
+}
+
+func Fuzz_nameFilter(f *testing.F) {
+	for _, name := range []string{"Test-App-Name", "Test App", "Test's App", "''", "a' or name eq 'b", "ä\x00\n"} {
+		f.Add(name)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		filter, err := nameFilter(name)
+		if err != nil {
+			return
+		}
+		require.LessOrEqual(t, len(filter), maxFilterLength)
+		parsed, ok := parseNameFilter(filter)
+		require.True(t, ok, "filter %q isn't a single comparison", filter)
+		require.Equal(t, name, parsed)
+	})
+}
+
+// parseNameFilter returns the name of a filter that compares the name with a single unquoted value or OData string literal.
+func parseNameFilter(filter string) (string, bool) {
+	value, ok := strings.CutPrefix(filter, "name eq ")
+	if !ok || value == "" {
+		return "", false
+	}
+	if !strings.HasPrefix(value, "'") {
+		return value, unquotedFilterValue.MatchString(value)
+	}
+	if len(value) < 2 || !strings.HasSuffix(value, "'") {
+		return "", false
+	}
+	literal := value[1 : len(value)-1]
+	// Every single quote within the literal must be escaped by another single quote.
+	if strings.Count(strings.ReplaceAll(literal, "''", ""), "'") > 0 {
+		return "", false
+	}
+	return strings.ReplaceAll(literal, "''", "'"), true
+}
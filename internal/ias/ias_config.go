@@ -3,6 +3,7 @@ package ias
 import (
 	"context"
 
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
 	"github.com/pkg/errors"
 	kcorev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -16,6 +17,7 @@ const (
 )
 
 func NewCredentials(url, username, password string) *Credentials {
+	redact.Secret(password)
 	return &Credentials{
 		URL:      url,
 		Username: username,
@@ -39,7 +41,11 @@ var ReadCredentials = func(namespace, name string, k8sClient kpkgclient.Client)
 	if err := k8sClient.Get(context.TODO(), namespacedName, iasSecret); err != nil {
 		return nil, err
 	}
+	return CredentialsFromSecret(iasSecret)
+}
 
+// CredentialsFromSecret returns the ias credentials of the keys 'url', 'username', and 'password' of the secret.
+func CredentialsFromSecret(iasSecret *kcorev1.Secret) (*Credentials, error) {
 	var exists bool
 	var url, username, password []byte
 	var err error
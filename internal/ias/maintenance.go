@@ -0,0 +1,73 @@
+package ias
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Maintenance detects the maintenance of an IAS tenant, during which the tenant responds to all requests with 503 Service Unavailable. The
+// tenant is in maintenance once its responses were 503 for longer than the threshold, and leaves the maintenance with the first response
+// of another status code. Requests that fail without response, e.g. because of a timeout, don't change the state.
+type Maintenance struct {
+	threshold time.Duration
+	now       func() time.Time
+
+	mu sync.Mutex
+	// unavailableSince is the time of the first 503 response after the last response of another status code. It is zero if the last
+	// response wasn't 503.
+	unavailableSince time.Time
+}
+
+// NewMaintenance returns the maintenance detection of a tenant, whose responses must be 503 for longer than the threshold.
+func NewMaintenance(threshold time.Duration) *Maintenance {
+	return &Maintenance{threshold: threshold, now: time.Now}
+}
+
+// Active returns true if the tenant is in maintenance, and the time since the responses of the tenant are 503.
+func (m *Maintenance) Active() (bool, time.Time) {
+	if m == nil {
+		return false, time.Time{}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.unavailableSince.IsZero() {
+		return false, time.Time{}
+	}
+	return m.now().Sub(m.unavailableSince) >= m.threshold, m.unavailableSince
+}
+
+// observe records the status code of a response of the tenant.
+func (m *Maintenance) observe(statusCode int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case statusCode != http.StatusServiceUnavailable:
+		m.unavailableSince = time.Time{}
+	case m.unavailableSince.IsZero():
+		m.unavailableSince = m.now()
+	}
+}
+
+// maintenanceTransport records the status codes of the responses of the IAS tenant in its maintenance detection.
+type maintenanceTransport struct {
+	next        http.RoundTripper
+	maintenance *Maintenance
+}
+
+// newMaintenanceTransport returns a transport that records the responses of the given transport in the maintenance detection, or the given
+// transport if the maintenance detection is nil.
+func newMaintenanceTransport(next http.RoundTripper, maintenance *Maintenance) http.RoundTripper {
+	if maintenance == nil {
+		return next
+	}
+	return &maintenanceTransport{next: next, maintenance: maintenance}
+}
+
+func (t *maintenanceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err == nil {
+		t.maintenance.observe(res.StatusCode)
+	}
+	return res, err
+}
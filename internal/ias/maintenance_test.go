@@ -0,0 +1,66 @@
+package ias
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Maintenance(t *testing.T) {
+	// given
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	statusCode := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+	maintenance := NewMaintenance(10 * time.Minute)
+	maintenance.now = func() time.Time { return now }
+	httpClient := &http.Client{Transport: newMaintenanceTransport(http.DefaultTransport, maintenance)}
+	request := func() {
+		res, err := httpClient.Get(server.URL)
+		require.NoError(t, err)
+		require.NoError(t, res.Body.Close())
+	}
+	start := now
+
+	// when
+	request()
+	now = now.Add(5 * time.Minute)
+	request()
+
+	// then
+	active, since := maintenance.Active()
+	require.False(t, active)
+	require.Equal(t, start, since)
+
+	// when
+	now = now.Add(5 * time.Minute)
+
+	// then
+	active, since = maintenance.Active()
+	require.True(t, active)
+	require.Equal(t, start, since)
+
+	// when
+	statusCode = http.StatusOK
+	request()
+
+	// then
+	active, since = maintenance.Active()
+	require.False(t, active)
+	require.True(t, since.IsZero())
+}
+
+func Test_Maintenance_Nil(t *testing.T) {
+	// when
+	var maintenance *Maintenance
+	active, _ := maintenance.Active()
+
+	// then
+	require.False(t, active)
+	require.Equal(t, http.DefaultTransport, newMaintenanceTransport(http.DefaultTransport, nil))
+}
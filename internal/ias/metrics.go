@@ -0,0 +1,121 @@
+package ias
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Operations of the requests to the IAS tenant, that the request metrics are labeled with.
+const (
+	operationCreateApp     = "createApp"
+	operationGetApp        = "getApp"
+	operationUpdateApp     = "updateApp"
+	operationDeleteApp     = "deleteApp"
+	operationCreateSecret  = "createSecret"
+	operationGetSecrets    = "getSecrets"
+	operationDeleteSecret  = "deleteSecret"
+	operationOIDCDiscovery = "oidcDiscovery"
+	operationOther         = "other"
+)
+
+// codeError is the status code label of the requests that failed without a response, e.g. because of a timeout.
+const codeError = "error"
+
+const (
+	applicationsPath  = "/Applications/v1/"
+	apiSecretsSuffix  = "/apiSecrets"
+	oidcDiscoveryPath = "/.well-known/openid-configuration"
+)
+
+// requestsTotal counts the requests to the IAS tenants by their operation and the status code of their response.
+var requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_ias_requests_total",
+	Help: "Number of requests to the IAS tenants by their operation and the HTTP status code of their response.",
+}, []string{"operation", "code"})
+
+// requestDuration observes the latency of the requests to the IAS tenants by their operation and the status code of their response.
+var requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name:    "eventing_auth_manager_ias_request_duration_seconds",
+	Help:    "Latency of the requests to the IAS tenants in seconds by their operation and the HTTP status code of their response.",
+	Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+}, []string{"operation", "code"})
+
+func init() { //nolint:gochecknoinits // Metrics are registered on the package level.
+	metrics.Registry.MustRegister(requestsTotal, requestDuration)
+}
+
+// instrumentedTransport records the requests to the IAS tenant in the request metrics.
+type instrumentedTransport struct {
+	next http.RoundTripper
+}
+
+// newInstrumentedTransport returns a transport that records the requests of the given transport in the request metrics. Since it is
+// wrapped by the rate limited transport, the latency doesn't include the time that the requests are delayed by the rate limit.
+func newInstrumentedTransport(next http.RoundTripper) http.RoundTripper {
+	return &instrumentedTransport{next: next}
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	code := codeError
+	if err == nil {
+		code = strconv.Itoa(res.StatusCode)
+	}
+	operation := operationOf(req)
+	requestDuration.WithLabelValues(operation, code).Observe(time.Since(start).Seconds())
+	requestsTotal.WithLabelValues(operation, code).Inc()
+	return res, err
+}
+
+// spanNameOf returns the name of the span of a request to the IAS tenant, which is named by its operation.
+func spanNameOf(req *http.Request) string {
+	return "IAS " + operationOf(req)
+}
+
+// operationOf returns the operation label of a request of the Applications API or the OIDC discovery.
+func operationOf(req *http.Request) string {
+	path := req.URL.Path
+	if path == oidcDiscoveryPath {
+		return operationOIDCDiscovery
+	}
+	i := strings.Index(path, applicationsPath)
+	if i < 0 {
+		return operationOther
+	}
+	resource := path[i+len(applicationsPath):]
+
+	switch {
+	case strings.HasSuffix(resource, apiSecretsSuffix):
+		switch req.Method {
+		case http.MethodPost:
+			return operationCreateSecret
+		case http.MethodGet:
+			return operationGetSecrets
+		case http.MethodDelete:
+			return operationDeleteSecret
+		}
+	case resource == "":
+		switch req.Method {
+		case http.MethodPost:
+			return operationCreateApp
+		case http.MethodGet:
+			return operationGetApp
+		}
+	default:
+		switch req.Method {
+		case http.MethodGet:
+			return operationGetApp
+		case http.MethodPatch, http.MethodPut:
+			return operationUpdateApp
+		case http.MethodDelete:
+			return operationDeleteApp
+		}
+	}
+	return operationOther
+}
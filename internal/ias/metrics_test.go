@@ -0,0 +1,114 @@
+package ias
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_operationOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		url    string
+		want   string
+	}{
+		{
+			name:   "should return createApp for the creation of an application",
+			method: http.MethodPost,
+			url:    "https://tenant.accounts.ondemand.com/Applications/v1/",
+			want:   operationCreateApp,
+		},
+		{
+			name:   "should return getApp for the listing of the applications",
+			method: http.MethodGet,
+			url:    "https://tenant.accounts.ondemand.com/Applications/v1/?filter=name%20eq%20app",
+			want:   operationGetApp,
+		},
+		{
+			name:   "should return getApp for the retrieval of an application",
+			method: http.MethodGet,
+			url:    "https://tenant.accounts.ondemand.com/Applications/v1/5f8a6b9c-6c4b-4d2a-9d6e-0f8b1b1c2d3e",
+			want:   operationGetApp,
+		},
+		{
+			name:   "should return updateApp for the patch of an application",
+			method: http.MethodPatch,
+			url:    "https://tenant.accounts.ondemand.com/Applications/v1/5f8a6b9c-6c4b-4d2a-9d6e-0f8b1b1c2d3e",
+			want:   operationUpdateApp,
+		},
+		{
+			name:   "should return deleteApp for the deletion of an application",
+			method: http.MethodDelete,
+			url:    "https://tenant.accounts.ondemand.com/Applications/v1/5f8a6b9c-6c4b-4d2a-9d6e-0f8b1b1c2d3e",
+			want:   operationDeleteApp,
+		},
+		{
+			name:   "should return createSecret for the creation of an API secret",
+			method: http.MethodPost,
+			url:    "https://tenant.accounts.ondemand.com/Applications/v1/5f8a6b9c-6c4b-4d2a-9d6e-0f8b1b1c2d3e/apiSecrets",
+			want:   operationCreateSecret,
+		},
+		{
+			name:   "should return getSecrets for the listing of the API secrets",
+			method: http.MethodGet,
+			url:    "https://tenant.accounts.ondemand.com/Applications/v1/5f8a6b9c-6c4b-4d2a-9d6e-0f8b1b1c2d3e/apiSecrets",
+			want:   operationGetSecrets,
+		},
+		{
+			name:   "should return deleteSecret for the deletion of an API secret",
+			method: http.MethodDelete,
+			url:    "https://tenant.accounts.ondemand.com/Applications/v1/5f8a6b9c-6c4b-4d2a-9d6e-0f8b1b1c2d3e/apiSecrets",
+			want:   operationDeleteSecret,
+		},
+		{
+			name:   "should return oidcDiscovery for the OIDC configuration",
+			method: http.MethodGet,
+			url:    "https://tenant.accounts.ondemand.com/.well-known/openid-configuration",
+			want:   operationOIDCDiscovery,
+		},
+		{
+			name:   "should return other for unknown requests",
+			method: http.MethodGet,
+			url:    "https://tenant.accounts.ondemand.com/service/scim/Users",
+			want:   operationOther,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+
+			// when
+			operation := operationOf(req)
+
+			// then
+			require.Equal(t, tt.want, operation)
+		})
+	}
+}
+
+func Test_instrumentedTransport_RoundTrip(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	httpClient := &http.Client{Transport: newInstrumentedTransport(http.DefaultTransport)}
+	before := testutil.ToFloat64(requestsTotal.WithLabelValues(operationCreateApp, "429"))
+	beforeErrors := testutil.ToFloat64(requestsTotal.WithLabelValues(operationOIDCDiscovery, codeError))
+
+	// when
+	res, err := httpClient.Post(server.URL+"/Applications/v1/", "application/json", nil)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	_, err = httpClient.Get("http://127.0.0.1:0/.well-known/openid-configuration") //nolint:noctx // The request fails anyway.
+
+	// then
+	require.Error(t, err)
+	require.Equal(t, before+1, testutil.ToFloat64(requestsTotal.WithLabelValues(operationCreateApp, "429")))
+	require.Equal(t, beforeErrors+1, testutil.ToFloat64(requestsTotal.WithLabelValues(operationOIDCDiscovery, codeError)))
+}
@@ -0,0 +1,68 @@
+package ias
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/ias/internal/api"
+	"k8s.io/utils/ptr"
+)
+
+// probeApplicationName is the name of the application that is requested by the probe. The application doesn't need to exist.
+const probeApplicationName = "eventing-auth-manager-probe"
+
+// ProbeError is returned if the IAS tenant isn't reachable with the credentials of the client.
+type ProbeError struct {
+	// Endpoint is the endpoint of the tenant that failed, which is either the OIDC discovery or the Applications API.
+	Endpoint string
+	Err      error
+}
+
+func (e *ProbeError) Error() string {
+	return fmt.Sprintf("%s of the IAS tenant failed: %s", e.Endpoint, e.Err)
+}
+
+func (e *ProbeError) Unwrap() error {
+	return e.Err
+}
+
+// ConditionReason returns the reason of the IASTenantReady condition of the IASTenant CR.
+func (e *ProbeError) ConditionReason() string {
+	if e.Endpoint == ProbeEndpointOIDCDiscovery {
+		return eamapiv1alpha1.ConditionReasonTenantDiscoveryFailed
+	}
+	return eamapiv1alpha1.ConditionReasonTenantAPIUnavailable
+}
+
+// The endpoints of the IAS tenant that are checked by the probe, in the order of the checks.
+const (
+	ProbeEndpointOIDCDiscovery  = "OIDC discovery"
+	ProbeEndpointApplicationAPI = "Applications API"
+)
+
+// Probe checks that the IAS tenant is reachable with the credentials of the client. The OIDC configuration is fetched from the tenant
+// instead of the cache of the client, unless both of its endpoints are overridden, and an application is requested from the Applications
+// API, which rejects invalid credentials.
+func (c *client) Probe(ctx context.Context) error {
+	if !c.staticEndpoints {
+		tokenEndpoint, err := c.oidcClient.GetTokenEndpoint(ctx)
+		if err != nil {
+			return &ProbeError{Endpoint: ProbeEndpointOIDCDiscovery, Err: err}
+		}
+		if tokenEndpoint == nil {
+			return &ProbeError{Endpoint: ProbeEndpointOIDCDiscovery, Err: errFetchTokenURL}
+		}
+	}
+
+	res, err := c.api.GetAllApplicationsWithResponse(ctx, &api.GetAllApplicationsParams{Filter: ptr.To("name eq " + probeApplicationName)})
+	if err != nil {
+		return &ProbeError{Endpoint: ProbeEndpointApplicationAPI, Err: err}
+	}
+	// The Applications API responds with 404 if no application matches the filter.
+	if res.StatusCode() != http.StatusOK && res.StatusCode() != http.StatusNotFound {
+		return &ProbeError{Endpoint: ProbeEndpointApplicationAPI, Err: fmt.Errorf("unexpected status code %d", res.StatusCode())}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package ias
+
+import (
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitedTransport delays the requests to the IAS tenant, so that they don't exceed the configured rate limit.
+type rateLimitedTransport struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+// newRateLimitedTransport returns the given transport if the options don't limit the rate of the requests.
+func newRateLimitedTransport(next http.RoundTripper, opts ClientOptions) http.RoundTripper {
+	if opts.RequestsPerSecond <= 0 {
+		return next
+	}
+	burst := opts.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+	return &rateLimitedTransport{next: next, limiter: rate.NewLimiter(rate.Limit(opts.RequestsPerSecond), burst)}
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.next.RoundTrip(req)
+}
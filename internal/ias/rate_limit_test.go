@@ -0,0 +1,50 @@
+package ias
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+func Test_newRateLimitedTransport(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      ClientOptions
+		wantLimit rate.Limit
+		wantBurst int
+	}{
+		{
+			name: "should not limit the requests without requests per second",
+		},
+		{
+			name:      "should limit the requests with the default burst",
+			opts:      ClientOptions{RequestsPerSecond: 10},
+			wantLimit: 10,
+			wantBurst: 1,
+		},
+		{
+			name:      "should limit the requests with the given burst",
+			opts:      ClientOptions{RequestsPerSecond: 10, Burst: 5},
+			wantLimit: 10,
+			wantBurst: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			transport := newRateLimitedTransport(http.DefaultTransport, tt.opts)
+
+			// then
+			if tt.wantLimit == 0 {
+				require.Equal(t, http.DefaultTransport, transport)
+				return
+			}
+			limited, ok := transport.(*rateLimitedTransport)
+			require.True(t, ok)
+			require.Equal(t, tt.wantLimit, limited.limiter.Limit())
+			require.Equal(t, tt.wantBurst, limited.limiter.Burst())
+		})
+	}
+}
@@ -1,10 +1,26 @@
 package ias
 
 import (
+	"time"
+
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
 	kcorev1 "k8s.io/api/core/v1"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Keys of the data of the application secret.
+const (
+	SecretKeyClientID     = "client_id"
+	SecretKeyClientSecret = "client_secret"
+	SecretKeyTokenURL     = "token_url"
+	SecretKeyCertsURL     = "certs_url"
+)
+
+// SecretKeys returns the keys of the data of the application secret in a stable order.
+func SecretKeys() []string {
+	return []string{SecretKeyClientID, SecretKeyClientSecret, SecretKeyTokenURL, SecretKeyCertsURL}
+}
+
 type Application struct {
 	id           string
 	clientID     string
@@ -14,6 +30,7 @@ type Application struct {
 }
 
 func NewApplication(id, clientID, clientSecret, tokenURL, certsURL string) Application {
+	redact.Secret(clientSecret)
 	return Application{
 		id:           id,
 		clientID:     clientID,
@@ -29,15 +46,28 @@ func (a Application) ToSecret(name, ns string) kcorev1.Secret {
 			Name:      name,
 			Namespace: ns,
 		},
-		Data: map[string][]byte{
-			"client_id":     []byte(a.clientID),
-			"client_secret": []byte(a.clientSecret),
-			"token_url":     []byte(a.tokenURL),
-			"certs_url":     []byte(a.certsURL),
-		},
+		Data: a.Credentials(),
+	}
+}
+
+// Credentials returns the credentials of the application keyed by the data keys of the application secret.
+func (a Application) Credentials() map[string][]byte {
+	return map[string][]byte{
+		SecretKeyClientID:     []byte(a.clientID),
+		SecretKeyClientSecret: []byte(a.clientSecret),
+		SecretKeyTokenURL:     []byte(a.tokenURL),
+		SecretKeyCertsURL:     []byte(a.certsURL),
 	}
 }
 
 func (a Application) GetID() string {
 	return a.id
 }
+
+// ManagedApplication is an application in IAS that is marked as managed by the manager.
+type ManagedApplication struct {
+	ID   string
+	Name string
+	// Created is the time when the application was created, or nil if IAS didn't return it.
+	Created *time.Time
+}
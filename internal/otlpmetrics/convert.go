@@ -0,0 +1,86 @@
+package otlpmetrics
+
+import (
+	"math"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newScopeMetrics converts the gathered metric families to the metrics of the OpenTelemetry SDK. Counters are converted to cumulative
+// monotonic sums, gauges and untyped metrics to gauges, and histograms to cumulative histograms with explicit bounds. Summaries are
+// skipped, since the OTLP exporter of the SDK can't export them.
+func newScopeMetrics(families []*dto.MetricFamily, start, now time.Time) metricdata.ScopeMetrics {
+	metrics := make([]metricdata.Metrics, 0, len(families))
+	for _, family := range families {
+		metric := metricdata.Metrics{Name: family.GetName(), Description: family.GetHelp()}
+		switch family.GetType() {
+		case dto.MetricType_COUNTER:
+			sum := metricdata.Sum[float64]{Temporality: metricdata.CumulativeTemporality, IsMonotonic: true}
+			for _, m := range family.GetMetric() {
+				sum.DataPoints = append(sum.DataPoints, metricdata.DataPoint[float64]{
+					Attributes: attributesOf(m), StartTime: start, Time: now, Value: m.GetCounter().GetValue(),
+				})
+			}
+			metric.Data = sum
+		case dto.MetricType_GAUGE, dto.MetricType_UNTYPED:
+			gauge := metricdata.Gauge[float64]{}
+			for _, m := range family.GetMetric() {
+				value := m.GetGauge().GetValue()
+				if family.GetType() == dto.MetricType_UNTYPED {
+					value = m.GetUntyped().GetValue()
+				}
+				gauge.DataPoints = append(gauge.DataPoints, metricdata.DataPoint[float64]{
+					Attributes: attributesOf(m), Time: now, Value: value,
+				})
+			}
+			metric.Data = gauge
+		case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+			histogram := metricdata.Histogram[float64]{Temporality: metricdata.CumulativeTemporality}
+			for _, m := range family.GetMetric() {
+				histogram.DataPoints = append(histogram.DataPoints, histogramDataPointOf(m, start, now))
+			}
+			metric.Data = histogram
+		default:
+			// Summaries aren't supported by the OTLP exporter of the OpenTelemetry SDK and aren't pushed.
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+	return metricdata.ScopeMetrics{Scope: instrumentation.Scope{Name: scopeName}, Metrics: metrics}
+}
+
+// histogramDataPointOf converts the cumulative buckets of a Prometheus histogram to the per-bucket counts of OpenTelemetry. The bucket with
+// the upper bound +Inf is implicit in OpenTelemetry and counts the remaining observations.
+func histogramDataPointOf(m *dto.Metric, start, now time.Time) metricdata.HistogramDataPoint[float64] {
+	h := m.GetHistogram()
+	point := metricdata.HistogramDataPoint[float64]{
+		Attributes: attributesOf(m),
+		StartTime:  start,
+		Time:       now,
+		Count:      h.GetSampleCount(),
+		Sum:        h.GetSampleSum(),
+	}
+	var previous uint64
+	for _, bucket := range h.GetBucket() {
+		if math.IsInf(bucket.GetUpperBound(), 1) {
+			continue
+		}
+		point.Bounds = append(point.Bounds, bucket.GetUpperBound())
+		point.BucketCounts = append(point.BucketCounts, bucket.GetCumulativeCount()-previous)
+		previous = bucket.GetCumulativeCount()
+	}
+	point.BucketCounts = append(point.BucketCounts, h.GetSampleCount()-previous)
+	return point
+}
+
+func attributesOf(m *dto.Metric) attribute.Set {
+	attributes := make([]attribute.KeyValue, 0, len(m.GetLabel()))
+	for _, label := range m.GetLabel() {
+		attributes = append(attributes, attribute.String(label.GetName(), label.GetValue()))
+	}
+	return attribute.NewSet(attributes...)
+}
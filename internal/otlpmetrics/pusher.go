@@ -0,0 +1,177 @@
+package otlpmetrics
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultInterval is the default interval in which the metrics are pushed.
+	DefaultInterval = time.Minute
+	// metricsPath is the path of the OTLP/HTTP receiver that the metrics are sent to.
+	metricsPath = "/v1/metrics"
+	// scopeName is the name of the instrumentation scope of the pushed metrics.
+	scopeName = "github.com/kyma-project/eventing-auth-manager"
+)
+
+// Config defines where and how often the metrics are pushed.
+type Config struct {
+	// Endpoint is the base URL of the OTLP/HTTP receiver that the metrics are pushed to, e.g. 'http://otel-collector.kyma-system:4318'.
+	Endpoint string
+	// Headers are sent with each push, e.g. to authenticate at the receiver.
+	Headers map[string]string
+	// Interval is the interval in which the metrics are pushed. Defaults to DefaultInterval.
+	Interval time.Duration
+	// ServiceName and ServiceVersion are the attributes of the resource of the pushed metrics.
+	ServiceName    string
+	ServiceVersion string
+}
+
+// Pusher pushes the metrics of a Prometheus gatherer with the periodic reader and the OTLP/HTTP exporter of the OpenTelemetry SDK to a
+// receiver, e.g. an OpenTelemetry collector, for landscapes in which the metrics endpoint of the manager isn't scraped. The pusher must be
+// added to the manager to start pushing.
+type Pusher struct {
+	config   Config
+	exporter *loggingExporter
+	provider *sdkmetric.MeterProvider
+}
+
+// NewPusher returns a pusher that pushes the metrics of the gatherer to the endpoint of the config.
+func NewPusher(config Config, gatherer prometheus.Gatherer) (*Pusher, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("endpoint of the OTLP receiver must be set")
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	u, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the endpoint of the OTLP receiver")
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("endpoint %s of the OTLP receiver must be a URL with scheme and host", config.Endpoint)
+	}
+	opts := []otlpmetrichttp.Option{
+		otlpmetrichttp.WithEndpoint(u.Host),
+		otlpmetrichttp.WithURLPath(strings.TrimSuffix(u.Path, "/") + metricsPath),
+		otlpmetrichttp.WithHeaders(config.Headers),
+		otlpmetrichttp.WithTimeout(config.Interval),
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlpmetrichttp.WithInsecure())
+	}
+	otlpExporter, err := otlpmetrichttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the OTLP exporter")
+	}
+
+	exporter := &loggingExporter{Exporter: otlpExporter, logger: logr.Discard()}
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(config.Interval),
+		sdkmetric.WithTimeout(config.Interval),
+		sdkmetric.WithProducer(&producer{gatherer: gatherer, start: time.Now(), now: time.Now}),
+	)
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(resource.NewSchemaless(
+		attribute.String("service.name", config.ServiceName),
+		attribute.String("service.version", config.ServiceVersion),
+	)))
+	return &Pusher{config: config, exporter: exporter, provider: provider}, nil
+}
+
+// NeedLeaderElection returns false, since each replica of the manager pushes its own metrics.
+func (p *Pusher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start logs the failed pushes of the periodic reader until the context is done, and pushes the metrics a last time afterward.
+func (p *Pusher) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("otlp-metrics")
+	p.exporter.setLogger(logger.WithValues("endpoint", p.config.Endpoint))
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), p.config.Interval)
+	defer cancel()
+	if err := p.provider.Shutdown(shutdownCtx); err != nil {
+		logger.Error(err, "Failed to push the metrics before shutdown")
+	}
+	return nil
+}
+
+// Push gathers the metrics and pushes them to the receiver right away.
+func (p *Pusher) Push(ctx context.Context) error {
+	return errors.Wrap(p.provider.ForceFlush(ctx), "failed to push metrics")
+}
+
+// producer produces the metrics of the Prometheus gatherer for the periodic reader.
+type producer struct {
+	gatherer prometheus.Gatherer
+	start    time.Time
+	now      func() time.Time
+}
+
+func (p *producer) Produce(context.Context) ([]metricdata.ScopeMetrics, error) {
+	families, err := p.gatherer.Gather()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to gather metrics")
+	}
+	return []metricdata.ScopeMetrics{newScopeMetrics(families, p.start, p.now())}, nil
+}
+
+// loggingExporter logs the failed exports of the periodic reader, which only reports them to the global error handler of OpenTelemetry.
+type loggingExporter struct {
+	sdkmetric.Exporter
+
+	mu     sync.Mutex
+	logger logr.Logger
+}
+
+func (e *loggingExporter) setLogger(logger logr.Logger) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.logger = logger
+}
+
+func (e *loggingExporter) Export(ctx context.Context, metrics *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, metrics)
+	if err != nil {
+		e.mu.Lock()
+		logger := e.logger
+		e.mu.Unlock()
+		logger.Error(err, "Failed to push the metrics")
+	}
+	return err
+}
+
+// ParseHeaders parses headers in the format of the environment variable OTEL_EXPORTER_OTLP_HEADERS, e.g. 'key1=value1,key2=value2'.
+func ParseHeaders(headers string) (map[string]string, error) {
+	parsed := map[string]string{}
+	for _, header := range strings.Split(headers, ",") {
+		if strings.TrimSpace(header) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(header, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, errors.Errorf("header %q must have the format key=value", header)
+		}
+		// The values are URL encoded, like in the environment variable.
+		decoded, err := url.QueryUnescape(strings.TrimSpace(value))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the value of header %q", key)
+		}
+		parsed[key] = decoded
+	}
+	return parsed, nil
+}
@@ -0,0 +1,191 @@
+package otlpmetrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestPusher_Push(t *testing.T) {
+	// given
+	var path, contentType, authorization string
+	var body colmetricpb.ExportMetricsServiceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		path = req.URL.Path
+		contentType = req.Header.Get("Content-Type")
+		authorization = req.Header.Get("Authorization")
+		b, err := io.ReadAll(req.Body)
+		if err == nil {
+			err = proto.Unmarshal(b, &body)
+		}
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	counter := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "test_total", Help: "Test counter."}, []string{"result"})
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "test_gauge", Help: "Test gauge."})
+	histogram := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "test_seconds", Help: "Test histogram.", Buckets: []float64{1, 5}})
+	summary := prometheus.NewSummary(prometheus.SummaryOpts{Name: "test_summary_seconds", Help: "Test summary."})
+	registry.MustRegister(counter, gauge, histogram, summary)
+	counter.WithLabelValues("success").Add(3)
+	gauge.Set(0.5)
+	histogram.Observe(0.5)
+	histogram.Observe(2)
+	histogram.Observe(10)
+	summary.Observe(1)
+
+	pusher, err := NewPusher(Config{
+		Endpoint:    server.URL,
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+		ServiceName: "eventing-auth-manager",
+	}, registry)
+	require.NoError(t, err)
+
+	// when
+	err = pusher.Push(context.Background())
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, metricsPath, path)
+	require.Equal(t, "application/x-protobuf", contentType)
+	require.Equal(t, "Bearer token", authorization)
+	require.Len(t, body.GetResourceMetrics(), 1)
+	require.Contains(t, body.GetResourceMetrics()[0].GetResource().GetAttributes(), stringKeyValue("service.name", "eventing-auth-manager"))
+	scopeMetrics := body.GetResourceMetrics()[0].GetScopeMetrics()
+	require.Len(t, scopeMetrics, 1)
+	require.Equal(t, scopeName, scopeMetrics[0].GetScope().GetName())
+	metrics := map[string]*metricpb.Metric{}
+	for _, m := range scopeMetrics[0].GetMetrics() {
+		metrics[m.GetName()] = m
+	}
+	// The summary is skipped.
+	require.Len(t, metrics, 3)
+
+	sum := metrics["test_total"].GetSum()
+	require.NotNil(t, sum)
+	require.True(t, sum.GetIsMonotonic())
+	require.Equal(t, metricpb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE, sum.GetAggregationTemporality())
+	require.Equal(t, []*commonpb.KeyValue{stringKeyValue("result", "success")}, sum.GetDataPoints()[0].GetAttributes())
+	require.InDelta(t, 3, sum.GetDataPoints()[0].GetAsDouble(), 0)
+
+	gaugePoints := metrics["test_gauge"].GetGauge().GetDataPoints()
+	require.InDelta(t, 0.5, gaugePoints[0].GetAsDouble(), 0)
+
+	histogramPoint := metrics["test_seconds"].GetHistogram().GetDataPoints()[0]
+	require.Equal(t, uint64(3), histogramPoint.GetCount())
+	require.Equal(t, []float64{1, 5}, histogramPoint.GetExplicitBounds())
+	require.Equal(t, []uint64{1, 1, 1}, histogramPoint.GetBucketCounts())
+}
+
+func TestPusher_Push_Error(t *testing.T) {
+	// given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	pusher, err := NewPusher(Config{Endpoint: server.URL}, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	// when
+	err = pusher.Push(context.Background())
+
+	// then
+	require.ErrorContains(t, err, "401")
+}
+
+func TestNewPusher(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenConfig Config
+		wantErr     string
+	}{
+		{
+			name:        "should return a pusher",
+			givenConfig: Config{Endpoint: "http://otel-collector.kyma-system:4318"},
+		},
+		{
+			name:        "should fail without endpoint",
+			givenConfig: Config{},
+			wantErr:     "endpoint of the OTLP receiver must be set",
+		},
+		{
+			name:        "should fail for an endpoint without scheme",
+			givenConfig: Config{Endpoint: "otel-collector:4318"},
+			wantErr:     "endpoint otel-collector:4318 of the OTLP receiver must be a URL with scheme and host",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			_, err := NewPusher(tt.givenConfig, prometheus.NewRegistry())
+
+			// then
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// stringKeyValue returns the OTLP attribute with the given string value.
+func stringKeyValue(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: key, Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}}}
+}
+
+func TestParseHeaders(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "should return no headers for an empty string",
+			headers: "",
+			want:    map[string]string{},
+		},
+		{
+			name:    "should parse multiple headers and decode their values",
+			headers: "Authorization=Bearer%20token, X-Tenant = kcp",
+			want:    map[string]string{"Authorization": "Bearer token", "X-Tenant": "kcp"},
+		},
+		{
+			name:    "should fail for a header without value",
+			headers: "Authorization",
+			wantErr: true,
+		},
+		{
+			name:    "should fail for a header without key",
+			headers: "=value",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			headers, err := ParseHeaders(tt.headers)
+
+			// then
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, headers)
+		})
+	}
+}
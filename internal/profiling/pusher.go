@@ -0,0 +1,219 @@
+// Package profiling pushes the CPU, heap, and goroutine profiles of the manager continuously to a receiver that is compatible with the
+// ingest API of Pyroscope, so that the memory growth of long-running managers can be diagnosed in production.
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// DefaultInterval is the default interval in which the profiles are pushed, which is also the duration of each CPU profile.
+	DefaultInterval = 15 * time.Second
+	// ingestPath is the path of the ingest API of the receiver that the profiles are sent to.
+	ingestPath = "/ingest"
+	// spyName identifies the profiles as profiles of the Go runtime.
+	spyName = "gospy"
+	// cpuSampleRate is the rate in Hz in which the Go runtime samples the CPU profile.
+	cpuSampleRate = 100
+)
+
+// Types of the pushed profiles, which are appended to the application name.
+const (
+	profileCPU       = "cpu"
+	profileHeap      = "heap"
+	profileGoroutine = "goroutine"
+)
+
+// Config defines where and how often the profiles are pushed.
+type Config struct {
+	// Endpoint is the base URL of the receiver that the profiles are pushed to, e.g. 'http://pyroscope.kyma-system:4040'.
+	Endpoint string
+	// Headers are sent with each push, e.g. to authenticate at the receiver.
+	Headers map[string]string
+	// Interval is the interval in which the profiles are pushed. Defaults to DefaultInterval.
+	Interval time.Duration
+	// ApplicationName is the name of the application that the profiles are pushed for.
+	ApplicationName string
+	// Tags are the labels of the pushed profiles, e.g. the version and the instance of the manager.
+	Tags map[string]string
+}
+
+// Pusher records the profiles of the manager and pushes them in the pprof format to the receiver. The CPU profile is recorded
+// continuously and pushed in the interval, the heap and goroutine profiles are snapshots at the time of the push. The pusher must be added
+// to the manager to start pushing.
+type Pusher struct {
+	config     Config
+	httpClient *http.Client
+	url        string
+	// cpuProfile is the CPU profile that is recorded since cpuStart, or nil if no CPU profile is recorded.
+	cpuProfile *bytes.Buffer
+	cpuStart   time.Time
+}
+
+// NewPusher returns a pusher that pushes the profiles to the endpoint of the config.
+func NewPusher(config Config, httpClient *http.Client) (*Pusher, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("endpoint of the profiling receiver must be set")
+	}
+	if config.ApplicationName == "" {
+		return nil, errors.New("application name of the profiles must be set")
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Pusher{
+		config:     config,
+		httpClient: httpClient,
+		url:        strings.TrimSuffix(config.Endpoint, "/") + ingestPath,
+	}, nil
+}
+
+// NeedLeaderElection returns false, since each replica of the manager pushes its own profiles.
+func (p *Pusher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start records the CPU profile and pushes the profiles in the interval until the context is done, and pushes them a last time afterward.
+func (p *Pusher) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("profiling")
+	if err := p.startCPUProfile(); err != nil {
+		// The CPU profile can't be recorded while another one is recorded, e.g. by a request to the pprof endpoint.
+		logger.Error(err, "Failed to start the CPU profile, only the heap and goroutine profiles are pushed")
+	}
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			pushCtx, cancel := context.WithTimeout(context.Background(), p.config.Interval)
+			defer cancel()
+			if err := p.push(pushCtx, false); err != nil {
+				logger.Error(err, "Failed to push the profiles before shutdown")
+			}
+			return nil
+		case <-ticker.C:
+			if err := p.push(ctx, true); err != nil {
+				logger.Error(err, "Failed to push the profiles", "endpoint", p.url)
+			}
+		}
+	}
+}
+
+// Push pushes the heap and goroutine profiles, and the CPU profile if it is recorded, to the receiver.
+func (p *Pusher) Push(ctx context.Context) error {
+	return p.push(ctx, p.cpuProfile != nil)
+}
+
+// push pushes the profiles. If restartCPUProfile is true, the recording of the next CPU profile is started right after the recorded one
+// is stopped, so that no CPU samples are lost between the pushes, or it is started again if it couldn't be started before.
+func (p *Pusher) push(ctx context.Context, restartCPUProfile bool) error {
+	now := time.Now()
+	cpuProfile, cpuStart := p.cpuProfile, p.cpuStart
+	if cpuProfile != nil {
+		pprof.StopCPUProfile()
+		p.cpuProfile = nil
+	}
+	var restartErr error
+	if restartCPUProfile {
+		restartErr = p.startCPUProfile()
+	}
+	if cpuProfile != nil {
+		if err := p.upload(ctx, profileCPU, cpuStart, now, cpuProfile.Bytes()); err != nil {
+			return err
+		}
+	}
+	for _, profileType := range []string{profileHeap, profileGoroutine} {
+		var profile bytes.Buffer
+		if err := pprof.Lookup(profileType).WriteTo(&profile, 0); err != nil {
+			return errors.Wrapf(err, "failed to write the %s profile", profileType)
+		}
+		if err := p.upload(ctx, profileType, now, now, profile.Bytes()); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(restartErr, "failed to restart the CPU profile")
+}
+
+func (p *Pusher) startCPUProfile() error {
+	profile := &bytes.Buffer{}
+	if err := pprof.StartCPUProfile(profile); err != nil {
+		return err
+	}
+	p.cpuProfile = profile
+	p.cpuStart = time.Now()
+	return nil
+}
+
+// upload posts the profile as multipart form to the ingest API of the receiver.
+func (p *Pusher) upload(ctx context.Context, profileType string, from, until time.Time, profile []byte) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("profile", "profile.pprof")
+	if err != nil {
+		return errors.Wrap(err, "failed to encode profile")
+	}
+	if _, err := part.Write(profile); err != nil {
+		return errors.Wrap(err, "failed to encode profile")
+	}
+	if err := form.Close(); err != nil {
+		return errors.Wrap(err, "failed to encode profile")
+	}
+
+	query := url.Values{}
+	query.Set("name", p.name(profileType))
+	query.Set("from", strconv.FormatInt(from.Unix(), 10))
+	query.Set("until", strconv.FormatInt(until.Unix(), 10))
+	query.Set("spyName", spyName)
+	query.Set("format", "pprof")
+	if profileType == profileCPU {
+		query.Set("sampleRate", strconv.Itoa(cpuSampleRate))
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+"?"+query.Encode(), &body)
+	if err != nil {
+		return errors.Wrap(err, "failed to create push request")
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	for key, value := range p.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to push %s profile", profileType)
+	}
+	defer res.Body.Close()
+	_, _ = io.Copy(io.Discard, res.Body)
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return errors.Errorf("failed to push %s profile: %s", profileType, res.Status)
+	}
+	return nil
+}
+
+// name returns the name of the profile in the format of the ingest API, e.g. 'eventing-auth-manager.cpu{instance=pod-1,version=1.0.0}'.
+func (p *Pusher) name(profileType string) string {
+	keys := make([]string, 0, len(p.config.Tags))
+	for key, value := range p.config.Tags {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	tags := make([]string, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, key+"="+p.config.Tags[key])
+	}
+	return p.config.ApplicationName + "." + profileType + "{" + strings.Join(tags, ",") + "}"
+}
@@ -0,0 +1,143 @@
+package profiling
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type ingestedProfile struct {
+	name          string
+	spyName       string
+	format        string
+	sampleRate    string
+	authorization string
+	size          int
+}
+
+func newIngestServer(t *testing.T, statusCode int) (*httptest.Server, func() []ingestedProfile) {
+	t.Helper()
+	var mu sync.Mutex
+	var profiles []ingestedProfile
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != ingestPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		file, _, err := req.FormFile("profile")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := io.ReadAll(file)
+		mu.Lock()
+		profiles = append(profiles, ingestedProfile{
+			name:          req.URL.Query().Get("name"),
+			spyName:       req.URL.Query().Get("spyName"),
+			format:        req.URL.Query().Get("format"),
+			sampleRate:    req.URL.Query().Get("sampleRate"),
+			authorization: req.Header.Get("Authorization"),
+			size:          len(content),
+		})
+		mu.Unlock()
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []ingestedProfile {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]ingestedProfile(nil), profiles...)
+	}
+}
+
+func TestPusher_Push(t *testing.T) {
+	// given
+	server, ingested := newIngestServer(t, http.StatusOK)
+	pusher, err := NewPusher(Config{
+		Endpoint:        server.URL,
+		Headers:         map[string]string{"Authorization": "Bearer token"},
+		ApplicationName: "eventing-auth-manager",
+		Tags:            map[string]string{"version": "1.0.0", "instance": "pod-1", "landscape": ""},
+	}, server.Client())
+	require.NoError(t, err)
+	require.NoError(t, pusher.startCPUProfile())
+
+	// when
+	err = pusher.Push(context.Background())
+
+	// then
+	require.NoError(t, err)
+	profiles := ingested()
+	require.Len(t, profiles, 3)
+	require.Equal(t, "eventing-auth-manager.cpu{instance=pod-1,version=1.0.0}", profiles[0].name)
+	require.Equal(t, "100", profiles[0].sampleRate)
+	require.Equal(t, "eventing-auth-manager.heap{instance=pod-1,version=1.0.0}", profiles[1].name)
+	require.Equal(t, "eventing-auth-manager.goroutine{instance=pod-1,version=1.0.0}", profiles[2].name)
+	for _, profile := range profiles {
+		require.Equal(t, spyName, profile.spyName)
+		require.Equal(t, "pprof", profile.format)
+		require.Equal(t, "Bearer token", profile.authorization)
+		require.Positive(t, profile.size)
+	}
+
+	// The CPU profile is recorded again for the next push.
+	require.NotNil(t, pusher.cpuProfile)
+	require.NoError(t, pusher.push(context.Background(), false))
+	require.Nil(t, pusher.cpuProfile)
+}
+
+func TestPusher_Push_Error(t *testing.T) {
+	// given
+	server, _ := newIngestServer(t, http.StatusUnauthorized)
+	pusher, err := NewPusher(Config{Endpoint: server.URL, ApplicationName: "eventing-auth-manager"}, server.Client())
+	require.NoError(t, err)
+
+	// when
+	err = pusher.Push(context.Background())
+
+	// then
+	require.ErrorContains(t, err, "401")
+}
+
+func TestNewPusher(t *testing.T) {
+	tests := []struct {
+		name        string
+		givenConfig Config
+		wantErr     bool
+	}{
+		{
+			name:        "should default the interval",
+			givenConfig: Config{Endpoint: "http://pyroscope:4040", ApplicationName: "eventing-auth-manager"},
+		},
+		{
+			name:        "should fail without endpoint",
+			givenConfig: Config{ApplicationName: "eventing-auth-manager"},
+			wantErr:     true,
+		},
+		{
+			name:        "should fail without application name",
+			givenConfig: Config{Endpoint: "http://pyroscope:4040"},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			pusher, err := NewPusher(tt.givenConfig, http.DefaultClient)
+
+			// then
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, DefaultInterval, pusher.config.Interval)
+			require.Equal(t, "http://pyroscope:4040/ingest", pusher.url)
+		})
+	}
+}
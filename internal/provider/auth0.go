@@ -0,0 +1,307 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/clientcredentials"
+	kcorev1 "k8s.io/api/core/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	Auth0Name                              = "auth0"
+	DefaultAuth0CredentialsSecretNamespace = "kcp-system"
+	DefaultAuth0CredentialsSecretName      = "eventing-auth-auth0-creds" //nolint:gosec // This is a name, not a credential.
+	auth0DomainKey                         = "domain"
+	auth0ClientIDKey                       = "client_id"
+	auth0ClientSecretKey                   = "client_secret"
+	auth0ClientsPageSize                   = 100
+)
+
+// Auth0Config defines how the applications are managed in Auth0.
+type Auth0Config struct {
+	// CredentialsSecretNamespace is the namespace of the secret that contains the domain of the tenant and the client ID and client secret
+	// of the machine-to-machine application that the manager uses to call the Management API.
+	CredentialsSecretNamespace string
+	// CredentialsSecretName is the name of the secret that contains the credentials of the manager.
+	CredentialsSecretName string
+	// Audience is the identifier of the API that the applications of the SKR clusters are granted access to.
+	Audience string
+	// Scopes are the scopes of the API that are granted to the applications.
+	Scopes []string
+}
+
+// auth0Credentials contains the credentials of the manager in Auth0.
+type auth0Credentials struct {
+	domain       string
+	clientID     string
+	clientSecret string
+}
+
+type auth0Factory struct {
+	httpClient *http.Client
+	k8sClient  kpkgclient.Client
+	config     Auth0Config
+
+	mu          sync.Mutex
+	credentials auth0Credentials
+	// providers contains the providers by their audiences and scopes.
+	providers map[string]*auth0Provider
+}
+
+// NewAuth0Factory returns the factory of the Auth0 provider. The credentials of the manager are read from the configured secret on every
+// call, and the providers are recreated if they changed. The audience and the scopes can be overridden by spec.auth0 of the EventingAuth CR.
+func NewAuth0Factory(httpClient *http.Client, k8sClient kpkgclient.Client, config Auth0Config) Factory {
+	return &auth0Factory{httpClient: httpClient, k8sClient: k8sClient, config: config}
+}
+
+func (f *auth0Factory) Get(ctx context.Context, spec eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	config := f.config
+	if spec.Auth0 != nil {
+		if spec.Auth0.Audience != "" {
+			config.Audience = spec.Auth0.Audience
+		}
+		if spec.Auth0.Scopes != nil {
+			config.Scopes = spec.Auth0.Scopes
+		}
+	}
+	if config.Audience == "" {
+		return nil, errors.New("the audience of the Auth0 API must be configured")
+	}
+	var secret kcorev1.Secret
+	key := kpkgclient.ObjectKey{Namespace: f.config.CredentialsSecretNamespace, Name: f.config.CredentialsSecretName}
+	if err := f.k8sClient.Get(ctx, key, &secret); err != nil {
+		return nil, err
+	}
+	credentials := auth0Credentials{
+		domain:       string(secret.Data[auth0DomainKey]),
+		clientID:     string(secret.Data[auth0ClientIDKey]),
+		clientSecret: string(secret.Data[auth0ClientSecretKey]),
+	}
+	if credentials.domain == "" || credentials.clientID == "" || credentials.clientSecret == "" {
+		return nil, errors.Errorf("keys %s, %s, and %s must be set in the Auth0 secret", auth0DomainKey, auth0ClientIDKey,
+			auth0ClientSecretKey)
+	}
+	redact.Secret(credentials.clientSecret)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.providers == nil || f.credentials != credentials {
+		f.credentials = credentials
+		f.providers = map[string]*auth0Provider{}
+	}
+	// The audience is a URI and the scopes don't contain spaces, so the key is unique.
+	providerKey := config.Audience + " " + strings.Join(config.Scopes, " ")
+	p, ok := f.providers[providerKey]
+	if !ok {
+		p = newAuth0Provider(f.httpClient, credentials, config)
+		f.providers[providerKey] = p
+	}
+	return p, nil
+}
+
+// auth0Provider creates machine-to-machine applications in Auth0 with the Management API.
+type auth0Provider struct {
+	httpClient *http.Client
+	url        string
+	audience   string
+	scopes     []string
+}
+
+func newAuth0Provider(httpClient *http.Client, credentials auth0Credentials, config Auth0Config) *auth0Provider {
+	tenantURL := "https://" + strings.TrimSuffix(credentials.domain, "/")
+	oauthConfig := clientcredentials.Config{
+		ClientID:       credentials.clientID,
+		ClientSecret:   credentials.clientSecret,
+		TokenURL:       tenantURL + "/oauth/token",
+		EndpointParams: url.Values{"audience": []string{tenantURL + "/api/v2/"}},
+	}
+	scopes := config.Scopes
+	if scopes == nil {
+		scopes = []string{}
+	}
+	return &auth0Provider{
+		httpClient: newOAuthHTTPClient(httpClient, oauthConfig),
+		url:        tenantURL,
+		audience:   config.Audience,
+		scopes:     scopes,
+	}
+}
+
+// auth0Client is a client of the Management API.
+type auth0Client struct {
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	Name         string `json:"name"`
+	Description  string `json:"description,omitempty"`
+}
+
+// CreateApplication creates a machine-to-machine application with the client credentials grant and grants it access to the configured API.
+// An existing application with the same name is deleted before, if it is managed by the manager.
+func (p *auth0Provider) CreateApplication(ctx context.Context, name string) (eamias.Application, error) {
+	existingClient, err := p.getClientByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	if existingClient != nil {
+		if err := p.deleteClient(ctx, existingClient.ClientID); err != nil {
+			return eamias.Application{}, errors.Wrap(err, "failed to delete existing application before creation")
+		}
+	}
+
+	var client auth0Client
+	if _, err := p.call(ctx, http.MethodPost, "/api/v2/clients", map[string]interface{}{
+		"name":                       name,
+		"description":                managedByMarker,
+		"app_type":                   "non_interactive",
+		"grant_types":                []string{"client_credentials"},
+		"token_endpoint_auth_method": "client_secret_post",
+	}, &client); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to create application")
+	}
+	if _, err := p.call(ctx, http.MethodPost, "/api/v2/client-grants", map[string]interface{}{
+		"client_id": client.ClientID,
+		"audience":  p.audience,
+		"scope":     p.scopes,
+	}, nil); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to create client grant")
+	}
+	return p.newApplication(client), nil
+}
+
+func (p *auth0Provider) UpdateApplication(ctx context.Context, name string) error {
+	client, err := p.getExistingClientByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = p.call(ctx, http.MethodPatch, "/api/v2/clients/"+url.PathEscape(client.ClientID), map[string]string{"name": name}, nil)
+	return errors.Wrap(err, "failed to update application")
+}
+
+// DeleteApplication deletes the application, whose client grants are deleted by Auth0.
+func (p *auth0Provider) DeleteApplication(ctx context.Context, name string) error {
+	client, err := p.getClientByName(ctx, name)
+	if err != nil || client == nil {
+		return err
+	}
+	return errors.Wrap(p.deleteClient(ctx, client.ClientID), "failed to delete application")
+}
+
+// Rotate rotates the client secret, which revokes the previous client secret immediately.
+func (p *auth0Provider) Rotate(ctx context.Context, name string) (eamias.Application, error) {
+	client, err := p.getExistingClientByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	var rotated auth0Client
+	if _, err := p.call(ctx, http.MethodPost, "/api/v2/clients/"+url.PathEscape(client.ClientID)+"/rotate-secret", nil,
+		&rotated); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to rotate client secret")
+	}
+	return p.newApplication(rotated), nil
+}
+
+func (p *auth0Provider) Discovery(_ context.Context) (Discovery, error) {
+	return Discovery{TokenURL: p.url + "/oauth/token", JWKSURI: p.url + "/.well-known/jwks.json"}, nil
+}
+
+// newApplication returns the application of the client, whose client ID is also the ID of the application.
+func (p *auth0Provider) newApplication(client auth0Client) eamias.Application {
+	discovery, _ := p.Discovery(context.Background())
+	return eamias.NewApplication(client.ClientID, client.ClientID, client.ClientSecret, discovery.TokenURL, discovery.JWKSURI)
+}
+
+// getClientByName returns the machine-to-machine application with the given name, or nil if it doesn't exist. It fails if the application
+// isn't managed by the manager. The Management API can't filter the applications by name, so all pages are fetched.
+func (p *auth0Provider) getClientByName(ctx context.Context, name string) (*auth0Client, error) {
+	var found *auth0Client
+	for page := 0; ; page++ {
+		path := "/api/v2/clients?app_type=non_interactive&fields=client_id%2Cname%2Cdescription&include_fields=true" +
+			"&page=" + strconv.Itoa(page) + "&per_page=" + strconv.Itoa(auth0ClientsPageSize)
+		var clients []auth0Client
+		if _, err := p.call(ctx, http.MethodGet, path, nil, &clients); err != nil {
+			return nil, errors.Wrap(err, "failed to fetch existing applications")
+		}
+		for i := range clients {
+			if clients[i].Name != name {
+				continue
+			}
+			if found != nil {
+				return nil, errors.Errorf("found multiple applications with the same name %s", name)
+			}
+			found = &clients[i]
+		}
+		if len(clients) < auth0ClientsPageSize {
+			if found != nil && found.Description != managedByMarker {
+				return nil, newUnmanagedApplicationError(name)
+			}
+			return found, nil
+		}
+	}
+}
+
+// getExistingClientByName returns the application with the given name, and fails if the application doesn't exist.
+func (p *auth0Provider) getExistingClientByName(ctx context.Context, name string) (*auth0Client, error) {
+	client, err := p.getClientByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, errors.Errorf("application %s does not exist", name)
+	}
+	return client, nil
+}
+
+func (p *auth0Provider) deleteClient(ctx context.Context, clientID string) error {
+	status, err := p.call(ctx, http.MethodDelete, "/api/v2/clients/"+url.PathEscape(clientID), nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// call sends a request to the Management API of Auth0 and decodes the response into the output if it isn't nil. It returns the status code
+// of the response.
+func (p *auth0Provider) call(ctx context.Context, method, path string, input, output interface{}) (int, error) {
+	var body []byte
+	if input != nil {
+		var err error
+		if body, err = json.Marshal(input); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.url+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, errors.Errorf("unexpected status code %d from Auth0: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
+	}
+	if output != nil && len(resBody) > 0 {
+		return res.StatusCode, errors.Wrap(json.Unmarshal(resBody, output), "failed to decode response of Auth0")
+	}
+	return res.StatusCode, nil
+}
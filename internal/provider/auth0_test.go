@@ -0,0 +1,244 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest/fake"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type auth0Request struct {
+	method string
+	url    string
+	input  map[string]interface{}
+}
+
+type auth0Response struct {
+	statusCode int
+	body       string
+}
+
+// newAuth0Mock returns an HTTP client that issues tokens of the Management API for the manager and records the requests to the
+// Management API. The requests are answered with the given responses in order, all following requests succeed without body.
+func newAuth0Mock(t *testing.T, requests *[]auth0Request, responses ...auth0Response) *http.Client {
+	t.Helper()
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == "https://tenant.eu.auth0.com/oauth/token" {
+			require.NoError(t, req.ParseForm())
+			require.Equal(t, "https://tenant.eu.auth0.com/api/v2/", req.PostForm.Get("audience"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`))),
+			}, nil
+		}
+		require.Equal(t, "Bearer access-token", req.Header.Get("Authorization"))
+
+		r := auth0Request{method: req.Method, url: req.URL.String()}
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		if len(b) > 0 {
+			require.NoError(t, json.Unmarshal(b, &r.input))
+		}
+		*requests = append(*requests, r)
+
+		res := auth0Response{statusCode: http.StatusNoContent}
+		if len(*requests) <= len(responses) {
+			res = responses[len(*requests)-1]
+		}
+		return &http.Response{StatusCode: res.statusCode, Body: io.NopCloser(bytes.NewReader([]byte(res.body)))}, nil
+	})
+}
+
+func newTestAuth0Provider(t *testing.T, httpClient *http.Client) Provider {
+	t.Helper()
+	k8sClient := kfake.NewClientBuilder().WithObjects(&kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Name: DefaultAuth0CredentialsSecretName, Namespace: DefaultAuth0CredentialsSecretNamespace},
+		Data: map[string][]byte{
+			"domain":        []byte("tenant.eu.auth0.com"),
+			"client_id":     []byte("manager-client-id"),
+			"client_secret": []byte("manager-client-secret"),
+		},
+	}).Build()
+	p, err := NewAuth0Factory(httpClient, k8sClient, Auth0Config{
+		CredentialsSecretNamespace: DefaultAuth0CredentialsSecretNamespace,
+		CredentialsSecretName:      DefaultAuth0CredentialsSecretName,
+		Audience:                   "https://eventing.example.com",
+		Scopes:                     []string{"publish:events"},
+	}).Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+	return p
+}
+
+const (
+	auth0ClientsURL = "https://tenant.eu.auth0.com/api/v2/clients?app_type=non_interactive&fields=client_id%2Cname%2Cdescription" +
+		"&include_fields=true&page=0&per_page=100"
+	auth0Clients = `[{"client_id":"client-id","name":"runtime-id","description":"Managed by eventing-auth-manager"}]`
+)
+
+func Test_auth0Provider_CreateApplication(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []auth0Response
+		wantRequests []auth0Request
+		wantApp      eamias.Application
+		wantErr      string
+	}{
+		{
+			name: "should create the application with the client grant",
+			responses: []auth0Response{
+				{statusCode: http.StatusOK, body: `[{"client_id":"other-client-id","name":"runtime-id-2"}]`},
+				{statusCode: http.StatusCreated, body: `{"client_id":"client-id","client_secret":"client-secret","name":"runtime-id"}`},
+				{statusCode: http.StatusCreated, body: `{"id":"cgr_1"}`},
+			},
+			wantRequests: []auth0Request{
+				{method: http.MethodGet, url: auth0ClientsURL},
+				{method: http.MethodPost, url: "https://tenant.eu.auth0.com/api/v2/clients", input: map[string]interface{}{
+					"name":                       "runtime-id",
+					"description":                "Managed by eventing-auth-manager",
+					"app_type":                   "non_interactive",
+					"grant_types":                []interface{}{"client_credentials"},
+					"token_endpoint_auth_method": "client_secret_post",
+				}},
+				{method: http.MethodPost, url: "https://tenant.eu.auth0.com/api/v2/client-grants", input: map[string]interface{}{
+					"client_id": "client-id",
+					"audience":  "https://eventing.example.com",
+					"scope":     []interface{}{"publish:events"},
+				}},
+			},
+			wantApp: eamias.NewApplication("client-id", "client-id", "client-secret",
+				"https://tenant.eu.auth0.com/oauth/token", "https://tenant.eu.auth0.com/.well-known/jwks.json"),
+		},
+		{
+			name: "should fail if the client grant can't be created",
+			responses: []auth0Response{
+				{statusCode: http.StatusOK, body: `[{"client_id":"old-client-id","name":"runtime-id",` +
+					`"description":"Managed by eventing-auth-manager"}]`},
+				{statusCode: http.StatusNoContent},
+				{statusCode: http.StatusCreated, body: `{"client_id":"client-id","client_secret":"client-secret","name":"runtime-id"}`},
+				{statusCode: http.StatusConflict, body: `{"errorCode":"inexistent_resource_server"}`},
+			},
+			wantRequests: []auth0Request{
+				{method: http.MethodGet, url: auth0ClientsURL},
+				{method: http.MethodDelete, url: "https://tenant.eu.auth0.com/api/v2/clients/old-client-id"},
+				{method: http.MethodPost, url: "https://tenant.eu.auth0.com/api/v2/clients", input: map[string]interface{}{
+					"name":                       "runtime-id",
+					"description":                "Managed by eventing-auth-manager",
+					"app_type":                   "non_interactive",
+					"grant_types":                []interface{}{"client_credentials"},
+					"token_endpoint_auth_method": "client_secret_post",
+				}},
+				{method: http.MethodPost, url: "https://tenant.eu.auth0.com/api/v2/client-grants", input: map[string]interface{}{
+					"client_id": "client-id",
+					"audience":  "https://eventing.example.com",
+					"scope":     []interface{}{"publish:events"},
+				}},
+			},
+			wantErr: `failed to create client grant: unexpected status code 409 from Auth0: {"errorCode":"inexistent_resource_server"}`,
+		},
+		{
+			name: "should fail without deleting an existing application that isn't managed by the manager",
+			responses: []auth0Response{
+				{statusCode: http.StatusOK, body: `[{"client_id":"old-client-id","name":"runtime-id","description":"Payment service"}]`},
+			},
+			wantRequests: []auth0Request{{method: http.MethodGet, url: auth0ClientsURL}},
+			wantErr:      "application runtime-id exists, but isn't managed by eventing-auth-manager",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []auth0Request
+			p := newTestAuth0Provider(t, newAuth0Mock(t, &requests, tt.responses...))
+
+			// when
+			app, err := p.CreateApplication(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantApp, app)
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_auth0Provider_Rotate(t *testing.T) {
+	// given
+	var requests []auth0Request
+	p := newTestAuth0Provider(t, newAuth0Mock(t, &requests,
+		auth0Response{statusCode: http.StatusOK, body: auth0Clients},
+		auth0Response{statusCode: http.StatusOK, body: `{"client_id":"client-id","client_secret":"new-client-secret","name":"runtime-id"}`},
+	))
+
+	// when
+	app, err := p.Rotate(context.TODO(), "runtime-id")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, []byte("new-client-secret"), app.Credentials()[eamias.SecretKeyClientSecret])
+	require.Equal(t, []auth0Request{
+		{method: http.MethodGet, url: auth0ClientsURL},
+		{method: http.MethodPost, url: "https://tenant.eu.auth0.com/api/v2/clients/client-id/rotate-secret"},
+	}, requests)
+}
+
+func Test_auth0Provider_DeleteApplication(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []auth0Response
+		wantRequests []auth0Request
+		wantErr      string
+	}{
+		{
+			name:      "should delete the application",
+			responses: []auth0Response{{statusCode: http.StatusOK, body: auth0Clients}},
+			wantRequests: []auth0Request{
+				{method: http.MethodGet, url: auth0ClientsURL},
+				{method: http.MethodDelete, url: "https://tenant.eu.auth0.com/api/v2/clients/client-id"},
+			},
+		},
+		{
+			name:         "should succeed if the application doesn't exist",
+			responses:    []auth0Response{{statusCode: http.StatusOK, body: `[]`}},
+			wantRequests: []auth0Request{{method: http.MethodGet, url: auth0ClientsURL}},
+		},
+		{
+			name:         "should fail without deleting an application that isn't managed by the manager",
+			responses:    []auth0Response{{statusCode: http.StatusOK, body: `[{"client_id":"client-id","name":"runtime-id"}]`}},
+			wantRequests: []auth0Request{{method: http.MethodGet, url: auth0ClientsURL}},
+			wantErr:      "application runtime-id exists, but isn't managed by eventing-auth-manager",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []auth0Request
+			p := newTestAuth0Provider(t, newAuth0Mock(t, &requests, tt.responses...))
+
+			// when
+			err := p.DeleteApplication(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	kcorev1 "k8s.io/api/core/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// The keys of the binding of the Cloud Management Service are the keys of the secret of the SAP BTP service operator, which contain the
+	// nested objects of the binding as JSON.
+	cisUAAKey       = "uaa"
+	cisEndpointsKey = "endpoints"
+	// iasSubscriptionAppName is the name of the SaaS application that a subaccount subscribes to for an IAS tenant.
+	iasSubscriptionAppName = "sap-identity-services-onboarding"
+	iasSubscriptionStateOK = "SUBSCRIBED"
+)
+
+// cisCredentials contains the credentials of the binding of the Cloud Management Service.
+type cisCredentials struct {
+	UAA struct {
+		ClientID     string `json:"clientid"`
+		ClientSecret string `json:"clientsecret"`
+		URL          string `json:"url"`
+	}
+	Endpoints struct {
+		SaaSRegistryServiceURL string `json:"saas_registry_service_url"`
+	}
+}
+
+// readCISCredentials reads the binding of the Cloud Management Service from the referenced secret.
+func readCISCredentials(ctx context.Context, k8sClient kpkgclient.Client, ref eamapiv1alpha1.SecretReference) (cisCredentials, error) {
+	var secret kcorev1.Secret
+	if err := k8sClient.Get(ctx, kpkgclient.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return cisCredentials{}, errors.Wrap(err, "failed to get Cloud Management Service secret")
+	}
+	var credentials cisCredentials
+	if err := json.Unmarshal(secret.Data[cisUAAKey], &credentials.UAA); err != nil {
+		return cisCredentials{}, errors.Wrapf(err, "failed to decode key %s of Cloud Management Service secret", cisUAAKey)
+	}
+	if err := json.Unmarshal(secret.Data[cisEndpointsKey], &credentials.Endpoints); err != nil {
+		return cisCredentials{}, errors.Wrapf(err, "failed to decode key %s of Cloud Management Service secret", cisEndpointsKey)
+	}
+	if credentials.UAA.ClientID == "" || credentials.UAA.ClientSecret == "" || credentials.UAA.URL == "" ||
+		credentials.Endpoints.SaaSRegistryServiceURL == "" {
+		return cisCredentials{}, errors.Errorf("keys %s and %s of the Cloud Management Service secret must contain the client credentials "+
+			"and the SaaS registry service URL", cisUAAKey, cisEndpointsKey)
+	}
+	redact.Secret(credentials.UAA.ClientSecret)
+	return credentials, nil
+}
+
+// ResolveIASTenantURL returns the URL of the IAS tenant of the subaccount, which is the host of the URL of the subscription of the subaccount
+// to SAP Cloud Identity Services. The subscription is read with the SaaS Provisioning API of the Cloud Management Service.
+func ResolveIASTenantURL(ctx context.Context, httpClient *http.Client, k8sClient kpkgclient.Client, subaccount eamapiv1alpha1.BTPSubaccount,
+) (string, error) {
+	credentials, err := readCISCredentials(ctx, k8sClient, subaccount.CredentialsSecretRef)
+	if err != nil {
+		return "", err
+	}
+	oauthConfig := clientcredentials.Config{
+		ClientID:     credentials.UAA.ClientID,
+		ClientSecret: credentials.UAA.ClientSecret,
+		TokenURL:     strings.TrimSuffix(credentials.UAA.URL, "/") + "/oauth/token",
+	}
+	cisClient := oauthConfig.Client(context.WithValue(ctx, oauth2.HTTPClient, httpClient))
+
+	subscriptionURL := strings.TrimSuffix(credentials.Endpoints.SaaSRegistryServiceURL, "/") + "/saas-manager/v1/applications/" +
+		iasSubscriptionAppName + "?subaccountId=" + url.QueryEscape(subaccount.ID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, subscriptionURL, nil)
+	if err != nil {
+		return "", err
+	}
+	res, err := cisClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to request the subscription of the subaccount")
+	}
+	defer func() { _ = res.Body.Close() }()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", errors.Errorf("unexpected status code %d from the SaaS Provisioning API: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var subscription struct {
+		State           string `json:"state"`
+		SubscriptionURL string `json:"subscriptionUrl"`
+	}
+	if err := json.Unmarshal(body, &subscription); err != nil {
+		return "", errors.Wrap(err, "failed to decode the subscription of the subaccount")
+	}
+	if subscription.State != iasSubscriptionStateOK {
+		return "", errors.Errorf("subaccount %s isn't subscribed to SAP Cloud Identity Services, the state of the subscription is '%s'",
+			subaccount.ID, subscription.State)
+	}
+	tenantURL, err := url.Parse(subscription.SubscriptionURL)
+	if err != nil || tenantURL.Host == "" {
+		return "", errors.Errorf("invalid subscription URL '%s' of subaccount %s", subscription.SubscriptionURL, subaccount.ID)
+	}
+	return "https://" + tenantURL.Host, nil
+}
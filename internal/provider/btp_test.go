@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_ResolveIASTenantURL(t *testing.T) {
+	tests := []struct {
+		name             string
+		subscriptionBody string
+		wantURL          string
+		wantErr          string
+	}{
+		{
+			name:             "should return the host of the subscription URL",
+			subscriptionBody: `{"state":"SUBSCRIBED","subscriptionUrl":"https://tenant.accounts.ondemand.com/admin"}`,
+			wantURL:          "https://tenant.accounts.ondemand.com",
+		},
+		{
+			name:             "should fail if the subaccount isn't subscribed",
+			subscriptionBody: `{"state":"NOT_SUBSCRIBED"}`,
+			wantErr:          "subaccount subaccount-id isn't subscribed to SAP Cloud Identity Services, the state of the subscription is 'NOT_SUBSCRIBED'",
+		},
+		{
+			name:             "should fail if the subscription URL has no host",
+			subscriptionBody: `{"state":"SUBSCRIBED","subscriptionUrl":"admin"}`,
+			wantErr:          "invalid subscription URL 'admin' of subaccount subaccount-id",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				switch r.URL.Path {
+				case "/oauth/token":
+					_, _ = w.Write([]byte(`{"access_token":"cis-token","token_type":"bearer","expires_in":3600}`))
+				case "/saas-manager/v1/applications/sap-identity-services-onboarding":
+					require.Equal(t, "Bearer cis-token", r.Header.Get("Authorization"))
+					require.Equal(t, "subaccount-id", r.URL.Query().Get("subaccountId"))
+					_, _ = w.Write([]byte(tt.subscriptionBody))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+			k8sClient := kfake.NewClientBuilder().WithObjects(&kcorev1.Secret{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "cis-central"},
+				Data: map[string][]byte{
+					"uaa":       []byte(`{"clientid":"cis-client-id","clientsecret":"cis-client-secret","url":"` + server.URL + `"}`),
+					"endpoints": []byte(`{"saas_registry_service_url":"` + server.URL + `/"}`),
+				},
+			}).Build()
+			subaccount := eamapiv1alpha1.BTPSubaccount{
+				ID:                   "subaccount-id",
+				CredentialsSecretRef: eamapiv1alpha1.SecretReference{Namespace: "kcp-system", Name: "cis-central"},
+			}
+
+			// when
+			tenantURL, err := ResolveIASTenantURL(context.TODO(), server.Client(), k8sClient, subaccount)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantURL, tenantURL)
+			}
+		})
+	}
+}
+
+func Test_ResolveIASTenantURL_invalidSecret(t *testing.T) {
+	// given
+	k8sClient := kfake.NewClientBuilder().WithObjects(&kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "cis-central"},
+		Data: map[string][]byte{
+			"uaa":       []byte(`{"clientid":"cis-client-id"}`),
+			"endpoints": []byte(`{}`),
+		},
+	}).Build()
+	subaccount := eamapiv1alpha1.BTPSubaccount{
+		ID:                   "subaccount-id",
+		CredentialsSecretRef: eamapiv1alpha1.SecretReference{Namespace: "kcp-system", Name: "cis-central"},
+	}
+
+	// when
+	_, err := ResolveIASTenantURL(context.TODO(), http.DefaultClient, k8sClient, subaccount)
+
+	// then
+	require.EqualError(t, err, "keys uaa and endpoints of the Cloud Management Service secret must contain the client credentials and "+
+		"the SaaS registry service URL")
+}
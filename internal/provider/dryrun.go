@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// dryRunCredential is the client ID and client secret of the applications that are created or rotated in a dry run.
+const dryRunCredential = "dry-run"
+
+type dryRunFactory struct {
+	factory Factory
+}
+
+// NewDryRunFactory returns a factory of providers that log the changes of the applications in the providers of the given factory instead
+// of performing them. Applications that would be created or rotated have placeholder credentials and the endpoints of the discovery of the
+// provider, which is still requested, since it doesn't change the provider.
+func NewDryRunFactory(f Factory) Factory {
+	return dryRunFactory{factory: f}
+}
+
+func (f dryRunFactory) Get(ctx context.Context, spec eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	p, err := f.factory.Get(ctx, spec)
+	if err != nil {
+		return nil, err
+	}
+	return dryRunProvider{provider: p}, nil
+}
+
+// dryRunProvider logs the changes of the applications in the wrapped provider.
+type dryRunProvider struct {
+	provider Provider
+}
+
+func (p dryRunProvider) CreateApplication(ctx context.Context, name string) (eamias.Application, error) {
+	log.FromContext(ctx).Info("Dry run: skipped creation of application", "application", name)
+	return p.application(ctx, name)
+}
+
+func (p dryRunProvider) UpdateApplication(ctx context.Context, name string) error {
+	log.FromContext(ctx).Info("Dry run: skipped update of application", "application", name)
+	return nil
+}
+
+func (p dryRunProvider) DeleteApplication(ctx context.Context, name string) error {
+	log.FromContext(ctx).Info("Dry run: skipped deletion of application", "application", name)
+	return nil
+}
+
+func (p dryRunProvider) Rotate(ctx context.Context, name string) (eamias.Application, error) {
+	log.FromContext(ctx).Info("Dry run: skipped rotation of credentials", "application", name)
+	return p.application(ctx, name)
+}
+
+func (p dryRunProvider) AdoptApplication(ctx context.Context, id, name string) (eamias.Application, error) {
+	if _, ok := p.provider.(Adopter); !ok {
+		return Adopt(ctx, p.provider, id, name)
+	}
+	log.FromContext(ctx).Info("Dry run: skipped adoption of application", "application", name, "id", id)
+	return p.application(ctx, name)
+}
+
+func (p dryRunProvider) DeleteApplicationByID(ctx context.Context, id, name string) error {
+	if _, ok := p.provider.(Adopter); !ok {
+		return DeleteReplaced(ctx, p.provider, id, name)
+	}
+	log.FromContext(ctx).Info("Dry run: skipped deletion of replaced application", "application", name, "id", id)
+	return nil
+}
+
+func (p dryRunProvider) Discovery(ctx context.Context) (Discovery, error) {
+	return p.provider.Discovery(ctx)
+}
+
+// application returns the placeholder of the application with the given name, whose ID is derived from the name.
+func (p dryRunProvider) application(ctx context.Context, name string) (eamias.Application, error) {
+	discovery, err := p.provider.Discovery(ctx)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	return eamias.NewApplication(dryRunCredential+"-"+name, dryRunCredential, dryRunCredential, discovery.TokenURL, discovery.JWKSURI), nil
+}
+
+// isDryRunApplication returns whether the application is the placeholder of an application in a dry run.
+func isDryRunApplication(app eamias.Application) bool {
+	return strings.HasPrefix(app.GetID(), dryRunCredential+"-") && string(app.Credentials()[eamias.SecretKeyClientID]) == dryRunCredential
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_dryRunProvider(t *testing.T) {
+	// given
+	ctx := context.TODO()
+	memory := NewMemoryFactory(MemoryConfig{Issuer: DefaultMemoryIssuer})
+	p, err := NewDryRunFactory(memory).Get(ctx, eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+	wantApplication := eamias.NewApplication("dry-run-runtime-id", "dry-run", "dry-run", "http://localhost:8080/oauth2/token",
+		"http://localhost:8080/oauth2/certs")
+
+	// when
+	created, err := p.CreateApplication(ctx, "runtime-id")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, wantApplication, created)
+	require.NoError(t, SmokeTest(ctx, http.DefaultClient, created), "the placeholder credentials must not be smoke tested")
+
+	// when
+	rotated, err := p.Rotate(ctx, "runtime-id")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, wantApplication, rotated)
+	require.NoError(t, p.UpdateApplication(ctx, "runtime-id"))
+	require.NoError(t, p.DeleteApplication(ctx, "runtime-id"))
+
+	// when the wrapped provider can't adopt applications
+	_, err = Adopt(ctx, p, "application-id", "runtime-id")
+
+	// then the adoption is rejected like without a dry run
+	require.True(t, IsValidationError(err))
+	require.True(t, IsValidationError(DeleteReplaced(ctx, p, "application-id", "runtime-id")))
+
+	// then the application wasn't created in the wrapped provider
+	wrapped, err := memory.Get(ctx, eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+	require.EqualError(t, wrapped.UpdateApplication(ctx, "runtime-id"), "application runtime-id does not exist")
+}
@@ -0,0 +1,293 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/clientcredentials"
+	kcorev1 "k8s.io/api/core/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	EntraName                              = "entra"
+	DefaultEntraCredentialsSecretNamespace = "kcp-system"
+	DefaultEntraCredentialsSecretName      = "eventing-auth-entra-creds" //nolint:gosec // This is a name, not a credential.
+	DefaultEntraAuthorityHost              = "https://login.microsoftonline.com"
+	DefaultEntraGraphEndpoint              = "https://graph.microsoft.com"
+	entraTenantIDKey                       = "tenant_id"
+	entraClientIDKey                       = "client_id"
+	entraClientSecretKey                   = "client_secret"
+	entraPasswordDisplayName               = "eventing-auth-manager"
+)
+
+// EntraConfig defines how the applications are managed in Microsoft Entra ID.
+type EntraConfig struct {
+	// CredentialsSecretNamespace is the namespace of the secret that contains the tenant ID, client ID, and client secret of the application
+	// that the manager uses to call Microsoft Graph.
+	CredentialsSecretNamespace string
+	// CredentialsSecretName is the name of the secret that contains the credentials of the manager.
+	CredentialsSecretName string
+	// AuthorityHost is the host of the Microsoft identity platform, e.g. 'https://login.microsoftonline.us' for national clouds.
+	AuthorityHost string
+	// GraphEndpoint is the endpoint of Microsoft Graph, e.g. 'https://graph.microsoft.us' for national clouds.
+	GraphEndpoint string
+}
+
+// entraCredentials contains the credentials of the manager in Microsoft Entra ID.
+type entraCredentials struct {
+	tenantID     string
+	clientID     string
+	clientSecret string
+}
+
+type entraFactory struct {
+	httpClient *http.Client
+	k8sClient  kpkgclient.Client
+	config     EntraConfig
+
+	mu          sync.Mutex
+	credentials entraCredentials
+	provider    *entraProvider
+}
+
+// NewEntraFactory returns the factory of the Microsoft Entra ID provider. The credentials of the manager are read from the configured secret
+// on every call, and the provider is recreated if they changed.
+func NewEntraFactory(httpClient *http.Client, k8sClient kpkgclient.Client, config EntraConfig) Factory {
+	return &entraFactory{httpClient: httpClient, k8sClient: k8sClient, config: config}
+}
+
+func (f *entraFactory) Get(ctx context.Context, _ eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	var secret kcorev1.Secret
+	key := kpkgclient.ObjectKey{Namespace: f.config.CredentialsSecretNamespace, Name: f.config.CredentialsSecretName}
+	if err := f.k8sClient.Get(ctx, key, &secret); err != nil {
+		return nil, err
+	}
+	credentials := entraCredentials{
+		tenantID:     string(secret.Data[entraTenantIDKey]),
+		clientID:     string(secret.Data[entraClientIDKey]),
+		clientSecret: string(secret.Data[entraClientSecretKey]),
+	}
+	if credentials.tenantID == "" || credentials.clientID == "" || credentials.clientSecret == "" {
+		return nil, errors.Errorf("keys %s, %s, and %s must be set in the Entra ID secret", entraTenantIDKey, entraClientIDKey,
+			entraClientSecretKey)
+	}
+	redact.Secret(credentials.clientSecret)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.provider == nil || f.credentials != credentials {
+		f.credentials = credentials
+		f.provider = newEntraProvider(f.httpClient, credentials, f.config)
+	}
+	return f.provider, nil
+}
+
+// entraProvider registers the applications in Microsoft Entra ID with Microsoft Graph.
+type entraProvider struct {
+	httpClient    *http.Client
+	tenantID      string
+	authorityHost string
+	graphEndpoint string
+}
+
+func newEntraProvider(httpClient *http.Client, credentials entraCredentials, config EntraConfig) *entraProvider {
+	authorityHost := strings.TrimSuffix(config.AuthorityHost, "/")
+	graphEndpoint := strings.TrimSuffix(config.GraphEndpoint, "/")
+	oauthConfig := clientcredentials.Config{
+		ClientID:     credentials.clientID,
+		ClientSecret: credentials.clientSecret,
+		TokenURL:     authorityHost + "/" + url.PathEscape(credentials.tenantID) + "/oauth2/v2.0/token",
+		Scopes:       []string{graphEndpoint + "/.default"},
+	}
+	return &entraProvider{
+		httpClient:    newOAuthHTTPClient(httpClient, oauthConfig),
+		tenantID:      credentials.tenantID,
+		authorityHost: authorityHost,
+		graphEndpoint: graphEndpoint,
+	}
+}
+
+// entraApplication is an application of Microsoft Graph.
+type entraApplication struct {
+	ID                  string                    `json:"id"`
+	AppID               string                    `json:"appId"`
+	Notes               string                    `json:"notes"`
+	PasswordCredentials []entraPasswordCredential `json:"passwordCredentials"`
+}
+
+type entraPasswordCredential struct {
+	KeyID      string `json:"keyId"`
+	SecretText string `json:"secretText,omitempty"`
+}
+
+// CreateApplication registers an application, creates its service principal, so that tokens can be requested with the client credentials
+// in the tenant, and adds a client secret. An existing application with the same display name is deleted before, if it is managed by the
+// manager.
+func (p *entraProvider) CreateApplication(ctx context.Context, name string) (eamias.Application, error) {
+	existingApp, err := p.getApplicationByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	if existingApp != nil {
+		if err := p.deleteApplication(ctx, existingApp.ID); err != nil {
+			return eamias.Application{}, errors.Wrap(err, "failed to delete existing application before creation")
+		}
+	}
+
+	var app entraApplication
+	if _, err := p.call(ctx, http.MethodPost, "/v1.0/applications", map[string]interface{}{
+		"displayName":    name,
+		"signInAudience": "AzureADMyOrg",
+		"notes":          managedByMarker,
+	}, &app); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to create application")
+	}
+	if _, err := p.call(ctx, http.MethodPost, "/v1.0/servicePrincipals", map[string]string{"appId": app.AppID}, nil); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to create service principal")
+	}
+	return p.addPassword(ctx, app)
+}
+
+func (p *entraProvider) UpdateApplication(ctx context.Context, name string) error {
+	app, err := p.getExistingApplicationByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = p.call(ctx, http.MethodPatch, "/v1.0/applications/"+url.PathEscape(app.ID), map[string]string{"displayName": name}, nil)
+	return errors.Wrap(err, "failed to update application")
+}
+
+func (p *entraProvider) DeleteApplication(ctx context.Context, name string) error {
+	app, err := p.getApplicationByName(ctx, name)
+	if err != nil || app == nil {
+		return err
+	}
+	return errors.Wrap(p.deleteApplication(ctx, app.ID), "failed to delete application")
+}
+
+// Rotate adds a new client secret to the application and removes the previous client secrets after the new one was added.
+func (p *entraProvider) Rotate(ctx context.Context, name string) (eamias.Application, error) {
+	app, err := p.getExistingApplicationByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	rotated, err := p.addPassword(ctx, *app)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	for _, credential := range app.PasswordCredentials {
+		if _, err := p.call(ctx, http.MethodPost, "/v1.0/applications/"+url.PathEscape(app.ID)+"/removePassword",
+			map[string]string{"keyId": credential.KeyID}, nil); err != nil {
+			return eamias.Application{}, errors.Wrap(err, "failed to remove previous client secret")
+		}
+	}
+	return rotated, nil
+}
+
+func (p *entraProvider) Discovery(_ context.Context) (Discovery, error) {
+	tenant := p.authorityHost + "/" + url.PathEscape(p.tenantID)
+	return Discovery{TokenURL: tenant + "/oauth2/v2.0/token", JWKSURI: tenant + "/discovery/v2.0/keys"}, nil
+}
+
+func (p *entraProvider) addPassword(ctx context.Context, app entraApplication) (eamias.Application, error) {
+	var password entraPasswordCredential
+	if _, err := p.call(ctx, http.MethodPost, "/v1.0/applications/"+url.PathEscape(app.ID)+"/addPassword", map[string]interface{}{
+		"passwordCredential": map[string]string{"displayName": entraPasswordDisplayName},
+	}, &password); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to add client secret")
+	}
+	discovery, err := p.Discovery(ctx)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	return eamias.NewApplication(app.ID, app.AppID, password.SecretText, discovery.TokenURL, discovery.JWKSURI), nil
+}
+
+// getApplicationByName returns the application with the given display name, or nil if it doesn't exist. It fails if the application isn't
+// managed by the manager.
+func (p *entraProvider) getApplicationByName(ctx context.Context, name string) (*entraApplication, error) {
+	filter := url.QueryEscape("displayName eq '" + strings.ReplaceAll(name, "'", "''") + "'")
+	var res struct {
+		Value []entraApplication `json:"value"`
+	}
+	if _, err := p.call(ctx, http.MethodGet, "/v1.0/applications?$filter="+strings.ReplaceAll(filter, "+", "%20"), nil, &res); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch existing applications")
+	}
+	switch len(res.Value) {
+	case 0:
+		return nil, nil //nolint:nilnil
+	case 1:
+		if res.Value[0].Notes != managedByMarker {
+			return nil, newUnmanagedApplicationError(name)
+		}
+		return &res.Value[0], nil
+	default:
+		return nil, errors.Errorf("found multiple applications with the same name %s", name)
+	}
+}
+
+// getExistingApplicationByName returns the application with the given name, and fails if the application doesn't exist.
+func (p *entraProvider) getExistingApplicationByName(ctx context.Context, name string) (*entraApplication, error) {
+	app, err := p.getApplicationByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, errors.Errorf("application %s does not exist", name)
+	}
+	return app, nil
+}
+
+func (p *entraProvider) deleteApplication(ctx context.Context, id string) error {
+	status, err := p.call(ctx, http.MethodDelete, "/v1.0/applications/"+url.PathEscape(id), nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// call sends a request to Microsoft Graph and decodes the response into the output if it isn't nil. It returns the status code of the
+// response.
+func (p *entraProvider) call(ctx context.Context, method, path string, input, output interface{}) (int, error) {
+	var body []byte
+	if input != nil {
+		var err error
+		if body, err = json.Marshal(input); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.graphEndpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, errors.Errorf("unexpected status code %d from Microsoft Graph: %s", res.StatusCode,
+			strings.TrimSpace(string(resBody)))
+	}
+	if output != nil && len(resBody) > 0 {
+		return res.StatusCode, errors.Wrap(json.Unmarshal(resBody, output), "failed to decode response of Microsoft Graph")
+	}
+	return res.StatusCode, nil
+}
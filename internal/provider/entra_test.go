@@ -0,0 +1,243 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest/fake"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type graphRequest struct {
+	method string
+	url    string
+	input  map[string]interface{}
+}
+
+type graphResponse struct {
+	statusCode int
+	body       string
+}
+
+// newGraphMock returns an HTTP client that issues tokens for the manager and records the requests to Microsoft Graph. The requests are
+// answered with the given responses in order, all following requests succeed without body.
+func newGraphMock(t *testing.T, requests *[]graphRequest, responses ...graphResponse) *http.Client {
+	t.Helper()
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == "https://login.microsoftonline.com/tenant-id/oauth2/v2.0/token" {
+			require.NoError(t, req.ParseForm())
+			require.Equal(t, "https://graph.microsoft.com/.default", req.PostForm.Get("scope"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`))),
+			}, nil
+		}
+		require.Equal(t, "Bearer access-token", req.Header.Get("Authorization"))
+
+		r := graphRequest{method: req.Method, url: req.URL.String()}
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		if len(b) > 0 {
+			require.NoError(t, json.Unmarshal(b, &r.input))
+		}
+		*requests = append(*requests, r)
+
+		res := graphResponse{statusCode: http.StatusNoContent}
+		if len(*requests) <= len(responses) {
+			res = responses[len(*requests)-1]
+		}
+		return &http.Response{StatusCode: res.statusCode, Body: io.NopCloser(bytes.NewReader([]byte(res.body)))}, nil
+	})
+}
+
+func newTestEntraProvider(t *testing.T, httpClient *http.Client) Provider {
+	t.Helper()
+	k8sClient := kfake.NewClientBuilder().WithObjects(&kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Name: DefaultEntraCredentialsSecretName, Namespace: DefaultEntraCredentialsSecretNamespace},
+		Data: map[string][]byte{
+			"tenant_id":     []byte("tenant-id"),
+			"client_id":     []byte("manager-client-id"),
+			"client_secret": []byte("manager-client-secret"),
+		},
+	}).Build()
+	p, err := NewEntraFactory(httpClient, k8sClient, EntraConfig{
+		CredentialsSecretNamespace: DefaultEntraCredentialsSecretNamespace,
+		CredentialsSecretName:      DefaultEntraCredentialsSecretName,
+		AuthorityHost:              DefaultEntraAuthorityHost,
+		GraphEndpoint:              DefaultEntraGraphEndpoint,
+	}).Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+	return p
+}
+
+const (
+	graphFilterURL = "https://graph.microsoft.com/v1.0/applications?$filter=displayName%20eq%20%27runtime-id%27"
+	graphAppURL    = "https://graph.microsoft.com/v1.0/applications/object-id"
+)
+
+func Test_entraProvider_CreateApplication(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []graphResponse
+		wantRequests []graphRequest
+		wantApp      eamias.Application
+		wantErr      string
+	}{
+		{
+			name: "should register the application with service principal and client secret",
+			responses: []graphResponse{
+				{statusCode: http.StatusOK, body: `{"value":[]}`},
+				{statusCode: http.StatusCreated, body: `{"id":"object-id","appId":"app-id"}`},
+				{statusCode: http.StatusCreated, body: `{"id":"sp-id"}`},
+				{statusCode: http.StatusOK, body: `{"keyId":"key-id","secretText":"client-secret"}`},
+			},
+			wantRequests: []graphRequest{
+				{method: http.MethodGet, url: graphFilterURL},
+				{method: http.MethodPost, url: "https://graph.microsoft.com/v1.0/applications", input: map[string]interface{}{
+					"displayName":    "runtime-id",
+					"signInAudience": "AzureADMyOrg",
+					"notes":          "Managed by eventing-auth-manager",
+				}},
+				{method: http.MethodPost, url: "https://graph.microsoft.com/v1.0/servicePrincipals", input: map[string]interface{}{"appId": "app-id"}},
+				{method: http.MethodPost, url: graphAppURL + "/addPassword", input: map[string]interface{}{
+					"passwordCredential": map[string]interface{}{"displayName": "eventing-auth-manager"},
+				}},
+			},
+			wantApp: eamias.NewApplication("object-id", "app-id", "client-secret",
+				"https://login.microsoftonline.com/tenant-id/oauth2/v2.0/token",
+				"https://login.microsoftonline.com/tenant-id/discovery/v2.0/keys"),
+		},
+		{
+			name: "should fail if the existing application can't be deleted",
+			responses: []graphResponse{
+				{statusCode: http.StatusOK, body: `{"value":[{"id":"object-id","appId":"app-id","notes":"Managed by eventing-auth-manager"}]}`},
+				{statusCode: http.StatusForbidden, body: `{"error":{"code":"Authorization_RequestDenied"}}`},
+			},
+			wantRequests: []graphRequest{
+				{method: http.MethodGet, url: graphFilterURL},
+				{method: http.MethodDelete, url: graphAppURL},
+			},
+			wantErr: "failed to delete existing application before creation: unexpected status code 403 from Microsoft Graph: " +
+				`{"error":{"code":"Authorization_RequestDenied"}}`,
+		},
+		{
+			name: "should fail without deleting an existing application that isn't managed by the manager",
+			responses: []graphResponse{
+				{statusCode: http.StatusOK, body: `{"value":[{"id":"object-id","appId":"app-id","notes":"Payment service"}]}`},
+			},
+			wantRequests: []graphRequest{{method: http.MethodGet, url: graphFilterURL}},
+			wantErr:      "application runtime-id exists, but isn't managed by eventing-auth-manager",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []graphRequest
+			p := newTestEntraProvider(t, newGraphMock(t, &requests, tt.responses...))
+
+			// when
+			app, err := p.CreateApplication(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantApp, app)
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_entraProvider_Rotate(t *testing.T) {
+	// given
+	var requests []graphRequest
+	p := newTestEntraProvider(t, newGraphMock(t, &requests,
+		graphResponse{statusCode: http.StatusOK, body: `{"value":[{"id":"object-id","appId":"app-id","notes":"Managed by eventing-auth-manager",` +
+			`"passwordCredentials":[{"keyId":"old-key-id"}]}]}`},
+		graphResponse{statusCode: http.StatusOK, body: `{"keyId":"key-id","secretText":"new-client-secret"}`},
+	))
+
+	// when
+	app, err := p.Rotate(context.TODO(), "runtime-id")
+
+	// then the previous client secret is removed after the new client secret is added
+	require.NoError(t, err)
+	require.Equal(t, []byte("new-client-secret"), app.Credentials()[eamias.SecretKeyClientSecret])
+	require.Equal(t, graphRequest{method: http.MethodPost, url: graphAppURL + "/removePassword", input: map[string]interface{}{"keyId": "old-key-id"}},
+		requests[2])
+	require.Len(t, requests, 3)
+}
+
+func Test_entraProvider_DeleteApplication(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []graphResponse
+		wantRequests []graphRequest
+		wantErr      string
+	}{
+		{
+			name: "should delete the application",
+			responses: []graphResponse{
+				{statusCode: http.StatusOK, body: `{"value":[{"id":"object-id","appId":"app-id","notes":"Managed by eventing-auth-manager"}]}`},
+			},
+			wantRequests: []graphRequest{
+				{method: http.MethodGet, url: graphFilterURL},
+				{method: http.MethodDelete, url: graphAppURL},
+			},
+		},
+		{
+			name:         "should succeed if the application doesn't exist",
+			responses:    []graphResponse{{statusCode: http.StatusOK, body: `{"value":[]}`}},
+			wantRequests: []graphRequest{{method: http.MethodGet, url: graphFilterURL}},
+		},
+		{
+			name: "should succeed if the application was already deleted",
+			responses: []graphResponse{
+				{statusCode: http.StatusOK, body: `{"value":[{"id":"object-id","appId":"app-id","notes":"Managed by eventing-auth-manager"}]}`},
+				{statusCode: http.StatusNotFound},
+			},
+			wantRequests: []graphRequest{
+				{method: http.MethodGet, url: graphFilterURL},
+				{method: http.MethodDelete, url: graphAppURL},
+			},
+		},
+		{
+			name: "should fail without deleting an application that isn't managed by the manager",
+			responses: []graphResponse{
+				{statusCode: http.StatusOK, body: `{"value":[{"id":"object-id","appId":"app-id"}]}`},
+			},
+			wantRequests: []graphRequest{{method: http.MethodGet, url: graphFilterURL}},
+			wantErr:      "application runtime-id exists, but isn't managed by eventing-auth-manager",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []graphRequest
+			p := newTestEntraProvider(t, newGraphMock(t, &requests, tt.responses...))
+
+			// when
+			err := p.DeleteApplication(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ValidationError is returned if the spec of an EventingAuth CR or the configuration of a provider is invalid, so that retrying doesn't
+// help until the spec or the configuration is fixed.
+type ValidationError struct {
+	Message string
+}
+
+func newValidationError(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// newUnmanagedApplicationError returns the ValidationError of an existing application with the given name that doesn't have the marker of
+// the applications that the manager created.
+func newUnmanagedApplicationError(name string) error {
+	return newValidationError("application %s exists, but isn't managed by eventing-auth-manager", name)
+}
+
+// IsValidationError returns true if the error is or wraps a ValidationError.
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
@@ -0,0 +1,399 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/kyma-project/eventing-auth-manager/internal/faultinjection"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	IASName                              = "ias"
+	DefaultIASCredentialsSecretNamespace = "kcp-system"
+	DefaultIASCredentialsSecretName      = "eventing-auth-ias-creds" //nolint:gosec // This is a name, not a credential.
+	iasCredentialsSecretNamespaceEnvVar  = "IAS_CREDS_SECRET_NAMESPACE"
+	iasCredentialsSecretNameEnvVar       = "IAS_CREDS_SECRET_NAME"
+	// IASCredentialsLabel is the label of the secrets that define IAS tenants with their keys 'url', 'username', and 'password'.
+	IASCredentialsLabel = "eventingauth.kyma-project.io/ias-credentials"
+	// IASTenantLabel is the label of the name of the tenant of a labeled secret. The name of the secret is used if it isn't set.
+	IASTenantLabel       = "eventingauth.kyma-project.io/ias-tenant"
+	iasTenantURLKey      = "url"
+	iasTenantUsernameKey = "username"
+	iasTenantPasswordKey = "password"
+	// iasTenantTokenURLKey and iasTenantJWKSURIKey are the optional keys of a labeled secret that override the endpoints of the OIDC
+	// configuration of the tenant.
+	iasTenantTokenURLKey = "token_url"
+	iasTenantJWKSURIKey  = "jwks_uri"
+)
+
+// IASClientPool contains the IAS clients of the IAS tenants, which are the tenants of the IASTenant CRs, the tenants of the secrets with the
+// label IASCredentialsLabel, and the tenant of the credentials secret of the manager.
+type IASClientPool struct {
+	k8sClient kpkgclient.Client
+
+	mu sync.Mutex
+	// clients contains the clients by the names of their tenants, and the client of the credentials secret of the manager by the empty name.
+	clients map[string]iasPoolEntry
+	// faults are injected into the requests of the clients of all tenants.
+	faults faultinjection.Config
+	// duplicatePolicy defines how the clients of all tenants handle multiple applications with the same name.
+	duplicatePolicy eamias.DuplicatePolicy
+	// maintenanceThreshold is the duration that the responses of a tenant must be 503 for until the tenant is in maintenance. The maintenance
+	// isn't detected if it is 0.
+	maintenanceThreshold time.Duration
+	// maintenances contains the maintenance detections by the names of their tenants. They are kept when a client is recreated.
+	maintenances map[string]*eamias.Maintenance
+	// discoveryTTL is the duration after which the clients of all tenants discover the endpoints of the OIDC configuration again.
+	discoveryTTL time.Duration
+	// requestTimeout is the deadline of every request of the clients of all tenants.
+	requestTimeout time.Duration
+}
+
+type iasPoolEntry struct {
+	client  eamias.Client
+	options eamias.ClientOptions
+}
+
+// NewIASClientPool returns an empty pool of IAS clients, that are created on the first request of their tenants.
+func NewIASClientPool(k8sClient kpkgclient.Client) *IASClientPool {
+	return &IASClientPool{k8sClient: k8sClient, clients: map[string]iasPoolEntry{}, maintenances: map[string]*eamias.Maintenance{}}
+}
+
+// InjectFaults injects the faults into the requests of the clients of all tenants. It must be called before the first client is requested.
+func (p *IASClientPool) InjectFaults(faults faultinjection.Config) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.faults = faults
+}
+
+// SetDuplicatePolicy sets how the clients of all tenants handle multiple applications with the same name. It must be called before the first
+// client is requested.
+func (p *IASClientPool) SetDuplicatePolicy(policy eamias.DuplicatePolicy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.duplicatePolicy = policy
+}
+
+// DetectMaintenance enables the maintenance detection of all tenants, which are in maintenance once their responses were 503 for longer
+// than the threshold. It must be called before the first client is requested.
+func (p *IASClientPool) DetectMaintenance(threshold time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maintenanceThreshold = threshold
+}
+
+// SetDiscoveryTTL sets the duration after which the clients of all tenants discover the endpoints of the OIDC configuration again. It must
+// be called before the first client is requested.
+func (p *IASClientPool) SetDiscoveryTTL(ttl time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.discoveryTTL = ttl
+}
+
+// SetRequestTimeout sets the deadline of every request of the clients of all tenants. It must be called before the first client is requested.
+func (p *IASClientPool) SetRequestTimeout(timeout time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requestTimeout = timeout
+}
+
+// InMaintenance returns true if the tenant with the given name is in maintenance, and the time since its responses are 503. It is false if
+// the maintenance detection isn't enabled or the client of the tenant wasn't requested yet.
+func (p *IASClientPool) InMaintenance(tenant string) (bool, time.Time) {
+	p.mu.Lock()
+	maintenance := p.maintenances[tenant]
+	p.mu.Unlock()
+	return maintenance.Active()
+}
+
+// Get returns the client of the tenant with the given name, or the client of the credentials secret of the manager if the name is empty.
+// A tenant is defined by the IASTenant CR with its name, or by the secret with the label IASCredentialsLabel whose label IASTenantLabel or
+// name is the name of the tenant.
+// The credentials of the tenant are read on every call, and the client is recreated if they or the rate limit of the tenant changed. The
+// client of a tenant that was removed is removed from the pool.
+func (p *IASClientPool) Get(ctx context.Context, tenant string) (eamias.Client, error) {
+	var credentials *eamias.Credentials
+	var options eamias.ClientOptions
+	var err error
+	if tenant == "" {
+		namespace, name := IASCredentialsSecret()
+		credentials, err = eamias.ReadCredentials(namespace, name, p.k8sClient)
+	} else {
+		credentials, options, err = p.readTenant(ctx, tenant)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		delete(p.clients, tenant)
+		delete(p.maintenances, tenant)
+		return nil, err
+	}
+	options.Faults = p.faults
+	options.DuplicatePolicy = p.duplicatePolicy
+	options.DiscoveryTTL = p.discoveryTTL
+	options.RequestTimeout = p.requestTimeout
+	if p.maintenanceThreshold > 0 {
+		if _, ok := p.maintenances[tenant]; !ok {
+			p.maintenances[tenant] = eamias.NewMaintenance(p.maintenanceThreshold)
+		}
+		options.Maintenance = p.maintenances[tenant]
+	}
+	// return from cache unless credentials are changed
+	entry, ok := p.clients[tenant]
+	if !ok || entry.options != options || !reflect.DeepEqual(entry.client.GetCredentials(), credentials) {
+		// update IAS client if credentials are changed
+		iasClient, err := eamias.NewClient(credentials.URL, credentials.Username, credentials.Password, options)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create a new IAS client")
+		}
+		entry = iasPoolEntry{client: iasClient, options: options}
+		p.clients[tenant] = entry
+	}
+	return entry.client, nil
+}
+
+// readTenant returns the credentials and the client options of the IASTenant CR with the given name, or the credentials of the labeled secret
+// of the tenant if no IASTenant CR exists.
+func (p *IASClientPool) readTenant(ctx context.Context, name string) (*eamias.Credentials, eamias.ClientOptions, error) {
+	var tenant eamapiv1alpha1.IASTenant
+	if err := p.k8sClient.Get(ctx, kpkgclient.ObjectKey{Name: name}, &tenant); err != nil {
+		if !kapierrors.IsNotFound(err) {
+			return nil, eamias.ClientOptions{}, errors.Wrapf(err, "failed to get IASTenant %s", name)
+		}
+		return p.readLabeledSecret(ctx, name)
+	}
+	var secret kcorev1.Secret
+	ref := tenant.Spec.CredentialsSecretRef
+	if err := p.k8sClient.Get(ctx, kpkgclient.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, &secret); err != nil {
+		return nil, eamias.ClientOptions{}, errors.Wrapf(err, "failed to get credentials secret of IASTenant %s", name)
+	}
+	username, password := string(secret.Data[iasTenantUsernameKey]), string(secret.Data[iasTenantPasswordKey])
+	if username == "" || password == "" {
+		return nil, eamias.ClientOptions{}, newValidationError("keys %s and %s must be set in the credentials secret of IASTenant %s",
+			iasTenantUsernameKey, iasTenantPasswordKey, name)
+	}
+	// The URL of a tenant of a subaccount is discovered by the reconciler of the IASTenant CRs.
+	tenantURL := tenant.Spec.URL
+	if tenantURL == "" {
+		tenantURL = tenant.Status.URL
+	}
+	if tenantURL == "" {
+		return nil, eamias.ClientOptions{}, errors.Errorf("the URL of IASTenant %s is neither set nor discovered from its subaccount", name)
+	}
+	options := eamias.ClientOptions{TokenURL: tenant.Spec.TokenURL, JWKSURI: tenant.Spec.JWKSURI}
+	if tenant.Spec.RateLimit != nil {
+		options.RequestsPerSecond = int(tenant.Spec.RateLimit.RequestsPerSecond)
+		options.Burst = int(tenant.Spec.RateLimit.Burst)
+	}
+	return eamias.NewCredentials(tenantURL, username, password), options, nil
+}
+
+// readLabeledSecret returns the credentials and the client options of the secret with the label IASCredentialsLabel of the tenant with the
+// given name. The secrets are listed on every call, so that secrets that are added or removed at runtime are discovered.
+func (p *IASClientPool) readLabeledSecret(ctx context.Context, tenant string) (*eamias.Credentials, eamias.ClientOptions, error) {
+	var secrets kcorev1.SecretList
+	if err := p.k8sClient.List(ctx, &secrets, kpkgclient.MatchingLabels{IASCredentialsLabel: "true"}); err != nil {
+		return nil, eamias.ClientOptions{}, errors.Wrap(err, "failed to list IAS credentials secrets")
+	}
+	var found *kcorev1.Secret
+	for i := range secrets.Items {
+		if iasTenantOfSecret(&secrets.Items[i]) != tenant {
+			continue
+		}
+		if found != nil {
+			return nil, eamias.ClientOptions{}, newValidationError("found multiple IAS credentials secrets of tenant %s", tenant)
+		}
+		found = &secrets.Items[i]
+	}
+	if found == nil {
+		return nil, eamias.ClientOptions{}, newValidationError("IAS tenant %s is neither defined by an IASTenant CR nor by a secret with label %s=true", tenant,
+			IASCredentialsLabel)
+	}
+	url := string(found.Data[iasTenantURLKey])
+	username, password := string(found.Data[iasTenantUsernameKey]), string(found.Data[iasTenantPasswordKey])
+	if url == "" || username == "" || password == "" {
+		return nil, eamias.ClientOptions{}, newValidationError("keys %s, %s, and %s must be set in the IAS credentials secret %s/%s", iasTenantURLKey, iasTenantUsernameKey,
+			iasTenantPasswordKey, found.Namespace, found.Name)
+	}
+	options := eamias.ClientOptions{
+		TokenURL: string(found.Data[iasTenantTokenURLKey]),
+		JWKSURI:  string(found.Data[iasTenantJWKSURIKey]),
+	}
+	return eamias.NewCredentials(url, username, password), options, nil
+}
+
+// TenantsOfSecret returns the names of the tenants whose credentials are read from the given secret, so that the consumers of the clients of
+// the tenants can react to rotated credentials right away. The tenant of the credentials secret of the manager has the empty name.
+func (p *IASClientPool) TenantsOfSecret(ctx context.Context, secret kpkgclient.Object) ([]string, error) {
+	var tenants []string
+	if namespace, name := IASCredentialsSecret(); secret.GetNamespace() == namespace && secret.GetName() == name {
+		tenants = append(tenants, "")
+	}
+	var iasTenants eamapiv1alpha1.IASTenantList
+	if err := p.k8sClient.List(ctx, &iasTenants); err != nil {
+		return nil, errors.Wrap(err, "failed to list IASTenants")
+	}
+	crTenants := map[string]bool{}
+	for _, tenant := range iasTenants.Items {
+		crTenants[tenant.Name] = true
+		ref := tenant.Spec.CredentialsSecretRef
+		if ref.Namespace == secret.GetNamespace() && ref.Name == secret.GetName() {
+			tenants = append(tenants, tenant.Name)
+		}
+	}
+	// A labeled secret of a tenant that is defined by an IASTenant CR isn't used.
+	if secret.GetLabels()[IASCredentialsLabel] == "true" {
+		if tenant := iasTenantOfSecret(secret); !crTenants[tenant] {
+			tenants = append(tenants, tenant)
+		}
+	}
+	return tenants, nil
+}
+
+// iasTenantOfSecret returns the name of the tenant of the labeled secret, which defaults to the name of the secret.
+func iasTenantOfSecret(secret kpkgclient.Object) string {
+	if tenant := secret.GetLabels()[IASTenantLabel]; tenant != "" {
+		return tenant
+	}
+	return secret.GetName()
+}
+
+// SelectIASTenant returns the name of the IASTenant CR whose Kyma selector matches the given labels of a Kyma CR, or an empty name if no
+// IASTenant CR selects the Kyma CR.
+func SelectIASTenant(ctx context.Context, k8sClient kpkgclient.Client, kymaLabels map[string]string) (string, error) {
+	var tenants eamapiv1alpha1.IASTenantList
+	if err := k8sClient.List(ctx, &tenants); err != nil {
+		return "", errors.Wrap(err, "failed to list IASTenants")
+	}
+	var selected []string
+	for _, tenant := range tenants.Items {
+		if tenant.Spec.KymaSelector == nil {
+			continue
+		}
+		selector, err := kmetav1.LabelSelectorAsSelector(tenant.Spec.KymaSelector)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid Kyma selector of IASTenant %s", tenant.Name)
+		}
+		if selector.Matches(labels.Set(kymaLabels)) {
+			selected = append(selected, tenant.Name)
+		}
+	}
+	switch len(selected) {
+	case 0:
+		return "", nil
+	case 1:
+		return selected[0], nil
+	default:
+		sort.Strings(selected)
+		return "", newValidationError("multiple IASTenants select the Kyma (%s)", strings.Join(selected, ", "))
+	}
+}
+
+// FallbackIASTenant returns the fallback tenant of the IASTenant CR with the given name, or an empty name if the tenant has no fallback tenant
+// or isn't defined by an IASTenant CR.
+func FallbackIASTenant(ctx context.Context, k8sClient kpkgclient.Client, name string) (string, error) {
+	var tenant eamapiv1alpha1.IASTenant
+	if err := k8sClient.Get(ctx, kpkgclient.ObjectKey{Name: name}, &tenant); err != nil {
+		if kapierrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get IASTenant %s", name)
+	}
+	if tenant.Spec.FallbackTenant == name {
+		return "", newValidationError("IASTenant %s must not be its own fallback tenant", name)
+	}
+	return tenant.Spec.FallbackTenant, nil
+}
+
+// iasFactory returns the IAS provider with the client of the IAS tenant that is requested by the EventingAuth CR.
+type iasFactory struct {
+	pool *IASClientPool
+}
+
+// NewIASFactory returns the factory of the IAS provider. The applications are managed in the tenant of the IASTenant CR that is requested by
+// spec.ias.tenant of the EventingAuth CR, or in the tenant of the secret that is defined by the environment variables
+// IAS_CREDS_SECRET_NAMESPACE and IAS_CREDS_SECRET_NAME.
+func NewIASFactory(pool *IASClientPool) Factory {
+	return &iasFactory{pool: pool}
+}
+
+func (f *iasFactory) Get(ctx context.Context, spec eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	var tenant string
+	if spec.IAS != nil {
+		tenant = spec.IAS.Tenant
+	}
+	iasClient, err := f.pool.Get(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+	return iasProvider{client: iasClient}, nil
+}
+
+// IASCredentialsSecret returns the namespace and the name of the credentials secret of the manager, which can be overridden with the
+// environment variables IAS_CREDS_SECRET_NAMESPACE and IAS_CREDS_SECRET_NAME.
+func IASCredentialsSecret() (string, string) {
+	namespace := os.Getenv(iasCredentialsSecretNamespaceEnvVar)
+	if len(namespace) == 0 {
+		namespace = DefaultIASCredentialsSecretNamespace
+	}
+	name := os.Getenv(iasCredentialsSecretNameEnvVar)
+	if len(name) == 0 {
+		name = DefaultIASCredentialsSecretName
+	}
+	return namespace, name
+}
+
+// iasProvider manages the applications in SAP Cloud Identity Services (IAS).
+type iasProvider struct {
+	client eamias.Client
+}
+
+func (p iasProvider) CreateApplication(ctx context.Context, name string) (eamias.Application, error) {
+	return p.client.CreateApplication(ctx, name)
+}
+
+func (p iasProvider) UpdateApplication(ctx context.Context, name string) error {
+	return p.client.UpdateApplication(ctx, name)
+}
+
+func (p iasProvider) DeleteApplication(ctx context.Context, name string) error {
+	return p.client.DeleteApplication(ctx, name)
+}
+
+func (p iasProvider) Rotate(ctx context.Context, name string) (eamias.Application, error) {
+	return p.client.RotateSecret(ctx, name)
+}
+
+func (p iasProvider) AdoptApplication(ctx context.Context, id, name string) (eamias.Application, error) {
+	return p.client.AdoptApplication(ctx, id, name)
+}
+
+func (p iasProvider) DeleteApplicationByID(ctx context.Context, id, name string) error {
+	return p.client.DeleteApplicationByID(ctx, id, name)
+}
+
+func (p iasProvider) Discovery(ctx context.Context) (Discovery, error) {
+	tokenURL, err := p.client.GetTokenURL(ctx)
+	if err != nil {
+		return Discovery{}, err
+	}
+	jwksURI, err := p.client.GetJWKSURI(ctx)
+	if err != nil {
+		return Discovery{}, err
+	}
+	return Discovery{TokenURL: *tokenURL, JWKSURI: *jwksURI}, nil
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	IASServiceManagerName                              = "ias-service-manager"
+	DefaultIASServiceManagerCredentialsSecretNamespace = "kcp-system"
+	//nolint:gosec // This is a name, not a credential.
+	DefaultIASServiceManagerCredentialsSecretName = "eventing-auth-ias-service-manager-creds"
+	DefaultIASServiceManagerPlan                  = "application"
+)
+
+// iasServiceManagerOffering provisions service instances of SAP Cloud Identity Services, that can only request tokens with the client
+// credentials grant. The service bindings contain a client secret instead of a certificate.
+var iasServiceManagerOffering = serviceOffering{
+	displayName: "IAS",
+	catalogName: "identity",
+	instanceParameters: func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"display-name": name,
+			"oauth2-configuration": map[string]interface{}{
+				"grant-types": []string{"client_credentials"},
+			},
+		}
+	},
+	bindingParameters: map[string]interface{}{"credential-type": "SECRET"},
+	discovery: func(tenantURL string) Discovery {
+		return Discovery{TokenURL: tenantURL + "/oauth2/token", JWKSURI: tenantURL + "/oauth2/certs"}
+	},
+}
+
+// IASServiceManagerConfig defines how the IAS service instances are provisioned.
+type IASServiceManagerConfig struct {
+	// CredentialsSecretNamespace is the namespace of the secret that contains the service manager binding that the manager uses to provision
+	// the IAS service instances.
+	CredentialsSecretNamespace string
+	// CredentialsSecretName is the name of the secret that contains the service manager binding.
+	CredentialsSecretName string
+	// Plan is the service plan of the IAS service instances.
+	Plan string
+}
+
+type iasServiceManagerFactory struct {
+	httpClient *http.Client
+	k8sClient  kpkgclient.Client
+	config     IASServiceManagerConfig
+
+	mu          sync.Mutex
+	credentials serviceManagerCredentials
+	provider    *serviceManagerProvider
+}
+
+// NewIASServiceManagerFactory returns the factory of the provider that provisions the applications in IAS as service instances with the
+// service manager API, for subaccounts without credentials of an administrator of the IAS tenant. The service manager binding is read from
+// the configured secret on every call, and the provider is recreated if it changed.
+func NewIASServiceManagerFactory(httpClient *http.Client, k8sClient kpkgclient.Client, config IASServiceManagerConfig) Factory {
+	return &iasServiceManagerFactory{httpClient: httpClient, k8sClient: k8sClient, config: config}
+}
+
+func (f *iasServiceManagerFactory) Get(ctx context.Context, _ eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	credentials, err := readServiceManagerCredentials(ctx, f.k8sClient, f.config.CredentialsSecretNamespace, f.config.CredentialsSecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.provider == nil || f.credentials != credentials {
+		f.credentials = credentials
+		// The service manager binding doesn't contain the URL of the IAS tenant, which is only known from the service bindings of the
+		// IAS service instances.
+		f.provider = newServiceManagerProvider(f.httpClient, credentials, iasServiceManagerOffering, f.config.Plan, "")
+	}
+	return f.provider, nil
+}
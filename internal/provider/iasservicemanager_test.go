@@ -0,0 +1,98 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestIASServiceManagerProvider(t *testing.T, httpClient *http.Client) Provider {
+	t.Helper()
+	k8sClient := kfake.NewClientBuilder().WithObjects(&kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{
+			Name:      DefaultIASServiceManagerCredentialsSecretName,
+			Namespace: DefaultIASServiceManagerCredentialsSecretNamespace,
+		},
+		Data: map[string][]byte{
+			"clientid":     []byte("sm-client-id"),
+			"clientsecret": []byte("sm-client-secret"),
+			"sm_url":       []byte("https://service-manager.cfapps.eu10.hana.ondemand.com"),
+			"tokenurl":     []byte("https://subaccount.authentication.eu10.hana.ondemand.com"),
+		},
+	}).Build()
+	p, err := NewIASServiceManagerFactory(httpClient, k8sClient, IASServiceManagerConfig{
+		CredentialsSecretNamespace: DefaultIASServiceManagerCredentialsSecretNamespace,
+		CredentialsSecretName:      DefaultIASServiceManagerCredentialsSecretName,
+		Plan:                       DefaultIASServiceManagerPlan,
+	}).Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+	return p
+}
+
+func Test_iasServiceManagerProvider_CreateApplication(t *testing.T) {
+	// given
+	var requests []serviceManagerRequest
+	p := newTestIASServiceManagerProvider(t, newServiceManagerMock(t, &requests,
+		serviceManagerResponse{statusCode: http.StatusOK, body: `{"items":[]}`},
+		serviceManagerResponse{statusCode: http.StatusOK, body: `{"items":[{"id":"offering-id","name":"identity"}]}`},
+		serviceManagerResponse{statusCode: http.StatusOK, body: `{"items":[{"id":"plan-id","name":"application"}]}`},
+		serviceManagerResponse{statusCode: http.StatusCreated, body: `{"id":"instance-id","name":"runtime-id"}`},
+		serviceManagerResponse{statusCode: http.StatusCreated, body: `{"id":"binding-id","credentials":{"clientid":"client-id",` +
+			`"clientsecret":"client-secret","url":"https://tenant.accounts.ondemand.com/"}}`},
+	))
+
+	// when
+	app, err := p.CreateApplication(context.TODO(), "runtime-id")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, eamias.NewApplication("instance-id", "client-id", "client-secret", "https://tenant.accounts.ondemand.com/oauth2/token",
+		"https://tenant.accounts.ondemand.com/oauth2/certs"), app)
+	require.Equal(t, []serviceManagerRequest{
+		{method: http.MethodGet, url: serviceManagerInstancesURL},
+		{method: http.MethodGet, url: serviceManagerURL + "/v1/service_offerings?fieldQuery=catalog_name%20eq%20%27identity%27"},
+		{method: http.MethodGet, url: serviceManagerURL + "/v1/service_plans?fieldQuery=catalog_name%20eq%20%27application%27%20and" +
+			"%20service_offering_id%20eq%20%27offering-id%27"},
+		{method: http.MethodPost, url: serviceManagerURL + "/v1/service_instances?async=false", input: map[string]interface{}{
+			"name":            "runtime-id",
+			"service_plan_id": "plan-id",
+			"parameters": map[string]interface{}{
+				"display-name": "runtime-id",
+				"oauth2-configuration": map[string]interface{}{
+					"grant-types": []interface{}{"client_credentials"},
+				},
+			},
+		}},
+		{method: http.MethodPost, url: serviceManagerURL + "/v1/service_bindings?async=false", input: map[string]interface{}{
+			"name":                "runtime-id-xxxxx",
+			"service_instance_id": "instance-id",
+			"parameters":          map[string]interface{}{"credential-type": "SECRET"},
+		}},
+	}, requests)
+
+	// and the IAS tenant of the service binding is discovered
+	discovery, err := p.Discovery(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, Discovery{TokenURL: "https://tenant.accounts.ondemand.com/oauth2/token",
+		JWKSURI: "https://tenant.accounts.ondemand.com/oauth2/certs"}, discovery)
+}
+
+func Test_iasServiceManagerProvider_Discovery(t *testing.T) {
+	// given
+	var requests []serviceManagerRequest
+	p := newTestIASServiceManagerProvider(t, newServiceManagerMock(t, &requests))
+
+	// when
+	_, err := p.Discovery(context.TODO())
+
+	// then
+	require.EqualError(t, err, "the IAS tenant is only known after a service binding was created")
+	require.Empty(t, requests)
+}
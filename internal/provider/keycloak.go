@@ -0,0 +1,325 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/clientcredentials"
+	kcorev1 "k8s.io/api/core/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	KeycloakName                              = "keycloak"
+	DefaultKeycloakCredentialsSecretNamespace = "kcp-system"
+	DefaultKeycloakCredentialsSecretName      = "eventing-auth-keycloak-creds" //nolint:gosec // This is a name, not a credential.
+	DefaultKeycloakRealm                      = "eventing"
+	DefaultKeycloakAdminRealm                 = "master"
+	keycloakURLKey                            = "url"
+	keycloakClientIDKey                       = "client_id"
+	keycloakClientSecretKey                   = "client_secret"
+)
+
+// KeycloakConfig defines how the applications are managed in Keycloak.
+type KeycloakConfig struct {
+	// CredentialsSecretNamespace is the namespace of the secret that contains the URL of Keycloak and the client ID and client secret of the
+	// service account client that the manager uses to call the admin REST API.
+	CredentialsSecretNamespace string
+	// CredentialsSecretName is the name of the secret that contains the credentials of the manager.
+	CredentialsSecretName string
+	// AdminRealm is the realm of the service account client of the manager.
+	AdminRealm string
+	// Realm is the realm that the clients of the SKR clusters are created in. It is created if it doesn't exist.
+	Realm string
+}
+
+// keycloakCredentials contains the credentials of the manager in Keycloak.
+type keycloakCredentials struct {
+	url          string
+	clientID     string
+	clientSecret string
+}
+
+type keycloakFactory struct {
+	httpClient *http.Client
+	k8sClient  kpkgclient.Client
+	config     KeycloakConfig
+
+	mu          sync.Mutex
+	credentials keycloakCredentials
+	// providers contains the providers by their realms.
+	providers map[string]*keycloakProvider
+}
+
+// NewKeycloakFactory returns the factory of the Keycloak provider. The credentials of the manager are read from the configured secret on
+// every call, and the providers are recreated if they changed. The realm can be overridden by spec.keycloak of the EventingAuth CR.
+func NewKeycloakFactory(httpClient *http.Client, k8sClient kpkgclient.Client, config KeycloakConfig) Factory {
+	return &keycloakFactory{httpClient: httpClient, k8sClient: k8sClient, config: config}
+}
+
+func (f *keycloakFactory) Get(ctx context.Context, spec eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	var secret kcorev1.Secret
+	key := kpkgclient.ObjectKey{Namespace: f.config.CredentialsSecretNamespace, Name: f.config.CredentialsSecretName}
+	if err := f.k8sClient.Get(ctx, key, &secret); err != nil {
+		return nil, err
+	}
+	credentials := keycloakCredentials{
+		url:          strings.TrimSuffix(string(secret.Data[keycloakURLKey]), "/"),
+		clientID:     string(secret.Data[keycloakClientIDKey]),
+		clientSecret: string(secret.Data[keycloakClientSecretKey]),
+	}
+	if credentials.url == "" || credentials.clientID == "" || credentials.clientSecret == "" {
+		return nil, errors.Errorf("keys %s, %s, and %s must be set in the Keycloak secret", keycloakURLKey, keycloakClientIDKey,
+			keycloakClientSecretKey)
+	}
+	redact.Secret(credentials.clientSecret)
+
+	config := f.config
+	if spec.Keycloak != nil && spec.Keycloak.Realm != "" {
+		config.Realm = spec.Keycloak.Realm
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.providers == nil || f.credentials != credentials {
+		f.credentials = credentials
+		f.providers = map[string]*keycloakProvider{}
+	}
+	p, ok := f.providers[config.Realm]
+	if !ok {
+		p = newKeycloakProvider(f.httpClient, credentials, config)
+		f.providers[config.Realm] = p
+	}
+	return p, nil
+}
+
+// keycloakProvider creates the clients of the SKR clusters in a realm of Keycloak with the admin REST API.
+type keycloakProvider struct {
+	httpClient *http.Client
+	url        string
+	realm      string
+
+	// realmEnsured is set after the realm was found or created, so that it is only requested once.
+	mu           sync.Mutex
+	realmEnsured bool
+}
+
+func newKeycloakProvider(httpClient *http.Client, credentials keycloakCredentials, config KeycloakConfig) *keycloakProvider {
+	oauthConfig := clientcredentials.Config{
+		ClientID:     credentials.clientID,
+		ClientSecret: credentials.clientSecret,
+		TokenURL:     credentials.url + "/realms/" + url.PathEscape(config.AdminRealm) + "/protocol/openid-connect/token",
+	}
+	return &keycloakProvider{
+		httpClient: newOAuthHTTPClient(httpClient, oauthConfig),
+		url:        credentials.url,
+		realm:      config.Realm,
+	}
+}
+
+// keycloakClient is a client representation of the admin REST API.
+type keycloakClient struct {
+	ID          string `json:"id"`
+	ClientID    string `json:"clientId"`
+	Description string `json:"description"`
+}
+
+// CreateApplication creates a confidential client with the client credentials grant. An existing client with the same client ID is deleted
+// before, if it is managed by the manager.
+func (p *keycloakProvider) CreateApplication(ctx context.Context, name string) (eamias.Application, error) {
+	if err := p.ensureRealm(ctx); err != nil {
+		return eamias.Application{}, err
+	}
+	existingClient, err := p.getClientByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	if existingClient != nil {
+		if err := p.deleteClient(ctx, existingClient.ID); err != nil {
+			return eamias.Application{}, errors.Wrap(err, "failed to delete existing client before creation")
+		}
+	}
+
+	if _, err := p.call(ctx, http.MethodPost, p.realmPath()+"/clients", map[string]interface{}{
+		"clientId":                  name,
+		"name":                      name,
+		"description":               managedByMarker,
+		"enabled":                   true,
+		"protocol":                  "openid-connect",
+		"publicClient":              false,
+		"clientAuthenticatorType":   "client-secret",
+		"serviceAccountsEnabled":    true,
+		"standardFlowEnabled":       false,
+		"directAccessGrantsEnabled": false,
+	}, nil); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to create client")
+	}
+	// The ID of the created client is only returned in the location header, so the client is fetched again.
+	client, err := p.getExistingClientByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	return p.secret(ctx, *client, http.MethodGet)
+}
+
+func (p *keycloakProvider) UpdateApplication(ctx context.Context, name string) error {
+	client, err := p.getExistingClientByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = p.call(ctx, http.MethodPut, p.clientPath(client.ID), map[string]string{"clientId": name, "name": name}, nil)
+	return errors.Wrap(err, "failed to update client")
+}
+
+func (p *keycloakProvider) DeleteApplication(ctx context.Context, name string) error {
+	client, err := p.getClientByName(ctx, name)
+	if err != nil || client == nil {
+		return err
+	}
+	return errors.Wrap(p.deleteClient(ctx, client.ID), "failed to delete client")
+}
+
+// Rotate regenerates the client secret, which revokes the previous client secret immediately.
+func (p *keycloakProvider) Rotate(ctx context.Context, name string) (eamias.Application, error) {
+	client, err := p.getExistingClientByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	return p.secret(ctx, *client, http.MethodPost)
+}
+
+func (p *keycloakProvider) Discovery(_ context.Context) (Discovery, error) {
+	oidc := p.url + "/realms/" + url.PathEscape(p.realm) + "/protocol/openid-connect"
+	return Discovery{TokenURL: oidc + "/token", JWKSURI: oidc + "/certs"}, nil
+}
+
+// secret returns the application with the client secret of the client, which is regenerated if the method is POST.
+func (p *keycloakProvider) secret(ctx context.Context, client keycloakClient, method string) (eamias.Application, error) {
+	var secret struct {
+		Value string `json:"value"`
+	}
+	if _, err := p.call(ctx, method, p.clientPath(client.ID)+"/client-secret", nil, &secret); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to fetch client secret")
+	}
+	discovery, err := p.Discovery(ctx)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	return eamias.NewApplication(client.ID, client.ClientID, secret.Value, discovery.TokenURL, discovery.JWKSURI), nil
+}
+
+// ensureRealm creates the realm of the clients if it doesn't exist.
+func (p *keycloakProvider) ensureRealm(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.realmEnsured {
+		return nil
+	}
+	status, err := p.call(ctx, http.MethodGet, p.realmPath(), nil, nil)
+	if status == http.StatusNotFound {
+		_, err = p.call(ctx, http.MethodPost, "/admin/realms", map[string]interface{}{"realm": p.realm, "enabled": true}, nil)
+		err = errors.Wrapf(err, "failed to create realm %s", p.realm)
+	}
+	if err != nil {
+		return err
+	}
+	p.realmEnsured = true
+	return nil
+}
+
+// getClientByName returns the client with the given client ID, or nil if it doesn't exist. It fails if the client isn't managed by the
+// manager.
+func (p *keycloakProvider) getClientByName(ctx context.Context, name string) (*keycloakClient, error) {
+	var clients []keycloakClient
+	status, err := p.call(ctx, http.MethodGet, p.realmPath()+"/clients?clientId="+url.QueryEscape(name), nil, &clients)
+	if status == http.StatusNotFound {
+		// The realm doesn't exist yet, so it doesn't contain the client.
+		return nil, nil //nolint:nilnil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch existing clients")
+	}
+	// The client ID is searched as a substring, so only the exact match is returned.
+	for i := range clients {
+		if clients[i].ClientID == name {
+			if clients[i].Description != managedByMarker {
+				return nil, newUnmanagedApplicationError(name)
+			}
+			return &clients[i], nil
+		}
+	}
+	return nil, nil //nolint:nilnil
+}
+
+// getExistingClientByName returns the client with the given name, and fails if the client doesn't exist.
+func (p *keycloakProvider) getExistingClientByName(ctx context.Context, name string) (*keycloakClient, error) {
+	client, err := p.getClientByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		return nil, errors.Errorf("client %s does not exist", name)
+	}
+	return client, nil
+}
+
+func (p *keycloakProvider) deleteClient(ctx context.Context, id string) error {
+	status, err := p.call(ctx, http.MethodDelete, p.clientPath(id), nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (p *keycloakProvider) realmPath() string {
+	return "/admin/realms/" + url.PathEscape(p.realm)
+}
+
+func (p *keycloakProvider) clientPath(id string) string {
+	return p.realmPath() + "/clients/" + url.PathEscape(id)
+}
+
+// call sends a request to the admin REST API of Keycloak and decodes the response into the output if it isn't nil. It returns the status
+// code of the response.
+func (p *keycloakProvider) call(ctx context.Context, method, path string, input, output interface{}) (int, error) {
+	var body []byte
+	if input != nil {
+		var err error
+		if body, err = json.Marshal(input); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.url+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, errors.Errorf("unexpected status code %d from Keycloak: %s", res.StatusCode,
+			strings.TrimSpace(string(resBody)))
+	}
+	if output != nil && len(resBody) > 0 {
+		return res.StatusCode, errors.Wrap(json.Unmarshal(resBody, output), "failed to decode response of Keycloak")
+	}
+	return res.StatusCode, nil
+}
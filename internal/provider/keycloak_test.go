@@ -0,0 +1,312 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest/fake"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type keycloakRequest struct {
+	method string
+	url    string
+	input  map[string]interface{}
+}
+
+type keycloakResponse struct {
+	statusCode int
+	body       string
+}
+
+// newKeycloakMock returns an HTTP client that issues tokens for the manager and records the requests to the admin REST API. The requests
+// are answered with the given responses in order, all following requests succeed without body.
+func newKeycloakMock(t *testing.T, requests *[]keycloakRequest, responses ...keycloakResponse) *http.Client {
+	t.Helper()
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == "https://keycloak.example.com/realms/master/protocol/openid-connect/token" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`))),
+			}, nil
+		}
+		require.Equal(t, "Bearer access-token", req.Header.Get("Authorization"))
+
+		r := keycloakRequest{method: req.Method, url: req.URL.String()}
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		if len(b) > 0 {
+			require.NoError(t, json.Unmarshal(b, &r.input))
+		}
+		*requests = append(*requests, r)
+
+		res := keycloakResponse{statusCode: http.StatusNoContent}
+		if len(*requests) <= len(responses) {
+			res = responses[len(*requests)-1]
+		}
+		return &http.Response{StatusCode: res.statusCode, Body: io.NopCloser(bytes.NewReader([]byte(res.body)))}, nil
+	})
+}
+
+func newTestKeycloakProvider(t *testing.T, httpClient *http.Client) Provider {
+	t.Helper()
+	k8sClient := kfake.NewClientBuilder().WithObjects(&kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Name: DefaultKeycloakCredentialsSecretName, Namespace: DefaultKeycloakCredentialsSecretNamespace},
+		Data: map[string][]byte{
+			"url":           []byte("https://keycloak.example.com/"),
+			"client_id":     []byte("manager-client-id"),
+			"client_secret": []byte("manager-client-secret"),
+		},
+	}).Build()
+	p, err := NewKeycloakFactory(httpClient, k8sClient, KeycloakConfig{
+		CredentialsSecretNamespace: DefaultKeycloakCredentialsSecretNamespace,
+		CredentialsSecretName:      DefaultKeycloakCredentialsSecretName,
+		AdminRealm:                 DefaultKeycloakAdminRealm,
+		Realm:                      DefaultKeycloakRealm,
+	}).Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+	return p
+}
+
+const (
+	keycloakRealmURL   = "https://keycloak.example.com/admin/realms/eventing"
+	keycloakClientsURL = keycloakRealmURL + "/clients?clientId=runtime-id"
+	keycloakClientURL  = keycloakRealmURL + "/clients/client-uuid"
+	keycloakClients    = `[{"id":"client-uuid","clientId":"runtime-id","description":"Managed by eventing-auth-manager"}]`
+)
+
+func Test_keycloakProvider_CreateApplication(t *testing.T) {
+	createClient := keycloakRequest{method: http.MethodPost, url: keycloakRealmURL + "/clients", input: map[string]interface{}{
+		"clientId":                  "runtime-id",
+		"name":                      "runtime-id",
+		"description":               "Managed by eventing-auth-manager",
+		"enabled":                   true,
+		"protocol":                  "openid-connect",
+		"publicClient":              false,
+		"clientAuthenticatorType":   "client-secret",
+		"serviceAccountsEnabled":    true,
+		"standardFlowEnabled":       false,
+		"directAccessGrantsEnabled": false,
+	}}
+	tests := []struct {
+		name         string
+		responses    []keycloakResponse
+		wantRequests []keycloakRequest
+		wantApp      eamias.Application
+		wantErr      string
+	}{
+		{
+			name: "should create the realm and the client",
+			responses: []keycloakResponse{
+				{statusCode: http.StatusNotFound},
+				{statusCode: http.StatusCreated},
+				{statusCode: http.StatusOK, body: `[]`},
+				{statusCode: http.StatusCreated},
+				{statusCode: http.StatusOK, body: keycloakClients},
+				{statusCode: http.StatusOK, body: `{"type":"secret","value":"client-secret"}`},
+			},
+			wantRequests: []keycloakRequest{
+				{method: http.MethodGet, url: keycloakRealmURL},
+				{method: http.MethodPost, url: "https://keycloak.example.com/admin/realms", input: map[string]interface{}{
+					"realm":   "eventing",
+					"enabled": true,
+				}},
+				{method: http.MethodGet, url: keycloakClientsURL},
+				createClient,
+				{method: http.MethodGet, url: keycloakClientsURL},
+				{method: http.MethodGet, url: keycloakClientURL + "/client-secret"},
+			},
+			wantApp: eamias.NewApplication("client-uuid", "runtime-id", "client-secret",
+				"https://keycloak.example.com/realms/eventing/protocol/openid-connect/token",
+				"https://keycloak.example.com/realms/eventing/protocol/openid-connect/certs"),
+		},
+		{
+			name: "should replace the existing client",
+			responses: []keycloakResponse{
+				{statusCode: http.StatusOK, body: `{"realm":"eventing"}`},
+				{statusCode: http.StatusOK, body: `[{"id":"other-uuid","clientId":"runtime-id-2"},` +
+					`{"id":"old-uuid","clientId":"runtime-id","description":"Managed by eventing-auth-manager"}]`},
+				{statusCode: http.StatusNoContent},
+				{statusCode: http.StatusCreated},
+				{statusCode: http.StatusOK, body: keycloakClients},
+				{statusCode: http.StatusOK, body: `{"type":"secret","value":"client-secret"}`},
+			},
+			wantRequests: []keycloakRequest{
+				{method: http.MethodGet, url: keycloakRealmURL},
+				{method: http.MethodGet, url: keycloakClientsURL},
+				{method: http.MethodDelete, url: keycloakRealmURL + "/clients/old-uuid"},
+				createClient,
+				{method: http.MethodGet, url: keycloakClientsURL},
+				{method: http.MethodGet, url: keycloakClientURL + "/client-secret"},
+			},
+			wantApp: eamias.NewApplication("client-uuid", "runtime-id", "client-secret",
+				"https://keycloak.example.com/realms/eventing/protocol/openid-connect/token",
+				"https://keycloak.example.com/realms/eventing/protocol/openid-connect/certs"),
+		},
+		{
+			name: "should fail if the realm can't be created",
+			responses: []keycloakResponse{
+				{statusCode: http.StatusNotFound},
+				{statusCode: http.StatusForbidden, body: `{"error":"unknown_error"}`},
+			},
+			wantRequests: []keycloakRequest{
+				{method: http.MethodGet, url: keycloakRealmURL},
+				{method: http.MethodPost, url: "https://keycloak.example.com/admin/realms", input: map[string]interface{}{
+					"realm":   "eventing",
+					"enabled": true,
+				}},
+			},
+			wantErr: `failed to create realm eventing: unexpected status code 403 from Keycloak: {"error":"unknown_error"}`,
+		},
+		{
+			name: "should fail without deleting an existing client that isn't managed by the manager",
+			responses: []keycloakResponse{
+				{statusCode: http.StatusOK, body: `{"realm":"eventing"}`},
+				{statusCode: http.StatusOK, body: `[{"id":"old-uuid","clientId":"runtime-id","description":"Payment service"}]`},
+			},
+			wantRequests: []keycloakRequest{
+				{method: http.MethodGet, url: keycloakRealmURL},
+				{method: http.MethodGet, url: keycloakClientsURL},
+			},
+			wantErr: "application runtime-id exists, but isn't managed by eventing-auth-manager",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []keycloakRequest
+			p := newTestKeycloakProvider(t, newKeycloakMock(t, &requests, tt.responses...))
+
+			// when
+			app, err := p.CreateApplication(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantApp, app)
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_keycloakProvider_Rotate(t *testing.T) {
+	// given
+	var requests []keycloakRequest
+	p := newTestKeycloakProvider(t, newKeycloakMock(t, &requests,
+		keycloakResponse{statusCode: http.StatusOK, body: keycloakClients},
+		keycloakResponse{statusCode: http.StatusOK, body: `{"type":"secret","value":"new-client-secret"}`},
+	))
+
+	// when
+	app, err := p.Rotate(context.TODO(), "runtime-id")
+
+	// then the client secret is regenerated
+	require.NoError(t, err)
+	require.Equal(t, []byte("new-client-secret"), app.Credentials()[eamias.SecretKeyClientSecret])
+	require.Equal(t, []keycloakRequest{
+		{method: http.MethodGet, url: keycloakClientsURL},
+		{method: http.MethodPost, url: keycloakClientURL + "/client-secret"},
+	}, requests)
+}
+
+func Test_keycloakProvider_DeleteApplication(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []keycloakResponse
+		wantRequests []keycloakRequest
+		wantErr      string
+	}{
+		{
+			name:      "should delete the client",
+			responses: []keycloakResponse{{statusCode: http.StatusOK, body: keycloakClients}},
+			wantRequests: []keycloakRequest{
+				{method: http.MethodGet, url: keycloakClientsURL},
+				{method: http.MethodDelete, url: keycloakClientURL},
+			},
+		},
+		{
+			name:         "should succeed if the client doesn't exist",
+			responses:    []keycloakResponse{{statusCode: http.StatusOK, body: `[]`}},
+			wantRequests: []keycloakRequest{{method: http.MethodGet, url: keycloakClientsURL}},
+		},
+		{
+			name:         "should succeed if the realm doesn't exist",
+			responses:    []keycloakResponse{{statusCode: http.StatusNotFound}},
+			wantRequests: []keycloakRequest{{method: http.MethodGet, url: keycloakClientsURL}},
+		},
+		{
+			name:         "should fail without deleting a client that isn't managed by the manager",
+			responses:    []keycloakResponse{{statusCode: http.StatusOK, body: `[{"id":"client-uuid","clientId":"runtime-id"}]`}},
+			wantRequests: []keycloakRequest{{method: http.MethodGet, url: keycloakClientsURL}},
+			wantErr:      "application runtime-id exists, but isn't managed by eventing-auth-manager",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []keycloakRequest
+			p := newTestKeycloakProvider(t, newKeycloakMock(t, &requests, tt.responses...))
+
+			// when
+			err := p.DeleteApplication(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_keycloakFactory_Get(t *testing.T) {
+	// given
+	var requests []keycloakRequest
+	k8sClient := kfake.NewClientBuilder().WithObjects(&kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Name: DefaultKeycloakCredentialsSecretName, Namespace: DefaultKeycloakCredentialsSecretNamespace},
+		Data: map[string][]byte{
+			"url":           []byte("https://keycloak.example.com"),
+			"client_id":     []byte("manager-client-id"),
+			"client_secret": []byte("manager-client-secret"),
+		},
+	}).Build()
+	f := NewKeycloakFactory(newKeycloakMock(t, &requests), k8sClient, KeycloakConfig{
+		CredentialsSecretNamespace: DefaultKeycloakCredentialsSecretNamespace,
+		CredentialsSecretName:      DefaultKeycloakCredentialsSecretName,
+		AdminRealm:                 DefaultKeycloakAdminRealm,
+		Realm:                      DefaultKeycloakRealm,
+	})
+	spec := eamapiv1alpha1.EventingAuthSpec{Provider: KeycloakName, Keycloak: &eamapiv1alpha1.KeycloakProviderSpec{Realm: "other-realm"}}
+
+	// when
+	defaultProvider, err := f.Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+	overriddenProvider, err := f.Get(context.TODO(), spec)
+	require.NoError(t, err)
+	cachedProvider, err := f.Get(context.TODO(), spec)
+	require.NoError(t, err)
+
+	// then the realm of the spec overrides the realm of the manager
+	defaultDiscovery, err := defaultProvider.Discovery(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, "https://keycloak.example.com/realms/eventing/protocol/openid-connect/token", defaultDiscovery.TokenURL)
+	overriddenDiscovery, err := overriddenProvider.Discovery(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, "https://keycloak.example.com/realms/other-realm/protocol/openid-connect/token", overriddenDiscovery.TokenURL)
+	require.Same(t, overriddenProvider, cachedProvider)
+}
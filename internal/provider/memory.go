@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/pkg/errors"
+)
+
+const (
+	MemoryName          = "memory"
+	DefaultMemoryIssuer = "http://localhost:8080"
+)
+
+// MemoryConfig defines the behavior of the in-memory provider.
+type MemoryConfig struct {
+	// Issuer is the URL that the token URL and the JWKS URI are derived from.
+	Issuer string
+	// Latency is the duration that every call is delayed by.
+	Latency time.Duration
+	// ErrorRate is the probability between 0 and 1 that a call fails. The failures are pseudo-random with a fixed seed, so that the same
+	// sequence of calls fails in the same way on every run.
+	ErrorRate float64
+}
+
+// memoryApp is an application that is only stored in memory.
+type memoryApp struct {
+	id         string
+	clientID   string
+	generation int
+}
+
+type memoryFactory struct {
+	provider *memoryProvider
+}
+
+// NewMemoryFactory returns the factory of the in-memory provider, which always returns the same provider, since the applications are only
+// stored in it. The provider must only be used for development and tests, since the credentials aren't accepted by any identity provider.
+func NewMemoryFactory(config MemoryConfig) Factory {
+	return memoryFactory{provider: newMemoryProvider(config)}
+}
+
+func (f memoryFactory) Get(_ context.Context, _ eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	return f.provider, nil
+}
+
+// memoryProvider manages the applications in memory. The client IDs and client secrets are derived from the names of the applications and
+// the number of their credentials.
+type memoryProvider struct {
+	config MemoryConfig
+
+	mu     sync.Mutex
+	random *rand.Rand
+	apps   map[string]*memoryApp
+	// generations contains the number of credentials of every application, which isn't reset if the application is recreated, so that
+	// recreated applications get new credentials.
+	generations map[string]int
+}
+
+func newMemoryProvider(config MemoryConfig) *memoryProvider {
+	return &memoryProvider{
+		config:      config,
+		random:      rand.New(rand.NewSource(1)), //nolint:gosec // The failures are simulated and need to be reproducible.
+		apps:        map[string]*memoryApp{},
+		generations: map[string]int{},
+	}
+}
+
+func (p *memoryProvider) CreateApplication(ctx context.Context, name string) (eamias.Application, error) {
+	if err := p.simulate(ctx, "create application"); err != nil {
+		return eamias.Application{}, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	app := &memoryApp{id: "app-" + name, clientID: "client-" + name}
+	p.apps[name] = app
+	return p.newCredentials(name, app), nil
+}
+
+func (p *memoryProvider) UpdateApplication(ctx context.Context, name string) error {
+	if err := p.simulate(ctx, "update application"); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.apps[name]; !ok {
+		return errors.Errorf("application %s does not exist", name)
+	}
+	return nil
+}
+
+func (p *memoryProvider) DeleteApplication(ctx context.Context, name string) error {
+	if err := p.simulate(ctx, "delete application"); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.apps, name)
+	return nil
+}
+
+func (p *memoryProvider) Rotate(ctx context.Context, name string) (eamias.Application, error) {
+	if err := p.simulate(ctx, "rotate client secret"); err != nil {
+		return eamias.Application{}, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	app, ok := p.apps[name]
+	if !ok {
+		return eamias.Application{}, errors.Errorf("application %s does not exist", name)
+	}
+	return p.newCredentials(name, app), nil
+}
+
+func (p *memoryProvider) Discovery(ctx context.Context) (Discovery, error) {
+	if err := p.simulate(ctx, "discovery"); err != nil {
+		return Discovery{}, err
+	}
+	return p.discovery(), nil
+}
+
+func (p *memoryProvider) discovery() Discovery {
+	issuer := strings.TrimSuffix(p.config.Issuer, "/")
+	return Discovery{TokenURL: issuer + "/oauth2/token", JWKSURI: issuer + "/oauth2/certs"}
+}
+
+// newCredentials returns the application with a new client secret. The caller must hold the lock.
+func (p *memoryProvider) newCredentials(name string, app *memoryApp) eamias.Application {
+	p.generations[name]++
+	app.generation = p.generations[name]
+	discovery := p.discovery()
+	return eamias.NewApplication(app.id, app.clientID, fmt.Sprintf("secret-%s-%d", name, app.generation), discovery.TokenURL,
+		discovery.JWKSURI)
+}
+
+// simulate delays the call by the configured latency and fails it with the configured error rate.
+func (p *memoryProvider) simulate(ctx context.Context, operation string) error {
+	if p.config.Latency > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(p.config.Latency):
+		}
+	}
+	p.mu.Lock()
+	fail := p.config.ErrorRate > 0 && p.random.Float64() < p.config.ErrorRate
+	p.mu.Unlock()
+	if fail {
+		return errors.Errorf("simulated failure of %s", operation)
+	}
+	return nil
+}
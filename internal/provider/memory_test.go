@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_memoryProvider(t *testing.T) {
+	// given
+	ctx := context.TODO()
+	p, err := NewMemoryFactory(MemoryConfig{Issuer: DefaultMemoryIssuer}).Get(ctx, eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+
+	// when
+	created, err := p.CreateApplication(ctx, "runtime-id")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, eamias.NewApplication("app-runtime-id", "client-runtime-id", "secret-runtime-id-1",
+		"http://localhost:8080/oauth2/token", "http://localhost:8080/oauth2/certs"), created)
+	require.NoError(t, p.UpdateApplication(ctx, "runtime-id"))
+
+	// when
+	rotated, err := p.Rotate(ctx, "runtime-id")
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret-runtime-id-2"), rotated.Credentials()[eamias.SecretKeyClientSecret])
+
+	// when
+	require.NoError(t, p.DeleteApplication(ctx, "runtime-id"))
+
+	// then
+	require.EqualError(t, p.UpdateApplication(ctx, "runtime-id"), "application runtime-id does not exist")
+	_, err = p.Rotate(ctx, "runtime-id")
+	require.EqualError(t, err, "application runtime-id does not exist")
+	require.NoError(t, p.DeleteApplication(ctx, "runtime-id"))
+
+	// when the application is recreated
+	recreated, err := p.CreateApplication(ctx, "runtime-id")
+
+	// then it gets new credentials
+	require.NoError(t, err)
+	require.Equal(t, []byte("secret-runtime-id-3"), recreated.Credentials()[eamias.SecretKeyClientSecret])
+}
+
+func Test_memoryProvider_simulate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    MemoryConfig
+		ctx       func() context.Context
+		wantErr   string
+		wantDelay time.Duration
+	}{
+		{
+			name:    "should fail every call with error rate 1",
+			config:  MemoryConfig{ErrorRate: 1},
+			ctx:     context.TODO,
+			wantErr: "simulated failure of create application",
+		},
+		{
+			name:      "should delay the call by the latency",
+			config:    MemoryConfig{Latency: 10 * time.Millisecond},
+			ctx:       context.TODO,
+			wantDelay: 10 * time.Millisecond,
+		},
+		{
+			name:   "should stop waiting if the context is done",
+			config: MemoryConfig{Latency: time.Hour},
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.TODO())
+				cancel()
+				return ctx
+			},
+			wantErr: "context canceled",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			p, err := NewMemoryFactory(tt.config).Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+			require.NoError(t, err)
+			start := time.Now()
+
+			// when
+			_, err = p.CreateApplication(tt.ctx(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.GreaterOrEqual(t, time.Since(start), tt.wantDelay)
+		})
+	}
+}
+
+func Test_memoryProvider_simulate_isReproducible(t *testing.T) {
+	// given two providers with the same error rate
+	failures := func() []bool {
+		p, err := NewMemoryFactory(MemoryConfig{ErrorRate: 0.5}).Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+		require.NoError(t, err)
+		var result []bool
+		for i := 0; i < 20; i++ {
+			result = append(result, p.DeleteApplication(context.TODO(), "runtime-id") != nil)
+		}
+		return result
+	}
+
+	// when
+	first, second := failures(), failures()
+
+	// then
+	require.Equal(t, first, second)
+	require.Contains(t, first, true)
+	require.Contains(t, first, false)
+}
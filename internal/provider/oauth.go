@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// newOAuthHTTPClient returns a client that authenticates its requests with the tokens of the client credentials grant of the config. The
+// tokens are requested with the given HTTP client.
+func newOAuthHTTPClient(httpClient *http.Client, config clientcredentials.Config) *http.Client {
+	return newTokenHTTPClient(httpClient, func(ctx context.Context) oauth2.TokenSource {
+		return config.TokenSource(ctx)
+	})
+}
+
+// newTokenHTTPClient returns a client that authenticates its requests with the tokens of the token source. The token source is created with
+// a context that requests the tokens with the given HTTP client. It is reused across calls, so that a token is only requested when it
+// expired.
+func newTokenHTTPClient(httpClient *http.Client, newTokenSource func(ctx context.Context) oauth2.TokenSource) *http.Client {
+	tokenCtx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	return oauth2.NewClient(tokenCtx, oauth2.ReuseTokenSource(nil, newTokenSource(tokenCtx)))
+}
@@ -0,0 +1,440 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	kcorev1 "k8s.io/api/core/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	OktaName                              = "okta"
+	DefaultOktaCredentialsSecretNamespace = "kcp-system"
+	DefaultOktaCredentialsSecretName      = "eventing-auth-okta-creds" //nolint:gosec // This is a name, not a credential.
+	DefaultOktaAuthorizationServer        = "default"
+	oktaDomainKey                         = "domain"
+	oktaClientIDKey                       = "client_id"
+	oktaPrivateKeyKey                     = "private_key"
+	oktaKeyIDKey                          = "key_id"
+	oktaAssertionType                     = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	oktaAssertionValidity                 = 5 * time.Minute
+	oktaSecretStatusActive                = "ACTIVE"
+)
+
+// oktaScopes are the scopes of the Okta management API that the manager requests.
+var oktaScopes = []string{"okta.apps.read", "okta.apps.manage"}
+
+// OktaConfig defines how the applications are managed in Okta.
+type OktaConfig struct {
+	// CredentialsSecretNamespace is the namespace of the secret that contains the domain of the Okta organization and the client ID and
+	// private key of the service app that the manager uses to call the management API.
+	CredentialsSecretNamespace string
+	// CredentialsSecretName is the name of the secret that contains the credentials of the manager.
+	CredentialsSecretName string
+	// AuthorizationServer is the ID of the custom authorization server that issues the tokens of the applications.
+	AuthorizationServer string
+}
+
+// oktaCredentials contains the credentials of the manager in Okta.
+type oktaCredentials struct {
+	domain     string
+	clientID   string
+	privateKey string
+	keyID      string
+}
+
+type oktaFactory struct {
+	httpClient *http.Client
+	k8sClient  kpkgclient.Client
+	config     OktaConfig
+
+	mu          sync.Mutex
+	credentials oktaCredentials
+	// providers contains the providers by their authorization servers.
+	providers map[string]*oktaProvider
+}
+
+// NewOktaFactory returns the factory of the Okta provider. The credentials of the manager are read from the configured secret on every
+// call, and the providers are recreated if they changed. The authorization server can be overridden by spec.okta of the EventingAuth CR.
+func NewOktaFactory(httpClient *http.Client, k8sClient kpkgclient.Client, config OktaConfig) Factory {
+	return &oktaFactory{httpClient: httpClient, k8sClient: k8sClient, config: config}
+}
+
+func (f *oktaFactory) Get(ctx context.Context, spec eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	var secret kcorev1.Secret
+	key := kpkgclient.ObjectKey{Namespace: f.config.CredentialsSecretNamespace, Name: f.config.CredentialsSecretName}
+	if err := f.k8sClient.Get(ctx, key, &secret); err != nil {
+		return nil, err
+	}
+	credentials := oktaCredentials{
+		domain:     strings.TrimSuffix(string(secret.Data[oktaDomainKey]), "/"),
+		clientID:   string(secret.Data[oktaClientIDKey]),
+		privateKey: string(secret.Data[oktaPrivateKeyKey]),
+		keyID:      string(secret.Data[oktaKeyIDKey]),
+	}
+	if credentials.domain == "" || credentials.clientID == "" || credentials.privateKey == "" {
+		return nil, errors.Errorf("keys %s, %s, and %s must be set in the Okta secret", oktaDomainKey, oktaClientIDKey, oktaPrivateKeyKey)
+	}
+
+	config := f.config
+	if spec.Okta != nil && spec.Okta.AuthorizationServer != "" {
+		config.AuthorizationServer = spec.Okta.AuthorizationServer
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.providers == nil || f.credentials != credentials {
+		f.credentials = credentials
+		f.providers = map[string]*oktaProvider{}
+	}
+	p, ok := f.providers[config.AuthorizationServer]
+	if !ok {
+		var err error
+		if p, err = newOktaProvider(f.httpClient, credentials, config); err != nil {
+			return nil, err
+		}
+		f.providers[config.AuthorizationServer] = p
+	}
+	return p, nil
+}
+
+// oktaProvider creates OAuth service apps in Okta with the management API.
+type oktaProvider struct {
+	httpClient          *http.Client
+	url                 string
+	authorizationServer string
+}
+
+func newOktaProvider(httpClient *http.Client, credentials oktaCredentials, config OktaConfig) (*oktaProvider, error) {
+	privateKey, err := parseOktaPrivateKey([]byte(credentials.privateKey))
+	if err != nil {
+		return nil, err
+	}
+	orgURL := "https://" + credentials.domain
+	return &oktaProvider{
+		httpClient: newTokenHTTPClient(httpClient, func(ctx context.Context) oauth2.TokenSource {
+			return &oktaTokenSource{
+				ctx:        ctx,
+				clientID:   credentials.clientID,
+				keyID:      credentials.keyID,
+				privateKey: privateKey,
+				tokenURL:   orgURL + "/oauth2/v1/token",
+			}
+		}),
+		url:                 orgURL,
+		authorizationServer: config.AuthorizationServer,
+	}, nil
+}
+
+// oktaTokenSource requests tokens of the management API with the client credentials grant, whose client is authenticated with a JWT that
+// is signed with the private key of the manager (private_key_jwt).
+type oktaTokenSource struct {
+	ctx        context.Context //nolint:containedctx // The token source can't get the context of the request.
+	clientID   string
+	keyID      string
+	privateKey *rsa.PrivateKey
+	tokenURL   string
+}
+
+func (s *oktaTokenSource) Token() (*oauth2.Token, error) {
+	assertion, err := s.assertion(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	config := clientcredentials.Config{
+		ClientID: s.clientID,
+		TokenURL: s.tokenURL,
+		Scopes:   oktaScopes,
+		EndpointParams: url.Values{
+			"client_assertion_type": []string{oktaAssertionType},
+			"client_assertion":      []string{assertion},
+		},
+		AuthStyle: oauth2.AuthStyleInParams,
+	}
+	return config.Token(s.ctx)
+}
+
+// assertion returns the JWT that authenticates the client of the manager at the token endpoint.
+func (s *oktaTokenSource) assertion(now time.Time) (string, error) {
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	if s.keyID != "" {
+		header["kid"] = s.keyID
+	}
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	claims := map[string]interface{}{
+		"iss": s.clientID,
+		"sub": s.clientID,
+		"aud": s.tokenURL,
+		"iat": now.Unix(),
+		"exp": now.Add(oktaAssertionValidity).Unix(),
+		"jti": base64.RawURLEncoding.EncodeToString(jti),
+	}
+	var parts []string
+	for _, part := range []interface{}{header, claims} {
+		b, err := json.Marshal(part)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, base64.RawURLEncoding.EncodeToString(b))
+	}
+	digest := sha256.Sum256([]byte(strings.Join(parts, ".")))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", errors.Wrap(err, "failed to sign client assertion")
+	}
+	return strings.Join(parts, ".") + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parseOktaPrivateKey parses the PEM encoded RSA private key in PKCS #1 or PKCS #8 format.
+func parseOktaPrivateKey(b []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("private key of the Okta secret is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse private key of the Okta secret")
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key of the Okta secret is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// oktaApp is an application of the management API.
+type oktaApp struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	// Profile contains the description of the application, since the management API has no description of applications.
+	Profile struct {
+		Description string `json:"description"`
+	} `json:"profile"`
+	Credentials struct {
+		OAuthClient struct {
+			ClientID     string `json:"client_id"`
+			ClientSecret string `json:"client_secret,omitempty"`
+		} `json:"oauthClient"`
+	} `json:"credentials"`
+}
+
+// oktaClientSecret is a client secret of an application of the management API.
+type oktaClientSecret struct {
+	ID           string `json:"id"`
+	ClientSecret string `json:"client_secret"`
+	Status       string `json:"status"`
+}
+
+// CreateApplication creates an OAuth service app with the client credentials grant. An existing application with the same label is deleted
+// before, if it is managed by the manager.
+func (p *oktaProvider) CreateApplication(ctx context.Context, name string) (eamias.Application, error) {
+	existingApp, err := p.getAppByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	if existingApp != nil {
+		if err := p.deleteApp(ctx, existingApp.ID); err != nil {
+			return eamias.Application{}, errors.Wrap(err, "failed to delete existing application before creation")
+		}
+	}
+
+	var app oktaApp
+	if _, err := p.call(ctx, http.MethodPost, "/api/v1/apps", map[string]interface{}{
+		"name":       "oidc_client",
+		"label":      name,
+		"signOnMode": "OPENID_CONNECT",
+		"profile":    map[string]string{"description": managedByMarker},
+		"credentials": map[string]interface{}{
+			"oauthClient": map[string]interface{}{"token_endpoint_auth_method": "client_secret_basic"},
+		},
+		"settings": map[string]interface{}{
+			"oauthClient": map[string]interface{}{
+				"application_type": "service",
+				"grant_types":      []string{"client_credentials"},
+				"response_types":   []string{"token"},
+			},
+		},
+	}, &app); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to create application")
+	}
+	return p.newApplication(app, app.Credentials.OAuthClient.ClientSecret), nil
+}
+
+// UpdateApplication updates the label of the existing application with the given name. The management API only replaces applications, so
+// the application is fetched and sent back.
+func (p *oktaProvider) UpdateApplication(ctx context.Context, name string) error {
+	app, err := p.getExistingAppByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	var current map[string]interface{}
+	if _, err := p.call(ctx, http.MethodGet, p.appPath(app.ID), nil, &current); err != nil {
+		return errors.Wrap(err, "failed to fetch application")
+	}
+	current["label"] = name
+	_, err = p.call(ctx, http.MethodPut, p.appPath(app.ID), current, nil)
+	return errors.Wrap(err, "failed to update application")
+}
+
+func (p *oktaProvider) DeleteApplication(ctx context.Context, name string) error {
+	app, err := p.getAppByName(ctx, name)
+	if err != nil || app == nil {
+		return err
+	}
+	return errors.Wrap(p.deleteApp(ctx, app.ID), "failed to delete application")
+}
+
+// Rotate adds a new client secret to the application and deactivates and deletes the previous client secrets after the new one was added.
+func (p *oktaProvider) Rotate(ctx context.Context, name string) (eamias.Application, error) {
+	app, err := p.getExistingAppByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	var previousSecrets []oktaClientSecret
+	if _, err := p.call(ctx, http.MethodGet, p.appPath(app.ID)+"/credentials/secrets", nil, &previousSecrets); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to fetch client secrets")
+	}
+	var secret oktaClientSecret
+	if _, err := p.call(ctx, http.MethodPost, p.appPath(app.ID)+"/credentials/secrets", map[string]string{}, &secret); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to add client secret")
+	}
+	for _, previous := range previousSecrets {
+		secretPath := p.appPath(app.ID) + "/credentials/secrets/" + url.PathEscape(previous.ID)
+		// Only inactive client secrets can be deleted.
+		if previous.Status == oktaSecretStatusActive {
+			if _, err := p.call(ctx, http.MethodPost, secretPath+"/lifecycle/deactivate", nil, nil); err != nil {
+				return eamias.Application{}, errors.Wrap(err, "failed to deactivate previous client secret")
+			}
+		}
+		if _, err := p.call(ctx, http.MethodDelete, secretPath, nil, nil); err != nil {
+			return eamias.Application{}, errors.Wrap(err, "failed to delete previous client secret")
+		}
+	}
+	return p.newApplication(*app, secret.ClientSecret), nil
+}
+
+func (p *oktaProvider) Discovery(_ context.Context) (Discovery, error) {
+	server := p.url + "/oauth2/" + url.PathEscape(p.authorizationServer) + "/v1"
+	return Discovery{TokenURL: server + "/token", JWKSURI: server + "/keys"}, nil
+}
+
+func (p *oktaProvider) newApplication(app oktaApp, clientSecret string) eamias.Application {
+	discovery, _ := p.Discovery(context.Background())
+	return eamias.NewApplication(app.ID, app.Credentials.OAuthClient.ClientID, clientSecret, discovery.TokenURL, discovery.JWKSURI)
+}
+
+// getAppByName returns the application with the given label, or nil if it doesn't exist. It fails if the application isn't managed by the
+// manager. The search of the management API matches the beginning of the label, so only the exact match is returned.
+func (p *oktaProvider) getAppByName(ctx context.Context, name string) (*oktaApp, error) {
+	var apps []oktaApp
+	if _, err := p.call(ctx, http.MethodGet, "/api/v1/apps?q="+url.QueryEscape(name)+"&limit=200", nil, &apps); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch existing applications")
+	}
+	var found *oktaApp
+	for i := range apps {
+		if apps[i].Label != name {
+			continue
+		}
+		if found != nil {
+			return nil, errors.Errorf("found multiple applications with the same name %s", name)
+		}
+		found = &apps[i]
+	}
+	if found != nil && found.Profile.Description != managedByMarker {
+		return nil, newUnmanagedApplicationError(name)
+	}
+	return found, nil
+}
+
+// getExistingAppByName returns the application with the given name, and fails if the application doesn't exist.
+func (p *oktaProvider) getExistingAppByName(ctx context.Context, name string) (*oktaApp, error) {
+	app, err := p.getAppByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if app == nil {
+		return nil, errors.Errorf("application %s does not exist", name)
+	}
+	return app, nil
+}
+
+// deleteApp deactivates the application, since only inactive applications can be deleted, and deletes it afterwards.
+func (p *oktaProvider) deleteApp(ctx context.Context, id string) error {
+	status, err := p.call(ctx, http.MethodPost, p.appPath(id)+"/lifecycle/deactivate", nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	status, err = p.call(ctx, http.MethodDelete, p.appPath(id), nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (p *oktaProvider) appPath(id string) string {
+	return "/api/v1/apps/" + url.PathEscape(id)
+}
+
+// call sends a request to the management API of Okta and decodes the response into the output if it isn't nil. It returns the status code
+// of the response.
+func (p *oktaProvider) call(ctx context.Context, method, path string, input, output interface{}) (int, error) {
+	var body []byte
+	if input != nil {
+		var err error
+		if body, err = json.Marshal(input); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.url+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, errors.Errorf("unexpected status code %d from Okta: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
+	}
+	if output != nil && len(resBody) > 0 {
+		return res.StatusCode, errors.Wrap(json.Unmarshal(resBody, output), "failed to decode response of Okta")
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,326 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest/fake"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type oktaRequest struct {
+	method string
+	url    string
+	input  map[string]interface{}
+}
+
+type oktaResponse struct {
+	statusCode int
+	body       string
+}
+
+// newOktaMock returns an HTTP client that issues tokens for client assertions signed with the given key and records the requests to the
+// management API. The requests are answered with the given responses in order, all following requests succeed without body.
+func newOktaMock(t *testing.T, key *rsa.PrivateKey, requests *[]oktaRequest, responses ...oktaResponse) *http.Client {
+	t.Helper()
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == "https://example.okta.com/oauth2/v1/token" {
+			require.NoError(t, req.ParseForm())
+			require.Equal(t, "client_credentials", req.PostForm.Get("grant_type"))
+			require.Equal(t, "okta.apps.read okta.apps.manage", req.PostForm.Get("scope"))
+			require.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", req.PostForm.Get("client_assertion_type"))
+			requireValidOktaAssertion(t, &key.PublicKey, req.PostForm.Get("client_assertion"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`))),
+			}, nil
+		}
+		require.Equal(t, "Bearer access-token", req.Header.Get("Authorization"))
+
+		r := oktaRequest{method: req.Method, url: req.URL.String()}
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		if len(b) > 0 {
+			require.NoError(t, json.Unmarshal(b, &r.input))
+		}
+		*requests = append(*requests, r)
+
+		res := oktaResponse{statusCode: http.StatusNoContent}
+		if len(*requests) <= len(responses) {
+			res = responses[len(*requests)-1]
+		}
+		return &http.Response{StatusCode: res.statusCode, Body: io.NopCloser(bytes.NewReader([]byte(res.body)))}, nil
+	})
+}
+
+// requireValidOktaAssertion verifies the signature and the claims of the client assertion.
+func requireValidOktaAssertion(t *testing.T, key *rsa.PublicKey, assertion string) {
+	t.Helper()
+	parts := strings.Split(assertion, ".")
+	require.Len(t, parts, 3)
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature))
+
+	b, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims map[string]interface{}
+	require.NoError(t, json.Unmarshal(b, &claims))
+	require.Equal(t, "manager-client-id", claims["iss"])
+	require.Equal(t, "manager-client-id", claims["sub"])
+	require.Equal(t, "https://example.okta.com/oauth2/v1/token", claims["aud"])
+}
+
+func newTestOktaProvider(t *testing.T, key *rsa.PrivateKey, httpClient *http.Client) Provider {
+	t.Helper()
+	privateKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	k8sClient := kfake.NewClientBuilder().WithObjects(&kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Name: DefaultOktaCredentialsSecretName, Namespace: DefaultOktaCredentialsSecretNamespace},
+		Data: map[string][]byte{
+			"domain":      []byte("example.okta.com"),
+			"client_id":   []byte("manager-client-id"),
+			"private_key": privateKey,
+		},
+	}).Build()
+	p, err := NewOktaFactory(httpClient, k8sClient, OktaConfig{
+		CredentialsSecretNamespace: DefaultOktaCredentialsSecretNamespace,
+		CredentialsSecretName:      DefaultOktaCredentialsSecretName,
+		AuthorizationServer:        DefaultOktaAuthorizationServer,
+	}).Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+	return p
+}
+
+const (
+	oktaAppsURL = "https://example.okta.com/api/v1/apps?q=runtime-id&limit=200"
+	oktaAppURL  = "https://example.okta.com/api/v1/apps/app-id"
+	oktaApps    = `[{"id":"app-id","label":"runtime-id","profile":{"description":"Managed by eventing-auth-manager"}}]`
+)
+
+func Test_oktaProvider_CreateApplication(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		responses    []oktaResponse
+		wantRequests []oktaRequest
+		wantApp      eamias.Application
+		wantErr      string
+	}{
+		{
+			name: "should replace the existing application",
+			responses: []oktaResponse{
+				{statusCode: http.StatusOK, body: `[{"id":"other-app-id","label":"runtime-id-2"},` +
+					`{"id":"old-app-id","label":"runtime-id","profile":{"description":"Managed by eventing-auth-manager"}}]`},
+				{statusCode: http.StatusOK, body: `{}`},
+				{statusCode: http.StatusNoContent},
+				{statusCode: http.StatusOK, body: `{"id":"app-id","label":"runtime-id",` +
+					`"credentials":{"oauthClient":{"client_id":"client-id","client_secret":"client-secret"}}}`},
+			},
+			wantRequests: []oktaRequest{
+				{method: http.MethodGet, url: oktaAppsURL},
+				{method: http.MethodPost, url: "https://example.okta.com/api/v1/apps/old-app-id/lifecycle/deactivate"},
+				{method: http.MethodDelete, url: "https://example.okta.com/api/v1/apps/old-app-id"},
+				{method: http.MethodPost, url: "https://example.okta.com/api/v1/apps", input: map[string]interface{}{
+					"name":       "oidc_client",
+					"label":      "runtime-id",
+					"signOnMode": "OPENID_CONNECT",
+					"profile":    map[string]interface{}{"description": "Managed by eventing-auth-manager"},
+					"credentials": map[string]interface{}{
+						"oauthClient": map[string]interface{}{"token_endpoint_auth_method": "client_secret_basic"},
+					},
+					"settings": map[string]interface{}{
+						"oauthClient": map[string]interface{}{
+							"application_type": "service",
+							"grant_types":      []interface{}{"client_credentials"},
+							"response_types":   []interface{}{"token"},
+						},
+					},
+				}},
+			},
+			wantApp: eamias.NewApplication("app-id", "client-id", "client-secret",
+				"https://example.okta.com/oauth2/default/v1/token", "https://example.okta.com/oauth2/default/v1/keys"),
+		},
+		{
+			name: "should fail if the existing applications can't be fetched",
+			responses: []oktaResponse{
+				{statusCode: http.StatusForbidden, body: `{"errorCode":"E0000006"}`},
+			},
+			wantRequests: []oktaRequest{{method: http.MethodGet, url: oktaAppsURL}},
+			wantErr:      `failed to fetch existing applications: unexpected status code 403 from Okta: {"errorCode":"E0000006"}`,
+		},
+		{
+			name: "should fail without deleting an existing application that isn't managed by the manager",
+			responses: []oktaResponse{
+				{statusCode: http.StatusOK, body: `[{"id":"old-app-id","label":"runtime-id","profile":{"description":"Payment service"}}]`},
+			},
+			wantRequests: []oktaRequest{{method: http.MethodGet, url: oktaAppsURL}},
+			wantErr:      "application runtime-id exists, but isn't managed by eventing-auth-manager",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []oktaRequest
+			p := newTestOktaProvider(t, key, newOktaMock(t, key, &requests, tt.responses...))
+
+			// when
+			app, err := p.CreateApplication(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantApp, app)
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_oktaProvider_Rotate(t *testing.T) {
+	// given
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	var requests []oktaRequest
+	p := newTestOktaProvider(t, key, newOktaMock(t, key, &requests,
+		oktaResponse{statusCode: http.StatusOK, body: `[{"id":"app-id","label":"runtime-id",` +
+			`"profile":{"description":"Managed by eventing-auth-manager"},"credentials":{"oauthClient":{"client_id":"client-id"}}}]`},
+		oktaResponse{statusCode: http.StatusOK, body: `[{"id":"old-secret-id","status":"ACTIVE"},{"id":"inactive-secret-id","status":"INACTIVE"}]`},
+		oktaResponse{statusCode: http.StatusOK, body: `{"id":"secret-id","client_secret":"new-client-secret","status":"ACTIVE"}`},
+	))
+
+	// when
+	app, err := p.Rotate(context.TODO(), "runtime-id")
+
+	// then the previous client secrets are deactivated and deleted after the new client secret is added
+	require.NoError(t, err)
+	require.Equal(t, []byte("new-client-secret"), app.Credentials()[eamias.SecretKeyClientSecret])
+	require.Equal(t, []oktaRequest{
+		{method: http.MethodGet, url: oktaAppsURL},
+		{method: http.MethodGet, url: oktaAppURL + "/credentials/secrets"},
+		{method: http.MethodPost, url: oktaAppURL + "/credentials/secrets", input: map[string]interface{}{}},
+		{method: http.MethodPost, url: oktaAppURL + "/credentials/secrets/old-secret-id/lifecycle/deactivate"},
+		{method: http.MethodDelete, url: oktaAppURL + "/credentials/secrets/old-secret-id"},
+		{method: http.MethodDelete, url: oktaAppURL + "/credentials/secrets/inactive-secret-id"},
+	}, requests)
+}
+
+func Test_oktaProvider_DeleteApplication(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		responses    []oktaResponse
+		wantRequests []oktaRequest
+		wantErr      string
+	}{
+		{
+			name:      "should deactivate and delete the application",
+			responses: []oktaResponse{{statusCode: http.StatusOK, body: oktaApps}},
+			wantRequests: []oktaRequest{
+				{method: http.MethodGet, url: oktaAppsURL},
+				{method: http.MethodPost, url: oktaAppURL + "/lifecycle/deactivate"},
+				{method: http.MethodDelete, url: oktaAppURL},
+			},
+		},
+		{
+			name: "should succeed if the application was already deleted",
+			responses: []oktaResponse{
+				{statusCode: http.StatusOK, body: oktaApps},
+				{statusCode: http.StatusNotFound},
+			},
+			wantRequests: []oktaRequest{
+				{method: http.MethodGet, url: oktaAppsURL},
+				{method: http.MethodPost, url: oktaAppURL + "/lifecycle/deactivate"},
+			},
+		},
+		{
+			name:         "should fail without deleting an application that isn't managed by the manager",
+			responses:    []oktaResponse{{statusCode: http.StatusOK, body: `[{"id":"app-id","label":"runtime-id"}]`}},
+			wantRequests: []oktaRequest{{method: http.MethodGet, url: oktaAppsURL}},
+			wantErr:      "application runtime-id exists, but isn't managed by eventing-auth-manager",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []oktaRequest
+			p := newTestOktaProvider(t, key, newOktaMock(t, key, &requests, tt.responses...))
+
+			// when
+			err := p.DeleteApplication(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_parseOktaPrivateKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		pem     []byte
+		wantErr string
+	}{
+		{
+			name: "should parse PKCS #1 keys",
+			pem:  pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		},
+		{
+			name: "should parse PKCS #8 keys",
+			pem:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+		},
+		{
+			name:    "should fail if the key isn't PEM encoded",
+			pem:     []byte("not-a-key"),
+			wantErr: "private key of the Okta secret is not PEM encoded",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			parsed, err := parseOktaPrivateKey(tt.pem)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, key.Equal(parsed))
+		})
+	}
+}
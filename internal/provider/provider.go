@@ -0,0 +1,137 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/pkg/errors"
+)
+
+// managedByMarker is the description of the applications that the manager creates. An existing application with the name of an application
+// is only replaced or deleted if it has the marker, so that applications of others that happen to have the same name are left alone.
+const managedByMarker = "Managed by eventing-auth-manager"
+
+// Discovery contains the endpoints of an identity provider that the consumers of the credentials need.
+type Discovery struct {
+	// TokenURL is the URL of the token endpoint.
+	TokenURL string
+	// JWKSURI is the URI of the JSON Web Key Set that the tokens are verified with.
+	JWKSURI string
+}
+
+// Provider manages the OAuth applications of the SKR clusters in an identity provider. The credentials of the applications are returned as
+// eamias.Application, since its data keys are the keys of the application secret for every provider.
+type Provider interface {
+	// CreateApplication creates the application with the given name. An existing application with the same name is replaced.
+	CreateApplication(ctx context.Context, name string) (eamias.Application, error)
+	// UpdateApplication updates the configuration of the existing application with the given name.
+	UpdateApplication(ctx context.Context, name string) error
+	// DeleteApplication deletes the application with the given name. It does nothing if the application doesn't exist.
+	DeleteApplication(ctx context.Context, name string) error
+	// Rotate creates new credentials for the existing application with the given name and revokes the previous credentials.
+	Rotate(ctx context.Context, name string) (eamias.Application, error)
+	// Discovery returns the endpoints of the identity provider.
+	Discovery(ctx context.Context) (Discovery, error)
+}
+
+// Adopter is implemented by the providers that can take over existing applications that weren't created by the manager.
+type Adopter interface {
+	// AdoptApplication takes over the existing application with the given ID under the given name, so that it is managed like the
+	// applications that the manager created. The application gets new credentials, which revoke its previous credentials. Another
+	// application with the same name is kept, so that its credentials stay valid until the credentials of the adopted application were
+	// delivered.
+	AdoptApplication(ctx context.Context, id, name string) (eamias.Application, error)
+	// DeleteApplicationByID deletes the application with the given ID, which the adopted application with the same name replaced. It does
+	// nothing if the application doesn't exist, and fails with an eamias.ApplicationChangedError if the application doesn't have the name
+	// anymore or isn't managed.
+	DeleteApplicationByID(ctx context.Context, id, name string) error
+}
+
+// Adopt takes over the existing application with the given ID in the provider under the given name. It fails with a ValidationError if the
+// provider can't adopt applications.
+func Adopt(ctx context.Context, p Provider, id, name string) (eamias.Application, error) {
+	adopter, ok := p.(Adopter)
+	if !ok {
+		return eamias.Application{}, newValidationError("the provider of application %s can't adopt existing applications", name)
+	}
+	return adopter.AdoptApplication(ctx, id, name)
+}
+
+// DeleteReplaced deletes the application with the given ID in the provider, which an adopted application with the given name replaced. It
+// fails with a ValidationError if the provider can't adopt applications.
+func DeleteReplaced(ctx context.Context, p Provider, id, name string) error {
+	adopter, ok := p.(Adopter)
+	if !ok {
+		return newValidationError("the provider of application %s can't adopt existing applications", name)
+	}
+	return adopter.DeleteApplicationByID(ctx, id, name)
+}
+
+// Factory returns a provider with the current configuration, e.g. a provider that is recreated because its credentials were rotated. The
+// configuration of the manager is overridden by the provider-specific section of the spec of the EventingAuth CR.
+type Factory interface {
+	Get(ctx context.Context, spec eamapiv1alpha1.EventingAuthSpec) (Provider, error)
+}
+
+// Registry contains the factories of the enabled providers by their names.
+type Registry struct {
+	defaultName string
+	factories   map[string]Factory
+}
+
+// NewRegistry returns a registry of the given factories, whose provider with the default name is used if no provider is requested.
+func NewRegistry(defaultName string, factories map[string]Factory) (*Registry, error) {
+	if _, ok := factories[defaultName]; !ok {
+		return nil, errors.Errorf("default provider %s is not enabled", defaultName)
+	}
+	return &Registry{defaultName: defaultName, factories: factories}, nil
+}
+
+// Name returns the name of the provider that is requested by the spec, or the name of the default provider if the spec doesn't request a
+// provider.
+func (r *Registry) Name(spec eamapiv1alpha1.EventingAuthSpec) string {
+	if spec.Provider == "" {
+		return r.defaultName
+	}
+	return spec.Provider
+}
+
+// Get returns the provider that is requested by the spec, or the default provider if the spec doesn't request a provider.
+func (r *Registry) Get(ctx context.Context, spec eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	name := r.Name(spec)
+	f, ok := r.factories[name]
+	if !ok {
+		return nil, newValidationError("provider %s is not enabled, enabled providers are (%s)", name, strings.Join(r.names(), ", "))
+	}
+	// A section of another provider is rejected, since it is most likely meant for the provider that the application should be managed in.
+	// The sections are named after their providers.
+	for _, section := range []struct {
+		provider string
+		set      bool
+	}{
+		{provider: IASName, set: spec.IAS != nil},
+		{provider: KeycloakName, set: spec.Keycloak != nil},
+		{provider: Auth0Name, set: spec.Auth0 != nil},
+		{provider: XSUAAName, set: spec.XSUAA != nil},
+		{provider: OktaName, set: spec.Okta != nil},
+	} {
+		if section.set && section.provider != name {
+			return nil, newValidationError("spec.%s is only used by provider %s, but the application is managed in provider %s", section.provider,
+				section.provider, name)
+		}
+	}
+	p, err := f.Get(ctx, spec)
+	return p, errors.Wrapf(err, "failed to get provider %s", name)
+}
+
+func (r *Registry) names() []string {
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
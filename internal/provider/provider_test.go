@@ -0,0 +1,553 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type providerStub struct {
+	Provider
+	name string
+}
+
+type factoryStub struct {
+	provider Provider
+}
+
+func (f factoryStub) Get(_ context.Context, _ eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	return f.provider, nil
+}
+
+func Test_Registry_Get(t *testing.T) {
+	ias := providerStub{name: IASName}
+	other := providerStub{name: "other"}
+	keycloak := providerStub{name: KeycloakName}
+	registry, err := NewRegistry(IASName, map[string]Factory{
+		IASName:      factoryStub{provider: ias},
+		"other":      factoryStub{provider: other},
+		KeycloakName: factoryStub{provider: keycloak},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		spec         eamapiv1alpha1.EventingAuthSpec
+		wantProvider Provider
+		wantErr      string
+	}{
+		{
+			name:         "should return the default provider if no provider is requested",
+			wantProvider: ias,
+		},
+		{
+			name:         "should return the requested provider",
+			spec:         eamapiv1alpha1.EventingAuthSpec{Provider: "other"},
+			wantProvider: other,
+		},
+		{
+			name: "should return the requested provider with its section",
+			spec: eamapiv1alpha1.EventingAuthSpec{
+				Provider: KeycloakName,
+				Keycloak: &eamapiv1alpha1.KeycloakProviderSpec{Realm: "realm"},
+			},
+			wantProvider: keycloak,
+		},
+		{
+			name:    "should fail if the requested provider is not enabled",
+			spec:    eamapiv1alpha1.EventingAuthSpec{Provider: "unknown"},
+			wantErr: "provider unknown is not enabled, enabled providers are (ias, keycloak, other)",
+		},
+		{
+			name:    "should fail if the spec contains the section of another provider",
+			spec:    eamapiv1alpha1.EventingAuthSpec{Keycloak: &eamapiv1alpha1.KeycloakProviderSpec{Realm: "realm"}},
+			wantErr: "spec.keycloak is only used by provider keycloak, but the application is managed in provider ias",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			p, err := registry.Get(context.TODO(), tt.spec)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.True(t, IsValidationError(err))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantProvider, p)
+			}
+		})
+	}
+}
+
+func Test_NewRegistry(t *testing.T) {
+	// when the default provider is not enabled
+	_, err := NewRegistry("other", map[string]Factory{IASName: factoryStub{}})
+
+	// then
+	require.EqualError(t, err, "default provider other is not enabled")
+}
+
+type iasClientStub struct {
+	eamias.Client
+	credentials *eamias.Credentials
+}
+
+func (c iasClientStub) GetCredentials() *eamias.Credentials {
+	return c.credentials
+}
+
+func Test_iasFactory_Get(t *testing.T) {
+	// given
+	originalReadCredentials, originalNewClient := eamias.ReadCredentials, eamias.NewClient
+	t.Cleanup(func() { eamias.ReadCredentials, eamias.NewClient = originalReadCredentials, originalNewClient })
+	credentials := eamias.NewCredentials("https://ias.url", "user", "password")
+	eamias.ReadCredentials = func(_, _ string, _ kpkgclient.Client) (*eamias.Credentials, error) {
+		return credentials, nil
+	}
+	var createdClients int
+	eamias.NewClient = func(url, user, password string, _ eamias.ClientOptions) (eamias.Client, error) {
+		createdClients++
+		return iasClientStub{credentials: eamias.NewCredentials(url, user, password)}, nil
+	}
+	f := NewIASFactory(NewIASClientPool(nil))
+
+	// when
+	_, err := f.Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+	_, err = f.Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+
+	// then the client is reused while the credentials are unchanged
+	require.Equal(t, 1, createdClients)
+
+	// when the credentials are rotated
+	credentials = eamias.NewCredentials("https://ias.url", "user", "new-password")
+	_, err = f.Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, 2, createdClients)
+}
+
+func Test_IASClientPool_Get(t *testing.T) {
+	// given
+	originalNewClient := eamias.NewClient
+	t.Cleanup(func() { eamias.NewClient = originalNewClient })
+	var createdOptions []eamias.ClientOptions
+	eamias.NewClient = func(url, user, password string, opts eamias.ClientOptions) (eamias.Client, error) {
+		createdOptions = append(createdOptions, opts)
+		return iasClientStub{credentials: eamias.NewCredentials(url, user, password)}, nil
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, kcorev1.AddToScheme(scheme))
+	require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+	tenant := &eamapiv1alpha1.IASTenant{
+		ObjectMeta: kmetav1.ObjectMeta{Name: "eu"},
+		Spec: eamapiv1alpha1.IASTenantSpec{
+			URL:                  "https://eu.accounts.ondemand.com",
+			CredentialsSecretRef: eamapiv1alpha1.SecretReference{Namespace: "kcp-system", Name: "ias-eu"},
+			RateLimit:            &eamapiv1alpha1.RateLimit{RequestsPerSecond: 10, Burst: 5},
+		},
+	}
+	k8sClient := kfake.NewClientBuilder().WithScheme(scheme).WithObjects(tenant,
+		&kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "ias-eu"},
+			Data:       map[string][]byte{"username": []byte("user"), "password": []byte("password")},
+		},
+		&eamapiv1alpha1.IASTenant{
+			ObjectMeta: kmetav1.ObjectMeta{Name: "us"},
+			Spec: eamapiv1alpha1.IASTenantSpec{
+				URL:                  "https://us.accounts.ondemand.com",
+				CredentialsSecretRef: eamapiv1alpha1.SecretReference{Namespace: "kcp-system", Name: "ias-us"},
+			},
+		},
+		&kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "ias-us"},
+			Data:       map[string][]byte{"username": []byte("user")},
+		},
+	).Build()
+	pool := NewIASClientPool(k8sClient)
+
+	// when
+	c, err := pool.Get(context.TODO(), "eu")
+	require.NoError(t, err)
+	_, err = pool.Get(context.TODO(), "eu")
+	require.NoError(t, err)
+
+	// then the client of the tenant is created once with the URL of the tenant and the credentials of its secret
+	require.Equal(t, eamias.NewCredentials("https://eu.accounts.ondemand.com", "user", "password"), c.GetCredentials())
+	require.Equal(t, []eamias.ClientOptions{{RequestsPerSecond: 10, Burst: 5}}, createdOptions)
+
+	// when the rate limit of the tenant is changed
+	tenant.Spec.RateLimit.RequestsPerSecond = 20
+	require.NoError(t, k8sClient.Update(context.TODO(), tenant))
+	_, err = pool.Get(context.TODO(), "eu")
+
+	// then the client is recreated
+	require.NoError(t, err)
+	require.Equal(t, []eamias.ClientOptions{{RequestsPerSecond: 10, Burst: 5}, {RequestsPerSecond: 20, Burst: 5}}, createdOptions)
+
+	// when the endpoints of the tenant are overridden
+	tenant.Spec.TokenURL = "https://eu.example.com/oauth2/token"
+	tenant.Spec.JWKSURI = "https://eu.example.com/oauth2/certs"
+	require.NoError(t, k8sClient.Update(context.TODO(), tenant))
+	_, err = pool.Get(context.TODO(), "eu")
+
+	// then the client is recreated with the overrides
+	require.NoError(t, err)
+	require.Equal(t, eamias.ClientOptions{RequestsPerSecond: 20, Burst: 5, TokenURL: "https://eu.example.com/oauth2/token",
+		JWKSURI: "https://eu.example.com/oauth2/certs"}, createdOptions[len(createdOptions)-1])
+
+	// when the credentials of the tenant are incomplete
+	_, err = pool.Get(context.TODO(), "us")
+
+	// then
+	require.EqualError(t, err, "keys username and password must be set in the credentials secret of IASTenant us")
+
+	// when the tenant doesn't exist
+	_, err = pool.Get(context.TODO(), "unknown")
+
+	// then
+	require.EqualError(t, err, "IAS tenant unknown is neither defined by an IASTenant CR nor by a secret with label "+
+		"eventingauth.kyma-project.io/ias-credentials=true")
+}
+
+func Test_IASClientPool_Get_labeledSecrets(t *testing.T) {
+	labeledSecret := func(namespace, name, tenant string, data map[string][]byte) *kcorev1.Secret {
+		secret := &kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{Namespace: namespace, Name: name, Labels: map[string]string{IASCredentialsLabel: "true"}},
+			Data:       data,
+		}
+		if tenant != "" {
+			secret.Labels[IASTenantLabel] = tenant
+		}
+		return secret
+	}
+	credentials := map[string][]byte{
+		"url":      []byte("https://eu.accounts.ondemand.com"),
+		"username": []byte("user"),
+		"password": []byte("password"),
+	}
+	tests := []struct {
+		name            string
+		givenSecrets    []kpkgclient.Object
+		wantCredentials *eamias.Credentials
+		wantErr         string
+	}{
+		{
+			name:            "should discover the tenant by the name of the secret",
+			givenSecrets:    []kpkgclient.Object{labeledSecret("kcp-system", "eu", "", credentials)},
+			wantCredentials: eamias.NewCredentials("https://eu.accounts.ondemand.com", "user", "password"),
+		},
+		{
+			name:            "should discover the tenant by the tenant label of the secret",
+			givenSecrets:    []kpkgclient.Object{labeledSecret("other", "ias-creds", "eu", credentials)},
+			wantCredentials: eamias.NewCredentials("https://eu.accounts.ondemand.com", "user", "password"),
+		},
+		{
+			name: "should ignore secrets without the credentials label",
+			givenSecrets: []kpkgclient.Object{&kcorev1.Secret{
+				ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "eu"},
+				Data:       credentials,
+			}},
+			wantErr: "IAS tenant eu is neither defined by an IASTenant CR nor by a secret with label " +
+				"eventingauth.kyma-project.io/ias-credentials=true",
+		},
+		{
+			name: "should fail if multiple secrets define the tenant",
+			givenSecrets: []kpkgclient.Object{
+				labeledSecret("kcp-system", "eu", "", credentials),
+				labeledSecret("other", "ias-creds", "eu", credentials),
+			},
+			wantErr: "found multiple IAS credentials secrets of tenant eu",
+		},
+		{
+			name:         "should fail if the credentials are incomplete",
+			givenSecrets: []kpkgclient.Object{labeledSecret("kcp-system", "eu", "", map[string][]byte{"url": credentials["url"]})},
+			wantErr:      "keys url, username, and password must be set in the IAS credentials secret kcp-system/eu",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			originalNewClient := eamias.NewClient
+			t.Cleanup(func() { eamias.NewClient = originalNewClient })
+			eamias.NewClient = func(url, user, password string, _ eamias.ClientOptions) (eamias.Client, error) {
+				return iasClientStub{credentials: eamias.NewCredentials(url, user, password)}, nil
+			}
+			scheme := runtime.NewScheme()
+			require.NoError(t, kcorev1.AddToScheme(scheme))
+			require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+			pool := NewIASClientPool(kfake.NewClientBuilder().WithScheme(scheme).WithObjects(tt.givenSecrets...).Build())
+
+			// when
+			c, err := pool.Get(context.TODO(), "eu")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				require.True(t, IsValidationError(err))
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantCredentials, c.GetCredentials())
+			}
+		})
+	}
+}
+
+func Test_IASClientPool_Get_removedSecret(t *testing.T) {
+	// given
+	originalNewClient := eamias.NewClient
+	t.Cleanup(func() { eamias.NewClient = originalNewClient })
+	eamias.NewClient = func(url, user, password string, _ eamias.ClientOptions) (eamias.Client, error) {
+		return iasClientStub{credentials: eamias.NewCredentials(url, user, password)}, nil
+	}
+	secret := &kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "eu", Labels: map[string]string{IASCredentialsLabel: "true"}},
+		Data: map[string][]byte{
+			"url":      []byte("https://eu.accounts.ondemand.com"),
+			"username": []byte("user"),
+			"password": []byte("password"),
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, kcorev1.AddToScheme(scheme))
+	require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+	k8sClient := kfake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	pool := NewIASClientPool(k8sClient)
+	_, err := pool.Get(context.TODO(), "eu")
+	require.NoError(t, err)
+
+	// when the secret is removed at runtime
+	require.NoError(t, k8sClient.Delete(context.TODO(), secret))
+	_, err = pool.Get(context.TODO(), "eu")
+
+	// then the client of the tenant is removed
+	require.Error(t, err)
+	require.NotContains(t, pool.clients, "eu")
+}
+
+func Test_IASClientPool_DetectMaintenance(t *testing.T) {
+	// given
+	originalNewClient := eamias.NewClient
+	t.Cleanup(func() { eamias.NewClient = originalNewClient })
+	var createdOptions []eamias.ClientOptions
+	eamias.NewClient = func(url, user, password string, opts eamias.ClientOptions) (eamias.Client, error) {
+		createdOptions = append(createdOptions, opts)
+		return iasClientStub{credentials: eamias.NewCredentials(url, user, password)}, nil
+	}
+	secret := &kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Namespace: "kcp-system", Name: "eu", Labels: map[string]string{IASCredentialsLabel: "true"}},
+		Data: map[string][]byte{
+			"url":      []byte("https://eu.accounts.ondemand.com"),
+			"username": []byte("user"),
+			"password": []byte("password"),
+		},
+	}
+	scheme := runtime.NewScheme()
+	require.NoError(t, kcorev1.AddToScheme(scheme))
+	require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+	k8sClient := kfake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	pool := NewIASClientPool(k8sClient)
+	pool.DetectMaintenance(10 * time.Minute)
+
+	// when
+	_, err := pool.Get(context.TODO(), "eu")
+	require.NoError(t, err)
+	secret.Data["password"] = []byte("rotated")
+	require.NoError(t, k8sClient.Update(context.TODO(), secret))
+	_, err = pool.Get(context.TODO(), "eu")
+	require.NoError(t, err)
+
+	// then the recreated client keeps the maintenance detection of the tenant
+	require.Len(t, createdOptions, 2)
+	require.NotNil(t, createdOptions[0].Maintenance)
+	require.Same(t, createdOptions[0].Maintenance, createdOptions[1].Maintenance)
+	inMaintenance, _ := pool.InMaintenance("eu")
+	require.False(t, inMaintenance)
+}
+
+func Test_SelectIASTenant(t *testing.T) {
+	tenantWithSelector := func(name string, selector *kmetav1.LabelSelector) *eamapiv1alpha1.IASTenant {
+		return &eamapiv1alpha1.IASTenant{ObjectMeta: kmetav1.ObjectMeta{Name: name}, Spec: eamapiv1alpha1.IASTenantSpec{KymaSelector: selector}}
+	}
+	tenants := []kpkgclient.Object{
+		tenantWithSelector("eu", &kmetav1.LabelSelector{MatchLabels: map[string]string{"kyma-project.io/region": "europe-west1"}}),
+		tenantWithSelector("us", &kmetav1.LabelSelector{MatchExpressions: []kmetav1.LabelSelectorRequirement{{
+			Key:      "kyma-project.io/region",
+			Operator: kmetav1.LabelSelectorOpIn,
+			Values:   []string{"us-east1", "us-west1"},
+		}}}),
+		tenantWithSelector("trial", &kmetav1.LabelSelector{MatchLabels: map[string]string{"kyma-project.io/broker-plan-name": "trial"}}),
+		tenantWithSelector("unselective", nil),
+	}
+	tests := []struct {
+		name       string
+		kymaLabels map[string]string
+		wantTenant string
+		wantErr    string
+	}{
+		{
+			name:       "should select the tenant by its match labels",
+			kymaLabels: map[string]string{"kyma-project.io/region": "europe-west1", "kyma-project.io/broker-plan-name": "aws"},
+			wantTenant: "eu",
+		},
+		{
+			name:       "should select the tenant by its match expressions",
+			kymaLabels: map[string]string{"kyma-project.io/region": "us-west1"},
+			wantTenant: "us",
+		},
+		{
+			name:       "should select no tenant if no selector matches",
+			kymaLabels: map[string]string{"kyma-project.io/region": "asia-south1"},
+		},
+		{
+			name:       "should fail if multiple tenants select the Kyma",
+			kymaLabels: map[string]string{"kyma-project.io/region": "europe-west1", "kyma-project.io/broker-plan-name": "trial"},
+			wantErr:    "multiple IASTenants select the Kyma (eu, trial)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			scheme := runtime.NewScheme()
+			require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+			k8sClient := kfake.NewClientBuilder().WithScheme(scheme).WithObjects(tenants...).Build()
+
+			// when
+			tenant, err := SelectIASTenant(context.TODO(), k8sClient, tt.kymaLabels)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantTenant, tenant)
+			}
+		})
+	}
+}
+
+func Test_FallbackIASTenant(t *testing.T) {
+	tests := []struct {
+		name         string
+		tenant       string
+		wantFallback string
+		wantErr      string
+	}{
+		{
+			name:         "should return the fallback tenant of the IASTenant",
+			tenant:       "primary",
+			wantFallback: "secondary",
+		},
+		{
+			name:   "should return no fallback tenant if the IASTenant has none",
+			tenant: "secondary",
+		},
+		{
+			name:   "should return no fallback tenant if the tenant isn't defined by an IASTenant",
+			tenant: "labeled",
+		},
+		{
+			name:    "should fail if the IASTenant is its own fallback tenant",
+			tenant:  "circular",
+			wantErr: "IASTenant circular must not be its own fallback tenant",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			scheme := runtime.NewScheme()
+			require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+			k8sClient := kfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&eamapiv1alpha1.IASTenant{ObjectMeta: kmetav1.ObjectMeta{Name: "primary"}, Spec: eamapiv1alpha1.IASTenantSpec{FallbackTenant: "secondary"}},
+				&eamapiv1alpha1.IASTenant{ObjectMeta: kmetav1.ObjectMeta{Name: "secondary"}},
+				&eamapiv1alpha1.IASTenant{ObjectMeta: kmetav1.ObjectMeta{Name: "circular"}, Spec: eamapiv1alpha1.IASTenantSpec{FallbackTenant: "circular"}},
+			).Build()
+
+			// when
+			fallback, err := FallbackIASTenant(context.TODO(), k8sClient, tt.tenant)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.wantFallback, fallback)
+			}
+		})
+	}
+}
+
+func Test_IASClientPool_TenantsOfSecret(t *testing.T) {
+	secret := func(namespace, name string, labels map[string]string) *kcorev1.Secret {
+		return &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Namespace: namespace, Name: name, Labels: labels}}
+	}
+	tenant := func(name, secretNamespace, secretName string) *eamapiv1alpha1.IASTenant {
+		return &eamapiv1alpha1.IASTenant{
+			ObjectMeta: kmetav1.ObjectMeta{Name: name},
+			Spec: eamapiv1alpha1.IASTenantSpec{
+				CredentialsSecretRef: eamapiv1alpha1.SecretReference{Namespace: secretNamespace, Name: secretName},
+			},
+		}
+	}
+	tests := []struct {
+		name        string
+		secret      *kcorev1.Secret
+		wantTenants []string
+	}{
+		{
+			name:        "should return the empty tenant for the credentials secret of the manager",
+			secret:      secret(DefaultIASCredentialsSecretNamespace, DefaultIASCredentialsSecretName, nil),
+			wantTenants: []string{""},
+		},
+		{
+			name:        "should return the IASTenants that reference the secret",
+			secret:      secret("kcp-system", "eu-creds", nil),
+			wantTenants: []string{"eu", "eu-secondary"},
+		},
+		{
+			name:        "should return the tenant of a labeled secret",
+			secret:      secret("kcp-system", "us", map[string]string{IASCredentialsLabel: "true"}),
+			wantTenants: []string{"us"},
+		},
+		{
+			name:   "should not return the tenant of a labeled secret that is defined by an IASTenant",
+			secret: secret("kcp-system", "other", map[string]string{IASCredentialsLabel: "true", IASTenantLabel: "eu"}),
+		},
+		{
+			name:   "should return no tenants for another secret",
+			secret: secret("kcp-system", "kubeconfig-runtime-id", nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			scheme := runtime.NewScheme()
+			require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+			k8sClient := kfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				tenant("eu", "kcp-system", "eu-creds"),
+				tenant("eu-secondary", "kcp-system", "eu-creds"),
+				tenant("ap", "kcp-system", "ap-creds"),
+			).Build()
+
+			// when
+			tenants, err := NewIASClientPool(k8sClient).TenantsOfSecret(context.TODO(), tt.secret)
+
+			// then
+			require.NoError(t, err)
+			require.ElementsMatch(t, tt.wantTenants, tenants)
+		})
+	}
+}
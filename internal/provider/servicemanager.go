@@ -0,0 +1,375 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/redact"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2/clientcredentials"
+	kcorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// The keys of the service manager binding are the keys of the secret of the SAP BTP service operator.
+	serviceManagerClientIDKey         = "clientid"
+	serviceManagerClientSecretKey     = "clientsecret"
+	serviceManagerURLKey              = "sm_url"
+	serviceManagerTokenURLKey         = "tokenurl"
+	serviceManagerBindingSuffixLength = 5
+)
+
+// serviceManagerCredentials contains the credentials of the service manager binding.
+type serviceManagerCredentials struct {
+	clientID     string
+	clientSecret string
+	smURL        string
+	tokenURL     string
+}
+
+// readServiceManagerCredentials reads the service manager binding from the secret with the given namespace and name.
+func readServiceManagerCredentials(ctx context.Context, k8sClient kpkgclient.Client, namespace, name string) (serviceManagerCredentials,
+	error,
+) {
+	var secret kcorev1.Secret
+	if err := k8sClient.Get(ctx, kpkgclient.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
+		return serviceManagerCredentials{}, err
+	}
+	credentials := serviceManagerCredentials{
+		clientID:     string(secret.Data[serviceManagerClientIDKey]),
+		clientSecret: string(secret.Data[serviceManagerClientSecretKey]),
+		smURL:        strings.TrimSuffix(string(secret.Data[serviceManagerURLKey]), "/"),
+		tokenURL:     strings.TrimSuffix(string(secret.Data[serviceManagerTokenURLKey]), "/"),
+	}
+	if credentials.clientID == "" || credentials.clientSecret == "" || credentials.smURL == "" || credentials.tokenURL == "" {
+		return serviceManagerCredentials{}, errors.Errorf("keys %s, %s, %s, and %s must be set in the service manager secret",
+			serviceManagerClientIDKey, serviceManagerClientSecretKey, serviceManagerURLKey, serviceManagerTokenURLKey)
+	}
+	redact.Secret(credentials.clientSecret)
+	return credentials, nil
+}
+
+// serviceOffering defines how the service instances of a service offering of the service manager are provisioned for the applications.
+type serviceOffering struct {
+	// displayName is the name of the service in error messages.
+	displayName string
+	// catalogName is the name of the service offering in the catalog of the service manager.
+	catalogName string
+	// instanceParameters returns the parameters of the service instance of the application with the given name.
+	instanceParameters func(name string) map[string]interface{}
+	// bindingParameters are the parameters of the service bindings, which are omitted if nil.
+	bindingParameters map[string]interface{}
+	// discovery returns the endpoints of the identity provider with the given URL of the credentials of a service binding.
+	discovery func(url string) Discovery
+}
+
+// serviceManagerProvider provisions a service instance with a service binding for every SKR cluster with the service manager API.
+type serviceManagerProvider struct {
+	httpClient *http.Client
+	smURL      string
+	offering   serviceOffering
+	plan       string
+
+	mu sync.Mutex
+	// planID is the ID of the service plan, which is cached after it was found.
+	planID string
+	// identityProviderURL is the URL of the identity provider, which is the URL of the service manager binding, or the URL of the last
+	// created service binding if the service manager binding doesn't belong to the identity provider.
+	identityProviderURL string
+}
+
+// newServiceManagerProvider returns a provider that provisions the service instances of the offering with the given plan. The URL of the
+// identity provider is used for the discovery, and is empty if it is only known from the service bindings.
+func newServiceManagerProvider(httpClient *http.Client, credentials serviceManagerCredentials, offering serviceOffering, plan,
+	identityProviderURL string,
+) *serviceManagerProvider {
+	oauthConfig := clientcredentials.Config{
+		ClientID:     credentials.clientID,
+		ClientSecret: credentials.clientSecret,
+		TokenURL:     credentials.tokenURL + "/oauth/token",
+	}
+	return &serviceManagerProvider{
+		httpClient:          newOAuthHTTPClient(httpClient, oauthConfig),
+		smURL:               credentials.smURL,
+		offering:            offering,
+		plan:                plan,
+		identityProviderURL: identityProviderURL,
+	}
+}
+
+// serviceManagerResource is a service instance, service binding, service offering, or service plan of the service manager API.
+type serviceManagerResource struct {
+	ID          string                    `json:"id"`
+	Name        string                    `json:"name"`
+	Credentials serviceManagerBindingData `json:"credentials"`
+}
+
+// serviceManagerBindingData contains the credentials of a service binding of an identity provider.
+type serviceManagerBindingData struct {
+	ClientID     string `json:"clientid"`
+	ClientSecret string `json:"clientsecret"`
+	URL          string `json:"url"`
+}
+
+// CreateApplication provisions a service instance with the name of the application and creates a service binding of it. An existing
+// service instance with the same name is deleted before.
+func (p *serviceManagerProvider) CreateApplication(ctx context.Context, name string) (eamias.Application, error) {
+	existingInstance, err := p.getInstanceByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	if existingInstance != nil {
+		if err := p.deleteInstance(ctx, existingInstance.ID); err != nil {
+			return eamias.Application{}, errors.Wrap(err, "failed to delete existing service instance before creation")
+		}
+	}
+
+	planID, err := p.getPlanID(ctx)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	var instance serviceManagerResource
+	if err := p.callSync(ctx, http.MethodPost, "/v1/service_instances", map[string]interface{}{
+		"name":            name,
+		"service_plan_id": planID,
+		"parameters":      p.offering.instanceParameters(name),
+	}, &instance); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to create service instance")
+	}
+	return p.createBinding(ctx, instance)
+}
+
+// UpdateApplication updates the parameters of the existing service instance with the given name.
+func (p *serviceManagerProvider) UpdateApplication(ctx context.Context, name string) error {
+	instance, err := p.getExistingInstanceByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	err = p.callSync(ctx, http.MethodPatch, "/v1/service_instances/"+url.PathEscape(instance.ID), map[string]interface{}{
+		"parameters": p.offering.instanceParameters(name),
+	}, nil)
+	return errors.Wrap(err, "failed to update service instance")
+}
+
+func (p *serviceManagerProvider) DeleteApplication(ctx context.Context, name string) error {
+	instance, err := p.getInstanceByName(ctx, name)
+	if err != nil || instance == nil {
+		return err
+	}
+	return errors.Wrap(p.deleteInstance(ctx, instance.ID), "failed to delete service instance")
+}
+
+// Rotate creates a new service binding of the existing service instance and deletes the previous service bindings after the new one was
+// created.
+func (p *serviceManagerProvider) Rotate(ctx context.Context, name string) (eamias.Application, error) {
+	instance, err := p.getExistingInstanceByName(ctx, name)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	previousBindings, err := p.getBindings(ctx, instance.ID)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	rotated, err := p.createBinding(ctx, *instance)
+	if err != nil {
+		return eamias.Application{}, err
+	}
+	for _, binding := range previousBindings {
+		if err := p.deleteBinding(ctx, binding.ID); err != nil {
+			return eamias.Application{}, errors.Wrap(err, "failed to delete previous service binding")
+		}
+	}
+	return rotated, nil
+}
+
+// Discovery returns the endpoints of the identity provider, which issues the tokens of all service instances of the subaccount.
+func (p *serviceManagerProvider) Discovery(_ context.Context) (Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.identityProviderURL == "" {
+		return Discovery{}, errors.Errorf("the %s tenant is only known after a service binding was created", p.offering.displayName)
+	}
+	return p.offering.discovery(p.identityProviderURL), nil
+}
+
+// createBinding creates a service binding of the service instance, whose name has a random suffix, so that it is unique during rotations.
+func (p *serviceManagerProvider) createBinding(ctx context.Context, instance serviceManagerResource) (eamias.Application, error) {
+	input := map[string]interface{}{
+		"name":                instance.Name + "-" + rand.String(serviceManagerBindingSuffixLength),
+		"service_instance_id": instance.ID,
+	}
+	if p.offering.bindingParameters != nil {
+		input["parameters"] = p.offering.bindingParameters
+	}
+	var binding serviceManagerResource
+	if err := p.callSync(ctx, http.MethodPost, "/v1/service_bindings", input, &binding); err != nil {
+		return eamias.Application{}, errors.Wrap(err, "failed to create service binding")
+	}
+	identityProviderURL := strings.TrimSuffix(binding.Credentials.URL, "/")
+	p.mu.Lock()
+	if p.identityProviderURL == "" {
+		p.identityProviderURL = identityProviderURL
+	}
+	p.mu.Unlock()
+	discovery := p.offering.discovery(identityProviderURL)
+	return eamias.NewApplication(instance.ID, binding.Credentials.ClientID, binding.Credentials.ClientSecret, discovery.TokenURL,
+		discovery.JWKSURI), nil
+}
+
+// getPlanID returns the ID of the configured service plan of the service offering.
+func (p *serviceManagerProvider) getPlanID(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.planID != "" {
+		return p.planID, nil
+	}
+	offering, err := p.getOne(ctx, "/v1/service_offerings", "catalog_name eq '"+p.offering.catalogName+"'")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to fetch %s service offering", p.offering.displayName)
+	}
+	if offering == nil {
+		return "", errors.Errorf("%s service offering is not available", p.offering.displayName)
+	}
+	plan, err := p.getOne(ctx, "/v1/service_plans", "catalog_name eq '"+p.plan+"' and service_offering_id eq '"+offering.ID+"'")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to fetch %s service plan", p.offering.displayName)
+	}
+	if plan == nil {
+		return "", errors.Errorf("%s service plan %s is not available", p.offering.displayName, p.plan)
+	}
+	p.planID = plan.ID
+	return p.planID, nil
+}
+
+func (p *serviceManagerProvider) getInstanceByName(ctx context.Context, name string) (*serviceManagerResource, error) {
+	instance, err := p.getOne(ctx, "/v1/service_instances", "name eq '"+name+"'")
+	return instance, errors.Wrap(err, "failed to fetch existing service instances")
+}
+
+// getExistingInstanceByName returns the service instance with the given name, and fails if the service instance doesn't exist.
+func (p *serviceManagerProvider) getExistingInstanceByName(ctx context.Context, name string) (*serviceManagerResource, error) {
+	instance, err := p.getInstanceByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if instance == nil {
+		return nil, errors.Errorf("service instance %s does not exist", name)
+	}
+	return instance, nil
+}
+
+func (p *serviceManagerProvider) getBindings(ctx context.Context, instanceID string) ([]serviceManagerResource, error) {
+	bindings, err := p.list(ctx, "/v1/service_bindings", "service_instance_id eq '"+instanceID+"'")
+	return bindings, errors.Wrap(err, "failed to fetch service bindings")
+}
+
+// deleteInstance deletes the service bindings of the service instance, since the service manager only deletes service instances without
+// service bindings, and the service instance afterwards.
+func (p *serviceManagerProvider) deleteInstance(ctx context.Context, id string) error {
+	bindings, err := p.getBindings(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, binding := range bindings {
+		if err := p.deleteBinding(ctx, binding.ID); err != nil {
+			return errors.Wrap(err, "failed to delete service binding")
+		}
+	}
+	return p.delete(ctx, "/v1/service_instances/"+url.PathEscape(id))
+}
+
+func (p *serviceManagerProvider) deleteBinding(ctx context.Context, id string) error {
+	return p.delete(ctx, "/v1/service_bindings/"+url.PathEscape(id))
+}
+
+func (p *serviceManagerProvider) delete(ctx context.Context, path string) error {
+	status, err := p.call(ctx, http.MethodDelete, path+"?async=false", nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	if err == nil && status == http.StatusAccepted {
+		return errors.New("service manager deletes asynchronously, retrying later")
+	}
+	return err
+}
+
+// getOne returns the only resource of the given list that matches the field query, or nil if no resource matches.
+func (p *serviceManagerProvider) getOne(ctx context.Context, path, fieldQuery string) (*serviceManagerResource, error) {
+	resources, err := p.list(ctx, path, fieldQuery)
+	if err != nil {
+		return nil, err
+	}
+	switch len(resources) {
+	case 0:
+		return nil, nil //nolint:nilnil
+	case 1:
+		return &resources[0], nil
+	default:
+		return nil, errors.Errorf("found multiple resources matching %s", fieldQuery)
+	}
+}
+
+func (p *serviceManagerProvider) list(ctx context.Context, path, fieldQuery string) ([]serviceManagerResource, error) {
+	var res struct {
+		Items []serviceManagerResource `json:"items"`
+	}
+	query := strings.ReplaceAll(url.QueryEscape(fieldQuery), "+", "%20")
+	if _, err := p.call(ctx, http.MethodGet, path+"?fieldQuery="+query, nil, &res); err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// callSync sends a request that must be processed synchronously by the service manager, which is the case for the service brokers of the
+// identity providers.
+func (p *serviceManagerProvider) callSync(ctx context.Context, method, path string, input, output interface{}) error {
+	status, err := p.call(ctx, method, path+"?async=false", input, output)
+	if err == nil && status == http.StatusAccepted {
+		return errors.New("service manager processes the request asynchronously, retrying later")
+	}
+	return err
+}
+
+// call sends a request to the service manager API and decodes the response into the output if it isn't nil. It returns the status code of
+// the response.
+func (p *serviceManagerProvider) call(ctx context.Context, method, path string, input, output interface{}) (int, error) {
+	var body []byte
+	if input != nil {
+		var err error
+		if body, err = json.Marshal(input); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.smURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := p.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, errors.Errorf("unexpected status code %d from service manager: %s", res.StatusCode,
+			strings.TrimSpace(string(resBody)))
+	}
+	if output != nil && len(resBody) > 0 {
+		return res.StatusCode, errors.Wrap(json.Unmarshal(resBody, output), "failed to decode response of service manager")
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// The credentials of a newly created application might not be accepted by the identity provider right away, so the token is requested
+// multiple times before the smoke test fails.
+var (
+	smokeTestAttempts = 5               //nolint:gochecknoglobals // For testing purposes.
+	smokeTestInterval = time.Second * 2 //nolint:gochecknoglobals // For testing purposes.
+)
+
+// SmokeTest verifies that a token can be requested with the client credentials grant and the credentials of the application from its token
+// URL, which is what the consumers of the application secret do. The placeholder credentials of a dry run aren't tested.
+func SmokeTest(ctx context.Context, httpClient *http.Client, app eamias.Application) error {
+	if isDryRunApplication(app) {
+		log.FromContext(ctx).Info("Dry run: skipped smoke test of application", "application", app.GetID())
+		return nil
+	}
+	credentials := app.Credentials()
+	oauthConfig := clientcredentials.Config{
+		ClientID:     string(credentials[eamias.SecretKeyClientID]),
+		ClientSecret: string(credentials[eamias.SecretKeyClientSecret]),
+		TokenURL:     string(credentials[eamias.SecretKeyTokenURL]),
+		// The auto detection would retry a rejected request with the credentials in the body, which doubles the requests.
+		AuthStyle: oauth2.AuthStyleInHeader,
+	}
+	tokenCtx := context.WithValue(ctx, oauth2.HTTPClient, httpClient)
+
+	var err error
+	for attempt := 1; attempt <= smokeTestAttempts; attempt++ {
+		if _, err = oauthConfig.Token(tokenCtx); err == nil {
+			return nil
+		}
+		if attempt < smokeTestAttempts {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(smokeTestInterval):
+			}
+		}
+	}
+	return errors.Wrapf(err, "failed to request a token with the credentials of application %s", app.GetID())
+}
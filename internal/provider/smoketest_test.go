@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SmokeTest(t *testing.T) {
+	smokeTestInterval = 0
+	tests := []struct {
+		name            string
+		failingRequests int
+		wantRequests    int
+		wantErr         bool
+	}{
+		{
+			name:         "should succeed if a token is issued",
+			wantRequests: 1,
+		},
+		{
+			name:            "should succeed if a token is issued after the credentials were rejected",
+			failingRequests: 2,
+			wantRequests:    3,
+		},
+		{
+			name:            "should fail if the credentials are rejected persistently",
+			failingRequests: smokeTestAttempts,
+			wantRequests:    smokeTestAttempts,
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				clientID, clientSecret, ok := r.BasicAuth()
+				require.True(t, ok)
+				require.Equal(t, "client-id", clientID)
+				require.Equal(t, "client-secret", clientSecret)
+				require.NoError(t, r.ParseForm())
+				require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+				if requests <= tt.failingRequests {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"access_token":"token","token_type":"bearer","expires_in":3600}`))
+			}))
+			defer server.Close()
+			app := eamias.NewApplication("app-id", "client-id", "client-secret", server.URL+"/oauth2/token", server.URL+"/oauth2/certs")
+
+			// when
+			err := SmokeTest(context.TODO(), server.Client(), app)
+
+			// then
+			if tt.wantErr {
+				require.ErrorContains(t, err, "failed to request a token with the credentials of application app-id")
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	XSUAAName                              = "xsuaa"
+	DefaultXSUAACredentialsSecretNamespace = "kcp-system"
+	DefaultXSUAACredentialsSecretName      = "eventing-auth-xsuaa-creds" //nolint:gosec // This is a name, not a credential.
+	DefaultXSUAAPlan                       = "application"
+)
+
+// xsuaaOffering provisions XSUAA service instances, that can only request tokens with the client credentials grant.
+var xsuaaOffering = serviceOffering{
+	displayName: "XSUAA",
+	catalogName: "xsuaa",
+	instanceParameters: func(name string) map[string]interface{} {
+		return map[string]interface{}{
+			"xsappname":   name,
+			"tenant-mode": "dedicated",
+			"oauth2-configuration": map[string]interface{}{
+				"grant-types": []string{"client_credentials"},
+			},
+		}
+	},
+	discovery: func(uaaURL string) Discovery {
+		return Discovery{TokenURL: uaaURL + "/oauth/token", JWKSURI: uaaURL + "/token_keys"}
+	},
+}
+
+// XSUAAConfig defines how the XSUAA service instances are provisioned.
+type XSUAAConfig struct {
+	// CredentialsSecretNamespace is the namespace of the secret that contains the service manager binding that the manager uses to provision
+	// the XSUAA service instances.
+	CredentialsSecretNamespace string
+	// CredentialsSecretName is the name of the secret that contains the service manager binding.
+	CredentialsSecretName string
+	// Plan is the service plan of the XSUAA service instances.
+	Plan string
+}
+
+type xsuaaFactory struct {
+	httpClient *http.Client
+	k8sClient  kpkgclient.Client
+	config     XSUAAConfig
+
+	mu          sync.Mutex
+	credentials serviceManagerCredentials
+	// providers contains the providers by their service plans.
+	providers map[string]*serviceManagerProvider
+}
+
+// NewXSUAAFactory returns the factory of the XSUAA provider. The service manager binding is read from the configured secret on every call,
+// and the providers are recreated if it changed. The service plan can be overridden by spec.xsuaa of the EventingAuth CR.
+func NewXSUAAFactory(httpClient *http.Client, k8sClient kpkgclient.Client, config XSUAAConfig) Factory {
+	return &xsuaaFactory{httpClient: httpClient, k8sClient: k8sClient, config: config}
+}
+
+func (f *xsuaaFactory) Get(ctx context.Context, spec eamapiv1alpha1.EventingAuthSpec) (Provider, error) {
+	credentials, err := readServiceManagerCredentials(ctx, f.k8sClient, f.config.CredentialsSecretNamespace, f.config.CredentialsSecretName)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := f.config.Plan
+	if spec.XSUAA != nil && spec.XSUAA.Plan != "" {
+		plan = spec.XSUAA.Plan
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.providers == nil || f.credentials != credentials {
+		f.credentials = credentials
+		f.providers = map[string]*serviceManagerProvider{}
+	}
+	p, ok := f.providers[plan]
+	if !ok {
+		// The service manager binding belongs to the UAA of the subaccount, which issues the tokens of all XSUAA service instances.
+		p = newServiceManagerProvider(f.httpClient, credentials, xsuaaOffering, plan, credentials.tokenURL)
+		f.providers[plan] = p
+	}
+	return p, nil
+}
@@ -0,0 +1,257 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest/fake"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type serviceManagerRequest struct {
+	method string
+	url    string
+	input  map[string]interface{}
+}
+
+type serviceManagerResponse struct {
+	statusCode int
+	body       string
+}
+
+// newServiceManagerMock returns an HTTP client that issues tokens for the service manager binding and records the requests to the service
+// manager API. The requests are answered with the given responses in order, all following requests succeed without body. The random
+// suffix of the names of service bindings is removed from the recorded requests.
+func newServiceManagerMock(t *testing.T, requests *[]serviceManagerRequest, responses ...serviceManagerResponse) *http.Client {
+	t.Helper()
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == "https://subaccount.authentication.eu10.hana.ondemand.com/oauth/token" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"access_token":"access-token","token_type":"Bearer","expires_in":3600}`))),
+			}, nil
+		}
+		require.Equal(t, "Bearer access-token", req.Header.Get("Authorization"))
+
+		r := serviceManagerRequest{method: req.Method, url: req.URL.String()}
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		if len(b) > 0 {
+			require.NoError(t, json.Unmarshal(b, &r.input))
+		}
+		if name, ok := r.input["name"].(string); ok && r.input["service_instance_id"] != nil {
+			require.Regexp(t, "^runtime-id-[a-z0-9]{5}$", name)
+			r.input["name"] = "runtime-id-xxxxx"
+		}
+		*requests = append(*requests, r)
+
+		res := serviceManagerResponse{statusCode: http.StatusOK}
+		if len(*requests) <= len(responses) {
+			res = responses[len(*requests)-1]
+		}
+		return &http.Response{StatusCode: res.statusCode, Body: io.NopCloser(bytes.NewReader([]byte(res.body)))}, nil
+	})
+}
+
+func newTestXSUAAProvider(t *testing.T, httpClient *http.Client) Provider {
+	t.Helper()
+	k8sClient := kfake.NewClientBuilder().WithObjects(&kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{Name: DefaultXSUAACredentialsSecretName, Namespace: DefaultXSUAACredentialsSecretNamespace},
+		Data: map[string][]byte{
+			"clientid":     []byte("sm-client-id"),
+			"clientsecret": []byte("sm-client-secret"),
+			"sm_url":       []byte("https://service-manager.cfapps.eu10.hana.ondemand.com"),
+			"tokenurl":     []byte("https://subaccount.authentication.eu10.hana.ondemand.com"),
+		},
+	}).Build()
+	p, err := NewXSUAAFactory(httpClient, k8sClient, XSUAAConfig{
+		CredentialsSecretNamespace: DefaultXSUAACredentialsSecretNamespace,
+		CredentialsSecretName:      DefaultXSUAACredentialsSecretName,
+		Plan:                       DefaultXSUAAPlan,
+	}).Get(context.TODO(), eamapiv1alpha1.EventingAuthSpec{})
+	require.NoError(t, err)
+	return p
+}
+
+const (
+	serviceManagerURL          = "https://service-manager.cfapps.eu10.hana.ondemand.com"
+	serviceManagerInstancesURL = serviceManagerURL + "/v1/service_instances?fieldQuery=name%20eq%20%27runtime-id%27"
+	serviceManagerBindingsURL  = serviceManagerURL + "/v1/service_bindings?fieldQuery=service_instance_id%20eq%20%27instance-id%27"
+	serviceManagerBinding      = `{"id":"binding-id","credentials":{"clientid":"sb-runtime-id!t1","clientsecret":"client-secret",` +
+		`"url":"https://subaccount.authentication.eu10.hana.ondemand.com"}}`
+)
+
+func Test_xsuaaProvider_CreateApplication(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []serviceManagerResponse
+		wantRequests []serviceManagerRequest
+		wantApp      eamias.Application
+		wantErr      string
+	}{
+		{
+			name: "should create the service instance and the service binding",
+			responses: []serviceManagerResponse{
+				{statusCode: http.StatusOK, body: `{"items":[]}`},
+				{statusCode: http.StatusOK, body: `{"items":[{"id":"offering-id","name":"xsuaa"}]}`},
+				{statusCode: http.StatusOK, body: `{"items":[{"id":"plan-id","name":"application"}]}`},
+				{statusCode: http.StatusCreated, body: `{"id":"instance-id","name":"runtime-id"}`},
+				{statusCode: http.StatusCreated, body: serviceManagerBinding},
+			},
+			wantRequests: []serviceManagerRequest{
+				{method: http.MethodGet, url: serviceManagerInstancesURL},
+				{method: http.MethodGet, url: serviceManagerURL + "/v1/service_offerings?fieldQuery=catalog_name%20eq%20%27xsuaa%27"},
+				{method: http.MethodGet, url: serviceManagerURL + "/v1/service_plans?fieldQuery=catalog_name%20eq%20%27application%27%20and" +
+					"%20service_offering_id%20eq%20%27offering-id%27"},
+				{method: http.MethodPost, url: serviceManagerURL + "/v1/service_instances?async=false", input: map[string]interface{}{
+					"name":            "runtime-id",
+					"service_plan_id": "plan-id",
+					"parameters": map[string]interface{}{
+						"xsappname":   "runtime-id",
+						"tenant-mode": "dedicated",
+						"oauth2-configuration": map[string]interface{}{
+							"grant-types": []interface{}{"client_credentials"},
+						},
+					},
+				}},
+				{method: http.MethodPost, url: serviceManagerURL + "/v1/service_bindings?async=false", input: map[string]interface{}{
+					"name":                "runtime-id-xxxxx",
+					"service_instance_id": "instance-id",
+				}},
+			},
+			wantApp: eamias.NewApplication("instance-id", "sb-runtime-id!t1", "client-secret",
+				"https://subaccount.authentication.eu10.hana.ondemand.com/oauth/token",
+				"https://subaccount.authentication.eu10.hana.ondemand.com/token_keys"),
+		},
+		{
+			name: "should fail if the service plan isn't available",
+			responses: []serviceManagerResponse{
+				{statusCode: http.StatusOK, body: `{"items":[]}`},
+				{statusCode: http.StatusOK, body: `{"items":[{"id":"offering-id","name":"xsuaa"}]}`},
+				{statusCode: http.StatusOK, body: `{"items":[]}`},
+			},
+			wantRequests: []serviceManagerRequest{
+				{method: http.MethodGet, url: serviceManagerInstancesURL},
+				{method: http.MethodGet, url: serviceManagerURL + "/v1/service_offerings?fieldQuery=catalog_name%20eq%20%27xsuaa%27"},
+				{method: http.MethodGet, url: serviceManagerURL + "/v1/service_plans?fieldQuery=catalog_name%20eq%20%27application%27%20and" +
+					"%20service_offering_id%20eq%20%27offering-id%27"},
+			},
+			wantErr: "XSUAA service plan application is not available",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []serviceManagerRequest
+			p := newTestXSUAAProvider(t, newServiceManagerMock(t, &requests, tt.responses...))
+
+			// when
+			app, err := p.CreateApplication(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantApp, app)
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_xsuaaProvider_Rotate(t *testing.T) {
+	// given
+	var requests []serviceManagerRequest
+	p := newTestXSUAAProvider(t, newServiceManagerMock(t, &requests,
+		serviceManagerResponse{statusCode: http.StatusOK, body: `{"items":[{"id":"instance-id","name":"runtime-id"}]}`},
+		serviceManagerResponse{statusCode: http.StatusOK, body: `{"items":[{"id":"old-binding-id"}]}`},
+		serviceManagerResponse{statusCode: http.StatusCreated, body: serviceManagerBinding},
+	))
+
+	// when
+	app, err := p.Rotate(context.TODO(), "runtime-id")
+
+	// then the previous service binding is deleted after the new service binding is created
+	require.NoError(t, err)
+	require.Equal(t, []byte("client-secret"), app.Credentials()[eamias.SecretKeyClientSecret])
+	require.Equal(t, []serviceManagerRequest{
+		{method: http.MethodGet, url: serviceManagerInstancesURL},
+		{method: http.MethodGet, url: serviceManagerBindingsURL},
+		{method: http.MethodPost, url: serviceManagerURL + "/v1/service_bindings?async=false", input: map[string]interface{}{
+			"name":                "runtime-id-xxxxx",
+			"service_instance_id": "instance-id",
+		}},
+		{method: http.MethodDelete, url: serviceManagerURL + "/v1/service_bindings/old-binding-id?async=false"},
+	}, requests)
+}
+
+func Test_xsuaaProvider_DeleteApplication(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []serviceManagerResponse
+		wantRequests []serviceManagerRequest
+		wantErr      string
+	}{
+		{
+			name: "should delete the service bindings and the service instance",
+			responses: []serviceManagerResponse{
+				{statusCode: http.StatusOK, body: `{"items":[{"id":"instance-id","name":"runtime-id"}]}`},
+				{statusCode: http.StatusOK, body: `{"items":[{"id":"binding-id"}]}`},
+			},
+			wantRequests: []serviceManagerRequest{
+				{method: http.MethodGet, url: serviceManagerInstancesURL},
+				{method: http.MethodGet, url: serviceManagerBindingsURL},
+				{method: http.MethodDelete, url: serviceManagerURL + "/v1/service_bindings/binding-id?async=false"},
+				{method: http.MethodDelete, url: serviceManagerURL + "/v1/service_instances/instance-id?async=false"},
+			},
+		},
+		{
+			name:         "should succeed if the service instance doesn't exist",
+			responses:    []serviceManagerResponse{{statusCode: http.StatusOK, body: `{"items":[]}`}},
+			wantRequests: []serviceManagerRequest{{method: http.MethodGet, url: serviceManagerInstancesURL}},
+		},
+		{
+			name: "should fail if the service instance is deleted asynchronously",
+			responses: []serviceManagerResponse{
+				{statusCode: http.StatusOK, body: `{"items":[{"id":"instance-id","name":"runtime-id"}]}`},
+				{statusCode: http.StatusOK, body: `{"items":[]}`},
+				{statusCode: http.StatusAccepted},
+			},
+			wantRequests: []serviceManagerRequest{
+				{method: http.MethodGet, url: serviceManagerInstancesURL},
+				{method: http.MethodGet, url: serviceManagerBindingsURL},
+				{method: http.MethodDelete, url: serviceManagerURL + "/v1/service_instances/instance-id?async=false"},
+			},
+			wantErr: "failed to delete service instance: service manager deletes asynchronously, retrying later",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []serviceManagerRequest
+			p := newTestXSUAAProvider(t, newServiceManagerMock(t, &requests, tt.responses...))
+
+			// when
+			err := p.DeleteApplication(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
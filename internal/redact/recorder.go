@@ -0,0 +1,35 @@
+package redact
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+)
+
+// eventRecorder redacts the messages of the events of the wrapped recorder.
+type eventRecorder struct {
+	recorder record.EventRecorder
+}
+
+// EventRecorder returns a recorder that emits the events with the given recorder after redacting their messages.
+func EventRecorder(recorder record.EventRecorder) record.EventRecorder {
+	if recorder == nil {
+		return nil
+	}
+	return &eventRecorder{recorder: recorder}
+}
+
+func (r *eventRecorder) Event(object runtime.Object, eventType, reason, message string) {
+	r.recorder.Event(object, eventType, reason, String(message))
+}
+
+func (r *eventRecorder) Eventf(object runtime.Object, eventType, reason, messageFmt string, args ...interface{}) {
+	r.recorder.Event(object, eventType, reason, String(fmt.Sprintf(messageFmt, args...)))
+}
+
+func (r *eventRecorder) AnnotatedEventf(object runtime.Object, annotations map[string]string, eventType, reason, messageFmt string,
+	args ...interface{},
+) {
+	r.recorder.AnnotatedEventf(object, annotations, eventType, reason, "%s", String(fmt.Sprintf(messageFmt, args...)))
+}
@@ -0,0 +1,139 @@
+// Package redact removes credentials from the text that leaves the manager, i.e. log lines, events, and status messages. Credentials are
+// removed by their value, if they were registered with Secret, and by patterns that match credentials in kubeconfigs, PEM blocks,
+// authorization headers, URLs, and key value pairs, so that credentials that are wrapped into error messages don't leak.
+package redact
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Placeholder replaces the redacted credentials.
+const Placeholder = "[REDACTED]"
+
+// minSecretLength is the minimum length of the registered secret values. Shorter values aren't redacted by their value, since they would
+// redact unrelated text.
+const minSecretLength = 8
+
+// secrets are the registered values of credentials.
+var secrets = &secretSet{values: map[string]struct{}{}} //nolint:gochecknoglobals // Shared by all packages that emit text.
+
+// credentialKeys matches the keys of credentials case-insensitively with '-' and '_' as optional separators.
+const credentialKeys = `(?:client[-_]?secret|client[-_]?key[-_]?data|client[-_]?certificate[-_]?data|password|passwd|api[-_]?secret|` +
+	`private[-_]?key|access[-_]?token|refresh[-_]?token|id[-_]?token|token)`
+
+//nolint:gochecknoglobals // Compiled once.
+var patterns = []struct {
+	regexp      *regexp.Regexp
+	replacement string
+}{
+	// PEM blocks, e.g. private keys and client certificates, also if they are cut off.
+	{
+		regexp:      regexp.MustCompile(`-----BEGIN [A-Z0-9 ]+-----[\s\S]*?(-----END [A-Z0-9 ]+-----|$)`),
+		replacement: Placeholder,
+	},
+	// Authorization headers.
+	{
+		regexp:      regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`),
+		replacement: "$1 " + Placeholder,
+	},
+	// Passwords in the user info of URLs.
+	{
+		regexp:      regexp.MustCompile(`(://[^:/@\s]+:)[^@/\s]+@`),
+		replacement: "${1}" + Placeholder + "@",
+	},
+	// JSON fields, also if the JSON is escaped, e.g. in the message of an error that is logged as JSON.
+	{
+		regexp:      regexp.MustCompile(`(?i)(\\?"` + credentialKeys + `\\?"\s*:\s*\\?")[^"\\]*`),
+		replacement: "${1}" + Placeholder,
+	},
+	// YAML fields at the start of a line, e.g. the token or the client key of a kubeconfig, also if the newlines are escaped.
+	{
+		regexp:      regexp.MustCompile(`(?i)((?:^|\n|\\n)\s*-?\s*` + credentialKeys + `\s*:\s*)[^\s\\]+`),
+		replacement: "${1}" + Placeholder,
+	},
+	// Parameters of query strings and form bodies.
+	{
+		regexp:      regexp.MustCompile(`(?i)(\b` + credentialKeys + `=)[^\s&"',;]+`),
+		replacement: "${1}" + Placeholder,
+	},
+}
+
+// Secret registers the value of a credential, e.g. the client secret of an application or the password of a technical user, so that it
+// is redacted wherever it appears.
+func Secret(value string) {
+	if len(value) < minSecretLength {
+		return
+	}
+	secrets.add(value)
+}
+
+// String returns the text without the registered credentials and the credentials that match the patterns.
+func String(text string) string {
+	text = secrets.redact(text)
+	for _, p := range patterns {
+		text = p.regexp.ReplaceAllString(text, p.replacement)
+	}
+	return text
+}
+
+// Error returns an error, whose message is the redacted message of the given error. The given error is wrapped, so that it can still be
+// inspected with errors.Is and errors.As. It returns nil if the given error is nil.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &redactedError{err: err}
+}
+
+type redactedError struct {
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return String(e.err.Error())
+}
+
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// secretSet stores the registered values, and a replacer of the values that is rebuilt when a value is added.
+type secretSet struct {
+	mu       sync.RWMutex
+	values   map[string]struct{}
+	replacer *strings.Replacer
+}
+
+func (s *secretSet) add(value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[value]; ok {
+		return
+	}
+	s.values[value] = struct{}{}
+
+	// Longer values are replaced first, so that a value that contains another value is redacted completely.
+	values := make([]string, 0, len(s.values))
+	for v := range s.values {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+	oldnew := make([]string, 0, 2*len(values))
+	for _, v := range values {
+		oldnew = append(oldnew, v, Placeholder)
+	}
+	s.replacer = strings.NewReplacer(oldnew...)
+}
+
+func (s *secretSet) redact(text string) string {
+	s.mu.RLock()
+	replacer := s.replacer
+	s.mu.RUnlock()
+	if replacer == nil {
+		return text
+	}
+	return replacer.Replace(text)
+}
@@ -0,0 +1,179 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsv4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultAWSSecretsManagerNameTemplate = "eventing-auth/{{ .RuntimeID }}"
+	awsSecretsManagerService             = "secretsmanager"
+	awsSecretsManagerContentType         = "application/x-amz-json-1.1"
+	awsResourceNotFound                  = "ResourceNotFoundException"
+)
+
+// AWSSecretsManagerConfig defines how the credentials are written to AWS Secrets Manager.
+type AWSSecretsManagerConfig struct {
+	// Region is the AWS region of the secrets.
+	Region string
+	// Endpoint overrides the endpoint of AWS Secrets Manager, e.g. for VPC endpoints. Defaults to the public endpoint of the region.
+	Endpoint string
+	// NameTemplate is the Go template of the name of the secret. The runtime ID can be referenced with '{{ .RuntimeID }}'.
+	NameTemplate string
+}
+
+type awsSecretsManagerSink struct {
+	httpClient   *http.Client
+	credentials  aws.CredentialsProvider
+	signer       *awsv4.Signer
+	config       AWSSecretsManagerConfig
+	nameTemplate skr.RuntimeIDTemplate
+	now          func() time.Time
+}
+
+// NewAWSSecretsManagerSink returns a sink that writes the credentials as JSON object into a secret of AWS Secrets Manager. The AWS credentials
+// are resolved with the default credential chain of the AWS SDK, so that IAM roles for service accounts (IRSA) and EKS Pod Identity are used
+// when the manager runs on EKS.
+func NewAWSSecretsManagerSink(ctx context.Context, httpClient *http.Client, config AWSSecretsManagerConfig) (Sink, error) {
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(config.Region), awsconfig.WithHTTPClient(httpClient))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load AWS configuration")
+	}
+	return newAWSSecretsManagerSink(httpClient, awsConfig.Credentials, config)
+}
+
+func newAWSSecretsManagerSink(httpClient *http.Client, credentials aws.CredentialsProvider, config AWSSecretsManagerConfig) (*awsSecretsManagerSink, error) {
+	if config.Region == "" {
+		return nil, errors.New("region of AWS Secrets Manager must not be empty")
+	}
+	if credentials == nil {
+		return nil, errors.New("no AWS credentials found")
+	}
+	t, err := skr.NewRuntimeIDTemplate("awsSecretName", config.NameTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid name template of AWS Secrets Manager")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com", awsSecretsManagerService, config.Region)
+	}
+	return &awsSecretsManagerSink{
+		httpClient:   httpClient,
+		credentials:  credentials,
+		signer:       awsv4.NewSigner(),
+		config:       config,
+		nameTemplate: t,
+		now:          time.Now,
+	}, nil
+}
+
+func (s *awsSecretsManagerSink) Name() string {
+	return "aws-secrets-manager"
+}
+
+func (s *awsSecretsManagerSink) Write(ctx context.Context, skrClusterID string, app eamias.Application) error {
+	name, err := s.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render name of the secret")
+	}
+	value, err := credentialJSON(app)
+	if err != nil {
+		return err
+	}
+
+	// A new version of the secret is stored on every write, so that consumers can still read the previous credentials while they are rotated.
+	err = s.call(ctx, "PutSecretValue", map[string]interface{}{"SecretId": name, "SecretString": value})
+	if !isAWSError(err, awsResourceNotFound) {
+		return err
+	}
+	return s.call(ctx, "CreateSecret", map[string]interface{}{
+		"Name":         name,
+		"SecretString": value,
+		"Tags":         []map[string]string{{"Key": skr.ManagedByLabelKey, "Value": skr.ManagedByLabelValue}},
+	})
+}
+
+func (s *awsSecretsManagerSink) Delete(ctx context.Context, skrClusterID string) error {
+	name, err := s.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render name of the secret")
+	}
+	// The secret is deleted without recovery window, so that a secret with the same name can be created immediately if the SKR cluster is re-added.
+	err = s.call(ctx, "DeleteSecret", map[string]interface{}{"SecretId": name, "ForceDeleteWithoutRecovery": true})
+	if isAWSError(err, awsResourceNotFound) {
+		return nil
+	}
+	return err
+}
+
+// awsError is returned if AWS Secrets Manager rejects a request.
+type awsError struct {
+	StatusCode int
+	Type       string `json:"__type"`
+	Message    string `json:"message"`
+}
+
+func (e *awsError) Error() string {
+	return fmt.Sprintf("unexpected status code %d from AWS Secrets Manager: %s: %s", e.StatusCode, e.Type, e.Message)
+}
+
+func isAWSError(err error, errorType string) bool {
+	var awsErr *awsError
+	// The type can be prefixed with the namespace of the service, e.g. 'com.amazonaws.secretsmanager#ResourceNotFoundException'.
+	return errors.As(err, &awsErr) && (awsErr.Type == errorType || strings.HasSuffix(awsErr.Type, "#"+errorType))
+}
+
+// call sends a signed request of the given action of the AWS Secrets Manager API.
+func (s *awsSecretsManagerSink) call(ctx context.Context, action string, input interface{}) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", awsSecretsManagerContentType)
+	req.Header.Set("X-Amz-Target", "secretsmanager."+action)
+
+	credentials, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve AWS credentials")
+	}
+	payloadHash := sha256.Sum256(body)
+	if err := s.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(payloadHash[:]), awsSecretsManagerService, s.config.Region, s.now()); err != nil {
+		return errors.Wrap(err, "failed to sign request")
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		awsErr := &awsError{StatusCode: res.StatusCode}
+		if err := json.Unmarshal(resBody, awsErr); err != nil {
+			awsErr.Message = strings.TrimSpace(string(resBody))
+		}
+		return awsErr
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest/fake"
+)
+
+type awsRequest struct {
+	action string
+	input  map[string]interface{}
+}
+
+// newAWSSecretsManagerMock returns an HTTP client that records the actions sent to AWS Secrets Manager and responds to them with the
+// given errors, e.g. 'ResourceNotFoundException'. Actions without error succeed.
+func newAWSSecretsManagerMock(t *testing.T, requests *[]awsRequest, errorTypes map[string]string) *http.Client {
+	t.Helper()
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, "https://secretsmanager.eu-central-1.amazonaws.com", req.URL.String())
+		require.Equal(t, awsSecretsManagerContentType, req.Header.Get("Content-Type"))
+		require.True(t, strings.HasPrefix(req.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=access-key/"))
+		require.Contains(t, req.Header.Get("Authorization"), "/eu-central-1/secretsmanager/aws4_request")
+
+		r := awsRequest{action: strings.TrimPrefix(req.Header.Get("X-Amz-Target"), "secretsmanager.")}
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &r.input))
+		*requests = append(*requests, r)
+
+		if errorType, ok := errorTypes[r.action]; ok {
+			body := `{"__type":"` + errorType + `","message":"failed"}`
+			return &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(bytes.NewReader([]byte(body)))}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte(`{}`)))}, nil
+	})
+}
+
+func newTestAWSSecretsManagerSink(t *testing.T, httpClient *http.Client) *awsSecretsManagerSink {
+	t.Helper()
+	credentials := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "access-key", SecretAccessKey: "secret-key", SessionToken: "session-token"}, nil
+	})
+	s, err := newAWSSecretsManagerSink(httpClient, credentials, AWSSecretsManagerConfig{
+		Region:       "eu-central-1",
+		NameTemplate: DefaultAWSSecretsManagerNameTemplate,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func Test_awsSecretsManagerSink_Write(t *testing.T) {
+	app := eamias.NewApplication("app-id", "client-id", "client-secret", "https://token.url", "https://certs.url")
+	credentials := `{"certs_url":"https://certs.url","client_id":"client-id","client_secret":"client-secret","token_url":"https://token.url"}`
+
+	tests := []struct {
+		name         string
+		errorTypes   map[string]string
+		wantRequests []awsRequest
+		wantErr      string
+	}{
+		{
+			name: "should put a new version of an existing secret",
+			wantRequests: []awsRequest{
+				{action: "PutSecretValue", input: map[string]interface{}{"SecretId": "eventing-auth/runtime-id", "SecretString": credentials}},
+			},
+		},
+		{
+			name:       "should create the secret if it doesn't exist",
+			errorTypes: map[string]string{"PutSecretValue": "com.amazonaws.secretsmanager#ResourceNotFoundException"},
+			wantRequests: []awsRequest{
+				{action: "PutSecretValue", input: map[string]interface{}{"SecretId": "eventing-auth/runtime-id", "SecretString": credentials}},
+				{action: "CreateSecret", input: map[string]interface{}{
+					"Name":         "eventing-auth/runtime-id",
+					"SecretString": credentials,
+					"Tags":         []interface{}{map[string]interface{}{"Key": "app.kubernetes.io/managed-by", "Value": "eventing-auth-manager"}},
+				}},
+			},
+		},
+		{
+			name:       "should fail if the secret can't be written",
+			errorTypes: map[string]string{"PutSecretValue": "AccessDeniedException"},
+			wantRequests: []awsRequest{
+				{action: "PutSecretValue", input: map[string]interface{}{"SecretId": "eventing-auth/runtime-id", "SecretString": credentials}},
+			},
+			wantErr: "unexpected status code 400 from AWS Secrets Manager: AccessDeniedException: failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []awsRequest
+			s := newTestAWSSecretsManagerSink(t, newAWSSecretsManagerMock(t, &requests, tt.errorTypes))
+
+			// when
+			err := s.Write(context.TODO(), "runtime-id", app)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_awsSecretsManagerSink_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		errorTypes map[string]string
+		wantErr    string
+	}{
+		{
+			name: "should delete the secret without recovery window",
+		},
+		{
+			name:       "should succeed if the secret doesn't exist",
+			errorTypes: map[string]string{"DeleteSecret": "ResourceNotFoundException"},
+		},
+		{
+			name:       "should fail if the secret can't be deleted",
+			errorTypes: map[string]string{"DeleteSecret": "AccessDeniedException"},
+			wantErr:    "unexpected status code 400 from AWS Secrets Manager: AccessDeniedException: failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []awsRequest
+			s := newTestAWSSecretsManagerSink(t, newAWSSecretsManagerMock(t, &requests, tt.errorTypes))
+
+			// when
+			err := s.Delete(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, []awsRequest{
+				{action: "DeleteSecret", input: map[string]interface{}{"SecretId": "eventing-auth/runtime-id", "ForceDeleteWithoutRecovery": true}},
+			}, requests)
+		})
+	}
+}
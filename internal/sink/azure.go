@@ -0,0 +1,273 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	DefaultAzureKeyVaultNameTemplate = "eventing-auth-{{ .RuntimeID }}"
+	azureKeyVaultAPIVersion          = "7.4"
+	azureKeyVaultScope               = "https://vault.azure.net/.default"
+	azureKeyVaultResource            = "https://vault.azure.net"
+	azureDefaultAuthorityHost        = "https://login.microsoftonline.com/"
+	azureIMDSTokenURL                = "http://169.254.169.254/metadata/identity/oauth2/token"
+	azureManagedByTagKey             = "managed-by"
+	// The environment variables are injected into the pod by the mutating webhook of Azure Workload Identity.
+	azureClientIDEnv           = "AZURE_CLIENT_ID"
+	azureTenantIDEnv           = "AZURE_TENANT_ID"
+	azureFederatedTokenFileEnv = "AZURE_FEDERATED_TOKEN_FILE"
+	azureAuthorityHostEnv      = "AZURE_AUTHORITY_HOST"
+)
+
+// AzureKeyVaultConfig defines how the credentials are written to Azure Key Vault.
+type AzureKeyVaultConfig struct {
+	// VaultURL is the URL of the key vault, e.g. 'https://example.vault.azure.net'.
+	VaultURL string
+	// ClientID is the client ID of the managed identity. Defaults to the client ID of Azure Workload Identity or to the system-assigned
+	// managed identity.
+	ClientID string
+	// NameTemplate is the Go template of the name of the secret. The runtime ID can be referenced with '{{ .RuntimeID }}'.
+	NameTemplate string
+}
+
+type azureKeyVaultSink struct {
+	httpClient   *http.Client
+	tokenSource  oauth2.TokenSource
+	config       AzureKeyVaultConfig
+	nameTemplate skr.RuntimeIDTemplate
+}
+
+// NewAzureKeyVaultSink returns a sink that writes the credentials as JSON object into a secret of Azure Key Vault. The manager authenticates
+// with Azure Workload Identity if the federated token is injected into the pod, and with the managed identity of the node otherwise.
+func NewAzureKeyVaultSink(httpClient *http.Client, config AzureKeyVaultConfig) (Sink, error) {
+	return newAzureKeyVaultSink(httpClient, oauth2.ReuseTokenSource(nil, newAzureManagedIdentityTokenSource(httpClient, config.ClientID)), config)
+}
+
+func newAzureKeyVaultSink(httpClient *http.Client, tokenSource oauth2.TokenSource, config AzureKeyVaultConfig) (*azureKeyVaultSink, error) {
+	if config.VaultURL == "" {
+		return nil, errors.New("URL of Azure Key Vault must not be empty")
+	}
+	t, err := skr.NewRuntimeIDTemplate("azureSecretName", config.NameTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid name template of Azure Key Vault")
+	}
+	return &azureKeyVaultSink{
+		httpClient:   httpClient,
+		tokenSource:  tokenSource,
+		config:       config,
+		nameTemplate: t,
+	}, nil
+}
+
+func (s *azureKeyVaultSink) Name() string {
+	return "azure-key-vault"
+}
+
+func (s *azureKeyVaultSink) Write(ctx context.Context, skrClusterID string, app eamias.Application) error {
+	name, err := s.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render name of the secret")
+	}
+	value, err := credentialJSON(app)
+	if err != nil {
+		return err
+	}
+
+	// Setting a secret creates a new version of the secret, so that the credentials of previous rotations remain available by their version.
+	secret := map[string]interface{}{
+		"value":       value,
+		"contentType": "application/json",
+		"tags":        map[string]string{azureManagedByTagKey: skr.ManagedByLabelValue},
+	}
+	status, err := s.call(ctx, http.MethodPut, s.url("secrets", name), secret)
+	if status != http.StatusConflict {
+		return err
+	}
+	// Key Vault rejects the secret if a deleted secret with the same name is retained by soft-delete, e.g. because the SKR cluster was
+	// re-added. The deleted secret is recovered, and the write is retried with the next reconciliation since recovering is asynchronous.
+	if _, recoverErr := s.call(ctx, http.MethodPost, s.url("deletedsecrets", name+"/recover"), nil); recoverErr != nil {
+		return errors.Wrap(recoverErr, "failed to recover deleted secret")
+	}
+	return errors.Wrap(err, "deleted secret is being recovered")
+}
+
+func (s *azureKeyVaultSink) Delete(ctx context.Context, skrClusterID string) error {
+	name, err := s.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render name of the secret")
+	}
+	// Deleting the secret deletes all of its versions. If soft-delete is enabled, the secret is retained until the retention period expires.
+	status, err := s.call(ctx, http.MethodDelete, s.url("secrets", name), nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *azureKeyVaultSink) url(collection, name string) string {
+	return fmt.Sprintf("%s/%s/%s?api-version=%s", strings.TrimSuffix(s.config.VaultURL, "/"), collection, name, azureKeyVaultAPIVersion)
+}
+
+// call sends an authenticated request to the Azure Key Vault API and returns the status code of the response.
+func (s *azureKeyVaultSink) call(ctx context.Context, method, url string, input interface{}) (int, error) {
+	var body []byte
+	if input != nil {
+		var err error
+		if body, err = json.Marshal(input); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	token, err := s.tokenSource.Token()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to retrieve Azure access token")
+	}
+	token.SetAuthHeader(req)
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	return readAzureResponse(res, "Azure Key Vault", nil)
+}
+
+// azureManagedIdentityTokenSource returns access tokens of Azure Key Vault for the managed identity of the manager.
+type azureManagedIdentityTokenSource struct {
+	httpClient *http.Client
+	clientID   string
+	getenv     func(string) string
+	readFile   func(string) ([]byte, error)
+	now        func() time.Time
+}
+
+func newAzureManagedIdentityTokenSource(httpClient *http.Client, clientID string) *azureManagedIdentityTokenSource {
+	return &azureManagedIdentityTokenSource{
+		httpClient: httpClient,
+		clientID:   clientID,
+		getenv:     os.Getenv,
+		readFile:   os.ReadFile,
+		now:        time.Now,
+	}
+}
+
+func (ts *azureManagedIdentityTokenSource) Token() (*oauth2.Token, error) {
+	if tokenFile := ts.getenv(azureFederatedTokenFileEnv); tokenFile != "" {
+		return ts.workloadIdentityToken(tokenFile)
+	}
+	return ts.imdsToken()
+}
+
+// workloadIdentityToken exchanges the federated service account token of Azure Workload Identity for an access token.
+func (ts *azureManagedIdentityTokenSource) workloadIdentityToken(tokenFile string) (*oauth2.Token, error) {
+	assertion, err := ts.readFile(tokenFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read federated token")
+	}
+	clientID := ts.clientID
+	if clientID == "" {
+		clientID = ts.getenv(azureClientIDEnv)
+	}
+	authorityHost := ts.getenv(azureAuthorityHostEnv)
+	if authorityHost == "" {
+		authorityHost = azureDefaultAuthorityHost
+	}
+
+	form := url.Values{
+		"grant_type":            {"client_credentials"},
+		"client_id":             {clientID},
+		"scope":                 {azureKeyVaultScope},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+	}
+	tokenURL := fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimSuffix(authorityHost, "/"), ts.getenv(azureTenantIDEnv))
+	req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode())) //nolint:noctx // oauth2.TokenSource has no context.
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return ts.requestToken(req)
+}
+
+// imdsToken requests an access token of the managed identity of the node from the Azure Instance Metadata Service.
+func (ts *azureManagedIdentityTokenSource) imdsToken() (*oauth2.Token, error) {
+	query := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {azureKeyVaultResource},
+	}
+	if ts.clientID != "" {
+		query.Set("client_id", ts.clientID)
+	}
+	req, err := http.NewRequest(http.MethodGet, azureIMDSTokenURL+"?"+query.Encode(), nil) //nolint:noctx // oauth2.TokenSource has no context.
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata", "true")
+	return ts.requestToken(req)
+}
+
+func (ts *azureManagedIdentityTokenSource) requestToken(req *http.Request) (*oauth2.Token, error) {
+	requestedAt := ts.now()
+	res, err := ts.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request Azure access token")
+	}
+
+	// IMDS returns the expiry as string, while Microsoft Entra ID returns it as number.
+	var token struct {
+		AccessToken string      `json:"access_token"`
+		ExpiresIn   json.Number `json:"expires_in"`
+	}
+	if _, err := readAzureResponse(res, "Azure token endpoint", &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("token response of Azure doesn't contain an access token")
+	}
+	expiresIn, err := token.ExpiresIn.Int64()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse expiry of Azure access token")
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      requestedAt.Add(time.Duration(expiresIn) * time.Second),
+	}, nil
+}
+
+// readAzureResponse reads the response of an Azure API and returns its status code. If output is set, the body of a successful response is
+// parsed into it.
+func readAzureResponse(res *http.Response, api string, output interface{}) (int, error) {
+	defer func() { _ = res.Body.Close() }()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, errors.Errorf("unexpected status code %d from %s: %s", res.StatusCode, api, strings.TrimSpace(string(resBody)))
+	}
+	if output != nil {
+		if err := json.Unmarshal(resBody, output); err != nil {
+			return res.StatusCode, errors.Wrapf(err, "failed to parse response of %s", api)
+		}
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,222 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/rest/fake"
+)
+
+type azureRequest struct {
+	method string
+	url    string
+	input  map[string]interface{}
+}
+
+// newAzureKeyVaultMock returns an HTTP client that records the requests to Azure Key Vault. The first requests are answered with the given
+// status codes, all following requests succeed.
+func newAzureKeyVaultMock(t *testing.T, requests *[]azureRequest, statusCodes ...int) *http.Client {
+	t.Helper()
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, "Bearer access-token", req.Header.Get("Authorization"))
+
+		r := azureRequest{method: req.Method, url: req.URL.String()}
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		if len(b) > 0 {
+			require.NoError(t, json.Unmarshal(b, &r.input))
+		}
+		*requests = append(*requests, r)
+
+		statusCode := http.StatusOK
+		if len(*requests) <= len(statusCodes) {
+			statusCode = statusCodes[len(*requests)-1]
+		}
+		return &http.Response{StatusCode: statusCode, Body: io.NopCloser(bytes.NewReader([]byte(`{}`)))}, nil
+	})
+}
+
+func newTestAzureKeyVaultSink(t *testing.T, httpClient *http.Client) *azureKeyVaultSink {
+	t.Helper()
+	s, err := newAzureKeyVaultSink(httpClient, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access-token"}), AzureKeyVaultConfig{
+		VaultURL:     "https://example.vault.azure.net/",
+		NameTemplate: DefaultAzureKeyVaultNameTemplate,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func Test_azureKeyVaultSink_Write(t *testing.T) {
+	app := eamias.NewApplication("app-id", "client-id", "client-secret", "https://token.url", "https://certs.url")
+	setSecret := azureRequest{
+		method: http.MethodPut,
+		url:    "https://example.vault.azure.net/secrets/eventing-auth-runtime-id?api-version=7.4",
+		input: map[string]interface{}{
+			"value":       `{"certs_url":"https://certs.url","client_id":"client-id","client_secret":"client-secret","token_url":"https://token.url"}`,
+			"contentType": "application/json",
+			"tags":        map[string]interface{}{"managed-by": "eventing-auth-manager"},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		statusCodes  []int
+		wantRequests []azureRequest
+		wantErr      string
+	}{
+		{
+			name:         "should set a new version of the secret",
+			wantRequests: []azureRequest{setSecret},
+		},
+		{
+			name:        "should recover a deleted secret",
+			statusCodes: []int{http.StatusConflict},
+			wantRequests: []azureRequest{
+				setSecret,
+				{method: http.MethodPost, url: "https://example.vault.azure.net/deletedsecrets/eventing-auth-runtime-id/recover?api-version=7.4"},
+			},
+			wantErr: "deleted secret is being recovered: unexpected status code 409 from Azure Key Vault: {}",
+		},
+		{
+			name:         "should fail if the secret can't be set",
+			statusCodes:  []int{http.StatusForbidden},
+			wantRequests: []azureRequest{setSecret},
+			wantErr:      "unexpected status code 403 from Azure Key Vault: {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []azureRequest
+			s := newTestAzureKeyVaultSink(t, newAzureKeyVaultMock(t, &requests, tt.statusCodes...))
+
+			// when
+			err := s.Write(context.TODO(), "runtime-id", app)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_azureKeyVaultSink_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    string
+	}{
+		{
+			name:       "should delete the secret",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should succeed if the secret doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "should fail if the secret can't be deleted",
+			statusCode: http.StatusForbidden,
+			wantErr:    "unexpected status code 403 from Azure Key Vault: {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []azureRequest
+			s := newTestAzureKeyVaultSink(t, newAzureKeyVaultMock(t, &requests, tt.statusCode))
+
+			// when
+			err := s.Delete(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, []azureRequest{
+				{method: http.MethodDelete, url: "https://example.vault.azure.net/secrets/eventing-auth-runtime-id?api-version=7.4"},
+			}, requests)
+		})
+	}
+}
+
+func Test_azureManagedIdentityTokenSource(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		clientID string
+		env      map[string]string
+		wantURL  string
+		wantForm map[string]string
+		response string
+	}{
+		{
+			name: "should exchange the federated token of Azure Workload Identity",
+			env: map[string]string{
+				azureFederatedTokenFileEnv: "/var/run/secrets/azure/tokens/azure-identity-token",
+				azureClientIDEnv:           "workload-client-id",
+				azureTenantIDEnv:           "tenant-id",
+				azureAuthorityHostEnv:      "https://login.microsoftonline.com/",
+			},
+			wantURL: "https://login.microsoftonline.com/tenant-id/oauth2/v2.0/token",
+			wantForm: map[string]string{
+				"grant_type":            "client_credentials",
+				"client_id":             "workload-client-id",
+				"scope":                 azureKeyVaultScope,
+				"client_assertion_type": "urn:ietf:params:oauth:client-assertion-type:jwt-bearer",
+				"client_assertion":      "federated-token",
+			},
+			response: `{"access_token":"access-token","expires_in":3600}`,
+		},
+		{
+			name:     "should request the token of the managed identity from IMDS",
+			clientID: "managed-identity-client-id",
+			env:      map[string]string{},
+			wantURL:  azureIMDSTokenURL + "?api-version=2018-02-01&client_id=managed-identity-client-id&resource=https%3A%2F%2Fvault.azure.net",
+			response: `{"access_token":"access-token","expires_in":"3600"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			httpClient := fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+				require.Equal(t, tt.wantURL, req.URL.String())
+				if tt.wantForm != nil {
+					require.NoError(t, req.ParseForm())
+					for key, value := range tt.wantForm {
+						require.Equal(t, value, req.PostForm.Get(key), key)
+					}
+				} else {
+					require.Equal(t, "true", req.Header.Get("Metadata"))
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte(tt.response)))}, nil
+			})
+			ts := newAzureManagedIdentityTokenSource(httpClient, tt.clientID)
+			ts.getenv = func(key string) string { return tt.env[key] }
+			ts.readFile = func(string) ([]byte, error) { return []byte("federated-token\n"), nil }
+			ts.now = func() time.Time { return now }
+
+			// when
+			token, err := ts.Token()
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, "access-token", token.AccessToken)
+			require.Equal(t, now.Add(time.Hour), token.Expiry)
+		})
+	}
+}
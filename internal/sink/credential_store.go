@@ -0,0 +1,136 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultCredentialStoreNamespace    = "eventing-auth"
+	DefaultCredentialStoreNameTemplate = "{{ .RuntimeID }}"
+	credentialStoreNamespaceHeader     = "sapcp-credstore-namespace"
+	credentialStorePasswordPath        = "/api/v1/credentials/password"
+)
+
+// CredentialStoreConfig defines how the credentials are written to the SAP Credential Store.
+type CredentialStoreConfig struct {
+	// BindingPath is the directory of the service binding of the SAP Credential Store that contains the files 'url', 'username', and
+	// 'password', as mounted by the SAP BTP service operator. The payload encryption of the binding must be disabled.
+	BindingPath string
+	// Namespace is the namespace of the SAP Credential Store that contains the credentials.
+	Namespace string
+	// NameTemplate is the Go template of the name of the credential. The runtime ID can be referenced with '{{ .RuntimeID }}'.
+	NameTemplate string
+}
+
+type credentialStoreSink struct {
+	httpClient   *http.Client
+	config       CredentialStoreConfig
+	nameTemplate skr.RuntimeIDTemplate
+	url          string
+	username     string
+	password     string
+}
+
+// NewCredentialStoreSink returns a sink that writes the credentials as password credential to the SAP Credential Store. The client ID
+// is the username and the client secret is the value of the credential, and the token and certs URL are part of its metadata. Every write
+// updates the value, so that the rotated credentials replace the previous ones.
+func NewCredentialStoreSink(httpClient *http.Client, config CredentialStoreConfig) (Sink, error) {
+	if config.BindingPath == "" || config.Namespace == "" {
+		return nil, errors.New("binding path and namespace of the SAP Credential Store must not be empty")
+	}
+	t, err := skr.NewRuntimeIDTemplate("credentialStoreName", config.NameTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid name template of the SAP Credential Store")
+	}
+	s := &credentialStoreSink{httpClient: httpClient, config: config, nameTemplate: t}
+	for key, value := range map[string]*string{"url": &s.url, "username": &s.username, "password": &s.password} {
+		b, err := os.ReadFile(filepath.Join(config.BindingPath, key))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s of the SAP Credential Store binding", key)
+		}
+		*value = strings.TrimSpace(string(b))
+	}
+	s.url = strings.TrimSuffix(s.url, "/")
+	return s, nil
+}
+
+func (s *credentialStoreSink) Name() string {
+	return "credential-store"
+}
+
+func (s *credentialStoreSink) Write(ctx context.Context, skrClusterID string, app eamias.Application) error {
+	name, err := s.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render name of the credential")
+	}
+	credentials := app.Credentials()
+	metadata, err := json.Marshal(map[string]string{
+		eamias.SecretKeyTokenURL: string(credentials[eamias.SecretKeyTokenURL]),
+		eamias.SecretKeyCertsURL: string(credentials[eamias.SecretKeyCertsURL]),
+	})
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{
+		"name":     name,
+		"username": string(credentials[eamias.SecretKeyClientID]),
+		"value":    string(credentials[eamias.SecretKeyClientSecret]),
+		"metadata": string(metadata),
+	})
+	if err != nil {
+		return err
+	}
+	// Creating a password credential with the name of an existing one updates its value.
+	_, err = s.call(ctx, http.MethodPost, s.url+credentialStorePasswordPath, body)
+	return err
+}
+
+func (s *credentialStoreSink) Delete(ctx context.Context, skrClusterID string) error {
+	name, err := s.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render name of the credential")
+	}
+	status, err := s.call(ctx, http.MethodDelete, s.url+credentialStorePasswordPath+"?name="+url.QueryEscape(name), nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+// call sends a request to the SAP Credential Store and returns the status code of the response.
+func (s *credentialStoreSink) call(ctx context.Context, method, url string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.SetBasicAuth(s.username, s.password)
+	req.Header.Set(credentialStoreNamespaceHeader, s.config.Namespace)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, errors.Errorf("unexpected status code %d from SAP Credential Store: %s", res.StatusCode,
+			strings.TrimSpace(string(resBody)))
+	}
+	return res.StatusCode, nil
+}
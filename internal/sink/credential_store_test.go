@@ -0,0 +1,177 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest/fake"
+)
+
+type credentialStoreRequest struct {
+	method string
+	url    string
+	input  map[string]interface{}
+}
+
+// newCredentialStoreMock returns an HTTP client that records the requests to the SAP Credential Store. The first requests are answered
+// with the given status codes, all following requests succeed.
+func newCredentialStoreMock(t *testing.T, requests *[]credentialStoreRequest, statusCodes ...int) *http.Client {
+	t.Helper()
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		username, password, ok := req.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "binding-user", username)
+		require.Equal(t, "binding-password", password)
+		require.Equal(t, DefaultCredentialStoreNamespace, req.Header.Get("sapcp-credstore-namespace"))
+
+		r := credentialStoreRequest{method: req.Method, url: req.URL.String()}
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		if len(b) > 0 {
+			require.NoError(t, json.Unmarshal(b, &r.input))
+		}
+		*requests = append(*requests, r)
+
+		statusCode := http.StatusOK
+		if len(*requests) <= len(statusCodes) {
+			statusCode = statusCodes[len(*requests)-1]
+		}
+		return &http.Response{StatusCode: statusCode, Body: io.NopCloser(bytes.NewReader([]byte(`{}`)))}, nil
+	})
+}
+
+// newCredentialStoreBinding writes the files of a service binding of the SAP Credential Store and returns its directory.
+func newCredentialStoreBinding(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for key, value := range map[string]string{
+		"url":      "https://credstore.example.com/",
+		"username": "binding-user",
+		"password": "binding-password\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, key), []byte(value), 0o600))
+	}
+	return dir
+}
+
+func newTestCredentialStoreSink(t *testing.T, httpClient *http.Client) Sink {
+	t.Helper()
+	s, err := NewCredentialStoreSink(httpClient, CredentialStoreConfig{
+		BindingPath:  newCredentialStoreBinding(t),
+		Namespace:    DefaultCredentialStoreNamespace,
+		NameTemplate: DefaultCredentialStoreNameTemplate,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func Test_credentialStoreSink_Write(t *testing.T) {
+	app := eamias.NewApplication("app-id", "client-id", "client-secret", "https://token.url", "https://certs.url")
+	writePassword := credentialStoreRequest{
+		method: http.MethodPost,
+		url:    "https://credstore.example.com/api/v1/credentials/password",
+		input: map[string]interface{}{
+			"name":     "runtime-id",
+			"username": "client-id",
+			"value":    "client-secret",
+			"metadata": `{"certs_url":"https://certs.url","token_url":"https://token.url"}`,
+		},
+	}
+
+	tests := []struct {
+		name        string
+		statusCodes []int
+		wantErr     string
+	}{
+		{
+			name: "should write the password credential",
+		},
+		{
+			name:        "should fail if the password credential can't be written",
+			statusCodes: []int{http.StatusUnauthorized},
+			wantErr:     "unexpected status code 401 from SAP Credential Store: {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []credentialStoreRequest
+			s := newTestCredentialStoreSink(t, newCredentialStoreMock(t, &requests, tt.statusCodes...))
+
+			// when
+			err := s.Write(context.TODO(), "runtime-id", app)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, []credentialStoreRequest{writePassword}, requests)
+		})
+	}
+}
+
+func Test_credentialStoreSink_Delete(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCodes []int
+		wantErr     string
+	}{
+		{
+			name: "should delete the password credential",
+		},
+		{
+			name:        "should succeed if the password credential doesn't exist",
+			statusCodes: []int{http.StatusNotFound},
+		},
+		{
+			name:        "should fail if the password credential can't be deleted",
+			statusCodes: []int{http.StatusInternalServerError},
+			wantErr:     "unexpected status code 500 from SAP Credential Store: {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []credentialStoreRequest
+			s := newTestCredentialStoreSink(t, newCredentialStoreMock(t, &requests, tt.statusCodes...))
+
+			// when
+			err := s.Delete(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, []credentialStoreRequest{{
+				method: http.MethodDelete,
+				url:    "https://credstore.example.com/api/v1/credentials/password?name=runtime-id",
+			}}, requests)
+		})
+	}
+}
+
+func Test_NewCredentialStoreSink(t *testing.T) {
+	// when the binding doesn't contain the password
+	dir := newCredentialStoreBinding(t)
+	require.NoError(t, os.Remove(filepath.Join(dir, "password")))
+	_, err := NewCredentialStoreSink(http.DefaultClient, CredentialStoreConfig{
+		BindingPath:  dir,
+		Namespace:    DefaultCredentialStoreNamespace,
+		NameTemplate: DefaultCredentialStoreNameTemplate,
+	})
+
+	// then
+	require.ErrorContains(t, err, "failed to read password of the SAP Credential Store binding")
+}
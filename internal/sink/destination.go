@@ -0,0 +1,222 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	klmapiv1beta1 "github.com/kyma-project/lifecycle-manager/api/v1beta1"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	kcorev1 "k8s.io/api/core/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultDestinationNameTemplate        = "eventing-auth-{{ .RuntimeID }}"
+	DefaultDestinationSubaccountLabel     = "kyma-project.io/subaccount-id"
+	DefaultDestinationBindingSecretPrefix = "destination-service-"
+	destinationSubaccountDestinationsPath = "/destination-configuration/v1/subaccountDestinations"
+	// Keys of the secrets of destination service bindings created by the SAP BTP service operator.
+	destinationBindingClientIDKey     = "clientid"
+	destinationBindingClientSecretKey = "clientsecret"
+	destinationBindingTokenURLKey     = "url"
+	destinationBindingServiceURLKey   = "uri"
+)
+
+// DestinationConfig defines how the credentials are written to the SAP Destination Service of the subaccounts of the SKR clusters.
+type DestinationConfig struct {
+	// Namespace is the namespace of the KCP cluster that contains the Kyma CRs and the secrets of the destination service bindings.
+	Namespace string
+	// SubaccountLabel is the label of the Kyma CR that contains the ID of the subaccount of the SKR cluster.
+	SubaccountLabel string
+	// BindingSecretPrefix is the prefix of the name of the secret of the destination service binding of a subaccount. The ID of the
+	// subaccount is appended to the prefix.
+	BindingSecretPrefix string
+	// NameTemplate is the Go template of the name of the destination. The runtime ID can be referenced with '{{ .RuntimeID }}'.
+	NameTemplate string
+	// URLTemplate is the Go template of the URL of the destination. The runtime ID can be referenced with '{{ .RuntimeID }}'. Defaults to
+	// the URL of the IAS tenant.
+	URLTemplate string
+}
+
+type destinationSink struct {
+	httpClient   *http.Client
+	k8sClient    kpkgclient.Client
+	config       DestinationConfig
+	nameTemplate skr.RuntimeIDTemplate
+	urlTemplate  *skr.RuntimeIDTemplate
+}
+
+// destinationBinding contains the credentials of the destination service of a subaccount.
+type destinationBinding struct {
+	clientID     string
+	clientSecret string
+	tokenURL     string
+	serviceURL   string
+}
+
+// NewDestinationSink returns a sink that creates or updates a subaccount destination with the OAuth client credentials of the IAS
+// application in the SAP Destination Service of the subaccount of the SKR cluster, so that CAP applications can consume the credentials as
+// destination. The subaccount is read from the label of the Kyma CR, and the destination service is accessed with the credentials of the
+// destination service binding of the subaccount.
+func NewDestinationSink(httpClient *http.Client, k8sClient kpkgclient.Client, config DestinationConfig) (Sink, error) {
+	if config.Namespace == "" || config.SubaccountLabel == "" {
+		return nil, errors.New("namespace and subaccount label of the destination service must not be empty")
+	}
+	nameTemplate, err := skr.NewRuntimeIDTemplate("destinationName", config.NameTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid name template of the destination")
+	}
+	s := &destinationSink{
+		httpClient:   httpClient,
+		k8sClient:    k8sClient,
+		config:       config,
+		nameTemplate: nameTemplate,
+	}
+	if config.URLTemplate != "" {
+		urlTemplate, err := skr.NewRuntimeIDTemplate("destinationURL", config.URLTemplate)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid URL template of the destination")
+		}
+		s.urlTemplate = &urlTemplate
+	}
+	return s, nil
+}
+
+func (s *destinationSink) Name() string {
+	return "destination"
+}
+
+func (s *destinationSink) Write(ctx context.Context, skrClusterID string, app eamias.Application) error {
+	name, err := s.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render name of the destination")
+	}
+	destinationURL, err := s.destinationURL(skrClusterID, app)
+	if err != nil {
+		return err
+	}
+	binding, err := s.binding(ctx, skrClusterID)
+	if err != nil {
+		return err
+	}
+
+	credentials := app.Credentials()
+	destination := map[string]string{
+		"Name":                name,
+		"Type":                "HTTP",
+		"URL":                 destinationURL,
+		"Authentication":      "OAuth2ClientCredentials",
+		"ProxyType":           "Internet",
+		"clientId":            string(credentials[eamias.SecretKeyClientID]),
+		"clientSecret":        string(credentials[eamias.SecretKeyClientSecret]),
+		"tokenServiceURL":     string(credentials[eamias.SecretKeyTokenURL]),
+		"tokenServiceURLType": "Dedicated",
+		"Description":         "Managed by " + skr.ManagedByLabelValue,
+	}
+
+	// Updating a destination that doesn't exist fails, so the destination is created instead.
+	status, err := s.call(ctx, binding, http.MethodPut, destinationSubaccountDestinationsPath, destination)
+	if status != http.StatusNotFound {
+		return err
+	}
+	_, err = s.call(ctx, binding, http.MethodPost, destinationSubaccountDestinationsPath, destination)
+	return err
+}
+
+func (s *destinationSink) Delete(ctx context.Context, skrClusterID string) error {
+	name, err := s.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render name of the destination")
+	}
+	binding, err := s.binding(ctx, skrClusterID)
+	if err != nil {
+		// Without the Kyma CR or the destination service binding, the destination can't be accessed anymore.
+		return kpkgclient.IgnoreNotFound(err)
+	}
+	status, err := s.call(ctx, binding, http.MethodDelete, destinationSubaccountDestinationsPath+"/"+url.PathEscape(name), nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *destinationSink) destinationURL(skrClusterID string, app eamias.Application) (string, error) {
+	if s.urlTemplate != nil {
+		u, err := s.urlTemplate.Render(skrClusterID)
+		return u, errors.Wrap(err, "failed to render URL of the destination")
+	}
+	tokenURL, err := url.Parse(string(app.Credentials()[eamias.SecretKeyTokenURL]))
+	if err != nil || tokenURL.Host == "" {
+		return "", errors.New("failed to determine URL of the IAS tenant from the token URL")
+	}
+	return tokenURL.Scheme + "://" + tokenURL.Host, nil
+}
+
+// binding returns the credentials of the destination service of the subaccount of the given SKR cluster.
+func (s *destinationSink) binding(ctx context.Context, skrClusterID string) (destinationBinding, error) {
+	var kyma klmapiv1beta1.Kyma
+	if err := s.k8sClient.Get(ctx, kpkgclient.ObjectKey{Namespace: s.config.Namespace, Name: skrClusterID}, &kyma); err != nil {
+		return destinationBinding{}, errors.Wrap(err, "failed to get Kyma CR")
+	}
+	subaccount := kyma.Labels[s.config.SubaccountLabel]
+	if subaccount == "" {
+		return destinationBinding{}, errors.Errorf("Kyma CR %s has no label %s", skrClusterID, s.config.SubaccountLabel)
+	}
+
+	var secret kcorev1.Secret
+	secretName := s.config.BindingSecretPrefix + subaccount
+	if err := s.k8sClient.Get(ctx, kpkgclient.ObjectKey{Namespace: s.config.Namespace, Name: secretName}, &secret); err != nil {
+		return destinationBinding{}, errors.Wrapf(err, "failed to get destination service binding of subaccount %s", subaccount)
+	}
+	b := destinationBinding{
+		clientID:     string(secret.Data[destinationBindingClientIDKey]),
+		clientSecret: string(secret.Data[destinationBindingClientSecretKey]),
+		tokenURL:     strings.TrimSuffix(string(secret.Data[destinationBindingTokenURLKey]), "/") + "/oauth/token",
+		serviceURL:   strings.TrimSuffix(string(secret.Data[destinationBindingServiceURLKey]), "/"),
+	}
+	if b.clientID == "" || b.clientSecret == "" || b.serviceURL == "" {
+		return destinationBinding{}, errors.Errorf("destination service binding %s is incomplete", secretName)
+	}
+	return b, nil
+}
+
+// call sends a request to the destination service with a token of the client credentials of the binding and returns the status code of
+// the response.
+func (s *destinationSink) call(ctx context.Context, binding destinationBinding, method, path string, input interface{}) (int, error) {
+	var body []byte
+	if input != nil {
+		var err error
+		if body, err = json.Marshal(input); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, binding.serviceURL+path, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	oauthConfig := clientcredentials.Config{ClientID: binding.clientID, ClientSecret: binding.clientSecret, TokenURL: binding.tokenURL}
+	res, err := oauthConfig.Client(context.WithValue(ctx, oauth2.HTTPClient, s.httpClient)).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return res.StatusCode, errors.Errorf("unexpected status code %d from destination service: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,226 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	klmapiv1beta1 "github.com/kyma-project/lifecycle-manager/api/v1beta1"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest/fake"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	kfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type destinationRequest struct {
+	method string
+	url    string
+	input  map[string]interface{}
+}
+
+// newDestinationServiceMock returns an HTTP client that issues tokens for the destination service binding and records the requests to the
+// destination service. The first requests are answered with the given status codes, all following requests succeed.
+func newDestinationServiceMock(t *testing.T, requests *[]destinationRequest, statusCodes ...int) *http.Client {
+	t.Helper()
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.String() == "https://destination.auth/oauth/token" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"access_token":"access-token","token_type":"bearer"}`))),
+			}, nil
+		}
+		require.Equal(t, "Bearer access-token", req.Header.Get("Authorization"))
+
+		r := destinationRequest{method: req.Method, url: req.URL.String()}
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		if len(b) > 0 {
+			require.NoError(t, json.Unmarshal(b, &r.input))
+		}
+		*requests = append(*requests, r)
+
+		statusCode := http.StatusOK
+		if len(*requests) <= len(statusCodes) {
+			statusCode = statusCodes[len(*requests)-1]
+		}
+		return &http.Response{StatusCode: statusCode, Body: io.NopCloser(bytes.NewReader([]byte(`{}`)))}, nil
+	})
+}
+
+// newDestinationFakeClient returns a fake client with the Kyma CR of the SKR cluster 'runtime-id' and the destination service binding of
+// its subaccount.
+func newDestinationFakeClient(t *testing.T) kpkgclient.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, kscheme.AddToScheme(scheme))
+	require.NoError(t, klmapiv1beta1.AddToScheme(scheme))
+	return kfake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&klmapiv1beta1.Kyma{ObjectMeta: kmetav1.ObjectMeta{
+			Name:      "runtime-id",
+			Namespace: skr.KcpNamespace,
+			Labels:    map[string]string{DefaultDestinationSubaccountLabel: "subaccount-id"},
+		}},
+		&kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{Name: "destination-service-subaccount-id", Namespace: skr.KcpNamespace},
+			Data: map[string][]byte{
+				"clientid":     []byte("destination-client-id"),
+				"clientsecret": []byte("destination-client-secret"),
+				"url":          []byte("https://destination.auth"),
+				"uri":          []byte("https://destination.service/"),
+			},
+		},
+	).Build()
+}
+
+func newTestDestinationSink(t *testing.T, httpClient *http.Client) Sink {
+	t.Helper()
+	s, err := NewDestinationSink(httpClient, newDestinationFakeClient(t), DestinationConfig{
+		Namespace:           skr.KcpNamespace,
+		SubaccountLabel:     DefaultDestinationSubaccountLabel,
+		BindingSecretPrefix: DefaultDestinationBindingSecretPrefix,
+		NameTemplate:        DefaultDestinationNameTemplate,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func Test_destinationSink_Write(t *testing.T) {
+	app := eamias.NewApplication("app-id", "client-id", "client-secret", "https://tenant.accounts.ondemand.com/oauth2/token", "https://certs.url")
+	destination := map[string]interface{}{
+		"Name":                "eventing-auth-runtime-id",
+		"Type":                "HTTP",
+		"URL":                 "https://tenant.accounts.ondemand.com",
+		"Authentication":      "OAuth2ClientCredentials",
+		"ProxyType":           "Internet",
+		"clientId":            "client-id",
+		"clientSecret":        "client-secret",
+		"tokenServiceURL":     "https://tenant.accounts.ondemand.com/oauth2/token",
+		"tokenServiceURLType": "Dedicated",
+		"Description":         "Managed by eventing-auth-manager",
+	}
+	updateDestination := destinationRequest{
+		method: http.MethodPut,
+		url:    "https://destination.service/destination-configuration/v1/subaccountDestinations",
+		input:  destination,
+	}
+
+	tests := []struct {
+		name         string
+		skrClusterID string
+		statusCodes  []int
+		wantRequests []destinationRequest
+		wantErr      string
+	}{
+		{
+			name:         "should update the destination",
+			skrClusterID: "runtime-id",
+			wantRequests: []destinationRequest{updateDestination},
+		},
+		{
+			name:         "should create the destination if it doesn't exist",
+			skrClusterID: "runtime-id",
+			statusCodes:  []int{http.StatusNotFound},
+			wantRequests: []destinationRequest{updateDestination, {
+				method: http.MethodPost,
+				url:    "https://destination.service/destination-configuration/v1/subaccountDestinations",
+				input:  destination,
+			}},
+		},
+		{
+			name:         "should fail if the destination can't be updated",
+			skrClusterID: "runtime-id",
+			statusCodes:  []int{http.StatusForbidden},
+			wantRequests: []destinationRequest{updateDestination},
+			wantErr:      "unexpected status code 403 from destination service: {}",
+		},
+		{
+			name:         "should fail without Kyma CR",
+			skrClusterID: "unknown-runtime-id",
+			wantErr:      "failed to get Kyma CR",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []destinationRequest
+			s := newTestDestinationSink(t, newDestinationServiceMock(t, &requests, tt.statusCodes...))
+
+			// when
+			err := s.Write(context.TODO(), tt.skrClusterID, app)
+
+			// then
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_destinationSink_Delete(t *testing.T) {
+	deleteDestination := destinationRequest{
+		method: http.MethodDelete,
+		url:    "https://destination.service/destination-configuration/v1/subaccountDestinations/eventing-auth-runtime-id",
+	}
+
+	tests := []struct {
+		name         string
+		skrClusterID string
+		statusCodes  []int
+		wantRequests []destinationRequest
+		wantErr      string
+	}{
+		{
+			name:         "should delete the destination",
+			skrClusterID: "runtime-id",
+			wantRequests: []destinationRequest{deleteDestination},
+		},
+		{
+			name:         "should succeed if the destination doesn't exist",
+			skrClusterID: "runtime-id",
+			statusCodes:  []int{http.StatusNotFound},
+			wantRequests: []destinationRequest{deleteDestination},
+		},
+		{
+			name:         "should succeed without Kyma CR",
+			skrClusterID: "deleted-runtime-id",
+		},
+		{
+			name:         "should fail if the destination can't be deleted",
+			skrClusterID: "runtime-id",
+			statusCodes:  []int{http.StatusInternalServerError},
+			wantRequests: []destinationRequest{deleteDestination},
+			wantErr:      "unexpected status code 500 from destination service: {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []destinationRequest
+			s := newTestDestinationSink(t, newDestinationServiceMock(t, &requests, tt.statusCodes...))
+
+			// when
+			err := s.Delete(context.TODO(), tt.skrClusterID)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
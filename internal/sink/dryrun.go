@@ -0,0 +1,33 @@
+package sink
+
+import (
+	"context"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// dryRunSink logs the writes and deletions of the credentials of the wrapped sink instead of performing them.
+type dryRunSink struct {
+	sink Sink
+}
+
+// NewDryRunSink returns a sink that logs the writes and deletions of the given sink instead of performing them.
+func NewDryRunSink(s Sink) Sink {
+	return dryRunSink{sink: s}
+}
+
+func (s dryRunSink) Name() string {
+	return s.sink.Name()
+}
+
+func (s dryRunSink) Write(ctx context.Context, skrClusterID string, app eamias.Application) error {
+	log.FromContext(ctx).Info("Dry run: skipped write of credentials", "sink", s.sink.Name(), "cluster", skrClusterID,
+		"application", app.GetID())
+	return nil
+}
+
+func (s dryRunSink) Delete(ctx context.Context, skrClusterID string) error {
+	log.FromContext(ctx).Info("Dry run: skipped deletion of credentials", "sink", s.sink.Name(), "cluster", skrClusterID)
+	return nil
+}
@@ -0,0 +1,221 @@
+package sink
+
+import (
+	"context"
+	"time"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultExternalSecretsStoreKind         = "ClusterSecretStore"
+	DefaultExternalSecretsRemoteKeyTemplate = "eventing-auth/{{ .RuntimeID }}"
+	DefaultExternalSecretsRefreshInterval   = time.Hour
+	externalSecretsObjectNamePrefix         = "eventing-auth-"
+)
+
+//nolint:gochecknoglobals // Used as constant.
+var (
+	pushSecretGVK     = schema.GroupVersionKind{Group: "external-secrets.io", Version: "v1alpha1", Kind: "PushSecret"}
+	externalSecretGVK = schema.GroupVersionKind{Group: "external-secrets.io", Version: "v1beta1", Kind: "ExternalSecret"}
+)
+
+// ExternalSecretsConfig defines how the credentials are delivered with the External Secrets Operator (ESO).
+type ExternalSecretsConfig struct {
+	// Namespace is the namespace of the KCP cluster that contains the source secrets and the PushSecrets.
+	Namespace string
+	// StoreName is the name of the secret store that the PushSecrets push the credentials to.
+	StoreName string
+	// StoreKind is the kind of the secret store that the PushSecrets push the credentials to, i.e. 'SecretStore' or 'ClusterSecretStore'.
+	StoreKind string
+	// RemoteKeyTemplate is the Go template of the key of the credentials in the secret store. The runtime ID can be referenced with '{{ .RuntimeID }}'.
+	RemoteKeyTemplate string
+	// RefreshInterval is the interval in which ESO syncs the PushSecrets and ExternalSecrets.
+	RefreshInterval time.Duration
+	// SKRStoreName is the name of the secret store on the SKR cluster that the ExternalSecret reads the credentials from. If empty, no
+	// ExternalSecret is created and the delivery to the SKR cluster is left to the teams operating the secret store.
+	SKRStoreName string
+	// SKRStoreKind is the kind of the secret store on the SKR cluster, i.e. 'SecretStore' or 'ClusterSecretStore'.
+	SKRStoreKind string
+}
+
+// SkrClientGetter returns the client of an SKR cluster.
+type SkrClientGetter interface {
+	Get(ctx context.Context, skrClusterID string) (skr.Client, error)
+}
+
+// deleteOnSkrCluster deletes the object from the SKR cluster. The absence of the SKR kubeconfig secret means that the SKR cluster might have
+// been deleted, so nothing is deleted then.
+func deleteOnSkrCluster(ctx context.Context, skrClients SkrClientGetter, skrClusterID string, obj kpkgclient.Object) error {
+	skrClient, err := skrClients.Get(ctx, skrClusterID)
+	if err != nil {
+		return errors.Wrap(kpkgclient.IgnoreNotFound(err), "failed to create SKR client")
+	}
+	return skrClient.DeleteObject(ctx, obj)
+}
+
+type externalSecretsSink struct {
+	k8sClient         kpkgclient.Client
+	skrClients        SkrClientGetter
+	config            ExternalSecretsConfig
+	remoteKeyTemplate skr.RuntimeIDTemplate
+}
+
+// NewExternalSecretsSink returns a sink that writes the credentials into a source secret on the KCP cluster and creates a PushSecret of the
+// External Secrets Operator that pushes the source secret into a secret store. If a secret store of the SKR cluster is configured, an
+// ExternalSecret is created on the SKR cluster that creates the application secret from the secret store. ESO resources are written as
+// unstructured objects, so that neither the types of ESO nor ESO itself are required if the sink isn't used.
+func NewExternalSecretsSink(k8sClient kpkgclient.Client, skrClients SkrClientGetter, config ExternalSecretsConfig) (Sink, error) {
+	if config.Namespace == "" || config.StoreName == "" {
+		return nil, errors.New("namespace and secret store of the External Secrets Operator must not be empty")
+	}
+	if !isSecretStoreKind(config.StoreKind) || (config.SKRStoreName != "" && !isSecretStoreKind(config.SKRStoreKind)) {
+		return nil, errors.New("kind of the secret store must be one of (SecretStore, ClusterSecretStore)")
+	}
+	if config.SKRStoreName != "" && skrClients == nil {
+		return nil, errors.New("SKR clients are required to create ExternalSecrets on the SKR clusters")
+	}
+	t, err := skr.NewRuntimeIDTemplate("externalSecretsRemoteKey", config.RemoteKeyTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid remote key template of the External Secrets Operator")
+	}
+	return &externalSecretsSink{
+		k8sClient:         k8sClient,
+		skrClients:        skrClients,
+		config:            config,
+		remoteKeyTemplate: t,
+	}, nil
+}
+
+func isSecretStoreKind(kind string) bool {
+	return kind == "SecretStore" || kind == DefaultExternalSecretsStoreKind
+}
+
+func (s *externalSecretsSink) Name() string {
+	return "external-secrets"
+}
+
+func (s *externalSecretsSink) Write(ctx context.Context, skrClusterID string, app eamias.Application) error {
+	remoteKey, err := s.remoteKeyTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render remote key of the credentials")
+	}
+
+	source := s.sourceSecret(skrClusterID)
+	source.Data = app.Credentials()
+	if err := skr.ApplyObject(ctx, s.k8sClient, source); err != nil {
+		return errors.Wrap(err, "failed to apply source secret")
+	}
+	if err := skr.ApplyObject(ctx, s.k8sClient, s.pushSecret(skrClusterID, remoteKey)); err != nil {
+		return errors.Wrap(err, "failed to apply PushSecret")
+	}
+
+	if s.config.SKRStoreName == "" {
+		return nil
+	}
+	skrClient, err := s.skrClients.Get(ctx, skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to create SKR client")
+	}
+	return errors.Wrap(skrClient.ApplyObject(ctx, s.externalSecret(remoteKey)), "failed to apply ExternalSecret on SKR cluster")
+}
+
+func (s *externalSecretsSink) Delete(ctx context.Context, skrClusterID string) error {
+	if s.config.SKRStoreName != "" {
+		if err := deleteOnSkrCluster(ctx, s.skrClients, skrClusterID, s.externalSecret("")); err != nil {
+			return errors.Wrap(err, "failed to delete ExternalSecret on SKR cluster")
+		}
+	}
+
+	// The PushSecret is deleted with the deletion policy 'Delete', so that ESO deletes the credentials from the secret store.
+	pushSecret := &unstructured.Unstructured{}
+	pushSecret.SetGroupVersionKind(pushSecretGVK)
+	pushSecret.SetName(externalSecretsObjectNamePrefix + skrClusterID)
+	pushSecret.SetNamespace(s.config.Namespace)
+	if err := s.k8sClient.Delete(ctx, pushSecret); kpkgclient.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, "failed to delete PushSecret")
+	}
+	if err := s.k8sClient.Delete(ctx, s.sourceSecret(skrClusterID)); kpkgclient.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, "failed to delete source secret")
+	}
+	return nil
+}
+
+func (s *externalSecretsSink) sourceSecret(skrClusterID string) *kcorev1.Secret {
+	return &kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{
+			Name:      externalSecretsObjectNamePrefix + skrClusterID,
+			Namespace: s.config.Namespace,
+			Labels:    map[string]string{skr.ManagedByLabelKey: skr.ManagedByLabelValue},
+		},
+		Type: kcorev1.SecretTypeOpaque,
+	}
+}
+
+func (s *externalSecretsSink) pushSecret(skrClusterID, remoteKey string) *unstructured.Unstructured {
+	data := make([]interface{}, 0, len(eamias.SecretKeys()))
+	for _, key := range eamias.SecretKeys() {
+		data = append(data, map[string]interface{}{
+			"match": map[string]interface{}{
+				"secretKey": key,
+				"remoteRef": map[string]interface{}{"remoteKey": remoteKey, "property": key},
+			},
+		})
+	}
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"refreshInterval": s.config.RefreshInterval.String(),
+			"updatePolicy":    "Replace",
+			"deletionPolicy":  "Delete",
+			"secretStoreRefs": []interface{}{map[string]interface{}{"name": s.config.StoreName, "kind": s.config.StoreKind}},
+			"selector":        map[string]interface{}{"secret": map[string]interface{}{"name": externalSecretsObjectNamePrefix + skrClusterID}},
+			"data":            data,
+		},
+	}}
+	u.SetGroupVersionKind(pushSecretGVK)
+	u.SetName(externalSecretsObjectNamePrefix + skrClusterID)
+	u.SetNamespace(s.config.Namespace)
+	u.SetLabels(map[string]string{skr.ManagedByLabelKey: skr.ManagedByLabelValue})
+	return u
+}
+
+// externalSecret returns the ExternalSecret on the SKR cluster, whose target is the application secret.
+func (s *externalSecretsSink) externalSecret(remoteKey string) *unstructured.Unstructured {
+	data := make([]interface{}, 0, len(eamias.SecretKeys()))
+	for _, key := range eamias.SecretKeys() {
+		data = append(data, map[string]interface{}{
+			"secretKey": key,
+			"remoteRef": map[string]interface{}{"key": remoteKey, "property": key},
+		})
+	}
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"refreshInterval": s.config.RefreshInterval.String(),
+			"secretStoreRef":  map[string]interface{}{"name": s.config.SKRStoreName, "kind": s.config.SKRStoreKind},
+			"target": map[string]interface{}{
+				"name":           skr.ApplicationSecretName,
+				"creationPolicy": "Owner",
+				"template": map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"labels": map[string]interface{}{skr.ManagedByLabelKey: skr.ManagedByLabelValue},
+					},
+				},
+			},
+			"data": data,
+		},
+	}}
+	u.SetGroupVersionKind(externalSecretGVK)
+	u.SetName(skr.ApplicationSecretName)
+	u.SetNamespace(skr.ApplicationSecretNamespace)
+	u.SetLabels(map[string]string{skr.ManagedByLabelKey: skr.ManagedByLabelValue})
+	return u
+}
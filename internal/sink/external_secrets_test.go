@@ -0,0 +1,192 @@
+package sink
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type skrClientGetterStub struct {
+	clients map[string]skr.Client
+}
+
+func (g skrClientGetterStub) Get(_ context.Context, skrClusterID string) (skr.Client, error) {
+	c, ok := g.clients[skrClusterID]
+	if !ok {
+		return nil, kapierrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, skrClusterID)
+	}
+	return c, nil
+}
+
+// skrObjectClientStub applies the objects of an SKR cluster to a fake client.
+type skrObjectClientStub struct {
+	skr.Client
+	k8sClient kpkgclient.Client
+	// services contains the responses of the service proxy keyed by '<namespace>/<name>:<port><path>'.
+	services map[string][]byte
+}
+
+func (c skrObjectClientStub) GetServiceProxy(_ context.Context, namespace, name, port, path string) ([]byte, error) {
+	res, ok := c.services[namespace+"/"+name+":"+port+path]
+	if !ok {
+		return nil, kapierrors.NewNotFound(schema.GroupResource{Resource: "services"}, name)
+	}
+	return res, nil
+}
+
+func (c skrObjectClientStub) ApplyObject(ctx context.Context, obj kpkgclient.Object) error {
+	return skr.ApplyObject(ctx, c.k8sClient, obj)
+}
+
+func (c skrObjectClientStub) DeleteObject(ctx context.Context, obj kpkgclient.Object) error {
+	return kpkgclient.IgnoreNotFound(c.k8sClient.Delete(ctx, obj))
+}
+
+// newExternalSecretsFakeClient returns a fake client that knows the kinds of the External Secrets Operator and of SealedSecrets.
+func newExternalSecretsFakeClient() kpkgclient.Client {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(kcorev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	mapper.Add(pushSecretGVK, meta.RESTScopeNamespace)
+	mapper.Add(externalSecretGVK, meta.RESTScopeNamespace)
+	mapper.Add(sealedSecretGVK, meta.RESTScopeNamespace)
+	return fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRESTMapper(mapper).Build()
+}
+
+func getUnstructured(t *testing.T, c kpkgclient.Client, gvk schema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	t.Helper()
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	err := c.Get(context.TODO(), kpkgclient.ObjectKey{Namespace: namespace, Name: name}, u)
+	return u, err
+}
+
+func Test_externalSecretsSink(t *testing.T) {
+	// given
+	kcpClient := newExternalSecretsFakeClient()
+	skrK8sClient := newExternalSecretsFakeClient()
+	skrClients := skrClientGetterStub{clients: map[string]skr.Client{"runtime-id": skrObjectClientStub{k8sClient: skrK8sClient}}}
+	s, err := NewExternalSecretsSink(kcpClient, skrClients, ExternalSecretsConfig{
+		Namespace:         skr.KcpNamespace,
+		StoreName:         "store",
+		StoreKind:         DefaultExternalSecretsStoreKind,
+		RemoteKeyTemplate: DefaultExternalSecretsRemoteKeyTemplate,
+		RefreshInterval:   time.Minute,
+		SKRStoreName:      "skr-store",
+		SKRStoreKind:      "SecretStore",
+	})
+	require.NoError(t, err)
+	app := eamias.NewApplication("app-id", "client-id", "client-secret", "https://token.url", "https://certs.url")
+
+	// when
+	require.NoError(t, s.Write(context.TODO(), "runtime-id", app))
+	// then the credentials are written again without failing
+	require.NoError(t, s.Write(context.TODO(), "runtime-id", app))
+
+	// then the source secret is created
+	var source kcorev1.Secret
+	require.NoError(t, kcpClient.Get(context.TODO(), kpkgclient.ObjectKey{Namespace: skr.KcpNamespace, Name: "eventing-auth-runtime-id"}, &source))
+	require.Equal(t, app.Credentials(), source.Data)
+	require.Equal(t, skr.ManagedByLabelValue, source.Labels[skr.ManagedByLabelKey])
+
+	// then the PushSecret pushes the source secret to the secret store
+	pushSecret, err := getUnstructured(t, kcpClient, pushSecretGVK, skr.KcpNamespace, "eventing-auth-runtime-id")
+	require.NoError(t, err)
+	selected, _, _ := unstructured.NestedString(pushSecret.Object, "spec", "selector", "secret", "name")
+	require.Equal(t, "eventing-auth-runtime-id", selected)
+	stores, _, _ := unstructured.NestedSlice(pushSecret.Object, "spec", "secretStoreRefs")
+	require.Equal(t, []interface{}{map[string]interface{}{"name": "store", "kind": "ClusterSecretStore"}}, stores)
+	data, _, _ := unstructured.NestedSlice(pushSecret.Object, "spec", "data")
+	require.Len(t, data, 4)
+	require.Equal(t, map[string]interface{}{
+		"match": map[string]interface{}{
+			"secretKey": "client_id",
+			"remoteRef": map[string]interface{}{"remoteKey": "eventing-auth/runtime-id", "property": "client_id"},
+		},
+	}, data[0])
+
+	// then the ExternalSecret on the SKR cluster creates the application secret from the secret store
+	externalSecret, err := getUnstructured(t, skrK8sClient, externalSecretGVK, skr.ApplicationSecretNamespace, skr.ApplicationSecretName)
+	require.NoError(t, err)
+	target, _, _ := unstructured.NestedString(externalSecret.Object, "spec", "target", "name")
+	require.Equal(t, skr.ApplicationSecretName, target)
+	refreshInterval, _, _ := unstructured.NestedString(externalSecret.Object, "spec", "refreshInterval")
+	require.Equal(t, "1m0s", refreshInterval)
+	data, _, _ = unstructured.NestedSlice(externalSecret.Object, "spec", "data")
+	require.Equal(t, map[string]interface{}{
+		"secretKey": "client_secret",
+		"remoteRef": map[string]interface{}{"key": "eventing-auth/runtime-id", "property": "client_secret"},
+	}, data[1])
+
+	// when
+	require.NoError(t, s.Delete(context.TODO(), "runtime-id"))
+
+	// then all objects are deleted
+	_, err = getUnstructured(t, skrK8sClient, externalSecretGVK, skr.ApplicationSecretNamespace, skr.ApplicationSecretName)
+	require.True(t, kapierrors.IsNotFound(err))
+	_, err = getUnstructured(t, kcpClient, pushSecretGVK, skr.KcpNamespace, "eventing-auth-runtime-id")
+	require.True(t, kapierrors.IsNotFound(err))
+	err = kcpClient.Get(context.TODO(), kpkgclient.ObjectKey{Namespace: skr.KcpNamespace, Name: "eventing-auth-runtime-id"}, &source)
+	require.True(t, kapierrors.IsNotFound(err))
+
+	// then deleting the credentials of an SKR cluster without kubeconfig succeeds
+	require.NoError(t, s.Delete(context.TODO(), "deleted-runtime-id"))
+}
+
+func Test_NewExternalSecretsSink(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     ExternalSecretsConfig
+		skrClients SkrClientGetter
+		wantErr    string
+	}{
+		{
+			name:    "should fail without secret store",
+			config:  ExternalSecretsConfig{Namespace: skr.KcpNamespace, StoreKind: DefaultExternalSecretsStoreKind},
+			wantErr: "namespace and secret store of the External Secrets Operator must not be empty",
+		},
+		{
+			name:    "should fail with unknown kind of the secret store",
+			config:  ExternalSecretsConfig{Namespace: skr.KcpNamespace, StoreName: "store", StoreKind: "Vault"},
+			wantErr: "kind of the secret store must be one of (SecretStore, ClusterSecretStore)",
+		},
+		{
+			name: "should fail with secret store on the SKR clusters but without SKR clients",
+			config: ExternalSecretsConfig{
+				Namespace:    skr.KcpNamespace,
+				StoreName:    "store",
+				StoreKind:    DefaultExternalSecretsStoreKind,
+				SKRStoreName: "skr-store",
+				SKRStoreKind: DefaultExternalSecretsStoreKind,
+			},
+			wantErr: "SKR clients are required to create ExternalSecrets on the SKR clusters",
+		},
+		{
+			name: "should fail with invalid remote key template",
+			config: ExternalSecretsConfig{
+				Namespace:         skr.KcpNamespace,
+				StoreName:         "store",
+				StoreKind:         DefaultExternalSecretsStoreKind,
+				RemoteKeyTemplate: "{{ .RuntimeID",
+			},
+			wantErr: "invalid remote key template of the External Secrets Operator",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewExternalSecretsSink(newExternalSecretsFakeClient(), tt.skrClients, tt.config)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
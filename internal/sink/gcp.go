@@ -0,0 +1,161 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	DefaultGCPSecretManagerNameTemplate = "eventing-auth-{{ .RuntimeID }}"
+	gcpSecretManagerEndpoint            = "https://secretmanager.googleapis.com"
+	gcpCloudPlatformScope               = "https://www.googleapis.com/auth/cloud-platform"
+	gcpManagedByLabelKey                = "managed-by"
+)
+
+// GCPSecretManagerConfig defines how the credentials are written to GCP Secret Manager.
+type GCPSecretManagerConfig struct {
+	// Project is the ID of the GCP project of the secrets.
+	Project string
+	// Endpoint overrides the endpoint of GCP Secret Manager, e.g. for regional endpoints. Defaults to the global endpoint.
+	Endpoint string
+	// NameTemplate is the Go template of the ID of the secret. The runtime ID can be referenced with '{{ .RuntimeID }}'.
+	NameTemplate string
+}
+
+type gcpSecretManagerSink struct {
+	httpClient   *http.Client
+	tokenSource  oauth2.TokenSource
+	config       GCPSecretManagerConfig
+	nameTemplate skr.RuntimeIDTemplate
+}
+
+// NewGCPSecretManagerSink returns a sink that writes the credentials as JSON object into a secret of GCP Secret Manager. The GCP credentials
+// are resolved as application default credentials, so that Workload Identity is used when the manager runs on GKE, and workload identity
+// federation can be configured with a credential configuration file.
+func NewGCPSecretManagerSink(ctx context.Context, httpClient *http.Client, config GCPSecretManagerConfig) (Sink, error) {
+	tokenSource, err := google.DefaultTokenSource(context.WithValue(ctx, oauth2.HTTPClient, httpClient), gcpCloudPlatformScope)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find GCP credentials")
+	}
+	return newGCPSecretManagerSink(httpClient, tokenSource, config)
+}
+
+func newGCPSecretManagerSink(httpClient *http.Client, tokenSource oauth2.TokenSource, config GCPSecretManagerConfig) (*gcpSecretManagerSink, error) {
+	if config.Project == "" {
+		return nil, errors.New("project of GCP Secret Manager must not be empty")
+	}
+	t, err := skr.NewRuntimeIDTemplate("gcpSecretName", config.NameTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid name template of GCP Secret Manager")
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = gcpSecretManagerEndpoint
+	}
+	return &gcpSecretManagerSink{
+		httpClient:   httpClient,
+		tokenSource:  tokenSource,
+		config:       config,
+		nameTemplate: t,
+	}, nil
+}
+
+func (s *gcpSecretManagerSink) Name() string {
+	return "gcp-secret-manager"
+}
+
+func (s *gcpSecretManagerSink) Write(ctx context.Context, skrClusterID string, app eamias.Application) error {
+	name, err := s.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render name of the secret")
+	}
+	value, err := credentialJSON(app)
+	if err != nil {
+		return err
+	}
+	version := map[string]interface{}{"payload": map[string]string{"data": base64.StdEncoding.EncodeToString([]byte(value))}}
+
+	// A new version of the secret is added on every write, so that consumers reading the 'latest' version get the rotated credentials.
+	status, err := s.call(ctx, http.MethodPost, s.secretURL(name)+":addVersion", version)
+	if status != http.StatusNotFound {
+		return err
+	}
+	secret := map[string]interface{}{
+		"replication": map[string]interface{}{"automatic": map[string]interface{}{}},
+		"labels":      map[string]string{gcpManagedByLabelKey: skr.ManagedByLabelValue},
+	}
+	if _, err := s.call(ctx, http.MethodPost, s.secretsURL()+"?secretId="+url.QueryEscape(name), secret); err != nil {
+		return errors.Wrap(err, "failed to create secret")
+	}
+	_, err = s.call(ctx, http.MethodPost, s.secretURL(name)+":addVersion", version)
+	return err
+}
+
+func (s *gcpSecretManagerSink) Delete(ctx context.Context, skrClusterID string) error {
+	name, err := s.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render name of the secret")
+	}
+	// Deleting the secret deletes all of its versions.
+	status, err := s.call(ctx, http.MethodDelete, s.secretURL(name), nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *gcpSecretManagerSink) secretsURL() string {
+	return fmt.Sprintf("%s/v1/projects/%s/secrets", strings.TrimSuffix(s.config.Endpoint, "/"), url.PathEscape(s.config.Project))
+}
+
+func (s *gcpSecretManagerSink) secretURL(name string) string {
+	return s.secretsURL() + "/" + url.PathEscape(name)
+}
+
+// call sends an authenticated request to the GCP Secret Manager API and returns the status code of the response.
+func (s *gcpSecretManagerSink) call(ctx context.Context, method, url string, input interface{}) (int, error) {
+	var body []byte
+	if input != nil {
+		var err error
+		if body, err = json.Marshal(input); err != nil {
+			return 0, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	token, err := s.tokenSource.Token()
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to retrieve GCP access token")
+	}
+	token.SetAuthHeader(req)
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return res.StatusCode, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return res.StatusCode, errors.Errorf("unexpected status code %d from GCP Secret Manager: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
+	}
+	return res.StatusCode, nil
+}
@@ -0,0 +1,159 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+	"k8s.io/client-go/rest/fake"
+)
+
+type gcpRequest struct {
+	method string
+	url    string
+	input  map[string]interface{}
+}
+
+// newGCPSecretManagerMock returns an HTTP client that records the requests to GCP Secret Manager. The first requests are answered with the
+// given status codes, all following requests succeed.
+func newGCPSecretManagerMock(t *testing.T, requests *[]gcpRequest, statusCodes ...int) *http.Client {
+	t.Helper()
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, "Bearer access-token", req.Header.Get("Authorization"))
+
+		r := gcpRequest{method: req.Method, url: req.URL.String()}
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		if len(b) > 0 {
+			require.NoError(t, json.Unmarshal(b, &r.input))
+		}
+		*requests = append(*requests, r)
+
+		statusCode := http.StatusOK
+		if len(*requests) <= len(statusCodes) {
+			statusCode = statusCodes[len(*requests)-1]
+		}
+		return &http.Response{StatusCode: statusCode, Body: io.NopCloser(bytes.NewReader([]byte(`{}`)))}, nil
+	})
+}
+
+func newTestGCPSecretManagerSink(t *testing.T, httpClient *http.Client) *gcpSecretManagerSink {
+	t.Helper()
+	s, err := newGCPSecretManagerSink(httpClient, oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "access-token"}), GCPSecretManagerConfig{
+		Project:      "project",
+		NameTemplate: DefaultGCPSecretManagerNameTemplate,
+	})
+	require.NoError(t, err)
+	return s
+}
+
+func Test_gcpSecretManagerSink_Write(t *testing.T) {
+	app := eamias.NewApplication("app-id", "client-id", "client-secret", "https://token.url", "https://certs.url")
+	credentials := `{"certs_url":"https://certs.url","client_id":"client-id","client_secret":"client-secret","token_url":"https://token.url"}`
+	addVersion := gcpRequest{
+		method: http.MethodPost,
+		url:    "https://secretmanager.googleapis.com/v1/projects/project/secrets/eventing-auth-runtime-id:addVersion",
+		input:  map[string]interface{}{"payload": map[string]interface{}{"data": base64.StdEncoding.EncodeToString([]byte(credentials))}},
+	}
+
+	tests := []struct {
+		name         string
+		statusCodes  []int
+		wantRequests []gcpRequest
+		wantErr      string
+	}{
+		{
+			name:         "should add a version to an existing secret",
+			wantRequests: []gcpRequest{addVersion},
+		},
+		{
+			name:        "should create the secret if it doesn't exist",
+			statusCodes: []int{http.StatusNotFound},
+			wantRequests: []gcpRequest{
+				addVersion,
+				{
+					method: http.MethodPost,
+					url:    "https://secretmanager.googleapis.com/v1/projects/project/secrets?secretId=eventing-auth-runtime-id",
+					input: map[string]interface{}{
+						"replication": map[string]interface{}{"automatic": map[string]interface{}{}},
+						"labels":      map[string]interface{}{"managed-by": "eventing-auth-manager"},
+					},
+				},
+				addVersion,
+			},
+		},
+		{
+			name:         "should fail if the version can't be added",
+			statusCodes:  []int{http.StatusForbidden},
+			wantRequests: []gcpRequest{addVersion},
+			wantErr:      "unexpected status code 403 from GCP Secret Manager: {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []gcpRequest
+			s := newTestGCPSecretManagerSink(t, newGCPSecretManagerMock(t, &requests, tt.statusCodes...))
+
+			// when
+			err := s.Write(context.TODO(), "runtime-id", app)
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_gcpSecretManagerSink_Delete(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		wantErr    string
+	}{
+		{
+			name:       "should delete the secret",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "should succeed if the secret doesn't exist",
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "should fail if the secret can't be deleted",
+			statusCode: http.StatusForbidden,
+			wantErr:    "unexpected status code 403 from GCP Secret Manager: {}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			var requests []gcpRequest
+			s := newTestGCPSecretManagerSink(t, newGCPSecretManagerMock(t, &requests, tt.statusCode))
+
+			// when
+			err := s.Delete(context.TODO(), "runtime-id")
+
+			// then
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, []gcpRequest{
+				{method: http.MethodDelete, url: "https://secretmanager.googleapis.com/v1/projects/project/secrets/eventing-auth-runtime-id"},
+			}, requests)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package sink
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	DefaultSealedSecretsControllerNamespace = "kube-system"
+	DefaultSealedSecretsControllerName      = "sealed-secrets-controller"
+	sealedSecretsControllerPort             = "8080"
+	sealedSecretsCertPath                   = "/v1/cert.pem"
+	sealedSecretsSessionKeyBytes            = 32
+)
+
+//nolint:gochecknoglobals // Used as constant.
+var sealedSecretGVK = schema.GroupVersionKind{Group: "bitnami.com", Version: "v1alpha1", Kind: "SealedSecret"}
+
+// SealedSecretsConfig defines how the credentials are sealed for the SealedSecrets controller of the SKR clusters.
+type SealedSecretsConfig struct {
+	// ControllerNamespace is the namespace of the SealedSecrets controller on the SKR clusters.
+	ControllerNamespace string
+	// ControllerName is the name of the service of the SealedSecrets controller on the SKR clusters.
+	ControllerName string
+	// CertFile is the path of the public certificate that the credentials are sealed with. If empty, the certificate is read from the
+	// SealedSecrets controller of each SKR cluster.
+	CertFile string
+}
+
+type sealedSecretsSink struct {
+	skrClients SkrClientGetter
+	config     SealedSecretsConfig
+	cert       []byte
+	random     io.Reader
+}
+
+// NewSealedSecretsSink returns a sink that seals the credentials with the public certificate of the SealedSecrets controller of the SKR
+// cluster and applies a SealedSecret instead of the application secret, so that no plain secret is applied to GitOps-managed SKR clusters.
+// The SealedSecret has the strict scope, so it can only be unsealed as the application secret.
+func NewSealedSecretsSink(skrClients SkrClientGetter, config SealedSecretsConfig) (Sink, error) {
+	if skrClients == nil {
+		return nil, errors.New("SKR clients are required to create SealedSecrets on the SKR clusters")
+	}
+	s := &sealedSecretsSink{skrClients: skrClients, config: config, random: rand.Reader}
+	if config.CertFile != "" {
+		cert, err := os.ReadFile(config.CertFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read certificate of SealedSecrets")
+		}
+		if _, err := parseSealingKey(cert); err != nil {
+			return nil, err
+		}
+		s.cert = cert
+	}
+	return s, nil
+}
+
+func (s *sealedSecretsSink) Name() string {
+	return "sealed-secrets"
+}
+
+func (s *sealedSecretsSink) Write(ctx context.Context, skrClusterID string, app eamias.Application) error {
+	skrClient, err := s.skrClients.Get(ctx, skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to create SKR client")
+	}
+
+	cert := s.cert
+	if cert == nil {
+		if cert, err = skrClient.GetServiceProxy(ctx, s.config.ControllerNamespace, s.config.ControllerName, sealedSecretsControllerPort,
+			sealedSecretsCertPath); err != nil {
+			return errors.Wrap(err, "failed to read certificate of the SealedSecrets controller")
+		}
+	}
+	key, err := parseSealingKey(cert)
+	if err != nil {
+		return err
+	}
+
+	sealedSecret, err := s.seal(key, app)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(skrClient.ApplyObject(ctx, sealedSecret), "failed to apply SealedSecret on SKR cluster")
+}
+
+func (s *sealedSecretsSink) Delete(ctx context.Context, skrClusterID string) error {
+	// The SealedSecrets controller deletes the unsealed application secret, since it is owned by the SealedSecret.
+	return errors.Wrap(deleteOnSkrCluster(ctx, s.skrClients, skrClusterID, newSealedSecret()), "failed to delete SealedSecret on SKR cluster")
+}
+
+// seal returns the SealedSecret of the application secret with the credentials of the given application.
+func (s *sealedSecretsSink) seal(key *rsa.PublicKey, app eamias.Application) (*unstructured.Unstructured, error) {
+	// The label of the strict scope binds the sealed values to the name and namespace of the secret.
+	label := []byte(fmt.Sprintf("%s/%s", skr.ApplicationSecretNamespace, skr.ApplicationSecretName))
+	encryptedData := map[string]interface{}{}
+	for k, v := range app.Credentials() {
+		ciphertext, err := hybridEncrypt(s.random, key, v, label)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to seal %s", k)
+		}
+		encryptedData[k] = base64.StdEncoding.EncodeToString(ciphertext)
+	}
+
+	u := newSealedSecret()
+	u.SetLabels(map[string]string{skr.ManagedByLabelKey: skr.ManagedByLabelValue})
+	u.Object["spec"] = map[string]interface{}{
+		"encryptedData": encryptedData,
+		"template": map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":      skr.ApplicationSecretName,
+				"namespace": skr.ApplicationSecretNamespace,
+				"labels":    map[string]interface{}{skr.ManagedByLabelKey: skr.ManagedByLabelValue},
+			},
+			"type": string(kcorev1.SecretTypeOpaque),
+		},
+	}
+	return u, nil
+}
+
+func newSealedSecret() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	u.SetGroupVersionKind(sealedSecretGVK)
+	u.SetName(skr.ApplicationSecretName)
+	u.SetNamespace(skr.ApplicationSecretNamespace)
+	return u
+}
+
+// parseSealingKey returns the RSA public key of the given PEM encoded certificate of the SealedSecrets controller.
+func parseSealingKey(cert []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM certificate of SealedSecrets")
+	}
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse certificate of SealedSecrets")
+	}
+	key, ok := c.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("certificate of SealedSecrets doesn't contain an RSA public key")
+	}
+	return key, nil
+}
+
+// hybridEncrypt encrypts the plaintext the same way as kubeseal: a random session key is encrypted with RSA-OAEP and the given label, and
+// the plaintext is encrypted with AES-GCM and the session key. The result is the length of the encrypted session key as 2 bytes, followed
+// by the encrypted session key and the encrypted plaintext.
+func hybridEncrypt(random io.Reader, key *rsa.PublicKey, plaintext, label []byte) ([]byte, error) {
+	sessionKey := make([]byte, sealedSecretsSessionKeyBytes)
+	if _, err := io.ReadFull(random, sessionKey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	encryptedSessionKey, err := rsa.EncryptOAEP(sha256.New(), random, key, sessionKey, label)
+	if err != nil {
+		return nil, err
+	}
+
+	ciphertext := make([]byte, 2, 2+len(encryptedSessionKey)+len(plaintext)+aead.Overhead())
+	binary.BigEndian.PutUint16(ciphertext, uint16(len(encryptedSessionKey)))
+	ciphertext = append(ciphertext, encryptedSessionKey...)
+	// The session key is only used once, so the nonce can be zero.
+	zeroNonce := make([]byte, aead.NonceSize())
+	return aead.Seal(ciphertext, zeroNonce, plaintext, nil), nil
+}
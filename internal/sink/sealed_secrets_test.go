@@ -0,0 +1,129 @@
+package sink
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/stretchr/testify/require"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newSealingCert(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sealed-secret"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	return key, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// hybridDecrypt decrypts the ciphertext of hybridEncrypt the same way as the SealedSecrets controller.
+func hybridDecrypt(t *testing.T, key *rsa.PrivateKey, ciphertext, label []byte) []byte {
+	t.Helper()
+	require.Greater(t, len(ciphertext), 2)
+	rsaLen := int(binary.BigEndian.Uint16(ciphertext))
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, key, ciphertext[2:2+rsaLen], label)
+	require.NoError(t, err)
+	block, err := aes.NewCipher(sessionKey)
+	require.NoError(t, err)
+	aead, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	plaintext, err := aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext[2+rsaLen:], nil)
+	require.NoError(t, err)
+	return plaintext
+}
+
+func Test_sealedSecretsSink(t *testing.T) {
+	// given
+	key, cert := newSealingCert(t)
+	skrK8sClient := newExternalSecretsFakeClient()
+	skrClients := skrClientGetterStub{clients: map[string]skr.Client{"runtime-id": skrObjectClientStub{
+		k8sClient: skrK8sClient,
+		services:  map[string][]byte{"kube-system/sealed-secrets-controller:8080/v1/cert.pem": cert},
+	}}}
+	s, err := NewSealedSecretsSink(skrClients, SealedSecretsConfig{
+		ControllerNamespace: DefaultSealedSecretsControllerNamespace,
+		ControllerName:      DefaultSealedSecretsControllerName,
+	})
+	require.NoError(t, err)
+	app := eamias.NewApplication("app-id", "client-id", "client-secret", "https://token.url", "https://certs.url")
+
+	// when
+	require.NoError(t, s.Write(context.TODO(), "runtime-id", app))
+
+	// then the SealedSecret can be unsealed with the private key of the SKR cluster as the application secret
+	sealedSecret, err := getUnstructured(t, skrK8sClient, sealedSecretGVK, skr.ApplicationSecretNamespace, skr.ApplicationSecretName)
+	require.NoError(t, err)
+	encryptedData, _, err := unstructured.NestedStringMap(sealedSecret.Object, "spec", "encryptedData")
+	require.NoError(t, err)
+	require.Len(t, encryptedData, 4)
+	for k, v := range app.Credentials() {
+		ciphertext, err := base64.StdEncoding.DecodeString(encryptedData[k])
+		require.NoError(t, err)
+		require.Equal(t, v, hybridDecrypt(t, key, ciphertext, []byte("kyma-system/eventing-webhook-auth")))
+	}
+	templateName, _, _ := unstructured.NestedString(sealedSecret.Object, "spec", "template", "metadata", "name")
+	require.Equal(t, skr.ApplicationSecretName, templateName)
+
+	// when
+	require.NoError(t, s.Delete(context.TODO(), "runtime-id"))
+
+	// then
+	_, err = getUnstructured(t, skrK8sClient, sealedSecretGVK, skr.ApplicationSecretNamespace, skr.ApplicationSecretName)
+	require.True(t, kapierrors.IsNotFound(err))
+
+	// then deleting the SealedSecret of an SKR cluster without kubeconfig succeeds
+	require.NoError(t, s.Delete(context.TODO(), "deleted-runtime-id"))
+}
+
+func Test_sealedSecretsSink_CertFile(t *testing.T) {
+	// given a certificate file, so that the SealedSecrets controller is not called
+	key, cert := newSealingCert(t)
+	certFile := filepath.Join(t.TempDir(), "cert.pem")
+	require.NoError(t, os.WriteFile(certFile, cert, 0o600))
+	skrK8sClient := newExternalSecretsFakeClient()
+	skrClients := skrClientGetterStub{clients: map[string]skr.Client{"runtime-id": skrObjectClientStub{k8sClient: skrK8sClient}}}
+	s, err := NewSealedSecretsSink(skrClients, SealedSecretsConfig{CertFile: certFile})
+	require.NoError(t, err)
+
+	// when
+	require.NoError(t, s.Write(context.TODO(), "runtime-id", eamias.NewApplication("app-id", "client-id", "client-secret", "", "")))
+
+	// then
+	sealedSecret, err := getUnstructured(t, skrK8sClient, sealedSecretGVK, skr.ApplicationSecretNamespace, skr.ApplicationSecretName)
+	require.NoError(t, err)
+	clientID, _, _ := unstructured.NestedString(sealedSecret.Object, "spec", "encryptedData", eamias.SecretKeyClientID)
+	ciphertext, err := base64.StdEncoding.DecodeString(clientID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("client-id"), hybridDecrypt(t, key, ciphertext, []byte("kyma-system/eventing-webhook-auth")))
+
+	// when the certificate file is invalid
+	require.NoError(t, os.WriteFile(certFile, []byte("invalid"), 0o600))
+	_, err = NewSealedSecretsSink(skrClients, SealedSecretsConfig{CertFile: certFile})
+
+	// then
+	require.EqualError(t, err, "failed to decode PEM certificate of SealedSecrets")
+}
@@ -0,0 +1,103 @@
+package sink
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/pkg/errors"
+)
+
+const httpClientTimeout = time.Second * 30
+
+// Sink stores the credentials of the IAS application of an SKR cluster in a secret store outside of the SKR cluster.
+type Sink interface {
+	// Name returns the name of the sink that is used in logs and conditions.
+	Name() string
+	// Write writes the credentials of the IAS application of the given SKR cluster. Existing credentials are overwritten.
+	Write(ctx context.Context, skrClusterID string, app eamias.Application) error
+	// Delete deletes the credentials of the given SKR cluster. It doesn't fail if the credentials don't exist.
+	Delete(ctx context.Context, skrClusterID string) error
+}
+
+// Error is returned if the credentials couldn't be written to a sink.
+type Error struct {
+	Sink string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("failed to write credentials to sink %s: %s", e.Sink, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ConditionReason returns the reason of the SecretReady condition of the EventingAuth CR.
+func (e *Error) ConditionReason() string {
+	return eamapiv1alpha1.ConditionReasonSinkWriteFailed
+}
+
+// WriteAll writes the credentials of the IAS application of the given SKR cluster to all sinks. It stops at the first sink that fails.
+func WriteAll(ctx context.Context, sinks []Sink, skrClusterID string, app eamias.Application) error {
+	for _, s := range sinks {
+		if err := s.Write(ctx, skrClusterID, app); err != nil {
+			return &Error{Sink: s.Name(), Err: err}
+		}
+	}
+	return nil
+}
+
+// DeleteAll deletes the credentials of the given SKR cluster from all sinks.
+func DeleteAll(ctx context.Context, sinks []Sink, skrClusterID string) error {
+	for _, s := range sinks {
+		if err := s.Delete(ctx, skrClusterID); err != nil {
+			return errors.Wrapf(err, "failed to delete credentials from sink %s", s.Name())
+		}
+	}
+	return nil
+}
+
+// credentialData returns the credentials of the IAS application with the same keys as the application secret.
+func credentialData(app eamias.Application) map[string]string {
+	data := map[string]string{}
+	for key, value := range app.Credentials() {
+		data[key] = string(value)
+	}
+	return data
+}
+
+// credentialJSON returns the credentials of the IAS application as JSON object for secret stores that store a single value per secret.
+func credentialJSON(app eamias.Application) (string, error) {
+	b, err := json.Marshal(credentialData(app))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal credentials")
+	}
+	return string(b), nil
+}
+
+// NewHTTPClient returns the HTTP client of a sink. If the CA certificate file is set, the server certificate of the sink is verified
+// with the CA certificates of the file instead of the system CA certificates.
+func NewHTTPClient(caCertFile string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert // The default transport is always an *http.Transport.
+	if caCertFile != "" {
+		caCert, err := os.ReadFile(caCertFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read CA certificate")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, errors.Errorf("failed to parse CA certificate from %s", caCertFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &http.Client{Transport: transport, Timeout: httpClientTimeout}, nil
+}
@@ -0,0 +1,63 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+)
+
+type sinkStub struct {
+	name    string
+	err     error
+	written []string
+	deleted []string
+}
+
+func (s *sinkStub) Name() string {
+	return s.name
+}
+
+func (s *sinkStub) Write(_ context.Context, skrClusterID string, _ eamias.Application) error {
+	s.written = append(s.written, skrClusterID)
+	return s.err
+}
+
+func (s *sinkStub) Delete(_ context.Context, skrClusterID string) error {
+	s.deleted = append(s.deleted, skrClusterID)
+	return s.err
+}
+
+func Test_WriteAll(t *testing.T) {
+	// given
+	failing := &sinkStub{name: "failing", err: errors.New("unavailable")}
+	other := &sinkStub{name: "other"}
+
+	// when
+	err := WriteAll(context.TODO(), []Sink{failing, other}, "runtime-id", eamias.Application{})
+
+	// then the error of the failing sink sets the reason of the SecretReady condition
+	var reasonErr eamapiv1alpha1.ConditionReasonError
+	require.ErrorAs(t, err, &reasonErr)
+	require.Equal(t, eamapiv1alpha1.ConditionReasonSinkWriteFailed, reasonErr.ConditionReason())
+	require.EqualError(t, err, "failed to write credentials to sink failing: unavailable")
+	require.Equal(t, []string{"runtime-id"}, failing.written)
+	require.Empty(t, other.written)
+}
+
+func Test_DeleteAll(t *testing.T) {
+	// given
+	first := &sinkStub{name: "first"}
+	failing := &sinkStub{name: "failing", err: errors.New("unavailable")}
+
+	// when
+	err := DeleteAll(context.TODO(), []Sink{first, failing}, "runtime-id")
+
+	// then
+	require.EqualError(t, err, "failed to delete credentials from sink failing: unavailable")
+	require.Equal(t, []string{"runtime-id"}, first.deleted)
+	require.Equal(t, []string{"runtime-id"}, failing.deleted)
+}
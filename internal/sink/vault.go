@@ -0,0 +1,200 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/skr"
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultVaultAuthMount           = "kubernetes"
+	DefaultVaultKVMount             = "secret"
+	DefaultVaultPathTemplate        = "eventing-auth/{{ .RuntimeID }}"
+	DefaultVaultServiceAccountToken = "/var/run/secrets/kubernetes.io/serviceaccount/token" //nolint:gosec // This is a path, not a credential.
+	vaultNamespaceHeader            = "X-Vault-Namespace"
+	vaultTokenHeader                = "X-Vault-Token"
+	vaultTokenRenewalPercent        = 80
+)
+
+// VaultConfig defines how the credentials are written to a KV secrets engine (version 2) of Vault.
+type VaultConfig struct {
+	// Address is the address of Vault, e.g. 'https://vault.example.com:8200'.
+	Address string
+	// Namespace is the Vault Enterprise namespace. It is not sent if empty.
+	Namespace string
+	// AuthMount is the mount path of the Kubernetes auth method.
+	AuthMount string
+	// Role is the role of the Kubernetes auth method that the manager logs in with.
+	Role string
+	// ServiceAccountTokenFile is the path of the service account token that is used to log in.
+	ServiceAccountTokenFile string
+	// KVMount is the mount path of the KV secrets engine.
+	KVMount string
+	// PathTemplate is the Go template of the path of the credentials in the KV secrets engine. The runtime ID can be referenced with '{{ .RuntimeID }}'.
+	PathTemplate string
+}
+
+type vaultSink struct {
+	httpClient   *http.Client
+	config       VaultConfig
+	pathTemplate skr.RuntimeIDTemplate
+	now          func() time.Time
+	readFile     func(string) ([]byte, error)
+
+	mu      sync.Mutex
+	token   string
+	renewAt time.Time
+}
+
+// NewVaultSink returns a sink that writes the credentials into a KV secrets engine (version 2) of Vault. The manager logs in to Vault with
+// the Kubernetes auth method using its service account token.
+func NewVaultSink(httpClient *http.Client, config VaultConfig) (Sink, error) {
+	if config.Address == "" || config.Role == "" {
+		return nil, errors.New("address and role of Vault must not be empty")
+	}
+	t, err := skr.NewRuntimeIDTemplate("vaultPath", config.PathTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid path template of Vault")
+	}
+	return &vaultSink{
+		httpClient:   httpClient,
+		config:       config,
+		pathTemplate: t,
+		now:          time.Now,
+		readFile:     os.ReadFile,
+	}, nil
+}
+
+func (s *vaultSink) Name() string {
+	return "vault"
+}
+
+func (s *vaultSink) Write(ctx context.Context, skrClusterID string, app eamias.Application) error {
+	path, err := s.pathTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render path of the credentials")
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"data": credentialData(app)})
+	if err != nil {
+		return err
+	}
+	_, err = s.do(ctx, http.MethodPost, s.kvURL("data", path), body)
+	return err
+}
+
+func (s *vaultSink) Delete(ctx context.Context, skrClusterID string) error {
+	path, err := s.pathTemplate.Render(skrClusterID)
+	if err != nil {
+		return errors.Wrap(err, "failed to render path of the credentials")
+	}
+	// Deleting the metadata deletes all versions of the credentials. Vault responds with success if the path doesn't exist.
+	_, err = s.do(ctx, http.MethodDelete, s.kvURL("metadata", path), nil)
+	return err
+}
+
+func (s *vaultSink) kvURL(kind, path string) string {
+	return fmt.Sprintf("%s/v1/%s/%s/%s", strings.TrimSuffix(s.config.Address, "/"), s.config.KVMount, kind, strings.TrimPrefix(path, "/"))
+}
+
+// do sends an authenticated request to Vault. If Vault rejects the token, the manager logs in again and the request is retried once.
+func (s *vaultSink) do(ctx context.Context, method, url string, body []byte) ([]byte, error) {
+	token, err := s.getToken(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	res, status, err := s.send(ctx, method, url, body, token)
+	if status == http.StatusForbidden {
+		if token, err = s.getToken(ctx, true); err != nil {
+			return nil, err
+		}
+		res, _, err = s.send(ctx, method, url, body, token)
+	}
+	return res, err
+}
+
+func (s *vaultSink) getToken(ctx context.Context, refresh bool) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !refresh && s.token != "" && (s.renewAt.IsZero() || s.now().Before(s.renewAt)) {
+		return s.token, nil
+	}
+
+	jwt, err := s.readFile(s.config.ServiceAccountTokenFile)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read service account token")
+	}
+	body, err := json.Marshal(map[string]string{"role": s.config.Role, "jwt": strings.TrimSpace(string(jwt))})
+	if err != nil {
+		return "", err
+	}
+
+	requestedAt := s.now()
+	url := fmt.Sprintf("%s/v1/auth/%s/login", strings.TrimSuffix(s.config.Address, "/"), s.config.AuthMount)
+	res, _, err := s.send(ctx, http.MethodPost, url, body, "")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to log in to Vault")
+	}
+
+	var login struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int64  `json:"lease_duration"`
+		} `json:"auth"`
+	}
+	if err := json.Unmarshal(res, &login); err != nil {
+		return "", errors.Wrap(err, "failed to parse login response of Vault")
+	}
+	if login.Auth.ClientToken == "" {
+		return "", errors.New("login response of Vault doesn't contain a token")
+	}
+
+	s.token = login.Auth.ClientToken
+	// The token is renewed before its lease expires, so that requests never use an expired token. A lease duration of 0 is returned for
+	// tokens that don't expire, e.g. periodic tokens, which are only renewed once Vault rejects them.
+	s.renewAt = time.Time{}
+	if login.Auth.LeaseDuration > 0 {
+		s.renewAt = requestedAt.Add(time.Duration(login.Auth.LeaseDuration) * time.Second * vaultTokenRenewalPercent / 100)
+	}
+	return s.token, nil
+}
+
+func (s *vaultSink) send(ctx context.Context, method, url string, body []byte, token string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set(vaultTokenHeader, token)
+	}
+	if s.config.Namespace != "" {
+		req.Header.Set(vaultNamespaceHeader, s.config.Namespace)
+	}
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, res.StatusCode, err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return nil, res.StatusCode, errors.Errorf("unexpected status code %d from Vault: %s", res.StatusCode, strings.TrimSpace(string(resBody)))
+	}
+	return resBody, res.StatusCode, nil
+}
@@ -0,0 +1,226 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest/fake"
+)
+
+type vaultRequest struct {
+	method    string
+	path      string
+	token     string
+	namespace string
+	body      map[string]interface{}
+}
+
+// newVaultMock returns an HTTP client that records the requests to Vault. Logins return the tokens 'token-1', 'token-2', ... with a lease
+// of 100 seconds. Requests with a token that is contained in rejectedTokens are rejected with 403.
+func newVaultMock(t *testing.T, requests *[]vaultRequest, rejectedTokens ...string) *http.Client {
+	t.Helper()
+	return newVaultMockWithLease(t, requests, 100, rejectedTokens...)
+}
+
+// newVaultMockWithLease returns the HTTP client of newVaultMock, whose logins return tokens with a lease of leaseDuration seconds.
+func newVaultMockWithLease(t *testing.T, requests *[]vaultRequest, leaseDuration int, rejectedTokens ...string) *http.Client {
+	t.Helper()
+	logins := 0
+	return fake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+		r := vaultRequest{
+			method:    req.Method,
+			path:      req.URL.Path,
+			token:     req.Header.Get(vaultTokenHeader),
+			namespace: req.Header.Get(vaultNamespaceHeader),
+		}
+		if req.Body != nil {
+			b, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			if len(b) > 0 {
+				require.NoError(t, json.Unmarshal(b, &r.body))
+			}
+		}
+		*requests = append(*requests, r)
+
+		if req.URL.Path == "/v1/auth/kubernetes/login" {
+			logins++
+			body, err := json.Marshal(map[string]interface{}{"auth": map[string]interface{}{
+				"client_token":   "token-" + string(rune('0'+logins)),
+				"lease_duration": leaseDuration,
+			}})
+			require.NoError(t, err)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body))}, nil
+		}
+		for _, rejected := range rejectedTokens {
+			if r.token == rejected {
+				return &http.Response{StatusCode: http.StatusForbidden, Body: io.NopCloser(bytes.NewReader([]byte(`{"errors":["permission denied"]}`)))}, nil
+			}
+		}
+		return &http.Response{StatusCode: http.StatusNoContent, Body: io.NopCloser(bytes.NewReader(nil))}, nil
+	})
+}
+
+func newTestVaultSink(t *testing.T, httpClient *http.Client, now *time.Time) *vaultSink {
+	t.Helper()
+	s, err := NewVaultSink(httpClient, VaultConfig{
+		Address:                 "https://vault.example.com/",
+		Namespace:               "kyma",
+		AuthMount:               DefaultVaultAuthMount,
+		Role:                    "eventing-auth-manager",
+		ServiceAccountTokenFile: DefaultVaultServiceAccountToken,
+		KVMount:                 DefaultVaultKVMount,
+		PathTemplate:            DefaultVaultPathTemplate,
+	})
+	require.NoError(t, err)
+	vs := s.(*vaultSink) //nolint:forcetypeassert // The Vault sink is always a *vaultSink.
+	vs.now = func() time.Time { return *now }
+	vs.readFile = func(string) ([]byte, error) { return []byte("sa-token\n"), nil }
+	return vs
+}
+
+func Test_vaultSink_Write(t *testing.T) {
+	// given
+	var requests []vaultRequest
+	now := time.Now()
+	s := newTestVaultSink(t, newVaultMock(t, &requests), &now)
+	app := eamias.NewApplication("app-id", "client-id", "client-secret", "https://token.url", "https://certs.url")
+
+	// when
+	require.NoError(t, s.Write(context.TODO(), "runtime-id", app))
+
+	// then the manager logs in with its service account token and writes the credentials
+	require.Len(t, requests, 2)
+	require.Equal(t, vaultRequest{
+		method:    http.MethodPost,
+		path:      "/v1/auth/kubernetes/login",
+		namespace: "kyma",
+		body:      map[string]interface{}{"role": "eventing-auth-manager", "jwt": "sa-token"},
+	}, requests[0])
+	require.Equal(t, vaultRequest{
+		method:    http.MethodPost,
+		path:      "/v1/secret/data/eventing-auth/runtime-id",
+		token:     "token-1",
+		namespace: "kyma",
+		body: map[string]interface{}{"data": map[string]interface{}{
+			eamias.SecretKeyClientID:     "client-id",
+			eamias.SecretKeyClientSecret: "client-secret",
+			eamias.SecretKeyTokenURL:     "https://token.url",
+			eamias.SecretKeyCertsURL:     "https://certs.url",
+		}},
+	}, requests[1])
+
+	// when the credentials are written again before the token must be renewed
+	now = now.Add(time.Second * 79)
+	require.NoError(t, s.Write(context.TODO(), "runtime-id", app))
+
+	// then the token is reused
+	require.Len(t, requests, 3)
+	require.Equal(t, "token-1", requests[2].token)
+
+	// when the token must be renewed
+	now = now.Add(time.Second)
+	require.NoError(t, s.Write(context.TODO(), "runtime-id", app))
+
+	// then the manager logs in again
+	require.Len(t, requests, 5)
+	require.Equal(t, "/v1/auth/kubernetes/login", requests[3].path)
+	require.Equal(t, "token-2", requests[4].token)
+}
+
+func Test_vaultSink_Write_NonExpiringToken(t *testing.T) {
+	// given
+	var requests []vaultRequest
+	now := time.Now()
+	s := newTestVaultSink(t, newVaultMockWithLease(t, &requests, 0), &now)
+	app := eamias.NewApplication("app-id", "client-id", "client-secret", "https://token.url", "https://certs.url")
+	require.NoError(t, s.Write(context.TODO(), "runtime-id", app))
+
+	// when the credentials are written again much later
+	now = now.Add(time.Hour * 24)
+	require.NoError(t, s.Write(context.TODO(), "runtime-id", app))
+
+	// then the token without a lease is reused
+	require.Len(t, requests, 3)
+	require.Equal(t, "token-1", requests[2].token)
+}
+
+func Test_vaultSink_Write_RejectedToken(t *testing.T) {
+	// given
+	var requests []vaultRequest
+	now := time.Now()
+	s := newTestVaultSink(t, newVaultMock(t, &requests, "token-1"), &now)
+	app := eamias.NewApplication("app-id", "client-id", "client-secret", "https://token.url", "https://certs.url")
+
+	// when
+	require.NoError(t, s.Write(context.TODO(), "runtime-id", app))
+
+	// then the manager logs in again and retries the request with the new token
+	require.Len(t, requests, 4)
+	require.Equal(t, "token-1", requests[1].token)
+	require.Equal(t, "/v1/auth/kubernetes/login", requests[2].path)
+	require.Equal(t, "token-2", requests[3].token)
+
+	// given a sink whose tokens are always rejected
+	requests = nil
+	s = newTestVaultSink(t, newVaultMock(t, &requests, "token-1", "token-2"), &now)
+
+	// when
+	err := s.Write(context.TODO(), "runtime-id", app)
+
+	// then the request is retried only once
+	require.ErrorContains(t, err, "unexpected status code 403")
+	require.Len(t, requests, 4)
+}
+
+func Test_vaultSink_Delete(t *testing.T) {
+	// given
+	var requests []vaultRequest
+	now := time.Now()
+	s := newTestVaultSink(t, newVaultMock(t, &requests), &now)
+
+	// when
+	require.NoError(t, s.Delete(context.TODO(), "runtime-id"))
+
+	// then all versions of the credentials are deleted
+	require.Len(t, requests, 2)
+	require.Equal(t, http.MethodDelete, requests[1].method)
+	require.Equal(t, "/v1/secret/metadata/eventing-auth/runtime-id", requests[1].path)
+	require.Equal(t, "token-1", requests[1].token)
+}
+
+func Test_NewVaultSink(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  VaultConfig
+		wantErr string
+	}{
+		{
+			name:    "should fail without address",
+			config:  VaultConfig{Role: "role", PathTemplate: DefaultVaultPathTemplate},
+			wantErr: "address and role of Vault must not be empty",
+		},
+		{
+			name:    "should fail without role",
+			config:  VaultConfig{Address: "https://vault.example.com", PathTemplate: DefaultVaultPathTemplate},
+			wantErr: "address and role of Vault must not be empty",
+		},
+		{
+			name:    "should fail with invalid path template",
+			config:  VaultConfig{Address: "https://vault.example.com", Role: "role", PathTemplate: "{{ .RuntimeID"},
+			wantErr: "invalid path template of Vault",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewVaultSink(http.DefaultClient, tt.config)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
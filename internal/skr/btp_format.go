@@ -0,0 +1,75 @@
+package skr
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+)
+
+// Keys of the entries that the SAP BTP service operator adds to the secrets of service bindings.
+const (
+	BTPServiceOperatorMetadataKey = ".metadata"
+	btpURLKey                     = "url"
+	btpTypeKey                    = "type"
+	btpLabelKey                   = "label"
+	btpPlanKey                    = "plan"
+	btpIdentityOffering           = "identity"
+	btpIdentityPlan               = "application"
+	btpPropertyFormatText         = "text"
+)
+
+// btpKeyMapping maps the data keys of the application secret to the keys of the credentials of IAS service bindings.
+//
+//nolint:gochecknoglobals // Used as constant.
+var btpKeyMapping = map[string]string{
+	eamias.SecretKeyClientID:     "clientid",
+	eamias.SecretKeyClientSecret: "clientsecret",
+}
+
+type btpProperty struct {
+	Name   string `json:"name"`
+	Format string `json:"format"`
+}
+
+type btpMetadata struct {
+	CredentialProperties []btpProperty `json:"credentialProperties"`
+	MetaDataProperties   []btpProperty `json:"metaDataProperties"`
+}
+
+// addBTPServiceOperatorEntries adds the entries of the secrets of IAS service bindings created by the SAP BTP service operator to the
+// given data, whose keys must already be mapped with btpKeyMapping.
+func addBTPServiceOperatorEntries(data map[string][]byte) {
+	if tenantURL, ok := btpTenantURL(string(data[eamias.SecretKeyTokenURL])); ok {
+		data[btpURLKey] = tenantURL
+	}
+
+	metadata := btpMetadata{}
+	for key := range data {
+		metadata.CredentialProperties = append(metadata.CredentialProperties, btpProperty{Name: key, Format: btpPropertyFormatText})
+	}
+	// The order of the properties must be stable, so that the secret isn't changed on every reconciliation.
+	sort.Slice(metadata.CredentialProperties, func(i, j int) bool {
+		return metadata.CredentialProperties[i].Name < metadata.CredentialProperties[j].Name
+	})
+	for _, key := range []string{btpTypeKey, btpLabelKey, btpPlanKey} {
+		metadata.MetaDataProperties = append(metadata.MetaDataProperties, btpProperty{Name: key, Format: btpPropertyFormatText})
+	}
+
+	data[btpTypeKey] = []byte(btpIdentityOffering)
+	data[btpLabelKey] = []byte(btpIdentityOffering)
+	data[btpPlanKey] = []byte(btpIdentityPlan)
+	// Marshalling the metadata can't fail, since it only contains strings.
+	b, _ := json.Marshal(metadata) //nolint:errchkjson // See above.
+	data[BTPServiceOperatorMetadataKey] = b
+}
+
+// btpTenantURL returns the URL of IAS service bindings, which is the URL of the IAS tenant and the origin of the token URL.
+func btpTenantURL(tokenURL string) ([]byte, bool) {
+	parsed, err := url.Parse(tokenURL)
+	if err != nil || parsed.Host == "" {
+		return nil, false
+	}
+	return []byte(parsed.Scheme + "://" + parsed.Host), true
+}
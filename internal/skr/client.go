@@ -1,95 +1,664 @@
 package skr
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"time"
 
+	"github.com/kyma-project/eventing-auth-manager/internal/deadline"
+	"github.com/kyma-project/eventing-auth-manager/internal/dryrun"
+	"github.com/kyma-project/eventing-auth-manager/internal/faultinjection"
 	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/kyma-project/eventing-auth-manager/internal/tracing"
 	"github.com/pkg/errors"
 	kcorev1 "k8s.io/api/core/v1"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/ptr"
 	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	// Register the OIDC auth provider, so that kubeconfigs of SKR clusters can use it independently of the imports of the manager.
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 )
 
+var errExecPluginNotAllowed = errors.New("kubeconfig uses an exec credential plugin, which is not allowed")
+
+// writeConflictBackoff retries the writes to an SKR cluster that conflict with concurrent writers, e.g. backup tooling or policy controllers
+// that update the application secret between its read and its update. Every retry reads the object again before it is modified.
+var writeConflictBackoff = wait.Backoff{ //nolint:gochecknoglobals // Read-only backoff of the writes, overridden in tests.
+	Steps:    5,
+	Duration: 50 * time.Millisecond,
+	Factor:   2,
+	Jitter:   0.1,
+}
+
 const (
 	ApplicationSecretName      = "eventing-webhook-auth"
 	ApplicationSecretNamespace = "kyma-system"
 	KcpNamespace               = "kcp-system"
+	ManagedByLabelKey          = "app.kubernetes.io/managed-by"
+	ManagedByLabelValue        = "eventing-auth-manager"
+	// PublisherSecretName is the name of the secret with the credentials of the separate application of the event publisher.
+	PublisherSecretName = "eventing-publisher-auth"
+	// DefaultClientQPS and DefaultClientBurst are the client-go defaults of the rate limiting of a client.
+	DefaultClientQPS   = 5
+	DefaultClientBurst = 10
+	// DefaultRequestTimeout is the default deadline of every request to an SKR cluster.
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+// SecretConflictPolicy defines how an application secret is handled that already exists on the SKR cluster, but wasn't created by the manager.
+type SecretConflictPolicy string
+
+const (
+	// SecretConflictPolicyAdopt adopts the existing secret by stamping the managed-by label on it.
+	SecretConflictPolicyAdopt SecretConflictPolicy = "adopt"
+	// SecretConflictPolicyFail leaves the existing secret untouched and fails the reconciliation.
+	SecretConflictPolicyFail SecretConflictPolicy = "fail"
 )
 
 type Client interface {
 	DeleteSecret(ctx context.Context) error
 	HasApplicationSecret(ctx context.Context) (bool, error)
-	CreateSecret(ctx context.Context, app eamias.Application) (kcorev1.Secret, error)
+	CreateSecret(ctx context.Context, app eamias.Application, secretOpts SecretOptions) (kcorev1.Secret, error)
+	SyncApplicationSecret(ctx context.Context, secretOpts SecretOptions) (*kcorev1.Secret, error)
+	SyncDiscovery(ctx context.Context, tokenURL, certsURL string, secretOpts SecretOptions) (bool, error)
+	VerifySecret(ctx context.Context, expected kcorev1.Secret) error
+	HasApplicationCredentials(ctx context.Context, app eamias.Application) (bool, error)
+	WatchApplicationSecret(ctx context.Context) (watch.Interface, error)
+	ApplyObject(ctx context.Context, obj kpkgclient.Object) error
+	DeleteObject(ctx context.Context, obj kpkgclient.Object) error
+	CreatePublisherSecret(ctx context.Context, app eamias.Application, secretOpts SecretOptions) (kcorev1.Secret, error)
+	HasPublisherSecret(ctx context.Context) (bool, error)
+	DeletePublisherSecret(ctx context.Context) error
+	GetServiceProxy(ctx context.Context, namespace, name, port, path string) ([]byte, error)
+	ListConsumers(ctx context.Context, perSubscription bool) ([]Consumer, error)
+	WatchSubscriptions(ctx context.Context) (watch.Interface, error)
+	CreateConsumerSecret(ctx context.Context, app eamias.Application, consumer Consumer, secretOpts SecretOptions) (kcorev1.Secret, error)
+	HasConsumerSecret(ctx context.Context, consumer Consumer) (bool, error)
+	DeleteConsumerSecret(ctx context.Context, consumer Consumer) error
 }
 
 type client struct {
 	k8sClient kpkgclient.Client
+	clientset kubernetes.Interface
+	opts      ClientOptions
+}
+
+// SecretOptions configures the application secret of a single SKR cluster.
+type SecretOptions struct {
+	// Immutable defines if the application secret is immutable. Since the data of an immutable secret can't be updated, the secret is
+	// deleted and created again if its data changes.
+	Immutable bool
+}
+
+// ClientOptions configures the clients of the SKR clusters.
+type ClientOptions struct {
+	// AllowExecPlugins defines if kubeconfigs are allowed to use exec credential plugins. An exec credential plugin runs an arbitrary
+	// command in the manager container, so it should only be allowed if the kubeconfig secrets are trusted.
+	AllowExecPlugins bool
+	// CreateNamespace defines if the namespace of the application secret is created if it doesn't exist yet, e.g. on a freshly provisioned SKR cluster.
+	CreateNamespace bool
+	// SecretNamespaces defines the namespaces to which the application secret is replicated. The first namespace is the primary namespace,
+	// whose secret is the source of the replicas and is reported in the status of the EventingAuth CR. Defaults to 'kyma-system'.
+	SecretNamespaces []string
+	// SecretNamespaceSelector selects additional namespaces to which the application secret is replicated. The secret isn't removed from
+	// namespaces that stop matching the selector.
+	SecretNamespaceSelector labels.Selector
+	// SecretConflictPolicy defines how application secrets are handled that already exist, but weren't created by the manager. Defaults to
+	// adopting them, so that secrets created by previous versions of the manager, which didn't label their secrets, are adopted as well.
+	SecretConflictPolicy SecretConflictPolicy
+	// QPS defines the maximum queries per second of the client of each SKR cluster. Defaults to the client-go default if not set.
+	QPS float32
+	// SecretFormat defines the type and the data keys of the application secret. Changes of the key mapping are only applied to application
+	// secrets that are created afterwards.
+	SecretFormat SecretFormat
+	// Burst defines the maximum burst of queries of the client of each SKR cluster. Defaults to the client-go default if not set.
+	Burst int
+	// DryRun defines if the writes to the SKR clusters are only logged instead of being sent. The written secrets aren't verified, since
+	// they are never written.
+	DryRun bool
+	// Faults are injected into the requests to the SKR clusters, e.g. for chaos tests.
+	Faults faultinjection.Config
+	// RequestTimeout is the deadline of every request to the SKR clusters except watches, which is derived from the context of the caller.
+	// The requests aren't limited if it is 0.
+	RequestTimeout time.Duration
 }
 
-var NewClient = func(k8sClient kpkgclient.Client, skrClusterID string) (Client, error) { //nolint:gochecknoglobals // For mocking purposes.
-	kubeconfigSecretName := fmt.Sprintf("kubeconfig-%s", skrClusterID)
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		AllowExecPlugins:     true,
+		SecretNamespaces:     []string{ApplicationSecretNamespace},
+		SecretConflictPolicy: SecretConflictPolicyAdopt,
+		QPS:                  DefaultClientQPS,
+		Burst:                DefaultClientBurst,
+		RequestTimeout:       DefaultRequestTimeout,
+	}
+}
 
-	secret := &kcorev1.Secret{}
-	if err := k8sClient.Get(context.Background(), types.NamespacedName{Name: kubeconfigSecretName, Namespace: KcpNamespace}, secret); err != nil {
+// NewClient returns a new client for the SKR cluster defined by the given kubeconfig. Besides client certificates and tokens, kubeconfigs
+// can use exec credential plugins, if allowed by the options, and the OIDC auth provider.
+var NewClient = func(kubeconfig []byte, opts ClientOptions) (Client, error) { //nolint:gochecknoglobals // For mocking purposes.
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
 		return nil, err
 	}
 
-	kubeconfig := secret.Data["config"]
-	if len(kubeconfig) == 0 {
-		return nil, errors.Errorf("failed to find SKR cluster kubeconfig in secret %s", kubeconfigSecretName)
+	if config.ExecProvider != nil {
+		if !opts.AllowExecPlugins {
+			return nil, errExecPluginNotAllowed
+		}
+		// The manager runs without a terminal, so an exec credential plugin must never wait for user input.
+		config.ExecProvider.InteractiveMode = clientcmdapi.NeverExecInteractiveMode
 	}
 
-	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if opts.QPS > 0 {
+		config.QPS = opts.QPS
+	}
+	if opts.Burst > 0 {
+		config.Burst = opts.Burst
+	}
+	if opts.Faults.Enabled() {
+		config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return faultinjection.NewTransport(rt, opts.Faults)
+		})
+	}
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return tracing.NewTransport(rt, func(req *http.Request) string { return "SKR " + req.Method })
+	})
+	// The deadline isn't set with the timeout of the REST config, since that timeout would end the watches of the application secrets.
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return deadline.NewTransport(rt, opts.RequestTimeout, deadline.IsWatch)
+	})
+
+	var c kpkgclient.Client
+	c, err = kpkgclient.NewWithWatch(config, kpkgclient.Options{})
 	if err != nil {
 		return nil, err
 	}
+	if opts.DryRun {
+		c = dryrun.NewClient(c, false)
+	}
 
-	c, err := kpkgclient.New(config, kpkgclient.Options{})
+	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
 
-	return &client{k8sClient: c}, nil
+	return &client{k8sClient: c, clientset: clientset, opts: opts}, nil
 }
 
 func (c *client) DeleteSecret(ctx context.Context) error {
-	var s kcorev1.Secret
-	if err := c.k8sClient.Get(ctx, kpkgclient.ObjectKey{
-		Name:      ApplicationSecretName,
-		Namespace: ApplicationSecretNamespace,
-	}, &s); err != nil {
-		return kpkgclient.IgnoreNotFound(err)
-	}
+	return c.deleteSecret(ctx, ApplicationSecretName)
+}
+
+// DeletePublisherSecret deletes the publisher secret from all target namespaces.
+func (c *client) DeletePublisherSecret(ctx context.Context) error {
+	return c.deleteSecret(ctx, PublisherSecretName)
+}
 
-	if err := c.k8sClient.Delete(ctx, &s); err != nil {
+// deleteSecret deletes the secret with the given name from all target namespaces.
+func (c *client) deleteSecret(ctx context.Context, name string) error {
+	namespaces, err := c.secretNamespaces(ctx)
+	if err != nil {
 		return err
 	}
+
+	for _, namespace := range namespaces {
+		var s kcorev1.Secret
+		if err := c.k8sClient.Get(ctx, kpkgclient.ObjectKey{
+			Name:      name,
+			Namespace: namespace,
+		}, &s); err != nil {
+			if kapierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		// Secrets that are not managed by the manager are only deleted if they would have been adopted anyway.
+		if !isManaged(&s) && c.opts.SecretConflictPolicy == SecretConflictPolicyFail {
+			continue
+		}
+
+		if err := c.k8sClient.Delete(ctx, &s); kpkgclient.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (c *client) CreateSecret(ctx context.Context, app eamias.Application) (kcorev1.Secret, error) {
-	appSecret := app.ToSecret(ApplicationSecretName, ApplicationSecretNamespace)
-	err := c.k8sClient.Create(ctx, &appSecret)
-	return appSecret, err
+// CreateSecret creates the application secret in all target namespaces and returns the secret of the first namespace. Replicas that
+// already exist are updated with the credentials of the given application.
+func (c *client) CreateSecret(ctx context.Context, app eamias.Application, secretOpts SecretOptions) (kcorev1.Secret, error) {
+	return c.createSecret(ctx, ApplicationSecretName, app, secretOpts)
 }
 
-func (c *client) HasApplicationSecret(ctx context.Context) (bool, error) {
-	var s kcorev1.Secret
-	err := c.k8sClient.Get(ctx, kpkgclient.ObjectKey{
-		Name:      ApplicationSecretName,
-		Namespace: ApplicationSecretNamespace,
-	}, &s)
+// CreatePublisherSecret creates the publisher secret with the credentials of the publisher application in all target namespaces and returns
+// the secret of the first namespace.
+func (c *client) CreatePublisherSecret(ctx context.Context, app eamias.Application, secretOpts SecretOptions) (kcorev1.Secret, error) {
+	return c.createSecret(ctx, PublisherSecretName, app, secretOpts)
+}
+
+// createSecret creates the secret with the given name and the credentials of the application in all target namespaces and returns the
+// secret of the first namespace.
+func (c *client) createSecret(ctx context.Context, name string, app eamias.Application, secretOpts SecretOptions) (kcorev1.Secret, error) {
+	if c.opts.CreateNamespace {
+		for _, namespace := range c.configuredSecretNamespaces() {
+			if err := c.ensureNamespace(ctx, namespace); err != nil {
+				return kcorev1.Secret{}, err
+			}
+		}
+	}
 
-	if kapierrors.IsNotFound(err) {
+	namespaces, err := c.secretNamespaces(ctx)
+	if err != nil {
+		return kcorev1.Secret{}, err
+	}
+
+	var primarySecret kcorev1.Secret
+	for i, namespace := range namespaces {
+		appSecret := app.ToSecret(name, namespace)
+		appSecret.Labels = map[string]string{ManagedByLabelKey: ManagedByLabelValue}
+		appSecret.Immutable = immutable(secretOpts)
+		c.opts.SecretFormat.apply(&appSecret)
+		if err := c.applySecret(ctx, &appSecret); err != nil {
+			return kcorev1.Secret{}, err
+		}
+		if i == 0 {
+			primarySecret = appSecret
+		}
+	}
+	return primarySecret, nil
+}
+
+// SyncApplicationSecret replicates the application secret of the first target namespace to all other target namespaces in which the
+// replica is missing or outdated. If the secret doesn't exist in the first namespace, an existing replica is used as source. It returns
+// the secret of the first target namespace, or nil if the secret doesn't exist in any of the target namespaces, so that it needs to be created.
+func (c *client) SyncApplicationSecret(ctx context.Context, secretOpts SecretOptions) (*kcorev1.Secret, error) {
+	namespaces, err := c.secretNamespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := map[string]*kcorev1.Secret{}
+	var source *kcorev1.Secret
+	for _, namespace := range namespaces {
+		s := &kcorev1.Secret{}
+		err := c.k8sClient.Get(ctx, kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: namespace}, s)
+		if kapierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := c.adoptSecret(ctx, s); err != nil {
+			return nil, err
+		}
+		replicas[namespace] = s
+		if source == nil {
+			source = s
+		}
+	}
+	if source == nil {
+		return nil, nil
+	}
+
+	for _, namespace := range namespaces {
+		replica, ok := replicas[namespace]
+		if ok && reflect.DeepEqual(replica.Data, source.Data) && isImmutable(replica) == secretOpts.Immutable &&
+			replica.Type == c.opts.SecretFormat.secretType() {
+			continue
+		}
+		s := &kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{
+				Name:      ApplicationSecretName,
+				Namespace: namespace,
+				Labels:    source.Labels,
+			},
+			Type:      c.opts.SecretFormat.secretType(),
+			Data:      source.Data,
+			Immutable: immutable(secretOpts),
+		}
+		if err := c.applySecret(ctx, s); err != nil {
+			return nil, err
+		}
+		replicas[namespace] = s
+	}
+	return replicas[namespaces[0]], nil
+}
+
+// SyncDiscovery replaces the token URL and the JWKS URI of the existing application secrets in all target namespaces, if they differ from
+// the given endpoints, e.g. because the endpoints of the tenant moved to new hosts. The credentials of the secrets are kept. It returns true
+// if a secret was updated.
+func (c *client) SyncDiscovery(ctx context.Context, tokenURL, certsURL string, secretOpts SecretOptions) (bool, error) {
+	namespaces, err := c.secretNamespaces(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	updated := false
+	for _, namespace := range namespaces {
+		existing := &kcorev1.Secret{}
+		err := c.k8sClient.Get(ctx, kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: namespace}, existing)
+		if kapierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+		data := c.opts.SecretFormat.withDiscovery(existing.Data, tokenURL, certsURL)
+		if reflect.DeepEqual(data, existing.Data) {
+			continue
+		}
+		s := &kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{
+				Name:      ApplicationSecretName,
+				Namespace: namespace,
+				Labels:    existing.Labels,
+			},
+			Type:      existing.Type,
+			Data:      data,
+			Immutable: immutable(secretOpts),
+		}
+		if err := c.applySecret(ctx, s); err != nil {
+			return false, err
+		}
+		updated = true
+	}
+	return updated, nil
+}
+
+// VerifySecret reads the application secret back from all target namespaces and verifies that it contains the data of the expected secret.
+// The client of the SKR cluster isn't cached, so the secret is always read from the API server of the SKR cluster. In a dry run, the secret
+// isn't verified.
+func (c *client) VerifySecret(ctx context.Context, expected kcorev1.Secret) error {
+	if c.opts.DryRun {
+		return nil
+	}
+	namespaces, err := c.secretNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, namespace := range namespaces {
+		var s kcorev1.Secret
+		err := c.k8sClient.Get(ctx, kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: namespace}, &s)
+		if kapierrors.IsNotFound(err) {
+			return &SecretVerificationError{Namespace: namespace, Name: ApplicationSecretName, Reason: "secret not found"}
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read back secret %s/%s", namespace, ApplicationSecretName)
+		}
+		for key, value := range expected.Data {
+			if !bytes.Equal(s.Data[key], value) {
+				return &SecretVerificationError{Namespace: namespace, Name: ApplicationSecretName, Reason: fmt.Sprintf("unexpected value of key %s", key)}
+			}
+		}
+	}
+	return nil
+}
+
+// HasApplicationCredentials returns true if the application secret in all target namespaces contains the credentials of the application in
+// the format of the client, so that a drift of the secret from the credentials can be corrected.
+func (c *client) HasApplicationCredentials(ctx context.Context, app eamias.Application) (bool, error) {
+	expected := app.ToSecret(ApplicationSecretName, "")
+	c.opts.SecretFormat.apply(&expected)
+	err := c.VerifySecret(ctx, expected)
+	var verificationErr *SecretVerificationError
+	if errors.As(err, &verificationErr) {
 		return false, nil
 	}
+	return err == nil, err
+}
 
+// WatchApplicationSecret watches the managed application secrets in all namespaces of the SKR cluster.
+func (c *client) WatchApplicationSecret(ctx context.Context) (watch.Interface, error) {
+	watchClient, ok := c.k8sClient.(kpkgclient.WithWatch)
+	if !ok {
+		return nil, errors.New("client of the SKR cluster doesn't support watches")
+	}
+	return watchClient.Watch(ctx, &kcorev1.SecretList{},
+		kpkgclient.MatchingLabels{ManagedByLabelKey: ManagedByLabelValue},
+		kpkgclient.MatchingFields{"metadata.name": ApplicationSecretName},
+	)
+}
+
+// applySecret creates the given secret or updates the data of the secret if it already exists. A write that conflicts with a concurrent
+// writer is retried with writeConflictBackoff.
+func (c *client) applySecret(ctx context.Context, s *kcorev1.Secret) error {
+	return retry.OnError(writeConflictBackoff, isWriteConflict(ctx), func() error {
+		return c.applySecretOnce(ctx, s)
+	})
+}
+
+// applySecretOnce creates the given secret, or reads the existing secret and updates its data.
+func (c *client) applySecretOnce(ctx context.Context, s *kcorev1.Secret) error {
+	err := c.k8sClient.Create(ctx, s)
+	if err == nil {
+		return nil
+	}
+	if !kapierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create secret %s/%s", s.Namespace, s.Name)
+	}
+
+	var existing kcorev1.Secret
+	if err := c.k8sClient.Get(ctx, kpkgclient.ObjectKeyFromObject(s), &existing); err != nil {
+		return errors.Wrapf(err, "failed to retrieve secret %s/%s", s.Namespace, s.Name)
+	}
+	if !isManaged(&existing) && c.opts.SecretConflictPolicy == SecretConflictPolicyFail {
+		return &SecretNotManagedError{Namespace: existing.Namespace, Name: existing.Name}
+	}
+	if isImmutable(&existing) != isImmutable(s) || (isImmutable(&existing) && !reflect.DeepEqual(existing.Data, s.Data)) || existing.Type != s.Type {
+		return c.recreateSecret(ctx, &existing, s)
+	}
+
+	if existing.Labels == nil {
+		existing.Labels = map[string]string{}
+	}
+	existing.Labels[ManagedByLabelKey] = ManagedByLabelValue
+	existing.Data = s.Data
+	if err := c.k8sClient.Update(ctx, &existing); err != nil {
+		return errors.Wrapf(err, "failed to update secret %s/%s", s.Namespace, s.Name)
+	}
+	*s = existing
+	return nil
+}
+
+// recreateSecret deletes the existing secret and creates the given secret instead. This is required to change the data of an immutable secret,
+// the mutability of a secret, or the type of a secret. The secret is missing on the SKR cluster until it is created again.
+func (c *client) recreateSecret(ctx context.Context, existing, s *kcorev1.Secret) error {
+	if err := c.k8sClient.Delete(ctx, existing, kpkgclient.Preconditions{UID: &existing.UID}); kpkgclient.IgnoreNotFound(err) != nil {
+		return errors.Wrapf(err, "failed to delete secret %s/%s for recreation", existing.Namespace, existing.Name)
+	}
+	s.ResourceVersion = ""
+	if err := c.k8sClient.Create(ctx, s); err != nil {
+		return errors.Wrapf(err, "failed to recreate secret %s/%s", s.Namespace, s.Name)
+	}
+	return nil
+}
+
+func isImmutable(s *kcorev1.Secret) bool {
+	return s.Immutable != nil && *s.Immutable
+}
+
+func immutable(secretOpts SecretOptions) *bool {
+	if !secretOpts.Immutable {
+		return nil
+	}
+	return ptr.To(true)
+}
+
+// adoptSecret stamps the managed-by label on the given secret if it wasn't created by the manager. If the conflict policy doesn't allow
+// adopting secrets, a SecretNotManagedError is returned instead.
+func (c *client) adoptSecret(ctx context.Context, s *kcorev1.Secret) error {
+	if isManaged(s) {
+		return nil
+	}
+	if c.opts.SecretConflictPolicy == SecretConflictPolicyFail {
+		return &SecretNotManagedError{Namespace: s.Namespace, Name: s.Name}
+	}
+
+	// The secret is read again before a retry of a conflicting update, since it was changed by another writer meanwhile.
+	reread := false
+	err := retry.OnError(writeConflictBackoff, isWriteConflict(ctx), func() error {
+		if reread {
+			if err := c.k8sClient.Get(ctx, kpkgclient.ObjectKeyFromObject(s), s); err != nil {
+				return err
+			}
+			if isManaged(s) {
+				return nil
+			}
+		}
+		reread = true
+		if s.Labels == nil {
+			s.Labels = map[string]string{}
+		}
+		s.Labels[ManagedByLabelKey] = ManagedByLabelValue
+		return c.k8sClient.Update(ctx, s)
+	})
+	return errors.Wrapf(err, "failed to adopt secret %s/%s", s.Namespace, s.Name)
+}
+
+func isManaged(s *kcorev1.Secret) bool {
+	return s.Labels[ManagedByLabelKey] == ManagedByLabelValue
+}
+
+// configuredSecretNamespaces returns the explicitly configured target namespaces of the application secret.
+func (c *client) configuredSecretNamespaces() []string {
+	if len(c.opts.SecretNamespaces) == 0 {
+		return []string{ApplicationSecretNamespace}
+	}
+	return c.opts.SecretNamespaces
+}
+
+// secretNamespaces returns the configured target namespaces of the application secret followed by the namespaces matching the
+// namespace selector. Terminating namespaces are skipped, because secrets can't be created in them.
+func (c *client) secretNamespaces(ctx context.Context) ([]string, error) {
+	namespaces := slices.Clone(c.configuredSecretNamespaces())
+	if c.opts.SecretNamespaceSelector == nil || c.opts.SecretNamespaceSelector.Empty() {
+		return namespaces, nil
+	}
+
+	var nsList kcorev1.NamespaceList
+	if err := c.k8sClient.List(ctx, &nsList, kpkgclient.MatchingLabelsSelector{Selector: c.opts.SecretNamespaceSelector}); err != nil {
+		return nil, errors.Wrap(err, "failed to list namespaces matching the selector")
+	}
+	for _, ns := range nsList.Items {
+		if ns.Status.Phase == kcorev1.NamespaceTerminating || slices.Contains(namespaces, ns.Name) {
+			continue
+		}
+		namespaces = append(namespaces, ns.Name)
+	}
+	return namespaces, nil
+}
+
+func (c *client) ensureNamespace(ctx context.Context, name string) error {
+	var ns kcorev1.Namespace
+	err := c.k8sClient.Get(ctx, kpkgclient.ObjectKey{Name: name}, &ns)
+	if err == nil {
+		return nil
+	}
+	if !kapierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to retrieve namespace %s", name)
+	}
+
+	ns = kcorev1.Namespace{ObjectMeta: kmetav1.ObjectMeta{Name: name}}
+	if err := c.k8sClient.Create(ctx, &ns); err != nil && !kapierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to create namespace %s", name)
+	}
+	return nil
+}
+
+// ApplyObject creates the given object on the SKR cluster or updates the existing object.
+func (c *client) ApplyObject(ctx context.Context, obj kpkgclient.Object) error {
+	return ApplyObject(ctx, c.k8sClient, obj)
+}
+
+// DeleteObject deletes the given object from the SKR cluster. It doesn't fail if the object doesn't exist.
+func (c *client) DeleteObject(ctx context.Context, obj kpkgclient.Object) error {
+	return kpkgclient.IgnoreNotFound(c.k8sClient.Delete(ctx, obj))
+}
+
+// GetServiceProxy sends a GET request to the given path of a service on the SKR cluster through the service proxy of the API server, e.g. to
+// read the public certificate of the SealedSecrets controller.
+func (c *client) GetServiceProxy(ctx context.Context, namespace, name, port, path string) ([]byte, error) {
+	return c.clientset.CoreV1().Services(namespace).ProxyGet("http", name, port, path, nil).DoRaw(ctx)
+}
+
+// ApplyObject creates the given object or replaces the existing object with it. Objects of other controllers, e.g. of the External Secrets
+// Operator, are applied with this function, so that the objects can be passed as unstructured objects if their types are not registered.
+// A write that conflicts with a concurrent writer is retried with writeConflictBackoff.
+func ApplyObject(ctx context.Context, k8sClient kpkgclient.Client, obj kpkgclient.Object) error {
+	return retry.OnError(writeConflictBackoff, isWriteConflict(ctx), func() error {
+		existing, ok := obj.DeepCopyObject().(kpkgclient.Object)
+		if !ok {
+			return errors.Errorf("failed to copy object %s", obj.GetName())
+		}
+		err := k8sClient.Get(ctx, kpkgclient.ObjectKeyFromObject(obj), existing)
+		if kapierrors.IsNotFound(err) {
+			obj.SetResourceVersion("")
+			return k8sClient.Create(ctx, obj)
+		}
+		if err != nil {
+			return err
+		}
+		obj.SetResourceVersion(existing.GetResourceVersion())
+		return k8sClient.Update(ctx, obj)
+	})
+}
+
+// isWriteConflict returns a function that returns true for the errors of writes that conflict with a concurrent writer, i.e. an update of an
+// object that was changed since it was read, or a creation of an object that was created since it was found missing. The writes aren't
+// retried once the context is done.
+func isWriteConflict(ctx context.Context) func(error) bool {
+	return func(err error) bool {
+		return ctx.Err() == nil && (kapierrors.IsConflict(err) || kapierrors.IsAlreadyExists(err))
+	}
+}
+
+// HasApplicationSecret returns true if the application secret exists in all target namespaces.
+func (c *client) HasApplicationSecret(ctx context.Context) (bool, error) {
+	return c.hasSecret(ctx, ApplicationSecretName)
+}
+
+// HasPublisherSecret returns true if the publisher secret exists in all target namespaces.
+func (c *client) HasPublisherSecret(ctx context.Context) (bool, error) {
+	return c.hasSecret(ctx, PublisherSecretName)
+}
+
+// hasSecret returns true if the secret with the given name exists in all target namespaces.
+func (c *client) hasSecret(ctx context.Context, name string) (bool, error) {
+	namespaces, err := c.secretNamespaces(ctx)
 	if err != nil {
 		return false, err
 	}
 
+	for _, namespace := range namespaces {
+		var s kcorev1.Secret
+		err := c.k8sClient.Get(ctx, kpkgclient.ObjectKey{
+			Name:      name,
+			Namespace: namespace,
+		}, &s)
+
+		if kapierrors.IsNotFound(err) {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+	}
+
 	return true, nil
 }
@@ -0,0 +1,103 @@
+package skr
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+)
+
+const DefaultClientCacheTTL = time.Minute * 10
+
+// ClientCache caches the clients of the SKR clusters by runtime ID, so that REST configs and connections are reused across reconciliations.
+// A cached client is only reused as long as the kubeconfig returned by the kubeconfig provider does not change, and it is evicted if it
+// was not used for longer than the TTL.
+type ClientCache struct {
+	kubeconfigProvider KubeconfigProvider
+	clientOptions      ClientOptions
+	ttl                time.Duration
+	now                func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*clientCacheEntry
+}
+
+type clientCacheEntry struct {
+	client     Client
+	kubeconfig []byte
+	lastUsed   time.Time
+}
+
+// NewClientCache returns a new cache of SKR clients. Since the kubeconfig is requested from the provider each time a client is requested,
+// the provider is expected to serve the kubeconfig from a cache.
+func NewClientCache(kubeconfigProvider KubeconfigProvider, clientOptions ClientOptions, ttl time.Duration) *ClientCache {
+	return &ClientCache{
+		kubeconfigProvider: kubeconfigProvider,
+		clientOptions:      clientOptions,
+		ttl:                ttl,
+		now:                time.Now,
+		entries:            map[string]*clientCacheEntry{},
+	}
+}
+
+// Get returns the client of the SKR cluster with the given runtime ID. If there is no cached client, or the kubeconfig of the cluster changed,
+// a new client is created. If the kubeconfig can't be retrieved, e.g. because the kubeconfig secret no longer exists, the cached client is evicted.
+func (c *ClientCache) Get(ctx context.Context, skrClusterID string) (Client, error) {
+	return c.get(ctx, skrClusterID, false)
+}
+
+// Refresh evicts the cached client of the SKR cluster with the given runtime ID and creates a new client from a refreshed kubeconfig.
+// This is used if the SKR cluster rejects the credentials of the cached client, e.g. because the kubeconfig was rotated.
+func (c *ClientCache) Refresh(ctx context.Context, skrClusterID string) (Client, error) {
+	return c.get(ctx, skrClusterID, true)
+}
+
+func (c *ClientCache) get(ctx context.Context, skrClusterID string, refresh bool) (Client, error) {
+	kubeconfig, err := c.kubeconfigProvider.GetKubeconfig(ctx, skrClusterID, refresh)
+	if err != nil {
+		c.Evict(skrClusterID)
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.evictIdle(now)
+
+	if e, ok := c.entries[skrClusterID]; ok && !refresh && bytes.Equal(e.kubeconfig, kubeconfig) {
+		e.lastUsed = now
+		return e.client, nil
+	}
+
+	skrClient, err := NewClient(kubeconfig, c.clientOptions)
+	if err != nil {
+		delete(c.entries, skrClusterID)
+		return nil, err
+	}
+	c.entries[skrClusterID] = &clientCacheEntry{client: skrClient, kubeconfig: kubeconfig, lastUsed: now}
+	return skrClient, nil
+}
+
+// Evict removes the client of the SKR cluster with the given runtime ID from the cache.
+func (c *ClientCache) Evict(skrClusterID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, skrClusterID)
+}
+
+// Len returns the number of cached clients.
+func (c *ClientCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// evictIdle removes all clients that were not used within the TTL. The caller must hold the lock.
+func (c *ClientCache) evictIdle(now time.Time) {
+	for id, e := range c.entries {
+		if now.Sub(e.lastUsed) > c.ttl {
+			delete(c.entries, id)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package skr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_ClientCache_Get_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		k8sClient kpkgclient.Client
+		wantError error
+	}{
+		{
+			name:      "should return error when secret with kubeconfig is not found",
+			k8sClient: fake.NewClientBuilder().Build(),
+			wantError: errors.New("secrets \"kubeconfig-test\" not found"), //nolint:goerr113 // used one time only in tests.
+		},
+		{
+			name:      "should return error when secret doesn't contain config key",
+			k8sClient: fake.NewClientBuilder().WithObjects(&kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Name: "kubeconfig-test", Namespace: KcpNamespace}}).Build(),
+			wantError: errors.New("failed to find SKR cluster kubeconfig in secret kubeconfig-test"), //nolint:goerr113 // used one time only in tests.
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClientCache(NewSecretKubeconfigProvider(tt.k8sClient, tt.k8sClient, DefaultKubeconfigSecretConfig()), DefaultClientOptions(), DefaultClientCacheTTL)
+
+			// when
+			_, err := c.Get(context.TODO(), "test")
+
+			// then
+			require.Error(t, err)
+			require.EqualError(t, tt.wantError, err.Error())
+		})
+	}
+}
+
+func Test_ClientCache_Get(t *testing.T) {
+	// given
+	originalNewClient := NewClient
+	t.Cleanup(func() { NewClient = originalNewClient })
+	createdClients := 0
+	NewClient = func(_ []byte, _ ClientOptions) (Client, error) {
+		createdClients++
+		return &client{}, nil
+	}
+
+	kubeconfigSecret := newKubeconfigSecret("test", "kubeconfig-v1")
+	k8sClient := fake.NewClientBuilder().WithObjects(kubeconfigSecret).Build()
+	now := time.Now()
+	c := NewClientCache(NewSecretKubeconfigProvider(k8sClient, k8sClient, DefaultKubeconfigSecretConfig()), DefaultClientOptions(), time.Minute)
+	c.now = func() time.Time { return now }
+
+	// when the client is requested twice
+	first, err := c.Get(context.TODO(), "test")
+	require.NoError(t, err)
+	second, err := c.Get(context.TODO(), "test")
+	require.NoError(t, err)
+
+	// then the client is reused
+	require.Same(t, first, second)
+	require.Equal(t, 1, createdClients)
+
+	// when the kubeconfig changes
+	kubeconfigSecret.Data["config"] = []byte("kubeconfig-v2")
+	require.NoError(t, k8sClient.Update(context.TODO(), kubeconfigSecret))
+	third, err := c.Get(context.TODO(), "test")
+	require.NoError(t, err)
+
+	// then a new client is created
+	require.NotSame(t, second, third)
+	require.Equal(t, 2, createdClients)
+
+	// when the client was not used within the TTL
+	now = now.Add(time.Minute * 2)
+	_, err = c.Get(context.TODO(), "test")
+	require.NoError(t, err)
+
+	// then a new client is created
+	require.Equal(t, 3, createdClients)
+
+	// when the client is refreshed
+	fourth, err := c.Get(context.TODO(), "test")
+	require.NoError(t, err)
+	refreshed, err := c.Refresh(context.TODO(), "test")
+	require.NoError(t, err)
+
+	// then a new client is created even though the kubeconfig did not change
+	require.NotSame(t, fourth, refreshed)
+	require.Equal(t, 4, createdClients)
+
+	// when the kubeconfig secret is deleted
+	require.NoError(t, k8sClient.Delete(context.TODO(), kubeconfigSecret))
+	_, err = c.Get(context.TODO(), "test")
+
+	// then the client is evicted
+	require.Error(t, err)
+	require.Equal(t, 0, c.Len())
+}
+
+func newKubeconfigSecret(skrClusterID, kubeconfig string) *kcorev1.Secret {
+	return &kcorev1.Secret{
+		ObjectMeta: kmetav1.ObjectMeta{
+			Name:      "kubeconfig-" + skrClusterID,
+			Namespace: KcpNamespace,
+		},
+		Data: map[string][]byte{
+			"config": []byte(kubeconfig),
+		},
+	}
+}
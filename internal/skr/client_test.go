@@ -5,51 +5,99 @@ import (
 	"errors"
 	"testing"
 
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
 	"github.com/stretchr/testify/require"
 	kcorev1 "k8s.io/api/core/v1"
 	kapierrors "k8s.io/apimachinery/pkg/api/errors"
 	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 var errGetSecret = errors.New("error on getting secret")
 
+const (
+	execKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: skr
+  cluster:
+    server: https://skr.example.com
+contexts:
+- name: skr
+  context:
+    cluster: skr
+    user: skr
+current-context: skr
+users:
+- name: skr
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1
+      command: kubectl-oidc_login
+      interactiveMode: IfAvailable
+`
+	oidcKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: skr
+  cluster:
+    server: https://skr.example.com
+contexts:
+- name: skr
+  context:
+    cluster: skr
+    user: skr
+current-context: skr
+users:
+- name: skr
+  user:
+    auth-provider:
+      name: oidc
+      config:
+        idp-issuer-url: https://issuer.example.com
+        client-id: skr
+        id-token: token
+`
+)
+
 func Test_NewClient(t *testing.T) {
-	type args struct {
-		k8sClient    kpkgclient.Client
-		skrClusterID string
-	}
 	tests := []struct {
-		name      string
-		args      args
-		wantError error
+		name       string
+		kubeconfig string
+		opts       ClientOptions
+		wantErr    error
 	}{
 		{
-			name: "should return error when secret with kubeconfig is not found",
-			args: args{
-				k8sClient:    fake.NewClientBuilder().Build(),
-				skrClusterID: "test",
-			},
-			wantError: errors.New("secrets \"kubeconfig-test\" not found"), //nolint:goerr113 // used one time only in tests.
+			name:       "should create client for kubeconfig with exec credential plugin if allowed",
+			kubeconfig: execKubeconfig,
+			opts:       ClientOptions{AllowExecPlugins: true},
 		},
 		{
-			name: "should return error when secret doesn't contain config key",
-			args: args{
-				k8sClient:    fake.NewClientBuilder().WithObjects(&kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Name: "kubeconfig-test", Namespace: KcpNamespace}}).Build(),
-				skrClusterID: "test",
-			},
-			wantError: errors.New("failed to find SKR cluster kubeconfig in secret kubeconfig-test"), //nolint:goerr113 // used one time only in tests.
+			name:       "should return error for kubeconfig with exec credential plugin if not allowed",
+			kubeconfig: execKubeconfig,
+			opts:       ClientOptions{AllowExecPlugins: false},
+			wantErr:    errExecPluginNotAllowed,
+		},
+		{
+			name:       "should create client for kubeconfig with OIDC auth provider",
+			kubeconfig: oidcKubeconfig,
+			opts:       ClientOptions{AllowExecPlugins: false},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// when
-			_, err := NewClient(tt.args.k8sClient, tt.args.skrClusterID)
+			_, err := NewClient([]byte(tt.kubeconfig), tt.opts)
 
-			// then
-			require.Error(t, err)
-			require.EqualError(t, tt.wantError, err.Error())
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
 		})
 	}
 }
@@ -191,6 +239,421 @@ func Test_client_HasApplicationSecret(t *testing.T) {
 	}
 }
 
+func Test_client_CreateSecret(t *testing.T) {
+	tests := []struct {
+		name            string
+		createNamespace bool
+		existingObjects []kpkgclient.Object
+		wantNamespace   bool
+	}{
+		{
+			name:            "should create namespace if it doesn't exist and creation is enabled",
+			createNamespace: true,
+			wantNamespace:   true,
+		},
+		{
+			name:            "should not fail if namespace already exists and creation is enabled",
+			createNamespace: true,
+			existingObjects: []kpkgclient.Object{&kcorev1.Namespace{ObjectMeta: kmetav1.ObjectMeta{Name: ApplicationSecretNamespace}}},
+			wantNamespace:   true,
+		},
+		{
+			name:            "should not create namespace if creation is disabled",
+			createNamespace: false,
+			wantNamespace:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k8sClient := fake.NewClientBuilder().WithObjects(tt.existingObjects...).Build()
+			c := &client{
+				k8sClient: k8sClient,
+				opts:      ClientOptions{CreateNamespace: tt.createNamespace},
+			}
+
+			_, err := c.CreateSecret(context.TODO(), eamias.NewApplication("id", "client-id", "client-secret", "token-url", "certs-url"), SecretOptions{})
+			require.NoError(t, err)
+
+			err = k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Name: ApplicationSecretNamespace}, &kcorev1.Namespace{})
+			if tt.wantNamespace {
+				require.NoError(t, err)
+			} else {
+				require.True(t, kapierrors.IsNotFound(err))
+			}
+			require.NoError(t, k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: ApplicationSecretNamespace}, &kcorev1.Secret{}))
+		})
+	}
+}
+
+func Test_client_SecretReplication(t *testing.T) {
+	// given
+	k8sClient := fake.NewClientBuilder().WithObjects(
+		&kcorev1.Namespace{ObjectMeta: kmetav1.ObjectMeta{Name: "selected", Labels: map[string]string{"eventing": "enabled"}}},
+		&kcorev1.Namespace{ObjectMeta: kmetav1.ObjectMeta{Name: "not-selected"}},
+	).Build()
+	c := &client{
+		k8sClient: k8sClient,
+		opts: ClientOptions{
+			CreateNamespace:         true,
+			SecretNamespaces:        []string{ApplicationSecretNamespace, "default"},
+			SecretNamespaceSelector: labels.SelectorFromSet(labels.Set{"eventing": "enabled"}),
+		},
+	}
+	replicaNamespaces := []string{ApplicationSecretNamespace, "default", "selected"}
+
+	// when the secret is created
+	appSecret, err := c.CreateSecret(context.TODO(), eamias.NewApplication("id", "client-id", "client-secret", "token-url", "certs-url"), SecretOptions{})
+
+	// then it is created in all target namespaces and the secret of the primary namespace is returned
+	require.NoError(t, err)
+	require.Equal(t, ApplicationSecretNamespace, appSecret.Namespace)
+	for _, namespace := range replicaNamespaces {
+		require.NoError(t, k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: namespace}, &kcorev1.Secret{}))
+	}
+	require.True(t, kapierrors.IsNotFound(k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: "not-selected"}, &kcorev1.Secret{})))
+	hasSecret, err := c.HasApplicationSecret(context.TODO())
+	require.NoError(t, err)
+	require.True(t, hasSecret)
+
+	// when a replica is deleted and another replica is modified
+	require.NoError(t, k8sClient.Delete(context.TODO(), &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{Name: ApplicationSecretName, Namespace: "default"}}))
+	var modified kcorev1.Secret
+	require.NoError(t, k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: "selected"}, &modified))
+	modified.Data = map[string][]byte{"client_id": []byte("modified")}
+	require.NoError(t, k8sClient.Update(context.TODO(), &modified))
+	hasSecret, err = c.HasApplicationSecret(context.TODO())
+	require.NoError(t, err)
+	require.False(t, hasSecret)
+
+	// then the replicas are synced with the secret of the primary namespace
+	primarySecret, err := c.SyncApplicationSecret(context.TODO(), SecretOptions{})
+	require.NoError(t, err)
+	require.Equal(t, ApplicationSecretNamespace, primarySecret.Namespace)
+	for _, namespace := range replicaNamespaces {
+		var replica kcorev1.Secret
+		require.NoError(t, k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: namespace}, &replica))
+		require.Equal(t, appSecret.Data, replica.Data)
+	}
+
+	// when the secret is deleted
+	require.NoError(t, c.DeleteSecret(context.TODO()))
+
+	// then it is deleted from all target namespaces and can't be synced anymore
+	for _, namespace := range replicaNamespaces {
+		require.True(t, kapierrors.IsNotFound(k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: namespace}, &kcorev1.Secret{})))
+	}
+	primarySecret, err = c.SyncApplicationSecret(context.TODO(), SecretOptions{})
+	require.NoError(t, err)
+	require.Nil(t, primarySecret)
+}
+
+func Test_client_PublisherSecret(t *testing.T) {
+	// given
+	k8sClient := fake.NewClientBuilder().Build()
+	c := &client{
+		k8sClient: k8sClient,
+		opts:      ClientOptions{CreateNamespace: true, SecretNamespaces: []string{ApplicationSecretNamespace, "default"}},
+	}
+	webhookSecret, err := c.CreateSecret(context.TODO(), eamias.NewApplication("id", "client-id", "client-secret", "token-url", "certs-url"),
+		SecretOptions{})
+	require.NoError(t, err)
+
+	// when the publisher secret is created
+	publisherSecret, err := c.CreatePublisherSecret(context.TODO(),
+		eamias.NewApplication("publisher-id", "publisher-client-id", "publisher-client-secret", "token-url", "certs-url"), SecretOptions{})
+
+	// then it is created in all target namespaces besides the application secret
+	require.NoError(t, err)
+	require.Equal(t, PublisherSecretName, publisherSecret.Name)
+	require.Equal(t, ApplicationSecretNamespace, publisherSecret.Namespace)
+	require.NotEqual(t, webhookSecret.Data, publisherSecret.Data)
+	hasSecret, err := c.HasPublisherSecret(context.TODO())
+	require.NoError(t, err)
+	require.True(t, hasSecret)
+
+	// when the publisher secret is deleted
+	require.NoError(t, c.DeletePublisherSecret(context.TODO()))
+
+	// then the application secret is kept
+	hasSecret, err = c.HasPublisherSecret(context.TODO())
+	require.NoError(t, err)
+	require.False(t, hasSecret)
+	hasSecret, err = c.HasApplicationSecret(context.TODO())
+	require.NoError(t, err)
+	require.True(t, hasSecret)
+}
+
+func Test_client_SecretConflictPolicy(t *testing.T) {
+	app := eamias.NewApplication("id", "client-id", "client-secret", "token-url", "certs-url")
+	userSecret := func() *kcorev1.Secret {
+		return &kcorev1.Secret{
+			ObjectMeta: kmetav1.ObjectMeta{Name: ApplicationSecretName, Namespace: ApplicationSecretNamespace},
+			Data:       map[string][]byte{"user": []byte("data")},
+		}
+	}
+	userSecretKey := kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: ApplicationSecretNamespace}
+
+	t.Run("should adopt existing secret with adopt policy", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithObjects(userSecret()).Build()
+		c := &client{k8sClient: k8sClient, opts: ClientOptions{SecretConflictPolicy: SecretConflictPolicyAdopt}}
+
+		_, err := c.CreateSecret(context.TODO(), app, SecretOptions{})
+		require.NoError(t, err)
+
+		var s kcorev1.Secret
+		require.NoError(t, k8sClient.Get(context.TODO(), userSecretKey, &s))
+		require.Equal(t, ManagedByLabelValue, s.Labels[ManagedByLabelKey])
+		require.Equal(t, []byte("client-id"), s.Data["client_id"])
+	})
+
+	t.Run("should fail on creation and keep existing secret with fail policy", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithObjects(userSecret()).Build()
+		c := &client{k8sClient: k8sClient, opts: ClientOptions{SecretConflictPolicy: SecretConflictPolicyFail}}
+
+		_, err := c.CreateSecret(context.TODO(), app, SecretOptions{})
+		require.True(t, IsSecretNotManagedError(err))
+
+		var s kcorev1.Secret
+		require.NoError(t, k8sClient.Get(context.TODO(), userSecretKey, &s))
+		require.Equal(t, userSecret().Data, s.Data)
+	})
+
+	t.Run("should fail on sync with fail policy", func(t *testing.T) {
+		c := &client{k8sClient: fake.NewClientBuilder().WithObjects(userSecret()).Build(), opts: ClientOptions{SecretConflictPolicy: SecretConflictPolicyFail}}
+
+		_, err := c.SyncApplicationSecret(context.TODO(), SecretOptions{})
+		require.True(t, IsSecretNotManagedError(err))
+	})
+
+	t.Run("should not delete existing secret with fail policy", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithObjects(userSecret()).Build()
+		c := &client{k8sClient: k8sClient, opts: ClientOptions{SecretConflictPolicy: SecretConflictPolicyFail}}
+
+		require.NoError(t, c.DeleteSecret(context.TODO()))
+		require.NoError(t, k8sClient.Get(context.TODO(), userSecretKey, &kcorev1.Secret{}))
+	})
+}
+
+func Test_client_ImmutableSecret(t *testing.T) {
+	// given
+	k8sClient := fake.NewClientBuilder().Build()
+	c := &client{k8sClient: k8sClient}
+	secretKey := kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: ApplicationSecretNamespace}
+	immutableOpts := SecretOptions{Immutable: true}
+
+	// when an immutable secret is created
+	_, err := c.CreateSecret(context.TODO(), eamias.NewApplication("id", "client-id", "client-secret", "token-url", "certs-url"), immutableOpts)
+	require.NoError(t, err)
+
+	// then the secret is immutable
+	var s kcorev1.Secret
+	require.NoError(t, k8sClient.Get(context.TODO(), secretKey, &s))
+	require.True(t, isImmutable(&s))
+
+	// when the credentials are rotated
+	_, err = c.CreateSecret(context.TODO(), eamias.NewApplication("id", "client-id", "rotated-secret", "token-url", "certs-url"), immutableOpts)
+	require.NoError(t, err)
+
+	// then the secret is recreated with the rotated credentials
+	require.NoError(t, k8sClient.Get(context.TODO(), secretKey, &s))
+	require.True(t, isImmutable(&s))
+	require.Equal(t, []byte("rotated-secret"), s.Data["client_secret"])
+
+	// when the secret is synced as mutable secret
+	_, err = c.SyncApplicationSecret(context.TODO(), SecretOptions{})
+	require.NoError(t, err)
+
+	// then the secret is recreated as mutable secret with the same credentials
+	require.NoError(t, k8sClient.Get(context.TODO(), secretKey, &s))
+	require.False(t, isImmutable(&s))
+	require.Equal(t, []byte("rotated-secret"), s.Data["client_secret"])
+}
+
+func Test_client_SyncDiscovery(t *testing.T) {
+	// given
+	k8sClient := fake.NewClientBuilder().Build()
+	c := &client{k8sClient: k8sClient, opts: ClientOptions{SecretNamespaces: []string{ApplicationSecretNamespace, "default"},
+		SecretFormat: SecretFormat{KeyMapping: map[string]string{eamias.SecretKeyTokenURL: "tokenurl"}}}}
+	_, err := c.CreateSecret(context.TODO(), eamias.NewApplication("id", "client-id", "client-secret", "https://old.example.com/token",
+		"https://old.example.com/certs"), SecretOptions{})
+	require.NoError(t, err)
+
+	// when the endpoints are unchanged
+	updated, err := c.SyncDiscovery(context.TODO(), "https://old.example.com/token", "https://old.example.com/certs", SecretOptions{})
+
+	// then the secrets aren't updated
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	// when the endpoints moved to a new host
+	updated, err = c.SyncDiscovery(context.TODO(), "https://new.example.com/token", "https://new.example.com/certs", SecretOptions{})
+
+	// then the endpoints of the secrets in all target namespaces are replaced and the credentials are kept
+	require.NoError(t, err)
+	require.True(t, updated)
+	for _, namespace := range []string{ApplicationSecretNamespace, "default"} {
+		var s kcorev1.Secret
+		require.NoError(t, k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: namespace}, &s))
+		require.Equal(t, map[string][]byte{
+			eamias.SecretKeyClientID:     []byte("client-id"),
+			eamias.SecretKeyClientSecret: []byte("client-secret"),
+			"tokenurl":                   []byte("https://new.example.com/token"),
+			eamias.SecretKeyCertsURL:     []byte("https://new.example.com/certs"),
+		}, s.Data)
+	}
+}
+
+func Test_client_WriteConflicts(t *testing.T) {
+	originalBackoff := writeConflictBackoff
+	t.Cleanup(func() { writeConflictBackoff = originalBackoff })
+	writeConflictBackoff = wait.Backoff{Steps: 3}
+	app := eamias.NewApplication("id", "client-id", "rotated-secret", "token-url", "certs-url")
+	secretKey := kpkgclient.ObjectKey{Name: ApplicationSecretName, Namespace: ApplicationSecretNamespace}
+	tests := []struct {
+		name          string
+		givenConflict int
+		wantError     bool
+	}{
+		{
+			name:          "should update the secret that was changed by a concurrent writer",
+			givenConflict: 2,
+		},
+		{
+			name:          "should fail if the updates keep conflicting",
+			givenConflict: 3,
+			wantError:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			existing := &kcorev1.Secret{
+				ObjectMeta: kmetav1.ObjectMeta{Name: ApplicationSecretName, Namespace: ApplicationSecretNamespace,
+					Labels: map[string]string{ManagedByLabelKey: ManagedByLabelValue}},
+				Type: kcorev1.SecretTypeOpaque,
+				Data: map[string][]byte{"client_secret": []byte("client-secret")},
+			}
+			var updates int
+			k8sClient := fake.NewClientBuilder().WithObjects(existing).WithInterceptorFuncs(interceptor.Funcs{
+				Update: func(ctx context.Context, c kpkgclient.WithWatch, obj kpkgclient.Object, opts ...kpkgclient.UpdateOption) error {
+					updates++
+					if updates > tt.givenConflict {
+						return c.Update(ctx, obj, opts...)
+					}
+					// A concurrent writer annotates the secret between the read and the update of the client.
+					var s kcorev1.Secret
+					require.NoError(t, c.Get(ctx, secretKey, &s))
+					s.Annotations = map[string]string{"backup.example.com/revision": "1"}
+					require.NoError(t, c.Update(ctx, &s))
+					return kapierrors.NewConflict(schema.GroupResource{Resource: "secrets"}, s.Name, errors.New("the object has been modified"))
+				},
+			}).Build()
+			c := &client{k8sClient: k8sClient}
+
+			// when
+			_, err := c.CreateSecret(context.TODO(), app, SecretOptions{})
+
+			// then
+			var s kcorev1.Secret
+			require.NoError(t, k8sClient.Get(context.TODO(), secretKey, &s))
+			if tt.wantError {
+				require.True(t, kapierrors.IsConflict(err))
+				require.Equal(t, []byte("client-secret"), s.Data["client_secret"])
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []byte("rotated-secret"), s.Data["client_secret"])
+				require.Equal(t, "1", s.Annotations["backup.example.com/revision"])
+			}
+		})
+	}
+}
+
+func Test_client_VerifySecret(t *testing.T) {
+	app := eamias.NewApplication("id", "client-id", "client-secret", "token-url", "certs-url")
+	expected := app.ToSecret(ApplicationSecretName, ApplicationSecretNamespace)
+	outdated := eamias.NewApplication("id", "client-id", "outdated-secret", "token-url", "certs-url").ToSecret(ApplicationSecretName, ApplicationSecretNamespace)
+
+	tests := []struct {
+		name            string
+		existingObjects []kpkgclient.Object
+		dryRun          bool
+		wantErr         bool
+	}{
+		{
+			name:            "should succeed if secret contains expected data",
+			existingObjects: []kpkgclient.Object{&expected},
+		},
+		{
+			name:    "should fail if secret doesn't exist",
+			wantErr: true,
+		},
+		{
+			name:   "should succeed in a dry run, since the secret is never written",
+			dryRun: true,
+		},
+		{
+			name:            "should fail if secret contains other data",
+			existingObjects: []kpkgclient.Object{&outdated},
+			wantErr:         true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &client{k8sClient: fake.NewClientBuilder().WithObjects(tt.existingObjects...).Build(), opts: ClientOptions{DryRun: tt.dryRun}}
+
+			err := c.VerifySecret(context.TODO(), expected)
+
+			if tt.wantErr {
+				var verificationErr *SecretVerificationError
+				require.ErrorAs(t, err, &verificationErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_client_HasApplicationCredentials(t *testing.T) {
+	app := eamias.NewApplication("id", "client-id", "client-secret", "token-url", "certs-url")
+	format := SecretFormat{KeyMapping: map[string]string{eamias.SecretKeyClientSecret: "clientSecret"}}
+	formatted := app.ToSecret(ApplicationSecretName, ApplicationSecretNamespace)
+	format.apply(&formatted)
+	outdated := eamias.NewApplication("id", "client-id", "outdated-secret", "token-url", "certs-url").ToSecret(ApplicationSecretName, ApplicationSecretNamespace)
+	format.apply(&outdated)
+
+	tests := []struct {
+		name            string
+		existingObjects []kpkgclient.Object
+		want            bool
+	}{
+		{
+			name:            "should return true if secret contains the credentials in the format of the client",
+			existingObjects: []kpkgclient.Object{&formatted},
+			want:            true,
+		},
+		{
+			name:            "should return false if secret contains other credentials",
+			existingObjects: []kpkgclient.Object{&outdated},
+		},
+		{
+			name: "should return false if secret doesn't exist",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			c := &client{k8sClient: fake.NewClientBuilder().WithObjects(tt.existingObjects...).Build(), opts: ClientOptions{SecretFormat: format}}
+
+			// when
+			got, err := c.HasApplicationCredentials(context.TODO(), app)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
 type errorFakeClient struct {
 	kpkgclient.Client
 	errorOnGet error
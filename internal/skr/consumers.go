@@ -0,0 +1,156 @@
+package skr
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ConsumerSecretName is the name of the secret of a namespace, and the prefix of the name of the secret of a Subscription, that contains
+// the credentials of the dedicated application of an event consumer.
+const ConsumerSecretName = "eventing-consumer-auth"
+
+// SubscriptionListGVK is the kind of the lists of the Subscriptions of Kyma Eventing on the SKR clusters.
+var SubscriptionListGVK = schema.GroupVersionKind{ //nolint:gochecknoglobals // Read-only kind of the Subscriptions.
+	Group:   "eventing.kyma-project.io",
+	Version: "v1alpha2",
+	Kind:    "SubscriptionList",
+}
+
+// Consumer is an event consumer on an SKR cluster that gets a dedicated application, i.e. a namespace with Subscriptions or a single
+// Subscription.
+type Consumer struct {
+	Namespace string
+	// Subscription is the name of the Subscription, or empty if the consumer is the namespace.
+	Subscription string
+}
+
+// ParseConsumer parses the consumer from its string representation.
+func ParseConsumer(s string) Consumer {
+	namespace, subscription, _ := strings.Cut(s, "/")
+	return Consumer{Namespace: namespace, Subscription: subscription}
+}
+
+// String returns the namespace of the consumer, or its namespace and the name of its Subscription separated by a slash.
+func (c Consumer) String() string {
+	if c.Subscription == "" {
+		return c.Namespace
+	}
+	return c.Namespace + "/" + c.Subscription
+}
+
+// SecretName returns the name of the secret of the consumer in its namespace.
+func (c Consumer) SecretName() string {
+	if c.Subscription == "" {
+		return ConsumerSecretName
+	}
+	return ConsumerSecretName + "-" + c.Subscription
+}
+
+// ListConsumers returns the consumers of the Subscriptions on the SKR cluster, either one per namespace with Subscriptions or one per
+// Subscription, sorted by namespace and name. Subscriptions that are being deleted are skipped. There are no consumers if Kyma Eventing
+// isn't installed on the SKR cluster.
+func (c *client) ListConsumers(ctx context.Context, perSubscription bool) ([]Consumer, error) {
+	subscriptions := &unstructured.UnstructuredList{}
+	subscriptions.SetGroupVersionKind(SubscriptionListGVK)
+	if err := c.k8sClient.List(ctx, subscriptions); err != nil {
+		if kmeta.IsNoMatchError(err) || kapierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to list Subscriptions")
+	}
+
+	var consumers []Consumer
+	for _, subscription := range subscriptions.Items {
+		if subscription.GetDeletionTimestamp() != nil {
+			continue
+		}
+		consumer := Consumer{Namespace: subscription.GetNamespace()}
+		if perSubscription {
+			consumer.Subscription = subscription.GetName()
+		}
+		if !slices.Contains(consumers, consumer) {
+			consumers = append(consumers, consumer)
+		}
+	}
+	slices.SortFunc(consumers, func(a, b Consumer) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return consumers, nil
+}
+
+// WatchSubscriptions watches the Subscriptions in all namespaces of the SKR cluster.
+func (c *client) WatchSubscriptions(ctx context.Context) (watch.Interface, error) {
+	watchClient, ok := c.k8sClient.(kpkgclient.WithWatch)
+	if !ok {
+		return nil, errors.New("client of the SKR cluster doesn't support watches")
+	}
+	subscriptions := &unstructured.UnstructuredList{}
+	subscriptions.SetGroupVersionKind(SubscriptionListGVK)
+	return watchClient.Watch(ctx, subscriptions)
+}
+
+// CreateConsumerSecret creates the secret of the consumer with the credentials of its dedicated application, or updates the existing secret.
+func (c *client) CreateConsumerSecret(ctx context.Context, app eamias.Application, consumer Consumer, secretOpts SecretOptions,
+) (kcorev1.Secret, error) {
+	s := app.ToSecret(consumer.SecretName(), consumer.Namespace)
+	s.Labels = map[string]string{ManagedByLabelKey: ManagedByLabelValue}
+	s.Immutable = immutable(secretOpts)
+	c.opts.SecretFormat.apply(&s)
+	if err := c.applySecret(ctx, &s); err != nil {
+		return kcorev1.Secret{}, err
+	}
+	return s, nil
+}
+
+// HasConsumerSecret returns true if the secret of the consumer exists.
+func (c *client) HasConsumerSecret(ctx context.Context, consumer Consumer) (bool, error) {
+	var s kcorev1.Secret
+	err := c.k8sClient.Get(ctx, kpkgclient.ObjectKey{Name: consumer.SecretName(), Namespace: consumer.Namespace}, &s)
+	if kapierrors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// DeleteConsumerSecret deletes the secret of the consumer. It doesn't fail if the secret doesn't exist, and secrets that aren't managed by
+// the manager are only deleted if they would have been adopted anyway.
+func (c *client) DeleteConsumerSecret(ctx context.Context, consumer Consumer) error {
+	var s kcorev1.Secret
+	if err := c.k8sClient.Get(ctx, kpkgclient.ObjectKey{Name: consumer.SecretName(), Namespace: consumer.Namespace}, &s); err != nil {
+		return kpkgclient.IgnoreNotFound(err)
+	}
+	if !isManaged(&s) && c.opts.SecretConflictPolicy == SecretConflictPolicyFail {
+		return nil
+	}
+	return kpkgclient.IgnoreNotFound(c.k8sClient.Delete(ctx, &s))
+}
+
+// SubscriptionWatcher watches the Subscriptions on the SKR clusters and emits an event for the EventingAuth CR of an SKR cluster if a
+// Subscription is created or deleted, so that the dedicated applications of the event consumers are provisioned and deleted immediately
+// instead of with the next resync.
+type SubscriptionWatcher struct {
+	*SecretWatcher
+}
+
+// NewSubscriptionWatcher returns a watcher that uses the clients of the given cache to watch the Subscriptions on at most maxClusters SKR
+// clusters. The watcher must be added to the manager to start watching.
+func NewSubscriptionWatcher(clientCache *ClientCache, maxClusters int) *SubscriptionWatcher {
+	w := NewSecretWatcher(clientCache, maxClusters)
+	w.resource = "Subscriptions"
+	w.watchResource = func(ctx context.Context, skrClient Client) (watch.Interface, error) {
+		return skrClient.WatchSubscriptions(ctx)
+	}
+	w.emitOn = []watch.EventType{watch.Added, watch.Deleted}
+	return &SubscriptionWatcher{SecretWatcher: w}
+}
@@ -0,0 +1,128 @@
+package skr
+
+import (
+	"context"
+	"testing"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newSubscription(namespace, name string) *unstructured.Unstructured {
+	s := &unstructured.Unstructured{}
+	s.SetGroupVersionKind(SubscriptionListGVK.GroupVersion().WithKind("Subscription"))
+	s.SetNamespace(namespace)
+	s.SetName(name)
+	return s
+}
+
+func Test_client_ListConsumers(t *testing.T) {
+	subscriptions := []kpkgclient.Object{
+		newSubscription("orders", "order-created"),
+		newSubscription("orders", "order-cancelled"),
+		newSubscription("billing", "invoice-paid"),
+	}
+	tests := []struct {
+		name            string
+		perSubscription bool
+		withoutCRD      bool
+		wantConsumers   []Consumer
+	}{
+		{
+			name:          "should return a consumer per namespace with Subscriptions",
+			wantConsumers: []Consumer{{Namespace: "billing"}, {Namespace: "orders"}},
+		},
+		{
+			name:            "should return a consumer per Subscription",
+			perSubscription: true,
+			wantConsumers: []Consumer{
+				{Namespace: "billing", Subscription: "invoice-paid"},
+				{Namespace: "orders", Subscription: "order-cancelled"},
+				{Namespace: "orders", Subscription: "order-created"},
+			},
+		},
+		{
+			name:       "should return no consumers if Kyma Eventing isn't installed",
+			withoutCRD: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			builder := fake.NewClientBuilder()
+			if tt.withoutCRD {
+				builder = builder.WithRESTMapper(kmeta.NewDefaultRESTMapper(nil))
+			} else {
+				scheme := runtime.NewScheme()
+				scheme.AddKnownTypeWithName(SubscriptionListGVK.GroupVersion().WithKind("Subscription"), &unstructured.Unstructured{})
+				scheme.AddKnownTypeWithName(SubscriptionListGVK, &unstructured.UnstructuredList{})
+				builder = builder.WithScheme(scheme).WithObjects(subscriptions...)
+			}
+			c := &client{k8sClient: builder.Build()}
+
+			// when
+			consumers, err := c.ListConsumers(context.TODO(), tt.perSubscription)
+
+			// then
+			require.NoError(t, err)
+			require.Equal(t, tt.wantConsumers, consumers)
+		})
+	}
+}
+
+func Test_client_ConsumerSecret(t *testing.T) {
+	// given
+	k8sClient := fake.NewClientBuilder().Build()
+	c := &client{k8sClient: k8sClient, opts: ClientOptions{SecretConflictPolicy: SecretConflictPolicyAdopt}}
+	consumer := ParseConsumer("orders/order-created")
+	app := eamias.NewApplication("id", "client-id", "client-secret", "token-url", "certs-url")
+
+	// when
+	s, err := c.CreateConsumerSecret(context.TODO(), app, consumer, SecretOptions{})
+
+	// then
+	require.NoError(t, err)
+	require.Equal(t, "orders", s.Namespace)
+	require.Equal(t, "eventing-consumer-auth-order-created", s.Name)
+	exists, err := c.HasConsumerSecret(context.TODO(), consumer)
+	require.NoError(t, err)
+	require.True(t, exists)
+	var created kcorev1.Secret
+	require.NoError(t, k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Namespace: "orders", Name: s.Name}, &created))
+	require.Equal(t, ManagedByLabelValue, created.Labels[ManagedByLabelKey])
+	require.Equal(t, []byte("client-secret"), created.Data[eamias.SecretKeyClientSecret])
+
+	// when
+	require.NoError(t, c.DeleteConsumerSecret(context.TODO(), consumer))
+
+	// then
+	err = k8sClient.Get(context.TODO(), kpkgclient.ObjectKey{Namespace: "orders", Name: s.Name}, &kcorev1.Secret{})
+	require.True(t, kapierrors.IsNotFound(err))
+	require.NoError(t, c.DeleteConsumerSecret(context.TODO(), consumer))
+}
+
+func Test_Consumer(t *testing.T) {
+	for _, tt := range []struct {
+		consumer       Consumer
+		wantString     string
+		wantSecretName string
+	}{
+		{consumer: Consumer{Namespace: "orders"}, wantString: "orders", wantSecretName: "eventing-consumer-auth"},
+		{
+			consumer:       Consumer{Namespace: "orders", Subscription: "order.created"},
+			wantString:     "orders/order.created",
+			wantSecretName: "eventing-consumer-auth-order.created",
+		},
+	} {
+		require.Equal(t, tt.wantString, tt.consumer.String())
+		require.Equal(t, tt.wantSecretName, tt.consumer.SecretName())
+		require.Equal(t, tt.consumer, ParseConsumer(tt.consumer.String()))
+	}
+}
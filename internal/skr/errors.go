@@ -0,0 +1,69 @@
+package skr
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// SecretNotManagedError is returned if the application secret already exists on the SKR cluster, but wasn't created by the manager and
+// the conflict policy doesn't allow adopting it.
+type SecretNotManagedError struct {
+	Namespace string
+	Name      string
+}
+
+func (e *SecretNotManagedError) Error() string {
+	return fmt.Sprintf("secret %s/%s already exists on the target cluster and is not managed by eventing-auth-manager", e.Namespace, e.Name)
+}
+
+// ConditionReason returns the reason of the SecretReady condition of the EventingAuth CR.
+func (e *SecretNotManagedError) ConditionReason() string {
+	return eamapiv1alpha1.ConditionReasonSecretNotManaged
+}
+
+// SecretVerificationError is returned if the application secret read back from the SKR cluster doesn't contain the expected credentials.
+type SecretVerificationError struct {
+	Namespace string
+	Name      string
+	Reason    string
+}
+
+func (e *SecretVerificationError) Error() string {
+	return fmt.Sprintf("verification of secret %s/%s on the target cluster failed: %s", e.Namespace, e.Name, e.Reason)
+}
+
+// ConditionReason returns the reason of the SecretReady condition of the EventingAuth CR.
+func (e *SecretVerificationError) ConditionReason() string {
+	return eamapiv1alpha1.ConditionReasonSecretVerificationFailed
+}
+
+// IsSecretNotManagedError returns true if the error is or wraps a SecretNotManagedError.
+func IsSecretNotManagedError(err error) bool {
+	var notManagedErr *SecretNotManagedError
+	return errors.As(err, &notManagedErr)
+}
+
+// IsAuthenticationError returns true if the error indicates that the SKR cluster rejected the credentials of the kubeconfig or that the
+// certificate of the SKR cluster can't be verified. Both happen if the kubeconfig of the SKR cluster was rotated.
+func IsAuthenticationError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if kapierrors.IsUnauthorized(err) {
+		return true
+	}
+
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var certificateInvalidErr x509.CertificateInvalidError
+	var hostnameErr x509.HostnameError
+	var certificateVerificationErr *tls.CertificateVerificationError
+	return errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &certificateInvalidErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &certificateVerificationErr)
+}
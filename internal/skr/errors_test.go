@@ -0,0 +1,51 @@
+package skr
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func Test_IsAuthenticationError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "should return false for nil error",
+			err:  nil,
+			want: false,
+		},
+		{
+			name: "should return true for unauthorized error",
+			err:  kapierrors.NewUnauthorized("token expired"),
+			want: true,
+		},
+		{
+			name: "should return true for wrapped unknown authority error",
+			err:  fmt.Errorf("failed to get secret: %w", x509.UnknownAuthorityError{}),
+			want: true,
+		},
+		{
+			name: "should return false for not found error",
+			err:  kapierrors.NewNotFound(kcorev1.Resource("secret"), ApplicationSecretName),
+			want: false,
+		},
+		{
+			name: "should return false for other errors",
+			err:  errors.New("some error"), //nolint:goerr113 // used one time only in tests.
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsAuthenticationError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package skr
+
+import (
+	"context"
+	"encoding/base64"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultGardenerShootNameTemplate      = "{{ .RuntimeID }}"
+	DefaultGardenerAdminKubeconfigTTL     = time.Hour
+	gardenerAdminKubeconfigSubresource    = "adminkubeconfig"
+	gardenerMinAdminKubeconfigTTL         = time.Minute * 10
+	gardenerAdminKubeconfigRenewalPercent = 80
+)
+
+//nolint:gochecknoglobals // Used as constants.
+var (
+	gardenerShootGVK = schema.GroupVersionKind{
+		Group:   "core.gardener.cloud",
+		Version: "v1beta1",
+		Kind:    "Shoot",
+	}
+	gardenerAdminKubeconfigRequestGVK = schema.GroupVersionKind{
+		Group:   "authentication.gardener.cloud",
+		Version: "v1alpha1",
+		Kind:    "AdminKubeconfigRequest",
+	}
+)
+
+// GardenerConfig defines how the shoots of the SKR clusters are found in the Gardener project.
+type GardenerConfig struct {
+	projectNamespace  string
+	shootNameTemplate RuntimeIDTemplate
+	kubeconfigTTL     time.Duration
+}
+
+// NewGardenerConfig returns the configuration to request admin kubeconfigs of the shoots of the SKR clusters. The shoot name template is a
+// Go template that can reference the runtime ID of the SKR cluster with '{{ .RuntimeID }}'. The TTL defines how long a requested admin
+// kubeconfig is valid.
+func NewGardenerConfig(projectNamespace, shootNameTemplate string, kubeconfigTTL time.Duration) (GardenerConfig, error) {
+	if projectNamespace == "" {
+		return GardenerConfig{}, errors.New("namespace of the Gardener project must not be empty")
+	}
+	if kubeconfigTTL < gardenerMinAdminKubeconfigTTL {
+		return GardenerConfig{}, errors.Errorf("TTL of the admin kubeconfig must be at least %s", gardenerMinAdminKubeconfigTTL)
+	}
+
+	t, err := NewRuntimeIDTemplate("gardenerShootName", shootNameTemplate)
+	if err != nil {
+		return GardenerConfig{}, errors.Wrap(err, "invalid name template of the Gardener shoot")
+	}
+	return GardenerConfig{projectNamespace: projectNamespace, shootNameTemplate: t, kubeconfigTTL: kubeconfigTTL}, nil
+}
+
+type gardenerKubeconfigProvider struct {
+	gardenClient kpkgclient.Client
+	config       GardenerConfig
+	now          func() time.Time
+
+	mu          sync.Mutex
+	kubeconfigs map[string]adminKubeconfig
+}
+
+type adminKubeconfig struct {
+	kubeconfig []byte
+	renewAt    time.Time
+}
+
+// NewGardenerKubeconfigProvider returns a provider that requests short-lived admin kubeconfigs of the SKR clusters using the
+// 'shoots/adminkubeconfig' subresource of the Gardener API. The admin kubeconfigs are cached and renewed before they expire.
+func NewGardenerKubeconfigProvider(gardenClient kpkgclient.Client, config GardenerConfig) KubeconfigProvider {
+	return &gardenerKubeconfigProvider{
+		gardenClient: gardenClient,
+		config:       config,
+		now:          time.Now,
+		kubeconfigs:  map[string]adminKubeconfig{},
+	}
+}
+
+func (p *gardenerKubeconfigProvider) GetKubeconfig(ctx context.Context, skrClusterID string, refresh bool) ([]byte, error) {
+	p.mu.Lock()
+	cached, ok := p.kubeconfigs[skrClusterID]
+	p.mu.Unlock()
+	if ok && !refresh && p.now().Before(cached.renewAt) {
+		return cached.kubeconfig, nil
+	}
+
+	requested, err := p.requestAdminKubeconfig(ctx, skrClusterID)
+	if err != nil {
+		p.mu.Lock()
+		delete(p.kubeconfigs, skrClusterID)
+		p.mu.Unlock()
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.kubeconfigs[skrClusterID] = requested
+	p.mu.Unlock()
+	return requested.kubeconfig, nil
+}
+
+func (p *gardenerKubeconfigProvider) requestAdminKubeconfig(ctx context.Context, skrClusterID string) (adminKubeconfig, error) {
+	shootName, err := p.config.shootNameTemplate.Render(skrClusterID)
+	if err != nil {
+		return adminKubeconfig{}, errors.Wrap(err, "failed to render name of the Gardener shoot")
+	}
+
+	shoot := &unstructured.Unstructured{}
+	shoot.SetGroupVersionKind(gardenerShootGVK)
+	shoot.SetNamespace(p.config.projectNamespace)
+	shoot.SetName(shootName)
+
+	request := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"expirationSeconds": int64(p.config.kubeconfigTTL.Seconds()),
+		},
+	}}
+	request.SetGroupVersionKind(gardenerAdminKubeconfigRequestGVK)
+
+	requestedAt := p.now()
+	if err := p.gardenClient.SubResource(gardenerAdminKubeconfigSubresource).Create(ctx, shoot, request); err != nil {
+		return adminKubeconfig{}, errors.Wrapf(err, "failed to request admin kubeconfig of shoot %s", shootName)
+	}
+
+	encodedKubeconfig, _, err := unstructured.NestedString(request.Object, "status", "kubeconfig")
+	if err != nil || encodedKubeconfig == "" {
+		return adminKubeconfig{}, errors.Errorf("failed to find admin kubeconfig in response for shoot %s", shootName)
+	}
+	kubeconfig, err := base64.StdEncoding.DecodeString(encodedKubeconfig)
+	if err != nil {
+		return adminKubeconfig{}, errors.Wrapf(err, "failed to decode admin kubeconfig of shoot %s", shootName)
+	}
+
+	expiresAt := requestedAt.Add(p.config.kubeconfigTTL)
+	if expirationTimestamp, _, _ := unstructured.NestedString(request.Object, "status", "expirationTimestamp"); expirationTimestamp != "" {
+		if t, err := time.Parse(time.RFC3339, expirationTimestamp); err == nil {
+			expiresAt = t
+		}
+	}
+
+	// The kubeconfig is renewed before it expires, so that clients never use an expired kubeconfig.
+	renewAt := requestedAt.Add(expiresAt.Sub(requestedAt) * gardenerAdminKubeconfigRenewalPercent / 100)
+	return adminKubeconfig{kubeconfig: kubeconfig, renewAt: renewAt}, nil
+}
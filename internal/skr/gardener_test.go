@@ -0,0 +1,98 @@
+package skr
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var errRequestAdminKubeconfig = errors.New("error on requesting admin kubeconfig")
+
+func Test_gardenerKubeconfigProvider_GetKubeconfig(t *testing.T) {
+	// given
+	requests := 0
+	var requestedShoot string
+	var requestErr error
+	gardenClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		SubResourceCreate: func(_ context.Context, _ kpkgclient.Client, subResourceName string, obj kpkgclient.Object, subResource kpkgclient.Object, _ ...kpkgclient.SubResourceCreateOption) error {
+			require.Equal(t, "adminkubeconfig", subResourceName)
+			if requestErr != nil {
+				return requestErr
+			}
+			requests++
+			requestedShoot = obj.GetNamespace() + "/" + obj.GetName()
+			u, ok := subResource.(*unstructured.Unstructured)
+			require.True(t, ok)
+			u.Object["status"] = map[string]interface{}{
+				"kubeconfig": base64.StdEncoding.EncodeToString([]byte("admin-kubeconfig")),
+			}
+			return nil
+		},
+	}).Build()
+
+	config, err := NewGardenerConfig("garden-kyma", "shoot-{{ .RuntimeID }}", time.Hour)
+	require.NoError(t, err)
+	now := time.Now()
+	p := &gardenerKubeconfigProvider{
+		gardenClient: gardenClient,
+		config:       config,
+		now:          func() time.Time { return now },
+		kubeconfigs:  map[string]adminKubeconfig{},
+	}
+
+	// when the kubeconfig is requested twice
+	kubeconfig, err := p.GetKubeconfig(context.TODO(), "test", false)
+	require.NoError(t, err)
+	_, err = p.GetKubeconfig(context.TODO(), "test", false)
+	require.NoError(t, err)
+
+	// then the admin kubeconfig is only requested once
+	require.Equal(t, []byte("admin-kubeconfig"), kubeconfig)
+	require.Equal(t, "garden-kyma/shoot-test", requestedShoot)
+	require.Equal(t, 1, requests)
+
+	// when the renewal time is reached
+	now = now.Add(time.Minute * 50)
+	_, err = p.GetKubeconfig(context.TODO(), "test", false)
+	require.NoError(t, err)
+
+	// then the admin kubeconfig is renewed
+	require.Equal(t, 2, requests)
+
+	// when a refresh is forced
+	_, err = p.GetKubeconfig(context.TODO(), "test", true)
+	require.NoError(t, err)
+
+	// then the admin kubeconfig is requested again
+	require.Equal(t, 3, requests)
+
+	// when the request fails
+	requestErr = errRequestAdminKubeconfig
+	_, err = p.GetKubeconfig(context.TODO(), "test", true)
+
+	// then the error is returned and the cached kubeconfig is removed
+	require.ErrorIs(t, err, errRequestAdminKubeconfig)
+	require.Empty(t, p.kubeconfigs)
+}
+
+func Test_NewGardenerConfig(t *testing.T) {
+	_, err := NewGardenerConfig("", DefaultGardenerShootNameTemplate, DefaultGardenerAdminKubeconfigTTL)
+	require.Error(t, err)
+
+	_, err = NewGardenerConfig("garden-kyma", DefaultGardenerShootNameTemplate, time.Minute)
+	require.Error(t, err)
+
+	_, err = NewGardenerConfig("garden-kyma", "{{ .Unknown }}", DefaultGardenerAdminKubeconfigTTL)
+	require.Error(t, err)
+
+	_, err = NewGardenerConfig("garden-kyma", DefaultGardenerShootNameTemplate, DefaultGardenerAdminKubeconfigTTL)
+	require.NoError(t, err)
+}
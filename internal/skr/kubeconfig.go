@@ -0,0 +1,219 @@
+package skr
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"text/template"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	kpkgclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const DefaultKubeconfigSecretNameTemplate = "kubeconfig-{{ .RuntimeID }}"
+
+// kubeconfigSecretDataKey is the key of the kubeconfig in the data of the kubeconfig secrets.
+const kubeconfigSecretDataKey = "config"
+
+// KubeconfigProvider provides the kubeconfig of SKR clusters.
+type KubeconfigProvider interface {
+	// GetKubeconfig returns the kubeconfig of the SKR cluster with the given runtime ID. If refresh is true, the kubeconfig must not be
+	// served from a cache, because the SKR cluster rejected the credentials of the previously returned kubeconfig.
+	GetKubeconfig(ctx context.Context, skrClusterID string, refresh bool) ([]byte, error)
+}
+
+// KubeconfigSecretConfig defines where the secrets containing the kubeconfig of the SKR clusters are stored in the KCP cluster.
+type KubeconfigSecretConfig struct {
+	namespace    string
+	nameTemplate RuntimeIDTemplate
+}
+
+// NewKubeconfigSecretConfig returns the configuration of the kubeconfig secrets. The name template is a Go template that can
+// reference the runtime ID of the SKR cluster with '{{ .RuntimeID }}', e.g. 'kubeconfig-{{ .RuntimeID }}'.
+func NewKubeconfigSecretConfig(namespace, nameTemplate string) (KubeconfigSecretConfig, error) {
+	if namespace == "" {
+		return KubeconfigSecretConfig{}, errors.New("namespace of the kubeconfig secret must not be empty")
+	}
+
+	t, err := NewRuntimeIDTemplate("kubeconfigSecretName", nameTemplate)
+	if err != nil {
+		return KubeconfigSecretConfig{}, errors.Wrap(err, "invalid name template of the kubeconfig secret")
+	}
+	return KubeconfigSecretConfig{namespace: namespace, nameTemplate: t}, nil
+}
+
+// DefaultKubeconfigSecretConfig returns the configuration of the kubeconfig secrets that is used by Kyma control-plane.
+func DefaultKubeconfigSecretConfig() KubeconfigSecretConfig {
+	c, err := NewKubeconfigSecretConfig(KcpNamespace, DefaultKubeconfigSecretNameTemplate)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// SecretKey returns the namespaced name of the secret containing the kubeconfig of the SKR cluster with the given runtime ID.
+func (c KubeconfigSecretConfig) SecretKey(skrClusterID string) (types.NamespacedName, error) {
+	name, err := c.nameTemplate.Render(skrClusterID)
+	if err != nil {
+		return types.NamespacedName{}, errors.Wrap(err, "failed to render name of the kubeconfig secret")
+	}
+	return types.NamespacedName{Namespace: c.namespace, Name: name}, nil
+}
+
+type secretKubeconfigProvider struct {
+	k8sClient kpkgclient.Reader
+	// apiReader reads directly from the API server. It is used to read the kubeconfig when the informer cache might not
+	// yet contain a rotated kubeconfig.
+	apiReader kpkgclient.Reader
+	config    KubeconfigSecretConfig
+}
+
+// NewSecretKubeconfigProvider returns a provider that reads the kubeconfig of the SKR clusters from secrets in the KCP cluster. The secrets
+// are read with the given client, which is expected to be the cached client of the manager, so that reading the kubeconfig does not
+// result in a request to the API server. The API reader is only used to refresh a kubeconfig after the SKR cluster rejected its credentials.
+func NewSecretKubeconfigProvider(k8sClient, apiReader kpkgclient.Reader, config KubeconfigSecretConfig) KubeconfigProvider {
+	return &secretKubeconfigProvider{
+		k8sClient: k8sClient,
+		apiReader: apiReader,
+		config:    config,
+	}
+}
+
+func (p *secretKubeconfigProvider) GetKubeconfig(ctx context.Context, skrClusterID string, refresh bool) ([]byte, error) {
+	kubeconfigSecretKey, err := p.config.SecretKey(skrClusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := p.k8sClient
+	if refresh {
+		reader = p.apiReader
+	}
+
+	secret := &kcorev1.Secret{}
+	if err := reader.Get(ctx, kubeconfigSecretKey, secret); err != nil {
+		return nil, err
+	}
+
+	kubeconfig := secret.Data[kubeconfigSecretDataKey]
+	if len(kubeconfig) == 0 {
+		return nil, errors.Errorf("failed to find SKR cluster kubeconfig in secret %s", kubeconfigSecretKey.Name)
+	}
+	return kubeconfig, nil
+}
+
+type eventingAuthKey struct{}
+
+// WithEventingAuth returns a context that stores the namespaced name of the EventingAuth CR whose target cluster is accessed, so that the
+// kubeconfig of a target cluster referenced by the CR is read from the referenced secret.
+func WithEventingAuth(ctx context.Context, eventingAuth types.NamespacedName) context.Context {
+	return context.WithValue(ctx, eventingAuthKey{}, eventingAuth)
+}
+
+// eventingAuthOf returns the namespaced name of the EventingAuth CR that is stored in the context, and false if there is none.
+func eventingAuthOf(ctx context.Context) (types.NamespacedName, bool) {
+	eventingAuth, ok := ctx.Value(eventingAuthKey{}).(types.NamespacedName)
+	return eventingAuth, ok
+}
+
+type referencedKubeconfigProvider struct {
+	k8sClient kpkgclient.Reader
+	apiReader kpkgclient.Reader
+	fallback  KubeconfigProvider
+	// allowedNamespaces are the namespaces that the kubeconfig secrets can be referenced from in addition to the namespace of the
+	// EventingAuth CR.
+	allowedNamespaces []string
+}
+
+// NewReferencedKubeconfigProvider returns a provider that reads the kubeconfig of the target cluster of an EventingAuth CR with
+// spec.kubeconfigSecretRef from the referenced secret, so that clusters that aren't managed runtimes can be targeted. The EventingAuth CR is
+// the one stored in the context with WithEventingAuth, and the kubeconfig of all other clusters is provided by the fallback provider. The
+// referenced secret must be in the namespace of the EventingAuth CR or in one of the allowed namespaces. The EventingAuth CRs and the
+// secrets are read with the given client, and the secrets are read with the API reader if the kubeconfig is refreshed.
+func NewReferencedKubeconfigProvider(k8sClient, apiReader kpkgclient.Reader, fallback KubeconfigProvider,
+	allowedNamespaces []string,
+) KubeconfigProvider {
+	return &referencedKubeconfigProvider{
+		k8sClient:         k8sClient,
+		apiReader:         apiReader,
+		fallback:          fallback,
+		allowedNamespaces: allowedNamespaces,
+	}
+}
+
+func (p *referencedKubeconfigProvider) GetKubeconfig(ctx context.Context, skrClusterID string, refresh bool) ([]byte, error) {
+	eventingAuth, ok := eventingAuthOf(ctx)
+	if !ok || eventingAuth.Name != skrClusterID {
+		return p.fallback.GetKubeconfig(ctx, skrClusterID, refresh)
+	}
+	cr := &eamapiv1alpha1.EventingAuth{}
+	if err := p.k8sClient.Get(ctx, eventingAuth, cr); err != nil {
+		return nil, errors.Wrapf(err, "failed to get EventingAuth CR %s to find the kubeconfig secret", eventingAuth)
+	}
+	ref := cr.Spec.KubeconfigSecretRef
+	if ref == nil {
+		return p.fallback.GetKubeconfig(ctx, skrClusterID, refresh)
+	}
+	if ref.Namespace != cr.Namespace && !slices.Contains(p.allowedNamespaces, ref.Namespace) {
+		return nil, errors.Errorf("kubeconfig secret %s/%s must be in namespace %s of the EventingAuth CR or in an allowed namespace",
+			ref.Namespace, ref.Name, cr.Namespace)
+	}
+
+	reader := p.k8sClient
+	if refresh {
+		reader = p.apiReader
+	}
+	key := ref.Key
+	if key == "" {
+		key = kubeconfigSecretDataKey
+	}
+	secret := &kcorev1.Secret{}
+	if err := reader.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, err
+	}
+	kubeconfig := secret.Data[key]
+	if len(kubeconfig) == 0 {
+		return nil, errors.Errorf("failed to find kubeconfig of target cluster under key %s in secret %s/%s", key, ref.Namespace, ref.Name)
+	}
+	return kubeconfig, nil
+}
+
+// RuntimeIDTemplate is a Go template that renders a name based on the runtime ID of an SKR cluster.
+type RuntimeIDTemplate struct {
+	t *template.Template
+}
+
+// RuntimeIDTemplateData is the data that can be used in a RuntimeIDTemplate.
+type RuntimeIDTemplateData struct {
+	RuntimeID string
+}
+
+// NewRuntimeIDTemplate parses the given Go template, which can reference the runtime ID of an SKR cluster with '{{ .RuntimeID }}'.
+func NewRuntimeIDTemplate(name, text string) (RuntimeIDTemplate, error) {
+	t, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return RuntimeIDTemplate{}, errors.Wrap(err, "failed to parse template")
+	}
+
+	r := RuntimeIDTemplate{t: t}
+	// Render the template once to detect invalid templates at startup instead of at the first reconciliation.
+	if _, err := r.Render("runtime-id"); err != nil {
+		return RuntimeIDTemplate{}, err
+	}
+	return r, nil
+}
+
+// Render renders the template for the SKR cluster with the given runtime ID. It returns an error if the rendered template is empty.
+func (r RuntimeIDTemplate) Render(skrClusterID string) (string, error) {
+	var b bytes.Buffer
+	if err := r.t.Execute(&b, RuntimeIDTemplateData{RuntimeID: skrClusterID}); err != nil {
+		return "", errors.Wrap(err, "failed to render template")
+	}
+	if b.Len() == 0 {
+		return "", errors.New("rendered template is empty")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,192 @@
+package skr
+
+import (
+	"context"
+	"testing"
+
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_KubeconfigSecretConfig_SecretKey(t *testing.T) {
+	tests := []struct {
+		name         string
+		namespace    string
+		nameTemplate string
+		want         types.NamespacedName
+		wantErr      bool
+	}{
+		{
+			name:         "should return the key of the kubeconfig secret with default configuration",
+			namespace:    KcpNamespace,
+			nameTemplate: DefaultKubeconfigSecretNameTemplate,
+			want:         types.NamespacedName{Namespace: KcpNamespace, Name: "kubeconfig-test"},
+		},
+		{
+			name:         "should return the key of the kubeconfig secret with custom configuration",
+			namespace:    "custom-ns",
+			nameTemplate: "{{ .RuntimeID }}-admin-kubeconfig",
+			want:         types.NamespacedName{Namespace: "custom-ns", Name: "test-admin-kubeconfig"},
+		},
+		{
+			name:         "should return error when namespace is empty",
+			nameTemplate: DefaultKubeconfigSecretNameTemplate,
+			wantErr:      true,
+		},
+		{
+			name:         "should return error when template cannot be parsed",
+			namespace:    KcpNamespace,
+			nameTemplate: "kubeconfig-{{ .RuntimeID ",
+			wantErr:      true,
+		},
+		{
+			name:         "should return error when template references unknown field",
+			namespace:    KcpNamespace,
+			nameTemplate: "kubeconfig-{{ .ClusterName }}",
+			wantErr:      true,
+		},
+		{
+			name:         "should return error when rendered name is empty",
+			namespace:    KcpNamespace,
+			nameTemplate: "",
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewKubeconfigSecretConfig(tt.namespace, tt.nameTemplate)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			got, err := c.SecretKey("test")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type staticKubeconfigProvider []byte
+
+func (p staticKubeconfigProvider) GetKubeconfig(context.Context, string, bool) ([]byte, error) {
+	return p, nil
+}
+
+func Test_referencedKubeconfigProvider_GetKubeconfig(t *testing.T) {
+	edgeRuntime := types.NamespacedName{Namespace: KcpNamespace, Name: "edge-runtime"}
+	tests := []struct {
+		name                   string
+		ref                    *eamapiv1alpha1.KubeconfigSecretReference
+		givenAllowedNamespaces []string
+		givenEventingAuth      *types.NamespacedName
+		want                   []byte
+		wantErr                bool
+	}{
+		{
+			name:              "should return the kubeconfig of the referenced secret under the default key",
+			ref:               &eamapiv1alpha1.KubeconfigSecretReference{Namespace: KcpNamespace, Name: "edge"},
+			givenEventingAuth: &edgeRuntime,
+			want:              []byte("default-kubeconfig"),
+		},
+		{
+			name:              "should return the kubeconfig of the referenced secret under a custom key",
+			ref:               &eamapiv1alpha1.KubeconfigSecretReference{Namespace: KcpNamespace, Name: "edge", Key: "kubeconfig"},
+			givenEventingAuth: &edgeRuntime,
+			want:              []byte("custom-kubeconfig"),
+		},
+		{
+			name:                   "should return the kubeconfig of the referenced secret in an allowed namespace",
+			ref:                    &eamapiv1alpha1.KubeconfigSecretReference{Namespace: "targets", Name: "edge"},
+			givenAllowedNamespaces: []string{"targets"},
+			givenEventingAuth:      &edgeRuntime,
+			want:                   []byte("targets-kubeconfig"),
+		},
+		{
+			name:              "should return the kubeconfig of the fallback provider without reference",
+			givenEventingAuth: &edgeRuntime,
+			want:              []byte("fallback-kubeconfig"),
+		},
+		{
+			name: "should return the kubeconfig of the fallback provider without EventingAuth CR in the context",
+			ref:  &eamapiv1alpha1.KubeconfigSecretReference{Namespace: KcpNamespace, Name: "edge"},
+			want: []byte("fallback-kubeconfig"),
+		},
+		{
+			name:              "should return the kubeconfig of the fallback provider for another EventingAuth CR in the context",
+			ref:               &eamapiv1alpha1.KubeconfigSecretReference{Namespace: KcpNamespace, Name: "edge"},
+			givenEventingAuth: &types.NamespacedName{Namespace: KcpNamespace, Name: "other-runtime"},
+			want:              []byte("fallback-kubeconfig"),
+		},
+		{
+			name:              "should return error when the referenced secret isn't in an allowed namespace",
+			ref:               &eamapiv1alpha1.KubeconfigSecretReference{Namespace: "targets", Name: "edge"},
+			givenEventingAuth: &edgeRuntime,
+			wantErr:           true,
+		},
+		{
+			name:              "should return error when the EventingAuth CR of the context is missing",
+			ref:               &eamapiv1alpha1.KubeconfigSecretReference{Namespace: KcpNamespace, Name: "edge"},
+			givenEventingAuth: &types.NamespacedName{Namespace: "other", Name: "edge-runtime"},
+			wantErr:           true,
+		},
+		{
+			name:              "should return error when the referenced key is missing",
+			ref:               &eamapiv1alpha1.KubeconfigSecretReference{Namespace: KcpNamespace, Name: "edge", Key: "missing"},
+			givenEventingAuth: &edgeRuntime,
+			wantErr:           true,
+		},
+		{
+			name:              "should return error when the referenced secret is missing",
+			ref:               &eamapiv1alpha1.KubeconfigSecretReference{Namespace: KcpNamespace, Name: "missing"},
+			givenEventingAuth: &edgeRuntime,
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			scheme := runtime.NewScheme()
+			require.NoError(t, clientgoscheme.AddToScheme(scheme))
+			require.NoError(t, eamapiv1alpha1.AddToScheme(scheme))
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&eamapiv1alpha1.EventingAuth{
+					ObjectMeta: kmetav1.ObjectMeta{Namespace: edgeRuntime.Namespace, Name: edgeRuntime.Name},
+					Spec:       eamapiv1alpha1.EventingAuthSpec{KubeconfigSecretRef: tt.ref},
+				},
+				&kcorev1.Secret{
+					ObjectMeta: kmetav1.ObjectMeta{Namespace: KcpNamespace, Name: "edge"},
+					Data:       map[string][]byte{"config": []byte("default-kubeconfig"), "kubeconfig": []byte("custom-kubeconfig")},
+				},
+				&kcorev1.Secret{
+					ObjectMeta: kmetav1.ObjectMeta{Namespace: "targets", Name: "edge"},
+					Data:       map[string][]byte{"config": []byte("targets-kubeconfig")},
+				},
+			).Build()
+			p := NewReferencedKubeconfigProvider(k8sClient, k8sClient, staticKubeconfigProvider("fallback-kubeconfig"),
+				tt.givenAllowedNamespaces)
+			ctx := context.TODO()
+			if tt.givenEventingAuth != nil {
+				ctx = WithEventingAuth(ctx, *tt.givenEventingAuth)
+			}
+
+			// when
+			got, err := p.GetKubeconfig(ctx, "edge-runtime", false)
+
+			// then
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
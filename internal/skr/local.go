@@ -0,0 +1,69 @@
+package skr
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const localKubeconfigName = "local"
+
+type localKubeconfigProvider struct {
+	kubeconfig []byte
+}
+
+// NewLocalKubeconfigProvider returns a provider that returns the kubeconfig of the cluster the manager runs in for every SKR cluster, so
+// that the application secret is written into the same cluster. This is used for single-cluster Kyma installations and local development.
+func NewLocalKubeconfigProvider(config *rest.Config) (KubeconfigProvider, error) {
+	kubeconfig, err := kubeconfigFromRESTConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create kubeconfig of the local cluster")
+	}
+	return &localKubeconfigProvider{kubeconfig: kubeconfig}, nil
+}
+
+func (p *localKubeconfigProvider) GetKubeconfig(_ context.Context, _ string, _ bool) ([]byte, error) {
+	return p.kubeconfig, nil
+}
+
+// kubeconfigFromRESTConfig serializes the given REST config as kubeconfig. Credential files, e.g. the service account token of an in-cluster
+// config, are referenced by their path, so that rotated credentials are still picked up by the clients.
+func kubeconfigFromRESTConfig(config *rest.Config) ([]byte, error) {
+	if config.Host == "" {
+		return nil, errors.New("host of the REST config must not be empty")
+	}
+
+	kubeconfig := clientcmdapi.NewConfig()
+	kubeconfig.Clusters[localKubeconfigName] = &clientcmdapi.Cluster{
+		Server:                   config.Host,
+		TLSServerName:            config.ServerName,
+		InsecureSkipTLSVerify:    config.Insecure,
+		CertificateAuthority:     config.CAFile,
+		CertificateAuthorityData: config.CAData,
+	}
+	kubeconfig.AuthInfos[localKubeconfigName] = &clientcmdapi.AuthInfo{
+		ClientCertificate:     config.CertFile,
+		ClientCertificateData: config.CertData,
+		ClientKey:             config.KeyFile,
+		ClientKeyData:         config.KeyData,
+		Token:                 config.BearerToken,
+		TokenFile:             config.BearerTokenFile,
+		Impersonate:           config.Impersonate.UserName,
+		ImpersonateUID:        config.Impersonate.UID,
+		ImpersonateGroups:     config.Impersonate.Groups,
+		ImpersonateUserExtra:  config.Impersonate.Extra,
+		Username:              config.Username,
+		Password:              config.Password,
+		AuthProvider:          config.AuthProvider,
+		Exec:                  config.ExecProvider,
+	}
+	kubeconfig.Contexts[localKubeconfigName] = &clientcmdapi.Context{
+		Cluster:  localKubeconfigName,
+		AuthInfo: localKubeconfigName,
+	}
+	kubeconfig.CurrentContext = localKubeconfigName
+	return clientcmd.Write(*kubeconfig)
+}
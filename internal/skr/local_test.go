@@ -0,0 +1,47 @@
+package skr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func Test_NewLocalKubeconfigProvider(t *testing.T) {
+	// given an in-cluster config, whose credential files are validated when the kubeconfig is loaded
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token"), 0o600))
+	require.NoError(t, os.WriteFile(caFile, []byte("ca"), 0o600))
+	config := &rest.Config{
+		Host:            "https://kubernetes.default.svc",
+		BearerTokenFile: tokenFile,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAFile: caFile,
+		},
+	}
+
+	// when
+	p, err := NewLocalKubeconfigProvider(config)
+	require.NoError(t, err)
+	kubeconfig, err := p.GetKubeconfig(context.TODO(), "any-runtime-id", false)
+	require.NoError(t, err)
+
+	// then the kubeconfig results in the same REST config
+	got, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	require.NoError(t, err)
+	require.Equal(t, config.Host, got.Host)
+	require.Equal(t, config.BearerTokenFile, got.BearerTokenFile)
+	require.Equal(t, config.CAFile, got.CAFile)
+
+	// when the REST config has no host
+	_, err = NewLocalKubeconfigProvider(&rest.Config{})
+
+	// then an error is returned
+	require.Error(t, err)
+}
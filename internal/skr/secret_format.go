@@ -0,0 +1,167 @@
+package skr
+
+import (
+	"slices"
+	"strings"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/pkg/errors"
+	kcorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Keys of the entries of the Service Binding Specification, see https://servicebinding.io/spec/core/1.0.0/#well-known-secret-entries.
+const (
+	ServiceBindingTypeKey          = "type"
+	ServiceBindingProviderKey      = "provider"
+	serviceBindingSecretTypePrefix = "servicebinding.io/"
+)
+
+//nolint:gochecknoglobals // Used as constant.
+var applicationSecretKeys = eamias.SecretKeys()
+
+// SecretFormat defines the type and the data keys of the application secret, so that the secret can be consumed by eventing versions that
+// expect a different format.
+type SecretFormat struct {
+	// Type is the type of the application secret. Defaults to 'Opaque'.
+	Type kcorev1.SecretType
+	// KeyMapping maps the data keys of the application secret, e.g. 'client_id', to the keys that are written instead. Keys that aren't
+	// mapped are written unchanged.
+	KeyMapping map[string]string
+	// ServiceBinding adds the entries of the Service Binding Specification to the application secret if set, so that the secret can be
+	// projected into workloads by service binding implementations and read by binding libraries.
+	ServiceBinding *ServiceBinding
+	// BTPServiceOperator writes the application secret in the format of the secrets of IAS service bindings created by the SAP BTP service
+	// operator, so that applications can consume the credentials of both provisioning paths without changes.
+	BTPServiceOperator bool
+}
+
+// ServiceBinding defines the entries of the Service Binding Specification of the application secret.
+type ServiceBinding struct {
+	// Type is the value of the 'type' entry, e.g. 'oauth2'. The secret type defaults to 'servicebinding.io/<type>'.
+	Type string
+	// Provider is the value of the 'provider' entry. The entry is omitted if empty.
+	Provider string
+}
+
+// Validate checks that the entries of the Service Binding Specification don't conflict with the mapped data keys.
+func (f SecretFormat) Validate() error {
+	if f.BTPServiceOperator && (len(f.KeyMapping) > 0 || f.ServiceBinding != nil) {
+		return errors.New("the format of the SAP BTP service operator can't be combined with a key mapping or a service binding")
+	}
+	if f.ServiceBinding == nil {
+		return nil
+	}
+	if f.ServiceBinding.Type == "" {
+		return errors.New("type of the service binding must not be empty")
+	}
+	for _, key := range applicationSecretKeys {
+		if mapped := f.mappedKey(key); mapped == ServiceBindingTypeKey || mapped == ServiceBindingProviderKey {
+			return errors.Errorf("mapped key %q is reserved by the Service Binding Specification", mapped)
+		}
+	}
+	return nil
+}
+
+// ParseSecretKeyMapping parses a comma-separated list of mappings of data keys of the application secret, e.g. 'client_id=clientid,client_secret=clientsecret'.
+func ParseSecretKeyMapping(s string) (map[string]string, error) {
+	mapping := map[string]string{}
+	mappedKeys := map[string]bool{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, errors.Errorf("invalid key mapping %q, expected format is '<key>=<mapped key>'", entry)
+		}
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !slices.Contains(applicationSecretKeys, from) {
+			return nil, errors.Errorf("unknown key %q of the application secret, supported keys are %s", from, strings.Join(applicationSecretKeys, ", "))
+		}
+		if msgs := validation.IsConfigMapKey(to); len(msgs) > 0 {
+			return nil, errors.Errorf("invalid mapped key %q: %s", to, strings.Join(msgs, ", "))
+		}
+		if _, ok := mapping[from]; ok {
+			return nil, errors.Errorf("key %q is mapped more than once", from)
+		}
+		mapping[from] = to
+	}
+
+	// Keys must be unique after the mapping, including the keys that aren't mapped.
+	for _, key := range applicationSecretKeys {
+		mapped := key
+		if to, ok := mapping[key]; ok {
+			mapped = to
+		}
+		if mappedKeys[mapped] {
+			return nil, errors.Errorf("mapped key %q is used more than once", mapped)
+		}
+		mappedKeys[mapped] = true
+	}
+	return mapping, nil
+}
+
+// secretType returns the type of the application secret.
+func (f SecretFormat) secretType() kcorev1.SecretType {
+	if f.Type == "" {
+		if f.ServiceBinding != nil {
+			return kcorev1.SecretType(serviceBindingSecretTypePrefix + f.ServiceBinding.Type)
+		}
+		return kcorev1.SecretTypeOpaque
+	}
+	return f.Type
+}
+
+// mappedKey returns the data key that is written instead of the given key.
+func (f SecretFormat) mappedKey(key string) string {
+	keyMapping := f.KeyMapping
+	if f.BTPServiceOperator {
+		keyMapping = btpKeyMapping
+	}
+	if mapped, ok := keyMapping[key]; ok {
+		return mapped
+	}
+	return key
+}
+
+// apply sets the type of the given secret, maps its data keys and adds the entries of the Service Binding Specification or of the SAP BTP
+// service operator.
+func (f SecretFormat) apply(s *kcorev1.Secret) {
+	s.Type = f.secretType()
+	data := make(map[string][]byte, len(s.Data)+2)
+	for key, value := range s.Data {
+		data[f.mappedKey(key)] = value
+	}
+	if f.ServiceBinding != nil {
+		data[ServiceBindingTypeKey] = []byte(f.ServiceBinding.Type)
+		if f.ServiceBinding.Provider != "" {
+			data[ServiceBindingProviderKey] = []byte(f.ServiceBinding.Provider)
+		}
+	}
+	if f.BTPServiceOperator {
+		addBTPServiceOperatorEntries(data)
+	}
+	s.Data = data
+}
+
+// withDiscovery returns a copy of the data of an application secret with the given token URL and JWKS URI. Only the keys that exist in the
+// data are replaced, since the secret might have been written with another key mapping. Empty endpoints aren't replaced.
+func (f SecretFormat) withDiscovery(data map[string][]byte, tokenURL, certsURL string) map[string][]byte {
+	updated := make(map[string][]byte, len(data))
+	for key, value := range data {
+		updated[key] = value
+	}
+	for key, value := range map[string]string{eamias.SecretKeyTokenURL: tokenURL, eamias.SecretKeyCertsURL: certsURL} {
+		if _, ok := data[f.mappedKey(key)]; ok && value != "" {
+			updated[f.mappedKey(key)] = []byte(value)
+		}
+	}
+	if _, ok := data[btpURLKey]; ok && f.BTPServiceOperator {
+		if tenantURL, ok := btpTenantURL(string(updated[eamias.SecretKeyTokenURL])); ok {
+			updated[btpURLKey] = tenantURL
+		}
+	}
+	return updated
+}
@@ -0,0 +1,228 @@
+package skr
+
+import (
+	"testing"
+
+	eamias "github.com/kyma-project/eventing-auth-manager/internal/ias"
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+)
+
+func Test_ParseSecretKeyMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "should return empty mapping for empty string",
+			mapping: "",
+			want:    map[string]string{},
+		},
+		{
+			name:    "should parse mapping",
+			mapping: "client_id=clientid, client_secret=clientsecret",
+			want:    map[string]string{"client_id": "clientid", "client_secret": "clientsecret"},
+		},
+		{
+			name:    "should return error for unknown key",
+			mapping: "unknown=clientid",
+			wantErr: true,
+		},
+		{
+			name:    "should return error for invalid mapped key",
+			mapping: "client_id=client id",
+			wantErr: true,
+		},
+		{
+			name:    "should return error for entry without mapped key",
+			mapping: "client_id",
+			wantErr: true,
+		},
+		{
+			name:    "should return error if mapped key is a key that isn't mapped",
+			mapping: "client_id=client_secret",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSecretKeyMapping(tt.mapping)
+
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_SecretFormat_apply(t *testing.T) {
+	// given
+	s := eamias.NewApplication("id", "client-id", "client-secret", "token-url", "certs-url").ToSecret(ApplicationSecretName, ApplicationSecretNamespace)
+	f := SecretFormat{
+		Type:       "kyma-project.io/eventing-webhook-auth",
+		KeyMapping: map[string]string{eamias.SecretKeyClientID: "clientid", eamias.SecretKeyClientSecret: "clientsecret"},
+	}
+
+	// when
+	f.apply(&s)
+
+	// then
+	require.Equal(t, kcorev1.SecretType("kyma-project.io/eventing-webhook-auth"), s.Type)
+	require.Equal(t, map[string][]byte{
+		"clientid":               []byte("client-id"),
+		"clientsecret":           []byte("client-secret"),
+		eamias.SecretKeyTokenURL: []byte("token-url"),
+		eamias.SecretKeyCertsURL: []byte("certs-url"),
+	}, s.Data)
+	require.Equal(t, kcorev1.SecretTypeOpaque, SecretFormat{}.secretType())
+}
+
+func Test_SecretFormat_apply_ServiceBinding(t *testing.T) {
+	// given
+	s := eamias.NewApplication("id", "client-id", "client-secret", "token-url", "certs-url").ToSecret(ApplicationSecretName, ApplicationSecretNamespace)
+	f := SecretFormat{ServiceBinding: &ServiceBinding{Type: "oauth2", Provider: "sap"}}
+
+	// when
+	f.apply(&s)
+
+	// then the entries of the Service Binding Specification are added
+	require.Equal(t, kcorev1.SecretType("servicebinding.io/oauth2"), s.Type)
+	require.Equal(t, map[string][]byte{
+		eamias.SecretKeyClientID:     []byte("client-id"),
+		eamias.SecretKeyClientSecret: []byte("client-secret"),
+		eamias.SecretKeyTokenURL:     []byte("token-url"),
+		eamias.SecretKeyCertsURL:     []byte("certs-url"),
+		ServiceBindingTypeKey:        []byte("oauth2"),
+		ServiceBindingProviderKey:    []byte("sap"),
+	}, s.Data)
+
+	// then a configured type is not overwritten
+	require.Equal(t, kcorev1.SecretTypeOpaque, SecretFormat{Type: kcorev1.SecretTypeOpaque, ServiceBinding: f.ServiceBinding}.secretType())
+}
+
+func Test_SecretFormat_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  SecretFormat
+		wantErr string
+	}{
+		{
+			name:   "should accept format without service binding",
+			format: SecretFormat{KeyMapping: map[string]string{eamias.SecretKeyClientID: ServiceBindingTypeKey}},
+		},
+		{
+			name:   "should accept service binding without provider",
+			format: SecretFormat{ServiceBinding: &ServiceBinding{Type: "oauth2"}},
+		},
+		{
+			name:    "should return error for service binding without type",
+			format:  SecretFormat{ServiceBinding: &ServiceBinding{Provider: "sap"}},
+			wantErr: "type of the service binding must not be empty",
+		},
+		{
+			name:    "should return error if the format of the BTP service operator is combined with a key mapping",
+			format:  SecretFormat{KeyMapping: map[string]string{eamias.SecretKeyClientID: "id"}, BTPServiceOperator: true},
+			wantErr: "the format of the SAP BTP service operator can't be combined with a key mapping or a service binding",
+		},
+		{
+			name: "should return error if a mapped key is reserved by the service binding",
+			format: SecretFormat{
+				KeyMapping:     map[string]string{eamias.SecretKeyClientID: ServiceBindingProviderKey},
+				ServiceBinding: &ServiceBinding{Type: "oauth2"},
+			},
+			wantErr: `mapped key "provider" is reserved by the Service Binding Specification`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.format.Validate()
+
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_SecretFormat_apply_BTPServiceOperator(t *testing.T) {
+	// given
+	s := eamias.NewApplication("id", "client-id", "client-secret", "https://tenant.accounts.ondemand.com/oauth2/token",
+		"https://tenant.accounts.ondemand.com/oauth2/certs").ToSecret(ApplicationSecretName, ApplicationSecretNamespace)
+	f := SecretFormat{BTPServiceOperator: true}
+
+	// when
+	f.apply(&s)
+
+	// then the secret has the shape of the secret of an IAS service binding
+	require.Equal(t, kcorev1.SecretTypeOpaque, s.Type)
+	require.Equal(t, map[string][]byte{
+		"clientid":               []byte("client-id"),
+		"clientsecret":           []byte("client-secret"),
+		"url":                    []byte("https://tenant.accounts.ondemand.com"),
+		eamias.SecretKeyTokenURL: []byte("https://tenant.accounts.ondemand.com/oauth2/token"),
+		eamias.SecretKeyCertsURL: []byte("https://tenant.accounts.ondemand.com/oauth2/certs"),
+		"type":                   []byte("identity"),
+		"label":                  []byte("identity"),
+		"plan":                   []byte("application"),
+		BTPServiceOperatorMetadataKey: []byte(`{"credentialProperties":[` +
+			`{"name":"certs_url","format":"text"},{"name":"clientid","format":"text"},{"name":"clientsecret","format":"text"},` +
+			`{"name":"token_url","format":"text"},{"name":"url","format":"text"}],` +
+			`"metaDataProperties":[{"name":"type","format":"text"},{"name":"label","format":"text"},{"name":"plan","format":"text"}]}`),
+	}, s.Data)
+}
+
+func Test_SecretFormat_withDiscovery(t *testing.T) {
+	tests := []struct {
+		name     string
+		format   SecretFormat
+		data     map[string][]byte
+		tokenURL string
+		certsURL string
+		wantData map[string][]byte
+	}{
+		{
+			name:     "should replace the endpoints",
+			data:     map[string][]byte{"client_id": []byte("id"), "token_url": []byte("https://old/token"), "certs_url": []byte("https://old/certs")},
+			tokenURL: "https://new/token",
+			certsURL: "https://new/certs",
+			wantData: map[string][]byte{"client_id": []byte("id"), "token_url": []byte("https://new/token"), "certs_url": []byte("https://new/certs")},
+		},
+		{
+			name:     "should only replace the keys that exist",
+			format:   SecretFormat{KeyMapping: map[string]string{"certs_url": "jwks"}},
+			data:     map[string][]byte{"token_url": []byte("https://old/token"), "certs_url": []byte("https://old/certs")},
+			tokenURL: "https://new/token",
+			certsURL: "https://new/certs",
+			wantData: map[string][]byte{"token_url": []byte("https://new/token"), "certs_url": []byte("https://old/certs")},
+		},
+		{
+			name:     "should keep the endpoints that weren't discovered",
+			data:     map[string][]byte{"token_url": []byte("https://old/token"), "certs_url": []byte("https://old/certs")},
+			certsURL: "https://new/certs",
+			wantData: map[string][]byte{"token_url": []byte("https://old/token"), "certs_url": []byte("https://new/certs")},
+		},
+		{
+			name:     "should replace the tenant URL of the format of the SAP BTP service operator",
+			format:   SecretFormat{BTPServiceOperator: true},
+			data:     map[string][]byte{"url": []byte("https://old"), "token_url": []byte("https://old/token")},
+			tokenURL: "https://new/token",
+			wantData: map[string][]byte{"url": []byte("https://new"), "token_url": []byte("https://new/token")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			data := tt.format.withDiscovery(tt.data, tt.tokenURL, tt.certsURL)
+
+			// then
+			require.Equal(t, tt.wantData, data)
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package skr
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	eamapiv1alpha1 "github.com/kyma-project/eventing-auth-manager/api/v1alpha1"
+	kapierrors "k8s.io/apimachinery/pkg/api/errors"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	DefaultSecretWatcherMaxClusters = 100
+	secretWatcherEventBufferSize    = 1024
+)
+
+// SecretWatcher watches the application secrets on the SKR clusters and emits an event for the EventingAuth CR of an SKR cluster if its
+// application secret is modified or deleted, so that the EventingAuth CR is reconciled immediately instead of with the next resync.
+// The number of watched SKR clusters is bounded, SKR clusters exceeding the limit are only reconciled with the resync.
+type SecretWatcher struct {
+	clientCache *ClientCache
+	maxClusters int
+	backoff     wait.Backoff
+	events      chan event.GenericEvent
+	// resource is the watched resource in the logs.
+	resource string
+	// watchResource starts the watch of the resource on an SKR cluster.
+	watchResource func(ctx context.Context, skrClient Client) (watch.Interface, error)
+	// emitOn are the types of the watch events that emit an event for the EventingAuth CR.
+	emitOn []watch.EventType
+
+	mu sync.Mutex
+	// ctx is the context of the manager. It is nil until the watcher is started.
+	ctx     context.Context //nolint:containedctx // The watches started by the reconcilers must live as long as the manager.
+	watches map[string]*secretWatch
+}
+
+type secretWatch struct {
+	eventingAuth types.NamespacedName
+	cancel       context.CancelFunc
+}
+
+// NewSecretWatcher returns a watcher that uses the clients of the given cache to watch the application secrets on at most maxClusters SKR
+// clusters. The watcher must be added to the manager to start watching.
+func NewSecretWatcher(clientCache *ClientCache, maxClusters int) *SecretWatcher {
+	return &SecretWatcher{
+		clientCache: clientCache,
+		maxClusters: maxClusters,
+		backoff: wait.Backoff{
+			Duration: time.Second,
+			Factor:   2,
+			Jitter:   0.1,
+			Steps:    10,
+			Cap:      time.Minute * 5,
+		},
+		events:   make(chan event.GenericEvent, secretWatcherEventBufferSize),
+		resource: "application secret",
+		watchResource: func(ctx context.Context, skrClient Client) (watch.Interface, error) {
+			return skrClient.WatchApplicationSecret(ctx)
+		},
+		emitOn:  []watch.EventType{watch.Modified, watch.Deleted},
+		watches: map[string]*secretWatch{},
+	}
+}
+
+// Events returns the channel of the events for the EventingAuth CRs whose application secret was modified or deleted.
+func (w *SecretWatcher) Events() <-chan event.GenericEvent {
+	return w.events
+}
+
+// Start starts the watches that were requested before the manager was started and blocks until the context is done.
+func (w *SecretWatcher) Start(ctx context.Context) error {
+	w.mu.Lock()
+	w.ctx = ctx
+	for skrClusterID, sw := range w.watches {
+		w.startLocked(skrClusterID, sw)
+	}
+	w.mu.Unlock()
+
+	<-ctx.Done()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for skrClusterID, sw := range w.watches {
+		sw.cancel()
+		delete(w.watches, skrClusterID)
+	}
+	return nil
+}
+
+// Watch starts watching the application secret on the SKR cluster of the given EventingAuth CR, if it isn't watched yet. It returns false
+// if the SKR cluster isn't watched, because the maximum number of watched SKR clusters is reached.
+func (w *SecretWatcher) Watch(eventingAuth types.NamespacedName) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	skrClusterID := eventingAuth.Name
+	if _, ok := w.watches[skrClusterID]; ok {
+		return true
+	}
+	if len(w.watches) >= w.maxClusters {
+		return false
+	}
+
+	sw := &secretWatch{eventingAuth: eventingAuth, cancel: func() {}}
+	w.watches[skrClusterID] = sw
+	if w.ctx != nil {
+		w.startLocked(skrClusterID, sw)
+	}
+	return true
+}
+
+// Stop stops watching the application secret on the given SKR cluster.
+func (w *SecretWatcher) Stop(skrClusterID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if sw, ok := w.watches[skrClusterID]; ok {
+		sw.cancel()
+		delete(w.watches, skrClusterID)
+	}
+}
+
+func (w *SecretWatcher) startLocked(skrClusterID string, sw *secretWatch) {
+	// The EventingAuth CR in the context selects the kubeconfig of its target cluster.
+	ctx, cancel := context.WithCancel(WithEventingAuth(w.ctx, sw.eventingAuth))
+	sw.cancel = cancel
+	go w.run(ctx, skrClusterID, sw.eventingAuth)
+}
+
+// run watches the resource until the context is done. If the watch fails or is closed by the SKR cluster, it is re-established
+// with an exponential backoff.
+func (w *SecretWatcher) run(ctx context.Context, skrClusterID string, eventingAuth types.NamespacedName) {
+	logger := log.FromContext(ctx).WithValues("skrClusterID", skrClusterID)
+	backoff := w.backoff
+	reestablished := false
+	for {
+		established, err := w.watch(ctx, logger, skrClusterID, eventingAuth, reestablished)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.V(1).Info("Watch failed", "resource", w.resource, "error", err.Error())
+		}
+		if established {
+			backoff = w.backoff
+			reestablished = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+// watch watches the resource until the watch is closed. It returns true if the watch was established.
+func (w *SecretWatcher) watch(ctx context.Context, logger logr.Logger, skrClusterID string, eventingAuth types.NamespacedName, reestablished bool) (bool, error) {
+	skrClient, err := w.clientCache.Get(ctx, skrClusterID)
+	if err != nil {
+		return false, err
+	}
+	watcher, err := w.watchResource(ctx, skrClient)
+	if err != nil {
+		// The client might use outdated credentials, so a new client is created for the next attempt.
+		w.clientCache.Evict(skrClusterID)
+		return false, err
+	}
+	defer watcher.Stop()
+
+	// Changes of the resource might have been missed while the watch wasn't established.
+	if reestablished {
+		w.emit(ctx, eventingAuth)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, nil
+		case e, ok := <-watcher.ResultChan():
+			if !ok {
+				return true, nil
+			}
+			if e.Type == watch.Error {
+				return true, kapierrors.FromObject(e.Object)
+			}
+			if slices.Contains(w.emitOn, e.Type) {
+				logger.V(1).Info("Watched resource changed on SKR cluster", "resource", w.resource, "eventType", e.Type)
+				w.emit(ctx, eventingAuth)
+			}
+		}
+	}
+}
+
+func (w *SecretWatcher) emit(ctx context.Context, eventingAuth types.NamespacedName) {
+	// The event only needs to reference the EventingAuth CR, because it is enqueued by name.
+	obj := &eamapiv1alpha1.EventingAuth{ObjectMeta: kmetav1.ObjectMeta{Name: eventingAuth.Name, Namespace: eventingAuth.Namespace}}
+	select {
+	case w.events <- event.GenericEvent{Object: obj}:
+	case <-ctx.Done():
+	}
+}
@@ -0,0 +1,60 @@
+package skr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	kcorev1 "k8s.io/api/core/v1"
+	kmetav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_SecretWatcher(t *testing.T) {
+	// given
+	skrK8sClient := fake.NewClientBuilder().Build()
+	originalNewClient := NewClient
+	t.Cleanup(func() { NewClient = originalNewClient })
+	NewClient = func(_ []byte, _ ClientOptions) (Client, error) {
+		return &client{k8sClient: skrK8sClient}, nil
+	}
+
+	kcpK8sClient := fake.NewClientBuilder().WithObjects(newKubeconfigSecret("test", "kubeconfig")).Build()
+	clientCache := NewClientCache(NewSecretKubeconfigProvider(kcpK8sClient, kcpK8sClient, DefaultKubeconfigSecretConfig()), DefaultClientOptions(), DefaultClientCacheTTL)
+	w := NewSecretWatcher(clientCache, 1)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	t.Cleanup(cancel)
+	// when watches are requested before the watcher is started
+	require.True(t, w.Watch(types.NamespacedName{Name: "test", Namespace: KcpNamespace}))
+	require.False(t, w.Watch(types.NamespacedName{Name: "other", Namespace: KcpNamespace}))
+	go func() {
+		require.NoError(t, w.Start(ctx))
+	}()
+
+	// then the secret is watched after the watcher is started
+	secret := &kcorev1.Secret{ObjectMeta: kmetav1.ObjectMeta{
+		Name:      ApplicationSecretName,
+		Namespace: ApplicationSecretNamespace,
+		Labels:    map[string]string{ManagedByLabelKey: ManagedByLabelValue},
+	}}
+	require.Eventually(t, func() bool {
+		// The secret is recreated until the watch is established and the deletion is observed.
+		_ = skrK8sClient.Create(ctx, secret.DeepCopy())
+		_ = skrK8sClient.Delete(ctx, secret.DeepCopy())
+		select {
+		case e := <-w.Events():
+			return e.Object.GetName() == "test" && e.Object.GetNamespace() == KcpNamespace
+		default:
+			return false
+		}
+	}, time.Second*5, time.Millisecond*50)
+
+	// when the watch is stopped
+	w.Stop("test")
+
+	// then another SKR cluster can be watched
+	require.True(t, w.Watch(types.NamespacedName{Name: "other", Namespace: KcpNamespace}))
+}
@@ -0,0 +1,31 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// CorrelationIDHeader is the header of the requests to the IAS tenants and the SKR clusters that contains the correlation ID of the
+// reconciliation, so that the requests can be found in the logs of the servers even if the reconciliation isn't traced.
+const CorrelationIDHeader = "X-Correlation-ID"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context with the correlation ID of a reconciliation, which links its log lines, events, status messages,
+// and audit records. The correlation ID is the ID of the trace of the context, so that they can be looked up in the traces, or a new ID if
+// the reconciliation isn't traced.
+func WithCorrelationID(ctx context.Context) (context.Context, string) {
+	id := string(uuid.NewUUID())
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		id = spanContext.TraceID().String()
+	}
+	return context.WithValue(ctx, correlationIDKey{}, id), id
+}
+
+// CorrelationID returns the correlation ID of the context, or an empty ID if the context has none.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
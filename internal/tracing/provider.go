@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// tracesPath is the path of the OTLP/HTTP receiver that the spans are sent to.
+	tracesPath = "/v1/traces"
+	// maxQueueSize is the number of ended spans that are kept until they are exported. Spans that end while the queue is full are dropped,
+	// so that an unavailable receiver doesn't increase the memory of the manager.
+	maxQueueSize = 2048
+	// maxExportBatchSize is the number of spans that are exported at most with one request.
+	maxExportBatchSize = 512
+)
+
+// newExporter returns an exporter that sends the spans with the OTLP/HTTP protobuf encoding to the receiver at the base URL of the
+// endpoint, e.g. an OpenTelemetry collector.
+func newExporter(ctx context.Context, endpoint string, timeout time.Duration) (sdktrace.SpanExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the OTLP endpoint")
+	}
+	if u.Host == "" {
+		return nil, errors.Errorf("OTLP endpoint %s must be a URL with scheme and host", endpoint)
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(strings.TrimSuffix(u.Path, "/") + tracesPath),
+		otlptracehttp.WithTimeout(timeout),
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	return exporter, errors.Wrap(err, "failed to create the OTLP exporter")
+}
+
+// newTracerProvider returns a tracer provider that samples the traces without sampled parent with the sample ratio of the config, and
+// exports the ended spans in batches in the export interval.
+func newTracerProvider(config Config, exporter sdktrace.SpanExporter) *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithBatchTimeout(config.ExportInterval),
+			sdktrace.WithMaxQueueSize(maxQueueSize),
+			sdktrace.WithMaxExportBatchSize(maxExportBatchSize),
+		),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", config.ServiceName),
+			attribute.String("service.version", config.ServiceVersion),
+		)),
+	)
+}
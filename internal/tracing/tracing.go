@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// TracerName is the name of the tracer of the spans of the manager.
+	TracerName = "github.com/kyma-project/eventing-auth-manager"
+	// DefaultExportInterval is the default interval in which the ended spans are exported.
+	DefaultExportInterval = 5 * time.Second
+	// DefaultExportTimeout is the default timeout of an export of the spans.
+	DefaultExportTimeout = 10 * time.Second
+	// DefaultSampleRatio is the default ratio of the traces that are sampled.
+	DefaultSampleRatio = 1.0
+)
+
+// Config defines how the spans are sampled and exported.
+type Config struct {
+	// Endpoint is the base URL of the OTLP/HTTP receiver that the spans are exported to, e.g. 'http://otel-collector.kyma-system:4318'.
+	// Tracing is disabled if it is empty.
+	Endpoint string
+	// ServiceName and ServiceVersion are the attributes of the resource of the exported spans.
+	ServiceName    string
+	ServiceVersion string
+	// SampleRatio is the ratio of the traces without sampled parent that are sampled, between 0 and 1.
+	SampleRatio float64
+	// ExportInterval is the interval in which the ended spans are exported. Defaults to DefaultExportInterval.
+	ExportInterval time.Duration
+	// ExportTimeout is the timeout of an export of the spans. Defaults to DefaultExportTimeout.
+	ExportTimeout time.Duration
+}
+
+// Setup registers a tracer provider of the OpenTelemetry SDK that exports the spans with the OTLP/HTTP protobuf encoding to the endpoint of
+// the config, and the W3C trace context propagator, so that the traces are continued by other KCP components. Without endpoint, the spans
+// aren't recorded. The returned function exports the remaining spans and stops the export.
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	if config.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return nil, errors.Errorf("sample ratio %v must be between 0 and 1", config.SampleRatio)
+	}
+	if config.ExportInterval <= 0 {
+		config.ExportInterval = DefaultExportInterval
+	}
+	if config.ExportTimeout <= 0 {
+		config.ExportTimeout = DefaultExportTimeout
+	}
+
+	exporter, err := newExporter(ctx, config.Endpoint, config.ExportTimeout)
+	if err != nil {
+		return nil, err
+	}
+	p := newTracerProvider(config, exporter)
+	otel.SetTracerProvider(p)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return p.Shutdown, nil
+}
+
+// Tracer returns the tracer of the spans of the manager.
+func Tracer() trace.Tracer {
+	return otel.Tracer(TracerName)
+}
+
+// End ends the span, whose status is set to error if the error isn't nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// transport starts a client span for every request, and propagates the trace context and the correlation ID to the server.
+type transport struct {
+	next     http.RoundTripper
+	spanName func(req *http.Request) string
+}
+
+// NewTransport returns a transport that traces the requests of the given transport with spans that are named by spanName.
+func NewTransport(next http.RoundTripper, spanName func(req *http.Request) string) http.RoundTripper {
+	return &transport{next: next, spanName: spanName}
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := Tracer().Start(req.Context(), t.spanName(req), trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Host),
+		attribute.String("url.path", req.URL.Path),
+	))
+	// A round tripper must not modify the request, so the trace context is injected into a copy.
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	if correlationID := CorrelationID(ctx); correlationID != "" {
+		req.Header.Set(CorrelationIDHeader, correlationID)
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		End(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", res.StatusCode))
+	if res.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, res.Status)
+	}
+	span.End()
+	return res, nil
+}
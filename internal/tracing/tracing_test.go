@@ -0,0 +1,199 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// receiver is an OTLP/HTTP receiver that stores the exported spans.
+type receiver struct {
+	mu           sync.Mutex
+	paths        []string
+	contentTypes []string
+	spans        []*tracepb.Span
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	b, err := io.ReadAll(req.Body)
+	var body coltracepb.ExportTraceServiceRequest
+	if err == nil {
+		err = proto.Unmarshal(b, &body)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.paths = append(r.paths, req.URL.Path)
+	r.contentTypes = append(r.contentTypes, req.Header.Get("Content-Type"))
+	for _, resourceSpans := range body.GetResourceSpans() {
+		for _, scopeSpans := range resourceSpans.GetScopeSpans() {
+			r.spans = append(r.spans, scopeSpans.GetSpans()...)
+		}
+	}
+}
+
+func Test_newExporter(t *testing.T) {
+	// given
+	r := &receiver{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+	exporter, err := newExporter(context.Background(), server.URL+"/", time.Second)
+	require.NoError(t, err)
+	p := newTracerProvider(Config{SampleRatio: 1, ExportInterval: time.Hour}, exporter)
+
+	// when
+	ctx, parent := p.Tracer(TracerName).Start(context.Background(), "Reconcile")
+	_, child := p.Tracer(TracerName).Start(ctx, "IAS createApp", trace.WithSpanKind(trace.SpanKindClient))
+	End(child, nil)
+	End(parent, nil)
+	err = p.Shutdown(context.Background())
+
+	// then the spans are exported with one request
+	require.NoError(t, err)
+	require.Equal(t, []string{tracesPath}, r.paths)
+	require.Equal(t, []string{"application/x-protobuf"}, r.contentTypes)
+	require.Len(t, r.spans, 2)
+	require.Equal(t, "IAS createApp", r.spans[0].GetName())
+	require.Equal(t, r.spans[1].GetSpanId(), r.spans[0].GetParentSpanId())
+}
+
+func Test_newExporter_InvalidEndpoint(t *testing.T) {
+	// when
+	_, err := newExporter(context.Background(), "otel-collector:4318", time.Second)
+
+	// then
+	require.EqualError(t, err, "OTLP endpoint otel-collector:4318 must be a URL with scheme and host")
+}
+
+func Test_tracerProvider(t *testing.T) {
+	// given
+	exporter := tracetest.NewInMemoryExporter()
+	p := newTracerProvider(Config{SampleRatio: 1, ExportInterval: time.Hour}, exporter)
+	tracer := p.Tracer(TracerName)
+
+	// when
+	ctx, parent := tracer.Start(context.Background(), "Reconcile")
+	_, child := tracer.Start(ctx, "IAS createApp", trace.WithSpanKind(trace.SpanKindClient))
+	End(child, errors.New("request failed"))
+	End(parent, nil)
+	// The in-memory exporter drops the spans when it is shut down, so the spans are only flushed.
+	err := p.ForceFlush(context.Background())
+
+	// then
+	require.NoError(t, err)
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	childSpan, parentSpan := spans[0], spans[1]
+	require.Equal(t, "IAS createApp", childSpan.Name)
+	require.Equal(t, trace.SpanKindClient, childSpan.SpanKind)
+	require.Equal(t, parentSpan.SpanContext.TraceID(), childSpan.SpanContext.TraceID())
+	require.Equal(t, parentSpan.SpanContext.SpanID(), childSpan.Parent.SpanID())
+	require.Equal(t, codes.Error, childSpan.Status.Code)
+	require.Equal(t, "request failed", childSpan.Status.Description)
+	require.Len(t, childSpan.Events, 1)
+	require.Equal(t, "exception", childSpan.Events[0].Name)
+	require.Equal(t, "Reconcile", parentSpan.Name)
+	require.Equal(t, trace.SpanKindInternal, parentSpan.SpanKind)
+	require.False(t, parentSpan.Parent.IsValid())
+	require.Equal(t, codes.Unset, parentSpan.Status.Code)
+}
+
+func Test_tracerProvider_sampled(t *testing.T) {
+	tests := []struct {
+		name        string
+		sampleRatio float64
+		want        bool
+	}{
+		{
+			name:        "should sample all traces with ratio 1",
+			sampleRatio: 1,
+			want:        true,
+		},
+		{
+			name:        "should sample no traces with ratio 0",
+			sampleRatio: 0,
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			p := newTracerProvider(Config{SampleRatio: tt.sampleRatio, ExportInterval: time.Hour}, tracetest.NewInMemoryExporter())
+
+			// when
+			_, s := p.Tracer(TracerName).Start(context.Background(), "Reconcile")
+
+			// then
+			require.Equal(t, tt.want, s.IsRecording())
+			require.Equal(t, tt.want, s.SpanContext().IsSampled())
+			require.True(t, s.SpanContext().IsValid())
+		})
+	}
+}
+
+func Test_transport_RoundTrip(t *testing.T) {
+	// given
+	var traceparent, correlationID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		traceparent = req.Header.Get("traceparent")
+		correlationID = req.Header.Get(CorrelationIDHeader)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	otel.SetTracerProvider(newTracerProvider(Config{SampleRatio: 1, ExportInterval: time.Hour}, tracetest.NewInMemoryExporter()))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	ctx, parent := Tracer().Start(context.Background(), "Reconcile")
+	ctx, wantCorrelationID := WithCorrelationID(ctx)
+	httpClient := &http.Client{Transport: NewTransport(http.DefaultTransport, func(req *http.Request) string { return "SKR " + req.Method })}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	// when
+	res, err := httpClient.Do(req)
+
+	// then
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	require.Empty(t, req.Header.Get("traceparent"))
+	propagated := trace.SpanContextFromContext(propagation.TraceContext{}.Extract(context.Background(),
+		propagation.HeaderCarrier{"Traceparent": []string{traceparent}}))
+	require.Equal(t, parent.SpanContext().TraceID(), propagated.TraceID())
+	require.NotEqual(t, parent.SpanContext().SpanID(), propagated.SpanID())
+	require.Equal(t, parent.SpanContext().TraceID().String(), wantCorrelationID)
+	require.Equal(t, wantCorrelationID, correlationID)
+}
+
+func TestWithCorrelationID(t *testing.T) {
+	// given
+	traceID := trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	}))
+
+	// when
+	ctx, correlationID := WithCorrelationID(ctx)
+
+	// then
+	require.Equal(t, traceID.String(), correlationID)
+	require.Equal(t, correlationID, CorrelationID(ctx))
+	require.Empty(t, CorrelationID(context.Background()))
+}
@@ -0,0 +1,68 @@
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const unknown = "unknown"
+
+// The build information is set with the linker flags of the build, e.g.
+// '-X github.com/kyma-project/eventing-auth-manager/internal/version.version=1.2.3'.
+var (
+	version   = "dev"   //nolint:gochecknoglobals // Set by the linker.
+	gitSHA    = unknown //nolint:gochecknoglobals // Set by the linker.
+	buildDate = unknown //nolint:gochecknoglobals // Set by the linker.
+)
+
+// Info contains the build information of the manager.
+type Info struct {
+	// Version is the released version of the manager, or 'dev' for local builds.
+	Version string
+	// GitSHA is the commit that the manager was built from.
+	GitSHA string
+	// BuildDate is the time of the build in RFC 3339 format.
+	BuildDate string
+	// GoVersion is the version of Go that the manager was built with.
+	GoVersion string
+}
+
+// String returns the build information in a single line.
+func (i Info) String() string {
+	return fmt.Sprintf("version %s, git SHA %s, build date %s, %s", i.Version, i.GitSHA, i.BuildDate, i.GoVersion)
+}
+
+// Get returns the build information of the manager. The git SHA and the build date of builds without linker flags, e.g. with 'go run',
+// are taken from the VCS information that Go embeds into the binary.
+func Get() Info {
+	info := Info{Version: version, GitSHA: gitSHA, BuildDate: buildDate, GoVersion: runtime.Version()}
+	buildInfo, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, setting := range buildInfo.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && info.GitSHA == unknown:
+			info.GitSHA = setting.Value
+		case setting.Key == "vcs.time" && info.BuildDate == unknown:
+			info.BuildDate = setting.Value
+		}
+	}
+	return info
+}
+
+// buildInfo reports the build information of the manager as labels of a gauge that is always 1.
+var buildInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{ //nolint:gochecknoglobals // Registered once on the package level.
+	Name: "eventing_auth_manager_build_info",
+	Help: "Build information of the manager, with the labels version, git_sha, build_date, and go_version, and the constant value 1.",
+}, []string{"version", "git_sha", "build_date", "go_version"})
+
+func init() { //nolint:gochecknoinits // Metrics are registered on the package level.
+	info := Get()
+	buildInfo.WithLabelValues(info.Version, info.GitSHA, info.BuildDate, info.GoVersion).Set(1)
+	metrics.Registry.MustRegister(buildInfo)
+}
@@ -0,0 +1,33 @@
+package version
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Get(t *testing.T) {
+	// given
+	version, gitSHA, buildDate = "1.2.3", "0123456789abcdef", "2024-03-01T12:00:00Z"
+	t.Cleanup(func() {
+		version, gitSHA, buildDate = "dev", unknown, unknown
+	})
+
+	// when
+	info := Get()
+
+	// then
+	require.Equal(t, Info{Version: "1.2.3", GitSHA: "0123456789abcdef", BuildDate: "2024-03-01T12:00:00Z", GoVersion: runtime.Version()}, info)
+	require.Equal(t, "version 1.2.3, git SHA 0123456789abcdef, build date 2024-03-01T12:00:00Z, "+runtime.Version(), info.String())
+}
+
+func Test_buildInfo(t *testing.T) {
+	// when
+	info := Get()
+
+	// then
+	require.Equal(t, 1, testutil.CollectAndCount(buildInfo))
+	require.InDelta(t, 1, testutil.ToFloat64(buildInfo.WithLabelValues(info.Version, info.GitSHA, info.BuildDate, info.GoVersion)), 0)
+}